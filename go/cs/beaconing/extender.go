@@ -63,6 +63,10 @@ type DefaultExtender struct {
 	Task string
 	// StaticInfo contains the configuration used for the StaticInfo Extension.
 	StaticInfo func() *StaticInfoCfg
+	// LinkLatencies provides the measured inter-AS link latencies. If it is
+	// set, recent measurements take precedence over the latencies configured
+	// in the StaticInfo configuration.
+	LinkLatencies LinkLatencies
 	// EPIC defines whether the EPIC authenticators should be added when the segment is extended.
 	EPIC bool
 }
@@ -109,6 +113,10 @@ func (s *DefaultExtender) Extend(ctx context.Context, pseg *seg.PathSegment,
 	if static := s.StaticInfo(); static != nil {
 		asEntry.Extensions.StaticInfo = static.Generate(s.Intfs, ingress, egress)
 	}
+	if s.LinkLatencies != nil {
+		asEntry.Extensions.StaticInfo = addMeasuredLatencies(asEntry.Extensions.StaticInfo,
+			s.LinkLatencies, interfaceTypeTable(s.Intfs), egress)
+	}
 
 	// Add the detachable Epic extension
 	if s.EPIC {
@@ -19,14 +19,24 @@ import (
 	"net"
 
 	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/scrypto/cppki"
 	"github.com/scionproto/scion/go/pkg/grpc"
 	cppb "github.com/scionproto/scion/go/pkg/proto/control_plane"
 )
 
+// LatestTRCProvider provides the ID of the latest TRC of the local ISD.
+type LatestTRCProvider interface {
+	LatestTRC(ctx context.Context) (cppki.TRCID, error)
+}
+
 // BeaconSender propagates beacons.
 type BeaconSender struct {
 	// Dialer dials a new gRPC connection.
 	Dialer grpc.Dialer
+	// TRCs provides the latest TRC that is announced with every beacon. If it
+	// is nil, no TRC is announced.
+	TRCs LatestTRCProvider
 }
 
 // SendBeacon sends a beacon to the remote.
@@ -39,9 +49,26 @@ func (r BeaconSender) SendBeacon(ctx context.Context, b *seg.PathSegment, remote
 	client := cppb.NewSegmentCreationServiceClient(conn)
 	_, err = client.Beacon(ctx,
 		&cppb.BeaconRequest{
-			Segment: seg.PathSegmentToPB(b),
+			Segment:   seg.PathSegmentToPB(b),
+			LatestTrc: r.latestTRC(ctx),
 		},
 		grpc.RetryProfile...,
 	)
 	return err
 }
+
+func (r BeaconSender) latestTRC(ctx context.Context) *cppb.TRCID {
+	if r.TRCs == nil {
+		return nil
+	}
+	id, err := r.TRCs.LatestTRC(ctx)
+	if err != nil {
+		log.FromCtx(ctx).Debug("Failed to load latest TRC, not announcing TRC", "err", err)
+		return nil
+	}
+	return &cppb.TRCID{
+		Isd:    uint32(id.ISD),
+		Base:   uint64(id.Base),
+		Serial: uint64(id.Serial),
+	}
+}
@@ -17,15 +17,19 @@ package grpc
 import (
 	"context"
 	"fmt"
+	"net"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
 	"github.com/scionproto/scion/go/cs/beacon"
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/ctrl/seg"
 	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/scrypto/cppki"
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/slayers/path/scion"
 	"github.com/scionproto/scion/go/lib/snet"
@@ -38,9 +42,18 @@ type BeaconHandler interface {
 	HandleBeacon(ctx context.Context, b beacon.Beacon, peer *snet.UDPAddr) error
 }
 
+// TRCAnnouncementHandler handles the TRC announcements attached to beacons.
+type TRCAnnouncementHandler interface {
+	HandleTRCAnnouncement(ctx context.Context, id cppki.TRCID, server net.Addr) error
+}
+
 // SegmentCreationServer handles beaconing requests.
 type SegmentCreationServer struct {
 	Handler BeaconHandler
+	// TRCHandler handles the TRC announced with the beacon. It is called in
+	// the RPC handler and must not block, slow work such as fetching the TRC
+	// updates must be handed off. If it is nil, announcements are ignored.
+	TRCHandler TRCAnnouncementHandler
 }
 
 func (s SegmentCreationServer) Beacon(ctx context.Context,
@@ -76,10 +89,41 @@ func (s SegmentCreationServer) Beacon(ctx context.Context,
 		// TODO(roosd): return better error with status code.
 		return nil, serrors.WrapStr("handling beacon", err)
 	}
+	if req.LatestTrc != nil && s.TRCHandler != nil {
+		s.handleTRCAnnouncement(ctx, req.LatestTrc, peer)
+	}
 	return &cppb.BeaconResponse{}, nil
 
 }
 
+// handleTRCAnnouncement passes the announced TRC to the handler. The TRC
+// updates are fetched from the control service of the beacon sender. Failures
+// do not affect the beacon, they are only logged.
+func (s SegmentCreationServer) handleTRCAnnouncement(ctx context.Context, pb *cppb.TRCID,
+	peer *snet.UDPAddr) {
+
+	logger := log.FromCtx(ctx)
+	id := cppki.TRCID{
+		ISD:    addr.ISD(pb.Isd),
+		Base:   scrypto.Version(pb.Base),
+		Serial: scrypto.Version(pb.Serial),
+	}
+	peerPath, err := peer.GetPath()
+	if err != nil {
+		logger.Debug("Failed to reverse path for TRC announcement", "peer", peer, "err", err)
+		return
+	}
+	server := &snet.SVCAddr{
+		IA:      peer.IA,
+		Path:    peerPath.Path(),
+		NextHop: peerPath.UnderlayNextHop(),
+		SVC:     addr.SvcCS,
+	}
+	if err := s.TRCHandler.HandleTRCAnnouncement(ctx, id, server); err != nil {
+		logger.Info("Failed to handle TRC announcement", "peer", peer, "id", id, "err", err)
+	}
+}
+
 // extractIngressIfID extracts the ingress interface ID from a path.
 func extractIngressIfID(path spath.Path) (common.IFIDType, error) {
 	var sp scion.Raw
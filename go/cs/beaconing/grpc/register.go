@@ -29,15 +29,18 @@ type Registrar struct {
 	Dialer grpc.Dialer
 }
 
-// RegisterSegment registers a segment with the remote.
-func (r Registrar) RegisterSegment(ctx context.Context, meta seg.Meta, remote net.Addr) error {
+// RegisterSegment registers a segment with the remote. It returns the
+// registration state ID reported by the remote.
+func (r Registrar) RegisterSegment(ctx context.Context, meta seg.Meta,
+	remote net.Addr) (uint64, error) {
+
 	conn, err := r.Dialer.Dial(ctx, remote)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer conn.Close()
 	client := cppb.NewSegmentRegistrationServiceClient(conn)
-	_, err = client.SegmentsRegistration(ctx,
+	rep, err := client.SegmentsRegistration(ctx,
 		&cppb.SegmentsRegistrationRequest{
 			Segments: map[int32]*cppb.SegmentsRegistrationRequest_Segments{
 				int32(meta.Type): {
@@ -49,5 +52,8 @@ func (r Registrar) RegisterSegment(ctx context.Context, meta seg.Meta, remote ne
 		},
 		grpc.RetryProfile...,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return rep.StateId, nil
 }
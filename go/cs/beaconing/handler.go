@@ -47,6 +47,9 @@ type Handler struct {
 	Inserter   BeaconInserter
 	Verifier   infra.Verifier
 	Interfaces *ifstate.Interfaces
+	// Trigger is notified about inserted beacons. If it is nil, received
+	// beacons do not trigger propagation.
+	Trigger *Trigger
 
 	BeaconsHandled metrics.Counter
 }
@@ -96,6 +99,9 @@ func (h Handler) HandleBeacon(ctx context.Context, b beacon.Beacon, peer *snet.U
 		return serrors.WrapStr("inserting beacon", err)
 
 	}
+	if stat.Inserted > 0 {
+		h.Trigger.BeaconReceived(b)
+	}
 	labels = labels.WithResult(resultValue(stat.Inserted, stat.Updated, stat.Filtered))
 	h.updateMetric(span, labels, err)
 	logger.Debug("Inserted beacon")
@@ -0,0 +1,171 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package beaconing
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/topology"
+)
+
+// IntfConfig configures beaconing on an egress interface. Zero values indicate
+// that the global default is used.
+type IntfConfig struct {
+	// OriginationInterval is the interval between originating beacons on the
+	// interface.
+	OriginationInterval time.Duration
+	// PropagationInterval is the interval between propagating beacons on the
+	// interface.
+	PropagationInterval time.Duration
+	// BestSetSize is the maximum number of beacons per originating AS that
+	// are propagated on the interface in a single run. It only has an effect
+	// if it is smaller than the best set size of the propagation policy.
+	BestSetSize int
+	// MaxBeaconsPerSecond is the maximum number of beacons per second that are
+	// sent on the interface.
+	MaxBeaconsPerSecond int
+}
+
+// IntfConfigs contains the beaconing configuration per link type and per
+// interface. The configuration of an interface takes precedence over the
+// configuration of its link type.
+type IntfConfigs struct {
+	LinkTypes  map[topology.LinkType]IntfConfig
+	Interfaces map[common.IFIDType]IntfConfig
+}
+
+// Get returns the configuration for the interface with the given link type.
+// Each value that is not set for the interface is inherited from the link
+// type.
+func (c IntfConfigs) Get(ifid common.IFIDType, linkType topology.LinkType) IntfConfig {
+	cfg := c.LinkTypes[linkType]
+	intf, ok := c.Interfaces[ifid]
+	if !ok {
+		return cfg
+	}
+	if intf.OriginationInterval != 0 {
+		cfg.OriginationInterval = intf.OriginationInterval
+	}
+	if intf.PropagationInterval != 0 {
+		cfg.PropagationInterval = intf.PropagationInterval
+	}
+	if intf.BestSetSize != 0 {
+		cfg.BestSetSize = intf.BestSetSize
+	}
+	if intf.MaxBeaconsPerSecond != 0 {
+		cfg.MaxBeaconsPerSecond = intf.MaxBeaconsPerSecond
+	}
+	return cfg
+}
+
+// RateLimiter caps the number of beacons per second that are sent on each
+// interface. It is shared between the originator and the propagator, such
+// that the cap applies to all beacons sent on the interface. A nil rate
+// limiter allows all beacons.
+type RateLimiter struct {
+	// Configs contains the per interface caps.
+	Configs IntfConfigs
+
+	mu      sync.Mutex
+	buckets map[common.IFIDType]*tokenBucket
+}
+
+// Allow indicates whether a beacon can be sent on the interface at the given
+// time. If it returns true, the beacon is accounted for.
+func (l *RateLimiter) Allow(ifid common.IFIDType, linkType topology.LinkType,
+	now time.Time) bool {
+
+	if l == nil {
+		return true
+	}
+	rate := l.Configs.Get(ifid, linkType).MaxBeaconsPerSecond
+	if rate <= 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[ifid]
+	if !ok || b.rate != float64(rate) {
+		if l.buckets == nil {
+			l.buckets = make(map[common.IFIDType]*tokenBucket)
+		}
+		b = &tokenBucket{rate: float64(rate), tokens: float64(rate), last: now}
+		l.buckets[ifid] = b
+	}
+	return b.take(now)
+}
+
+// tokenBucket allows a burst of one second worth of tokens.
+type tokenBucket struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) take(now time.Time) bool {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(b.rate, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// bestSets keeps track of the number of beacons per originating AS that are
+// propagated on each interface during a single propagation run.
+type bestSets struct {
+	configs  IntfConfigs
+	linkType topology.LinkType
+
+	mu     sync.Mutex
+	counts map[bestSetKey]int
+}
+
+type bestSetKey struct {
+	ifid common.IFIDType
+	src  addr.IA
+}
+
+func newBestSets(configs IntfConfigs, linkType topology.LinkType) *bestSets {
+	return &bestSets{
+		configs:  configs,
+		linkType: linkType,
+		counts:   make(map[bestSetKey]int),
+	}
+}
+
+// take indicates whether a beacon from the originating AS can still be
+// propagated on the interface. If it returns true, the beacon is accounted for.
+func (s *bestSets) take(ifid common.IFIDType, src addr.IA) bool {
+	size := s.configs.Get(ifid, s.linkType).BestSetSize
+	if size <= 0 {
+		return true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := bestSetKey{ifid: ifid, src: src}
+	if s.counts[k] >= size {
+		return false
+	}
+	s.counts[k]++
+	return true
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package beaconing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/topology"
+)
+
+func TestIntfConfigsGet(t *testing.T) {
+	configs := IntfConfigs{
+		LinkTypes: map[topology.LinkType]IntfConfig{
+			topology.Core: {
+				OriginationInterval: time.Second,
+				PropagationInterval: time.Second,
+				BestSetSize:         5,
+			},
+		},
+		Interfaces: map[common.IFIDType]IntfConfig{
+			1: {PropagationInterval: time.Minute, MaxBeaconsPerSecond: 10},
+		},
+	}
+	assert.Equal(t, IntfConfig{
+		OriginationInterval: time.Second,
+		PropagationInterval: time.Minute,
+		BestSetSize:         5,
+		MaxBeaconsPerSecond: 10,
+	}, configs.Get(1, topology.Core))
+	assert.Equal(t, IntfConfig{
+		OriginationInterval: time.Second,
+		PropagationInterval: time.Second,
+		BestSetSize:         5,
+	}, configs.Get(2, topology.Core))
+	assert.Equal(t, IntfConfig{
+		PropagationInterval: time.Minute,
+		MaxBeaconsPerSecond: 10,
+	}, configs.Get(1, topology.Child))
+	assert.Equal(t, IntfConfig{}, configs.Get(2, topology.Child))
+}
+
+func TestRateLimiterAllow(t *testing.T) {
+	l := &RateLimiter{
+		Configs: IntfConfigs{
+			Interfaces: map[common.IFIDType]IntfConfig{
+				1: {MaxBeaconsPerSecond: 2},
+			},
+		},
+	}
+	now := time.Now()
+	// The burst is one second worth of beacons.
+	assert.True(t, l.Allow(1, topology.Core, now))
+	assert.True(t, l.Allow(1, topology.Core, now))
+	assert.False(t, l.Allow(1, topology.Core, now))
+	// Tokens are refilled at the configured rate.
+	assert.True(t, l.Allow(1, topology.Core, now.Add(500*time.Millisecond)))
+	assert.False(t, l.Allow(1, topology.Core, now.Add(500*time.Millisecond)))
+	// Interfaces without a cap are not limited.
+	for i := 0; i < 10; i++ {
+		assert.True(t, l.Allow(2, topology.Core, now))
+	}
+	// A nil rate limiter allows all beacons.
+	var nilLimiter *RateLimiter
+	assert.True(t, nilLimiter.Allow(1, topology.Core, now))
+}
@@ -167,11 +167,12 @@ func (m *MockRPC) EXPECT() *MockRPCMockRecorder {
 }
 
 // RegisterSegment mocks base method
-func (m *MockRPC) RegisterSegment(arg0 context.Context, arg1 seg.Meta, arg2 net.Addr) error {
+func (m *MockRPC) RegisterSegment(arg0 context.Context, arg1 seg.Meta, arg2 net.Addr) (uint64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "RegisterSegment", arg0, arg1, arg2)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // RegisterSegment indicates an expected call of RegisterSegment
@@ -50,6 +50,12 @@ type Originator struct {
 	IA           addr.IA
 	Signer       seg.Signer
 	Intfs        *ifstate.Interfaces
+	// IntfConfigs overrides the origination interval per link type and per
+	// interface.
+	IntfConfigs IntfConfigs
+	// RateLimiter caps the number of beacons per second on each interface. If
+	// it is nil, the number of beacons is not capped.
+	RateLimiter *RateLimiter
 
 	Originated metrics.Counter
 
@@ -86,7 +92,7 @@ func (o *Originator) Run(ctx context.Context) {
 func (o *Originator) originateBeacons(ctx context.Context, linkType topology.LinkType) {
 	logger := log.FromCtx(ctx)
 	active := sortedIntfs(o.Intfs, linkType)
-	intfs := o.needBeacon(active)
+	intfs := o.needBeacon(active, linkType)
 	if len(intfs) == 0 {
 		return
 	}
@@ -114,20 +120,26 @@ func (o *Originator) originateBeacons(ctx context.Context, linkType topology.Lin
 	o.logSummary(logger, s, linkType)
 }
 
-// needBeacon returns a list of interfaces that need a beacon.
-func (o *Originator) needBeacon(active []common.IFIDType) []common.IFIDType {
-	if o.Tick.Passed() {
-		return active
-	}
+// needBeacon returns a list of interfaces that need a beacon. Interfaces that
+// exceed their beacon rate are skipped, they are beaconed on in a later run.
+func (o *Originator) needBeacon(active []common.IFIDType,
+	linkType topology.LinkType) []common.IFIDType {
+
 	stale := make([]common.IFIDType, 0, len(active))
 	for _, ifid := range active {
 		intf := o.Intfs.Get(ifid)
 		if intf == nil {
 			continue
 		}
-		if o.Tick.Overdue(intf.LastOriginate()) {
-			stale = append(stale, ifid)
+		interval := o.IntfConfigs.Get(ifid, linkType).OriginationInterval
+		if !o.Tick.Due(intf.LastOriginate(), interval) {
+			continue
+		}
+		if !o.RateLimiter.Allow(ifid, linkType, o.Tick.Now()) {
+			o.incrementMetrics(originatorLabels{Egress: ifid, Result: "err_rate_limited"})
+			continue
 		}
+		stale = append(stale, ifid)
 	}
 	return stale
 }
@@ -213,11 +225,11 @@ func (o *beaconOriginator) onSuccess(intf *ifstate.Interface) {
 	o.summary.Inc()
 }
 
-func (o *beaconOriginator) incrementMetrics(labels originatorLabels) {
-	if o.Originator.Originated == nil {
+func (o *Originator) incrementMetrics(labels originatorLabels) {
+	if o.Originated == nil {
 		return
 	}
-	o.Originator.Originated.With(labels.Expand()...).Add(1)
+	o.Originated.With(labels.Expand()...).Add(1)
 }
 
 type originatorLabels struct {
@@ -37,6 +37,7 @@ import (
 	"github.com/scionproto/scion/go/lib/infra/modules/itopo/itopotest"
 	"github.com/scionproto/scion/go/lib/scrypto/signed"
 	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/topology"
 	cryptopb "github.com/scionproto/scion/go/pkg/proto/crypto"
 )
 
@@ -147,6 +148,74 @@ func TestOriginatorRun(t *testing.T) {
 		// Fourth run. Since period has passed, two writes are expected.
 		o.Run(context.Background())
 	})
+	t.Run("Interface configuration", func(t *testing.T) {
+		testCases := map[string]struct {
+			IntfConfigs IntfConfigs
+			// Expected is the number of beacons in the second run.
+			Expected int
+		}{
+			"interval": {
+				IntfConfigs: IntfConfigs{
+					Interfaces: map[common.IFIDType]IntfConfig{
+						42: {OriginationInterval: time.Nanosecond},
+					},
+				},
+				Expected: 1,
+			},
+			"link type interval": {
+				IntfConfigs: IntfConfigs{
+					LinkTypes: map[topology.LinkType]IntfConfig{
+						topology.Core: {OriginationInterval: time.Nanosecond},
+					},
+				},
+				Expected: 3,
+			},
+			"rate limit": {
+				IntfConfigs: IntfConfigs{
+					Interfaces: map[common.IFIDType]IntfConfig{
+						42: {OriginationInterval: time.Nanosecond, MaxBeaconsPerSecond: 1},
+					},
+				},
+				Expected: 0,
+			},
+		}
+		for name, tc := range testCases {
+			name, tc := name, tc
+			t.Run(name, func(t *testing.T) {
+				mctrl := gomock.NewController(t)
+				defer mctrl.Finish()
+				intfs := ifstate.NewInterfaces(topoProvider.Get().IFInfoMap(),
+					ifstate.Config{})
+				sender := mock_beaconing.NewMockBeaconSender(mctrl)
+
+				o := Originator{
+					Extender: &DefaultExtender{
+						IA:         topoProvider.Get().IA(),
+						MTU:        topoProvider.Get().MTU(),
+						Signer:     signer,
+						Intfs:      intfs,
+						MAC:        macFactory,
+						MaxExpTime: func() uint8 { return uint8(beacon.DefaultMaxExpTime) },
+						StaticInfo: func() *StaticInfoCfg { return nil },
+					},
+					BeaconSender: sender,
+					IA:           topoProvider.Get().IA(),
+					Signer:       signer,
+					Intfs:        intfs,
+					Tick:         NewTick(time.Hour),
+					IntfConfigs:  tc.IntfConfigs,
+					RateLimiter:  &RateLimiter{Configs: tc.IntfConfigs},
+				}
+				sender.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+					gomock.Any()).Times(4 + tc.Expected).Return(nil)
+				// Initial run. Beacons on all interfaces expected.
+				o.Run(context.Background())
+				// Second run. Only interfaces with a shorter interval are
+				// beaconed on.
+				o.Run(context.Background())
+			})
+		}
+	})
 }
 
 type segVerifier struct {
@@ -62,6 +62,15 @@ type Propagator struct {
 	Intfs        *ifstate.Interfaces
 	Core         bool
 	AllowIsdLoop bool
+	// IntfConfigs overrides the propagation interval and the best set size
+	// per link type and per interface.
+	IntfConfigs IntfConfigs
+	// RateLimiter caps the number of beacons per second on each interface. If
+	// it is nil, the number of beacons is not capped.
+	RateLimiter *RateLimiter
+	// Trigger requests triggered propagations. If it is nil, beacons are only
+	// propagated according to the propagation intervals.
+	Trigger *Trigger
 
 	Propagated     metrics.Counter
 	InternalErrors metrics.Counter
@@ -89,10 +98,12 @@ func (p *Propagator) Run(ctx context.Context) {
 
 func (p *Propagator) run(ctx context.Context) error {
 	logger := log.FromCtx(ctx)
-	intfs := p.needsBeacons(logger)
+	linkType := p.linkType()
+	intfs := p.needsBeacons(logger, linkType)
 	if len(intfs) == 0 {
 		return nil
 	}
+	bestSets := newBestSets(p.IntfConfigs, linkType)
 	peers := sortedIntfs(p.Intfs, topology.Peer)
 	beacons, err := p.Provider.BeaconsToPropagate(ctx)
 	if err != nil {
@@ -113,11 +124,14 @@ func (p *Propagator) run(ctx context.Context) error {
 		b := beaconPropagator{
 			Propagator: p,
 			beacon:     bOrErr.Beacon,
-			intfs:      intfs,
 			peers:      peers,
+			linkType:   linkType,
 			summary:    s,
 			logger:     logger,
 		}
+		// The egress interfaces are selected before starting the goroutine,
+		// such that the best sets are filled in the order of the provider.
+		b.intfs = b.selectIntfs(intfs, bestSets)
 		b.start(ctx, &wg)
 	}
 	wg.Wait()
@@ -127,16 +141,16 @@ func (p *Propagator) run(ctx context.Context) error {
 
 // needsBeacons returns a list of active interface ids that beacons should be
 // propagated to. In a core AS, these are all active core links. In a non-core
-// AS, these are all active child links.
-func (p *Propagator) needsBeacons(logger log.Logger) []common.IFIDType {
-	var intfs []common.IFIDType
-	if p.Core {
-		intfs = sortedIntfs(p.Intfs, topology.Core)
-	} else {
-		intfs = sortedIntfs(p.Intfs, topology.Child)
-	}
-	if p.Tick.Passed() {
-		return intfs
+// AS, these are all active child links. Interfaces that have not been
+// propagated on yet, e.g., because they were just added, are always included.
+// In a triggered propagation, all interfaces are included.
+func (p *Propagator) needsBeacons(logger log.Logger,
+	linkType topology.LinkType) []common.IFIDType {
+
+	intfs := sortedIntfs(p.Intfs, linkType)
+	triggered := p.Trigger.consume(p.Tick.Now())
+	if triggered {
+		logger.Debug("Triggered propagation", "egress_interfaces", intfs)
 	}
 	stale := make([]common.IFIDType, 0, len(intfs))
 	for _, ifid := range intfs {
@@ -144,13 +158,22 @@ func (p *Propagator) needsBeacons(logger log.Logger) []common.IFIDType {
 		if intf == nil {
 			continue
 		}
-		if p.Tick.Overdue(intf.LastPropagate()) {
+		interval := p.IntfConfigs.Get(ifid, linkType).PropagationInterval
+		if triggered || p.Tick.Due(intf.LastPropagate(), interval) {
 			stale = append(stale, ifid)
 		}
 	}
 	return stale
 }
 
+// linkType returns the link type of the target interfaces.
+func (p *Propagator) linkType() topology.LinkType {
+	if p.Core {
+		return topology.Core
+	}
+	return topology.Child
+}
+
 func (p *Propagator) logSummary(logger log.Logger, s *summary) {
 	if p.Tick.Passed() {
 		logger.Debug("Propagated beacons",
@@ -170,20 +193,21 @@ func (p *Propagator) incrementInternalErrors() {
 	p.InternalErrors.Add(1)
 }
 
-// beaconPropagator propagates one beacon to all active interfaces.
+// beaconPropagator propagates one beacon on the selected egress interfaces.
 type beaconPropagator struct {
 	*Propagator
-	wg      sync.WaitGroup
-	beacon  beacon.Beacon
-	intfs   []common.IFIDType
-	peers   []common.IFIDType
-	success ctr
-	summary *summary
-	logger  log.Logger
+	wg       sync.WaitGroup
+	beacon   beacon.Beacon
+	intfs    []common.IFIDType
+	peers    []common.IFIDType
+	linkType topology.LinkType
+	success  ctr
+	summary  *summary
+	logger   log.Logger
 }
 
 // start adds to the wait group and starts propagation of the beacon on
-// all active interfaces.
+// the selected interfaces.
 func (p *beaconPropagator) start(ctx context.Context, wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
@@ -196,14 +220,41 @@ func (p *beaconPropagator) start(ctx context.Context, wg *sync.WaitGroup) {
 	}()
 }
 
-func (p *beaconPropagator) propagate(ctx context.Context) error {
-	pb := seg.PathSegmentToPB(p.beacon.Segment)
-	var expected int
-	for _, egIfid := range p.intfs {
+// selectIntfs returns the interfaces the beacon is propagated on. Interfaces
+// that would create a loop, where the best set of the originating AS is
+// already full, or where the rate limit is exceeded, are skipped.
+func (p *beaconPropagator) selectIntfs(intfs []common.IFIDType,
+	bestSets *bestSets) []common.IFIDType {
+
+	var selected []common.IFIDType
+	for _, egIfid := range intfs {
 		if p.shouldIgnore(p.beacon, egIfid) {
 			continue
 		}
-		expected++
+		if !bestSets.take(egIfid, p.beacon.Segment.FirstIA()) {
+			continue
+		}
+		if !p.RateLimiter.Allow(egIfid, p.linkType, p.Tick.Now()) {
+			p.incrementMetrics(propagatorLabels{
+				StartIA: p.beacon.Segment.FirstIA(),
+				Ingress: p.beacon.InIfId,
+				Egress:  egIfid,
+				Result:  "err_rate_limited",
+			})
+			continue
+		}
+		selected = append(selected, egIfid)
+	}
+	return selected
+}
+
+func (p *beaconPropagator) propagate(ctx context.Context) error {
+	expected := len(p.intfs)
+	if expected == 0 {
+		return nil
+	}
+	pb := seg.PathSegmentToPB(p.beacon.Segment)
+	for _, egIfid := range p.intfs {
 		// Create a "copy" from the original beacon to avoid races on the
 		// ASEntry slice.
 		ps, err := seg.BeaconFromPB(pb)
@@ -214,9 +265,6 @@ func (p *beaconPropagator) propagate(ctx context.Context) error {
 		p.extendAndSend(ctx, beacon.Beacon{Segment: ps, InIfId: p.beacon.InIfId}, egIfid)
 	}
 	p.wg.Wait()
-	if expected == 0 {
-		return nil
-	}
 	if p.success.c <= 0 {
 		return serrors.New("no beacon propagated", "expected", expected)
 	}
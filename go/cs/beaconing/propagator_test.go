@@ -20,6 +20,7 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"net"
+	"sync"
 	"testing"
 	"time"
 
@@ -35,6 +36,7 @@ import (
 	"github.com/scionproto/scion/go/lib/ctrl/seg"
 	"github.com/scionproto/scion/go/lib/infra/modules/itopo/itopotest"
 	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/topology"
 	"github.com/scionproto/scion/go/lib/xtest/graph"
 )
 
@@ -244,4 +246,209 @@ func TestPropagatorRun(t *testing.T) {
 		// Fourth run. Since period has passed, two writes are expected.
 		p.Run(context.Background())
 	})
+	t.Run("Triggered propagation", func(t *testing.T) {
+		mctrl := gomock.NewController(t)
+		defer mctrl.Finish()
+		topoProvider := itopotest.TopoProviderFromFile(t, topoCore)
+		intfs := ifstate.NewInterfaces(topoProvider.Get().IFInfoMap(), ifstate.Config{})
+		provider := mock_beaconing.NewMockBeaconProvider(mctrl)
+		sender := mock_beaconing.NewMockBeaconSender(mctrl)
+
+		trigger := &Trigger{}
+		p := Propagator{
+			Extender: &DefaultExtender{
+				IA:         topoProvider.Get().IA(),
+				MTU:        topoProvider.Get().MTU(),
+				Signer:     testSigner(t, priv, topoProvider.Get().IA()),
+				Intfs:      intfs,
+				MAC:        macFactory,
+				MaxExpTime: func() uint8 { return uint8(beacon.DefaultMaxExpTime) },
+				StaticInfo: func() *StaticInfoCfg { return nil },
+			},
+			BeaconSender: sender,
+			IA:           topoProvider.Get().IA(),
+			Signer:       testSigner(t, priv, topoProvider.Get().IA()),
+			Intfs:        intfs,
+			Tick:         NewTick(time.Hour),
+			Core:         true,
+			Provider:     provider,
+			Trigger:      trigger,
+		}
+
+		g := graph.NewDefaultGraph(mctrl)
+		// The provider is queried in every run, since the interface to
+		// 1-ff00:0:120 is never beaconed on because of loops.
+		provider.EXPECT().BeaconsToPropagate(gomock.Any()).Times(4).DoAndReturn(
+			func(_ interface{}) (<-chan beacon.BeaconOrErr, error) {
+				res := make(chan beacon.BeaconOrErr, 1)
+				res <- testBeaconOrErr(g, beacons[true][0])
+				close(res)
+				return res, nil
+			},
+		)
+		// 1. Initial run where the beacon is sent on all interfaces. -> 2 calls
+		// 2. Run where no beacon is sent. -> no call
+		// 3. Triggered run where the beacon is sent on all interfaces. -> 2 calls
+		// 4. Run where no beacon is sent, the beacon is not better. -> no call
+		sender.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any()).Times(4).Return(nil)
+		p.Run(context.Background())
+		p.Run(context.Background())
+		trigger.BeaconReceived(testBeaconOrErr(g, beacons[true][1]).Beacon)
+		p.Run(context.Background())
+		trigger.BeaconReceived(testBeaconOrErr(g, beacons[true][1]).Beacon)
+		p.Run(context.Background())
+	})
+	t.Run("Interface configuration", func(t *testing.T) {
+		testCases := map[string]struct {
+			IntfConfigs IntfConfigs
+			Expected    int
+		}{
+			"none": {
+				Expected: 4,
+			},
+			"best set size": {
+				IntfConfigs: IntfConfigs{
+					Interfaces: map[common.IFIDType]IntfConfig{
+						graph.If_110_X_210_X: {BestSetSize: 1},
+					},
+				},
+				Expected: 3,
+			},
+			"rate limit": {
+				IntfConfigs: IntfConfigs{
+					LinkTypes: map[topology.LinkType]IntfConfig{
+						topology.Core: {MaxBeaconsPerSecond: 1},
+					},
+				},
+				Expected: 2,
+			},
+		}
+		for name, tc := range testCases {
+			name, tc := name, tc
+			t.Run(name, func(t *testing.T) {
+				mctrl := gomock.NewController(t)
+				defer mctrl.Finish()
+				topoProvider := itopotest.TopoProviderFromFile(t, topoCore)
+				intfs := ifstate.NewInterfaces(topoProvider.Get().IFInfoMap(),
+					ifstate.Config{})
+				provider := mock_beaconing.NewMockBeaconProvider(mctrl)
+				sender := mock_beaconing.NewMockBeaconSender(mctrl)
+
+				p := Propagator{
+					Extender: &DefaultExtender{
+						IA:         topoProvider.Get().IA(),
+						MTU:        topoProvider.Get().MTU(),
+						Signer:     testSigner(t, priv, topoProvider.Get().IA()),
+						Intfs:      intfs,
+						MAC:        macFactory,
+						MaxExpTime: func() uint8 { return uint8(beacon.DefaultMaxExpTime) },
+						StaticInfo: func() *StaticInfoCfg { return nil },
+					},
+					BeaconSender: sender,
+					IA:           topoProvider.Get().IA(),
+					Signer:       testSigner(t, priv, topoProvider.Get().IA()),
+					Intfs:        intfs,
+					Tick:         NewTick(time.Hour),
+					Core:         true,
+					Provider:     provider,
+					IntfConfigs:  tc.IntfConfigs,
+					RateLimiter:  &RateLimiter{Configs: tc.IntfConfigs},
+				}
+
+				g := graph.NewDefaultGraph(mctrl)
+				// Two beacons from the same originating AS. The interface to
+				// 1-ff00:0:120 is never beaconed on because of loops.
+				provider.EXPECT().BeaconsToPropagate(gomock.Any()).DoAndReturn(
+					func(_ interface{}) (<-chan beacon.BeaconOrErr, error) {
+						res := make(chan beacon.BeaconOrErr, 2)
+						res <- testBeaconOrErr(g, beacons[true][0])
+						res <- testBeaconOrErr(g, beacons[true][0])
+						close(res)
+						return res, nil
+					},
+				)
+				sender.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+					gomock.Any()).Times(tc.Expected).Return(nil)
+				p.Run(context.Background())
+			})
+		}
+	})
+	t.Run("Best set in provider order", func(t *testing.T) {
+		mctrl := gomock.NewController(t)
+		defer mctrl.Finish()
+		topoProvider := itopotest.TopoProviderFromFile(t, topoCore)
+		intfs := ifstate.NewInterfaces(topoProvider.Get().IFInfoMap(), ifstate.Config{})
+		provider := mock_beaconing.NewMockBeaconProvider(mctrl)
+		sender := mock_beaconing.NewMockBeaconSender(mctrl)
+
+		intfConfigs := IntfConfigs{
+			LinkTypes: map[topology.LinkType]IntfConfig{
+				topology.Core: {BestSetSize: 2},
+			},
+		}
+		p := Propagator{
+			Extender: &DefaultExtender{
+				IA:         topoProvider.Get().IA(),
+				MTU:        topoProvider.Get().MTU(),
+				Signer:     testSigner(t, priv, topoProvider.Get().IA()),
+				Intfs:      intfs,
+				MAC:        macFactory,
+				MaxExpTime: func() uint8 { return uint8(beacon.DefaultMaxExpTime) },
+				StaticInfo: func() *StaticInfoCfg { return nil },
+			},
+			BeaconSender: sender,
+			IA:           topoProvider.Get().IA(),
+			Signer:       testSigner(t, priv, topoProvider.Get().IA()),
+			Intfs:        intfs,
+			Tick:         NewTick(time.Hour),
+			Core:         true,
+			Provider:     provider,
+			IntfConfigs:  intfConfigs,
+		}
+
+		g := graph.NewDefaultGraph(mctrl)
+		// More beacons from the same originating AS than fit into the best
+		// set. The beacons are identified by their segment ID.
+		var ordered []beacon.BeaconOrErr
+		ids := make(map[uint16]bool)
+		for len(ordered) < 10 {
+			b := testBeaconOrErr(g, beacons[true][0])
+			if id := b.Beacon.Segment.Info.SegmentID; !ids[id] {
+				ids[id] = true
+				ordered = append(ordered, b)
+			}
+		}
+		provider.EXPECT().BeaconsToPropagate(gomock.Any()).DoAndReturn(
+			func(_ interface{}) (<-chan beacon.BeaconOrErr, error) {
+				res := make(chan beacon.BeaconOrErr, len(ordered))
+				for _, b := range ordered {
+					res <- b
+				}
+				close(res)
+				return res, nil
+			},
+		)
+		var mtx sync.Mutex
+		sent := make(map[common.IFIDType][]uint16)
+		// The interface to 1-ff00:0:120 is never beaconed on because of loops.
+		sender.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any()).Times(4).DoAndReturn(
+			func(_ context.Context, b *seg.PathSegment, _ addr.IA,
+				egress common.IFIDType, _ *net.UDPAddr) error {
+
+				mtx.Lock()
+				defer mtx.Unlock()
+				sent[egress] = append(sent[egress], b.Info.SegmentID)
+				return nil
+			},
+		)
+		p.Run(context.Background())
+		first := []uint16{
+			ordered[0].Beacon.Segment.Info.SegmentID,
+			ordered[1].Beacon.Segment.Info.SegmentID,
+		}
+		assert.ElementsMatch(t, first, sent[graph.If_110_X_130_A])
+		assert.ElementsMatch(t, first, sent[graph.If_110_X_210_X])
+	})
 }
@@ -0,0 +1,303 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package beaconing
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/metrics"
+)
+
+const (
+	// DefaultRegistrationRetryInterval is the default interval after which
+	// the registration at a failing remote is retried for the first time.
+	DefaultRegistrationRetryInterval = time.Second
+	// DefaultRegistrationFailureThreshold is the default number of
+	// consecutive failures after which the registrations at a remote are
+	// reported as failing persistently.
+	DefaultRegistrationFailureThreshold = 3
+
+	// maxRetryBackoffExp caps the exponential backoff of retries at
+	// 2^maxRetryBackoffExp times the retry interval.
+	maxRetryBackoffExp = 6
+	// maxStateIDs is the number of state IDs that are remembered per remote.
+	// Remotes with multiple control service instances, e.g., behind an
+	// anycast address, report one state ID per instance.
+	maxStateIDs = 8
+)
+
+// RegisteredSegment describes a segment that is registered at a remote.
+type RegisteredSegment struct {
+	// ID is the segment ID.
+	ID []byte
+	// Type is the type of the segment.
+	Type seg.Type
+	// Registered is the time of the last successful registration.
+	Registered time.Time
+	// Expiry is the expiration time of the segment.
+	Expiry time.Time
+}
+
+// RegistrationState describes the registration state at a remote.
+type RegistrationState struct {
+	// Remote is the remote AS.
+	Remote addr.IA
+	// StateID is the last registration state ID reported by the remote.
+	StateID uint64
+	// LastSuccess is the time of the last successful registration.
+	LastSuccess time.Time
+	// LastFailure is the time of the last failed registration.
+	LastFailure time.Time
+	// LastError is the error of the last failed registration.
+	LastError string
+	// ConsecutiveFailures is the number of failed registrations since the
+	// last successful one.
+	ConsecutiveFailures int
+	// Segments are the segments that are registered at the remote and have
+	// not expired yet, sorted by ID.
+	Segments []RegisteredSegment
+}
+
+// RegistrationTracker keeps track of the segments that are registered at each
+// remote. It detects remotes that lost their registration state, and remotes
+// where registrations fail, such that the segments can be registered again
+// before the next regular registration. The state IDs of the recently
+// responding instances of a remote are remembered, such that alternating
+// between the instances of a remote is not mistaken for a lost state. It is
+// safe for concurrent use.
+type RegistrationTracker struct {
+	// RetryInterval is the interval after which the registration at a failing
+	// remote is retried. It is doubled with every consecutive failure. If it
+	// is zero, DefaultRegistrationRetryInterval is used.
+	RetryInterval time.Duration
+	// FailureThreshold is the number of consecutive failures after which the
+	// registrations at a remote are logged as failing persistently. If it is
+	// zero, DefaultRegistrationFailureThreshold is used.
+	FailureThreshold int
+	// Failures is set to the number of consecutive failures per remote. The
+	// label is "remote_isd_as". If it is nil, nothing is reported.
+	Failures metrics.Gauge
+
+	mu      sync.Mutex
+	remotes map[addr.IA]*remoteState
+}
+
+type remoteState struct {
+	// stateID is the last reported state ID.
+	stateID uint64
+	// stateIDs are the recently reported state IDs, most recent first.
+	stateIDs            []uint64
+	lastSuccess         time.Time
+	lastFailure         time.Time
+	lastErr             string
+	consecutiveFailures int
+	// reregister indicates that the remote lost its state and all segments
+	// should be registered again.
+	reregister bool
+	// retryAt is the time at which the registration at a failing remote is
+	// retried.
+	retryAt  time.Time
+	segments map[string]RegisteredSegment
+}
+
+// Success records the successful registration of the segment at the remote.
+// If the remote reports a state ID that was not reported recently, the
+// previously registered segments are considered lost, and a registration of
+// all segments at the remote is requested.
+func (t *RegistrationTracker) Success(ctx context.Context, remote addr.IA, meta seg.Meta,
+	stateID uint64, now time.Time) {
+
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.remote(remote)
+	if stateID != 0 && len(s.stateIDs) > 0 && !s.knownStateID(stateID) {
+		log.FromCtx(ctx).Info("Remote lost registered segments, registering again",
+			"remote", remote, "lost", len(s.segments))
+		s.segments = make(map[string]RegisteredSegment)
+		s.reregister = true
+	}
+	s.addStateID(stateID)
+	s.stateID = stateID
+	s.lastSuccess = now
+	s.consecutiveFailures = 0
+	s.retryAt = time.Time{}
+	id := meta.Segment.ID()
+	s.segments[string(id)] = RegisteredSegment{
+		ID:         id,
+		Type:       meta.Type,
+		Registered: now,
+		Expiry:     meta.Segment.MaxExpiry(),
+	}
+	metrics.GaugeSet(metrics.GaugeWith(t.Failures, "remote_isd_as", remote.String()), 0)
+}
+
+// Failure records the failed registration at the remote.
+func (t *RegistrationTracker) Failure(ctx context.Context, remote addr.IA, err error,
+	now time.Time) {
+
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.remote(remote)
+	s.lastFailure = now
+	s.lastErr = err.Error()
+	s.consecutiveFailures++
+	s.retryAt = now.Add(t.backoff(s.consecutiveFailures))
+	if s.consecutiveFailures == t.failureThreshold() {
+		log.FromCtx(ctx).Error("Segment registration failing persistently",
+			"remote", remote, "failures", s.consecutiveFailures, "err", err)
+	}
+	metrics.GaugeSet(metrics.GaugeWith(t.Failures, "remote_isd_as", remote.String()),
+		float64(s.consecutiveFailures))
+}
+
+// DueRemotes returns the remotes at which segments should be registered
+// before the next regular registration, because the remote lost its
+// registration state, or because the registration at the failing remote
+// should be retried. Every request is only reported once. If no registration
+// is due, nil is returned.
+func (t *RegistrationTracker) DueRemotes(now time.Time) map[addr.IA]struct{} {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var due map[addr.IA]struct{}
+	for remote, s := range t.remotes {
+		reregister := s.reregister
+		s.reregister = false
+		if s.consecutiveFailures > 0 && !now.Before(s.retryAt) {
+			s.retryAt = now.Add(t.backoff(s.consecutiveFailures))
+			reregister = true
+		}
+		if !reregister {
+			continue
+		}
+		if due == nil {
+			due = make(map[addr.IA]struct{})
+		}
+		due[remote] = struct{}{}
+	}
+	return due
+}
+
+// States returns the registration state of all remotes, sorted by remote.
+// Expired segments are removed, as are remotes without segments that do not
+// fail.
+func (t *RegistrationTracker) States(now time.Time) []RegistrationState {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	states := make([]RegistrationState, 0, len(t.remotes))
+	for remote, s := range t.remotes {
+		for id, registered := range s.segments {
+			if now.After(registered.Expiry) {
+				delete(s.segments, id)
+			}
+		}
+		if len(s.segments) == 0 && s.consecutiveFailures == 0 && !s.reregister {
+			delete(t.remotes, remote)
+			continue
+		}
+		segments := make([]RegisteredSegment, 0, len(s.segments))
+		for _, registered := range s.segments {
+			segments = append(segments, registered)
+		}
+		sort.Slice(segments, func(i, j int) bool {
+			return string(segments[i].ID) < string(segments[j].ID)
+		})
+		states = append(states, RegistrationState{
+			Remote:              remote,
+			StateID:             s.stateID,
+			LastSuccess:         s.lastSuccess,
+			LastFailure:         s.lastFailure,
+			LastError:           s.lastErr,
+			ConsecutiveFailures: s.consecutiveFailures,
+			Segments:            segments,
+		})
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Remote.IAInt() < states[j].Remote.IAInt()
+	})
+	return states
+}
+
+func (t *RegistrationTracker) remote(remote addr.IA) *remoteState {
+	if t.remotes == nil {
+		t.remotes = make(map[addr.IA]*remoteState)
+	}
+	s, ok := t.remotes[remote]
+	if !ok {
+		s = &remoteState{segments: make(map[string]RegisteredSegment)}
+		t.remotes[remote] = s
+	}
+	return s
+}
+
+func (s *remoteState) knownStateID(stateID uint64) bool {
+	for _, known := range s.stateIDs {
+		if known == stateID {
+			return true
+		}
+	}
+	return false
+}
+
+// addStateID moves the state ID to the front of the recently reported state
+// IDs, and drops the least recently reported one if there are too many.
+func (s *remoteState) addStateID(stateID uint64) {
+	if stateID == 0 {
+		return
+	}
+	ids := []uint64{stateID}
+	for _, known := range s.stateIDs {
+		if known != stateID && len(ids) < maxStateIDs {
+			ids = append(ids, known)
+		}
+	}
+	s.stateIDs = ids
+}
+
+func (t *RegistrationTracker) backoff(failures int) time.Duration {
+	interval := t.RetryInterval
+	if interval == 0 {
+		interval = DefaultRegistrationRetryInterval
+	}
+	exp := failures - 1
+	if exp > maxRetryBackoffExp {
+		exp = maxRetryBackoffExp
+	}
+	return interval << uint(exp)
+}
+
+func (t *RegistrationTracker) failureThreshold() int {
+	if t.FailureThreshold == 0 {
+		return DefaultRegistrationFailureThreshold
+	}
+	return t.FailureThreshold
+}
@@ -0,0 +1,167 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package beaconing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/metrics"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/lib/xtest/graph"
+)
+
+func TestRegistrationTracker(t *testing.T) {
+	mctrl := gomock.NewController(t)
+	defer mctrl.Finish()
+	g := graph.NewDefaultGraph(mctrl)
+	segA := seg.Meta{
+		Type:    seg.TypeDown,
+		Segment: g.Beacon([]common.IFIDType{graph.If_120_X_111_B}),
+	}
+	segB := seg.Meta{
+		Type:    seg.TypeDown,
+		Segment: g.Beacon([]common.IFIDType{graph.If_130_B_120_A, graph.If_120_X_111_B}),
+	}
+	core120 := xtest.MustParseIA("1-ff00:0:120")
+	core130 := xtest.MustParseIA("1-ff00:0:130")
+	now := segA.Segment.Info.Timestamp
+
+	t.Run("nil tracker", func(t *testing.T) {
+		var tracker *RegistrationTracker
+		tracker.Success(context.Background(), core120, segA, 1, now)
+		tracker.Failure(context.Background(), core120, serrors.New("test"), now)
+		assert.Empty(t, tracker.DueRemotes(now))
+		assert.Empty(t, tracker.States(now))
+	})
+	t.Run("success is tracked", func(t *testing.T) {
+		tracker := &RegistrationTracker{}
+		tracker.Success(context.Background(), core120, segA, 1, now)
+		tracker.Success(context.Background(), core130, segB, 2, now.Add(time.Second))
+		assert.Empty(t, tracker.DueRemotes(now.Add(time.Second)))
+
+		states := tracker.States(now.Add(time.Second))
+		require.Len(t, states, 2)
+		assert.Equal(t, core120, states[0].Remote)
+		assert.Equal(t, uint64(1), states[0].StateID)
+		assert.Equal(t, now, states[0].LastSuccess)
+		assert.Equal(t, []RegisteredSegment{{
+			ID:         segA.Segment.ID(),
+			Type:       seg.TypeDown,
+			Registered: now,
+			Expiry:     segA.Segment.MaxExpiry(),
+		}}, states[0].Segments)
+		assert.Equal(t, core130, states[1].Remote)
+		assert.Equal(t, uint64(2), states[1].StateID)
+	})
+	t.Run("expired segments are removed", func(t *testing.T) {
+		tracker := &RegistrationTracker{}
+		tracker.Success(context.Background(), core120, segA, 1, now)
+		assert.Empty(t, tracker.States(segA.Segment.MaxExpiry().Add(time.Second)))
+	})
+	t.Run("lost state triggers registration", func(t *testing.T) {
+		tracker := &RegistrationTracker{}
+		tracker.Success(context.Background(), core120, segA, 1, now)
+		tracker.Success(context.Background(), core120, segB, 1, now)
+		tracker.Success(context.Background(), core120, segA, 2, now.Add(time.Second))
+		states := tracker.States(now.Add(time.Second))
+		require.Len(t, states, 1)
+		assert.Equal(t, uint64(2), states[0].StateID)
+		require.Len(t, states[0].Segments, 1)
+		assert.Equal(t, segA.Segment.ID(), states[0].Segments[0].ID)
+
+		assert.Equal(t, remotes(core120), tracker.DueRemotes(now.Add(time.Second)))
+		// The request is only reported once.
+		assert.Empty(t, tracker.DueRemotes(now.Add(time.Second)))
+	})
+	t.Run("alternating instances do not trigger registration", func(t *testing.T) {
+		tracker := &RegistrationTracker{}
+		// The remote has two instances that respond alternately. The second
+		// instance is seen for the first time and could have lost its state.
+		tracker.Success(context.Background(), core120, segA, 1, now)
+		tracker.Success(context.Background(), core120, segA, 2, now)
+		assert.Equal(t, remotes(core120), tracker.DueRemotes(now))
+		tracker.Success(context.Background(), core120, segB, 2, now)
+
+		for i := 0; i < 4; i++ {
+			tracker.Success(context.Background(), core120, segA, uint64(1+i%2), now)
+			assert.Empty(t, tracker.DueRemotes(now), i)
+		}
+		states := tracker.States(now)
+		require.Len(t, states, 1)
+		assert.Equal(t, uint64(2), states[0].StateID)
+		assert.Len(t, states[0].Segments, 2)
+
+		// A restarted instance reports a new state ID.
+		tracker.Success(context.Background(), core120, segA, 3, now)
+		assert.Equal(t, remotes(core120), tracker.DueRemotes(now))
+	})
+	t.Run("registration is only due at the affected remote", func(t *testing.T) {
+		tracker := &RegistrationTracker{}
+		tracker.Success(context.Background(), core120, segA, 1, now)
+		tracker.Success(context.Background(), core130, segB, 1, now)
+		tracker.Success(context.Background(), core130, segB, 2, now)
+		assert.Equal(t, remotes(core130), tracker.DueRemotes(now))
+	})
+	t.Run("unknown state does not trigger registration", func(t *testing.T) {
+		tracker := &RegistrationTracker{}
+		tracker.Success(context.Background(), core120, segA, 0, now)
+		tracker.Success(context.Background(), core120, segA, 0, now.Add(time.Second))
+		assert.Empty(t, tracker.DueRemotes(now.Add(time.Second)))
+	})
+	t.Run("failures are retried with backoff", func(t *testing.T) {
+		failures := metrics.NewTestGauge()
+		tracker := &RegistrationTracker{
+			RetryInterval: time.Second,
+			Failures:      failures,
+		}
+		tracker.Failure(context.Background(), core120, serrors.New("test"), now)
+		assert.Empty(t, tracker.DueRemotes(now.Add(500*time.Millisecond)))
+		assert.Equal(t, remotes(core120), tracker.DueRemotes(now.Add(time.Second)))
+
+		tracker.Failure(context.Background(), core120, serrors.New("test"),
+			now.Add(time.Second))
+		assert.Empty(t, tracker.DueRemotes(now.Add(2*time.Second)))
+		assert.Equal(t, remotes(core120), tracker.DueRemotes(now.Add(3*time.Second)))
+		assert.Equal(t, 2.0, metrics.GaugeValue(failures.With("remote_isd_as", core120.String())))
+
+		states := tracker.States(now.Add(3 * time.Second))
+		require.Len(t, states, 1)
+		assert.Equal(t, 2, states[0].ConsecutiveFailures)
+		assert.Equal(t, "test", states[0].LastError)
+		assert.Equal(t, now.Add(time.Second), states[0].LastFailure)
+
+		tracker.Success(context.Background(), core120, segA, 1, now.Add(3*time.Second))
+		assert.Empty(t, tracker.DueRemotes(now.Add(time.Hour)))
+		assert.Equal(t, 0.0, metrics.GaugeValue(failures.With("remote_isd_as", core120.String())))
+	})
+}
+
+func remotes(ias ...addr.IA) map[addr.IA]struct{} {
+	m := make(map[addr.IA]struct{}, len(ias))
+	for _, ia := range ias {
+		m[ia] = struct{}{}
+	}
+	return m
+}
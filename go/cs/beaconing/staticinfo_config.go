@@ -77,6 +77,14 @@ func (l *LinkType) UnmarshalText(text []byte) error {
 	return nil
 }
 
+// LinkLatencies provides the measured latencies of the inter-AS links.
+type LinkLatencies interface {
+	// LinkLatency returns the measured one-way latency of the inter-AS link
+	// attached to the interface. The second return value indicates whether a
+	// recent measurement is available.
+	LinkLatency(ifid common.IFIDType) (time.Duration, bool)
+}
+
 // StaticInfoCfg is used to parse data from config.json.
 type StaticInfoCfg struct {
 	Latency   map[common.IFIDType]InterfaceLatencies  `json:"Latency"`
@@ -219,6 +227,34 @@ func (cfg StaticInfoCfg) generate(ifType map[common.IFIDType]topology.LinkType,
 	}
 }
 
+// addMeasuredLatencies sets the inter-AS latencies in the StaticInfo extension
+// to the measured latencies. Only the links that the extension describes are
+// considered, i.e., the link of the egress interface and the peering links.
+// Links without a recent measurement keep the configured latency. If ext is
+// nil, an extension is created as soon as a measurement is available.
+func addMeasuredLatencies(ext *staticinfo.Extension, latencies LinkLatencies,
+	ifType map[common.IFIDType]topology.LinkType,
+	egress common.IFIDType) *staticinfo.Extension {
+
+	for ifid, t := range ifType {
+		if ifid != egress && t != topology.Peer {
+			continue
+		}
+		latency, ok := latencies.LinkLatency(ifid)
+		if !ok {
+			continue
+		}
+		if ext == nil {
+			ext = &staticinfo.Extension{}
+		}
+		if ext.Latency.Inter == nil {
+			ext.Latency.Inter = make(map[common.IFIDType]time.Duration)
+		}
+		ext.Latency.Inter[ifid] = latency
+	}
+	return ext
+}
+
 // generateLatency creates the LatencyInfo by extracting the relevant values from
 // the config.
 func (cfg StaticInfoCfg) generateLatency(ifType map[common.IFIDType]topology.LinkType,
@@ -569,3 +569,57 @@ func TestGenerateStaticInfo(t *testing.T) {
 		})
 	}
 }
+
+type linkLatencies map[common.IFIDType]time.Duration
+
+func (l linkLatencies) LinkLatency(ifid common.IFIDType) (time.Duration, bool) {
+	latency, ok := l[ifid]
+	return latency, ok
+}
+
+func TestAddMeasuredLatencies(t *testing.T) {
+	ifType := map[common.IFIDType]topology.LinkType{
+		1: topology.Child,
+		2: topology.Child,
+		3: topology.Parent,
+		5: topology.Peer,
+	}
+	measured := linkLatencies{
+		1: 5 * time.Millisecond,
+		2: 6 * time.Millisecond,
+		5: 7 * time.Millisecond,
+	}
+
+	t.Run("override configured", func(t *testing.T) {
+		ext := getTestConfigData().generate(ifType, 3, 1)
+		ext = addMeasuredLatencies(ext, measured, ifType, 1)
+		assert.Equal(t, map[common.IFIDType]time.Duration{
+			1: 5 * time.Millisecond,
+			5: 7 * time.Millisecond,
+		}, ext.Latency.Inter)
+		// Intra-AS latencies are not measured.
+		assert.Equal(t, latency_intra_1_2, ext.Latency.Intra[2])
+	})
+	t.Run("fall back to configured", func(t *testing.T) {
+		ext := getTestConfigData().generate(ifType, 3, 1)
+		ext = addMeasuredLatencies(ext, linkLatencies{5: 7 * time.Millisecond}, ifType, 1)
+		assert.Equal(t, map[common.IFIDType]time.Duration{
+			1: latency_inter_1,
+			5: 7 * time.Millisecond,
+		}, ext.Latency.Inter)
+	})
+	t.Run("no configuration", func(t *testing.T) {
+		ext := addMeasuredLatencies(nil, measured, ifType, 2)
+		assert.Equal(t, &staticinfo.Extension{
+			Latency: staticinfo.LatencyInfo{
+				Inter: map[common.IFIDType]time.Duration{
+					2: 6 * time.Millisecond,
+					5: 7 * time.Millisecond,
+				},
+			},
+		}, ext)
+	})
+	t.Run("no measurements", func(t *testing.T) {
+		assert.Nil(t, addMeasuredLatencies(nil, linkLatencies{}, ifType, 2))
+	})
+}
@@ -45,6 +45,18 @@ func (t *Tick) Overdue(timestamp time.Time) bool {
 	return t.now.Sub(timestamp) > t.period
 }
 
+// Due returns true if beaconing on an interface that was last beaconed on at
+// the given timestamp is due. If the interval is zero, beaconing is due if the
+// Tick's period has passed or if the timestamp is overdue. Otherwise, beaconing
+// is due if the interval has elapsed since the timestamp, independent of the
+// Tick's period.
+func (t *Tick) Due(timestamp time.Time, interval time.Duration) bool {
+	if interval == 0 {
+		return t.Passed() || t.Overdue(timestamp)
+	}
+	return t.now.Sub(timestamp) >= interval
+}
+
 func (t *Tick) Period() time.Duration {
 	return t.period
 }
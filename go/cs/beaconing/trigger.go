@@ -0,0 +1,74 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package beaconing
+
+import (
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/cs/beacon"
+	"github.com/scionproto/scion/go/lib/addr"
+)
+
+// Trigger requests a triggered propagation when a significantly better beacon
+// is received. In a triggered propagation, the propagator sends beacons on all
+// target interfaces in its next run, regardless of the propagation intervals.
+// A beacon is significantly better if it is the first beacon received from its
+// originating AS, or if it has fewer AS hops than all beacons received from
+// that AS before. A nil trigger never requests a triggered propagation.
+type Trigger struct {
+	// MinInterval is the minimum interval between two triggered propagations.
+	MinInterval time.Duration
+
+	mu       sync.Mutex
+	shortest map[addr.IA]int
+	pending  bool
+	last     time.Time
+}
+
+// BeaconReceived records the received beacon and requests a triggered
+// propagation if it is significantly better than the beacons received before.
+func (t *Trigger) BeaconReceived(b beacon.Beacon) {
+	if t == nil {
+		return
+	}
+	src, hops := b.Segment.FirstIA(), len(b.Segment.ASEntries)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.shortest == nil {
+		t.shortest = make(map[addr.IA]int)
+	}
+	if prev, ok := t.shortest[src]; ok && prev <= hops {
+		return
+	}
+	t.shortest[src] = hops
+	t.pending = true
+}
+
+// consume indicates whether a triggered propagation is due at the given time.
+// If it returns true, the pending request is cleared.
+func (t *Trigger) consume(now time.Time) bool {
+	if t == nil {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.pending || now.Sub(t.last) < t.MinInterval {
+		return false
+	}
+	t.pending = false
+	t.last = now
+	return true
+}
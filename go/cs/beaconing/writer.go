@@ -57,7 +57,11 @@ type SegmentStore interface {
 
 // RPC registers the path segment with the remote.
 type RPC interface {
-	RegisterSegment(ctx context.Context, meta seg.Meta, remote net.Addr) error
+	// RegisterSegment registers the segment with the remote and returns the
+	// ID of the registration state of the remote. The ID changes when the
+	// remote lost the registered segments. Zero means that the remote does
+	// not report its state.
+	RegisterSegment(ctx context.Context, meta seg.Meta, remote net.Addr) (uint64, error)
 }
 
 // WriteStats provides statistics about segment writing.
@@ -94,6 +98,11 @@ type WriteScheduler struct {
 	// time to write.
 	Writer Writer
 
+	// Registrations is used to determine whether segments should be written
+	// before the period has passed, because a remote lost its registration
+	// state or failed. If it is nil, segments are only written periodically.
+	Registrations *RegistrationTracker
+
 	// Tick is mutable. It's used to determine when to call write.
 	Tick Tick
 	// lastWrite indicates the time of the last successful write.
@@ -117,26 +126,55 @@ func (r *WriteScheduler) Run(ctx context.Context) {
 }
 
 func (r *WriteScheduler) run(ctx context.Context) error {
-	if r.Tick.Now().Sub(r.lastWrite) < r.Tick.Period() && !r.Tick.Passed() {
+	// The due remotes are always fetched, such that requests that are covered
+	// by a regular write are not reported again.
+	due := r.Registrations.DueRemotes(r.Tick.Now())
+	regular := r.Tick.Now().Sub(r.lastWrite) >= r.Tick.Period() || r.Tick.Passed()
+	if !regular && len(due) == 0 {
 		return nil
 	}
 	segments, err := r.Provider.SegmentsToRegister(ctx, r.Type)
 	if err != nil {
 		return err
 	}
+	if !regular {
+		// Only the segments of the remotes that require an early
+		// registration are written.
+		segments = filterRemotes(segments, due)
+	}
 	peers := sortedIntfs(r.Intfs, topology.Peer)
 	stats, err := r.Writer.Write(ctx, segments, peers)
 	if err != nil {
 		return err
 	}
-	r.lastWrite = r.Tick.Now()
 	r.logSummary(log.FromCtx(ctx), &summary{count: stats.Count, srcs: stats.StartIAs})
-	if stats.Count > 0 {
+	if regular {
 		r.lastWrite = r.Tick.Now()
 	}
 	return err
 }
 
+// filterRemotes forwards the segments that start at one of the remotes, and
+// all errors. The input channel is drained.
+func filterRemotes(segments <-chan beacon.BeaconOrErr,
+	remotes map[addr.IA]struct{}) <-chan beacon.BeaconOrErr {
+
+	filtered := make(chan beacon.BeaconOrErr)
+	go func() {
+		defer log.HandlePanic()
+		defer close(filtered)
+		for bOrErr := range segments {
+			if bOrErr.Err == nil {
+				if _, ok := remotes[bOrErr.Beacon.Segment.FirstIA()]; !ok {
+					continue
+				}
+			}
+			filtered <- bOrErr
+		}
+	}()
+	return filtered
+}
+
 // RemoteWriter writes segments via an RPC to the source AS of a segment.
 type RemoteWriter struct {
 	// InternalErrors counts errors that happened before being able to send a
@@ -156,6 +194,9 @@ type RemoteWriter struct {
 	RPC RPC
 	// Pather is used to find paths to a remote.
 	Pather Pather
+	// Registrations keeps track of the segments registered at each remote.
+	// If it is nil, nothing is tracked.
+	Registrations *RegistrationTracker
 }
 
 // Write writes the segment at the source AS of the segment.
@@ -328,13 +369,16 @@ func (r *remoteWriter) startSendSegReg(ctx context.Context, bseg beacon.Beacon,
 		}
 
 		logger := log.FromCtx(ctx)
-		if err := r.rpc.RegisterSegment(ctx, reg, addr); err != nil {
+		stateID, err := r.rpc.RegisterSegment(ctx, reg, addr)
+		if err != nil {
 			logger.Error("Unable to register segment",
 				"seg_type", r.writer.Type, "addr", addr, "err", err)
 			metrics.CounterInc(metrics.CounterWith(r.writer.Registered,
 				labels.WithResult(prom.ErrNetwork).Expand()...))
+			r.writer.Registrations.Failure(ctx, bseg.Segment.FirstIA(), err, time.Now())
 			return
 		}
+		r.writer.Registrations.Success(ctx, bseg.Segment.FirstIA(), reg, stateID, time.Now())
 		r.summary.AddSrc(bseg.Segment.FirstIA())
 		r.summary.Inc()
 
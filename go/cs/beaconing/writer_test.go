@@ -169,6 +169,7 @@ func TestRegistrarRun(t *testing.T) {
 			intfs := ifstate.NewInterfaces(topoProvider.Get().IFInfoMap(), ifstate.Config{})
 			segProvider := mock_beaconing.NewMockSegmentProvider(mctrl)
 			rpc := mock_beaconing.NewMockRPC(mctrl)
+			registrations := &RegistrationTracker{}
 
 			r := WriteScheduler{
 				Writer: &RemoteWriter{
@@ -186,14 +187,16 @@ func TestRegistrarRun(t *testing.T) {
 							return topoProvider.Get().UnderlayNextHop2(common.IFIDType(ifID))
 						},
 					},
-					RPC:   rpc,
-					Type:  test.segType,
-					Intfs: intfs,
+					RPC:           rpc,
+					Type:          test.segType,
+					Intfs:         intfs,
+					Registrations: registrations,
 				},
-				Intfs:    intfs,
-				Tick:     NewTick(time.Hour),
-				Provider: segProvider,
-				Type:     test.segType,
+				Intfs:         intfs,
+				Tick:          NewTick(time.Hour),
+				Provider:      segProvider,
+				Type:          test.segType,
+				Registrations: registrations,
 			}
 			g := graph.NewDefaultGraph(mctrl)
 			segProvider.EXPECT().SegmentsToRegister(gomock.Any(), test.segType).DoAndReturn(
@@ -215,14 +218,14 @@ func TestRegistrarRun(t *testing.T) {
 
 			rpc.EXPECT().RegisterSegment(gomock.Any(), gomock.Any(),
 				gomock.Any()).Times(len(test.beacons)).DoAndReturn(
-				func(_ context.Context, meta seg.Meta, remote net.Addr) error {
+				func(_ context.Context, meta seg.Meta, remote net.Addr) (uint64, error) {
 					segMu.Lock()
 					defer segMu.Unlock()
 					sent = append(sent, regMsg{
 						Meta: meta,
 						Addr: remote.(*snet.SVCAddr),
 					})
-					return nil
+					return 0, nil
 				},
 			)
 			r.Run(context.Background())
@@ -253,10 +256,62 @@ func TestRegistrarRun(t *testing.T) {
 					}
 				})
 			}
+			states := registrations.States(time.Now())
+			require.Len(t, states, len(test.beacons))
+			for _, state := range states {
+				assert.Len(t, state.Segments, 1)
+				assert.Zero(t, state.ConsecutiveFailures)
+			}
 			// The second run should not do anything, since the period has not passed.
 			r.Run(context.Background())
 		})
 	}
+	t.Run("Lost registration state triggers early write", func(t *testing.T) {
+		mctrl := gomock.NewController(t)
+		defer mctrl.Finish()
+		topoProvider := itopotest.TopoProviderFromFile(t, topoNonCore)
+		intfs := ifstate.NewInterfaces(topoProvider.Get().IFInfoMap(), ifstate.Config{})
+		segProvider := mock_beaconing.NewMockSegmentProvider(mctrl)
+		g := graph.NewDefaultGraph(mctrl)
+		registrations := &RegistrationTracker{}
+		writer := &countingWriter{}
+
+		r := WriteScheduler{
+			Writer:        writer,
+			Intfs:         intfs,
+			Tick:          NewTick(time.Hour),
+			Provider:      segProvider,
+			Type:          seg.TypeDown,
+			Registrations: registrations,
+		}
+		lost := testBeaconOrErr(g, []common.IFIDType{graph.If_120_X_111_B})
+		other := testBeaconOrErr(g, []common.IFIDType{graph.If_130_B_120_A,
+			graph.If_120_X_111_B})
+		segProvider.EXPECT().SegmentsToRegister(gomock.Any(), seg.TypeDown).DoAndReturn(
+			func(_, _ interface{}) (<-chan beacon.BeaconOrErr, error) {
+				res := make(chan beacon.BeaconOrErr, 2)
+				res <- lost
+				res <- other
+				close(res)
+				return res, nil
+			},
+		).Times(2)
+		r.Run(context.Background())
+		r.Run(context.Background())
+		assert.Equal(t, 1, writer.count)
+		assert.Equal(t, []addr.IA{lost.Beacon.Segment.FirstIA(),
+			other.Beacon.Segment.FirstIA()}, writer.remotes)
+
+		meta := seg.Meta{Type: seg.TypeDown, Segment: lost.Beacon.Segment}
+		remote := meta.Segment.FirstIA()
+		registrations.Success(context.Background(), remote, meta, 1, time.Now())
+		registrations.Success(context.Background(), remote, meta, 2, time.Now())
+		writer.remotes = nil
+		r.Run(context.Background())
+		assert.Equal(t, 2, writer.count)
+		// Only the segments of the remote that lost its state are written.
+		assert.Equal(t, []addr.IA{remote}, writer.remotes)
+	})
 	t.Run("Run drains the channel", func(t *testing.T) {
 		mctrl := gomock.NewController(t)
 		defer mctrl.Finish()
@@ -349,6 +404,23 @@ func TestRegistrarRun(t *testing.T) {
 	})
 }
 
+// countingWriter drains the segments and counts the calls to Write. The first
+// IAs of the written segments are recorded.
+type countingWriter struct {
+	count   int
+	remotes []addr.IA
+}
+
+func (w *countingWriter) Write(_ context.Context, segments <-chan beacon.BeaconOrErr,
+	_ []common.IFIDType) (WriteStats, error) {
+
+	for bOrErr := range segments {
+		w.remotes = append(w.remotes, bOrErr.Beacon.Segment.FirstIA())
+	}
+	w.count++
+	return WriteStats{}, nil
+}
+
 func testBeaconOrErr(g *graph.Graph, desc []common.IFIDType) beacon.BeaconOrErr {
 	bseg := g.Beacon(desc)
 	asEntry := bseg.ASEntries[bseg.MaxIdx()]
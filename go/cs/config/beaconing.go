@@ -0,0 +1,143 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/config"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/topology"
+	"github.com/scionproto/scion/go/lib/util"
+)
+
+const (
+	// DefaultTriggeredPropagationMinInterval is the default minimum interval
+	// between two triggered propagations.
+	DefaultTriggeredPropagationMinInterval = time.Second
+)
+
+// BeaconingIntf overrides the beaconing configuration of an interface, or of
+// all interfaces of a link type. Zero values indicate that the global value is
+// used.
+type BeaconingIntf struct {
+	// OriginationInterval is the interval between originating beacons on the
+	// interface.
+	OriginationInterval util.DurWrap `toml:"origination_interval,omitempty"`
+	// PropagationInterval is the interval between propagating beacons on the
+	// interface.
+	PropagationInterval util.DurWrap `toml:"propagation_interval,omitempty"`
+	// BestSetSize is the maximum number of beacons per originating AS that are
+	// propagated on the interface in a single run.
+	BestSetSize int `toml:"best_set_size,omitempty"`
+	// MaxBeaconsPerSecond is the maximum number of beacons per second that are
+	// sent on the interface.
+	MaxBeaconsPerSecond int `toml:"max_beacons_per_second,omitempty"`
+}
+
+func (cfg BeaconingIntf) validate() error {
+	if cfg.OriginationInterval.Duration < 0 {
+		return serrors.New("origination_interval must not be negative",
+			"origination_interval", cfg.OriginationInterval)
+	}
+	if cfg.PropagationInterval.Duration < 0 {
+		return serrors.New("propagation_interval must not be negative",
+			"propagation_interval", cfg.PropagationInterval)
+	}
+	if cfg.BestSetSize < 0 {
+		return serrors.New("best_set_size must not be negative",
+			"best_set_size", cfg.BestSetSize)
+	}
+	if cfg.MaxBeaconsPerSecond < 0 {
+		return serrors.New("max_beacons_per_second must not be negative",
+			"max_beacons_per_second", cfg.MaxBeaconsPerSecond)
+	}
+	return nil
+}
+
+// ParseLinkTypes parses the keys of the per link type overrides. Only core and
+// child links are sent beacons on.
+func ParseLinkTypes(
+	raw map[string]BeaconingIntf) (map[topology.LinkType]BeaconingIntf, error) {
+
+	parsed := make(map[topology.LinkType]BeaconingIntf, len(raw))
+	for k, v := range raw {
+		linkType := topology.LinkTypeFromString(k)
+		if linkType != topology.Core && linkType != topology.Child {
+			return nil, serrors.New("link type must be core or child", "link_type", k)
+		}
+		if err := v.validate(); err != nil {
+			return nil, serrors.WrapStr("validating link type", err, "link_type", k)
+		}
+		parsed[linkType] = v
+	}
+	return parsed, nil
+}
+
+// ParseInterfaces parses the keys of the per interface overrides.
+func ParseInterfaces(
+	raw map[string]BeaconingIntf) (map[common.IFIDType]BeaconingIntf, error) {
+
+	parsed := make(map[common.IFIDType]BeaconingIntf, len(raw))
+	for k, v := range raw {
+		ifid, err := strconv.ParseUint(k, 10, 64)
+		if err != nil || ifid == 0 {
+			return nil, serrors.New("invalid interface ID", "interface", k)
+		}
+		if err := v.validate(); err != nil {
+			return nil, serrors.WrapStr("validating interface", err, "interface", k)
+		}
+		parsed[common.IFIDType(ifid)] = v
+	}
+	return parsed, nil
+}
+
+var _ config.Config = (*TriggeredPropagation)(nil)
+
+// TriggeredPropagation is the configuration of the triggered propagation. If
+// it is enabled, beacons are propagated on all interfaces as soon as a
+// significantly better beacon is received.
+type TriggeredPropagation struct {
+	// Enabled enables the triggered propagation.
+	Enabled bool `toml:"enabled,omitempty"`
+	// MinInterval is the minimum interval between two triggered propagations.
+	MinInterval util.DurWrap `toml:"min_interval,omitempty"`
+}
+
+// InitDefaults initializes the default values.
+func (cfg *TriggeredPropagation) InitDefaults() {
+	initDurWrap(&cfg.MinInterval, DefaultTriggeredPropagationMinInterval)
+}
+
+// Validate validates that the minimum interval is positive.
+func (cfg *TriggeredPropagation) Validate() error {
+	if cfg.MinInterval.Duration <= 0 {
+		return serrors.New("min_interval must be positive", "min_interval", cfg.MinInterval)
+	}
+	return nil
+}
+
+// Sample writes a config sample to the writer.
+func (cfg *TriggeredPropagation) Sample(dst io.Writer, _ config.Path, _ config.CtxMap) {
+	config.WriteString(dst, triggeredPropagationSample)
+}
+
+// ConfigName is the key in the toml file.
+func (cfg *TriggeredPropagation) ConfigName() string {
+	return "triggered_propagation"
+}
@@ -0,0 +1,99 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pelletier/go-toml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/topology"
+)
+
+func TestBSConfigIntfOverrides(t *testing.T) {
+	raw := `
+[link_types.core]
+propagation_interval = "1s"
+best_set_size = 5
+
+[interfaces.42]
+origination_interval = "10s"
+max_beacons_per_second = 100
+`
+	var cfg BSConfig
+	cfg.InitDefaults()
+	require.NoError(t, toml.NewDecoder(strings.NewReader(raw)).Strict(true).Decode(&cfg))
+	require.NoError(t, cfg.Validate())
+
+	linkTypes, err := ParseLinkTypes(cfg.LinkTypes)
+	require.NoError(t, err)
+	assert.Len(t, linkTypes, 1)
+	assert.Equal(t, time.Second, linkTypes[topology.Core].PropagationInterval.Duration)
+	assert.Equal(t, 5, linkTypes[topology.Core].BestSetSize)
+
+	interfaces, err := ParseInterfaces(cfg.Interfaces)
+	require.NoError(t, err)
+	assert.Len(t, interfaces, 1)
+	assert.Equal(t, 10*time.Second, interfaces[common.IFIDType(42)].OriginationInterval.Duration)
+	assert.Equal(t, 100, interfaces[common.IFIDType(42)].MaxBeaconsPerSecond)
+}
+
+func TestBSConfigValidateIntfOverrides(t *testing.T) {
+	testCases := map[string]struct {
+		Config       BSConfig
+		ErrAssertion assert.ErrorAssertionFunc
+	}{
+		"default": {
+			ErrAssertion: assert.NoError,
+		},
+		"parent link type": {
+			Config: BSConfig{
+				LinkTypes: map[string]BeaconingIntf{"parent": {}},
+			},
+			ErrAssertion: assert.Error,
+		},
+		"invalid interface ID": {
+			Config: BSConfig{
+				Interfaces: map[string]BeaconingIntf{"core": {}},
+			},
+			ErrAssertion: assert.Error,
+		},
+		"negative best set size": {
+			Config: BSConfig{
+				Interfaces: map[string]BeaconingIntf{"1": {BestSetSize: -1}},
+			},
+			ErrAssertion: assert.Error,
+		},
+		"negative rate": {
+			Config: BSConfig{
+				LinkTypes: map[string]BeaconingIntf{"child": {MaxBeaconsPerSecond: -1}},
+			},
+			ErrAssertion: assert.Error,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			tc.Config.InitDefaults()
+			tc.ErrAssertion(t, tc.Config.Validate())
+		})
+	}
+}
@@ -23,6 +23,25 @@ propagation_interval = "5s"
 
 # The interval between registering beacons. (default 5s)
 registration_interval = "5s"
+
+# The beaconing configuration can be overridden per link type of the egress
+# interface ("core" or "child"), and per egress interface ID. The
+# configuration of an interface takes precedence over the configuration of its
+# link type. Values that are not set fall back to the global configuration.
+#
+# [beaconing.link_types.core]
+# origination_interval = "5s"
+# propagation_interval = "5s"
+# # The maximum number of beacons per originating AS that are propagated on
+# # the interface in a single run. Only has an effect if it is smaller than the
+# # best set size of the propagation policy. (default 0, i.e., no limit)
+# best_set_size = 5
+# # The maximum number of beacons per second that are sent on the interface.
+# # Beacons exceeding the rate are dropped. (default 0, i.e., no limit)
+# max_beacons_per_second = 100
+#
+# [beaconing.interfaces.1]
+# propagation_interval = "1s"
 `
 
 const policiesSample = `
@@ -47,3 +66,31 @@ up_registration = ""
 # (default "")
 down_registration = ""
 `
+
+const linkLatencySample = `
+# The URLs of the link latency status pages of the border routers in the AS,
+# e.g., "http://127.0.0.1:30442/link_latency". Recent link latencies measured
+# by the border routers take precedence over the inter-AS latencies in the
+# static info configuration. If empty, only the static info configuration is
+# used. (default [])
+routers = []
+
+# The interval between fetching the measured link latencies from the border
+# routers. (default 10s)
+fetch_interval = "10s"
+
+# The maximum age of a measured link latency. Older measurements are ignored.
+# (default 1m)
+max_age = "1m"
+`
+
+const triggeredPropagationSample = `
+# Enable the triggered propagation. If enabled, beacons are propagated on all
+# interfaces as soon as a significantly better beacon is received, i.e., the
+# first beacon from an originating AS, or a beacon with fewer AS hops than all
+# beacons received from that AS before. (default false)
+enabled = false
+
+# The minimum interval between two triggered propagations. (default 1s)
+min_interval = "1s"
+`
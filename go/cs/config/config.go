@@ -22,6 +22,7 @@ import (
 
 	"github.com/scionproto/scion/go/lib/config"
 	"github.com/scionproto/scion/go/lib/env"
+	"github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/util"
@@ -64,6 +65,8 @@ type Config struct {
 	BS          BSConfig           `toml:"beaconing,omitempty"`
 	PS          PSConfig           `toml:"path,omitempty"`
 	CA          CA                 `toml:"ca,omitempty"`
+	Crypto      Crypto             `toml:"crypto,omitempty"`
+	ASRenewal   ASRenewal          `toml:"as_renewal,omitempty"`
 	TrustEngine trustengine.Config `toml:"trustengine,omitempty"`
 	DRKey       DRKeyConfig        `toml:"drkey,omitempty"`
 }
@@ -84,6 +87,8 @@ func (cfg *Config) InitDefaults() {
 		&cfg.BS,
 		&cfg.PS,
 		&cfg.CA,
+		&cfg.Crypto,
+		&cfg.ASRenewal,
 		&cfg.TrustEngine,
 		&cfg.DRKey,
 	)
@@ -91,7 +96,7 @@ func (cfg *Config) InitDefaults() {
 
 // Validate validates all parts of the config.
 func (cfg *Config) Validate() error {
-	return config.ValidateAll(
+	err := config.ValidateAll(
 		&cfg.General,
 		&cfg.Features,
 		&cfg.Logging,
@@ -104,9 +109,19 @@ func (cfg *Config) Validate() error {
 		&cfg.BS,
 		&cfg.PS,
 		&cfg.CA,
+		&cfg.Crypto,
+		&cfg.ASRenewal,
 		&cfg.TrustEngine,
 		&cfg.DRKey,
 	)
+	if err != nil {
+		return err
+	}
+	// Private keys cannot be written to a PKCS#11 token.
+	if cfg.ASRenewal.Enabled && !cfg.ASRenewal.ReuseKey && cfg.Crypto.Backend == PKCS11Backend {
+		return serrors.New("as_renewal.reuse_key must be set for the pkcs11 key backend")
+	}
+	return nil
 }
 
 // Sample generates a sample config file for the beacon server.
@@ -150,6 +165,8 @@ func (cfg *Config) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
 		&cfg.BS,
 		&cfg.PS,
 		&cfg.CA,
+		&cfg.Crypto,
+		&cfg.ASRenewal,
 		&cfg.TrustEngine,
 		&cfg.DRKey,
 	)
@@ -167,10 +184,23 @@ type BSConfig struct {
 	RegistrationInterval util.DurWrap `toml:"registration_interval,omitempty"`
 	// Policies contains the policy files.
 	Policies Policies `toml:"policies,omitempty"`
+	// LinkLatency contains the configuration of the measured link latencies.
+	LinkLatency LinkLatency `toml:"link_latency,omitempty"`
+	// LinkTypes overrides the beaconing configuration per link type of the
+	// egress interface. The keys are "core" and "child".
+	LinkTypes map[string]BeaconingIntf `toml:"link_types,omitempty"`
+	// Interfaces overrides the beaconing configuration per egress interface.
+	// The keys are the interface IDs. The configuration of an interface takes
+	// precedence over the configuration of its link type.
+	Interfaces map[string]BeaconingIntf `toml:"interfaces,omitempty"`
+	// TriggeredPropagation contains the configuration of the triggered
+	// propagation.
+	TriggeredPropagation TriggeredPropagation `toml:"triggered_propagation,omitempty"`
 }
 
 // InitDefaults the default values for the durations that are equal to zero.
 func (cfg *BSConfig) InitDefaults() {
+	config.InitAll(&cfg.LinkLatency, &cfg.TriggeredPropagation)
 }
 
 // Validate validates that all durations are set.
@@ -184,13 +214,20 @@ func (cfg *BSConfig) Validate() error {
 	if cfg.RegistrationInterval.Duration == 0 {
 		initDurWrap(&cfg.RegistrationInterval, DefaultRegistrationInterval)
 	}
-	return nil
+	if _, err := ParseLinkTypes(cfg.LinkTypes); err != nil {
+		return serrors.WrapStr("validating link_types", err)
+	}
+	if _, err := ParseInterfaces(cfg.Interfaces); err != nil {
+		return serrors.WrapStr("validating interfaces", err)
+	}
+	return config.ValidateAll(&cfg.LinkLatency, &cfg.TriggeredPropagation)
 }
 
 // Sample generates a sample for the beacon server specific configuration.
 func (cfg *BSConfig) Sample(dst io.Writer, path config.Path, ctx config.CtxMap) {
 	config.WriteString(dst, bsSample)
-	config.WriteSample(dst, path, ctx, &cfg.Policies)
+	config.WriteSample(dst, path, ctx, &cfg.Policies, &cfg.LinkLatency,
+		&cfg.TriggeredPropagation)
 }
 
 // ConfigName is the toml key for the beacon server specific configuration.
@@ -214,23 +251,27 @@ type PSConfig struct {
 	// If HiddenPathsCfg begins with http:// or https://, it will be fetched
 	// over the network from the specified URL instead.
 	HiddenPathsCfg string `toml:"hidden_paths_cfg,omitempty"`
+	// Prefetch contains the configuration of the segment prefetcher.
+	Prefetch segfetcher.PrefetchConfig `toml:"prefetch,omitempty"`
 }
 
 func (cfg *PSConfig) InitDefaults() {
 	if cfg.QueryInterval.Duration == 0 {
 		cfg.QueryInterval.Duration = DefaultQueryInterval
 	}
+	config.InitAll(&cfg.Prefetch)
 }
 
 func (cfg *PSConfig) Validate() error {
 	if cfg.QueryInterval.Duration == 0 {
 		return serrors.New("query_interval must not be zero")
 	}
-	return nil
+	return config.ValidateAll(&cfg.Prefetch)
 }
 
 func (cfg *PSConfig) Sample(dst io.Writer, path config.Path, ctx config.CtxMap) {
 	config.WriteString(dst, psSample)
+	config.WriteSample(dst, path, ctx, &cfg.Prefetch)
 }
 
 func (cfg *PSConfig) ConfigName() string {
@@ -22,7 +22,9 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/scionproto/scion/go/lib/env/envtest"
+	"github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
 	"github.com/scionproto/scion/go/lib/log/logtest"
+	"github.com/scionproto/scion/go/lib/xtest"
 	"github.com/scionproto/scion/go/pkg/api/apitest"
 	"github.com/scionproto/scion/go/pkg/api/jwtauth"
 	storagetest "github.com/scionproto/scion/go/pkg/storage/test"
@@ -69,6 +71,8 @@ func CheckTestConfig(t *testing.T, cfg *Config, id string) {
 	CheckTestBSConfig(t, &cfg.BS)
 	CheckTestPSConfig(t, &cfg.PS, id)
 	CheckTestCA(t, &cfg.CA)
+	CheckTestCrypto(t, &cfg.Crypto)
+	CheckTestASRenewal(t, &cfg.ASRenewal)
 }
 
 func CheckTestBSConfig(t *testing.T, cfg *BSConfig) {
@@ -76,6 +80,21 @@ func CheckTestBSConfig(t *testing.T, cfg *BSConfig) {
 	assert.Equal(t, DefaultPropagationInterval, cfg.PropagationInterval.Duration)
 	assert.Equal(t, DefaultRegistrationInterval, cfg.RegistrationInterval.Duration)
 	CheckTestPolicies(t, &cfg.Policies)
+	CheckTestLinkLatency(t, &cfg.LinkLatency)
+	assert.Empty(t, cfg.LinkTypes)
+	assert.Empty(t, cfg.Interfaces)
+	CheckTestTriggeredPropagation(t, &cfg.TriggeredPropagation)
+}
+
+func CheckTestTriggeredPropagation(t *testing.T, cfg *TriggeredPropagation) {
+	assert.False(t, cfg.Enabled)
+	assert.Equal(t, DefaultTriggeredPropagationMinInterval, cfg.MinInterval.Duration)
+}
+
+func CheckTestLinkLatency(t *testing.T, cfg *LinkLatency) {
+	assert.Empty(t, cfg.Routers)
+	assert.Equal(t, DefaultLinkLatencyFetchInterval, cfg.FetchInterval.Duration)
+	assert.Equal(t, DefaultLinkLatencyMaxAge, cfg.MaxAge.Duration)
 }
 
 func CheckTestPolicies(t *testing.T, cfg *Policies) {
@@ -92,6 +111,11 @@ func InitTestPSConfig(cfg *PSConfig) {
 func CheckTestPSConfig(t *testing.T, cfg *PSConfig, id string) {
 	assert.Equal(t, DefaultQueryInterval, cfg.QueryInterval.Duration)
 	assert.Empty(t, cfg.HiddenPathsCfg)
+	assert.False(t, cfg.Prefetch.Enabled)
+	assert.Equal(t, segfetcher.DefaultPrefetchInterval, cfg.Prefetch.Interval.Duration)
+	assert.Equal(t, segfetcher.DefaultPrefetchLeadTime, cfg.Prefetch.LeadTime.Duration)
+	assert.Equal(t, segfetcher.DefaultPrefetchTopN, *cfg.Prefetch.TopN)
+	assert.Empty(t, cfg.Prefetch.Destinations)
 }
 
 func InitTestCA(cfg *CA) {
@@ -111,3 +135,20 @@ func CheckTestService(t *testing.T, cfg *CAService) {
 	assert.Equal(t, jwtauth.DefaultTokenLifetime, cfg.Lifetime.Duration)
 	assert.Empty(t, cfg.ClientID)
 }
+
+func CheckTestCrypto(t *testing.T, cfg *Crypto) {
+	assert.Equal(t, FileBackend, cfg.Backend)
+	assert.Equal(t, "/usr/lib/softhsm/libsofthsm2.so", cfg.PKCS11.Module)
+	assert.Equal(t, "scion", cfg.PKCS11.TokenLabel)
+	assert.Empty(t, cfg.PKCS11.PINFile)
+	assert.Empty(t, cfg.PKCS11.ASKeyIDs)
+	assert.Empty(t, cfg.PKCS11.CAKeyIDs)
+}
+
+func CheckTestASRenewal(t *testing.T, cfg *ASRenewal) {
+	assert.False(t, cfg.Enabled)
+	assert.Equal(t, xtest.MustParseIA("1-ff00:0:110"), cfg.CA)
+	assert.Equal(t, DefaultRenewalLifetimeFraction, cfg.LifetimeFraction)
+	assert.False(t, cfg.ReuseKey)
+	assert.Equal(t, DefaultRenewalInterval, cfg.Interval.Duration)
+}
@@ -0,0 +1,165 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/scionproto/scion/go/lib/config"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+// KeyBackend is the backend that holds the private keys of the control
+// service.
+type KeyBackend string
+
+const (
+	// FileBackend loads the private keys from PEM files in the crypto
+	// directory.
+	FileBackend KeyBackend = "file"
+	// PKCS11Backend uses the private keys stored on a PKCS#11 token.
+	PKCS11Backend KeyBackend = "pkcs11"
+)
+
+var _ config.Config = (*Crypto)(nil)
+
+// Crypto is the configuration of the private key storage.
+type Crypto struct {
+	// Backend is the backend that holds the AS signing and CA private keys.
+	// If it is the empty string, the file backend is selected as the default.
+	Backend KeyBackend `toml:"backend,omitempty"`
+	// PKCS11 contains the PKCS#11 configuration. It is only used if the
+	// PKCS#11 backend is selected.
+	PKCS11 PKCS11 `toml:"pkcs11,omitempty"`
+}
+
+// InitDefaults initializes the default backend.
+func (cfg *Crypto) InitDefaults() {
+	if cfg.Backend == "" {
+		cfg.Backend = FileBackend
+	}
+}
+
+// Validate validates the selected backend.
+func (cfg *Crypto) Validate() error {
+	if cfg.Backend == "" {
+		cfg.Backend = FileBackend
+	}
+	switch KeyBackend(strings.ToLower(string(cfg.Backend))) {
+	case FileBackend:
+		cfg.Backend = FileBackend
+		return nil
+	case PKCS11Backend:
+		cfg.Backend = PKCS11Backend
+		return cfg.PKCS11.Validate()
+	default:
+		return serrors.New("unknown key backend", "backend", cfg.Backend)
+	}
+}
+
+// Sample writes a config sample to the writer.
+func (cfg *Crypto) Sample(dst io.Writer, path config.Path, ctx config.CtxMap) {
+	config.WriteString(dst, cryptoSample)
+	config.WriteSample(dst, path, ctx, &cfg.PKCS11)
+}
+
+// ConfigName is the key in the toml file.
+func (cfg *Crypto) ConfigName() string {
+	return "crypto"
+}
+
+// PKCS11 is the configuration of the PKCS#11 key backend.
+type PKCS11 struct {
+	// Module is the path to the PKCS#11 module (shared library).
+	Module string `toml:"module,omitempty"`
+	// TokenLabel is the label of the token that holds the keys.
+	TokenLabel string `toml:"token_label,omitempty"`
+	// PINFile is the path to the file that contains the user PIN.
+	PINFile string `toml:"pin_file,omitempty"`
+	// ASKeyIDs are the hex encoded key IDs (CKA_ID) of the AS signing keys.
+	// If empty, all keys on the token are considered.
+	ASKeyIDs []string `toml:"as_key_ids,omitempty"`
+	// CAKeyIDs are the hex encoded key IDs (CKA_ID) of the CA signing keys.
+	// If empty, all keys on the token are considered.
+	CAKeyIDs []string `toml:"ca_key_ids,omitempty"`
+}
+
+// Validate validates that the module and token are set, and that the key
+// IDs are hex encoded.
+func (cfg *PKCS11) Validate() error {
+	if cfg.Module == "" {
+		return serrors.New("module must be set")
+	}
+	if cfg.TokenLabel == "" {
+		return serrors.New("token_label must be set")
+	}
+	if _, err := decodeKeyIDs(cfg.ASKeyIDs); err != nil {
+		return serrors.WrapStr("parsing as_key_ids", err)
+	}
+	if _, err := decodeKeyIDs(cfg.CAKeyIDs); err != nil {
+		return serrors.WrapStr("parsing ca_key_ids", err)
+	}
+	return nil
+}
+
+// Sample writes a config sample to the writer.
+func (cfg *PKCS11) Sample(dst io.Writer, _ config.Path, _ config.CtxMap) {
+	config.WriteString(dst, pkcs11Sample)
+}
+
+// ConfigName is the key in the toml file.
+func (cfg *PKCS11) ConfigName() string {
+	return "pkcs11"
+}
+
+// PIN reads the user PIN from the configured file. If no file is configured,
+// the empty string is returned.
+func (cfg *PKCS11) PIN() (string, error) {
+	if cfg.PINFile == "" {
+		return "", nil
+	}
+	raw, err := ioutil.ReadFile(cfg.PINFile)
+	if err != nil {
+		return "", serrors.WrapStr("reading PIN file", err)
+	}
+	return strings.TrimSpace(string(raw)), nil
+}
+
+// ASKeys returns the decoded AS signing key IDs.
+func (cfg *PKCS11) ASKeys() [][]byte {
+	ids, _ := decodeKeyIDs(cfg.ASKeyIDs)
+	return ids
+}
+
+// CAKeys returns the decoded CA signing key IDs.
+func (cfg *PKCS11) CAKeys() [][]byte {
+	ids, _ := decodeKeyIDs(cfg.CAKeyIDs)
+	return ids
+}
+
+func decodeKeyIDs(encoded []string) ([][]byte, error) {
+	var ids [][]byte
+	for _, e := range encoded {
+		id, err := hex.DecodeString(e)
+		if err != nil {
+			return nil, serrors.WrapStr("decoding key ID", err, "id", e)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCryptoValidate(t *testing.T) {
+	testCases := map[string]struct {
+		Config       Crypto
+		Backend      KeyBackend
+		ErrAssertion assert.ErrorAssertionFunc
+	}{
+		"default": {
+			Backend:      FileBackend,
+			ErrAssertion: assert.NoError,
+		},
+		"file": {
+			Config:       Crypto{Backend: "FILE"},
+			Backend:      FileBackend,
+			ErrAssertion: assert.NoError,
+		},
+		"pkcs11": {
+			Config: Crypto{
+				Backend: PKCS11Backend,
+				PKCS11: PKCS11{
+					Module:     "/usr/lib/softhsm/libsofthsm2.so",
+					TokenLabel: "scion",
+					ASKeyIDs:   []string{"0102"},
+				},
+			},
+			Backend:      PKCS11Backend,
+			ErrAssertion: assert.NoError,
+		},
+		"pkcs11 without module": {
+			Config: Crypto{
+				Backend: PKCS11Backend,
+				PKCS11:  PKCS11{TokenLabel: "scion"},
+			},
+			Backend:      PKCS11Backend,
+			ErrAssertion: assert.Error,
+		},
+		"pkcs11 invalid key ID": {
+			Config: Crypto{
+				Backend: PKCS11Backend,
+				PKCS11: PKCS11{
+					Module:     "/usr/lib/softhsm/libsofthsm2.so",
+					TokenLabel: "scion",
+					CAKeyIDs:   []string{"xyz"},
+				},
+			},
+			Backend:      PKCS11Backend,
+			ErrAssertion: assert.Error,
+		},
+		"unknown": {
+			Config:       Crypto{Backend: "vault"},
+			Backend:      "vault",
+			ErrAssertion: assert.Error,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.Config.Validate()
+			tc.ErrAssertion(t, err)
+			assert.Equal(t, tc.Backend, tc.Config.Backend)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/config"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/util"
+)
+
+const (
+	// DefaultLinkLatencyFetchInterval is the default interval between fetching
+	// the link latencies measured by the border routers.
+	DefaultLinkLatencyFetchInterval = 10 * time.Second
+	// DefaultLinkLatencyMaxAge is the default maximum age of a measured link
+	// latency that is still used in beacons.
+	DefaultLinkLatencyMaxAge = time.Minute
+)
+
+var _ config.Config = (*LinkLatency)(nil)
+
+// LinkLatency is the configuration of the link latencies that are measured by
+// the border routers and included in the StaticInfo extension of beacons.
+type LinkLatency struct {
+	// Routers contains the URLs of the link latency status pages of the border
+	// routers in the AS. If it is empty, only the latencies in the static info
+	// configuration are used.
+	Routers []string `toml:"routers,omitempty"`
+	// FetchInterval is the interval between fetching the measured link
+	// latencies from the border routers.
+	FetchInterval util.DurWrap `toml:"fetch_interval,omitempty"`
+	// MaxAge is the maximum age of a measured link latency. Older measurements
+	// are ignored, and the latency in the static info configuration is used
+	// instead.
+	MaxAge util.DurWrap `toml:"max_age,omitempty"`
+}
+
+// InitDefaults initializes the default values.
+func (cfg *LinkLatency) InitDefaults() {
+	initDurWrap(&cfg.FetchInterval, DefaultLinkLatencyFetchInterval)
+	initDurWrap(&cfg.MaxAge, DefaultLinkLatencyMaxAge)
+}
+
+// Validate validates that the router URLs are valid and that the durations
+// are positive.
+func (cfg *LinkLatency) Validate() error {
+	for _, r := range cfg.Routers {
+		u, err := url.Parse(r)
+		if err != nil {
+			return serrors.WrapStr("parsing router URL", err, "url", r)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return serrors.New("router URL must use http or https", "url", r)
+		}
+	}
+	if cfg.FetchInterval.Duration <= 0 {
+		return serrors.New("fetch_interval must be positive",
+			"fetch_interval", cfg.FetchInterval)
+	}
+	if cfg.MaxAge.Duration <= 0 {
+		return serrors.New("max_age must be positive", "max_age", cfg.MaxAge)
+	}
+	return nil
+}
+
+// Sample writes a config sample to the writer.
+func (cfg *LinkLatency) Sample(dst io.Writer, _ config.Path, _ config.CtxMap) {
+	config.WriteString(dst, linkLatencySample)
+}
+
+// ConfigName is the key in the toml file.
+func (cfg *LinkLatency) ConfigName() string {
+	return "link_latency"
+}
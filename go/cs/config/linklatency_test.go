@@ -0,0 +1,64 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/util"
+)
+
+func TestLinkLatencyValidate(t *testing.T) {
+	testCases := map[string]struct {
+		Config       LinkLatency
+		ErrAssertion assert.ErrorAssertionFunc
+	}{
+		"default": {
+			ErrAssertion: assert.NoError,
+		},
+		"routers": {
+			Config: LinkLatency{
+				Routers: []string{
+					"http://127.0.0.1:30442/link_latency",
+					"https://[fd00::1]:30442/link_latency",
+				},
+			},
+			ErrAssertion: assert.NoError,
+		},
+		"invalid scheme": {
+			Config: LinkLatency{
+				Routers: []string{"127.0.0.1:30442/link_latency"},
+			},
+			ErrAssertion: assert.Error,
+		},
+		"negative max age": {
+			Config: LinkLatency{
+				MaxAge: util.DurWrap{Duration: -time.Second},
+			},
+			ErrAssertion: assert.Error,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			tc.Config.InitDefaults()
+			tc.ErrAssertion(t, tc.Config.Validate())
+		})
+	}
+}
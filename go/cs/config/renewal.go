@@ -0,0 +1,84 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"io"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/config"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/util"
+)
+
+const (
+	// DefaultRenewalLifetimeFraction is the default fraction of the AS
+	// certificate validity period after which the certificate is renewed.
+	DefaultRenewalLifetimeFraction = 0.75
+	// DefaultRenewalInterval is the default interval between checks whether
+	// the AS certificate needs to be renewed.
+	DefaultRenewalInterval = time.Minute
+)
+
+var _ config.Config = (*ASRenewal)(nil)
+
+// ASRenewal is the configuration of the automatic AS certificate renewal.
+type ASRenewal struct {
+	// Enabled enables the automatic AS certificate renewal.
+	Enabled bool `toml:"enabled,omitempty"`
+	// CA is the ISD-AS of the CA that the renewal requests are sent to. If it
+	// is zero, the issuer of the active AS certificate is used.
+	CA addr.IA `toml:"ca,omitempty"`
+	// LifetimeFraction is the fraction of the AS certificate validity period
+	// after which the certificate is renewed.
+	LifetimeFraction float64 `toml:"lifetime_fraction,omitempty"`
+	// ReuseKey indicates that the private key of the active AS certificate is
+	// used for the renewed certificate instead of a freshly generated key.
+	ReuseKey bool `toml:"reuse_key,omitempty"`
+	// Interval is the interval between checks whether the AS certificate
+	// needs to be renewed.
+	Interval util.DurWrap `toml:"interval,omitempty"`
+}
+
+// InitDefaults initializes the default values.
+func (cfg *ASRenewal) InitDefaults() {
+	if cfg.LifetimeFraction == 0 {
+		cfg.LifetimeFraction = DefaultRenewalLifetimeFraction
+	}
+	initDurWrap(&cfg.Interval, DefaultRenewalInterval)
+}
+
+// Validate validates that the lifetime fraction is in the open interval (0, 1).
+func (cfg *ASRenewal) Validate() error {
+	if cfg.LifetimeFraction <= 0 || cfg.LifetimeFraction >= 1 {
+		return serrors.New("lifetime_fraction must be between 0 and 1",
+			"lifetime_fraction", cfg.LifetimeFraction)
+	}
+	if cfg.Interval.Duration <= 0 {
+		return serrors.New("interval must be positive", "interval", cfg.Interval)
+	}
+	return nil
+}
+
+// Sample writes a config sample to the writer.
+func (cfg *ASRenewal) Sample(dst io.Writer, _ config.Path, _ config.CtxMap) {
+	config.WriteString(dst, asRenewalSample)
+}
+
+// ConfigName is the key in the toml file.
+func (cfg *ASRenewal) ConfigName() string {
+	return "as_renewal"
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/util"
+)
+
+func TestASRenewalValidate(t *testing.T) {
+	testCases := map[string]struct {
+		Config       ASRenewal
+		ErrAssertion assert.ErrorAssertionFunc
+	}{
+		"default": {
+			ErrAssertion: assert.NoError,
+		},
+		"custom": {
+			Config: ASRenewal{
+				LifetimeFraction: 0.5,
+				Interval:         util.DurWrap{Duration: 10 * time.Second},
+			},
+			ErrAssertion: assert.NoError,
+		},
+		"fraction too large": {
+			Config:       ASRenewal{LifetimeFraction: 1},
+			ErrAssertion: assert.Error,
+		},
+		"negative fraction": {
+			Config:       ASRenewal{LifetimeFraction: -0.5},
+			ErrAssertion: assert.Error,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			tc.Config.InitDefaults()
+			tc.ErrAssertion(t, tc.Config.Validate())
+		})
+	}
+}
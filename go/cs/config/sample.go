@@ -76,3 +76,54 @@ lifetime = "10m"
 # authorization tokens. If not set, the SCION ID is used instead.
 client_id = ""
 `
+
+const cryptoSample = `
+# The backend that holds the private keys of the AS signing key and the CA
+# signing key.
+#
+# - file:   The private keys are loaded from the PEM files in the crypto/as and
+#           crypto/ca directories of the configuration directory.
+#
+# - pkcs11: The private keys are stored on a PKCS#11 token, e.g., a hardware
+#           security module. The private keys never leave the token. The
+#           certificates are still loaded from the configuration directory.
+#
+# (default file)
+backend = "file"
+`
+
+const asRenewalSample = `
+# Enable the automatic renewal of the AS certificate. If enabled, the control
+# service periodically checks the validity of the active AS certificate chain
+# and requests a renewed chain from the CA before it expires. The renewed chain
+# and private key are written to the crypto/as directory. (default false)
+enabled = false
+# The ISD-AS of the CA that the renewal requests are sent to. If not set, the
+# issuer of the active AS certificate is used. (default "")
+ca = "1-ff00:0:110"
+# The fraction of the AS certificate validity period after which the
+# certificate is renewed. Must be between 0 and 1. (default 0.75)
+lifetime_fraction = 0.75
+# Reuse the private key of the active AS certificate instead of generating a
+# fresh key for the renewed certificate. The PKCS#11 key backend requires
+# this, since keys cannot be written to the token. (default false)
+reuse_key = false
+# The interval between checks whether the AS certificate needs to be renewed.
+# (default 1m)
+interval = "1m"
+`
+
+const pkcs11Sample = `
+# The path to the PKCS#11 module (shared library). (default "")
+module = "/usr/lib/softhsm/libsofthsm2.so"
+# The label of the token that holds the keys. (default "")
+token_label = "scion"
+# The path to the file containing the user PIN of the token. (default "")
+pin_file = ""
+# The hex encoded key IDs (CKA_ID) of the AS signing keys. If empty, all keys
+# on the token are considered. (default [])
+as_key_ids = []
+# The hex encoded key IDs (CKA_ID) of the CA signing keys. If empty, all keys
+# on the token are considered. (default [])
+ca_key_ids = []
+`
@@ -17,6 +17,7 @@ package main
 import (
 	"context"
 	"crypto/tls"
+	"net"
 	"net/http"
 	_ "net/http/pprof"
 	"path/filepath"
@@ -39,17 +40,23 @@ import (
 	"github.com/scionproto/scion/go/cs/config"
 	"github.com/scionproto/scion/go/cs/ifstate"
 	"github.com/scionproto/scion/go/cs/onehop"
+	"github.com/scionproto/scion/go/cs/revocation"
+	revocationgrpc "github.com/scionproto/scion/go/cs/revocation/grpc"
 	segreggrpc "github.com/scionproto/scion/go/cs/segreg/grpc"
 	"github.com/scionproto/scion/go/cs/segreq"
 	segreqgrpc "github.com/scionproto/scion/go/cs/segreq/grpc"
 	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
 	"github.com/scionproto/scion/go/lib/drkeystorage"
 	"github.com/scionproto/scion/go/lib/fatal"
 	"github.com/scionproto/scion/go/lib/infra/infraenv"
 	"github.com/scionproto/scion/go/lib/infra/messenger"
 	"github.com/scionproto/scion/go/lib/infra/modules/itopo"
+	"github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
 	segfetchergrpc "github.com/scionproto/scion/go/lib/infra/modules/segfetcher/grpc"
 	"github.com/scionproto/scion/go/lib/infra/modules/seghandler"
+	"github.com/scionproto/scion/go/lib/infra/modules/upstream"
 	"github.com/scionproto/scion/go/lib/keyconf"
 	"github.com/scionproto/scion/go/lib/log"
 	libmetrics "github.com/scionproto/scion/go/lib/metrics"
@@ -60,7 +67,9 @@ import (
 	"github.com/scionproto/scion/go/lib/scrypto/cppki"
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/snet/multipath"
 	"github.com/scionproto/scion/go/lib/sock/reliable"
+	"github.com/scionproto/scion/go/lib/svc"
 	"github.com/scionproto/scion/go/lib/topology"
 	"github.com/scionproto/scion/go/pkg/api/jwtauth"
 	"github.com/scionproto/scion/go/pkg/app/launcher"
@@ -77,6 +86,7 @@ import (
 	cstrustmetrics "github.com/scionproto/scion/go/pkg/cs/trust/metrics"
 	"github.com/scionproto/scion/go/pkg/discovery"
 	libgrpc "github.com/scionproto/scion/go/pkg/grpc"
+	"github.com/scionproto/scion/go/pkg/pathprobe"
 	cppb "github.com/scionproto/scion/go/pkg/proto/control_plane"
 	dpb "github.com/scionproto/scion/go/pkg/proto/discovery"
 	"github.com/scionproto/scion/go/pkg/service"
@@ -116,24 +126,43 @@ func realMain() error {
 	}
 	defer closer.Close()
 
-	revCache := storage.NewRevocationStorage()
-	defer revCache.Close()
 	pathDB, err := storage.NewPathStorage(globalCfg.PathDB)
 	if err != nil {
 		return serrors.WrapStr("initializing path storage", err)
 	}
+	revCache := storage.NewRevocationStorage(pathDB)
+	defer revCache.Close()
+	// Revocations learned from SCMP messages are handed to the revocation
+	// gossiper, which is started below.
+	localRevocations := make(chan *path_mgmt.SignedRevInfo, 64)
 	pathDB = pathdb.WithMetrics(string(storage.BackendSqlite), pathDB)
 	defer pathDB.Close()
+	// The paths for multipath QUIC are resolved with the segment router. The
+	// router is set below, once the segment fetcher is initialized.
+	quicPaths := &cs.PathQuerier{}
+	var quicMultipath *multipath.Config
+	if globalCfg.QUIC.Multipath {
+		quicMultipath = &multipath.Config{
+			Querier: quicPaths,
+			Prober:  pathprobe.PathProber{LocalIA: topo.IA()},
+		}
+	}
+	svcInstances := &svc.Instances{}
 	nc := infraenv.NetworkConfig{
 		IA:                    topo.IA(),
 		Public:                topo.PublicAddress(addr.SvcCS, globalCfg.General.ID),
 		ReconnectToDispatcher: globalCfg.General.ReconnectToDispatcher,
 		QUIC: infraenv.QUIC{
-			Address: globalCfg.QUIC.Address,
+			Address:   globalCfg.QUIC.Address,
+			Multipath: quicMultipath,
 		},
-		SVCRouter: messenger.NewSVCRouter(itopo.Provider()),
+		SVCRouter:    messenger.NewSVCRouter(itopo.Provider()),
+		SVCInstances: svcInstances,
 		SCMPHandler: snet.DefaultSCMPHandler{
-			RevocationHandler: cs.RevocationHandler{RevCache: revCache},
+			RevocationHandler: cs.RevocationHandler{
+				RevCache:    revCache,
+				Revocations: localRevocations,
+			},
 		},
 	}
 	quicStack, err := nc.QUICStack()
@@ -149,6 +178,27 @@ func realMain() error {
 		Rewriter: nc.AddressRewriter(nil),
 		Dialer:   quicStack.Dialer,
 	}
+	svcProber := periodic.Start(
+		&svc.InstanceProber{
+			Instances: svcInstances,
+			Lookuper:  nc.AddressRewriter(nil).Resolver,
+			LocalIA:   topo.IA(),
+			SVC:       addr.SvcCS,
+			Peers: func() map[string]*net.UDPAddr {
+				return csPeers(itopo.Provider().Get(), globalCfg.General.ID)
+			},
+			Timeout:  time.Second,
+			TaskName: "control_svc_instance_prober",
+		},
+		5*time.Second,
+		5*time.Second,
+	)
+	defer svcProber.Stop()
+	upstreamBalancer := &upstream.Balancer{
+		Resolver: nc.AddressRewriter(nil),
+		Requests: libmetrics.NewPromCounter(metrics.UpstreamRequestsTotal),
+		Hedges:   libmetrics.NewPromCounter(metrics.UpstreamHedgedRequestsTotal),
+	}
 
 	trustDB, err := storage.NewTrustStorage(globalCfg.TrustDB)
 	if err != nil {
@@ -187,6 +237,7 @@ func realMain() error {
 			Requests: libmetrics.NewPromCounter(trustmetrics.RPC.Fetches),
 		},
 		Recurser: trust.ASLocalRecurser{IA: topo.IA()},
+		Balancer: upstreamBalancer,
 		// XXX(roosd): cyclic dependency on router. It is set below.
 	}
 	verifier := compat.Verifier{
@@ -205,6 +256,7 @@ func realMain() error {
 		RPC: &segfetchergrpc.Requester{
 			Dialer: dialer,
 		},
+		Balancer:     upstreamBalancer,
 		Inspector:    inspector,
 		TopoProvider: itopo.Provider(),
 		Verifier:     verifier,
@@ -214,6 +266,7 @@ func realMain() error {
 		DB:     trustDB,
 		Router: segreq.NewRouter(fetcherCfg),
 	}
+	quicPaths.SetRouter(segreq.NewRouter(fetcherCfg))
 
 	quicServer := grpc.NewServer(libgrpc.UnaryServerInterceptor())
 	tcpServer := grpc.NewServer(libgrpc.UnaryServerInterceptor())
@@ -228,16 +281,40 @@ func realMain() error {
 	cppb.RegisterTrustMaterialServiceServer(tcpServer, trustServer)
 
 	// Handle beaconing.
+	var propagationTrigger *beaconing.Trigger
+	if globalCfg.BS.TriggeredPropagation.Enabled {
+		propagationTrigger = &beaconing.Trigger{
+			MinInterval: globalCfg.BS.TriggeredPropagation.MinInterval.Duration,
+		}
+	}
+	trcAnnouncementHandler := trust.NewAsyncTRCAnnouncementHandler(
+		trust.TRCAnnouncementHandler{
+			ISD:      topo.IA().I,
+			DB:       trustDB,
+			Provider: provider,
+		},
+		trust.DefaultAnnouncementQueueSize,
+		trust.DefaultAnnouncementTimeout,
+	)
+	defer trcAnnouncementHandler.Close()
 	cppb.RegisterSegmentCreationServiceServer(quicServer, &beaconinggrpc.SegmentCreationServer{
 		Handler: &beaconing.Handler{
 			LocalIA:        topo.IA(),
 			Inserter:       beaconStore,
 			Interfaces:     intfs,
 			Verifier:       verifier,
+			Trigger:        propagationTrigger,
 			BeaconsHandled: libmetrics.NewPromCounter(metrics.BeaconingReceivedTotal),
 		},
+		TRCHandler: trcAnnouncementHandler,
 	})
 
+	// Track the remote ASes that fetch and register segments for the
+	// revocation gossip.
+	revTracker := revocation.NewTracker()
+	revTrackerCleaner := periodic.Start(revTracker, time.Minute, time.Minute)
+	defer revTrackerCleaner.Stop()
+
 	// Handle segment lookup
 	authLookupServer := &segreqgrpc.LookupServer{
 		Lookuper: segreq.AuthoritativeLookup{
@@ -246,21 +323,41 @@ func realMain() error {
 			PathDB:      pathDB,
 		},
 		RevCache:     revCache,
+		Tracker:      revTracker,
 		Requests:     libmetrics.NewPromCounter(metrics.SegmentLookupRequestsTotal),
 		SegmentsSent: libmetrics.NewPromCounter(metrics.SegmentLookupSegmentsSentTotal),
 	}
-	forwardingLookupServer := &segreqgrpc.LookupServer{
-		Lookuper: segreq.ForwardingLookup{
-			LocalIA:     topo.IA(),
-			CoreChecker: segreq.CoreChecker{Inspector: inspector},
-			Fetcher:     segreq.NewFetcher(fetcherCfg),
-			Expander: segreq.WildcardExpander{
-				LocalIA:   topo.IA(),
-				Core:      topo.Core(),
-				Inspector: inspector,
-				PathDB:    pathDB,
-			},
+	forwardingLookup := segreq.ForwardingLookup{
+		LocalIA:     topo.IA(),
+		CoreChecker: segreq.CoreChecker{Inspector: inspector},
+		Fetcher:     segreq.NewFetcher(fetcherCfg),
+		Expander: segreq.WildcardExpander{
+			LocalIA:   topo.IA(),
+			Core:      topo.Core(),
+			Inspector: inspector,
+			PathDB:    pathDB,
 		},
+		Splitter: segreq.NewSplitter(topo.IA(), topo.Core(), inspector, pathDB),
+	}
+	if prefetchCfg := globalCfg.PS.Prefetch; prefetchCfg.Enabled {
+		dsts, err := prefetchCfg.DestinationIAs()
+		if err != nil {
+			return serrors.WrapStr("parsing prefetch destinations", err)
+		}
+		forwardingLookup.Prefetcher = &segfetcher.Prefetcher{
+			Target:       forwardingLookup,
+			TopN:         *prefetchCfg.TopN,
+			LeadTime:     prefetchCfg.LeadTime.Duration,
+			Destinations: dsts,
+			TaskName:     "control_segment_prefetcher",
+		}
+		prefetchRunner := periodic.Start(forwardingLookup.Prefetcher,
+			prefetchCfg.Interval.Duration, prefetchCfg.Interval.Duration)
+		defer prefetchRunner.Stop()
+		prefetchRunner.TriggerRun()
+	}
+	forwardingLookupServer := &segreqgrpc.LookupServer{
+		Lookuper:     forwardingLookup,
 		RevCache:     revCache,
 		Requests:     libmetrics.NewPromCounter(metrics.SegmentLookupRequestsTotal),
 		SegmentsSent: libmetrics.NewPromCounter(metrics.SegmentLookupSegmentsSentTotal),
@@ -272,10 +369,12 @@ func realMain() error {
 		cppb.RegisterSegmentLookupServiceServer(quicServer, authLookupServer)
 	}
 
-	// Handle segment registration.
+	// Handle segment registration. The registration state ID changes on every
+	// start, such that registrants register their segments again.
 	if topo.Core() {
 		cppb.RegisterSegmentRegistrationServiceServer(quicServer, &segreggrpc.RegistrationServer{
 			LocalIA: topo.IA(),
+			StateID: scrypto.RandUint64(),
 			SegHandler: seghandler.Handler{
 				Verifier: &seghandler.DefaultVerifier{
 					Verifier: verifier,
@@ -285,16 +384,43 @@ func realMain() error {
 					RevCache: revCache,
 				},
 			},
+			Tracker:       revTracker,
 			Registrations: libmetrics.NewPromCounter(metrics.SegmentRegistrationsTotal),
 		})
 
 	}
 
-	signer, err := cs.NewSigner(topo.IA(), trustDB, globalCfg.General.ConfigDir)
+	keyRings, err := cs.NewKeyRings(globalCfg.Crypto, globalCfg.General.ConfigDir)
+	if err != nil {
+		return serrors.WrapStr("initializing key rings", err)
+	}
+	defer keyRings.Close()
+	signer, err := cs.NewSigner(topo.IA(), trustDB, keyRings.AS, globalCfg.General.ConfigDir)
 	if err != nil {
 		return serrors.WrapStr("initializing AS signer", err)
 	}
 
+	// Propagate verified revocations to the ASes that use the revoked
+	// interfaces.
+	revGossiper := &revocation.Gossiper{
+		LocalIA:  topo.IA(),
+		RevCache: revCache,
+		Tracker:  revTracker,
+		Signer:   signer,
+		Verifier: verifier,
+		RPC:      revocationgrpc.Sender{Dialer: dialer},
+		Resolver: revocation.RouterResolver{Router: segreq.NewRouter(fetcherCfg)},
+	}
+	cppb.RegisterRevocationServiceServer(quicServer, revocationgrpc.Server{
+		Gossiper: revGossiper,
+	})
+	revCtx, revCancel := context.WithCancel(context.Background())
+	defer revCancel()
+	go func() {
+		defer log.HandlePanic()
+		revGossiper.Run(revCtx, localRevocations)
+	}()
+
 	var chainBuilder renewal.ChainBuilder
 	if topo.CA() {
 		renewalGauges := libmetrics.NewPromGauge(metrics.RenewalRegisteredHandlers)
@@ -326,6 +452,7 @@ func realMain() error {
 					DB:                   trustDB,
 					MaxValidity:          globalCfg.CA.MaxASValidity.Duration,
 					ConfigDir:            globalCfg.General.ConfigDir,
+					KeyRing:              keyRings.CA,
 					ForceECDSAWithSHA512: !globalCfg.Features.AppropriateDigest,
 				},
 			)
@@ -553,10 +680,13 @@ func realMain() error {
 		IntraASTCPServer:  tcpServer,
 		InterASQUICServer: quicServer,
 	}
-	hpWriterCfg, err := hpCfg.Setup(globalCfg.PS.HiddenPathsCfg)
+	hpWriterCfg, hpGroupUpdater, err := hpCfg.Setup(globalCfg.PS.HiddenPathsCfg)
 	if err != nil {
 		return err
 	}
+	if hpGroupUpdater != nil {
+		defer hpGroupUpdater.Stop()
+	}
 
 	promgrpc.Register(quicServer)
 	promgrpc.Register(tcpServer)
@@ -582,20 +712,25 @@ func realMain() error {
 		}()
 	}
 
+	registrations := &beaconing.RegistrationTracker{
+		Failures: libmetrics.NewPromGauge(metrics.BeaconingRegistrationFailures),
+	}
+
 	if globalCfg.API.Addr != "" {
 		r := chi.NewRouter()
 		r.Use(cors.Handler(cors.Options{
 			AllowedOrigins: []string{"*"},
 		}))
 		server := api.Server{
-			Segments: pathDB,
-			CA:       chainBuilder,
-			Config:   service.NewConfigHandler(globalCfg),
-			Info:     service.NewInfoHandler(),
-			LogLevel: log.ConsoleLevel.ServeHTTP,
-			Signer:   signer,
-			Topology: itopo.TopologyHandler,
-			TrustDB:  trustDB,
+			Segments:      pathDB,
+			Registrations: registrations,
+			CA:            chainBuilder,
+			Config:        service.NewConfigHandler(globalCfg),
+			Info:          service.NewInfoHandler(),
+			LogLevel:      log.ConsoleLevel.ServeHTTP,
+			Signer:        signer,
+			Topology:      itopo.TopologyHandler,
+			TrustDB:       trustDB,
 		}
 		log.Info("Exposing API", "addr", globalCfg.API.Addr)
 		h := api.HandlerFromMux(&server, r)
@@ -632,6 +767,30 @@ func realMain() error {
 			},
 		},
 	)
+	var asRenewalCfg *cs.ASRenewalCfg
+	if globalCfg.ASRenewal.Enabled {
+		asRenewalCfg = &cs.ASRenewalCfg{
+			SignerGen:        signer.SignerGen,
+			Requester:        renewalgrpc.Requester{Dialer: dialer},
+			CA:               globalCfg.ASRenewal.CA,
+			Dir:              filepath.Join(globalCfg.General.ConfigDir, "crypto/as"),
+			LifetimeFraction: globalCfg.ASRenewal.LifetimeFraction,
+			ReuseKey:         globalCfg.ASRenewal.ReuseKey,
+			Interval:         globalCfg.ASRenewal.Interval.Duration,
+		}
+	}
+	var linkLatencyCfg *cs.LinkLatencyCfg
+	if routers := globalCfg.BS.LinkLatency.Routers; len(routers) > 0 {
+		linkLatencyCfg = &cs.LinkLatencyCfg{
+			Routers:       routers,
+			FetchInterval: globalCfg.BS.LinkLatency.FetchInterval.Duration,
+			MaxAge:        globalCfg.BS.LinkLatency.MaxAge.Duration,
+		}
+	}
+	intfConfigs, err := loadIntfConfigs(globalCfg.BS)
+	if err != nil {
+		return serrors.WrapStr("loading beaconing interface configuration", err)
+	}
 	tasks, err := cs.StartTasks(cs.TasksConfig{
 		Public:   nc.Public,
 		Intfs:    intfs,
@@ -648,6 +807,7 @@ func realMain() error {
 			AddressRewriter: addressRewriter,
 			RPC: beaconinggrpc.BeaconSender{
 				Dialer: dialer,
+				TRCs:   trust.TRCAnnouncer{ISD: topo.IA().I, DB: trustDB},
 			},
 		},
 		SegmentRegister: beaconinggrpc.Registrar{Dialer: dialer},
@@ -666,6 +826,11 @@ func realMain() error {
 		RegistrationInterval:      globalCfg.BS.RegistrationInterval.Duration,
 		DRKeyEpochInterval:        globalCfg.DRKey.EpochDuration.Duration,
 		HiddenPathRegistrationCfg: hpWriterCfg,
+		ASRenewalCfg:              asRenewalCfg,
+		LinkLatencyCfg:            linkLatencyCfg,
+		IntfConfigs:               intfConfigs,
+		PropagationTrigger:        propagationTrigger,
+		Registrations:             registrations,
 		AllowIsdLoop:              isdLoopAllowed,
 	})
 	if err != nil {
@@ -728,6 +893,36 @@ func loadBeaconStore(core bool, ia addr.IA, cfg config.Config) (cs.Store, bool,
 	return store, *policies.Prop.Filter.AllowIsdLoop, err
 }
 
+func loadIntfConfigs(cfg config.BSConfig) (beaconing.IntfConfigs, error) {
+	linkTypes, err := config.ParseLinkTypes(cfg.LinkTypes)
+	if err != nil {
+		return beaconing.IntfConfigs{}, err
+	}
+	interfaces, err := config.ParseInterfaces(cfg.Interfaces)
+	if err != nil {
+		return beaconing.IntfConfigs{}, err
+	}
+	convert := func(c config.BeaconingIntf) beaconing.IntfConfig {
+		return beaconing.IntfConfig{
+			OriginationInterval: c.OriginationInterval.Duration,
+			PropagationInterval: c.PropagationInterval.Duration,
+			BestSetSize:         c.BestSetSize,
+			MaxBeaconsPerSecond: c.MaxBeaconsPerSecond,
+		}
+	}
+	intfConfigs := beaconing.IntfConfigs{
+		LinkTypes:  make(map[topology.LinkType]beaconing.IntfConfig, len(linkTypes)),
+		Interfaces: make(map[common.IFIDType]beaconing.IntfConfig, len(interfaces)),
+	}
+	for linkType, c := range linkTypes {
+		intfConfigs.LinkTypes[linkType] = convert(c)
+	}
+	for ifid, c := range interfaces {
+		intfConfigs.Interfaces[ifid] = convert(c)
+	}
+	return intfConfigs, nil
+}
+
 func loadMasterSecret(dir string) (keyconf.Master, error) {
 	masterKey, err := keyconf.LoadMaster(filepath.Join(dir, "keys"))
 	if err != nil {
@@ -735,3 +930,20 @@ func loadMasterSecret(dir string) (keyconf.Master, error) {
 	}
 	return masterKey, nil
 }
+
+// csPeers returns the underlay addresses of the other control service
+// instances in the topology, keyed by instance name.
+func csPeers(topo topology.Topology, localID string) map[string]*net.UDPAddr {
+	peers := make(map[string]*net.UDPAddr)
+	for _, name := range topo.SVCNames(addr.SvcCS) {
+		if name == localID {
+			continue
+		}
+		underlay, err := topo.UnderlayByName(addr.SvcCS, name)
+		if err != nil {
+			continue
+		}
+		peers[name] = underlay
+	}
+	return peers
+}
@@ -0,0 +1,228 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package revocation propagates revocations between control services.
+//
+// Revocations of local interfaces are signed by the local AS and pushed to all
+// ASes that registered or fetched segments containing the revoked interface.
+// Receivers verify that the revocation is signed by the AS that owns the
+// revoked interface, store it, and forward it to the ASes that are interested
+// in it from their point of view. Revocations that are already known are not
+// forwarded, which bounds the propagation.
+package revocation
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/revcache"
+	"github.com/scionproto/scion/go/lib/scrypto/signed"
+	"github.com/scionproto/scion/go/lib/serrors"
+	cryptopb "github.com/scionproto/scion/go/pkg/proto/crypto"
+)
+
+// DefaultPropagationTimeout is the default timeout for propagating a
+// revocation to all interested ASes.
+const DefaultPropagationTimeout = 5 * time.Second
+
+// Signer signs messages.
+type Signer interface {
+	// Sign signs the msg and returns a signed message.
+	Sign(ctx context.Context, msg []byte, associatedData ...[]byte) (*cryptopb.SignedMessage, error)
+}
+
+// RPC sends signed revocations to a remote control service.
+type RPC interface {
+	SendRevocations(ctx context.Context, revs []*cryptopb.SignedMessage, remote net.Addr) error
+}
+
+// Resolver resolves the address of the control service in a remote AS.
+type Resolver interface {
+	Resolve(ctx context.Context, ia addr.IA) (net.Addr, error)
+}
+
+// Gossiper handles revocations and propagates them to the interested ASes.
+type Gossiper struct {
+	// LocalIA is the ISD-AS of the local AS.
+	LocalIA addr.IA
+	// RevCache stores the revocations.
+	RevCache revcache.RevCache
+	// Tracker provides the ASes that are interested in a revocation.
+	Tracker *Tracker
+	// Signer signs revocations of local interfaces.
+	Signer Signer
+	// Verifier verifies revocations received from remote ASes.
+	Verifier infra.Verifier
+	// RPC is used to send the revocations.
+	RPC RPC
+	// Resolver resolves the remote control services.
+	Resolver Resolver
+}
+
+// Run handles the revocations received on the channel until the channel is
+// closed or the context is canceled.
+func (g *Gossiper) Run(ctx context.Context, revs <-chan *path_mgmt.SignedRevInfo) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case rev, ok := <-revs:
+			if !ok {
+				return
+			}
+			func() {
+				ctx, cancel := context.WithTimeout(ctx, DefaultPropagationTimeout)
+				defer cancel()
+				if err := g.HandleLocal(ctx, rev); err != nil {
+					log.FromCtx(ctx).Info("Failed to propagate revocation", "err", err)
+				}
+			}()
+		}
+	}
+}
+
+// HandleLocal propagates a revocation that was learned locally and is
+// already inserted into the revocation cache. Only revocations of local
+// interfaces are signed and propagated, others are ignored.
+func (g *Gossiper) HandleLocal(ctx context.Context, rev *path_mgmt.SignedRevInfo) error {
+	revInfo, err := rev.RevInfo()
+	if err != nil {
+		return serrors.WrapStr("parsing revocation", err)
+	}
+	if !revInfo.IA().Equal(g.LocalIA) {
+		return nil
+	}
+	raw, err := rev.Pack()
+	if err != nil {
+		return serrors.WrapStr("packing revocation", err)
+	}
+	signedRev, err := g.Signer.Sign(ctx, raw)
+	if err != nil {
+		return serrors.WrapStr("signing revocation", err)
+	}
+	return g.Propagate(ctx, signedRev, revInfo)
+}
+
+// Handle verifies the revocation received from the sender and inserts it into
+// the revocation cache. It returns the parsed revocation and whether it was
+// new. Only new revocations should be propagated further.
+func (g *Gossiper) Handle(ctx context.Context, signedRev *cryptopb.SignedMessage,
+	sender net.Addr) (*path_mgmt.RevInfo, bool, error) {
+
+	// The unverified body is only used to determine the expected signer.
+	rawBody, err := signed.ExtractUnverifiedBody(signedRev)
+	if err != nil {
+		return nil, false, serrors.WrapStr("extracting body", err)
+	}
+	unverified, err := parseRevInfo(rawBody)
+	if err != nil {
+		return nil, false, err
+	}
+	msg, err := g.Verifier.WithIA(unverified.IA()).WithServer(sender).Verify(ctx, signedRev)
+	if err != nil {
+		return nil, false, serrors.WrapStr("verifying signature", err,
+			"isd_as", unverified.IA())
+	}
+	rev, err := path_mgmt.NewSignedRevInfoFromRaw(msg.Body)
+	if err != nil {
+		return nil, false, serrors.WrapStr("parsing verified revocation", err)
+	}
+	revInfo, err := rev.RevInfo()
+	if err != nil {
+		return nil, false, serrors.WrapStr("parsing verified revocation", err)
+	}
+	if !revInfo.IA().Equal(unverified.IA()) {
+		return nil, false, serrors.New("issuer mismatch",
+			"expected", unverified.IA(), "actual", revInfo.IA())
+	}
+	if err := revInfo.Active(); err != nil {
+		return nil, false, err
+	}
+	inserted, err := g.RevCache.Insert(ctx, rev)
+	if err != nil {
+		return nil, false, serrors.WrapStr("inserting revocation", err)
+	}
+	return revInfo, inserted, nil
+}
+
+// Propagate sends the signed revocation to all ASes that are interested in
+// it. The local AS and the excluded ASes are skipped.
+func (g *Gossiper) Propagate(ctx context.Context, signedRev *cryptopb.SignedMessage,
+	revInfo *path_mgmt.RevInfo, exclude ...addr.IA) error {
+
+	skip := map[addr.IA]struct{}{g.LocalIA: {}}
+	for _, ia := range exclude {
+		skip[ia] = struct{}{}
+	}
+	var targets []addr.IA
+	for _, ia := range g.Tracker.Interested(*revcache.NewKey(revInfo.IA(), revInfo.IfID)) {
+		if _, ok := skip[ia]; !ok {
+			targets = append(targets, ia)
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+	log.FromCtx(ctx).Debug("Propagating revocation", "revocation", revInfo,
+		"targets", targets)
+
+	var (
+		mu   sync.Mutex
+		errs serrors.List
+		wg   sync.WaitGroup
+	)
+	for _, ia := range targets {
+		ia := ia
+		wg.Add(1)
+		go func() {
+			defer log.HandlePanic()
+			defer wg.Done()
+			if err := g.send(ctx, signedRev, ia); err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				errs = append(errs, serrors.WithCtx(err, "isd_as", ia))
+			}
+		}()
+	}
+	wg.Wait()
+	return errs.ToError()
+}
+
+func (g *Gossiper) send(ctx context.Context, signedRev *cryptopb.SignedMessage,
+	ia addr.IA) error {
+
+	remote, err := g.Resolver.Resolve(ctx, ia)
+	if err != nil {
+		return serrors.WrapStr("resolving control service", err)
+	}
+	return g.RPC.SendRevocations(ctx, []*cryptopb.SignedMessage{signedRev}, remote)
+}
+
+func parseRevInfo(raw []byte) (*path_mgmt.RevInfo, error) {
+	rev, err := path_mgmt.NewSignedRevInfoFromRaw(raw)
+	if err != nil {
+		return nil, serrors.WrapStr("parsing revocation", err)
+	}
+	revInfo, err := rev.RevInfo()
+	if err != nil {
+		return nil, serrors.WrapStr("parsing revocation", err)
+	}
+	return revInfo, nil
+}
@@ -0,0 +1,180 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revocation_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/cs/revocation"
+	"github.com/scionproto/scion/go/cs/revocation/mock_revocation"
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/infra/mock_infra"
+	"github.com/scionproto/scion/go/lib/revcache/memrevcache"
+	"github.com/scionproto/scion/go/lib/scrypto/signed"
+	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/lib/xtest/graph"
+	cryptopb "github.com/scionproto/scion/go/pkg/proto/crypto"
+	"github.com/scionproto/scion/go/proto"
+)
+
+func TestGossiperHandleLocal(t *testing.T) {
+	local := xtest.MustParseIA("1-ff00:0:110")
+	remote1 := xtest.MustParseIA("1-ff00:0:111")
+	remote2 := xtest.MustParseIA("1-ff00:0:112")
+	remote1Addr := &net.UDPAddr{IP: net.ParseIP("10.0.0.1")}
+	remote2Addr := &net.UDPAddr{IP: net.ParseIP("10.0.0.2")}
+
+	newTracker := func(ctrl *gomock.Controller) *revocation.Tracker {
+		g := graph.NewDefaultGraph(ctrl)
+		tracker := revocation.NewTracker()
+		segs := []*seg.PathSegment{g.Beacon([]common.IFIDType{graph.If_110_X_120_A})}
+		for _, ia := range []addr.IA{local, remote1, remote2} {
+			tracker.Track(ia, segs)
+		}
+		return tracker
+	}
+
+	t.Run("local interface", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		resolver := mock_revocation.NewMockResolver(ctrl)
+		resolver.EXPECT().Resolve(gomock.Any(), remote1).Return(remote1Addr, nil)
+		resolver.EXPECT().Resolve(gomock.Any(), remote2).Return(remote2Addr, nil)
+		rpc := mock_revocation.NewMockRPC(ctrl)
+		rpc.EXPECT().SendRevocations(gomock.Any(), gomock.Len(1), remote1Addr)
+		rpc.EXPECT().SendRevocations(gomock.Any(), gomock.Len(1), remote2Addr)
+
+		g := &revocation.Gossiper{
+			LocalIA:  local,
+			Tracker:  newTracker(ctrl),
+			Signer:   graph.NewSigner(),
+			RPC:      rpc,
+			Resolver: resolver,
+		}
+		err := g.HandleLocal(context.Background(),
+			newRev(t, local, graph.If_110_X_120_A, time.Now()))
+		assert.NoError(t, err)
+	})
+	t.Run("remote interface", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		g := &revocation.Gossiper{
+			LocalIA:  local,
+			Tracker:  newTracker(ctrl),
+			Signer:   graph.NewSigner(),
+			RPC:      mock_revocation.NewMockRPC(ctrl),
+			Resolver: mock_revocation.NewMockResolver(ctrl),
+		}
+		err := g.HandleLocal(context.Background(),
+			newRev(t, remote1, graph.If_110_X_120_A, time.Now()))
+		assert.NoError(t, err)
+	})
+}
+
+func TestGossiperHandle(t *testing.T) {
+	local := xtest.MustParseIA("1-ff00:0:120")
+	issuer := xtest.MustParseIA("1-ff00:0:110")
+	issuerSigner := graph.NewSigner()
+	sender := &net.UDPAddr{IP: net.ParseIP("10.0.0.1")}
+
+	newGossiper := func(ctrl *gomock.Controller) *revocation.Gossiper {
+		verifier := mock_infra.NewMockVerifier(ctrl)
+		verifier.EXPECT().WithIA(issuer).Return(verifier).AnyTimes()
+		verifier.EXPECT().WithServer(sender).Return(verifier).AnyTimes()
+		verifier.EXPECT().Verify(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, msg *cryptopb.SignedMessage,
+				_ ...[]byte) (*signed.Message, error) {
+
+				return signed.Verify(msg, issuerSigner.PrivateKey.Public())
+			},
+		).AnyTimes()
+		return &revocation.Gossiper{
+			LocalIA:  local,
+			RevCache: memrevcache.New(),
+			Verifier: verifier,
+		}
+	}
+	sign := func(t *testing.T, signer graph.Signer,
+		rev *path_mgmt.SignedRevInfo) *cryptopb.SignedMessage {
+
+		raw, err := rev.Pack()
+		require.NoError(t, err)
+		signedRev, err := signer.Sign(context.Background(), raw)
+		require.NoError(t, err)
+		return signedRev
+	}
+
+	t.Run("new and known revocation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		g := newGossiper(ctrl)
+		signedRev := sign(t, issuerSigner, newRev(t, issuer, graph.If_110_X_120_A, time.Now()))
+
+		revInfo, inserted, err := g.Handle(context.Background(), signedRev, sender)
+		require.NoError(t, err)
+		assert.True(t, inserted)
+		assert.Equal(t, issuer, revInfo.IA())
+		assert.Equal(t, graph.If_110_X_120_A, revInfo.IfID)
+
+		_, inserted, err = g.Handle(context.Background(), signedRev, sender)
+		require.NoError(t, err)
+		assert.False(t, inserted)
+	})
+	t.Run("signed by other AS", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		g := newGossiper(ctrl)
+		signedRev := sign(t, graph.NewSigner(),
+			newRev(t, issuer, graph.If_110_X_120_A, time.Now()))
+
+		_, _, err := g.Handle(context.Background(), signedRev, sender)
+		assert.Error(t, err)
+	})
+	t.Run("expired revocation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		g := newGossiper(ctrl)
+		signedRev := sign(t, issuerSigner,
+			newRev(t, issuer, graph.If_110_X_120_A, time.Now().Add(-time.Minute)))
+
+		_, _, err := g.Handle(context.Background(), signedRev, sender)
+		assert.Error(t, err)
+	})
+}
+
+func newRev(t *testing.T, ia addr.IA, ifID common.IFIDType,
+	ts time.Time) *path_mgmt.SignedRevInfo {
+
+	rev, err := path_mgmt.NewSignedRevInfo(&path_mgmt.RevInfo{
+		IfID:         ifID,
+		RawIsdas:     ia.IAInt(),
+		LinkType:     proto.LinkType_core,
+		RawTimestamp: util.TimeToSecs(ts),
+		RawTTL:       10,
+	})
+	require.NoError(t, err)
+	return rev
+}
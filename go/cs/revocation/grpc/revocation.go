@@ -0,0 +1,95 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/scionproto/scion/go/cs/revocation"
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/snet"
+	libgrpc "github.com/scionproto/scion/go/pkg/grpc"
+	cppb "github.com/scionproto/scion/go/pkg/proto/control_plane"
+	cryptopb "github.com/scionproto/scion/go/pkg/proto/crypto"
+)
+
+// Server handles revocations that are pushed by remote control services.
+type Server struct {
+	Gossiper *revocation.Gossiper
+}
+
+// Revocations verifies and stores the pushed revocations. New revocations are
+// propagated further in the background.
+func (s Server) Revocations(ctx context.Context,
+	req *cppb.RevocationsRequest) (*cppb.RevocationsResponse, error) {
+
+	logger := log.FromCtx(ctx)
+	gPeer, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "peer must exist")
+	}
+	var sender addr.IA
+	if a, ok := gPeer.Addr.(*snet.UDPAddr); ok {
+		sender = a.IA
+	}
+	for i, signedRev := range req.SignedRevocations {
+		revInfo, inserted, err := s.Gossiper.Handle(ctx, signedRev, gPeer.Addr)
+		if err != nil {
+			logger.Info("Dropping revocation", "index", i, "peer", gPeer.Addr, "err", err)
+			continue
+		}
+		if !inserted {
+			continue
+		}
+		logger.Debug("Received new revocation", "revocation", revInfo, "peer", gPeer.Addr)
+		go func(signedRev *cryptopb.SignedMessage) {
+			defer log.HandlePanic()
+			ctx, cancel := context.WithTimeout(context.Background(),
+				revocation.DefaultPropagationTimeout)
+			defer cancel()
+			err := s.Gossiper.Propagate(ctx, signedRev, revInfo, sender)
+			if err != nil {
+				logger.Info("Failed to propagate revocation", "err", err)
+			}
+		}(signedRev)
+	}
+	return &cppb.RevocationsResponse{}, nil
+}
+
+// Sender pushes revocations to remote control services.
+type Sender struct {
+	Dialer libgrpc.Dialer
+}
+
+// SendRevocations sends the signed revocations to the remote.
+func (s Sender) SendRevocations(ctx context.Context, revs []*cryptopb.SignedMessage,
+	remote net.Addr) error {
+
+	conn, err := s.Dialer.Dial(ctx, remote)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := cppb.NewRevocationServiceClient(conn)
+	_, err = client.Revocations(ctx, &cppb.RevocationsRequest{SignedRevocations: revs},
+		libgrpc.RetryProfile...)
+	return err
+}
@@ -0,0 +1,100 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/cs/revocation"
+	revgrpc "github.com/scionproto/scion/go/cs/revocation/grpc"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/infra/mock_infra"
+	"github.com/scionproto/scion/go/lib/revcache"
+	"github.com/scionproto/scion/go/lib/revcache/memrevcache"
+	"github.com/scionproto/scion/go/lib/scrypto/signed"
+	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/lib/xtest/graph"
+	cppb "github.com/scionproto/scion/go/pkg/proto/control_plane"
+	cryptopb "github.com/scionproto/scion/go/pkg/proto/crypto"
+	"github.com/scionproto/scion/go/proto"
+)
+
+func TestSendRevocations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	issuer := xtest.MustParseIA("1-ff00:0:110")
+	signer := graph.NewSigner()
+	verifier := mock_infra.NewMockVerifier(ctrl)
+	verifier.EXPECT().WithIA(issuer).Return(verifier).AnyTimes()
+	verifier.EXPECT().WithServer(gomock.Any()).Return(verifier).AnyTimes()
+	verifier.EXPECT().Verify(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, msg *cryptopb.SignedMessage,
+			_ ...[]byte) (*signed.Message, error) {
+
+			return signed.Verify(msg, signer.PrivateKey.Public())
+		},
+	).AnyTimes()
+	revCache := memrevcache.New()
+
+	svc := xtest.NewGRPCService()
+	cppb.RegisterRevocationServiceServer(svc.Server(), revgrpc.Server{
+		Gossiper: &revocation.Gossiper{
+			LocalIA:  xtest.MustParseIA("1-ff00:0:120"),
+			RevCache: revCache,
+			Tracker:  revocation.NewTracker(),
+			Verifier: verifier,
+		},
+	})
+	svc.Start(t)
+
+	rev, err := path_mgmt.NewSignedRevInfo(&path_mgmt.RevInfo{
+		IfID:         graph.If_110_X_120_A,
+		RawIsdas:     issuer.IAInt(),
+		LinkType:     proto.LinkType_core,
+		RawTimestamp: util.TimeToSecs(time.Now()),
+		RawTTL:       10,
+	})
+	require.NoError(t, err)
+	raw, err := rev.Pack()
+	require.NoError(t, err)
+	valid, err := signer.Sign(context.Background(), raw)
+	require.NoError(t, err)
+	invalid, err := graph.NewSigner().Sign(context.Background(), raw)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	sender := revgrpc.Sender{Dialer: svc}
+	err = sender.SendRevocations(ctx, []*cryptopb.SignedMessage{invalid}, &net.UDPAddr{})
+	require.NoError(t, err)
+	revs, err := revCache.Get(ctx, revcache.SingleKey(issuer, graph.If_110_X_120_A))
+	require.NoError(t, err)
+	assert.Empty(t, revs)
+
+	err = sender.SendRevocations(ctx, []*cryptopb.SignedMessage{valid}, &net.UDPAddr{})
+	require.NoError(t, err)
+	revs, err = revCache.Get(ctx, revcache.SingleKey(issuer, graph.If_110_X_120_A))
+	require.NoError(t, err)
+	assert.Len(t, revs, 1)
+}
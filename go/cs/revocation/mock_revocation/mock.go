@@ -0,0 +1,89 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/scionproto/scion/go/cs/revocation (interfaces: RPC,Resolver)
+
+// Package mock_revocation is a generated GoMock package.
+package mock_revocation
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	addr "github.com/scionproto/scion/go/lib/addr"
+	crypto "github.com/scionproto/scion/go/pkg/proto/crypto"
+	net "net"
+	reflect "reflect"
+)
+
+// MockRPC is a mock of RPC interface
+type MockRPC struct {
+	ctrl     *gomock.Controller
+	recorder *MockRPCMockRecorder
+}
+
+// MockRPCMockRecorder is the mock recorder for MockRPC
+type MockRPCMockRecorder struct {
+	mock *MockRPC
+}
+
+// NewMockRPC creates a new mock instance
+func NewMockRPC(ctrl *gomock.Controller) *MockRPC {
+	mock := &MockRPC{ctrl: ctrl}
+	mock.recorder = &MockRPCMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRPC) EXPECT() *MockRPCMockRecorder {
+	return m.recorder
+}
+
+// SendRevocations mocks base method
+func (m *MockRPC) SendRevocations(arg0 context.Context, arg1 []*crypto.SignedMessage, arg2 net.Addr) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendRevocations", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendRevocations indicates an expected call of SendRevocations
+func (mr *MockRPCMockRecorder) SendRevocations(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendRevocations", reflect.TypeOf((*MockRPC)(nil).SendRevocations), arg0, arg1, arg2)
+}
+
+// MockResolver is a mock of Resolver interface
+type MockResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockResolverMockRecorder
+}
+
+// MockResolverMockRecorder is the mock recorder for MockResolver
+type MockResolverMockRecorder struct {
+	mock *MockResolver
+}
+
+// NewMockResolver creates a new mock instance
+func NewMockResolver(ctrl *gomock.Controller) *MockResolver {
+	mock := &MockResolver{ctrl: ctrl}
+	mock.recorder = &MockResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockResolver) EXPECT() *MockResolverMockRecorder {
+	return m.recorder
+}
+
+// Resolve mocks base method
+func (m *MockResolver) Resolve(arg0 context.Context, arg1 addr.IA) (net.Addr, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", arg0, arg1)
+	ret0, _ := ret[0].(net.Addr)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve
+func (mr *MockResolverMockRecorder) Resolve(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockResolver)(nil).Resolve), arg0, arg1)
+}
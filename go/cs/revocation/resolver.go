@@ -0,0 +1,47 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revocation
+
+import (
+	"context"
+	"net"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+)
+
+// RouterResolver resolves the control service of a remote AS to an SVC
+// address that is reached over a path provided by the router.
+type RouterResolver struct {
+	Router snet.Router
+}
+
+// Resolve returns the address of the control service in the given AS.
+func (r RouterResolver) Resolve(ctx context.Context, ia addr.IA) (net.Addr, error) {
+	path, err := r.Router.Route(ctx, ia)
+	if err != nil {
+		return nil, serrors.WrapStr("looking up path", err, "isd_as", ia)
+	}
+	if path == nil {
+		return nil, serrors.New("no path found", "isd_as", ia)
+	}
+	return &snet.SVCAddr{
+		IA:      ia,
+		Path:    path.Path(),
+		NextHop: path.UnderlayNextHop(),
+		SVC:     addr.SvcCS,
+	}, nil
+}
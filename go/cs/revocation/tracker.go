@@ -0,0 +1,108 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revocation
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/revcache"
+)
+
+// SegmentTracker tracks which ASes registered or fetched which segments.
+type SegmentTracker interface {
+	// Track records that the AS registered or fetched the segments.
+	Track(ia addr.IA, segs []*seg.PathSegment)
+}
+
+var _ SegmentTracker = (*Tracker)(nil)
+
+// Tracker keeps track of the ASes that are interested in the revocations of
+// an interface, i.e., the ASes that registered or fetched segments that
+// contain the interface. The interest expires together with the segments.
+// Tracker is meant to be run as a periodic task that removes expired entries.
+type Tracker struct {
+	mu         sync.Mutex
+	interested map[revcache.Key]map[addr.IA]time.Time
+}
+
+// NewTracker creates a new tracker.
+func NewTracker() *Tracker {
+	return &Tracker{
+		interested: make(map[revcache.Key]map[addr.IA]time.Time),
+	}
+}
+
+// Track records that the AS is interested in the revocations of all
+// interfaces that are part of the segments.
+func (t *Tracker) Track(ia addr.IA, segs []*seg.PathSegment) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, s := range segs {
+		expiry := s.MaxExpiry()
+		for key := range revcache.SegmentKeys(s) {
+			ias, ok := t.interested[key]
+			if !ok {
+				ias = make(map[addr.IA]time.Time)
+				t.interested[key] = ias
+			}
+			if expiry.After(ias[ia]) {
+				ias[ia] = expiry
+			}
+		}
+	}
+}
+
+// Interested returns the ASes that are interested in revocations of the
+// interface identified by the key. The result is sorted.
+func (t *Tracker) Interested(key revcache.Key) []addr.IA {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	var result []addr.IA
+	for ia, expiry := range t.interested[key] {
+		if expiry.After(now) {
+			result = append(result, ia)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].IAInt() < result[j].IAInt() })
+	return result
+}
+
+// Name returns the task name.
+func (t *Tracker) Name() string {
+	return "revocation_tracker_cleaner"
+}
+
+// Run removes all expired entries.
+func (t *Tracker) Run(_ context.Context) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	for key, ias := range t.interested {
+		for ia, expiry := range ias {
+			if !expiry.After(now) {
+				delete(ias, ia)
+			}
+		}
+		if len(ias) == 0 {
+			delete(t.interested, key)
+		}
+	}
+}
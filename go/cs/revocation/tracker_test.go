@@ -0,0 +1,60 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revocation_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/cs/revocation"
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/revcache"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/lib/xtest/graph"
+)
+
+func TestTracker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	g := graph.NewDefaultGraph(ctrl)
+	ia110 := xtest.MustParseIA("1-ff00:0:110")
+	ia120 := xtest.MustParseIA("1-ff00:0:120")
+	remote1 := xtest.MustParseIA("1-ff00:0:111")
+	remote2 := xtest.MustParseIA("1-ff00:0:112")
+
+	tracker := revocation.NewTracker()
+	tracker.Track(remote2, []*seg.PathSegment{
+		g.Beacon([]common.IFIDType{graph.If_110_X_120_A, graph.If_120_A_130_B}),
+	})
+	tracker.Track(remote1, []*seg.PathSegment{
+		g.Beacon([]common.IFIDType{graph.If_110_X_120_A}),
+	})
+
+	assert.Equal(t, []addr.IA{remote1, remote2},
+		tracker.Interested(*revcache.NewKey(ia110, graph.If_110_X_120_A)))
+	assert.Equal(t, []addr.IA{remote2},
+		tracker.Interested(*revcache.NewKey(ia120, graph.If_120_A_130_B)))
+	assert.Empty(t, tracker.Interested(*revcache.NewKey(ia110, graph.If_110_X_130_A)))
+
+	// Entries that are not expired are kept by the cleanup.
+	tracker.Run(context.Background())
+	assert.Equal(t, []addr.IA{remote1, remote2},
+		tracker.Interested(*revcache.NewKey(ia110, graph.If_110_X_120_A)))
+}
@@ -23,6 +23,7 @@ import (
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
+	"github.com/scionproto/scion/go/cs/revocation"
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/ctrl/seg"
 	"github.com/scionproto/scion/go/lib/infra"
@@ -42,6 +43,17 @@ var _ cppb.SegmentRegistrationServiceServer
 type RegistrationServer struct {
 	LocalIA    addr.IA
 	SegHandler seghandler.Handler
+	// Tracker records which remote ASes registered which segments, such that
+	// revocations can be pushed to them. If it is nil, nothing is recorded.
+	Tracker revocation.SegmentTracker
+	// StateID identifies the registration state of this server. It is
+	// reported to the registrants, which register their segments again when
+	// it changes. It must therefore change whenever registered segments may
+	// have been lost, e.g., on every start. Instances of the same AS, e.g.,
+	// behind an anycast address, may report different IDs, registrants
+	// remember the IDs of the recently responding instances. If it is zero, no
+	// state is reported.
+	StateID uint64
 
 	// Requests aggregates all the incoming registration requests. If it is not
 	// initialized, nothing is reported.
@@ -106,8 +118,15 @@ func (s *RegistrationServer) SegmentsRegistration(ctx context.Context,
 		// TODO(roosd): Classify crypto/db error and return appropriate status code.
 		return nil, err
 	}
+	if s.Tracker != nil {
+		registered := make([]*seg.PathSegment, 0, len(segs))
+		for _, meta := range segs {
+			registered = append(registered, meta.Segment)
+		}
+		s.Tracker.Track(peer.IA, registered)
+	}
 	s.successMetric(span, labels, res.Stats())
-	return &cppb.SegmentsRegistrationResponse{}, nil
+	return &cppb.SegmentsRegistrationResponse{StateId: s.StateID}, nil
 }
 
 func (s *RegistrationServer) failMetric(span opentracing.Span, l requestLabels, err error) {
@@ -28,6 +28,7 @@ import (
 	"github.com/scionproto/scion/go/lib/infra"
 	"github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
 	"github.com/scionproto/scion/go/lib/infra/modules/seghandler"
+	"github.com/scionproto/scion/go/lib/infra/modules/upstream"
 	"github.com/scionproto/scion/go/lib/pathdb"
 	"github.com/scionproto/scion/go/lib/pathdb/query"
 	"github.com/scionproto/scion/go/lib/revcache"
@@ -54,6 +55,10 @@ type FetcherConfig struct {
 	RevCache revcache.RevCache
 	// RPC is the RPC used to request segments.
 	RPC segfetcher.RPC
+	// Balancer spreads the segment requests across the instances of the
+	// remote control services. If it is nil, the requests are sent to the
+	// SVC address.
+	Balancer *upstream.Balancer
 }
 
 // NewFetcher creates a segment fetcher configured for fetching segments from
@@ -94,6 +99,7 @@ func NewFetcher(cfg FetcherConfig) *segfetcher.Fetcher {
 			RPC:         cfg.RPC,
 			DstProvider: d,
 			MaxRetries:  20,
+			Balancer:    cfg.Balancer,
 		},
 		Metrics: segfetcher.NewFetcherMetrics("control"),
 	}
@@ -16,6 +16,7 @@ package segreq
 
 import (
 	"context"
+	"time"
 
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/ctrl/seg"
@@ -31,6 +32,13 @@ type ForwardingLookup struct {
 	CoreChecker CoreChecker
 	Fetcher     *segfetcher.Fetcher
 	Expander    WildcardExpander
+	// Splitter splits a path lookup to a destination into the segment
+	// requests that are required to answer it, without wildcards. It is used
+	// for prefetching.
+	Splitter segfetcher.Splitter
+	// Prefetcher records the destinations of the lookups. If it is nil, the
+	// destinations are not recorded.
+	Prefetcher *segfetcher.Prefetcher
 }
 
 // LookupSegments looks up the segments for the given request
@@ -45,6 +53,7 @@ func (f ForwardingLookup) LookupSegments(ctx context.Context, src,
 	if err != nil {
 		return nil, err
 	}
+	f.Prefetcher.Record(dst)
 
 	reqs, err := f.Expander.ExpandSrcWildcard(ctx,
 		segfetcher.Request{
@@ -59,6 +68,22 @@ func (f ForwardingLookup) LookupSegments(ctx context.Context, src,
 	return f.Fetcher.Fetch(ctx, reqs, false)
 }
 
+// Prefetch refreshes the segments that end hosts in the local AS require to
+// build paths to the destination, if their next query time is within the lead
+// time. It returns the number of segment requests that were sent.
+func (f ForwardingLookup) Prefetch(ctx context.Context, dst addr.IA,
+	leadTime time.Duration) (int, error) {
+
+	if dst.Equal(f.LocalIA) {
+		return 0, nil
+	}
+	reqs, err := f.Splitter.Split(ctx, dst)
+	if err != nil {
+		return 0, serrors.WrapStr("splitting request", err)
+	}
+	return f.Fetcher.Prefetch(ctx, reqs, leadTime)
+}
+
 // classify validates the request and determines the segment type for the request
 func (f ForwardingLookup) classify(ctx context.Context,
 	src, dst addr.IA) (seg.Type, error) {
@@ -18,7 +18,9 @@ import (
 	"context"
 
 	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/peer"
 
+	"github.com/scionproto/scion/go/cs/revocation"
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/ctrl/seg"
 	"github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
@@ -26,6 +28,7 @@ import (
 	"github.com/scionproto/scion/go/lib/metrics"
 	"github.com/scionproto/scion/go/lib/prom"
 	"github.com/scionproto/scion/go/lib/revcache"
+	"github.com/scionproto/scion/go/lib/snet"
 	"github.com/scionproto/scion/go/lib/tracing"
 	cppb "github.com/scionproto/scion/go/pkg/proto/control_plane"
 )
@@ -39,6 +42,9 @@ type Lookuper interface {
 type LookupServer struct {
 	Lookuper Lookuper
 	RevCache revcache.RevCache
+	// Tracker records which remote ASes fetched which segments, such that
+	// revocations can be pushed to them. If it is nil, nothing is recorded.
+	Tracker revocation.SegmentTracker
 
 	// Requests aggregates all the incoming requests received by the handler.
 	// If it is not initialized, nothing is reported.
@@ -96,6 +102,14 @@ func (s LookupServer) Segments(ctx context.Context,
 		rawRevs = append(rawRevs, rawRev)
 	}
 
+	if s.Tracker != nil {
+		if p, ok := peer.FromContext(ctx); ok {
+			if remote, ok := p.Addr.(*snet.UDPAddr); ok {
+				s.Tracker.Track(remote.IA, segs.Segs())
+			}
+		}
+	}
+
 	m := map[int32]*cppb.SegmentsResponse_Segments{}
 	for _, meta := range segs {
 		s, ok := m[int32(meta.Type)]
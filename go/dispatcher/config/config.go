@@ -27,6 +27,7 @@ import (
 	"github.com/scionproto/scion/go/lib/sock/reliable"
 	"github.com/scionproto/scion/go/lib/topology"
 	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/pkg/api"
 )
 
 var _ config.Config = (*Config)(nil)
@@ -35,6 +36,7 @@ type Config struct {
 	Features   env.Features `toml:"features,omitempty"`
 	Logging    log.Config   `toml:"log,omitempty"`
 	Metrics    env.Metrics  `toml:"metrics,omitempty"`
+	API        api.Config   `toml:"api,omitempty"`
 	Dispatcher Dispatcher   `toml:"dispatcher,omitempty"`
 }
 
@@ -81,6 +83,7 @@ func (cfg *Config) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
 		&cfg.Features,
 		&cfg.Logging,
 		&cfg.Metrics,
+		&cfg.API,
 		dispSampler,
 	)
 }
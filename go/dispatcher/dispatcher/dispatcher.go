@@ -111,6 +111,22 @@ func (as *Server) Register(ctx context.Context, ia addr.IA, address *net.UDPAddr
 	return conn, uint16(ref.UDPAddr().Port), nil
 }
 
+// Registrations returns the currently active application registrations.
+func (as *Server) Registrations() []Registration {
+	return as.routingTable.Registrations()
+}
+
+// Registration returns the application registration with the given ID.
+func (as *Server) Registration(id uint64) (Registration, bool) {
+	return as.routingTable.Registration(id)
+}
+
+// CloseRegistration forcibly closes the application registration with the
+// given ID.
+func (as *Server) CloseRegistration(id uint64) bool {
+	return as.routingTable.CloseRegistration(id)
+}
+
 func (as *Server) Close() {
 	as.ipv4Conn.Close()
 	as.ipv6Conn.Close()
@@ -16,6 +16,10 @@ package dispatcher
 
 import (
 	"net"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/scionproto/scion/go/dispatcher/internal/registration"
 	"github.com/scionproto/scion/go/lib/addr"
@@ -23,7 +27,15 @@ import (
 )
 
 type TableEntry struct {
+	// The counters are accessed atomically and must be kept at the start of
+	// the struct for 64-bit alignment.
+	delivered  uint64
+	dropped    uint64
+	scmpErrors uint64
+
 	appIngressRing *ringbuf.Ring
+	// info is set when the entry is registered in the IATable.
+	info Registration
 }
 
 func newTableEntry() *TableEntry {
@@ -34,15 +46,133 @@ func newTableEntry() *TableEntry {
 	}
 }
 
+// Registration describes an application registration.
+type Registration struct {
+	// ID uniquely identifies the registration for the lifetime of the
+	// dispatcher.
+	ID uint64
+	// IA is the ISD-AS the application registered in.
+	IA addr.IA
+	// Public is the public address of the registration.
+	Public *net.UDPAddr
+	// Bind is the bind address of an SVC registration. It is nil if the
+	// application did not register for an SVC address.
+	Bind *net.UDPAddr
+	// SVC is the SVC address the application registered for.
+	SVC addr.HostSVC
+	// Registered is the time the application registered.
+	Registered time.Time
+	// Stats are the packet statistics at the time the registration was
+	// listed.
+	Stats RegistrationStats
+}
+
+// RegistrationStats are the packet statistics of an application registration.
+type RegistrationStats struct {
+	// Delivered is the number of packets enqueued to the application.
+	Delivered uint64
+	// Dropped is the number of packets dropped because the application's
+	// ingress ring was full.
+	Dropped uint64
+	// SCMPErrors is the number of SCMP error messages enqueued to the
+	// application.
+	SCMPErrors uint64
+}
+
+func (e *TableEntry) registration() Registration {
+	r := e.info
+	r.Stats = RegistrationStats{
+		Delivered:  atomic.LoadUint64(&e.delivered),
+		Dropped:    atomic.LoadUint64(&e.dropped),
+		SCMPErrors: atomic.LoadUint64(&e.scmpErrors),
+	}
+	return r
+}
+
 // IATable is a type-safe convenience wrapper around a generic routing table.
+// Additionally, it keeps track of all registrations such that they can be
+// listed and closed by operators.
 type IATable struct {
 	registration.IATable
+
+	mtx     sync.Mutex
+	nextID  uint64
+	entries map[uint64]*TableEntry
 }
 
 func NewIATable(minPort, maxPort int) *IATable {
 	return &IATable{
 		IATable: registration.NewIATable(minPort, maxPort),
+		entries: make(map[uint64]*TableEntry),
+	}
+}
+
+// Register registers the entry in the routing table. The entry is listed
+// until the returned reference is freed.
+func (t *IATable) Register(ia addr.IA, public *net.UDPAddr, bind net.IP, svc addr.HostSVC,
+	entry *TableEntry) (registration.RegReference, error) {
+
+	ref, err := t.IATable.Register(ia, public, bind, svc, entry)
+	if err != nil {
+		return nil, err
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.nextID++
+	entry.info = Registration{
+		ID:         t.nextID,
+		IA:         ia,
+		Public:     ref.UDPAddr(),
+		SVC:        svc,
+		Registered: time.Now(),
+	}
+	if svc != addr.SvcNone {
+		if bind == nil {
+			bind = ref.UDPAddr().IP
+		}
+		entry.info.Bind = &net.UDPAddr{IP: bind, Port: ref.UDPAddr().Port}
 	}
+	t.entries[t.nextID] = entry
+	return &entryReference{RegReference: ref, table: t, id: t.nextID}, nil
+}
+
+// Registrations returns the currently active registrations sorted by ID.
+func (t *IATable) Registrations() []Registration {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	regs := make([]Registration, 0, len(t.entries))
+	for _, e := range t.entries {
+		regs = append(regs, e.registration())
+	}
+	sort.Slice(regs, func(i, j int) bool { return regs[i].ID < regs[j].ID })
+	return regs
+}
+
+// Registration returns the registration with the given ID. If no such
+// registration exists, the returned boolean is set to false.
+func (t *IATable) Registration(id uint64) (Registration, bool) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	e, ok := t.entries[id]
+	if !ok {
+		return Registration{}, false
+	}
+	return e.registration(), true
+}
+
+// CloseRegistration forcibly closes the registration with the given ID. The
+// application's ingress ring is closed, which causes the connection to the
+// application to be torn down and the registration to be freed. If no such
+// registration exists, the returned boolean is set to false.
+func (t *IATable) CloseRegistration(id uint64) bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	e, ok := t.entries[id]
+	if !ok {
+		return false
+	}
+	e.appIngressRing.Close()
+	return true
 }
 
 func (t *IATable) LookupPublic(ia addr.IA, public *net.UDPAddr) (*TableEntry, bool) {
@@ -69,3 +199,18 @@ func (t *IATable) LookupID(ia addr.IA, id uint64) (*TableEntry, bool) {
 	}
 	return e.(*TableEntry), true
 }
+
+// entryReference removes the entry from the list of registrations when it is
+// freed.
+type entryReference struct {
+	registration.RegReference
+	table *IATable
+	id    uint64
+}
+
+func (r *entryReference) Free() {
+	r.table.mtx.Lock()
+	delete(r.table.entries, r.id)
+	r.table.mtx.Unlock()
+	r.RegReference.Free()
+}
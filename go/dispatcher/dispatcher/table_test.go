@@ -0,0 +1,90 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/dispatcher/internal/respool"
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/ringbuf"
+	"github.com/scionproto/scion/go/lib/slayers"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestIATableRegistrations(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	table := NewIATable(32768, 65535)
+
+	app := newTableEntry()
+	appRef, err := table.Register(ia, &net.UDPAddr{IP: net.IP{192, 0, 2, 1}}, nil,
+		addr.SvcNone, app)
+	require.NoError(t, err)
+	cs := newTableEntry()
+	csRef, err := table.Register(ia, &net.UDPAddr{IP: net.IP{192, 0, 2, 2}, Port: 30252}, nil,
+		addr.SvcCS, cs)
+	require.NoError(t, err)
+	defer csRef.Free()
+
+	regs := table.Registrations()
+	require.Len(t, regs, 2)
+	assert.Equal(t, uint64(1), regs[0].ID)
+	assert.Equal(t, ia, regs[0].IA)
+	assert.Equal(t, appRef.UDPAddr(), regs[0].Public)
+	assert.Nil(t, regs[0].Bind)
+	assert.Equal(t, addr.SvcNone, regs[0].SVC)
+	assert.Equal(t, uint64(2), regs[1].ID)
+	assert.Equal(t, &net.UDPAddr{IP: net.IP{192, 0, 2, 2}, Port: 30252}, regs[1].Bind)
+	assert.Equal(t, addr.SvcCS, regs[1].SVC)
+
+	t.Run("stats", func(t *testing.T) {
+		sendPacket(app, respool.GetPacket())
+		scmpErr := respool.GetPacket()
+		scmpErr.L4 = slayers.LayerTypeSCMP
+		scmpErr.SCMP.TypeCode = slayers.CreateSCMPTypeCode(
+			slayers.SCMPTypeDestinationUnreachable, 0)
+		sendPacket(app, scmpErr)
+
+		reg, ok := table.Registration(1)
+		require.True(t, ok)
+		assert.Equal(t, RegistrationStats{Delivered: 2, SCMPErrors: 1}, reg.Stats)
+
+		app.appIngressRing.Read(make(ringbuf.EntryList, 2), false)
+		for i := 0; i < 129; i++ {
+			sendPacket(app, respool.GetPacket())
+		}
+		reg, ok = table.Registration(1)
+		require.True(t, ok)
+		assert.Equal(t, uint64(1), reg.Stats.Dropped)
+	})
+	t.Run("close", func(t *testing.T) {
+		assert.False(t, table.CloseRegistration(3))
+		assert.True(t, table.CloseRegistration(1))
+		n, _ := app.appIngressRing.Read(make(ringbuf.EntryList, 128), false)
+		assert.Equal(t, 128, n)
+		n, _ = app.appIngressRing.Read(make(ringbuf.EntryList, 1), false)
+		assert.Equal(t, -1, n)
+	})
+	t.Run("free", func(t *testing.T) {
+		appRef.Free()
+		_, ok := table.Registration(1)
+		assert.False(t, ok)
+		assert.Len(t, table.Registrations(), 1)
+	})
+}
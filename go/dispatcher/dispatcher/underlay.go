@@ -17,6 +17,7 @@ package dispatcher
 
 import (
 	"net"
+	"sync/atomic"
 
 	"github.com/google/gopacket"
 
@@ -401,9 +402,16 @@ type Destination interface {
 // reference to pkt.
 func sendPacket(routingEntry *TableEntry, pkt *respool.Packet) {
 	// Move packet reference to other goroutine.
+	isSCMPErr := pkt.L4 == slayers.LayerTypeSCMP && !pkt.SCMP.TypeCode.InfoMsg()
 	count, _ := routingEntry.appIngressRing.Write(ringbuf.EntryList{pkt}, false)
 	if count <= 0 {
+		atomic.AddUint64(&routingEntry.dropped, 1)
 		// Release buffer if we couldn't transmit it to the other goroutine.
 		pkt.Free()
+		return
+	}
+	atomic.AddUint64(&routingEntry.delivered, 1)
+	if isSCMPErr {
+		atomic.AddUint64(&routingEntry.scmpErrors, 1)
 	}
 }
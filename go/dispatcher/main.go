@@ -17,16 +17,23 @@ package dispatcher_bin
 
 import (
 	"fmt"
+	"net/http"
 	_ "net/http/pprof"
 	"os"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/cors"
+
 	"github.com/scionproto/scion/go/dispatcher/config"
+	"github.com/scionproto/scion/go/dispatcher/dispatcher"
 	"github.com/scionproto/scion/go/dispatcher/network"
 	"github.com/scionproto/scion/go/lib/fatal"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/slayers/path"
 	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/pkg/dispatcher/api"
+	"github.com/scionproto/scion/go/pkg/service"
 )
 
 var globalCfg config.Config
@@ -93,13 +100,38 @@ func RunDispatcher(deleteSocketFlag bool, applicationSocket string, socketFileMo
 			return err
 		}
 	}
-	dispatcher := &network.Dispatcher{
+	server, err := dispatcher.NewServer(fmt.Sprintf(":%d", underlayPort), nil, nil)
+	if err != nil {
+		return err
+	}
+	if globalCfg.API.Addr != "" {
+		r := chi.NewRouter()
+		r.Use(cors.Handler(cors.Options{
+			AllowedOrigins: []string{"*"},
+		}))
+		apiServer := api.Server{
+			Registrations: server,
+			Config:        service.NewConfigHandler(globalCfg),
+			Info:          service.NewInfoHandler(),
+			LogLevel:      log.ConsoleLevel.ServeHTTP,
+		}
+		log.Info("Exposing API", "addr", globalCfg.API.Addr)
+		h := api.HandlerFromMux(&apiServer, r)
+		go func() {
+			defer log.HandlePanic()
+			if err := http.ListenAndServe(globalCfg.API.Addr, h); err != nil {
+				fatal.Fatal(serrors.WrapStr("serving HTTP API", err))
+			}
+		}()
+	}
+	netDispatcher := &network.Dispatcher{
 		UnderlaySocket:    fmt.Sprintf(":%d", underlayPort),
 		ApplicationSocket: applicationSocket,
 		SocketFileMode:    socketFileMode,
+		Server:            server,
 	}
 	log.Debug("Dispatcher starting", "appSocket", applicationSocket, "underlayPort", underlayPort)
-	return dispatcher.ListenAndServe()
+	return netDispatcher.ListenAndServe()
 }
 
 func deleteSocket(socket string) error {
@@ -199,7 +199,10 @@ func (h *AppConnHandler) RunRingToAppDataplane() {
 	for {
 		pkt := h.DispConn.Read()
 		if pkt == nil {
-			// Ring was closed because app shut down its data socket
+			// Ring was closed because app shut down its data socket, or
+			// because the registration was closed by an operator. In the
+			// latter case, closing the connection tears down the handler.
+			h.Conn.Close()
 			return
 		}
 		n, err := pkt.SendOnConn(h.Conn, pkt.UnderlayRemote)
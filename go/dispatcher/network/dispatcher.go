@@ -27,12 +27,18 @@ type Dispatcher struct {
 	UnderlaySocket    string
 	ApplicationSocket string
 	SocketFileMode    os.FileMode
+	// Server is the dispatcher server that handles the registrations. If
+	// nil, a new server is created on the UnderlaySocket.
+	Server *dispatcher.Server
 }
 
 func (d *Dispatcher) ListenAndServe() error {
-	dispServer, err := dispatcher.NewServer(d.UnderlaySocket, nil, nil)
-	if err != nil {
-		return err
+	dispServer := d.Server
+	if dispServer == nil {
+		var err error
+		if dispServer, err = dispatcher.NewServer(d.UnderlaySocket, nil, nil); err != nil {
+			return err
+		}
 	}
 	defer dispServer.Close()
 
@@ -0,0 +1,40 @@
+// Copyright 2021 ETH Zurich
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"github.com/scionproto/scion/go/lib/drkey"
+)
+
+var _ Derivation = gateway{}
+
+// gateway implements the derivation for the keys protecting the tunnels
+// between SCION IP gateways.
+type gateway struct{}
+
+// Name returns gateway.
+func (gateway) Name() string {
+	return "gateway"
+}
+
+// DeriveLvl2 uses the standard derivation.
+func (gateway) DeriveLvl2(meta drkey.Lvl2Meta, key drkey.Lvl1Key) (drkey.Lvl2Key, error) {
+	return Standard{}.DeriveLvl2(meta, key)
+}
+
+func init() {
+	g := gateway{}
+	KnownDerivations[g.Name()] = g
+}
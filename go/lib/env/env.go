@@ -292,6 +292,8 @@ func (cfg *Tracing) NewTracer(id string) (opentracing.Tracer, io.Closer, error)
 // QUIC contains configuration for control-plane speakers.
 type QUIC struct {
 	Address string `toml:"address,omitempty"`
+	// Multipath enables multipath QUIC for the control-plane RPCs.
+	Multipath bool `toml:"multipath,omitempty"`
 }
 
 func (cfg *QUIC) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
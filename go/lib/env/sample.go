@@ -59,4 +59,9 @@ const quicSample = `
 # The address to start a QUIC server on (ip:port). If not set, a QUIC server on
 # the public IP and a high port is started. (default "")
 address = ""
+
+# Enable multipath QUIC. If enabled, QUIC sessions to remote ASes keep a set of
+# paths, probe them, and migrate to another path if the active path is revoked
+# or degrades. (default false)
+multipath = false
 `
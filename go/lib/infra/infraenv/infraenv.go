@@ -39,6 +39,7 @@ import (
 	"github.com/scionproto/scion/go/lib/sciond"
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/snet/multipath"
 	"github.com/scionproto/scion/go/lib/snet/squic"
 	"github.com/scionproto/scion/go/lib/sock/reliable"
 	"github.com/scionproto/scion/go/lib/sock/reliable/reconnect"
@@ -49,6 +50,12 @@ import (
 type QUIC struct {
 	// Address is the UDP address to start the QUIC server on.
 	Address string
+	// Multipath, if set, enables multipath QUIC. The client sockets keep a
+	// set of paths to every remote AS and migrate the QUIC sessions between
+	// them. The server sockets reply on the path of the most recently
+	// received packet. If nil, QUIC sessions use the path chosen by the
+	// caller.
+	Multipath *multipath.Config
 }
 
 // NetworkConfig describes the networking configuration of a SCION
@@ -74,6 +81,11 @@ type NetworkConfig struct {
 	// ignore SCMP messages. Otherwise, the server will shutdown when receiving
 	// an SCMP error message.
 	SCMPHandler snet.SCMPHandler
+	// SVCInstances, if set, tracks the healthy instances of the service. The
+	// SVC resolution replies then announce all healthy instances, such that
+	// clients can spread their requests. The transports of the local instance
+	// are set when the QUIC stack is initialized.
+	SVCInstances *svc.Instances
 }
 
 // QUICStack contains everything to run a QUIC based RPC stack.
@@ -238,6 +250,13 @@ func (nc *NetworkConfig) initSvcRedirect(quicAddress string, tlsQUICAdress strin
 	if err != nil {
 		return nil, serrors.WrapStr("building SVC resolution reply", err)
 	}
+	handler := &svc.BaseHandler{
+		Message: svcResolutionReply,
+	}
+	if nc.SVCInstances != nil {
+		nc.SVCInstances.Local = reply.Transports
+		handler.Source = nc.SVCInstances
+	}
 
 	dispatcherService := reliable.NewDispatcher("")
 	if nc.ReconnectToDispatcher {
@@ -248,9 +267,7 @@ func (nc *NetworkConfig) initSvcRedirect(quicAddress string, tlsQUICAdress strin
 			Dispatcher:  dispatcherService,
 			SCMPHandler: nc.SCMPHandler,
 		},
-		&svc.BaseHandler{
-			Message: svcResolutionReply,
-		},
+		handler,
 	)
 	network := &snet.SCIONNetwork{
 		LocalIA:    nc.IA,
@@ -322,6 +339,10 @@ func (nc *NetworkConfig) initQUICSockets(ignorePort bool) (net.PacketConn, net.P
 	if err != nil {
 		return nil, nil, serrors.WrapStr("creating client connection", err)
 	}
+	if nc.QUIC.Multipath != nil {
+		return multipath.NewConn(client, *nc.QUIC.Multipath),
+			multipath.NewConn(server, multipath.Config{}), nil
+	}
 	return client, server, nil
 }
 
@@ -145,6 +145,64 @@ func (r AddressRewriter) redirectToQUIC(ctx context.Context,
 		"addr", fmt.Sprintf("%v(%T)", address, address))
 }
 
+// ResolveInstances resolves the SVC address to the QUIC addresses of all
+// healthy instances that the remote announces in its SVC resolution reply. If
+// the remote does not announce its instances, only the address of the
+// instance that replied is returned. The returned addresses use the path the
+// reply arrived on. If the path is empty, i.e., the remote is in the local AS,
+// the next hop of each address is the instance itself.
+func (r AddressRewriter) ResolveInstances(ctx context.Context,
+	address *snet.SVCAddr) ([]*snet.UDPAddr, error) {
+
+	fa, err := r.buildFullAddress(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	path, err := fa.GetPath()
+	if err != nil {
+		return nil, serrors.WrapStr("bad path", err)
+	}
+	reply, err := r.Resolver.LookupSVC(ctx, path, fa.SVC)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, serrors.New("nil reply")
+	}
+	instances := []map[svc.Transport]string{reply.Transports}
+	if len(reply.Instances) > 0 {
+		instances = instances[:0]
+		for _, instance := range reply.Instances {
+			instances = append(instances, instance.Transports)
+		}
+	}
+	returnPath := reply.ReturnPath.Path()
+	var ret []*snet.UDPAddr
+	for _, transports := range instances {
+		u, err := parseTransports(transports, svc.QUIC)
+		if err != nil {
+			log.FromCtx(ctx).Debug("Ignoring announced instance", "err", err)
+			continue
+		}
+		nextHop := fa.NextHop
+		// In the local AS, the next hop is the instance itself and not the
+		// instance that the SVC address was resolved to.
+		if returnPath.IsEmpty() {
+			nextHop = &net.UDPAddr{IP: u.IP, Port: topology.EndhostPort, Zone: u.Zone}
+		}
+		ret = append(ret, &snet.UDPAddr{
+			IA:      fa.IA,
+			Path:    returnPath,
+			NextHop: nextHop,
+			Host:    u,
+		})
+	}
+	if len(ret) == 0 {
+		return nil, serrors.New("no instance with QUIC address announced")
+	}
+	return ret, nil
+}
+
 // buildFullAddress checks that a is a well-formed address (all fields set,
 // non-nil, only supported protocols). If the path is missing, the path and
 // next-hop are added by performing a routing lookup. The returned address is
@@ -243,7 +301,13 @@ func parseReply(reply *svc.Reply, transport svc.Transport) (*net.UDPAddr, error)
 	if reply.Transports == nil {
 		return nil, serrors.New("empty reply")
 	}
-	addressStr, ok := reply.Transports[transport]
+	return parseTransports(reply.Transports, transport)
+}
+
+func parseTransports(transports map[svc.Transport]string,
+	transport svc.Transport) (*net.UDPAddr, error) {
+
+	addressStr, ok := transports[transport]
 	if !ok {
 		return nil, serrors.New("QUIC server address not found")
 	}
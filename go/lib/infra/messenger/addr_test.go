@@ -190,6 +190,147 @@ func TestRedirectQUIC(t *testing.T) {
 	})
 }
 
+func TestResolveInstances(t *testing.T) {
+	dummyIA := xtest.MustParseIA("1-ff00:0:2")
+	nextHop := &net.UDPAddr{IP: net.ParseIP("10.1.1.1")}
+	returnPath := spath.Path{Raw: []byte{1, 2, 3}, Type: scion.PathType}
+	testCases := map[string]struct {
+		reply     *svc.Reply
+		lookupErr error
+		want      []*snet.UDPAddr
+		assertErr assert.ErrorAssertionFunc
+	}{
+		"lookup error": {
+			lookupErr: errors.New("lookup error"),
+			assertErr: assert.Error,
+		},
+		"no instances announced": {
+			reply: &svc.Reply{
+				Transports: map[svc.Transport]string{svc.QUIC: "192.168.1.1:8000"},
+				ReturnPath: &testPath{path: returnPath},
+			},
+			want: []*snet.UDPAddr{
+				{
+					IA:      dummyIA,
+					Path:    returnPath,
+					NextHop: nextHop,
+					Host:    &net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 8000},
+				},
+			},
+			assertErr: assert.NoError,
+		},
+		"instances announced": {
+			reply: &svc.Reply{
+				Transports: map[svc.Transport]string{svc.QUIC: "192.168.1.1:8000"},
+				Instances: []svc.Instance{
+					{Transports: map[svc.Transport]string{svc.QUIC: "192.168.1.1:8000"}},
+					{Transports: map[svc.Transport]string{svc.TLSQUIC: "192.168.1.3:8000"}},
+					{Transports: map[svc.Transport]string{svc.QUIC: "192.168.1.2:8000"}},
+				},
+				ReturnPath: &testPath{path: returnPath},
+			},
+			want: []*snet.UDPAddr{
+				{
+					IA:      dummyIA,
+					Path:    returnPath,
+					NextHop: nextHop,
+					Host:    &net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 8000},
+				},
+				{
+					IA:      dummyIA,
+					Path:    returnPath,
+					NextHop: nextHop,
+					Host:    &net.UDPAddr{IP: net.ParseIP("192.168.1.2"), Port: 8000},
+				},
+			},
+			assertErr: assert.NoError,
+		},
+		"no QUIC address": {
+			reply: &svc.Reply{
+				Transports: map[svc.Transport]string{svc.TLSQUIC: "192.168.1.1:8000"},
+				ReturnPath: &testPath{path: returnPath},
+			},
+			assertErr: assert.Error,
+		},
+	}
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			router := mock_snet.NewMockRouter(ctrl)
+			resolver := mock_messenger.NewMockResolver(ctrl)
+			resolver.EXPECT().LookupSVC(gomock.Any(), gomock.Any(), addr.SvcCS).
+				Return(tc.reply, tc.lookupErr)
+			path := mock_snet.NewMockPath(ctrl)
+			router.EXPECT().Route(gomock.Any(), dummyIA).Return(path, nil)
+			path.EXPECT().Path().Return(spath.Path{})
+			path.EXPECT().UnderlayNextHop().Return(nextHop)
+			path.EXPECT().Metadata().Return(&snet.PathMetadata{
+				Interfaces: make([]snet.PathInterface, 1), // just non-empty
+			})
+
+			aw := messenger.AddressRewriter{
+				Router:                router,
+				Resolver:              resolver,
+				SVCResolutionFraction: 1,
+			}
+			input := &snet.SVCAddr{IA: dummyIA, SVC: addr.SvcCS}
+			got, err := aw.ResolveInstances(context.Background(), input)
+			tc.assertErr(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+	t.Run("local AS", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		router := mock_snet.NewMockRouter(ctrl)
+		resolver := mock_messenger.NewMockResolver(ctrl)
+		svcRouter := mock_messenger.NewMockLocalSVCRouter(ctrl)
+		path := mock_snet.NewMockPath(ctrl)
+		router.EXPECT().Route(gomock.Any(), dummyIA).Return(path, nil)
+		path.EXPECT().Path().Return(spath.Path{})
+		path.EXPECT().UnderlayNextHop().Return(nil)
+		path.EXPECT().Metadata().Return(&snet.PathMetadata{})
+		// The SVC address is resolved to the underlay of the first instance.
+		svcRouter.EXPECT().GetUnderlay(addr.SvcCS).Return(
+			&net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 30041}, nil)
+		resolver.EXPECT().LookupSVC(gomock.Any(), gomock.Any(), addr.SvcCS).Return(
+			&svc.Reply{
+				Transports: map[svc.Transport]string{svc.QUIC: "192.168.1.1:8000"},
+				Instances: []svc.Instance{
+					{Transports: map[svc.Transport]string{svc.QUIC: "192.168.1.1:8000"}},
+					{Transports: map[svc.Transport]string{svc.QUIC: "192.168.1.2:8000"}},
+				},
+				ReturnPath: &testPath{},
+			}, nil)
+
+		aw := messenger.AddressRewriter{
+			Router:                router,
+			SVCRouter:             svcRouter,
+			Resolver:              resolver,
+			SVCResolutionFraction: 1,
+		}
+		input := &snet.SVCAddr{IA: dummyIA, SVC: addr.SvcCS}
+		got, err := aw.ResolveInstances(context.Background(), input)
+		assert.NoError(t, err)
+		want := []*snet.UDPAddr{
+			{
+				IA:      dummyIA,
+				Path:    spath.Path{},
+				NextHop: &net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 30041},
+				Host:    &net.UDPAddr{IP: net.ParseIP("192.168.1.1"), Port: 8000},
+			},
+			{
+				IA:      dummyIA,
+				Path:    spath.Path{},
+				NextHop: &net.UDPAddr{IP: net.ParseIP("192.168.1.2"), Port: 30041},
+				Host:    &net.UDPAddr{IP: net.ParseIP("192.168.1.2"), Port: 8000},
+			},
+		}
+		assert.Equal(t, want, got)
+	})
+}
+
 func TestBuildFullAddress(t *testing.T) {
 	t.Run("snet address without path, error retrieving path", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
@@ -458,14 +599,16 @@ func initResolver(resolver *mock_messenger.MockResolver, f func(*mock_messenger.
 	}
 }
 
-type testPath struct{}
+type testPath struct {
+	path spath.Path
+}
 
 func (t *testPath) UnderlayNextHop() *net.UDPAddr {
 	panic("not implemented")
 }
 
 func (t *testPath) Path() spath.Path {
-	return spath.Path{}
+	return t.path
 }
 
 func (t *testPath) Destination() addr.IA {
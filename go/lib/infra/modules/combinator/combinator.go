@@ -59,16 +59,51 @@ import (
 func Combine(src, dst addr.IA, ups, cores, downs []*seg.PathSegment,
 	findAllIdentical bool) []Path {
 
+	candidates, _ := combine(src, dst, ups, cores, downs, findAllIdentical)
+	var paths []Path
+	for _, c := range candidates {
+		if c.Filtered == "" {
+			paths = append(paths, c.Path)
+		}
+	}
+	return paths
+}
+
+// combine constructs all candidate paths between src and dst, and sets the
+// reason for the candidates that are filtered. The candidates are sorted, and
+// the solution they are constructed from is returned at the same index. The
+// segments of the candidates are not set.
+func combine(src, dst addr.IA, ups, cores, downs []*seg.PathSegment,
+	findAllIdentical bool) ([]Candidate, pathSolutionList) {
+
 	solutions := newDMG(ups, cores, downs).GetPaths(vertexFromIA(src), vertexFromIA(dst))
-	paths := make([]Path, len(solutions))
+	candidates := make([]Candidate, len(solutions))
+	// remaining are the paths that are not filtered yet, and their index in
+	// candidates.
+	var remaining []Path
+	var remainingIdx []int
 	for i, solution := range solutions {
-		paths[i] = solution.Path()
+		candidates[i].Path = solution.Path()
+		if isLongPath(candidates[i].Path) {
+			candidates[i].Filtered = FilterLoop
+			continue
+		}
+		remaining = append(remaining, candidates[i].Path)
+		remainingIdx = append(remainingIdx, i)
 	}
-	paths = filterLongPaths(paths)
-	if !findAllIdentical {
-		paths = filterDuplicates(paths)
+	if findAllIdentical {
+		return candidates, solutions
 	}
-	return paths
+	keep := make(map[int]bool, len(remaining))
+	for _, i := range uniquePaths(remaining) {
+		keep[remainingIdx[i]] = true
+	}
+	for _, i := range remainingIdx {
+		if !keep[i] {
+			candidates[i].Filtered = FilterDuplicate
+		}
+	}
+	return candidates, solutions
 }
 
 type Path struct {
@@ -78,36 +113,28 @@ type Path struct {
 	Weight   int // XXX(matzf): unused, drop this?
 }
 
-// filterLongPaths returns a new slice containing only those paths that do not
-// go more than twice through interfaces belonging to the same AS (thus
-// filtering paths containing useless loops).
-func filterLongPaths(paths []Path) []Path {
-	var newPaths []Path
-	for _, path := range paths {
-		long := false
-		iaCounts := make(map[addr.IA]int)
-		for _, iface := range path.Metadata.Interfaces {
-			iaCounts[iface.IA]++
-			if iaCounts[iface.IA] > 2 {
-				long = true
-				break
-			}
-		}
-		if !long {
-			newPaths = append(newPaths, path)
+// isLongPath indicates whether the path goes more than twice through interfaces
+// belonging to the same AS.
+func isLongPath(path Path) bool {
+	iaCounts := make(map[addr.IA]int)
+	for _, iface := range path.Metadata.Interfaces {
+		iaCounts[iface.IA]++
+		if iaCounts[iface.IA] > 2 {
+			return true
 		}
 	}
-	return newPaths
+	return false
 }
 
-// filterDuplicates removes paths with identical sequences of path interfaces,
-// keeping only the one instance with latest expiry.
+// uniquePaths returns the sorted indices of the paths to keep when removing
+// duplicates, i.e., the index of the path with the latest expiry for every
+// unique path interface sequence.
 // Duplicates can arise when multiple combinations of different path segments
 // result in the same "effective" path after applying short-cuts.
 // XXX(matzf): the duplicates could/should be avoided directly by reducing the
 // available options in the graph, as we could potentially create a large
 // number of duplicates in wide network topologies.
-func filterDuplicates(paths []Path) []Path {
+func uniquePaths(paths []Path) []int {
 	// uniquePaths stores the index of the path with the latest expiry for every
 	// unique path interface sequence (== fingerprint).
 	uniquePaths := make(map[snet.PathFingerprint]int)
@@ -124,11 +151,7 @@ func filterDuplicates(paths []Path) []Path {
 		toKeep = append(toKeep, idx)
 	}
 	sort.Ints(toKeep)
-	filtered := make([]Path, 0, len(toKeep))
-	for _, i := range toKeep {
-		filtered = append(filtered, paths[i])
-	}
-	return filtered
+	return toKeep
 }
 
 // fingerprint uniquely identifies the path based on the sequence of
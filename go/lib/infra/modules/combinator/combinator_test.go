@@ -92,6 +92,17 @@ func TestBadPeering(t *testing.T) {
 			expected, err := ioutil.ReadFile(xtest.ExpandPath(tc.FileName))
 			assert.NoError(t, err)
 			assert.Equal(t, string(expected), txtResult.String())
+
+			// The candidates that are not filtered by Explain are the paths
+			// returned by Combine.
+			var explained []combinator.Path
+			for _, c := range combinator.Explain(tc.SrcIA, tc.DstIA, tc.Ups, tc.Cores,
+				tc.Downs) {
+				if c.Filtered == "" {
+					explained = append(explained, c.Path)
+				}
+			}
+			assert.Equal(t, result, explained)
 		})
 	}
 }
@@ -138,6 +149,17 @@ func TestMultiPeering(t *testing.T) {
 			expected, err := ioutil.ReadFile(xtest.ExpandPath(tc.FileName))
 			assert.NoError(t, err)
 			assert.Equal(t, string(expected), txtResult.String())
+
+			// The candidates that are not filtered by Explain are the paths
+			// returned by Combine.
+			var explained []combinator.Path
+			for _, c := range combinator.Explain(tc.SrcIA, tc.DstIA, tc.Ups, tc.Cores,
+				tc.Downs) {
+				if c.Filtered == "" {
+					explained = append(explained, c.Path)
+				}
+			}
+			assert.Equal(t, result, explained)
 		})
 	}
 }
@@ -181,6 +203,17 @@ func TestSameCoreParent(t *testing.T) {
 			expected, err := ioutil.ReadFile(xtest.ExpandPath(tc.FileName))
 			assert.NoError(t, err)
 			assert.Equal(t, string(expected), txtResult.String())
+
+			// The candidates that are not filtered by Explain are the paths
+			// returned by Combine.
+			var explained []combinator.Path
+			for _, c := range combinator.Explain(tc.SrcIA, tc.DstIA, tc.Ups, tc.Cores,
+				tc.Downs) {
+				if c.Filtered == "" {
+					explained = append(explained, c.Path)
+				}
+			}
+			assert.Equal(t, result, explained)
 		})
 	}
 }
@@ -232,6 +265,86 @@ func TestLoops(t *testing.T) {
 			expected, err := ioutil.ReadFile(xtest.ExpandPath(tc.FileName))
 			assert.NoError(t, err)
 			assert.Equal(t, string(expected), txtResult.String())
+
+			// The candidates that are not filtered by Explain are the paths
+			// returned by Combine.
+			var explained []combinator.Path
+			for _, c := range combinator.Explain(tc.SrcIA, tc.DstIA, tc.Ups, tc.Cores,
+				tc.Downs) {
+				if c.Filtered == "" {
+					explained = append(explained, c.Path)
+				}
+			}
+			assert.Equal(t, result, explained)
+		})
+	}
+}
+
+func TestBeaconedSegments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	g := graph.NewDefaultGraph(ctrl)
+
+	// The segments are created by simulating beaconing over the default graph,
+	// the paths must only contain the shortcuts and peering links that are
+	// valid in SCION.
+	testCases := []struct {
+		Name     string
+		FileName string
+		SrcIA    addr.IA
+		DstIA    addr.IA
+		// NoCores omits the core segments, such that only shortcut and peering
+		// paths are constructed.
+		NoCores bool
+	}{
+		{
+			Name:     "shortcut between siblings of the same parent",
+			FileName: "00_beaconed_shortcut.txt",
+			SrcIA:    xtest.MustParseIA("1-ff00:0:111"),
+			DstIA:    xtest.MustParseIA("1-ff00:0:112"),
+		},
+		{
+			Name:     "peering in the same isd",
+			FileName: "01_beaconed_peering.txt",
+			SrcIA:    xtest.MustParseIA("1-ff00:0:121"),
+			DstIA:    xtest.MustParseIA("1-ff00:0:131"),
+		},
+		{
+			Name:     "peering across isds",
+			FileName: "02_beaconed_peering.txt",
+			SrcIA:    xtest.MustParseIA("1-ff00:0:112"),
+			DstIA:    xtest.MustParseIA("2-ff00:0:212"),
+			NoCores:  true,
+		},
+		{
+			Name:     "peering with core alternatives",
+			FileName: "03_beaconed_peering.txt",
+			SrcIA:    xtest.MustParseIA("1-ff00:0:133"),
+			DstIA:    xtest.MustParseIA("1-ff00:0:122"),
+		},
+		{
+			Name:     "core to core",
+			FileName: "04_beaconed_core.txt",
+			SrcIA:    xtest.MustParseIA("1-ff00:0:110"),
+			DstIA:    xtest.MustParseIA("2-ff00:0:220"),
+		},
+	}
+	t.Log("TestBeaconedSegments")
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			ups, cores, downs := g.Segments(tc.SrcIA, tc.DstIA)
+			if tc.NoCores {
+				cores = nil
+			}
+			result := combinator.Combine(tc.SrcIA, tc.DstIA, ups, cores, downs, false)
+			txtResult := writePaths(result)
+			if *update {
+				err := ioutil.WriteFile(xtest.ExpandPath(tc.FileName), txtResult.Bytes(), 0644)
+				xtest.FailOnErr(t, err)
+			}
+			expected, err := ioutil.ReadFile(xtest.ExpandPath(tc.FileName))
+			assert.NoError(t, err)
+			assert.Equal(t, string(expected), txtResult.String())
 		})
 	}
 }
@@ -539,6 +652,17 @@ func TestComputePath(t *testing.T) {
 			expected, err := ioutil.ReadFile(xtest.ExpandPath(tc.FileName))
 			assert.NoError(t, err)
 			assert.Equal(t, string(expected), txtResult.String())
+
+			// The candidates that are not filtered by Explain are the paths
+			// returned by Combine.
+			var explained []combinator.Path
+			for _, c := range combinator.Explain(tc.SrcIA, tc.DstIA, tc.Ups, tc.Cores,
+				tc.Downs) {
+				if c.Filtered == "" {
+					explained = append(explained, c.Path)
+				}
+			}
+			assert.Equal(t, result, explained)
 		})
 	}
 }
@@ -668,11 +792,11 @@ func TestFilterDuplicates(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.Name, func(t *testing.T) {
 
-			filtered := combinator.FilterDuplicates(tc.Paths)
+			toKeep := combinator.UniquePaths(tc.Paths)
 			// extract IDs hidden in the raw paths:
-			filteredIds := make([]uint32, len(filtered))
-			for i, path := range filtered {
-				filteredIds[i] = binary.LittleEndian.Uint32(path.SPath.Raw)
+			filteredIds := make([]uint32, len(toKeep))
+			for i, idx := range toKeep {
+				filteredIds[i] = binary.LittleEndian.Uint32(tc.Paths[idx].SPath.Raw)
 			}
 			assert.Equal(t, tc.Expected, filteredIds)
 		})
@@ -0,0 +1,98 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package combinator
+
+import (
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/proto"
+)
+
+// FilterReason describes why a candidate path was filtered.
+type FilterReason string
+
+// Reasons for filtering candidate paths in Combine.
+const (
+	// FilterLoop indicates that the path goes more than twice through
+	// interfaces of the same AS, see isLongPath.
+	FilterLoop FilterReason = "loop"
+	// FilterDuplicate indicates that another path with the same sequence of
+	// path interfaces and a later expiry exists, see uniquePaths.
+	FilterDuplicate FilterReason = "duplicate"
+)
+
+// Candidate is a path that was found in the segment graph.
+type Candidate struct {
+	// Path is the path constructed from the segments.
+	Path Path
+	// Segments are the segments the path is constructed from, in path order.
+	Segments []CandidateSegment
+	// Filtered is the reason why the path was filtered. It is empty if the
+	// path is returned by Combine.
+	Filtered FilterReason
+}
+
+// CandidateSegment describes how a segment is used in a candidate path.
+type CandidateSegment struct {
+	// ID is the segment ID.
+	ID []byte
+	// Type is the segment type.
+	Type seg.Type
+	// Shortcut is the index of the AS entry where the segment is left (up
+	// segments) or entered (down segments). If it is 0, the full segment is
+	// used.
+	Shortcut int
+	// Peer is the index + 1 of the peer entry in the AS entry at the shortcut
+	// index that is used to cross a peering link. It is 0 if no peering link is
+	// used.
+	Peer int
+}
+
+// Explain constructs the paths between src and dst using the supplied segments
+// like Combine with findAllIdentical=false, but returns all candidate paths
+// that are found in the segment graph, including the filtered ones. The
+// candidates are sorted in the same order as the paths returned by Combine,
+// and the candidates that are not filtered correspond to these paths.
+func Explain(src, dst addr.IA, ups, cores, downs []*seg.PathSegment) []Candidate {
+	candidates, solutions := combine(src, dst, ups, cores, downs, false)
+	for i, solution := range solutions {
+		candidates[i].Segments = solution.candidateSegments()
+	}
+	return candidates
+}
+
+func (solution *pathSolution) candidateSegments() []CandidateSegment {
+	segments := make([]CandidateSegment, 0, len(solution.edges))
+	for _, solEdge := range solution.edges {
+		segments = append(segments, CandidateSegment{
+			ID:       solEdge.segment.ID(),
+			Type:     segTypeFromProto(solEdge.segment.Type),
+			Shortcut: solEdge.edge.Shortcut,
+			Peer:     solEdge.edge.Peer,
+		})
+	}
+	return segments
+}
+
+func segTypeFromProto(t proto.PathSegType) seg.Type {
+	switch t {
+	case proto.PathSegType_up:
+		return seg.TypeUp
+	case proto.PathSegType_down:
+		return seg.TypeDown
+	default:
+		return seg.TypeCore
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package combinator_test
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/infra/modules/combinator"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/lib/xtest/graph"
+)
+
+func TestExplain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	g := graph.NewDefaultGraph(ctrl)
+
+	src := xtest.MustParseIA("1-ff00:0:111")
+	dst := xtest.MustParseIA("1-ff00:0:112")
+	ups := []*seg.PathSegment{
+		g.Beacon([]common.IFIDType{graph.If_120_X_111_B}),
+		g.Beacon([]common.IFIDType{graph.If_130_B_111_A}),
+	}
+	cores := []*seg.PathSegment{
+		g.Beacon([]common.IFIDType{graph.If_130_B_120_A}),
+		g.Beacon([]common.IFIDType{graph.If_120_A_130_B}),
+	}
+	downs := []*seg.PathSegment{
+		g.Beacon([]common.IFIDType{graph.If_130_A_112_X}),
+		g.Beacon([]common.IFIDType{graph.If_130_B_111_A, graph.If_111_A_112_X}),
+		// The same down segment again, such that duplicates are constructed.
+		g.Beacon([]common.IFIDType{graph.If_130_B_111_A, graph.If_111_A_112_X}),
+	}
+
+	candidates := combinator.Explain(src, dst, ups, cores, downs)
+	paths := combinator.Combine(src, dst, ups, cores, downs, false)
+
+	var kept []combinator.Path
+	reasons := make(map[combinator.FilterReason]int)
+	for _, c := range candidates {
+		require.NotEmpty(t, c.Segments)
+		if c.Filtered == "" {
+			kept = append(kept, c.Path)
+			continue
+		}
+		reasons[c.Filtered]++
+	}
+	assert.Equal(t, paths, kept)
+	assert.NotZero(t, reasons[combinator.FilterLoop])
+	assert.NotZero(t, reasons[combinator.FilterDuplicate])
+
+	for _, c := range candidates {
+		if c.Filtered != combinator.FilterLoop {
+			continue
+		}
+		iaCounts := make(map[string]int)
+		maxCount := 0
+		for _, intf := range c.Path.Metadata.Interfaces {
+			iaCounts[intf.IA.String()]++
+			if iaCounts[intf.IA.String()] > maxCount {
+				maxCount = iaCounts[intf.IA.String()]
+			}
+		}
+		assert.Greater(t, maxCount, 2)
+	}
+	// The first candidate is the shortest path, that uses the shortcut through
+	// the down segment.
+	assert.Equal(t, []combinator.CandidateSegment{{
+		ID:       candidates[0].Segments[0].ID,
+		Type:     seg.TypeDown,
+		Shortcut: 1,
+	}}, candidates[0].Segments)
+}
@@ -15,5 +15,5 @@
 package combinator
 
 var (
-	FilterDuplicates = filterDuplicates
+	UniquePaths = uniquePaths
 )
@@ -475,6 +475,17 @@ func (sl pathSolutionList) Less(i, j int) bool {
 			return trailI[ki].edge.Peer < trailJ[ki].edge.Peer
 		}
 	}
+	// Segments with the same hops can still differ in their info field. Order
+	// them too, such that the solutions are always sorted the same way.
+	for ki := range trailI {
+		infoI, infoJ := trailI[ki].segment.Info, trailJ[ki].segment.Info
+		if !infoI.Timestamp.Equal(infoJ.Timestamp) {
+			return infoI.Timestamp.Before(infoJ.Timestamp)
+		}
+		if infoI.SegmentID != infoJ.SegmentID {
+			return infoI.SegmentID < infoJ.SegmentID
+		}
+	}
 	return false
 }
 
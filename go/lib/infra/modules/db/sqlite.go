@@ -22,10 +22,23 @@ import (
 	"github.com/scionproto/scion/go/lib/serrors"
 )
 
+// Migrations maps a schema version to the statements that upgrade a database
+// from that version to the next version.
+type Migrations map[int]string
+
 // NewSqlite returns a new SQLite backend opening a database at the given path. If
 // no database exists a new database is be created. If the schema version of the
 // stored database is different from schemaVersion, an error is returned.
 func NewSqlite(path string, schema string, schemaVersion int) (*sql.DB, error) {
+	return NewSqliteWithMigrations(path, schema, schemaVersion, nil)
+}
+
+// NewSqliteWithMigrations is like NewSqlite, but a stored database with an older
+// schema version is upgraded in place using the migrations. If a migration step
+// is missing, an error is returned.
+func NewSqliteWithMigrations(path string, schema string, schemaVersion int,
+	migrations Migrations) (*sql.DB, error) {
+
 	var err error
 	if path == "" {
 		return nil, serrors.New("Empty path not allowed for sqlite")
@@ -54,12 +67,54 @@ func NewSqlite(path string, schema string, schemaVersion int) (*sql.DB, error) {
 			return nil, err
 		}
 	} else if existingVersion != schemaVersion {
-		return nil, serrors.New("Database schema version mismatch",
-			"expected", schemaVersion, "have", existingVersion, "path", path)
+		if !canMigrate(migrations, existingVersion, schemaVersion) {
+			err = serrors.New("Database schema version mismatch",
+				"expected", schemaVersion, "have", existingVersion, "path", path)
+			return nil, err
+		}
+		if err = migrate(db, migrations, existingVersion, schemaVersion, path); err != nil {
+			return nil, err
+		}
 	}
 	return db, nil
 }
 
+func canMigrate(migrations Migrations, from, to int) bool {
+	if from > to {
+		return false
+	}
+	for v := from; v < to; v++ {
+		if _, ok := migrations[v]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// migrate applies the migrations in a single transaction, so that a failed
+// migration leaves the database at its previous version.
+func migrate(db *sql.DB, migrations Migrations, from, to int, path string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return serrors.WrapStr("Failed to start migration", err, "path", path)
+	}
+	for v := from; v < to; v++ {
+		if _, err := tx.Exec(migrations[v]); err != nil {
+			tx.Rollback()
+			return serrors.WrapStr("Failed to migrate SQLite database", err,
+				"from", v, "to", v+1, "path", path)
+		}
+	}
+	if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", to)); err != nil {
+		tx.Rollback()
+		return serrors.WrapStr("Failed to write schema version", err, "path", path)
+	}
+	if err := tx.Commit(); err != nil {
+		return serrors.WrapStr("Failed to commit migration", err, "path", path)
+	}
+	return nil
+}
+
 func open(path string) (*sql.DB, error) {
 	var err error
 	u, err := url.Parse(path)
@@ -0,0 +1,121 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segfetcher
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/infra/modules/combinator"
+	"github.com/scionproto/scion/go/lib/revcache"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+)
+
+// Reasons for filtering candidate paths in the Pather, in addition to the ones
+// in the combinator.
+const (
+	// FilterExpired indicates that the path is expired.
+	FilterExpired combinator.FilterReason = "expired"
+	// FilterRevoked indicates that an interface on the path is revoked.
+	FilterRevoked combinator.FilterReason = "revoked"
+)
+
+// Explanation describes how the paths to a destination are constructed.
+type Explanation struct {
+	// Src is the source AS of the paths.
+	Src addr.IA
+	// Dst is the requested destination, which can be a wildcard.
+	Dst addr.IA
+	// Segments are the segments the paths are constructed from.
+	Segments Segments
+	// FetchErr is the error that occurred while fetching the segments, if
+	// any. The paths are constructed from the available segments anyway.
+	FetchErr error
+	// Candidates are all paths that are found in the segment graph, grouped by
+	// destination AS. Within a destination, the candidates are sorted from
+	// best to worst.
+	Candidates []Candidate
+}
+
+// Candidate is a path that was found in the segment graph.
+type Candidate struct {
+	combinator.Candidate
+	// Dst is the destination AS of the path.
+	Dst addr.IA
+	// Revoked are the revoked interfaces on the path.
+	Revoked []snet.PathInterface
+}
+
+// Explain fetches the segments to the destination like GetPaths, and returns
+// all candidate paths that are constructed from them, including the ones that
+// are filtered, together with the reason why they are filtered.
+func (p *Pather) Explain(ctx context.Context, dst addr.IA, refresh bool) (*Explanation, error) {
+	if dst.I == 0 {
+		return nil, serrors.WithCtx(ErrBadDst, "dst", dst)
+	}
+	src := p.TopoProvider.Get().IA()
+	if dst.Equal(src) {
+		// AS local communication uses an empty path, no segments are needed.
+		return &Explanation{Src: src, Dst: dst}, nil
+	}
+	reqs, err := p.Splitter.Split(ctx, dst)
+	if err != nil {
+		return nil, err
+	}
+	segs, fetchErr := p.Fetcher.Fetch(ctx, reqs, refresh)
+	explanation := ExplainPaths(ctx, src, dst, segs, p.RevCache, time.Now())
+	explanation.FetchErr = fetchErr
+	return explanation, nil
+}
+
+// ExplainPaths constructs all candidate paths from src to dst with the given
+// segments, and determines for each candidate whether and why it is filtered.
+// The candidates that are not filtered correspond to the paths that the Pather
+// returns for the same segments. If revCache is nil, revocations are not
+// considered.
+func ExplainPaths(ctx context.Context, src, dst addr.IA, segs Segments,
+	revCache revcache.RevCache, now time.Time) *Explanation {
+
+	up, core, down := categorizeSegs(segs)
+	var destinations []addr.IA
+	for dst := range findDestinations(src, dst, up, core) {
+		destinations = append(destinations, dst)
+	}
+	sort.Slice(destinations, func(i, j int) bool {
+		return destinations[i].IAInt() < destinations[j].IAInt()
+	})
+
+	explanation := &Explanation{Src: src, Dst: dst, Segments: segs}
+	for _, dst := range destinations {
+		for _, c := range combinator.Explain(src, dst, up, core, down) {
+			candidate := Candidate{Candidate: c, Dst: dst}
+			if candidate.Filtered == "" && !candidate.Path.Metadata.Expiry.After(now) {
+				candidate.Filtered = FilterExpired
+			}
+			if candidate.Filtered == "" && revCache != nil {
+				candidate.Revoked = revokedIntfs(ctx, revCache,
+					candidate.Path.Metadata.Interfaces)
+				if len(candidate.Revoked) > 0 {
+					candidate.Filtered = FilterRevoked
+				}
+			}
+			explanation.Candidates = append(explanation.Candidates, candidate)
+		}
+	}
+	return explanation
+}
@@ -0,0 +1,123 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segfetcher_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/infra/modules/combinator"
+	"github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
+	"github.com/scionproto/scion/go/lib/revcache"
+	"github.com/scionproto/scion/go/lib/revcache/mock_revcache"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/lib/xtest/graph"
+)
+
+func TestExplainPaths(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	g := graph.NewDefaultGraph(ctrl)
+
+	src := xtest.MustParseIA("1-ff00:0:111")
+	dst := xtest.MustParseIA("1-ff00:0:112")
+	segs := segfetcher.Segments{
+		{Type: seg.TypeUp, Segment: g.Beacon([]common.IFIDType{graph.If_130_B_111_A})},
+		{Type: seg.TypeCore, Segment: g.Beacon([]common.IFIDType{graph.If_130_B_120_A})},
+		{Type: seg.TypeDown, Segment: g.Beacon([]common.IFIDType{graph.If_130_A_112_X})},
+		{Type: seg.TypeDown, Segment: g.Beacon(
+			[]common.IFIDType{graph.If_130_B_111_A, graph.If_111_A_112_X})},
+	}
+	now := segs[0].Segment.Info.Timestamp
+	revoked := snet.PathInterface{IA: dst, ID: graph.If_112_X_130_A}
+
+	filtered := func(e *segfetcher.Explanation) map[combinator.FilterReason]int {
+		reasons := make(map[combinator.FilterReason]int)
+		for _, c := range e.Candidates {
+			reasons[c.Filtered]++
+		}
+		return reasons
+	}
+
+	t.Run("without revocations", func(t *testing.T) {
+		e := segfetcher.ExplainPaths(context.Background(), src, dst, segs, nil, now)
+		assert.Equal(t, src, e.Src)
+		assert.Equal(t, dst, e.Dst)
+		assert.Equal(t, segs, e.Segments)
+		require.Len(t, e.Candidates, 3)
+		assert.Equal(t, map[combinator.FilterReason]int{"": 2, combinator.FilterLoop: 1},
+			filtered(e))
+		for _, c := range e.Candidates {
+			assert.Equal(t, dst, c.Dst)
+		}
+	})
+	t.Run("revoked interface", func(t *testing.T) {
+		revCache := mock_revcache.NewMockRevCache(ctrl)
+		revCache.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, keys revcache.KeySet) (revcache.Revocations, error) {
+				revs := revcache.Revocations{}
+				for k := range keys {
+					if k.IA == revoked.IA && k.IfId == revoked.ID {
+						revs[k] = &path_mgmt.SignedRevInfo{}
+					}
+				}
+				return revs, nil
+			},
+		).AnyTimes()
+
+		e := segfetcher.ExplainPaths(context.Background(), src, dst, segs, revCache, now)
+		assert.Equal(t, map[combinator.FilterReason]int{
+			"":                       1,
+			combinator.FilterLoop:    1,
+			segfetcher.FilterRevoked: 1,
+		}, filtered(e))
+		for _, c := range e.Candidates {
+			if c.Filtered == segfetcher.FilterRevoked {
+				assert.Equal(t, []snet.PathInterface{revoked}, c.Revoked)
+			}
+		}
+	})
+	t.Run("expired", func(t *testing.T) {
+		e := segfetcher.ExplainPaths(context.Background(), src, dst, segs, nil,
+			now.Add(24*time.Hour))
+		assert.Equal(t, map[combinator.FilterReason]int{
+			combinator.FilterLoop:    1,
+			segfetcher.FilterExpired: 2,
+		}, filtered(e))
+	})
+	t.Run("wildcard destination", func(t *testing.T) {
+		coreSegs := append(segfetcher.Segments{{
+			Type:    seg.TypeCore,
+			Segment: g.Beacon([]common.IFIDType{graph.If_120_A_130_B}),
+		}}, segs...)
+		e := segfetcher.ExplainPaths(context.Background(), src,
+			xtest.MustParseIA("1-0"), coreSegs, nil, now)
+		var dsts []string
+		for _, c := range e.Candidates {
+			dsts = append(dsts, c.Dst.String())
+		}
+		assert.Contains(t, dsts, "1-ff00:0:130")
+		assert.Contains(t, dsts, "1-ff00:0:120")
+	})
+}
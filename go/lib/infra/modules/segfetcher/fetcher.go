@@ -73,6 +73,7 @@ func (f *Fetcher) Fetch(ctx context.Context, reqs Requests, refresh bool) (Segme
 		fmt.Printf("f.Resolver.Resolve returned error %v\n", err)
 		return Segments{}, serrors.Wrap(errDB, err)
 	}
+	f.incLookups(len(fetchReqs) == 0)
 	if len(fetchReqs) == 0 {
 		return loadedSegs, nil
 	}
@@ -85,6 +86,45 @@ func (f *Fetcher) Fetch(ctx context.Context, reqs Requests, refresh bool) (Segme
 	return append(loadedSegs, fetchedSegs...), err
 }
 
+// Prefetch refreshes the segments for the requests whose next query time is
+// within the lead time, such that later lookups can be answered from the
+// local cache. It returns the number of segment requests that were sent.
+func (f *Fetcher) Prefetch(ctx context.Context, reqs Requests,
+	leadTime time.Duration) (int, error) {
+
+	var due Requests
+	for _, req := range reqs {
+		nextQuery, err := f.PathDB.GetNextQuery(ctx, req.Src, req.Dst, nil)
+		if err != nil {
+			return 0, serrors.Wrap(errDB, err)
+		}
+		if time.Until(nextQuery) <= leadTime {
+			due = append(due, req)
+		}
+	}
+	if len(due) == 0 {
+		return 0, nil
+	}
+	// Requests that are always locally available are resolved from the DB.
+	_, fetchReqs, err := f.Resolver.Resolve(ctx, due, true)
+	if err != nil {
+		return 0, serrors.Wrap(errDB, err)
+	}
+	if len(fetchReqs) == 0 {
+		return 0, nil
+	}
+	_, err = f.Request(ctx, fetchReqs)
+	labels := metrics.RequestLabels{Result: metrics.OkSuccess}
+	if err != nil {
+		err = serrors.Wrap(errFetch, err)
+		labels.Result = ErrToMetricsLabel(err)
+	}
+	if f.Metrics != nil {
+		f.Metrics.Prefetches(labels).Add(float64(len(fetchReqs)))
+	}
+	return len(fetchReqs), err
+}
+
 func (f *Fetcher) Request(ctx context.Context, reqs Requests) (Segments, error) {
 	// Pass shorter context for requesting, such that we can reply even if a
 	// request hangs.
@@ -140,6 +180,17 @@ func (f *Fetcher) waitOnProcessed(ctx context.Context,
 	return segs, nil
 }
 
+func (f *Fetcher) incLookups(hit bool) {
+	if f.Metrics == nil {
+		return
+	}
+	labels := metrics.RequestLabels{Result: metrics.Miss}
+	if hit {
+		labels.Result = metrics.Hit
+	}
+	f.Metrics.Lookups(labels).Inc()
+}
+
 // nextQuery decides the next time a query should be issued based on the
 // received segments.
 func (f *Fetcher) nextQuery(segs Segments) time.Time {
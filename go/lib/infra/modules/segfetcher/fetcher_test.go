@@ -104,3 +104,69 @@ func TestFetcher(t *testing.T) {
 		})
 	}
 }
+
+func TestFetcherPrefetch(t *testing.T) {
+	testErr := errors.New("Test err")
+	req := segfetcher.Request{SegType: Down, Src: core_130, Dst: non_core_111}
+
+	tests := map[string]struct {
+		PrepareFetcher func(*TestableFetcher)
+		ErrorAssertion require.ErrorAssertionFunc
+		ExpectedCount  int
+	}{
+		"DB error": {
+			PrepareFetcher: func(f *TestableFetcher) {
+				f.PathDB.EXPECT().GetNextQuery(gomock.Any(), core_130, non_core_111, nil).
+					Return(time.Time{}, testErr)
+			},
+			ErrorAssertion: require.Error,
+		},
+		"Not due": {
+			PrepareFetcher: func(f *TestableFetcher) {
+				f.PathDB.EXPECT().GetNextQuery(gomock.Any(), core_130, non_core_111, nil).
+					Return(time.Now().Add(time.Hour), nil)
+			},
+			ErrorAssertion: require.NoError,
+		},
+		"Due": {
+			PrepareFetcher: func(f *TestableFetcher) {
+				f.PathDB.EXPECT().GetNextQuery(gomock.Any(), core_130, non_core_111, nil).
+					Return(time.Now().Add(time.Second), nil)
+				f.Resolver.EXPECT().Resolve(gomock.Any(), segfetcher.Requests{req}, true).
+					Return(segfetcher.Segments{}, segfetcher.Requests{req}, nil)
+				f.Requester.EXPECT().Request(gomock.Any(), segfetcher.Requests{req}).
+					DoAndReturn(func(_ context.Context,
+						_ segfetcher.Requests) <-chan segfetcher.ReplyOrErr {
+
+						replies := make(chan segfetcher.ReplyOrErr)
+						close(replies)
+						return replies
+					})
+			},
+			ErrorAssertion: require.NoError,
+			ExpectedCount:  1,
+		},
+		"Due but resolved locally": {
+			PrepareFetcher: func(f *TestableFetcher) {
+				f.PathDB.EXPECT().GetNextQuery(gomock.Any(), core_130, non_core_111, nil).
+					Return(time.Time{}, nil)
+				f.Resolver.EXPECT().Resolve(gomock.Any(), segfetcher.Requests{req}, true).
+					Return(segfetcher.Segments{}, segfetcher.Requests{}, nil)
+			},
+			ErrorAssertion: require.NoError,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
+			defer cancelF()
+			f := NewTestFetcher(ctrl)
+			test.PrepareFetcher(f)
+			n, err := f.Fetcher().Prefetch(ctx, segfetcher.Requests{req}, time.Minute)
+			test.ErrorAssertion(t, err)
+			assert.Equal(t, test.ExpectedCount, n)
+		})
+	}
+}
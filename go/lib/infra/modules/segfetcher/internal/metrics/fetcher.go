@@ -68,12 +68,16 @@ func (l RevocationLabels) WithResult(result string) RevocationLabels {
 // Fetcher exposes all metrics for the fetcher.
 type Fetcher interface {
 	SegRequests(labels RequestLabels) prometheus.Counter
+	Lookups(labels RequestLabels) prometheus.Counter
+	Prefetches(labels RequestLabels) prometheus.Counter
 	RevocationsReceived(labels RevocationLabels) prometheus.Counter
 	UpdateRevocation(stored int, dbErrs int, verifyErrs int)
 }
 
 type fetcher struct {
 	segRequest  *prometheus.CounterVec
+	lookups     *prometheus.CounterVec
+	prefetches  *prometheus.CounterVec
 	revocations *prometheus.CounterVec
 }
 
@@ -83,6 +87,13 @@ func NewFetcher(namespace string) Fetcher {
 	return fetcher{
 		segRequest: prom.NewCounterVecWithLabels(namespace, sub, "seg_requests_total",
 			"The number of segment request sent.", RequestLabels{Result: OkSuccess}),
+		lookups: prom.NewCounterVecWithLabels(namespace, sub, "lookups_total",
+			"The number of segment lookups. The result indicates whether the lookup was "+
+				"answered from the local cache (hit) or required fetching (miss).",
+			RequestLabels{Result: Hit}),
+		prefetches: prom.NewCounterVecWithLabels(namespace, sub, "prefetch_requests_total",
+			"The number of segment requests sent by the prefetcher.",
+			RequestLabels{Result: OkSuccess}),
 		revocations: prom.NewCounterVecWithLabels(namespace, "", "received_revocations_total",
 			"The amount of revocations received.",
 			RevocationLabels{Result: OkSuccess, Src: revSrcPathReply}),
@@ -93,6 +104,14 @@ func (f fetcher) SegRequests(l RequestLabels) prometheus.Counter {
 	return f.segRequest.WithLabelValues(l.Values()...)
 }
 
+func (f fetcher) Lookups(l RequestLabels) prometheus.Counter {
+	return f.lookups.WithLabelValues(l.Values()...)
+}
+
+func (f fetcher) Prefetches(l RequestLabels) prometheus.Counter {
+	return f.prefetches.WithLabelValues(l.Values()...)
+}
+
 func (f fetcher) RevocationsReceived(l RevocationLabels) prometheus.Counter {
 	l.Src = revSrcPathReply
 	return f.revocations.WithLabelValues(l.Values()...)
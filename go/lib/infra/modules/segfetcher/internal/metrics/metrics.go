@@ -32,4 +32,8 @@ const (
 	ErrVerify = prom.ErrVerify
 	// OkSuccess is no error.
 	OkSuccess = prom.Success
+	// Hit indicates that a lookup was answered from the local cache.
+	Hit = "hit"
+	// Miss indicates that a lookup required fetching segments remotely.
+	Miss = "miss"
 )
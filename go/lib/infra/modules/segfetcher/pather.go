@@ -92,9 +92,25 @@ func (p *Pather) GetPaths(ctx context.Context, dst addr.IA,
 	return p.translatePaths(paths)
 }
 
+// Prefetch refreshes the segments that are required to build paths to the
+// destination, if their next query time is within the lead time. It returns
+// the number of segment requests that were sent.
+func (p *Pather) Prefetch(ctx context.Context, dst addr.IA,
+	leadTime time.Duration) (int, error) {
+
+	if dst.Equal(p.TopoProvider.Get().IA()) {
+		return 0, nil
+	}
+	reqs, err := p.Splitter.Split(ctx, dst)
+	if err != nil {
+		return 0, err
+	}
+	return p.Fetcher.Prefetch(ctx, reqs, leadTime)
+}
+
 func (p *Pather) buildAllPaths(src, dst addr.IA, segs Segments) []combinator.Path {
 	up, core, down := categorizeSegs(segs)
-	destinations := p.findDestinations(dst, up, core)
+	destinations := findDestinations(src, dst, up, core)
 	var paths []combinator.Path
 	for dst := range destinations {
 		paths = append(paths, combinator.Combine(src, dst, up, core, down, false)...)
@@ -110,12 +126,14 @@ func (p *Pather) buildAllPaths(src, dst addr.IA, segs Segments) []combinator.Pat
 	return validPaths
 }
 
-func (p *Pather) findDestinations(dst addr.IA, ups, cores seg.Segments) map[addr.IA]struct{} {
+// findDestinations returns the destination ASes of the paths. For wildcard
+// destinations, these are the core ASes that can be reached with the segments.
+func findDestinations(src, dst addr.IA, ups, cores seg.Segments) map[addr.IA]struct{} {
 	if !dst.IsWildcard() {
 		return map[addr.IA]struct{}{dst: {}}
 	}
 	all := cores.FirstIAs()
-	if dst.I == p.TopoProvider.Get().IA().I {
+	if dst.I == src.I {
 		// for isd local wildcard we want to reach cores, they are at the end of the up segs.
 		all = append(all, ups.FirstIAs()...)
 	}
@@ -133,21 +151,11 @@ func (p *Pather) filterRevoked(ctx context.Context,
 	var newPaths []combinator.Path
 	revokedInterfaces := make(map[snet.PathInterface]struct{})
 	for _, path := range paths {
-		revoked := false
-		for _, iface := range path.Metadata.Interfaces {
-			// cache automatically expires outdated revocations every second,
-			// so a cache hit implies revocation is still active.
-			revs, err := p.RevCache.Get(ctx, revcache.SingleKey(iface.IA, iface.ID))
-			if err != nil {
-				logger.Error("Failed to get revocation", "err", err)
-				// continue, the client might still get some usable paths like this.
-			}
-			if len(revs) > 0 {
-				revokedInterfaces[snet.PathInterface{IA: iface.IA, ID: iface.ID}] = struct{}{}
-			}
-			revoked = revoked || len(revs) > 0
+		revoked := revokedIntfs(ctx, p.RevCache, path.Metadata.Interfaces)
+		for _, iface := range revoked {
+			revokedInterfaces[iface] = struct{}{}
 		}
-		if !revoked {
+		if len(revoked) == 0 {
 			newPaths = append(newPaths, path)
 		}
 	}
@@ -159,6 +167,27 @@ func (p *Pather) filterRevoked(ctx context.Context,
 	return newPaths
 }
 
+// revokedIntfs returns the interfaces that are revoked according to the
+// revocation cache.
+func revokedIntfs(ctx context.Context, revCache revcache.RevCache,
+	intfs []snet.PathInterface) []snet.PathInterface {
+
+	var revoked []snet.PathInterface
+	for _, iface := range intfs {
+		// cache automatically expires outdated revocations every second,
+		// so a cache hit implies revocation is still active.
+		revs, err := revCache.Get(ctx, revcache.SingleKey(iface.IA, iface.ID))
+		if err != nil {
+			log.FromCtx(ctx).Error("Failed to get revocation", "err", err)
+			// continue, the client might still get some usable paths like this.
+		}
+		if len(revs) > 0 {
+			revoked = append(revoked, snet.PathInterface{IA: iface.IA, ID: iface.ID})
+		}
+	}
+	return revoked
+}
+
 // revocationsString pretty-prints the revocations map to a string.
 func revocationsString(revocations map[snet.PathInterface]struct{}) string {
 	r := make([]string, 0, len(revocations))
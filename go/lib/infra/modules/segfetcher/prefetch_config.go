@@ -0,0 +1,135 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segfetcher
+
+import (
+	"io"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/config"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/util"
+)
+
+const (
+	// DefaultPrefetchInterval is the default interval between prefetcher runs.
+	DefaultPrefetchInterval = time.Minute
+	// DefaultPrefetchLeadTime is the default time before the next query time
+	// at which segments are refreshed.
+	DefaultPrefetchLeadTime = time.Minute
+	// DefaultPrefetchTopN is the default number of most frequently looked up
+	// destinations that are refreshed.
+	DefaultPrefetchTopN = 10
+)
+
+var _ config.Config = (*PrefetchConfig)(nil)
+
+// PrefetchConfig is the configuration of the segment prefetcher.
+type PrefetchConfig struct {
+	// Enabled enables the segment prefetcher.
+	Enabled bool `toml:"enabled,omitempty"`
+	// Interval is the interval between prefetcher runs.
+	Interval util.DurWrap `toml:"interval,omitempty"`
+	// LeadTime is the time before the next query time of a segment request
+	// at which the segments are refreshed.
+	LeadTime util.DurWrap `toml:"lead_time,omitempty"`
+	// TopN is the number of most frequently looked up destinations that are
+	// refreshed. If it is zero, only the configured destinations are
+	// refreshed. If it is not set, DefaultPrefetchTopN is used.
+	TopN *int `toml:"top_n,omitempty"`
+	// Destinations are refreshed at startup and in every run, independent of
+	// their lookup frequency.
+	Destinations []string `toml:"destinations,omitempty"`
+}
+
+// InitDefaults initializes the default values.
+func (cfg *PrefetchConfig) InitDefaults() {
+	if cfg.Interval.Duration == 0 {
+		cfg.Interval.Duration = DefaultPrefetchInterval
+	}
+	if cfg.LeadTime.Duration == 0 {
+		cfg.LeadTime.Duration = DefaultPrefetchLeadTime
+	}
+	if cfg.TopN == nil {
+		topN := DefaultPrefetchTopN
+		cfg.TopN = &topN
+	}
+}
+
+// Validate validates that the durations are positive, and that the
+// destinations are valid.
+func (cfg *PrefetchConfig) Validate() error {
+	if cfg.Interval.Duration <= 0 {
+		return serrors.New("interval must be positive", "interval", cfg.Interval)
+	}
+	if cfg.LeadTime.Duration < 0 {
+		return serrors.New("lead_time must not be negative", "lead_time", cfg.LeadTime)
+	}
+	if cfg.TopN != nil && *cfg.TopN < 0 {
+		return serrors.New("top_n must not be negative", "top_n", *cfg.TopN)
+	}
+	_, err := cfg.DestinationIAs()
+	return err
+}
+
+// DestinationIAs returns the parsed destinations.
+func (cfg *PrefetchConfig) DestinationIAs() ([]addr.IA, error) {
+	dsts := make([]addr.IA, 0, len(cfg.Destinations))
+	for _, raw := range cfg.Destinations {
+		dst, err := addr.IAFromString(raw)
+		if err != nil {
+			return nil, serrors.WrapStr("parsing destination", err, "destination", raw)
+		}
+		if dst.IsWildcard() {
+			return nil, serrors.New("destination must not be a wildcard", "destination", raw)
+		}
+		dsts = append(dsts, dst)
+	}
+	return dsts, nil
+}
+
+// Sample writes a config sample to the writer.
+func (cfg *PrefetchConfig) Sample(dst io.Writer, _ config.Path, _ config.CtxMap) {
+	config.WriteString(dst, prefetchSample)
+}
+
+// ConfigName is the key in the toml file.
+func (cfg *PrefetchConfig) ConfigName() string {
+	return "prefetch"
+}
+
+const prefetchSample = `
+# Enable the segment prefetcher. The prefetcher refreshes the segments towards
+# the most frequently looked up destinations and the configured destinations
+# before they have to be refetched, such that lookups can be answered from the
+# local cache. (default false)
+enabled = false
+
+# The interval between prefetcher runs. (default 1m)
+interval = "1m"
+
+# The time before the next query time of a segment request at which the
+# segments are refreshed. (default 1m)
+lead_time = "1m"
+
+# The number of most frequently looked up destinations that are refreshed. If
+# it is 0, only the configured destinations are refreshed. (default 10)
+top_n = 10
+
+# The destination ISD-ASes whose segments are fetched at startup and refreshed
+# in every run, e.g., ["1-ff00:0:110"]. (default [])
+destinations = []
+`
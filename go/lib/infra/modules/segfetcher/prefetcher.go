@@ -0,0 +1,152 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segfetcher
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/periodic"
+)
+
+const (
+	// maxTrackedDsts is the maximum number of destinations for which the
+	// lookup frequency is tracked.
+	maxTrackedDsts = 10000
+	// minTrackedCount is the decayed lookup count below which a destination is
+	// no longer tracked.
+	minTrackedCount = 0.125
+)
+
+// DstPrefetcher refreshes the segments towards a destination.
+type DstPrefetcher interface {
+	// Prefetch refreshes the segments that are required to build paths to the
+	// destination, if their next query time is within the lead time. It
+	// returns the number of segment requests that were sent.
+	Prefetch(ctx context.Context, dst addr.IA, leadTime time.Duration) (int, error)
+}
+
+var _ periodic.Task = (*Prefetcher)(nil)
+
+// Prefetcher is a periodic task that keeps the segments towards popular
+// destinations fresh, such that lookups do not have to wait for the segments
+// to be fetched. It tracks the lookup frequency per destination ISD-AS and, in
+// every run, refreshes the segments of the most frequently looked up
+// destinations and of the configured destinations. The lookup counts are
+// halved in every run, such that the popularity follows recent lookups.
+type Prefetcher struct {
+	// Target refreshes the segments towards a destination.
+	Target DstPrefetcher
+	// TopN is the number of most frequently looked up destinations that are
+	// refreshed in every run.
+	TopN int
+	// LeadTime is the time before the next query time of a segment request
+	// at which the segments are refreshed.
+	LeadTime time.Duration
+	// Destinations are refreshed in every run, independent of their lookup
+	// frequency.
+	Destinations []addr.IA
+	// TaskName is the name of the task.
+	TaskName string
+
+	mu     sync.Mutex
+	counts map[addr.IA]float64
+}
+
+// Name returns the task name.
+func (p *Prefetcher) Name() string {
+	return p.TaskName
+}
+
+// Record records a lookup for the destination. Wildcard destinations are not
+// tracked. It is safe to call Record on a nil prefetcher.
+func (p *Prefetcher) Record(dst addr.IA) {
+	if p == nil || dst.IsZero() || dst.IsWildcard() {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.counts == nil {
+		p.counts = make(map[addr.IA]float64)
+	}
+	if _, ok := p.counts[dst]; !ok && len(p.counts) >= maxTrackedDsts {
+		return
+	}
+	p.counts[dst]++
+}
+
+// Run refreshes the segments towards the configured and the most popular
+// destinations.
+func (p *Prefetcher) Run(ctx context.Context) {
+	logger := log.FromCtx(ctx)
+	var total int
+	dsts := p.dsts()
+	for _, dst := range dsts {
+		if ctx.Err() != nil {
+			break
+		}
+		n, err := p.Target.Prefetch(ctx, dst, p.LeadTime)
+		total += n
+		if err != nil {
+			logger.Debug("Failed to prefetch segments", "dst", dst, "err", err)
+		}
+	}
+	if total > 0 {
+		logger.Debug("Prefetched segments", "destinations", len(dsts), "requests", total)
+	}
+}
+
+// dsts returns the configured destinations and the TopN most frequently
+// looked up destinations, and decays the lookup counts.
+func (p *Prefetcher) dsts() []addr.IA {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	popular := make([]addr.IA, 0, len(p.counts))
+	for dst := range p.counts {
+		popular = append(popular, dst)
+	}
+	sort.Slice(popular, func(i, j int) bool {
+		ci, cj := p.counts[popular[i]], p.counts[popular[j]]
+		if ci != cj {
+			return ci > cj
+		}
+		return popular[i].IAInt() < popular[j].IAInt()
+	})
+	if len(popular) > p.TopN {
+		popular = popular[:p.TopN]
+	}
+	for dst, c := range p.counts {
+		if c /= 2; c < minTrackedCount {
+			delete(p.counts, dst)
+		} else {
+			p.counts[dst] = c
+		}
+	}
+
+	seen := make(map[addr.IA]struct{}, len(p.Destinations)+len(popular))
+	dsts := make([]addr.IA, 0, len(p.Destinations)+len(popular))
+	for _, dst := range append(append([]addr.IA(nil), p.Destinations...), popular...) {
+		if _, ok := seen[dst]; ok {
+			continue
+		}
+		seen[dst] = struct{}{}
+		dsts = append(dsts, dst)
+	}
+	return dsts
+}
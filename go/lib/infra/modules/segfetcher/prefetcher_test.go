@@ -0,0 +1,181 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segfetcher_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pelletier/go-toml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+type recordingTarget struct {
+	dsts []addr.IA
+}
+
+func (r *recordingTarget) Prefetch(_ context.Context, dst addr.IA,
+	_ time.Duration) (int, error) {
+
+	r.dsts = append(r.dsts, dst)
+	return 1, nil
+}
+
+func TestPrefetcherRun(t *testing.T) {
+	ia110 := xtest.MustParseIA("1-ff00:0:110")
+	ia111 := xtest.MustParseIA("1-ff00:0:111")
+	ia112 := xtest.MustParseIA("1-ff00:0:112")
+	ia120 := xtest.MustParseIA("1-ff00:0:120")
+
+	t.Run("top N and configured destinations", func(t *testing.T) {
+		target := &recordingTarget{}
+		p := &segfetcher.Prefetcher{
+			Target:       target,
+			TopN:         2,
+			Destinations: []addr.IA{ia120, ia111},
+		}
+		for i := 0; i < 3; i++ {
+			p.Record(ia111)
+			p.Record(ia112)
+		}
+		p.Record(ia110)
+		p.Record(xtest.MustParseIA("1-0"))
+		p.Record(addr.IA{})
+
+		p.Run(context.Background())
+		assert.Equal(t, []addr.IA{ia120, ia111, ia112}, target.dsts)
+	})
+	t.Run("counts decay", func(t *testing.T) {
+		target := &recordingTarget{}
+		p := &segfetcher.Prefetcher{Target: target, TopN: 1}
+		for i := 0; i < 4; i++ {
+			p.Record(ia111)
+		}
+		p.Record(ia112)
+		p.Run(context.Background())
+		require.Equal(t, []addr.IA{ia111}, target.dsts)
+
+		// ia111 decays to 2, ia112 to 0.5. Three lookups make ia112 more
+		// popular.
+		for i := 0; i < 3; i++ {
+			p.Record(ia112)
+		}
+		target.dsts = nil
+		p.Run(context.Background())
+		assert.Equal(t, []addr.IA{ia112}, target.dsts)
+
+		// Without lookups, the counts eventually decay to zero.
+		for i := 0; i < 10; i++ {
+			p.Run(context.Background())
+		}
+		target.dsts = nil
+		p.Run(context.Background())
+		assert.Empty(t, target.dsts)
+	})
+	t.Run("only configured destinations", func(t *testing.T) {
+		target := &recordingTarget{}
+		p := &segfetcher.Prefetcher{Target: target, Destinations: []addr.IA{ia120}}
+		p.Record(ia111)
+		p.Run(context.Background())
+		assert.Equal(t, []addr.IA{ia120}, target.dsts)
+	})
+	t.Run("nil prefetcher", func(t *testing.T) {
+		var p *segfetcher.Prefetcher
+		assert.NotPanics(t, func() { p.Record(ia111) })
+	})
+}
+
+func TestPrefetchConfigTopN(t *testing.T) {
+	testCases := map[string]struct {
+		Raw  string
+		TopN int
+	}{
+		"default":  {Raw: "enabled = true", TopN: segfetcher.DefaultPrefetchTopN},
+		"disabled": {Raw: "top_n = 0", TopN: 0},
+		"set":      {Raw: "top_n = 3", TopN: 3},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			var cfg segfetcher.PrefetchConfig
+			require.NoError(t, toml.Unmarshal([]byte(tc.Raw), &cfg))
+			cfg.InitDefaults()
+			require.NoError(t, cfg.Validate())
+			assert.Equal(t, tc.TopN, *cfg.TopN)
+		})
+	}
+}
+
+func TestPrefetchConfigValidate(t *testing.T) {
+	tests := map[string]struct {
+		Modify    func(cfg *segfetcher.PrefetchConfig)
+		Assertion assert.ErrorAssertionFunc
+	}{
+		"default": {
+			Modify:    func(*segfetcher.PrefetchConfig) {},
+			Assertion: assert.NoError,
+		},
+		"destinations": {
+			Modify: func(cfg *segfetcher.PrefetchConfig) {
+				cfg.Destinations = []string{"1-ff00:0:110", "2-ff00:0:210"}
+			},
+			Assertion: assert.NoError,
+		},
+		"invalid destination": {
+			Modify: func(cfg *segfetcher.PrefetchConfig) {
+				cfg.Destinations = []string{"garbage"}
+			},
+			Assertion: assert.Error,
+		},
+		"wildcard destination": {
+			Modify: func(cfg *segfetcher.PrefetchConfig) {
+				cfg.Destinations = []string{"1-0"}
+			},
+			Assertion: assert.Error,
+		},
+		"negative interval": {
+			Modify: func(cfg *segfetcher.PrefetchConfig) {
+				cfg.Interval.Duration = -time.Second
+			},
+			Assertion: assert.Error,
+		},
+		"zero top_n": {
+			Modify: func(cfg *segfetcher.PrefetchConfig) {
+				*cfg.TopN = 0
+			},
+			Assertion: assert.NoError,
+		},
+		"negative top_n": {
+			Modify: func(cfg *segfetcher.PrefetchConfig) {
+				*cfg.TopN = -1
+			},
+			Assertion: assert.Error,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var cfg segfetcher.PrefetchConfig
+			cfg.InitDefaults()
+			tc.Modify(&cfg)
+			tc.Assertion(t, cfg.Validate())
+		})
+	}
+}
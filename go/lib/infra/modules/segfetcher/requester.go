@@ -23,6 +23,7 @@ import (
 	"github.com/opentracing/opentracing-go"
 
 	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/infra/modules/upstream"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/serrors"
 )
@@ -58,6 +59,10 @@ type DefaultRequester struct {
 	RPC         RPC
 	DstProvider DstProvider
 	MaxRetries  int
+	// Balancer spreads the requests across the instances of the remote
+	// control service. If it is nil, the requests are sent to the destination
+	// returned by the DstProvider.
+	Balancer *upstream.Balancer
 }
 
 // Request all requests in the request set
@@ -112,11 +117,15 @@ func (r *DefaultRequester) requestWorker(ctx context.Context, reqs Requests, i i
 		if err != nil {
 			return nil, nil, err
 		}
-		segs, err := r.RPC.Segments(ctx, req, dst)
+		reply, peer, err := r.Balancer.Do(ctx, dst,
+			func(ctx context.Context, server net.Addr) (interface{}, error) {
+				return r.RPC.Segments(ctx, req, server)
+			},
+		)
 		if err != nil {
-			return nil, dst, err
+			return nil, peer, err
 		}
-		return segs, dst, nil
+		return reply.([]*seg.Meta), peer, nil
 	}
 	for tryIndex := 0; ctx.Err() == nil && tryIndex < r.MaxRetries+1; tryIndex++ {
 		segs, peer, err := try(ctx)
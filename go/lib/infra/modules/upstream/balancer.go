@@ -0,0 +1,296 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package upstream spreads requests to a remote service across all healthy
+// instances of the service.
+//
+// The instances are discovered with SVC resolution. Instances that fail
+// repeatedly, i.e., that cannot be reached or do not reply in time, are
+// avoided for a backoff period. If an instance does not reply within the hedge
+// delay, the request is additionally sent to the next instance, and the first
+// successful reply is used.
+package upstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/metrics"
+	"github.com/scionproto/scion/go/lib/prom"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+)
+
+const (
+	// DefaultHedgeDelay is the default time after which a request is
+	// additionally sent to the next instance.
+	DefaultHedgeDelay = time.Second
+	// DefaultFailureThreshold is the default number of consecutive failures
+	// after which an instance is considered unhealthy.
+	DefaultFailureThreshold = 3
+	// DefaultBackoff is the default time an unhealthy instance is avoided.
+	DefaultBackoff = 30 * time.Second
+)
+
+// Resolver resolves an SVC address to the addresses of all healthy instances
+// of the service.
+type Resolver interface {
+	ResolveInstances(ctx context.Context, address *snet.SVCAddr) ([]*snet.UDPAddr, error)
+}
+
+// Call sends a request to the server and returns the reply.
+type Call func(ctx context.Context, server net.Addr) (interface{}, error)
+
+// Balancer spreads requests to SVC addresses across the healthy instances of
+// the service. A nil balancer sends the requests to the SVC address directly.
+type Balancer struct {
+	// Resolver resolves the SVC address to the instances.
+	Resolver Resolver
+	// HedgeDelay is the time after which a request is additionally sent to
+	// the next instance, if no reply has been received yet. If it is zero,
+	// DefaultHedgeDelay is used. If it is negative, requests are not hedged
+	// and the next instance is only tried after a failure.
+	HedgeDelay time.Duration
+	// FailureThreshold is the number of consecutive failures after which an
+	// instance is considered unhealthy. If it is zero,
+	// DefaultFailureThreshold is used.
+	FailureThreshold int
+	// Backoff is the time an unhealthy instance is avoided. If it is zero,
+	// DefaultBackoff is used.
+	Backoff time.Duration
+	// Requests counts the requests per upstream instance. The labels are
+	// "upstream" and "result".
+	Requests metrics.Counter
+	// Hedges counts the hedged requests per upstream instance. The label is
+	// "upstream".
+	Hedges metrics.Counter
+
+	mu     sync.Mutex
+	states map[string]*state
+	next   int
+}
+
+type state struct {
+	failures int
+	retryAt  time.Time
+}
+
+type result struct {
+	upstream string
+	server   net.Addr
+	reply    interface{}
+	err      error
+}
+
+// Do sends the request to the service at dst. If dst is an SVC address, the
+// request is sent to the healthy instances of the service in round robin
+// order, and hedged or retried on the next instance. Otherwise, or if the
+// instances cannot be resolved, the request is sent to dst directly. Do
+// returns the reply and the address of the server that sent it.
+func (b *Balancer) Do(ctx context.Context, dst net.Addr, call Call) (interface{}, net.Addr,
+	error) {
+
+	svcAddr, ok := dst.(*snet.SVCAddr)
+	if b == nil || b.Resolver == nil || !ok {
+		reply, err := call(ctx, dst)
+		return reply, dst, err
+	}
+	instances, err := b.Resolver.ResolveInstances(ctx, svcAddr)
+	if err != nil || len(instances) == 0 {
+		log.FromCtx(ctx).Debug("Resolving instances failed, using SVC address",
+			"svc", svcAddr, "err", err)
+		reply, err := call(ctx, dst)
+		return reply, dst, err
+	}
+	return b.do(ctx, b.order(instances, time.Now()), call)
+}
+
+func (b *Balancer) do(ctx context.Context, instances []*snet.UDPAddr, call Call) (interface{},
+	net.Addr, error) {
+
+	ctx, cancelF := context.WithCancel(ctx)
+	defer cancelF()
+
+	results := make(chan result, len(instances))
+	launch := func(server *snet.UDPAddr) {
+		go func() {
+			defer log.HandlePanic()
+			reply, err := call(ctx, server)
+			results <- result{
+				upstream: upstreamKey(server),
+				server:   server,
+				reply:    reply,
+				err:      err,
+			}
+		}()
+	}
+
+	var hedge <-chan time.Time
+	hedgeDelay := b.hedgeDelay()
+	resetHedge := func() {
+		if hedgeDelay > 0 {
+			hedge = time.After(hedgeDelay)
+		}
+	}
+
+	launch(instances[0])
+	resetHedge()
+	next, pending := 1, 1
+	var errs serrors.List
+	var lastServer net.Addr
+	for pending > 0 {
+		select {
+		case res := <-results:
+			pending--
+			b.record(res.upstream, res.err, time.Now())
+			if res.err == nil {
+				return res.reply, res.server, nil
+			}
+			errs = append(errs, serrors.WithCtx(res.err, "upstream", res.upstream))
+			lastServer = res.server
+			if next < len(instances) && ctx.Err() == nil {
+				launch(instances[next])
+				next, pending = next+1, pending+1
+				resetHedge()
+			}
+		case <-hedge:
+			hedge = nil
+			if next < len(instances) {
+				metrics.CounterInc(metrics.CounterWith(b.Hedges,
+					"upstream", upstreamKey(instances[next])))
+				launch(instances[next])
+				next, pending = next+1, pending+1
+				resetHedge()
+			}
+		}
+	}
+	return nil, lastServer, errs.ToError()
+}
+
+// order returns the instances in round robin order. Unhealthy instances are
+// moved to the end.
+func (b *Balancer) order(instances []*snet.UDPAddr, now time.Time) []*snet.UDPAddr {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	start := b.next % len(instances)
+	b.next++
+
+	healthy := make([]*snet.UDPAddr, 0, len(instances))
+	var unhealthy []*snet.UDPAddr
+	for i := range instances {
+		instance := instances[(start+i)%len(instances)]
+		s, ok := b.states[upstreamKey(instance)]
+		if ok && s.failures >= b.failureThreshold() && now.Before(s.retryAt) {
+			unhealthy = append(unhealthy, instance)
+			continue
+		}
+		healthy = append(healthy, instance)
+	}
+	return append(healthy, unhealthy...)
+}
+
+// record updates the health state of the upstream instance and the metrics.
+func (b *Balancer) record(upstream string, err error, now time.Time) {
+	metrics.CounterInc(metrics.CounterWith(b.Requests,
+		"upstream", upstream, prom.LabelResult, resultLabel(err)))
+	// Only transport failures and timeouts say something about the instance
+	// health. In particular, requests that are canceled by the caller, or
+	// because another instance replied first, and error replies of the
+	// instance are not counted.
+	if err != nil && !isTransportFailure(err) {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		delete(b.states, upstream)
+		return
+	}
+	if b.states == nil {
+		b.states = make(map[string]*state)
+	}
+	s, ok := b.states[upstream]
+	if !ok {
+		s = &state{}
+		b.states[upstream] = s
+	}
+	s.failures++
+	if s.failures >= b.failureThreshold() {
+		s.retryAt = now.Add(b.backoff())
+	}
+}
+
+func (b *Balancer) hedgeDelay() time.Duration {
+	if b.HedgeDelay == 0 {
+		return DefaultHedgeDelay
+	}
+	return b.HedgeDelay
+}
+
+func (b *Balancer) failureThreshold() int {
+	if b.FailureThreshold == 0 {
+		return DefaultFailureThreshold
+	}
+	return b.FailureThreshold
+}
+
+func (b *Balancer) backoff() time.Duration {
+	if b.Backoff == 0 {
+		return DefaultBackoff
+	}
+	return b.Backoff
+}
+
+func upstreamKey(a *snet.UDPAddr) string {
+	return fmt.Sprintf("%s,%s", a.IA, a.Host)
+}
+
+// isTransportFailure indicates whether the request failed because the instance
+// could not be reached or did not reply in time.
+func isTransportFailure(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		switch grpcErr.GRPCStatus().Code() {
+		case codes.Unavailable, codes.DeadlineExceeded:
+			return true
+		}
+	}
+	return false
+}
+
+func resultLabel(err error) string {
+	switch {
+	case err == nil:
+		return prom.Success
+	case errors.Is(err, context.DeadlineExceeded):
+		return prom.ErrTimeout
+	default:
+		return prom.ErrNetwork
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upstream_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/infra/modules/upstream"
+	"github.com/scionproto/scion/go/lib/metrics"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+type fakeResolver struct {
+	instances []*snet.UDPAddr
+	err       error
+}
+
+func (r fakeResolver) ResolveInstances(context.Context,
+	*snet.SVCAddr) ([]*snet.UDPAddr, error) {
+
+	return r.instances, r.err
+}
+
+func TestBalancerDo(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	svcAddr := &snet.SVCAddr{IA: ia, SVC: addr.SvcCS}
+	instanceA := &snet.UDPAddr{IA: ia, Host: &net.UDPAddr{IP: net.IP{10, 0, 0, 1}, Port: 1}}
+	instanceB := &snet.UDPAddr{IA: ia, Host: &net.UDPAddr{IP: net.IP{10, 0, 0, 2}, Port: 1}}
+	upstreamA, upstreamB := "1-ff00:0:110,10.0.0.1:1", "1-ff00:0:110,10.0.0.2:1"
+
+	echo := func(_ context.Context, server net.Addr) (interface{}, error) {
+		return server, nil
+	}
+
+	t.Run("nil balancer sends to dst", func(t *testing.T) {
+		var b *upstream.Balancer
+		reply, server, err := b.Do(context.Background(), svcAddr, echo)
+		require.NoError(t, err)
+		assert.Equal(t, svcAddr, reply)
+		assert.Equal(t, svcAddr, server)
+	})
+	t.Run("unicast address is not resolved", func(t *testing.T) {
+		b := &upstream.Balancer{Resolver: fakeResolver{err: serrors.New("unexpected")}}
+		reply, _, err := b.Do(context.Background(), instanceA, echo)
+		require.NoError(t, err)
+		assert.Equal(t, instanceA, reply)
+	})
+	t.Run("resolution error falls back to dst", func(t *testing.T) {
+		b := &upstream.Balancer{Resolver: fakeResolver{err: serrors.New("no reply")}}
+		reply, _, err := b.Do(context.Background(), svcAddr, echo)
+		require.NoError(t, err)
+		assert.Equal(t, svcAddr, reply)
+	})
+	t.Run("round robin", func(t *testing.T) {
+		requests := metrics.NewTestCounter()
+		b := &upstream.Balancer{
+			Resolver: fakeResolver{instances: []*snet.UDPAddr{instanceA, instanceB}},
+			Requests: requests,
+		}
+		var servers []net.Addr
+		for i := 0; i < 4; i++ {
+			_, server, err := b.Do(context.Background(), svcAddr, echo)
+			require.NoError(t, err)
+			servers = append(servers, server)
+		}
+		assert.Equal(t, []net.Addr{instanceA, instanceB, instanceA, instanceB}, servers)
+		assert.Equal(t, 2.0, metrics.CounterValue(
+			requests.With("upstream", upstreamA, "result", "ok_success")))
+		assert.Equal(t, 2.0, metrics.CounterValue(
+			requests.With("upstream", upstreamB, "result", "ok_success")))
+	})
+	t.Run("failed instance is retried on next and avoided", func(t *testing.T) {
+		var calls []net.Addr
+		failA := func(_ context.Context, server net.Addr) (interface{}, error) {
+			calls = append(calls, server)
+			if server == instanceA {
+				return nil, serrors.WrapStr("receiving reply",
+					status.Error(codes.Unavailable, "connection refused"))
+			}
+			return server, nil
+		}
+		b := &upstream.Balancer{
+			Resolver:         fakeResolver{instances: []*snet.UDPAddr{instanceA, instanceB}},
+			HedgeDelay:       -1,
+			FailureThreshold: 1,
+			Backoff:          time.Hour,
+		}
+		for i := 0; i < 3; i++ {
+			reply, _, err := b.Do(context.Background(), svcAddr, failA)
+			require.NoError(t, err)
+			assert.Equal(t, instanceB, reply)
+		}
+		// Instance A is only tried once, then it is unhealthy and moved to
+		// the end of the order.
+		assert.Equal(t, []net.Addr{instanceA, instanceB, instanceB, instanceB}, calls)
+	})
+	t.Run("error reply does not mark instance unhealthy", func(t *testing.T) {
+		var calls []net.Addr
+		errorA := func(_ context.Context, server net.Addr) (interface{}, error) {
+			calls = append(calls, server)
+			if server == instanceA {
+				return nil, status.Error(codes.NotFound, "no such chain")
+			}
+			return server, nil
+		}
+		b := &upstream.Balancer{
+			Resolver:         fakeResolver{instances: []*snet.UDPAddr{instanceA, instanceB}},
+			HedgeDelay:       -1,
+			FailureThreshold: 1,
+			Backoff:          time.Hour,
+		}
+		for i := 0; i < 3; i++ {
+			_, _, err := b.Do(context.Background(), svcAddr, errorA)
+			require.NoError(t, err)
+		}
+		// Instance A is not moved to the end of the order.
+		assert.Equal(t, []net.Addr{instanceA, instanceB, instanceB, instanceA, instanceB},
+			calls)
+	})
+	t.Run("all instances fail", func(t *testing.T) {
+		fail := func(context.Context, net.Addr) (interface{}, error) {
+			return nil, serrors.New("unavailable")
+		}
+		b := &upstream.Balancer{
+			Resolver:   fakeResolver{instances: []*snet.UDPAddr{instanceA, instanceB}},
+			HedgeDelay: -1,
+		}
+		_, _, err := b.Do(context.Background(), svcAddr, fail)
+		assert.Error(t, err)
+	})
+	t.Run("slow instance is hedged", func(t *testing.T) {
+		hedges := metrics.NewTestCounter()
+		slowA := func(ctx context.Context, server net.Addr) (interface{}, error) {
+			if server == instanceA {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return server, nil
+		}
+		b := &upstream.Balancer{
+			Resolver:   fakeResolver{instances: []*snet.UDPAddr{instanceA, instanceB}},
+			HedgeDelay: 10 * time.Millisecond,
+			Hedges:     hedges,
+		}
+		ctx, cancelF := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelF()
+		reply, server, err := b.Do(ctx, svcAddr, slowA)
+		require.NoError(t, err)
+		assert.Equal(t, instanceB, reply)
+		assert.Equal(t, instanceB, server)
+		assert.Equal(t, 1.0, metrics.CounterValue(hedges.With("upstream", upstreamB)))
+	})
+}
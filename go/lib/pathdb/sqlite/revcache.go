@@ -0,0 +1,180 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/infra/modules/db"
+	"github.com/scionproto/scion/go/lib/revcache"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+var _ revcache.RevCache = (*RevCache)(nil)
+
+// RevCache is a revocation cache that is persisted in the path database. It
+// shares the database connection with the path database backend it was
+// created from.
+type RevCache struct {
+	*executor
+}
+
+// RevCache returns a revocation cache that stores the revocations in the same
+// database as the path segments. Closing the revocation cache does not close
+// the database, the backend remains the owner of the database connection.
+func (b *Backend) RevCache() *RevCache {
+	return &RevCache{executor: b.executor}
+}
+
+// Get returns the non-expired revocations for the given keys.
+func (c *RevCache) Get(ctx context.Context, keys revcache.KeySet) (revcache.Revocations, error) {
+	c.RLock()
+	defer c.RUnlock()
+	query := `SELECT RawSignedRev FROM Revocations
+		WHERE IsdID=? AND AsID=? AND IntfID=? AND ExpirationTime>?`
+	now := time.Now().Unix()
+	revs := make(revcache.Revocations, len(keys))
+	for k := range keys {
+		var raw []byte
+		err := c.db.QueryRowContext(ctx, query, k.IA.I, k.IA.A, k.IfId, now).Scan(&raw)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, db.NewReadError("querying revocation", err, "key", k)
+		}
+		rev, err := path_mgmt.NewSignedRevInfoFromRaw(raw)
+		if err != nil {
+			return nil, db.NewDataError("parsing revocation", err, "key", k)
+		}
+		revs[k] = rev
+	}
+	return revs, nil
+}
+
+// GetAll returns all non-expired revocations in the cache.
+func (c *RevCache) GetAll(ctx context.Context) (revcache.ResultChan, error) {
+	c.RLock()
+	defer c.RUnlock()
+	query := `SELECT RawSignedRev FROM Revocations WHERE ExpirationTime>?`
+	rows, err := c.db.QueryContext(ctx, query, time.Now().Unix())
+	if err != nil {
+		return nil, db.NewReadError("querying revocations", err)
+	}
+	defer rows.Close()
+	var results []revcache.RevOrErr
+	for rows.Next() {
+		var raw []byte
+		if err := rows.Scan(&raw); err != nil {
+			return nil, db.NewReadError("scanning revocation", err)
+		}
+		rev, err := path_mgmt.NewSignedRevInfoFromRaw(raw)
+		if err != nil {
+			err = db.NewDataError("parsing revocation", err)
+		}
+		results = append(results, revcache.RevOrErr{Rev: rev, Err: err})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, db.NewReadError("iterating revocations", err)
+	}
+	resCh := make(chan revcache.RevOrErr, len(results))
+	for _, res := range results {
+		resCh <- res
+	}
+	close(resCh)
+	return resCh, nil
+}
+
+// Insert inserts the revocation if it is not expired and newer than the
+// revocation that is already stored for the same interface.
+func (c *RevCache) Insert(ctx context.Context, rev *path_mgmt.SignedRevInfo) (bool, error) {
+	c.Lock()
+	defer c.Unlock()
+	revInfo, err := rev.RevInfo()
+	if err != nil {
+		return false, serrors.WrapStr("parsing revocation", err)
+	}
+	if !revInfo.Expiration().After(time.Now()) {
+		return false, nil
+	}
+	raw, err := rev.Pack()
+	if err != nil {
+		return false, serrors.WrapStr("packing revocation", err)
+	}
+	var inserted bool
+	err = db.DoInTx(ctx, c.db, func(ctx context.Context, tx *sql.Tx) error {
+		newer, err := containsNewerRev(ctx, tx, revInfo.IA(), revInfo.IfID, revInfo.RawTimestamp)
+		if err != nil || newer {
+			return err
+		}
+		inserted = true
+		return insertRevocation(ctx, tx, revInfo.IA(), revInfo.IfID, revInfo.RawTimestamp,
+			revInfo.Expiration().Unix(), raw)
+	})
+	if err != nil {
+		return false, db.NewWriteError("inserting revocation", err)
+	}
+	return inserted, nil
+}
+
+// DeleteExpired deletes all expired revocations.
+func (c *RevCache) DeleteExpired(ctx context.Context) (int64, error) {
+	c.Lock()
+	defer c.Unlock()
+	deleted, err := db.DeleteInTx(ctx, c.db, func(tx *sql.Tx) (sql.Result, error) {
+		return tx.ExecContext(ctx, `DELETE FROM Revocations WHERE ExpirationTime<=?`,
+			time.Now().Unix())
+	})
+	return int64(deleted), err
+}
+
+// Close is a no-op, the database is closed by the path database backend.
+func (c *RevCache) Close() error { return nil }
+
+// SetMaxOpenConns is a no-op, the limits are set on the path database backend.
+func (c *RevCache) SetMaxOpenConns(_ int) {}
+
+// SetMaxIdleConns is a no-op, the limits are set on the path database backend.
+func (c *RevCache) SetMaxIdleConns(_ int) {}
+
+// containsNewerRev returns whether a revocation that is at least as recent as
+// the given timestamp is stored for the interface.
+func containsNewerRev(ctx context.Context, tx *sql.Tx, ia addr.IA, ifID common.IFIDType,
+	timestamp uint32) (bool, error) {
+
+	query := `SELECT 1 FROM Revocations
+		WHERE IsdID=? AND AsID=? AND IntfID=? AND IssuingTime>=?`
+	rows, err := tx.QueryContext(ctx, query, ia.I, ia.A, ifID, timestamp)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	return rows.Next(), rows.Err()
+}
+
+func insertRevocation(ctx context.Context, tx *sql.Tx, ia addr.IA, ifID common.IFIDType,
+	timestamp uint32, expiration int64, raw []byte) error {
+
+	query := `INSERT OR REPLACE INTO Revocations
+		(IsdID, AsID, IntfID, IssuingTime, ExpirationTime, RawSignedRev)
+		VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := tx.ExecContext(ctx, query, ia.I, ia.A, ifID, timestamp, expiration, raw)
+	return err
+}
@@ -0,0 +1,60 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/infra/modules/db"
+	"github.com/scionproto/scion/go/lib/revcache/revcachetest"
+)
+
+var _ revcachetest.TestableRevCache = (*testRevCache)(nil)
+
+type testRevCache struct {
+	*RevCache
+}
+
+func (c *testRevCache) InsertExpired(t *testing.T, ctx context.Context,
+	rev *path_mgmt.SignedRevInfo) {
+
+	revInfo, err := rev.RevInfo()
+	require.NoError(t, err)
+	raw, err := rev.Pack()
+	require.NoError(t, err)
+	err = db.DoInTx(ctx, c.db, func(ctx context.Context, tx *sql.Tx) error {
+		return insertRevocation(ctx, tx, revInfo.IA(), revInfo.IfID, revInfo.RawTimestamp,
+			revInfo.Expiration().Unix(), raw)
+	})
+	require.NoError(t, err)
+}
+
+func (c *testRevCache) Prepare(t *testing.T, _ context.Context) {
+	b, err := New("file::memory:")
+	require.NoError(t, err)
+	c.RevCache = b.RevCache()
+}
+
+func TestRevCacheSuite(t *testing.T) {
+	Convey("RevCache Suite", t, func() {
+		revcachetest.TestRevCache(t, &testRevCache{})
+	})
+}
@@ -17,13 +17,19 @@
 
 package sqlite
 
+import (
+	"github.com/scionproto/scion/go/lib/infra/modules/db"
+)
+
 const (
 	// SchemaVersion is the version of the SQLite schema understood by this backend.
 	// Whenever changes to the schema are made, this version number should be increased
 	// to prevent data corruption between incompatible database schemas.
-	SchemaVersion = 8
+	SchemaVersion = 9
 	// Schema is the SQLite database layout.
-	Schema = `CREATE TABLE Segments(
+	Schema = schemaV8 + revocationsSchema
+	// schemaV8 is the layout of schema version 8.
+	schemaV8 = `CREATE TABLE Segments(
 		RowID INTEGER PRIMARY KEY,
 		SegID DATA UNIQUE NOT NULL,
 		FullID DATA UNIQUE NOT NULL,
@@ -68,11 +74,28 @@ const (
 		NextQuery INTEGER NOT NULL,
 		UNIQUE(SrcIsdID, SrcAsID, DstIsdID, DstAsID, Policy) ON CONFLICT REPLACE
 	);`
-	SegmentsTable  = "Segments"
-	IntfToSegTable = "IntfToSeg"
-	StartsAtTable  = "StartsAt"
-	EndsAtTable    = "EndsAt"
-	SegTypesTable  = "SegTypes"
-	HpCfgIdsTable  = "HpCfgIds"
-	NextQueryTable = "NextQuery"
+	// revocationsSchema is the table added in schema version 9.
+	revocationsSchema = `
+	CREATE TABLE IF NOT EXISTS Revocations(
+		IsdID INTEGER NOT NULL,
+		AsID INTEGER NOT NULL,
+		IntfID INTEGER NOT NULL,
+		IssuingTime INTEGER NOT NULL,
+		ExpirationTime INTEGER NOT NULL,
+		RawSignedRev DATA NOT NULL,
+		PRIMARY KEY (IsdID, AsID, IntfID)
+	);`
+	SegmentsTable    = "Segments"
+	IntfToSegTable   = "IntfToSeg"
+	StartsAtTable    = "StartsAt"
+	EndsAtTable      = "EndsAt"
+	SegTypesTable    = "SegTypes"
+	HpCfgIdsTable    = "HpCfgIds"
+	NextQueryTable   = "NextQuery"
+	RevocationsTable = "Revocations"
 )
+
+// migrations upgrade older databases to SchemaVersion.
+var migrations = db.Migrations{
+	8: revocationsSchema,
+}
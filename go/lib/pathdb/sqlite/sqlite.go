@@ -55,10 +55,11 @@ type Backend struct {
 }
 
 // New returns a new SQLite backend opening a database at the given path. If
-// no database exists a new database is be created. If the schema version of the
-// stored database is different from the one in schema.go, an error is returned.
+// no database exists a new database is be created. A database with an older
+// schema version is migrated to the one in schema.go. If the schema version of
+// the stored database cannot be migrated, an error is returned.
 func New(path string) (*Backend, error) {
-	db, err := db.NewSqlite(path, Schema, SchemaVersion)
+	db, err := db.NewSqliteWithMigrations(path, Schema, SchemaVersion, migrations)
 	if err != nil {
 		return nil, err
 	}
@@ -30,6 +30,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/infra/modules/db"
 	"github.com/scionproto/scion/go/lib/pathdb/pathdbtest"
 	"github.com/scionproto/scion/go/lib/pathdb/query"
 	"github.com/scionproto/scion/go/proto"
@@ -104,6 +105,35 @@ func TestOpenNewer(t *testing.T) {
 	assert.Nil(t, b)
 }
 
+// TestOpenV8 tests that New migrates a database of schema version 8 without
+// losing the stored segments.
+func TestOpenV8(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	tmpF := tempFilename(t)
+	defer cleanup(tmpF)
+	sqlDB, err := db.NewSqlite(tmpF, schemaV8, 8)
+	require.NoError(t, err)
+	b := &Backend{executor: &executor{db: sqlDB}, db: sqlDB}
+	pseg1, _ := pathdbtest.AllocPathSegment(t, ctrl, ifs1, uint32(10))
+	ctx, cancelF := context.WithTimeout(context.Background(), timeout)
+	defer cancelF()
+	pathdbtest.InsertSeg(t, ctx, b, pseg1, hpCfgIDs)
+	b.db.Close()
+
+	b, err = New(tmpF)
+	require.NoError(t, err)
+	defer b.Close()
+	var version int
+	require.NoError(t, b.db.QueryRow("PRAGMA user_version;").Scan(&version))
+	assert.Equal(t, SchemaVersion, version)
+	res, err := b.Get(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(res), "Segment still exists")
+	_, err = b.RevCache().DeleteExpired(ctx)
+	assert.NoError(t, err)
+}
+
 func setupDB(t *testing.T) (*Backend, string) {
 	tmpFile := tempFilename(t)
 	b, err := New(tmpFile)
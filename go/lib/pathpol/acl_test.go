@@ -252,6 +252,64 @@ func TestACLEval(t *testing.T) {
 	}
 }
 
+// TestACLEvalSimulated evaluates ACLs on the valid paths that are combined
+// from the segments of the simulated beaconing.
+func TestACLEvalSimulated(t *testing.T) {
+	tests := map[string]struct {
+		ACL        *ACL
+		Src        addr.IA
+		Dst        addr.IA
+		ExpPathNum int
+	}{
+		"allow everything": {
+			ACL: &ACL{
+				Entries: []*ACLEntry{
+					{Action: Allow, Rule: mustHopPredicate(t, "0-0#0")},
+					denyEntry,
+				},
+			},
+			Src:        xtest.MustParseIA("1-ff00:0:111"),
+			Dst:        xtest.MustParseIA("2-ff00:0:211"),
+			ExpPathNum: 22,
+		},
+		"deny 1-ff00:0:110#0, 1-ff00:0:120#0, allow rest": {
+			ACL: &ACL{
+				Entries: []*ACLEntry{
+					{Action: Deny, Rule: mustHopPredicate(t, "1-ff00:0:110#0")},
+					{Action: Deny, Rule: mustHopPredicate(t, "1-ff00:0:120#0")},
+					allowEntry,
+				},
+			},
+			Src:        xtest.MustParseIA("1-ff00:0:111"),
+			Dst:        xtest.MustParseIA("2-ff00:0:211"),
+			ExpPathNum: 2,
+		},
+		"deny 1-ff00:0:110#0, 1-ff00:0:120#0 and 1-ff00:0:111#2823, allow rest": {
+			ACL: &ACL{
+				Entries: []*ACLEntry{
+					{Action: Deny, Rule: mustHopPredicate(t, "1-ff00:0:110#0")},
+					{Action: Deny, Rule: mustHopPredicate(t, "1-ff00:0:120#0")},
+					{Action: Deny, Rule: mustHopPredicate(t, "1-ff00:0:111#2823")},
+					allowEntry,
+				},
+			},
+			Src:        xtest.MustParseIA("1-ff00:0:111"),
+			Dst:        xtest.MustParseIA("2-ff00:0:211"),
+			ExpPathNum: 1,
+		},
+	}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	pp := NewSimulatedPathProvider(ctrl)
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			paths := pp.GetPaths(test.Src, test.Dst)
+			outPaths := test.ACL.Eval(paths)
+			assert.Equal(t, test.ExpPathNum, len(outPaths))
+		})
+	}
+}
+
 func TestACLPanic(t *testing.T) {
 	acl := &ACL{
 		Entries: []*ACLEntry{
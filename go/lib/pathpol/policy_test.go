@@ -28,6 +28,7 @@ import (
 	snetpath "github.com/scionproto/scion/go/lib/snet/path"
 	"github.com/scionproto/scion/go/lib/xtest"
 	"github.com/scionproto/scion/go/lib/xtest/graph"
+	"github.com/scionproto/scion/go/lib/xtest/graph/pathprovider"
 )
 
 func TestBasicPolicy(t *testing.T) {
@@ -262,6 +263,70 @@ func TestOptionsEval(t *testing.T) {
 	}
 }
 
+// TestOptionsEvalSimulated evaluates options on the valid paths that are
+// combined from the segments of the simulated beaconing.
+func TestOptionsEvalSimulated(t *testing.T) {
+	deny := func(weight int, rule string) Option {
+		return Option{
+			Policy: &ExtPolicy{
+				Policy: &Policy{
+					ACL: &ACL{
+						Entries: []*ACLEntry{
+							{Action: Deny, Rule: mustHopPredicate(t, rule)},
+							allowEntry,
+						},
+					},
+				},
+			},
+			Weight: weight,
+		}
+	}
+	tests := map[string]struct {
+		Policy     *Policy
+		Src        addr.IA
+		Dst        addr.IA
+		ExpPathNum int
+	}{
+		"two options, combined": {
+			Policy: NewPolicy("", nil, nil, []Option{
+				deny(0, "1-ff00:0:120#0"),
+				deny(0, "2-ff00:0:210#0"),
+			}),
+			Src:        xtest.MustParseIA("1-ff00:0:110"),
+			Dst:        xtest.MustParseIA("2-ff00:0:220"),
+			ExpPathNum: 5,
+		},
+		"two options, take first": {
+			Policy: NewPolicy("", nil, nil, []Option{
+				deny(1, "1-ff00:0:120#0"),
+				deny(0, "2-ff00:0:210#0"),
+			}),
+			Src:        xtest.MustParseIA("1-ff00:0:110"),
+			Dst:        xtest.MustParseIA("2-ff00:0:220"),
+			ExpPathNum: 1,
+		},
+		"two options, take second": {
+			Policy: NewPolicy("", nil, nil, []Option{
+				deny(1, "1-ff00:0:120#0"),
+				deny(10, "2-ff00:0:210#0"),
+			}),
+			Src:        xtest.MustParseIA("1-ff00:0:110"),
+			Dst:        xtest.MustParseIA("2-ff00:0:220"),
+			ExpPathNum: 4,
+		},
+	}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	pp := NewSimulatedPathProvider(ctrl)
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			paths := pp.GetPaths(test.Src, test.Dst)
+			outPaths := test.Policy.Filter(paths)
+			assert.Equal(t, test.ExpPathNum, len(outPaths))
+		})
+	}
+}
+
 func TestExtends(t *testing.T) {
 	tests := map[string]struct {
 		Policy         *ExtPolicy
@@ -584,6 +649,17 @@ func TestFilterOpt(t *testing.T) {
 	}
 }
 
+func TestFilterOptSimulated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	pp := NewSimulatedPathProvider(ctrl)
+	paths := pp.GetPaths(xtest.MustParseIA("1-ff00:0:110"), xtest.MustParseIA("2-ff00:0:220"))
+	require.Len(t, paths, 5)
+	policy := NewPolicy("", nil, newSequence(t, "0+ 1-ff00:0:111 0+"), nil)
+	assert.Empty(t, policy.Filter(paths))
+	assert.Len(t, policy.FilterOpt(paths, FilterOptions{IgnoreSequence: true}), 5)
+}
+
 func TestPolicyJsonConversion(t *testing.T) {
 	policy := NewPolicy("", nil, nil, []Option{
 		{
@@ -614,6 +690,16 @@ func newSequence(t *testing.T, str string) *Sequence {
 	return seq
 }
 
+// NewSimulatedPathProvider returns a path provider for the default graph that
+// only returns valid SCION paths. In contrast to the PathProvider, the paths
+// are combined from the segments of the simulated beaconing. Thus, they can
+// be longer than the shortest paths, and there can be more of them.
+func NewSimulatedPathProvider(ctrl *gomock.Controller) pathprovider.PathProvider {
+	return pathprovider.New(graph.NewDefaultGraph(ctrl))
+}
+
+// PathProvider returns the shortest paths in the default graph, regardless
+// whether they are valid SCION paths.
 type PathProvider struct {
 	g *graph.Graph
 }
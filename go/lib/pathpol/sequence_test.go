@@ -318,3 +318,110 @@ func TestSequenceEval(t *testing.T) {
 		})
 	}
 }
+
+// TestSequenceEvalSimulated evaluates sequences on the valid paths that are
+// combined from the segments of the simulated beaconing. In contrast to the
+// shortest paths, the valid paths from 1-ff00:0:133 to 1-ff00:0:110 do not
+// take the peering link between 1-ff00:0:122 and 1-ff00:0:133.
+func TestSequenceEvalSimulated(t *testing.T) {
+	tests := map[string]struct {
+		Seq        *Sequence
+		Src        addr.IA
+		Dst        addr.IA
+		ExpPathNum int
+	}{
+		"Longer Explicit matching, single wildcard": {
+			Seq: newSequence(t, "1-ff00:0:122#1815 1-ff00:0:121#0,1530 "+
+				"1-ff00:0:120#3015,2911 1-ff00:0:110#1129"),
+			Src:        xtest.MustParseIA("1-ff00:0:122"),
+			Dst:        xtest.MustParseIA("1-ff00:0:110"),
+			ExpPathNum: 1,
+		},
+		"Longer Explicit matching, reverse single wildcard": {
+			Seq: newSequence(t, "1-ff00:0:122#1815 1-ff00:0:121#1530,0 "+
+				"1-ff00:0:120#3015,2911 1-ff00:0:110#1129"),
+			Src:        xtest.MustParseIA("1-ff00:0:122"),
+			Dst:        xtest.MustParseIA("1-ff00:0:110"),
+			ExpPathNum: 0,
+		},
+		"Longer Explicit matching, multiple wildcard": {
+			Seq: newSequence(t, "1-ff00:0:122#0 1-ff00:0:121#0,1530 "+
+				"1-ff00:0:120#3015,0 1-ff00:0:110#1129"),
+			Src:        xtest.MustParseIA("1-ff00:0:122"),
+			Dst:        xtest.MustParseIA("1-ff00:0:110"),
+			ExpPathNum: 1,
+		},
+		"Longer Explicit matching, mixed wildcard types": {
+			Seq: newSequence(t, "1-ff00:0:133#0 1 "+
+				"0-0#0 1-ff00:0:130#0 1-ff00:0:120#0 1-ff00:0:110#1129"),
+			Src:        xtest.MustParseIA("1-ff00:0:133"),
+			Dst:        xtest.MustParseIA("1-ff00:0:110"),
+			ExpPathNum: 1,
+		},
+		"Longer Explicit matching, mixed wildcard types, two paths": {
+			Seq: newSequence(t, "1-ff00:0:133#0 1-0#0 "+
+				"0-0#0 1-0#0 1-0#0? 1-ff00:0:110#0"),
+			Src:        xtest.MustParseIA("1-ff00:0:133"),
+			Dst:        xtest.MustParseIA("1-ff00:0:110"),
+			ExpPathNum: 2,
+		},
+		"Successful match on hop count": {
+			Seq:        newSequence(t, "0 0 0"),
+			Src:        xtest.MustParseIA("2-ff00:0:220"),
+			Dst:        xtest.MustParseIA("1-ff00:0:110"),
+			ExpPathNum: 3,
+		},
+		"Failed match on hop count": {
+			Seq:        newSequence(t, "0 0"),
+			Src:        xtest.MustParseIA("2-ff00:0:220"),
+			Dst:        xtest.MustParseIA("1-ff00:0:110"),
+			ExpPathNum: 0,
+		},
+		"Select one of the intermediate ASes": {
+			Seq:        newSequence(t, "0 2-ff00:0:210 0"),
+			Src:        xtest.MustParseIA("2-ff00:0:220"),
+			Dst:        xtest.MustParseIA("1-ff00:0:110"),
+			ExpPathNum: 1,
+		},
+		"Select two alternative intermediate ASes": {
+			Seq:        newSequence(t, "0 (1-ff00:0:120 | 2-ff00:0:210) 0"),
+			Src:        xtest.MustParseIA("2-ff00:0:220"),
+			Dst:        xtest.MustParseIA("1-ff00:0:110"),
+			ExpPathNum: 3,
+		},
+		"Alternative intermediate ASes, but one doesn't exist": {
+			Seq:        newSequence(t, "0 (2-ff00:0:210 |64-12345) 0"),
+			Src:        xtest.MustParseIA("2-ff00:0:220"),
+			Dst:        xtest.MustParseIA("1-ff00:0:110"),
+			ExpPathNum: 1,
+		},
+		"Or has higher priority than concatenation": {
+			Seq:        newSequence(t, "0 2-ff00:0:210|64-12345 0"),
+			Src:        xtest.MustParseIA("2-ff00:0:220"),
+			Dst:        xtest.MustParseIA("1-ff00:0:110"),
+			ExpPathNum: 1,
+		},
+		"Question mark has higher priority than concatenation": {
+			Seq:        newSequence(t, "0 0 0 ?  "),
+			Src:        xtest.MustParseIA("2-ff00:0:220"),
+			Dst:        xtest.MustParseIA("1-ff00:0:110"),
+			ExpPathNum: 3,
+		},
+		"Parentheses change priority": {
+			Seq:        newSequence(t, "(0 0)?"),
+			Src:        xtest.MustParseIA("2-ff00:0:220"),
+			Dst:        xtest.MustParseIA("1-ff00:0:110"),
+			ExpPathNum: 0,
+		},
+	}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	pp := NewSimulatedPathProvider(ctrl)
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			paths := pp.GetPaths(test.Src, test.Dst)
+			outPaths := test.Seq.Eval(paths)
+			assert.Equal(t, test.ExpPathNum, len(outPaths))
+		})
+	}
+}
@@ -83,6 +83,14 @@ func RelevantRevInfos(ctx context.Context, revCache RevCache,
 	return allRevs, nil
 }
 
+// SegmentKeys returns the keys of all interfaces that are part of the given
+// segments, including the peering interfaces.
+func SegmentKeys(segs ...*seg.PathSegment) KeySet {
+	keys := make(KeySet)
+	addRevKeys(segs, keys, false)
+	return keys
+}
+
 // addRevKeys adds all revocations keys for the given segments to the keys set.
 // If hopOnly is set, only the first hop entry is considered.
 func addRevKeys(segs []*seg.PathSegment, keys KeySet, hopOnly bool) {
@@ -0,0 +1,144 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bgp_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/routemgr"
+	"github.com/scionproto/scion/go/lib/routemgr/bgp"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+// birdConfig is the configuration of the BIRD 2 speaker. BIRD connects to the
+// speaker under test, announces a static route and accepts all routes.
+const birdConfig = `
+router id 192.0.2.2;
+protocol device {}
+protocol static announced {
+	ipv4;
+	route 10.2.0.0/16 blackhole;
+}
+protocol bgp gateway {
+	local 127.0.0.2 port %d as 65002;
+	neighbor 127.0.0.1 port %d as 65001;
+	multihop;
+	ipv4 {
+		import all;
+		export where proto = "announced";
+		next hop self;
+	};
+}
+`
+
+// TestInteropBIRD tests the speaker against BIRD 2. The test is skipped if
+// BIRD is not installed. The binaries can be set with the BIRD and BIRDC
+// environment variables, otherwise they are looked up in PATH.
+func TestInteropBIRD(t *testing.T) {
+	birdBin, birdcBin := lookupBIRD(t)
+
+	dir, cleanup := xtest.MustTempDir("", "bgp-interop")
+	defer cleanup()
+	speakerAddr := freeAddr(t)
+	birdAddr := freeAddrOn(t, net.ParseIP("127.0.0.2"))
+	conf := filepath.Join(dir, "bird.conf")
+	sock := filepath.Join(dir, "bird.ctl")
+	err := ioutil.WriteFile(conf,
+		[]byte(fmt.Sprintf(birdConfig, birdAddr.Port, speakerAddr.Port)), 0644)
+	require.NoError(t, err)
+
+	s := &bgp.Speaker{
+		LocalAS:    65001,
+		RouterID:   net.ParseIP("192.0.2.1"),
+		ListenAddr: speakerAddr,
+		Neighbors: []bgp.Neighbor{
+			{Address: &net.TCPAddr{IP: birdAddr.IP}, AS: 65002, Passive: true},
+		},
+	}
+	pub := s.NewPublisher()
+	pub.AddRoute(routemgr.Route{Prefix: xtest.MustParseCIDR(t, "10.1.0.0/16")})
+	cons := s.NewConsumer()
+	run(t, s)
+	defer s.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bird := exec.CommandContext(ctx, birdBin, "-f", "-c", conf, "-s", sock)
+	bird.Stdout, bird.Stderr = os.Stdout, os.Stderr
+	require.NoError(t, bird.Start())
+	defer func() {
+		cancel()
+		bird.Wait()
+	}()
+
+	// The route announced by BIRD is learned by the speaker.
+	update := receive(t, cons)
+	assert.Equal(t, routemgr.RouteUpdate{
+		IsAdd: true,
+		Route: routemgr.Route{
+			Prefix:  xtest.MustParseCIDR(t, "10.2.0.0/16"),
+			NextHop: birdAddr.IP.To4(),
+		},
+	}, canonical(update))
+
+	// The route announced by the speaker is learned by BIRD.
+	learned := func(prefix string) bool {
+		out, err := exec.Command(birdcBin, "-s", sock, "show", "route", "protocol",
+			"gateway").CombinedOutput()
+		return err == nil && strings.Contains(string(out), prefix)
+	}
+	require.Eventually(t, func() bool { return learned("10.1.0.0/16") },
+		10*time.Second, 100*time.Millisecond)
+
+	// Withdrawn routes are removed from BIRD.
+	pub.DeleteRoute(routemgr.Route{Prefix: xtest.MustParseCIDR(t, "10.1.0.0/16")})
+	require.Eventually(t, func() bool { return !learned("10.1.0.0/16") },
+		10*time.Second, 100*time.Millisecond)
+}
+
+func lookupBIRD(t *testing.T) (string, string) {
+	lookup := func(env, name string) string {
+		if bin := os.Getenv(env); bin != "" {
+			return bin
+		}
+		bin, err := exec.LookPath(name)
+		if err != nil {
+			t.Skipf("%s not installed", name)
+		}
+		return bin
+	}
+	return lookup("BIRD", "bird"), lookup("BIRDC", "birdc")
+}
+
+// freeAddrOn returns a TCP address on the IP with a port that is currently
+// unused.
+func freeAddrOn(t *testing.T, ip net.IP) *net.TCPAddr {
+	l, err := net.ListenTCP("tcp", &net.TCPAddr{IP: ip})
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr)
+}
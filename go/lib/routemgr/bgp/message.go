@@ -0,0 +1,515 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bgp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+)
+
+const (
+	headerLen     = 19
+	maxMessageLen = 4096
+	bgpVersion    = 4
+	// asTrans is the AS number used in the 2-octet AS field of the OPEN
+	// message if the local AS does not fit into 2 octets (RFC 6793).
+	asTrans = 23456
+)
+
+type messageType uint8
+
+const (
+	msgOpen         messageType = 1
+	msgUpdate       messageType = 2
+	msgNotification messageType = 3
+	msgKeepalive    messageType = 4
+)
+
+func (t messageType) String() string {
+	switch t {
+	case msgOpen:
+		return "OPEN"
+	case msgUpdate:
+		return "UPDATE"
+	case msgNotification:
+		return "NOTIFICATION"
+	case msgKeepalive:
+		return "KEEPALIVE"
+	default:
+		return fmt.Sprintf("UNKNOWN (%d)", uint8(t))
+	}
+}
+
+// Path attribute flags and types.
+const (
+	flagOptional   = 0x80
+	flagTransitive = 0x40
+	flagExtended   = 0x10
+
+	attrOrigin        = 1
+	attrASPath        = 2
+	attrNextHop       = 3
+	attrLocalPref     = 5
+	attrMPReachNLRI   = 14
+	attrMPUnreachNLRI = 15
+
+	originIGP      = 0
+	asPathSequence = 2
+)
+
+// Address families and capabilities.
+const (
+	afiIPv4     = 1
+	afiIPv6     = 2
+	safiUnicast = 1
+
+	optParamCapabilities = 2
+	capMultiprotocol     = 1
+	capFourOctetAS       = 65
+)
+
+// Notification error codes and subcodes.
+const (
+	errMessageHeader = 1
+	errOpenMessage   = 2
+	errUpdateMessage = 3
+	errHoldTimer     = 4
+	errFSM           = 5
+	errCease         = 6
+
+	errSubBadMessageLength  = 2
+	errSubBadMessageType    = 3
+	errSubUnsupportedVer    = 1
+	errSubBadPeerAS         = 2
+	errSubBadBGPIdentifier  = 3
+	errSubUnacceptableHold  = 6
+	errSubUnsupportedCap    = 7
+	errSubMalformedAttrList = 1
+	errSubMissingAttr       = 3
+	errSubInvalidNetwork    = 10
+	errSubAdminShutdown     = 2
+	errSubCollision         = 7
+)
+
+// notificationError is an error that is signaled to the peer with a
+// NOTIFICATION message.
+type notificationError struct {
+	Code    uint8
+	Subcode uint8
+	Data    []byte
+	Reason  string
+	// received indicates that the error was received from the neighbor.
+	received bool
+}
+
+func (e *notificationError) Error() string {
+	return fmt.Sprintf("%s (code=%d subcode=%d)", e.Reason, e.Code, e.Subcode)
+}
+
+func newNotificationError(code, subcode uint8, reason string) *notificationError {
+	return &notificationError{Code: code, Subcode: subcode, Reason: reason}
+}
+
+// openMessage is a BGP OPEN message.
+type openMessage struct {
+	AS uint32
+	// HoldTime is the proposed hold time in seconds.
+	HoldTime uint16
+	RouterID net.IP
+	// IPv4 and IPv6 indicate whether unicast routes of the respective address
+	// family are supported.
+	IPv4 bool
+	IPv6 bool
+	// FourOctetAS indicates whether 4-octet AS numbers are supported.
+	FourOctetAS bool
+}
+
+func (m *openMessage) encode() []byte {
+	var caps []byte
+	if m.IPv4 {
+		caps = append(caps, capMultiprotocol, 4, 0, afiIPv4, 0, safiUnicast)
+	}
+	if m.IPv6 {
+		caps = append(caps, capMultiprotocol, 4, 0, afiIPv6, 0, safiUnicast)
+	}
+	caps = append(caps, capFourOctetAS, 4)
+	caps = appendUint32(caps, m.AS)
+
+	myAS := uint16(asTrans)
+	if m.AS <= 0xffff {
+		myAS = uint16(m.AS)
+	}
+	body := []byte{bgpVersion}
+	body = appendUint16(body, myAS)
+	body = appendUint16(body, m.HoldTime)
+	body = append(body, m.RouterID.To4()...)
+	body = append(body, byte(len(caps)+2), optParamCapabilities, byte(len(caps)))
+	body = append(body, caps...)
+	return encodeMessage(msgOpen, body)
+}
+
+func decodeOpen(body []byte) (*openMessage, error) {
+	if len(body) < 10 {
+		return nil, newNotificationError(errMessageHeader, errSubBadMessageLength,
+			"OPEN message too short")
+	}
+	if body[0] != bgpVersion {
+		e := newNotificationError(errOpenMessage, errSubUnsupportedVer,
+			fmt.Sprintf("unsupported version %d", body[0]))
+		e.Data = []byte{0, bgpVersion}
+		return nil, e
+	}
+	m := &openMessage{
+		AS:       uint32(binary.BigEndian.Uint16(body[1:3])),
+		HoldTime: binary.BigEndian.Uint16(body[3:5]),
+		RouterID: net.IP(append([]byte(nil), body[5:9]...)),
+	}
+	optLen := int(body[9])
+	opts := body[10:]
+	if len(opts) != optLen {
+		return nil, newNotificationError(errMessageHeader, errSubBadMessageLength,
+			"invalid optional parameters length")
+	}
+	var multiprotocol bool
+	for len(opts) > 0 {
+		if len(opts) < 2 || len(opts) < 2+int(opts[1]) {
+			return nil, newNotificationError(errOpenMessage, 0, "malformed optional parameter")
+		}
+		paramType, param := opts[0], opts[2:2+int(opts[1])]
+		opts = opts[2+int(opts[1]):]
+		if paramType != optParamCapabilities {
+			continue
+		}
+		for len(param) > 0 {
+			if len(param) < 2 || len(param) < 2+int(param[1]) {
+				return nil, newNotificationError(errOpenMessage, 0, "malformed capability")
+			}
+			code, value := param[0], param[2:2+int(param[1])]
+			param = param[2+int(param[1]):]
+			switch {
+			case code == capMultiprotocol && len(value) == 4:
+				multiprotocol = true
+				afi := binary.BigEndian.Uint16(value[0:2])
+				if value[3] != safiUnicast {
+					continue
+				}
+				m.IPv4 = m.IPv4 || afi == afiIPv4
+				m.IPv6 = m.IPv6 || afi == afiIPv6
+			case code == capFourOctetAS && len(value) == 4:
+				m.FourOctetAS = true
+				m.AS = binary.BigEndian.Uint32(value)
+			}
+		}
+	}
+	// Without multiprotocol capabilities, IPv4 unicast is implied.
+	if !multiprotocol {
+		m.IPv4 = true
+	}
+	return m, nil
+}
+
+// updateMessage is a BGP UPDATE message. IPv4 prefixes are carried in the
+// classic fields, IPv6 prefixes in the multiprotocol attributes.
+type updateMessage struct {
+	Withdrawn []*net.IPNet
+	Announced []*net.IPNet
+	// NextHop is the next hop of the announced IPv4 prefixes.
+	NextHop net.IP
+	// NextHopIPv6 is the next hop of the announced IPv6 prefixes.
+	NextHopIPv6 net.IP
+	ASPath      []uint32
+	// LocalPref is the local preference. It is only included if it is not
+	// zero.
+	LocalPref uint32
+}
+
+func (m *updateMessage) encode() []byte {
+	var withdrawn4, withdrawn6, announced4, announced6 []byte
+	for _, p := range m.Withdrawn {
+		if p.IP.To4() != nil {
+			withdrawn4 = appendPrefix(withdrawn4, p)
+		} else {
+			withdrawn6 = appendPrefix(withdrawn6, p)
+		}
+	}
+	for _, p := range m.Announced {
+		if p.IP.To4() != nil {
+			announced4 = appendPrefix(announced4, p)
+		} else {
+			announced6 = appendPrefix(announced6, p)
+		}
+	}
+
+	var attrs []byte
+	if len(announced4) > 0 || len(announced6) > 0 {
+		attrs = appendAttribute(attrs, flagTransitive, attrOrigin, []byte{originIGP})
+		var path []byte
+		if len(m.ASPath) > 0 {
+			path = append(path, asPathSequence, byte(len(m.ASPath)))
+			for _, as := range m.ASPath {
+				path = appendUint32(path, as)
+			}
+		}
+		attrs = appendAttribute(attrs, flagTransitive, attrASPath, path)
+		if len(announced4) > 0 {
+			attrs = appendAttribute(attrs, flagTransitive, attrNextHop, m.NextHop.To4())
+		}
+		if m.LocalPref != 0 {
+			attrs = appendAttribute(attrs, flagTransitive, attrLocalPref,
+				appendUint32(nil, m.LocalPref))
+		}
+	}
+	if len(announced6) > 0 {
+		reach := []byte{0, afiIPv6, safiUnicast, net.IPv6len}
+		reach = append(reach, m.NextHopIPv6.To16()...)
+		reach = append(reach, 0)
+		reach = append(reach, announced6...)
+		attrs = appendAttribute(attrs, flagOptional, attrMPReachNLRI, reach)
+	}
+	if len(withdrawn6) > 0 {
+		unreach := []byte{0, afiIPv6, safiUnicast}
+		unreach = append(unreach, withdrawn6...)
+		attrs = appendAttribute(attrs, flagOptional, attrMPUnreachNLRI, unreach)
+	}
+
+	body := appendUint16(nil, uint16(len(withdrawn4)))
+	body = append(body, withdrawn4...)
+	body = appendUint16(body, uint16(len(attrs)))
+	body = append(body, attrs...)
+	body = append(body, announced4...)
+	return encodeMessage(msgUpdate, body)
+}
+
+func decodeUpdate(body []byte) (*updateMessage, error) {
+	malformed := func(reason string) error {
+		return newNotificationError(errUpdateMessage, errSubMalformedAttrList, reason)
+	}
+	if len(body) < 4 {
+		return nil, malformed("UPDATE message too short")
+	}
+	m := &updateMessage{}
+	withdrawnLen := int(binary.BigEndian.Uint16(body[0:2]))
+	if len(body) < 4+withdrawnLen {
+		return nil, malformed("invalid withdrawn routes length")
+	}
+	var err error
+	if m.Withdrawn, err = decodePrefixes(body[2:2+withdrawnLen], net.IPv4len); err != nil {
+		return nil, err
+	}
+	body = body[2+withdrawnLen:]
+	attrsLen := int(binary.BigEndian.Uint16(body[0:2]))
+	if len(body) < 2+attrsLen {
+		return nil, malformed("invalid path attributes length")
+	}
+	attrs := body[2 : 2+attrsLen]
+	announced4, err := decodePrefixes(body[2+attrsLen:], net.IPv4len)
+	if err != nil {
+		return nil, err
+	}
+	var hasOrigin, hasASPath bool
+	for len(attrs) > 0 {
+		if len(attrs) < 3 {
+			return nil, malformed("truncated path attribute")
+		}
+		flags, typ := attrs[0], attrs[1]
+		var length, offset int
+		if flags&flagExtended != 0 {
+			if len(attrs) < 4 {
+				return nil, malformed("truncated path attribute")
+			}
+			length, offset = int(binary.BigEndian.Uint16(attrs[2:4])), 4
+		} else {
+			length, offset = int(attrs[2]), 3
+		}
+		if len(attrs) < offset+length {
+			return nil, malformed("truncated path attribute")
+		}
+		value := attrs[offset : offset+length]
+		attrs = attrs[offset+length:]
+
+		switch typ {
+		case attrOrigin:
+			hasOrigin = true
+		case attrASPath:
+			hasASPath = true
+			for len(value) > 0 {
+				if len(value) < 2 || len(value) < 2+4*int(value[1]) {
+					return nil, malformed("malformed AS_PATH")
+				}
+				segment := value[2 : 2+4*int(value[1])]
+				value = value[2+4*int(value[1]):]
+				for ; len(segment) > 0; segment = segment[4:] {
+					m.ASPath = append(m.ASPath, binary.BigEndian.Uint32(segment))
+				}
+			}
+		case attrNextHop:
+			if len(value) != net.IPv4len {
+				return nil, malformed("malformed NEXT_HOP")
+			}
+			m.NextHop = net.IP(append([]byte(nil), value...))
+		case attrLocalPref:
+			if len(value) != 4 {
+				return nil, malformed("malformed LOCAL_PREF")
+			}
+			m.LocalPref = binary.BigEndian.Uint32(value)
+		case attrMPReachNLRI:
+			if len(value) < 5 || len(value) < 5+int(value[3]) {
+				return nil, malformed("malformed MP_REACH_NLRI")
+			}
+			afi, safi, nhLen := binary.BigEndian.Uint16(value[0:2]), value[2], int(value[3])
+			if afi != afiIPv6 || safi != safiUnicast {
+				continue
+			}
+			// The next hop might contain a global and a link-local address.
+			if nhLen != net.IPv6len && nhLen != 2*net.IPv6len {
+				return nil, malformed("malformed MP_REACH_NLRI next hop")
+			}
+			m.NextHopIPv6 = net.IP(append([]byte(nil), value[4:4+net.IPv6len]...))
+			announced6, err := decodePrefixes(value[5+nhLen:], net.IPv6len)
+			if err != nil {
+				return nil, err
+			}
+			m.Announced = append(m.Announced, announced6...)
+		case attrMPUnreachNLRI:
+			if len(value) < 3 {
+				return nil, malformed("malformed MP_UNREACH_NLRI")
+			}
+			afi, safi := binary.BigEndian.Uint16(value[0:2]), value[2]
+			if afi != afiIPv6 || safi != safiUnicast {
+				continue
+			}
+			withdrawn6, err := decodePrefixes(value[3:], net.IPv6len)
+			if err != nil {
+				return nil, err
+			}
+			m.Withdrawn = append(m.Withdrawn, withdrawn6...)
+		}
+	}
+	if len(announced4) > 0 || len(m.Announced) > 0 {
+		if !hasOrigin || !hasASPath || (len(announced4) > 0 && m.NextHop == nil) {
+			return nil, newNotificationError(errUpdateMessage, errSubMissingAttr,
+				"missing well-known attribute")
+		}
+	}
+	m.Announced = append(announced4, m.Announced...)
+	return m, nil
+}
+
+func encodeNotification(e *notificationError) []byte {
+	body := append([]byte{e.Code, e.Subcode}, e.Data...)
+	return encodeMessage(msgNotification, body)
+}
+
+func decodeNotification(body []byte) *notificationError {
+	e := &notificationError{Reason: "received NOTIFICATION", received: true}
+	if len(body) >= 2 {
+		e.Code, e.Subcode, e.Data = body[0], body[1], body[2:]
+	}
+	return e
+}
+
+func encodeKeepalive() []byte {
+	return encodeMessage(msgKeepalive, nil)
+}
+
+func encodeMessage(typ messageType, body []byte) []byte {
+	msg := make([]byte, headerLen, headerLen+len(body))
+	for i := 0; i < 16; i++ {
+		msg[i] = 0xff
+	}
+	binary.BigEndian.PutUint16(msg[16:18], uint16(headerLen+len(body)))
+	msg[18] = byte(typ)
+	return append(msg, body...)
+}
+
+// readMessage reads a single BGP message and returns its type and body.
+func readMessage(r io.Reader) (messageType, []byte, error) {
+	var hdr [headerLen]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return 0, nil, err
+	}
+	for _, b := range hdr[:16] {
+		if b != 0xff {
+			return 0, nil, newNotificationError(errMessageHeader, 1, "invalid marker")
+		}
+	}
+	length := int(binary.BigEndian.Uint16(hdr[16:18]))
+	if length < headerLen || length > maxMessageLen {
+		e := newNotificationError(errMessageHeader, errSubBadMessageLength,
+			fmt.Sprintf("invalid message length %d", length))
+		e.Data = append([]byte(nil), hdr[16:18]...)
+		return 0, nil, e
+	}
+	typ := messageType(hdr[18])
+	if typ < msgOpen || typ > msgKeepalive {
+		e := newNotificationError(errMessageHeader, errSubBadMessageType,
+			fmt.Sprintf("invalid message type %d", typ))
+		e.Data = []byte{byte(typ)}
+		return 0, nil, e
+	}
+	body := make([]byte, length-headerLen)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return typ, body, nil
+}
+
+func appendAttribute(b []byte, flags, typ uint8, value []byte) []byte {
+	if len(value) > 0xff {
+		b = append(b, flags|flagExtended, typ)
+		b = appendUint16(b, uint16(len(value)))
+	} else {
+		b = append(b, flags, typ, byte(len(value)))
+	}
+	return append(b, value...)
+}
+
+func appendPrefix(b []byte, prefix *net.IPNet) []byte {
+	ones, _ := prefix.Mask.Size()
+	ip := prefix.IP.To4()
+	if ip == nil {
+		ip = prefix.IP.To16()
+	}
+	b = append(b, byte(ones))
+	return append(b, ip[:(ones+7)/8]...)
+}
+
+func decodePrefixes(b []byte, ipLen int) ([]*net.IPNet, error) {
+	var prefixes []*net.IPNet
+	for len(b) > 0 {
+		ones := int(b[0])
+		n := (ones + 7) / 8
+		if ones > 8*ipLen || len(b) < 1+n {
+			return nil, newNotificationError(errUpdateMessage, errSubInvalidNetwork,
+				"invalid prefix")
+		}
+		ip := make(net.IP, ipLen)
+		copy(ip, b[1:1+n])
+		mask := net.CIDRMask(ones, 8*ipLen)
+		prefixes = append(prefixes, &net.IPNet{IP: ip.Mask(mask), Mask: mask})
+		b = b[1+n:]
+	}
+	return prefixes, nil
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
@@ -0,0 +1,175 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bgp
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestOpenMessage(t *testing.T) {
+	testCases := map[string]openMessage{
+		"2-octet AS": {
+			AS:          65001,
+			HoldTime:    90,
+			RouterID:    net.ParseIP("192.0.2.1").To4(),
+			IPv4:        true,
+			IPv6:        true,
+			FourOctetAS: true,
+		},
+		"4-octet AS": {
+			AS:          4200000001,
+			HoldTime:    0,
+			RouterID:    net.ParseIP("192.0.2.2").To4(),
+			IPv4:        true,
+			FourOctetAS: true,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			typ, body, err := readMessage(bytes.NewReader(tc.encode()))
+			require.NoError(t, err)
+			assert.Equal(t, msgOpen, typ)
+			m, err := decodeOpen(body)
+			require.NoError(t, err)
+			assert.Equal(t, &tc, m)
+		})
+	}
+}
+
+func TestUpdateMessage(t *testing.T) {
+	testCases := map[string]updateMessage{
+		"IPv4 announce": {
+			Announced: xtest.MustParseCIDRs(t, "10.1.0.0/16", "10.2.3.0/24", "0.0.0.0/0"),
+			NextHop:   net.ParseIP("192.0.2.1").To4(),
+			ASPath:    []uint32{65001, 4200000001},
+		},
+		"IPv6 announce": {
+			Announced:   xtest.MustParseCIDRs(t, "2001:db8::/32", "2001:db8:1::/48"),
+			NextHopIPv6: net.ParseIP("2001:db8::1"),
+			ASPath:      []uint32{65001},
+		},
+		"mixed announce iBGP": {
+			Announced:   xtest.MustParseCIDRs(t, "10.1.0.0/16", "2001:db8::/32"),
+			NextHop:     net.ParseIP("192.0.2.1").To4(),
+			NextHopIPv6: net.ParseIP("2001:db8::1"),
+			LocalPref:   100,
+		},
+		"withdraw": {
+			Withdrawn: xtest.MustParseCIDRs(t, "10.1.0.0/16", "2001:db8::/32"),
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			typ, body, err := readMessage(bytes.NewReader(tc.encode()))
+			require.NoError(t, err)
+			assert.Equal(t, msgUpdate, typ)
+			m, err := decodeUpdate(body)
+			require.NoError(t, err)
+			assert.Equal(t, canonical(&tc), canonical(m))
+		})
+	}
+}
+
+func TestDecodeUpdateErrors(t *testing.T) {
+	testCases := map[string][]byte{
+		"truncated":         {0, 0},
+		"withdrawn too big": {0, 10, 0, 0},
+		"invalid prefix":    {0, 2, 33, 10, 0, 0},
+		"missing next hop": func() []byte {
+			m := updateMessage{Announced: xtest.MustParseCIDRs(t, "10.1.0.0/16")}
+			m.NextHop = net.IPv4zero
+			msg := m.encode()
+			// Strip the NEXT_HOP attribute (7 bytes) and fix the attribute length.
+			body := append([]byte(nil), msg[headerLen:]...)
+			attrsLen := int(body[3])
+			attrs := append([]byte(nil), body[4:4+attrsLen-7]...)
+			nlri := body[4+attrsLen:]
+			out := []byte{0, 0, 0, byte(len(attrs))}
+			out = append(out, attrs...)
+			return append(out, nlri...)
+		}(),
+	}
+	for name, body := range testCases {
+		name, body := name, body
+		t.Run(name, func(t *testing.T) {
+			_, err := decodeUpdate(body)
+			var e *notificationError
+			require.Error(t, err)
+			require.IsType(t, e, err)
+			assert.Equal(t, uint8(errUpdateMessage), err.(*notificationError).Code)
+		})
+	}
+}
+
+func TestReadMessageErrors(t *testing.T) {
+	valid := encodeKeepalive()
+	testCases := map[string]func() []byte{
+		"invalid marker": func() []byte {
+			msg := append([]byte(nil), valid...)
+			msg[0] = 0
+			return msg
+		},
+		"length too small": func() []byte {
+			msg := append([]byte(nil), valid...)
+			msg[17] = 18
+			return msg
+		},
+		"invalid type": func() []byte {
+			msg := append([]byte(nil), valid...)
+			msg[18] = 5
+			return msg
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			_, _, err := readMessage(bytes.NewReader(tc()))
+			require.Error(t, err)
+			assert.Equal(t, uint8(errMessageHeader), err.(*notificationError).Code)
+		})
+	}
+}
+
+// canonical converts the IPv4 addresses in the message to their 4-byte
+// representation.
+func canonical(m *updateMessage) *updateMessage {
+	c := *m
+	c.Announced, c.Withdrawn = canonicalPrefixes(m.Announced), canonicalPrefixes(m.Withdrawn)
+	if m.NextHop != nil {
+		c.NextHop = m.NextHop.To4()
+	}
+	return &c
+}
+
+func canonicalPrefixes(prefixes []*net.IPNet) []*net.IPNet {
+	var c []*net.IPNet
+	for _, p := range prefixes {
+		ip := p.IP
+		if v4 := ip.To4(); v4 != nil {
+			ip = v4
+		}
+		c = append(c, &net.IPNet{IP: ip, Mask: p.Mask})
+	}
+	return c
+}
@@ -0,0 +1,463 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bgp
+
+import (
+	"bytes"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/routemgr"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+const (
+	// openHoldTime is the hold time used while waiting for the OPEN message
+	// of the neighbor, as suggested by RFC 4271.
+	openHoldTime = 4 * time.Minute
+	// maxPrefixesPerUpdate bounds the number of prefixes per UPDATE message,
+	// such that the message stays below the maximum message size.
+	maxPrefixesPerUpdate = 200
+	// defaultLocalPref is the local preference announced to iBGP neighbors.
+	defaultLocalPref = 100
+)
+
+// session is a BGP session with a neighbor over a single TCP connection.
+type session struct {
+	speaker  *Speaker
+	neighbor *neighbor
+	conn     net.Conn
+	outgoing bool
+
+	// The following fields are set during the OPEN exchange.
+	remoteID net.IP
+	holdTime time.Duration
+	ipv4     bool
+	ipv6     bool
+
+	// established is protected by the mutex of the speaker.
+	established bool
+
+	mtx             sync.Mutex
+	pendingAnnounce map[string]*net.IPNet
+	pendingWithdraw map[string]*net.IPNet
+	pending         chan struct{}
+	closeChan       chan struct{}
+	closeOnce       sync.Once
+
+	// learned are the routes learned from the neighbor, indexed by prefix.
+	// They are only accessed by the reading goroutine.
+	learned   map[string]routemgr.Route
+	publisher routemgr.Publisher
+}
+
+func newSession(speaker *Speaker, n *neighbor, conn net.Conn, outgoing bool) *session {
+	return &session{
+		speaker:         speaker,
+		neighbor:        n,
+		conn:            conn,
+		outgoing:        outgoing,
+		pendingAnnounce: make(map[string]*net.IPNet),
+		pendingWithdraw: make(map[string]*net.IPNet),
+		pending:         make(chan struct{}, 1),
+		closeChan:       make(chan struct{}),
+		learned:         make(map[string]routemgr.Route),
+	}
+}
+
+// run runs the session until it terminates. If the session is terminated
+// because of an error that must be signaled to the neighbor, a NOTIFICATION
+// message is sent before the connection is closed.
+func (s *session) run() error {
+	if err := s.handshake(); err != nil {
+		s.speaker.unregister(s)
+		s.terminate(err)
+		return err
+	}
+	defer s.speaker.unregister(s)
+
+	s.publisher = s.speaker.importedRoutes.NewPublisher()
+	readErr := make(chan error, 1)
+	readDone := make(chan struct{})
+	go func() {
+		defer log.HandlePanic()
+		defer close(readDone)
+		readErr <- s.read()
+	}()
+
+	s.speaker.establish(s)
+	log.Info("BGP session established", "neighbor", s.neighbor.Address,
+		"remote_id", s.remoteID, "hold_time", s.holdTime)
+
+	err := s.serve(readErr)
+	s.terminate(err)
+	// The learned routes are retracted once the reader stopped.
+	<-readDone
+	s.publisher.Close()
+	return err
+}
+
+// serve sends keepalives and updates to the neighbor until the session
+// terminates.
+func (s *session) serve(readErr <-chan error) error {
+	var keepalive <-chan time.Time
+	if s.holdTime > 0 {
+		ticker := time.NewTicker(s.holdTime / 3)
+		defer ticker.Stop()
+		keepalive = ticker.C
+	}
+	for {
+		select {
+		case err := <-readErr:
+			return err
+		case <-s.closeChan:
+			return serrors.New("session closed")
+		case <-keepalive:
+			if err := s.write(encodeKeepalive()); err != nil {
+				return err
+			}
+		case <-s.pending:
+			if err := s.flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// handshake exchanges the OPEN and KEEPALIVE messages with the neighbor.
+func (s *session) handshake() error {
+	sp := s.speaker
+	open := &openMessage{
+		AS:          sp.LocalAS,
+		HoldTime:    uint16(sp.holdTime() / time.Second),
+		RouterID:    sp.RouterID,
+		IPv4:        true,
+		IPv6:        true,
+		FourOctetAS: true,
+	}
+	if err := s.write(open.encode()); err != nil {
+		return err
+	}
+
+	s.conn.SetReadDeadline(time.Now().Add(openHoldTime))
+	body, err := s.expect(msgOpen)
+	if err != nil {
+		return err
+	}
+	remote, err := decodeOpen(body)
+	if err != nil {
+		return err
+	}
+	if err := s.validateOpen(remote); err != nil {
+		return err
+	}
+	s.remoteID = remote.RouterID
+	s.ipv4, s.ipv6 = remote.IPv4, remote.IPv6
+	s.holdTime = time.Duration(remote.HoldTime) * time.Second
+	if local := sp.holdTime(); local < s.holdTime {
+		s.holdTime = local
+	}
+	if err := sp.register(s); err != nil {
+		return err
+	}
+	if err := s.write(encodeKeepalive()); err != nil {
+		return err
+	}
+	s.setReadDeadline()
+	_, err = s.expect(msgKeepalive)
+	return err
+}
+
+func (s *session) validateOpen(remote *openMessage) error {
+	if !remote.FourOctetAS {
+		return newNotificationError(errOpenMessage, errSubUnsupportedCap,
+			"4-octet AS number capability required")
+	}
+	if remote.AS != s.neighbor.AS {
+		return newNotificationError(errOpenMessage, errSubBadPeerAS, "unexpected AS")
+	}
+	if remote.HoldTime == 1 || remote.HoldTime == 2 {
+		return newNotificationError(errOpenMessage, errSubUnacceptableHold,
+			"unacceptable hold time")
+	}
+	id := remote.RouterID.To4()
+	if id.Equal(net.IPv4zero) || (remote.AS == s.speaker.LocalAS && id.Equal(s.speaker.RouterID)) {
+		return newNotificationError(errOpenMessage, errSubBadBGPIdentifier,
+			"invalid BGP identifier")
+	}
+	return nil
+}
+
+// expect reads the next message and checks that it is of the expected type.
+func (s *session) expect(expected messageType) ([]byte, error) {
+	typ, body, err := readMessage(s.conn)
+	switch {
+	case err != nil:
+		return nil, err
+	case typ == msgNotification:
+		return nil, decodeNotification(body)
+	case typ != expected:
+		return nil, newNotificationError(errFSM, 0,
+			"unexpected "+typ.String()+" message, expected "+expected.String())
+	}
+	return body, nil
+}
+
+// read reads messages from the neighbor until an error occurs.
+func (s *session) read() error {
+	for {
+		s.setReadDeadline()
+		typ, body, err := readMessage(s.conn)
+		if err != nil {
+			if e, ok := err.(net.Error); ok && e.Timeout() {
+				return newNotificationError(errHoldTimer, 0, "hold timer expired")
+			}
+			return err
+		}
+		switch typ {
+		case msgKeepalive:
+		case msgUpdate:
+			update, err := decodeUpdate(body)
+			if err != nil {
+				return err
+			}
+			s.learn(update)
+		case msgNotification:
+			return decodeNotification(body)
+		default:
+			return newNotificationError(errFSM, 0, "unexpected "+typ.String()+" message")
+		}
+	}
+}
+
+func (s *session) setReadDeadline() {
+	if s.holdTime == 0 {
+		s.conn.SetReadDeadline(time.Time{})
+		return
+	}
+	s.conn.SetReadDeadline(time.Now().Add(s.holdTime))
+}
+
+// learn processes the routes of an UPDATE message. Announced routes implicitly
+// replace previously learned routes for the same prefix.
+func (s *session) learn(update *updateMessage) {
+	for _, prefix := range update.Withdrawn {
+		key := prefix.String()
+		if route, ok := s.learned[key]; ok {
+			s.publisher.DeleteRoute(route)
+			delete(s.learned, key)
+		}
+	}
+	if len(update.Announced) == 0 {
+		return
+	}
+	for _, as := range update.ASPath {
+		if as == s.speaker.LocalAS {
+			// Routing loop, the route is treated as withdrawn.
+			for _, prefix := range update.Announced {
+				if route, ok := s.learned[prefix.String()]; ok {
+					s.publisher.DeleteRoute(route)
+					delete(s.learned, prefix.String())
+				}
+			}
+			return
+		}
+	}
+	for _, prefix := range update.Announced {
+		nextHop := update.NextHop
+		if prefix.IP.To4() == nil {
+			nextHop = update.NextHopIPv6
+		}
+		route := routemgr.Route{Prefix: prefix, NextHop: nextHop}
+		key := prefix.String()
+		if old, ok := s.learned[key]; ok {
+			if old.NextHop.Equal(nextHop) {
+				continue
+			}
+			s.publisher.DeleteRoute(old)
+		}
+		s.learned[key] = route
+		s.publisher.AddRoute(route)
+	}
+}
+
+func (s *session) setEstablished() {
+	s.established = true
+}
+
+// announce schedules the announcement of the prefix.
+func (s *session) announce(prefix *net.IPNet) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	key := prefix.String()
+	delete(s.pendingWithdraw, key)
+	s.pendingAnnounce[key] = prefix
+	s.signal()
+}
+
+// withdraw schedules the withdrawal of the prefix.
+func (s *session) withdraw(prefix *net.IPNet) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	key := prefix.String()
+	delete(s.pendingAnnounce, key)
+	s.pendingWithdraw[key] = prefix
+	s.signal()
+}
+
+func (s *session) signal() {
+	select {
+	case s.pending <- struct{}{}:
+	default:
+	}
+}
+
+// flush sends the pending announcements and withdrawals to the neighbor.
+func (s *session) flush() error {
+	s.mtx.Lock()
+	announce, withdraw := s.pendingAnnounce, s.pendingWithdraw
+	s.pendingAnnounce = make(map[string]*net.IPNet)
+	s.pendingWithdraw = make(map[string]*net.IPNet)
+	s.mtx.Unlock()
+
+	for _, update := range s.updates(sortedPrefixes(announce), sortedPrefixes(withdraw)) {
+		if err := s.write(update.encode()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updates creates the UPDATE messages for the announced and withdrawn
+// prefixes. Prefixes of address families that are not supported by the
+// neighbor are skipped.
+func (s *session) updates(announce, withdraw []*net.IPNet) []*updateMessage {
+	var updates []*updateMessage
+	for _, prefixes := range batch(s.filter(withdraw)) {
+		updates = append(updates, &updateMessage{Withdrawn: prefixes})
+	}
+	announce = s.filter(announce)
+	if len(announce) == 0 {
+		return updates
+	}
+	template := updateMessage{
+		NextHop:     s.nextHop(s.speaker.NextHopIPv4, true),
+		NextHopIPv6: s.nextHop(s.speaker.NextHopIPv6, false),
+	}
+	if s.neighbor.AS == s.speaker.LocalAS {
+		template.LocalPref = defaultLocalPref
+	} else {
+		template.ASPath = []uint32{s.speaker.LocalAS}
+	}
+	if template.NextHop == nil {
+		var ipv6 []*net.IPNet
+		for _, prefix := range announce {
+			if prefix.IP.To4() == nil {
+				ipv6 = append(ipv6, prefix)
+			}
+		}
+		if len(ipv6) != len(announce) {
+			log.Info("Not announcing IPv4 prefixes to BGP neighbor, no IPv4 next hop",
+				"neighbor", s.neighbor.Address)
+		}
+		announce = ipv6
+	}
+	for _, prefixes := range batch(announce) {
+		update := template
+		update.Announced = prefixes
+		updates = append(updates, &update)
+	}
+	return updates
+}
+
+func (s *session) filter(prefixes []*net.IPNet) []*net.IPNet {
+	var filtered []*net.IPNet
+	for _, prefix := range prefixes {
+		if ipv4 := prefix.IP.To4() != nil; (ipv4 && s.ipv4) || (!ipv4 && s.ipv6) {
+			filtered = append(filtered, prefix)
+		}
+	}
+	return filtered
+}
+
+// nextHop returns the configured next hop, or the local address of the
+// session in the requested address family.
+func (s *session) nextHop(configured net.IP, ipv4 bool) net.IP {
+	if configured != nil {
+		return configured
+	}
+	local, ok := s.conn.LocalAddr().(*net.TCPAddr)
+	if !ok {
+		return nil
+	}
+	if ipv4 {
+		return local.IP.To4()
+	}
+	// IPv4 addresses are returned as IPv4-mapped IPv6 addresses.
+	return local.IP.To16()
+}
+
+func (s *session) write(msg []byte) error {
+	if _, err := s.conn.Write(msg); err != nil {
+		return serrors.WrapStr("writing BGP message", err)
+	}
+	return nil
+}
+
+// terminate terminates the session. If the error must be signaled to the
+// neighbor, it is sent in a NOTIFICATION message. Only the first call has an
+// effect.
+func (s *session) terminate(err error) {
+	s.closeOnce.Do(func() {
+		if e, ok := err.(*notificationError); ok && !e.received {
+			s.conn.SetWriteDeadline(time.Now().Add(time.Second))
+			s.conn.Write(encodeNotification(e))
+		}
+		s.conn.Close()
+		close(s.closeChan)
+	})
+}
+
+func batch(prefixes []*net.IPNet) [][]*net.IPNet {
+	var batches [][]*net.IPNet
+	for len(prefixes) > 0 {
+		n := len(prefixes)
+		if n > maxPrefixesPerUpdate {
+			n = maxPrefixesPerUpdate
+		}
+		batches = append(batches, prefixes[:n])
+		prefixes = prefixes[n:]
+	}
+	return batches
+}
+
+func sortedPrefixes(prefixes map[string]*net.IPNet) []*net.IPNet {
+	sorted := make([]*net.IPNet, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		sorted = append(sorted, prefix)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if c := bytes.Compare(sorted[i].IP.To16(), sorted[j].IP.To16()); c != 0 {
+			return c < 0
+		}
+		a, _ := sorted[i].Mask.Size()
+		b, _ := sorted[j].Mask.Size()
+		return a < b
+	})
+	return sorted
+}
@@ -0,0 +1,427 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bgp implements a minimal embedded BGP-4 speaker that can be used as a
+// route management backend.
+//
+// The speaker announces all routes that are added through its publishers to
+// its BGP neighbors, with itself as the next hop. Routes learned from the BGP
+// neighbors are passed on to the consumers of the speaker. The speaker
+// supports IPv4 and IPv6 unicast routes and requires 4-octet AS number support
+// from its neighbors. It does not do any best path selection and it never
+// re-announces routes learned from one neighbor to another.
+//
+// The speaker is deliberately small instead of embedding a full BGP daemon such
+// as GoBGP. The gateway only needs to exchange unicast prefixes with a few
+// statically configured neighbors, and GoBGP would add a large dependency tree
+// (its gRPC API, configuration and policy packages) to the gateway binary.
+// Features beyond the above, e.g., route policies, graceful restart or route
+// reflection, are out of scope; deployments that need them should run a
+// dedicated routing daemon next to the gateway. Interoperability with BIRD is
+// covered by TestInteropBIRD, which runs when BIRD is installed.
+package bgp
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/routemgr"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+const (
+	// DefaultPort is the default TCP port of BGP.
+	DefaultPort = 179
+	// DefaultHoldTime is the default hold time proposed to the neighbors.
+	DefaultHoldTime = 90 * time.Second
+	// DefaultConnectRetryInterval is the default interval between connection
+	// attempts to a neighbor.
+	DefaultConnectRetryInterval = 30 * time.Second
+
+	dialTimeout = 10 * time.Second
+)
+
+// Neighbor is a configured BGP neighbor.
+type Neighbor struct {
+	// Address is the TCP address of the neighbor.
+	Address *net.TCPAddr
+	// AS is the AS number of the neighbor. If it is equal to the local AS, the
+	// session is an iBGP session.
+	AS uint32
+	// Passive indicates that the speaker does not initiate connections to this
+	// neighbor, but only accepts connections from it.
+	Passive bool
+}
+
+// Speaker is an embedded BGP speaker. It is a route management backend: routes
+// published via NewPublisher are announced to the neighbors, and routes learned
+// from the neighbors are received via NewConsumer.
+type Speaker struct {
+	// LocalAS is the AS number of the speaker.
+	LocalAS uint32
+	// RouterID is the BGP identifier of the speaker. It must be an IPv4
+	// address.
+	RouterID net.IP
+	// ListenAddr is the address on which connections from neighbors are
+	// accepted. If nil, the speaker only initiates connections.
+	ListenAddr *net.TCPAddr
+	// Neighbors is the list of BGP neighbors.
+	Neighbors []Neighbor
+	// HoldTime is the hold time proposed to the neighbors. If zero,
+	// DefaultHoldTime is used.
+	HoldTime time.Duration
+	// ConnectRetryInterval is the interval between connection attempts to a
+	// neighbor. If zero, DefaultConnectRetryInterval is used.
+	ConnectRetryInterval time.Duration
+	// NextHopIPv4 is the next hop announced for IPv4 routes. If nil, the local
+	// address of the BGP session is used.
+	NextHopIPv4 net.IP
+	// NextHopIPv6 is the next hop announced for IPv6 routes. If nil, the local
+	// address of the BGP session is used.
+	NextHopIPv6 net.IP
+
+	initOnce sync.Once
+	// exportedRoutes stores the routes published by the local process.
+	exportedRoutes routemgr.RouteDB
+	// importedRoutes stores the routes learned from the neighbors.
+	importedRoutes routemgr.RouteDB
+
+	mtx       sync.Mutex
+	exported  map[string]*exportedPrefix
+	neighbors []*neighbor
+	listener  net.Listener
+	closeChan chan struct{}
+	closed    bool
+}
+
+type exportedPrefix struct {
+	prefix   *net.IPNet
+	refCount int
+}
+
+// neighbor is the state of a configured neighbor.
+type neighbor struct {
+	Neighbor
+	// active is the session that won the collision resolution. It is nil if
+	// there is no session with the neighbor.
+	active *session
+}
+
+func (s *Speaker) init() {
+	s.initOnce.Do(func() {
+		s.mtx.Lock()
+		defer s.mtx.Unlock()
+		s.exported = make(map[string]*exportedPrefix)
+		s.closeChan = make(chan struct{})
+		for _, n := range s.Neighbors {
+			s.neighbors = append(s.neighbors, &neighbor{Neighbor: n})
+		}
+	})
+}
+
+// NewPublisher returns a publisher for routes that are announced to the
+// neighbors.
+func (s *Speaker) NewPublisher() routemgr.Publisher {
+	return s.exportedRoutes.NewPublisher()
+}
+
+// NewConsumer returns a consumer for the routes learned from the neighbors.
+func (s *Speaker) NewConsumer() routemgr.Consumer {
+	return s.importedRoutes.NewConsumer()
+}
+
+// Diagnostics returns the routes that are announced to the neighbors.
+func (s *Speaker) Diagnostics() routemgr.Diagnostics {
+	return s.exportedRoutes.Diagnostics()
+}
+
+// Run runs the speaker. It blocks until the speaker is closed or the listener
+// cannot be created.
+func (s *Speaker) Run() error {
+	s.init()
+	if err := s.validate(); err != nil {
+		return err
+	}
+	if s.ListenAddr != nil {
+		listener, err := net.ListenTCP("tcp", s.ListenAddr)
+		if err != nil {
+			return serrors.WrapStr("listening for BGP connections", err, "addr", s.ListenAddr)
+		}
+		s.mtx.Lock()
+		s.listener = listener
+		s.mtx.Unlock()
+		go func() {
+			defer log.HandlePanic()
+			s.accept(listener)
+		}()
+	}
+	go func() {
+		defer log.HandlePanic()
+		s.exportedRoutes.Run()
+	}()
+	go func() {
+		defer log.HandlePanic()
+		s.importedRoutes.Run()
+	}()
+	for _, n := range s.neighbors {
+		if n.Passive {
+			continue
+		}
+		n := n
+		go func() {
+			defer log.HandlePanic()
+			s.connect(n)
+		}()
+	}
+
+	consumer := s.exportedRoutes.NewConsumer()
+	for {
+		select {
+		case update := <-consumer.Updates():
+			s.export(update)
+		case <-s.closeChan:
+			consumer.Close()
+			s.exportedRoutes.Close()
+			s.importedRoutes.Close()
+			return nil
+		}
+	}
+}
+
+// Close shuts down the speaker and terminates all BGP sessions.
+func (s *Speaker) Close() {
+	s.init()
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.closeChan)
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	for _, n := range s.neighbors {
+		if n.active != nil {
+			n.active.terminate(
+				newNotificationError(errCease, errSubAdminShutdown, "speaker closed"))
+		}
+	}
+}
+
+func (s *Speaker) validate() error {
+	if s.LocalAS == 0 {
+		return serrors.New("local AS not set")
+	}
+	if s.RouterID.To4() == nil || s.RouterID.To4().Equal(net.IPv4zero) {
+		return serrors.New("router ID must be a non-zero IPv4 address", "router_id", s.RouterID)
+	}
+	for _, n := range s.Neighbors {
+		if n.Address == nil || n.AS == 0 {
+			return serrors.New("neighbor address and AS must be set", "neighbor", n.Address)
+		}
+	}
+	return nil
+}
+
+func (s *Speaker) holdTime() time.Duration {
+	if s.HoldTime == 0 {
+		return DefaultHoldTime
+	}
+	return s.HoldTime
+}
+
+func (s *Speaker) connectRetryInterval() time.Duration {
+	if s.ConnectRetryInterval == 0 {
+		return DefaultConnectRetryInterval
+	}
+	return s.ConnectRetryInterval
+}
+
+// connect periodically initiates a connection to the neighbor if there is no
+// session with it.
+func (s *Speaker) connect(n *neighbor) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		defer log.HandlePanic()
+		select {
+		case <-s.closeChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(s.connectRetryInterval())
+	defer ticker.Stop()
+	for {
+		if !s.hasSession(n) {
+			dialer := net.Dialer{Timeout: dialTimeout}
+			conn, err := dialer.DialContext(ctx, "tcp", n.Address.String())
+			if err != nil {
+				log.Debug("Failed to connect to BGP neighbor", "neighbor", n.Address, "err", err)
+			} else {
+				s.runSession(newSession(s, n, conn, true))
+			}
+		}
+		select {
+		case <-s.closeChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// accept accepts connections from configured neighbors until the listener is
+// closed.
+func (s *Speaker) accept(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case <-s.closeChan:
+			default:
+				log.Error("Accepting BGP connections failed", "err", err)
+			}
+			return
+		}
+		n := s.findNeighbor(conn.RemoteAddr())
+		if n == nil {
+			log.Info("Rejecting BGP connection from unknown neighbor", "remote", conn.RemoteAddr())
+			conn.Close()
+			continue
+		}
+		go func() {
+			defer log.HandlePanic()
+			s.runSession(newSession(s, n, conn, false))
+		}()
+	}
+}
+
+func (s *Speaker) findNeighbor(remote net.Addr) *neighbor {
+	tcp, ok := remote.(*net.TCPAddr)
+	if !ok {
+		return nil
+	}
+	for _, n := range s.neighbors {
+		if n.Address.IP.Equal(tcp.IP) {
+			return n
+		}
+	}
+	return nil
+}
+
+func (s *Speaker) runSession(sess *session) {
+	logger := log.New("neighbor", sess.neighbor.Address, "outgoing", sess.outgoing)
+	err := sess.run()
+	logger.Info("BGP session terminated", "err", err)
+}
+
+func (s *Speaker) hasSession(n *neighbor) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return n.active != nil
+}
+
+// register registers the session with its neighbor after the OPEN messages
+// have been exchanged. If there is another session with the same neighbor, the
+// collision is resolved according to RFC 4271, Section 6.8.
+func (s *Speaker) register(sess *session) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.closed {
+		return newNotificationError(errCease, errSubAdminShutdown, "speaker closed")
+	}
+	n := sess.neighbor
+	if other := n.active; other != nil {
+		// The connection initiated by the speaker with the higher BGP
+		// identifier is kept.
+		localHigher := ipLess(sess.remoteID, s.RouterID)
+		collision := newNotificationError(errCease, errSubCollision, "connection collision")
+		if other.established || localHigher != sess.outgoing {
+			return collision
+		}
+		other.terminate(collision)
+	}
+	n.active = sess
+	return nil
+}
+
+// unregister removes the session from its neighbor.
+func (s *Speaker) unregister(sess *session) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if sess.neighbor.active == sess {
+		sess.neighbor.active = nil
+	}
+}
+
+// establish marks the session as established and schedules the announcement
+// of all exported prefixes.
+func (s *Speaker) establish(sess *session) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	sess.setEstablished()
+	for _, e := range s.exported {
+		sess.announce(e.prefix)
+	}
+}
+
+// export processes an update of the exported routes. A prefix is announced as
+// long as at least one route for it exists.
+func (s *Speaker) export(update routemgr.RouteUpdate) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	key := update.Prefix.String()
+	e, ok := s.exported[key]
+	if update.IsAdd {
+		if ok {
+			e.refCount++
+			return
+		}
+		s.exported[key] = &exportedPrefix{prefix: update.Prefix, refCount: 1}
+		s.forEachEstablished(func(sess *session) { sess.announce(update.Prefix) })
+		return
+	}
+	if !ok {
+		return
+	}
+	e.refCount--
+	if e.refCount > 0 {
+		return
+	}
+	delete(s.exported, key)
+	s.forEachEstablished(func(sess *session) { sess.withdraw(update.Prefix) })
+}
+
+func (s *Speaker) forEachEstablished(f func(*session)) {
+	for _, n := range s.neighbors {
+		if n.active != nil && n.active.established {
+			f(n.active)
+		}
+	}
+}
+
+func ipLess(a, b net.IP) bool {
+	a, b = a.To4(), b.To4()
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bgp_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/routemgr"
+	"github.com/scionproto/scion/go/lib/routemgr/bgp"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestSpeakers(t *testing.T) {
+	addrA := freeAddr(t)
+	a := &bgp.Speaker{
+		LocalAS:    65001,
+		RouterID:   net.ParseIP("192.0.2.1"),
+		ListenAddr: addrA,
+		Neighbors: []bgp.Neighbor{
+			{Address: &net.TCPAddr{IP: net.ParseIP("127.0.0.1")}, AS: 65002, Passive: true},
+		},
+	}
+	b := &bgp.Speaker{
+		LocalAS:  65002,
+		RouterID: net.ParseIP("192.0.2.2"),
+		Neighbors: []bgp.Neighbor{
+			{Address: addrA, AS: 65001},
+		},
+		ConnectRetryInterval: 10 * time.Millisecond,
+	}
+	// Routes published before the session is established must be announced.
+	pubA := a.NewPublisher()
+	pubA.AddRoute(routemgr.Route{Prefix: xtest.MustParseCIDR(t, "10.1.0.0/16")})
+	consB := b.NewConsumer()
+	consA := a.NewConsumer()
+
+	run(t, a)
+	run(t, b)
+	defer a.Close()
+	defer b.Close()
+
+	update := receive(t, consB)
+	assert.Equal(t, routemgr.RouteUpdate{
+		IsAdd: true,
+		Route: routemgr.Route{
+			Prefix:  xtest.MustParseCIDR(t, "10.1.0.0/16"),
+			NextHop: net.ParseIP("127.0.0.1").To4(),
+		},
+	}, canonical(update))
+
+	// Routes published after the session is established are announced.
+	pubB := b.NewPublisher()
+	pubB.AddRoute(routemgr.Route{Prefix: xtest.MustParseCIDR(t, "2001:db8::/32")})
+	update = receive(t, consA)
+	assert.True(t, update.IsAdd)
+	assert.Equal(t, xtest.MustParseCIDR(t, "2001:db8::/32"), update.Prefix)
+	assert.Equal(t, net.ParseIP("::ffff:127.0.0.1"), update.NextHop)
+
+	// Deleted routes are withdrawn.
+	pubA.DeleteRoute(routemgr.Route{Prefix: xtest.MustParseCIDR(t, "10.1.0.0/16")})
+	update = receive(t, consB)
+	assert.False(t, update.IsAdd)
+	assert.Equal(t, xtest.MustParseCIDR(t, "10.1.0.0/16").String(), update.Prefix.String())
+
+	// Routes learned from a neighbor are retracted when the session terminates.
+	b.Close()
+	update = receive(t, consA)
+	assert.False(t, update.IsAdd)
+	assert.Equal(t, xtest.MustParseCIDR(t, "2001:db8::/32"), update.Prefix)
+}
+
+func TestSpeakerRejectsUnexpectedAS(t *testing.T) {
+	addrA := freeAddr(t)
+	a := &bgp.Speaker{
+		LocalAS:    65001,
+		RouterID:   net.ParseIP("192.0.2.1"),
+		ListenAddr: addrA,
+		Neighbors: []bgp.Neighbor{
+			{Address: &net.TCPAddr{IP: net.ParseIP("127.0.0.1")}, AS: 65003, Passive: true},
+		},
+	}
+	b := &bgp.Speaker{
+		LocalAS:  65002,
+		RouterID: net.ParseIP("192.0.2.2"),
+		Neighbors: []bgp.Neighbor{
+			{Address: addrA, AS: 65001},
+		},
+		ConnectRetryInterval: 10 * time.Millisecond,
+	}
+	pubA := a.NewPublisher()
+	pubA.AddRoute(routemgr.Route{Prefix: xtest.MustParseCIDR(t, "10.1.0.0/16")})
+	consB := b.NewConsumer()
+
+	run(t, a)
+	run(t, b)
+	defer a.Close()
+	defer b.Close()
+
+	select {
+	case update := <-consB.Updates():
+		t.Fatalf("unexpected update: %v", update)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestSpeakerValidation(t *testing.T) {
+	testCases := map[string]*bgp.Speaker{
+		"no local AS": {
+			RouterID: net.ParseIP("192.0.2.1"),
+		},
+		"IPv6 router ID": {
+			LocalAS:  65001,
+			RouterID: net.ParseIP("2001:db8::1"),
+		},
+		"neighbor without AS": {
+			LocalAS:   65001,
+			RouterID:  net.ParseIP("192.0.2.1"),
+			Neighbors: []bgp.Neighbor{{Address: &net.TCPAddr{IP: net.ParseIP("127.0.0.1")}}},
+		},
+	}
+	for name, s := range testCases {
+		name, s := name, s
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, s.Run())
+		})
+	}
+}
+
+func run(t *testing.T, s *bgp.Speaker) {
+	go func() {
+		defer log.HandlePanic()
+		assert.NoError(t, s.Run())
+	}()
+}
+
+func receive(t *testing.T, c routemgr.Consumer) routemgr.RouteUpdate {
+	select {
+	case update := <-c.Updates():
+		return update
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for route update")
+	}
+	return routemgr.RouteUpdate{}
+}
+
+func canonical(u routemgr.RouteUpdate) routemgr.RouteUpdate {
+	if v4 := u.Prefix.IP.To4(); v4 != nil {
+		u.Prefix = &net.IPNet{IP: v4, Mask: u.Prefix.Mask}
+	}
+	return u
+}
+
+// freeAddr returns a local TCP address with a port that is currently unused.
+func freeAddr(t *testing.T) *net.TCPAddr {
+	l, err := net.ListenTCP("tcp", &net.TCPAddr{IP: net.ParseIP("127.0.0.1")})
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr)
+}
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/scionproto/scion/go/lib/routemgr (interfaces: Publisher,PublisherFactory)
+// Source: github.com/scionproto/scion/go/lib/routemgr (interfaces: Publisher,PublisherFactory,Consumer)
 
 // Package mock_routemgr is a generated GoMock package.
 package mock_routemgr
@@ -105,3 +105,52 @@ func (mr *MockPublisherFactoryMockRecorder) NewPublisher() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewPublisher", reflect.TypeOf((*MockPublisherFactory)(nil).NewPublisher))
 }
+
+// MockConsumer is a mock of Consumer interface
+type MockConsumer struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsumerMockRecorder
+}
+
+// MockConsumerMockRecorder is the mock recorder for MockConsumer
+type MockConsumerMockRecorder struct {
+	mock *MockConsumer
+}
+
+// NewMockConsumer creates a new mock instance
+func NewMockConsumer(ctrl *gomock.Controller) *MockConsumer {
+	mock := &MockConsumer{ctrl: ctrl}
+	mock.recorder = &MockConsumerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockConsumer) EXPECT() *MockConsumerMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockConsumer) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close
+func (mr *MockConsumerMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockConsumer)(nil).Close))
+}
+
+// Updates mocks base method
+func (m *MockConsumer) Updates() <-chan routemgr.RouteUpdate {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Updates")
+	ret0, _ := ret[0].(<-chan routemgr.RouteUpdate)
+	return ret0
+}
+
+// Updates indicates an expected call of Updates
+func (mr *MockConsumerMockRecorder) Updates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Updates", reflect.TypeOf((*MockConsumer)(nil).Updates))
+}
@@ -0,0 +1,71 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routemgr
+
+// MultiPublisherFactory is a publisher factory that exports routes to multiple
+// routing backends, e.g., to the Linux kernel and to BGP.
+type MultiPublisherFactory []PublisherFactory
+
+func (f MultiPublisherFactory) NewPublisher() Publisher {
+	p := make(multiPublisher, 0, len(f))
+	for _, factory := range f {
+		p = append(p, factory.NewPublisher())
+	}
+	return p
+}
+
+// Diagnostics returns the union of the routes of all backends that provide
+// diagnostics.
+func (f MultiPublisherFactory) Diagnostics() Diagnostics {
+	routes := make(map[string]Route)
+	for _, factory := range f {
+		d, ok := factory.(interface{ Diagnostics() Diagnostics })
+		if !ok {
+			continue
+		}
+		for _, r := range d.Diagnostics().Routes {
+			routes[makeKey(r.Prefix, r.NextHop)] = r
+		}
+	}
+	if len(routes) == 0 {
+		return Diagnostics{}
+	}
+	d := Diagnostics{Routes: make([]Route, 0, len(routes))}
+	for _, r := range routes {
+		d.Routes = append(d.Routes, r)
+	}
+	sortRoutes(d.Routes)
+	return d
+}
+
+type multiPublisher []Publisher
+
+func (p multiPublisher) AddRoute(route Route) {
+	for _, pub := range p {
+		pub.AddRoute(route)
+	}
+}
+
+func (p multiPublisher) DeleteRoute(route Route) {
+	for _, pub := range p {
+		pub.DeleteRoute(route)
+	}
+}
+
+func (p multiPublisher) Close() {
+	for _, pub := range p {
+		pub.Close()
+	}
+}
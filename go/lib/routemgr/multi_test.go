@@ -0,0 +1,43 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routemgr
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiPublisherFactory(t *testing.T) {
+	db1, db2 := createRouteDB(), createRouteDB()
+	defer db1.Close()
+	defer db2.Close()
+	cons1, cons2 := db1.NewConsumer(), db2.NewConsumer()
+
+	f := MultiPublisherFactory{db1, db2, &Dummy{}}
+	pub := f.NewPublisher()
+	_, prefix, _ := net.ParseCIDR("192.168.0.0/24")
+	route := Route{Prefix: prefix, NextHop: net.ParseIP("10.11.12.13")}
+
+	pub.AddRoute(route)
+	assert.Equal(t, RouteUpdate{IsAdd: true, Route: route}, <-cons1.Updates())
+	assert.Equal(t, RouteUpdate{IsAdd: true, Route: route}, <-cons2.Updates())
+	assert.Equal(t, Diagnostics{Routes: []Route{route}}, f.Diagnostics())
+
+	pub.DeleteRoute(route)
+	assert.Equal(t, RouteUpdate{IsAdd: false, Route: route}, <-cons1.Updates())
+	assert.Equal(t, RouteUpdate{IsAdd: false, Route: route}, <-cons2.Updates())
+}
@@ -34,6 +34,11 @@ type PathReqFlags struct {
 type ASInfo struct {
 	IA  addr.IA
 	MTU uint16
+	// DispatchedPortStart and DispatchedPortEnd are the inclusive range of end
+	// host ports that routers deliver to directly. Both are zero if the AS
+	// does not support dispatcher-less applications.
+	DispatchedPortStart uint16
+	DispatchedPortEnd   uint16
 }
 
 type Querier struct {
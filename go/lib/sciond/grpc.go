@@ -104,8 +104,10 @@ func (c grpcConn) ASInfo(ctx context.Context, ia addr.IA) (ASInfo, error) {
 	}
 	c.metrics.incAS(nil)
 	return ASInfo{
-		IA:  addr.IAInt(response.IsdAs).IA(),
-		MTU: uint16(response.Mtu),
+		IA:                  addr.IAInt(response.IsdAs).IA(),
+		MTU:                 uint16(response.Mtu),
+		DispatchedPortStart: uint16(response.DispatchedPortStart),
+		DispatchedPortEnd:   uint16(response.DispatchedPortEnd),
 	}, nil
 }
 
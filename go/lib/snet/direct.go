@@ -0,0 +1,101 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"context"
+	"math/rand"
+	"net"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+var _ PacketDispatcherService = (*DirectPacketDispatcherService)(nil)
+
+// DirectPacketDispatcherService opens UDP sockets directly on the underlay,
+// bypassing the dispatcher. The ports are allocated from the dispatched port
+// range of the local AS, to which the routers deliver packets directly. SCMP
+// messages are delivered to the socket they refer to and handled in-process
+// by the SCMPHandler.
+type DirectPacketDispatcherService struct {
+	// StartPort and EndPort are the inclusive range of ports that can be
+	// allocated.
+	StartPort uint16
+	EndPort   uint16
+	// SCMPHandler is invoked for packets that contain an SCMP L4. If the
+	// handler is nil, errors are returned back to applications every time an
+	// SCMP message is received.
+	SCMPHandler SCMPHandler
+}
+
+// Register opens a UDP socket on the registration address. If the port of
+// the registration address is 0, a free port in the configured range is
+// chosen. SVC registrations are not supported, because SVC traffic is always
+// delivered to the dispatcher.
+func (s *DirectPacketDispatcherService) Register(ctx context.Context, ia addr.IA,
+	registration *net.UDPAddr, svc addr.HostSVC) (PacketConn, uint16, error) {
+
+	if s.StartPort == 0 || s.StartPort > s.EndPort {
+		return nil, 0, serrors.New("invalid port range",
+			"start", s.StartPort, "end", s.EndPort)
+	}
+	if registration == nil {
+		return nil, 0, serrors.New("nil registration address")
+	}
+	if svc != addr.SvcNone {
+		return nil, 0, serrors.New("SVC registration not supported", "svc", svc)
+	}
+	if registration.Port != 0 {
+		if registration.Port < int(s.StartPort) || registration.Port > int(s.EndPort) {
+			return nil, 0, serrors.New("port outside of dispatched range",
+				"port", registration.Port, "start", s.StartPort, "end", s.EndPort)
+		}
+		conn, err := s.listen(registration, registration.Port)
+		if err != nil {
+			return nil, 0, err
+		}
+		return conn, uint16(registration.Port), nil
+	}
+	size := int(s.EndPort) - int(s.StartPort) + 1
+	offset := rand.Intn(size)
+	for i := 0; i < size; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, 0, err
+		}
+		port := int(s.StartPort) + (offset+i)%size
+		conn, err := s.listen(registration, port)
+		if err != nil {
+			continue
+		}
+		return conn, uint16(port), nil
+	}
+	return nil, 0, serrors.New("no free port in dispatched range",
+		"start", s.StartPort, "end", s.EndPort)
+}
+
+func (s *DirectPacketDispatcherService) listen(registration *net.UDPAddr,
+	port int) (PacketConn, error) {
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{
+		IP:   registration.IP,
+		Port: port,
+		Zone: registration.Zone,
+	})
+	if err != nil {
+		return nil, serrors.WrapStr("opening underlay socket", err, "port", port)
+	}
+	return NewSCIONPacketConn(conn, s.SCMPHandler, true), nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+const (
+	testStartPort = 31000
+	testEndPort   = 31999
+)
+
+func TestDirectPacketDispatcherServiceRegister(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	localhost := net.IPv4(127, 0, 0, 1)
+	s := &snet.DirectPacketDispatcherService{
+		StartPort: testStartPort,
+		EndPort:   testEndPort,
+	}
+
+	t.Run("random port", func(t *testing.T) {
+		conn, port, err := s.Register(context.Background(), ia,
+			&net.UDPAddr{IP: localhost}, addr.SvcNone)
+		require.NoError(t, err)
+		defer conn.Close()
+		assert.GreaterOrEqual(t, port, uint16(testStartPort))
+		assert.LessOrEqual(t, port, uint16(testEndPort))
+	})
+	t.Run("port in use", func(t *testing.T) {
+		conn, port, err := s.Register(context.Background(), ia,
+			&net.UDPAddr{IP: localhost}, addr.SvcNone)
+		require.NoError(t, err)
+		defer conn.Close()
+		_, _, err = s.Register(context.Background(), ia,
+			&net.UDPAddr{IP: localhost, Port: int(port)}, addr.SvcNone)
+		assert.Error(t, err)
+	})
+	t.Run("port out of range", func(t *testing.T) {
+		_, _, err := s.Register(context.Background(), ia,
+			&net.UDPAddr{IP: localhost, Port: testEndPort + 1}, addr.SvcNone)
+		assert.Error(t, err)
+	})
+	t.Run("SVC", func(t *testing.T) {
+		_, _, err := s.Register(context.Background(), ia,
+			&net.UDPAddr{IP: localhost}, addr.SvcCS)
+		assert.Error(t, err)
+	})
+	t.Run("no range", func(t *testing.T) {
+		s := &snet.DirectPacketDispatcherService{}
+		_, _, err := s.Register(context.Background(), ia,
+			&net.UDPAddr{IP: localhost}, addr.SvcNone)
+		assert.Error(t, err)
+	})
+}
+
+func TestDirectNetworkLocalDelivery(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	localhost := net.IPv4(127, 0, 0, 1)
+	n := snet.NewDirectNetwork(ia, testStartPort, testEndPort, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	server, err := n.Listen(ctx, "udp", &net.UDPAddr{IP: localhost}, addr.SvcNone)
+	require.NoError(t, err)
+	defer server.Close()
+	client, err := n.Listen(ctx, "udp", &net.UDPAddr{IP: localhost}, addr.SvcNone)
+	require.NoError(t, err)
+	defer client.Close()
+
+	dst := &snet.UDPAddr{
+		IA:   ia,
+		Host: server.LocalAddr().(*net.UDPAddr),
+	}
+	_, err = client.WriteTo([]byte("hello"), dst)
+	require.NoError(t, err)
+
+	require.NoError(t, server.SetReadDeadline(time.Now().Add(time.Second)))
+	buf := make([]byte, 16)
+	l, src, err := server.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(buf[:l]))
+	assert.Equal(t, client.LocalAddr().(*net.UDPAddr).Port,
+		src.(*snet.UDPAddr).Host.Port)
+}
@@ -28,3 +28,5 @@ func NewScionConnBase(localIA addr.IA, listen *net.UDPAddr) *scionConnBase {
 		scionNet: &SCIONNetwork{LocalIA: localIA},
 	}
 }
+
+var NewReplyPathCache = newReplyPathCache
@@ -0,0 +1,514 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package multipath implements a SCION packet connection that keeps a set of
+// paths to every remote AS it sends to. It is meant to be used as the
+// underlying connection of QUIC sessions (e.g., with squic.ConnDialer), which
+// identify the session by the connection ID and not by the path the packets
+// travel on.
+//
+// For every remote AS, the connection fetches the paths, probes them
+// periodically, and sends on the best path. It migrates to another path if the
+// active path is revoked, expires, stops answering probes, or its round trip
+// time degrades compared to the other paths. Optionally, packets are spread
+// across several paths.
+//
+// The connection only replaces regular SCION paths chosen by the caller.
+// Empty paths for AS local traffic and one-hop paths, e.g., used for
+// beaconing, are always sent as they are.
+//
+// Packets to remote addresses the connection has received packets from, but
+// never sent to first, are sent over the reversed path of the most recently
+// received packet. Thus, a server using the connection follows the path
+// migrations of its clients.
+package multipath
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/slayers/path/scion"
+	"github.com/scionproto/scion/go/lib/snet"
+)
+
+const (
+	// DefaultProbeInterval is the default interval in which the paths are
+	// probed.
+	DefaultProbeInterval = 5 * time.Second
+	// DefaultMaxProbeFailures is the default number of consecutive failed
+	// probes after which a path is considered down.
+	DefaultMaxProbeFailures = 3
+	// DefaultDegradationFactor is the default factor by which the round trip
+	// time of the active path must exceed the round trip time of the best path
+	// for the connection to migrate.
+	DefaultDegradationFactor = 2.0
+	// DefaultIdleTimeout is the default time after which the state for a
+	// remote that is no longer used is discarded.
+	DefaultIdleTimeout = 5 * time.Minute
+
+	// rankInterval is the interval after which the paths are ranked again on
+	// the write path, such that expired paths are no longer used.
+	rankInterval = time.Second
+)
+
+// Prober probes the liveness of paths.
+type Prober interface {
+	// Probe probes the paths to dst. It returns the round trip time of every
+	// path that answered, keyed by the path fingerprint.
+	Probe(ctx context.Context, dst addr.IA,
+		paths []snet.Path) (map[snet.PathFingerprint]time.Duration, error)
+}
+
+// Config is the configuration of a multipath connection.
+type Config struct {
+	// Querier is used to fetch the paths to the remote ASes. If nil, the
+	// connection sends on the path chosen by the caller, or on the reversed
+	// path of the most recently received packet.
+	Querier snet.PathQuerier
+	// Policy restricts the paths that are used. If nil, all paths are used.
+	Policy snet.PathPolicy
+	// Prober probes the paths. If nil, paths are not probed and the
+	// connection only migrates on revocation and expiration.
+	Prober Prober
+	// ProbeInterval is the interval in which the paths are probed. If zero,
+	// DefaultProbeInterval is used.
+	ProbeInterval time.Duration
+	// MaxProbeFailures is the number of consecutive failed probes after which
+	// a path is considered down. If zero, DefaultMaxProbeFailures is used.
+	MaxProbeFailures int
+	// DegradationFactor is the factor by which the round trip time of the
+	// active path must exceed the one of the best path for the connection to
+	// migrate. If zero, DefaultDegradationFactor is used.
+	DegradationFactor float64
+	// Spread is the number of paths packets are spread across in a round
+	// robin fashion. If zero or one, all packets are sent on the active path.
+	Spread int
+	// IdleTimeout is the time after which the state for a remote that is no
+	// longer used is discarded. If zero, DefaultIdleTimeout is used.
+	IdleTimeout time.Duration
+}
+
+func (cfg *Config) initDefaults() {
+	if cfg.ProbeInterval == 0 {
+		cfg.ProbeInterval = DefaultProbeInterval
+	}
+	if cfg.MaxProbeFailures == 0 {
+		cfg.MaxProbeFailures = DefaultMaxProbeFailures
+	}
+	if cfg.DegradationFactor == 0 {
+		cfg.DegradationFactor = DefaultDegradationFactor
+	}
+	if cfg.IdleTimeout == 0 {
+		cfg.IdleTimeout = DefaultIdleTimeout
+	}
+}
+
+var _ net.PacketConn = (*Conn)(nil)
+
+// Conn is a multipath SCION packet connection. See the package documentation
+// for details.
+type Conn struct {
+	conn net.PacketConn
+	cfg  Config
+
+	mtx        sync.Mutex
+	sets       map[addr.IA]*pathSet
+	replyPaths map[string]*replyPath
+
+	cancel func()
+	done   chan struct{}
+}
+
+// NewConn wraps conn, which must return and accept *snet.UDPAddr addresses,
+// in a multipath connection. The connection starts a background goroutine
+// that probes the paths. It is stopped by closing the connection.
+func NewConn(conn net.PacketConn, cfg Config) *Conn {
+	cfg.initDefaults()
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &Conn{
+		conn:       conn,
+		cfg:        cfg,
+		sets:       make(map[addr.IA]*pathSet),
+		replyPaths: make(map[string]*replyPath),
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+	go func() {
+		defer log.HandlePanic()
+		c.run(ctx)
+	}()
+	return c
+}
+
+// Paths returns the paths to the destination AS that are currently used,
+// ordered by preference. The active path is the first entry.
+func (c *Conn) Paths(ia addr.IA) []snet.Path {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	set, ok := c.sets[ia]
+	if !ok {
+		return nil
+	}
+	return append([]snet.Path(nil), set.ranked...)
+}
+
+// ReadFrom reads a packet from the connection. SCMP interface down messages
+// are handled internally and are not returned to the caller.
+func (c *Conn) ReadFrom(b []byte) (int, net.Addr, error) {
+	for {
+		n, a, err := c.conn.ReadFrom(b)
+		if err != nil {
+			var opErr *snet.OpError
+			if errors.As(err, &opErr) && opErr.RevInfo() != nil {
+				c.revoke(opErr.RevInfo())
+				continue
+			}
+			return n, a, err
+		}
+		if remote, ok := a.(*snet.UDPAddr); ok {
+			c.recordReplyPath(remote)
+		}
+		return n, a, nil
+	}
+}
+
+// WriteTo sends b to raddr on the path selected by the connection. Only
+// regular SCION paths contained in raddr are replaced by the connection, and
+// they are only used if the connection does not know any usable path to the
+// remote AS. Empty paths, one-hop paths, and other path types are always used
+// as they are.
+func (c *Conn) WriteTo(b []byte, raddr net.Addr) (int, error) {
+	remote, ok := raddr.(*snet.UDPAddr)
+	if !ok {
+		return 0, serrors.New("Unable to write to non-SCION address",
+			"addr", fmt.Sprintf("%v(%T)", raddr, raddr))
+	}
+	return c.conn.WriteTo(b, c.resolve(remote))
+}
+
+// Close stops the background goroutine and closes the underlying connection.
+func (c *Conn) Close() error {
+	c.cancel()
+	<-c.done
+	return c.conn.Close()
+}
+
+func (c *Conn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+func (c *Conn) SetDeadline(t time.Time) error {
+	return c.conn.SetDeadline(t)
+}
+
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	return c.conn.SetWriteDeadline(t)
+}
+
+// resolve returns the address including the path to send on. It never blocks
+// on fetching paths; paths for new remote ASes are fetched in the background.
+func (c *Conn) resolve(remote *snet.UDPAddr) *snet.UDPAddr {
+	// Only regular SCION paths are subject to path selection. Empty paths are
+	// used for AS local traffic, and one-hop paths, e.g., for beacons, must
+	// traverse the exact interface chosen by the caller.
+	if remote.Path.Type != scion.PathType || remote.Path.IsEmpty() {
+		return remote
+	}
+	now := time.Now()
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if reply, ok := c.replyPaths[remote.String()]; ok {
+		return reply.addr
+	}
+	if c.cfg.Querier == nil {
+		return remote
+	}
+	set, ok := c.sets[remote.IA]
+	if !ok {
+		set = &pathSet{
+			selector: &snet.PathSelector{
+				Querier:     c.cfg.Querier,
+				Policy:      c.cfg.Policy,
+				Destination: remote.IA,
+			},
+			health: make(map[snet.PathFingerprint]*pathHealth),
+		}
+		c.sets[remote.IA] = set
+	}
+	set.lastUsed = now
+	if !set.updating && (set.updated.IsZero() || now.Sub(set.rankedAt) > rankInterval) {
+		set.updating = true
+		probe := set.updated.IsZero()
+		go func() {
+			defer log.HandlePanic()
+			c.update(set, probe)
+		}()
+	}
+	p := set.pick(c.cfg.Spread)
+	if p == nil {
+		return remote
+	}
+	return &snet.UDPAddr{
+		IA:      remote.IA,
+		Host:    remote.Host,
+		Path:    p.Path(),
+		NextHop: p.UnderlayNextHop(),
+	}
+}
+
+// recordReplyPath remembers the reversed path of a received packet, unless
+// the connection selects the paths to the sender itself.
+func (c *Conn) recordReplyPath(remote *snet.UDPAddr) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if _, ok := c.sets[remote.IA]; ok {
+		return
+	}
+	c.replyPaths[remote.String()] = &replyPath{addr: remote.Copy(), lastSeen: time.Now()}
+}
+
+func (c *Conn) revoke(revInfo *path_mgmt.RevInfo) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for _, set := range c.sets {
+		if set.selector.Revoke(revInfo) {
+			log.Debug("Active path revoked", "dst", set.selector.Destination,
+				"rev_info", revInfo)
+		}
+		set.rank(c.cfg)
+	}
+}
+
+func (c *Conn) run(ctx context.Context) {
+	defer close(c.done)
+	ticker := time.NewTicker(c.cfg.ProbeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, set := range c.expire(time.Now()) {
+				c.update(set, true)
+			}
+		}
+	}
+}
+
+// expire discards the state of idle remotes. It returns the path sets that
+// are still in use and are not currently updated. The returned sets are
+// marked as updating.
+func (c *Conn) expire(now time.Time) []*pathSet {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for key, reply := range c.replyPaths {
+		if now.Sub(reply.lastSeen) > c.cfg.IdleTimeout {
+			delete(c.replyPaths, key)
+		}
+	}
+	var sets []*pathSet
+	for ia, set := range c.sets {
+		if now.Sub(set.lastUsed) > c.cfg.IdleTimeout {
+			delete(c.sets, ia)
+			continue
+		}
+		if set.updating {
+			continue
+		}
+		set.updating = true
+		sets = append(sets, set)
+	}
+	return sets
+}
+
+// update refreshes the paths of the set, if they are stale, optionally probes
+// them, and ranks them. The set must be marked as updating by the caller.
+func (c *Conn) update(set *pathSet, probe bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.ProbeInterval)
+	defer cancel()
+	dst := set.selector.Destination
+	if _, err := set.selector.Path(ctx); err != nil {
+		log.Debug("Failed to fetch paths", "dst", dst, "err", err)
+	}
+	var probed []snet.Path
+	if probe && c.cfg.Prober != nil {
+		for _, p := range set.selector.Paths() {
+			if !p.Path().IsEmpty() {
+				probed = append(probed, p)
+			}
+		}
+	}
+	if len(probed) == 0 {
+		c.mtx.Lock()
+		defer c.mtx.Unlock()
+		set.rank(c.cfg)
+		set.updated = time.Now()
+		set.updating = false
+		return
+	}
+	if set.updated.IsZero() {
+		// Use the fetched paths right away, they are ranked again once the
+		// probes are answered.
+		c.mtx.Lock()
+		set.rank(c.cfg)
+		c.mtx.Unlock()
+	}
+	rtts, err := c.cfg.Prober.Probe(ctx, dst, probed)
+	if err != nil {
+		log.Debug("Failed to probe paths", "dst", dst, "err", err)
+		probed = nil
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for _, p := range probed {
+		fp := snet.Fingerprint(p)
+		h, ok := set.health[fp]
+		if !ok {
+			h = &pathHealth{}
+			set.health[fp] = h
+		}
+		if rtt, ok := rtts[fp]; ok {
+			h.rtt, h.failures = rtt, 0
+			continue
+		}
+		h.failures++
+	}
+	set.rank(c.cfg)
+	set.updated = time.Now()
+	set.updating = false
+}
+
+type replyPath struct {
+	addr     *snet.UDPAddr
+	lastSeen time.Time
+}
+
+type pathHealth struct {
+	// rtt is the round trip time measured by the last successful probe.
+	rtt time.Duration
+	// failures is the number of consecutive failed probes.
+	failures int
+}
+
+// pathSet is the state for a remote AS. It is protected by the mutex of the
+// connection.
+type pathSet struct {
+	selector *snet.PathSelector
+	health   map[snet.PathFingerprint]*pathHealth
+	// ranked contains the paths to send on, ordered by preference.
+	ranked   []snet.Path
+	active   snet.PathFingerprint
+	rankedAt time.Time
+	next     int
+	lastUsed time.Time
+	updated  time.Time
+	updating bool
+}
+
+// pick returns the path to send the next packet on, or nil if no path is
+// known.
+func (s *pathSet) pick(spread int) snet.Path {
+	if len(s.ranked) == 0 {
+		return nil
+	}
+	if spread <= 1 {
+		return s.ranked[0]
+	}
+	if spread > len(s.ranked) {
+		spread = len(s.ranked)
+	}
+	s.next = (s.next + 1) % spread
+	return s.ranked[s.next]
+}
+
+// rank orders the usable paths by preference. Paths that failed too many
+// probes are not used, unless all paths failed. Paths are ordered by round
+// trip time; the active path stays first unless it is no longer usable or its
+// round trip time degraded.
+func (s *pathSet) rank(cfg Config) {
+	s.rankedAt = time.Now()
+	paths := s.selector.Paths()
+	type entry struct {
+		path snet.Path
+		fp   snet.PathFingerprint
+		rtt  time.Duration
+	}
+	var all, alive []entry
+	health := make(map[snet.PathFingerprint]*pathHealth, len(paths))
+	for _, p := range paths {
+		e := entry{path: p, fp: snet.Fingerprint(p)}
+		h, ok := s.health[e.fp]
+		if ok {
+			health[e.fp] = h
+			e.rtt = h.rtt
+		}
+		all = append(all, e)
+		if !ok || h.failures < cfg.MaxProbeFailures {
+			alive = append(alive, e)
+		}
+	}
+	// Forget about the paths that are no longer usable.
+	s.health = health
+	if len(alive) == 0 {
+		alive = all
+	}
+	sort.SliceStable(alive, func(i, j int) bool {
+		if alive[i].rtt == 0 || alive[j].rtt == 0 {
+			return alive[i].rtt != 0
+		}
+		return alive[i].rtt < alive[j].rtt
+	})
+	for i, e := range alive {
+		if i == 0 || e.fp != s.active {
+			continue
+		}
+		best := alive[0].rtt
+		if e.rtt == 0 || best == 0 || float64(e.rtt) <= cfg.DegradationFactor*float64(best) {
+			reordered := make([]entry, 0, len(alive))
+			reordered = append(reordered, e)
+			reordered = append(reordered, alive[:i]...)
+			alive = append(reordered, alive[i+1:]...)
+		}
+		break
+	}
+	s.ranked = s.ranked[:0]
+	for _, e := range alive {
+		s.ranked = append(s.ranked, e.path)
+	}
+	if len(alive) == 0 {
+		s.active = ""
+		return
+	}
+	if alive[0].fp != s.active {
+		if s.active != "" {
+			log.Debug("Migrating to new path", "dst", s.selector.Destination,
+				"path", alive[0].path)
+		}
+		s.active = alive[0].fp
+	}
+}
@@ -0,0 +1,391 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multipath_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	slpath "github.com/scionproto/scion/go/lib/slayers/path"
+	"github.com/scionproto/scion/go/lib/slayers/path/onehop"
+	"github.com/scionproto/scion/go/lib/slayers/path/scion"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/snet/mock_snet"
+	"github.com/scionproto/scion/go/lib/snet/multipath"
+	"github.com/scionproto/scion/go/lib/snet/path"
+	"github.com/scionproto/scion/go/lib/spath"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+var (
+	localIA  = xtest.MustParseIA("1-ff00:0:110")
+	transit  = xtest.MustParseIA("1-ff00:0:111")
+	remoteIA = xtest.MustParseIA("1-ff00:0:112")
+	nextHop  = &net.UDPAddr{IP: net.IP{127, 0, 0, 1}, Port: 30041}
+)
+
+func TestConnWriteTo(t *testing.T) {
+	short := testPath(remoteIA, nextHop, []byte{1}, localIA, 1, remoteIA, 2)
+	long := testPath(remoteIA, nextHop, []byte{2}, localIA, 3, transit, 4, transit, 5, remoteIA, 6)
+	remote := &snet.UDPAddr{
+		IA:      remoteIA,
+		Host:    &net.UDPAddr{IP: net.IP{192, 0, 2, 1}, Port: 443},
+		Path:    spath.Path{Raw: []byte{3}, Type: scion.PathType},
+		NextHop: nextHop,
+	}
+
+	testCases := map[string]struct {
+		Config   multipath.Config
+		Expected []snet.Path
+	}{
+		"no probing": {
+			Expected: []snet.Path{short, short},
+		},
+		"active path alive": {
+			Config: multipath.Config{
+				Prober: probeWith(func() map[snet.PathFingerprint]time.Duration {
+					return map[snet.PathFingerprint]time.Duration{
+						snet.Fingerprint(short): 15 * time.Millisecond,
+						snet.Fingerprint(long):  10 * time.Millisecond,
+					}
+				}),
+			},
+			Expected: []snet.Path{short, short},
+		},
+		"active path degraded": {
+			Config: multipath.Config{
+				Prober: probeWith(func() map[snet.PathFingerprint]time.Duration {
+					return map[snet.PathFingerprint]time.Duration{
+						snet.Fingerprint(short): 50 * time.Millisecond,
+						snet.Fingerprint(long):  10 * time.Millisecond,
+					}
+				}),
+			},
+			Expected: []snet.Path{long, long},
+		},
+		"active path down": {
+			Config: multipath.Config{
+				Prober: probeWith(func() map[snet.PathFingerprint]time.Duration {
+					return map[snet.PathFingerprint]time.Duration{
+						snet.Fingerprint(long): 10 * time.Millisecond,
+					}
+				}),
+				MaxProbeFailures: 1,
+			},
+			Expected: []snet.Path{long, long},
+		},
+		"all paths down": {
+			Config: multipath.Config{
+				Prober: probeWith(func() map[snet.PathFingerprint]time.Duration {
+					return nil
+				}),
+				MaxProbeFailures: 1,
+			},
+			Expected: []snet.Path{short, short},
+		},
+		"spread": {
+			Config:   multipath.Config{Spread: 2},
+			Expected: []snet.Path{long, short},
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			querier := mock_snet.NewMockPathQuerier(ctrl)
+			querier.EXPECT().Query(gomock.Any(), remoteIA).
+				Return([]snet.Path{long, short}, nil).AnyTimes()
+
+			underlay := newFakeConn()
+			tc.Config.Querier = querier
+			tc.Config.ProbeInterval = 10 * time.Millisecond
+			var probes *countingProber
+			if p, ok := tc.Config.Prober.(*countingProber); ok {
+				probes = p
+			}
+			c := multipath.NewConn(underlay, tc.Config)
+			defer c.Close()
+
+			// Without known paths, the path chosen by the caller is used.
+			_, err := c.WriteTo([]byte("hello"), remote)
+			require.NoError(t, err)
+			assert.Equal(t, remote, underlay.written())
+
+			// Wait until the paths are fetched, and the result of the first
+			// probe is applied.
+			require.Eventually(t, func() bool {
+				return len(c.Paths(remoteIA)) > 0 && (probes == nil || probes.calls() > 1)
+			}, time.Second, 10*time.Millisecond)
+			for _, expected := range tc.Expected {
+				_, err := c.WriteTo([]byte("hello"), remote)
+				require.NoError(t, err)
+				written := underlay.written()
+				assert.Equal(t, expected.Path(), written.Path)
+				assert.Equal(t, remote.Host, written.Host)
+			}
+		})
+	}
+}
+
+func TestConnWriteToFixedPath(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	host := &net.UDPAddr{IP: net.IP{192, 0, 2, 1}, Port: 443}
+	testCases := map[string]*snet.UDPAddr{
+		"empty path": {
+			IA:   localIA,
+			Host: host,
+		},
+		"one-hop path": {
+			IA:      remoteIA,
+			Host:    host,
+			Path:    spath.Path{Raw: []byte{4}, Type: onehop.PathType},
+			NextHop: nextHop,
+		},
+	}
+	for name, remote := range testCases {
+		remote := remote
+		t.Run(name, func(t *testing.T) {
+			// The paths are never fetched, and neither the known paths nor the
+			// reply paths replace the path chosen by the caller.
+			querier := mock_snet.NewMockPathQuerier(ctrl)
+			underlay := newFakeConn()
+			c := multipath.NewConn(underlay, multipath.Config{Querier: querier})
+			defer c.Close()
+
+			underlay.read <- &snet.UDPAddr{
+				IA:      remote.IA,
+				Host:    host,
+				Path:    spath.Path{Raw: []byte{1}, Type: scion.PathType},
+				NextHop: nextHop,
+			}
+			_, _, err := c.ReadFrom(make([]byte, 10))
+			require.NoError(t, err)
+			_, err = c.WriteTo([]byte("hello"), remote)
+			require.NoError(t, err)
+			assert.Equal(t, remote, underlay.written())
+			assert.Empty(t, c.Paths(remote.IA))
+		})
+	}
+}
+
+func TestConnReplyPath(t *testing.T) {
+	underlay := newFakeConn()
+	c := multipath.NewConn(underlay, multipath.Config{})
+	defer c.Close()
+
+	host := &net.UDPAddr{IP: net.IP{192, 0, 2, 1}, Port: 443}
+	for _, raw := range [][]byte{{1}, {2}} {
+		underlay.read <- &snet.UDPAddr{
+			IA:      remoteIA,
+			Host:    host,
+			Path:    spath.Path{Raw: raw, Type: scion.PathType},
+			NextHop: nextHop,
+		}
+		_, from, err := c.ReadFrom(make([]byte, 10))
+		require.NoError(t, err)
+
+		// The reply is sent on the path of the most recently received
+		// packet, irrespective of the path in the remote address.
+		_, err = c.WriteTo([]byte("hello"), &snet.UDPAddr{
+			IA:   remoteIA,
+			Host: host,
+			Path: spath.Path{Raw: []byte{3}, Type: scion.PathType},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, from, underlay.written())
+	}
+}
+
+// countingProber is a prober that counts the probe calls.
+type countingProber struct {
+	rtts func() map[snet.PathFingerprint]time.Duration
+
+	mtx   sync.Mutex
+	count int
+}
+
+func probeWith(rtts func() map[snet.PathFingerprint]time.Duration) *countingProber {
+	return &countingProber{rtts: rtts}
+}
+
+func (p *countingProber) Probe(_ context.Context, _ addr.IA,
+	_ []snet.Path) (map[snet.PathFingerprint]time.Duration, error) {
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.count++
+	return p.rtts(), nil
+}
+
+func (p *countingProber) calls() int {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.count
+}
+
+// fakeConn is a packet connection that records the written addresses and
+// returns the addresses on the read channel when reading.
+type fakeConn struct {
+	net.PacketConn
+	read chan *snet.UDPAddr
+
+	mtx    sync.Mutex
+	writes []*snet.UDPAddr
+}
+
+func newFakeConn() *fakeConn {
+	return &fakeConn{read: make(chan *snet.UDPAddr, 10)}
+}
+
+func (c *fakeConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	return 0, <-c.read, nil
+}
+
+func (c *fakeConn) WriteTo(b []byte, a net.Addr) (int, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.writes = append(c.writes, a.(*snet.UDPAddr))
+	return len(b), nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+// written returns the address of the last write.
+func (c *fakeConn) written() *snet.UDPAddr {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.writes[len(c.writes)-1]
+}
+
+// testPath creates a path with the given interfaces, which are specified as
+// alternating IA and interface ID arguments.
+func testPath(dst addr.IA, nextHop *net.UDPAddr, raw []byte, intfs ...interface{}) snet.Path {
+	var pathIntfs []snet.PathInterface
+	for i := 0; i < len(intfs); i += 2 {
+		pathIntfs = append(pathIntfs, snet.PathInterface{
+			IA: intfs[i].(addr.IA),
+			ID: common.IFIDType(intfs[i+1].(int)),
+		})
+	}
+	return path.Path{
+		Dst:     dst,
+		SPath:   spath.Path{Raw: raw},
+		NextHop: nextHop,
+		Meta: snet.PathMetadata{
+			Interfaces: pathIntfs,
+			Expiry:     time.Now().Add(time.Hour),
+		},
+	}
+}
+
+func TestConnRevocation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	localhost := net.IPv4(127, 0, 0, 1)
+	n := snet.NewDirectNetwork(localIA, 32000, 32999, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	server, err := n.Listen(ctx, "udp", &net.UDPAddr{IP: localhost}, addr.SvcNone)
+	require.NoError(t, err)
+	defer server.Close()
+	serverAddr := server.LocalAddr().(*net.UDPAddr)
+
+	// Both paths lead to the server socket, they only differ in the
+	// interfaces announced in the metadata.
+	primary := testPath(localIA, serverAddr, nil, localIA, 1, transit, 2)
+	backup := testPath(localIA, serverAddr, nil, localIA, 3, transit, 4, transit, 5, localIA, 6)
+	querier := mock_snet.NewMockPathQuerier(ctrl)
+	querier.EXPECT().Query(gomock.Any(), localIA).
+		Return([]snet.Path{backup, primary}, nil).AnyTimes()
+
+	conn, err := n.Listen(ctx, "udp", &net.UDPAddr{IP: localhost}, addr.SvcNone)
+	require.NoError(t, err)
+	client := multipath.NewConn(conn, multipath.Config{Querier: querier})
+	defer client.Close()
+	clientAddr := client.LocalAddr().(*net.UDPAddr)
+
+	remote := &snet.UDPAddr{
+		IA:      localIA,
+		Host:    serverAddr,
+		Path:    rawSCIONPath(t),
+		NextHop: serverAddr,
+	}
+	_, err = client.WriteTo([]byte("hello"), remote)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return len(client.Paths(localIA)) == 2 },
+		time.Second, 10*time.Millisecond)
+	assert.Equal(t, snet.Fingerprint(primary), snet.Fingerprint(client.Paths(localIA)[0]))
+
+	// Inject an SCMP interface down message for the active path.
+	scmpSender := &snet.DirectPacketDispatcherService{StartPort: 32000, EndPort: 32999}
+	scmpConn, _, err := scmpSender.Register(ctx, localIA, &net.UDPAddr{IP: localhost},
+		addr.SvcNone)
+	require.NoError(t, err)
+	defer scmpConn.Close()
+	require.NoError(t, scmpConn.WriteTo(&snet.Packet{
+		PacketInfo: snet.PacketInfo{
+			Destination: snet.SCIONAddress{IA: localIA, Host: addr.HostFromIP(localhost)},
+			Source:      snet.SCIONAddress{IA: localIA, Host: addr.HostFromIP(localhost)},
+			Payload:     snet.SCMPExternalInterfaceDown{IA: transit, Interface: 2},
+		},
+	}, clientAddr))
+
+	buf := make([]byte, 16)
+	require.NoError(t, server.SetDeadline(time.Now().Add(2*time.Second)))
+	require.NoError(t, client.SetDeadline(time.Now().Add(2*time.Second)))
+	_, from, err := server.ReadFrom(buf)
+	require.NoError(t, err)
+	_, err = server.WriteTo([]byte("world"), from)
+	require.NoError(t, err)
+
+	// The SCMP message is consumed, and the client migrates to the backup
+	// path.
+	l, _, err := client.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "world", string(buf[:l]))
+	assert.Equal(t, []snet.Path{backup}, client.Paths(localIA))
+}
+
+// rawSCIONPath returns a valid regular SCION path, which is subject to path
+// selection by the connection.
+func rawSCIONPath(t *testing.T) spath.Path {
+	decoded := scion.Decoded{
+		Base: scion.Base{
+			PathMeta: scion.MetaHdr{SegLen: [3]uint8{2, 0, 0}},
+			NumINF:   1,
+			NumHops:  2,
+		},
+		InfoFields: []*slpath.InfoField{{ConsDir: true}},
+		HopFields:  []*slpath.HopField{{ConsEgress: 1}, {ConsIngress: 2}},
+	}
+	raw := make([]byte, decoded.Len())
+	require.NoError(t, decoded.SerializeTo(raw))
+	return spath.Path{Raw: raw, Type: scion.PathType}
+}
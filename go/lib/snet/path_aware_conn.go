@@ -0,0 +1,248 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+// DefaultPathLookupTimeout is the default timeout for fetching paths when
+// writing on a PathAwareConn.
+const DefaultPathLookupTimeout = 5 * time.Second
+
+var _ net.Conn = (*PathAwareConn)(nil)
+var _ net.PacketConn = (*PathAwareConn)(nil)
+
+// PathAwareConn is a SCION connection that selects the paths it sends on by
+// itself. The path contained in the remote address passed to WriteTo is
+// ignored:
+//
+// - If packets were received from the remote address, replies are sent over
+//   the reversed path of the most recently received packet. The reply paths
+//   of at most DefaultMaxReplyPaths remotes are kept, the least recently used
+//   ones are evicted first, and a reply path is only used for
+//   DefaultReplyPathTTL after the packet was received.
+//
+// - Otherwise, the path is chosen by a PathSelector for the remote AS. The
+//   selector fetches the paths from the querier, filters them with the
+//   policy, and fails over to another path as soon as an SCMP interface down
+//   message is received for the active path, or the active path expires.
+//
+// SCMP interface down messages are consumed by the connection and are not
+// returned to the caller of Read or ReadFrom.
+//
+// Because the path is chosen per packet, the connection can be passed to QUIC
+// (e.g., squic.ConnDialer) to get path failover for QUIC sessions.
+type PathAwareConn struct {
+	conn    *Conn
+	querier PathQuerier
+	policy  PathPolicy
+	// remote is the remote address for connections created with DialPathAware.
+	remote *UDPAddr
+
+	mtx        sync.Mutex
+	selectors  map[addr.IA]*PathSelector
+	replyPaths *replyPathCache
+}
+
+// NewPathAwareConn wraps conn in a PathAwareConn. The paths are fetched from
+// querier and filtered by policy. A nil policy allows all paths.
+func NewPathAwareConn(conn *Conn, querier PathQuerier, policy PathPolicy) *PathAwareConn {
+	return &PathAwareConn{
+		conn:       conn,
+		querier:    querier,
+		policy:     policy,
+		selectors:  make(map[addr.IA]*PathSelector),
+		replyPaths: newReplyPathCache(DefaultMaxReplyPaths, DefaultReplyPathTTL),
+	}
+}
+
+// DialPathAware returns a PathAwareConn to remote. The path and next hop of
+// remote are ignored; the paths to remote.IA are fetched from querier and
+// filtered by policy. A nil policy allows all paths.
+func (n *SCIONNetwork) DialPathAware(ctx context.Context, listen *net.UDPAddr,
+	remote *UDPAddr, querier PathQuerier, policy PathPolicy) (*PathAwareConn, error) {
+
+	if remote == nil {
+		return nil, serrors.New("Unable to dial to nil remote")
+	}
+	conn, err := n.Listen(ctx, "udp", listen, addr.SvcNone)
+	if err != nil {
+		return nil, err
+	}
+	c := NewPathAwareConn(conn, querier, policy)
+	c.remote = &UDPAddr{IA: remote.IA, Host: CopyUDPAddr(remote.Host)}
+	if _, err := c.selector(remote.IA).Path(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Selector returns the path selector for the destination AS.
+func (c *PathAwareConn) Selector(ia addr.IA) *PathSelector {
+	return c.selector(ia)
+}
+
+// ReadFrom reads a packet from the connection. SCMP interface down messages
+// are handled internally.
+func (c *PathAwareConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	for {
+		n, a, err := c.conn.ReadFrom(b)
+		if err != nil {
+			var opErr *OpError
+			if errors.As(err, &opErr) && opErr.RevInfo() != nil {
+				c.revoke(opErr.RevInfo())
+				continue
+			}
+			return n, a, err
+		}
+		if remote, ok := a.(*UDPAddr); ok {
+			c.recordReplyPath(remote)
+		}
+		return n, a, nil
+	}
+}
+
+// Read reads a packet from the connection.
+func (c *PathAwareConn) Read(b []byte) (int, error) {
+	n, _, err := c.ReadFrom(b)
+	return n, err
+}
+
+// WriteTo sends b to raddr on the path selected by the connection.
+func (c *PathAwareConn) WriteTo(b []byte, raddr net.Addr) (int, error) {
+	remote, ok := raddr.(*UDPAddr)
+	if !ok {
+		return 0, serrors.New("Unable to write to non-SCION address",
+			"addr", fmt.Sprintf("%v(%T)", raddr, raddr))
+	}
+	dst, err := c.resolve(remote)
+	if err != nil {
+		return 0, err
+	}
+	return c.conn.WriteTo(b, dst)
+}
+
+// Write sends b to the remote address of a connection created with
+// DialPathAware.
+func (c *PathAwareConn) Write(b []byte) (int, error) {
+	if c.remote == nil {
+		return 0, serrors.New("Unable to Write to connection without remote address")
+	}
+	return c.WriteTo(b, c.remote)
+}
+
+func (c *PathAwareConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *PathAwareConn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+// RemoteAddr returns the remote address of a connection created with
+// DialPathAware, and nil otherwise.
+func (c *PathAwareConn) RemoteAddr() net.Addr {
+	if c.remote == nil {
+		return nil
+	}
+	return c.remote
+}
+
+func (c *PathAwareConn) SetDeadline(t time.Time) error {
+	return c.conn.SetDeadline(t)
+}
+
+func (c *PathAwareConn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+func (c *PathAwareConn) SetWriteDeadline(t time.Time) error {
+	return c.conn.SetWriteDeadline(t)
+}
+
+// resolve returns the address including the path to send on.
+func (c *PathAwareConn) resolve(remote *UDPAddr) (*UDPAddr, error) {
+	c.mtx.Lock()
+	reply, ok := c.replyPaths.Get(remote.String(), time.Now())
+	c.mtx.Unlock()
+	if ok {
+		return reply, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultPathLookupTimeout)
+	defer cancel()
+	path, err := c.selector(remote.IA).Path(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &UDPAddr{
+		IA:      remote.IA,
+		Host:    remote.Host,
+		Path:    path.Path(),
+		NextHop: path.UnderlayNextHop(),
+	}, nil
+}
+
+// recordReplyPath remembers the reversed path of a received packet, unless
+// the connection selects the paths to the sender itself.
+func (c *PathAwareConn) recordReplyPath(remote *UDPAddr) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if _, ok := c.selectors[remote.IA]; ok {
+		return
+	}
+	c.replyPaths.Put(remote.String(), remote.Copy(), time.Now())
+}
+
+func (c *PathAwareConn) selector(ia addr.IA) *PathSelector {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	s, ok := c.selectors[ia]
+	if !ok {
+		s = &PathSelector{
+			Querier:     c.querier,
+			Policy:      c.policy,
+			Destination: ia,
+		}
+		c.selectors[ia] = s
+	}
+	return s
+}
+
+func (c *PathAwareConn) revoke(revInfo *path_mgmt.RevInfo) {
+	c.mtx.Lock()
+	selectors := make([]*PathSelector, 0, len(c.selectors))
+	for _, s := range c.selectors {
+		selectors = append(selectors, s)
+	}
+	c.mtx.Unlock()
+	for _, s := range selectors {
+		if s.Revoke(revInfo) {
+			log.Debug("Active path revoked, switching path", "dst", s.Destination,
+				"rev_info", revInfo)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/snet/mock_snet"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestPathAwareConn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	other := xtest.MustParseIA("1-ff00:0:111")
+	localhost := net.IPv4(127, 0, 0, 1)
+	n := snet.NewDirectNetwork(ia, testStartPort, testEndPort, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	serverConn, err := n.Listen(ctx, "udp", &net.UDPAddr{IP: localhost}, addr.SvcNone)
+	require.NoError(t, err)
+	server := snet.NewPathAwareConn(serverConn, nil, nil)
+	defer server.Close()
+	serverAddr := server.LocalAddr().(*net.UDPAddr)
+
+	// Both paths lead to the server socket, they only differ in the
+	// interfaces announced in the metadata.
+	expiry := time.Now().Add(time.Hour)
+	primary := testPath(ia, serverAddr, expiry, ia, 1, other, 2)
+	backup := testPath(ia, serverAddr, expiry, ia, 3, other, 4, other, 5, ia, 6)
+	querier := mock_snet.NewMockPathQuerier(ctrl)
+	querier.EXPECT().Query(gomock.Any(), ia).Return([]snet.Path{backup, primary}, nil)
+
+	remote := &snet.UDPAddr{IA: ia, Host: serverAddr}
+	client, err := n.DialPathAware(ctx, &net.UDPAddr{IP: localhost}, remote, querier, nil)
+	require.NoError(t, err)
+	defer client.Close()
+	assert.Equal(t, remote, client.RemoteAddr())
+
+	buf := make([]byte, 32)
+	require.NoError(t, server.SetDeadline(time.Now().Add(2*time.Second)))
+	require.NoError(t, client.SetDeadline(time.Now().Add(2*time.Second)))
+
+	// The client sends on the selected path, the server replies on the
+	// reversed incoming path.
+	_, err = client.Write([]byte("hello"))
+	require.NoError(t, err)
+	l, from, err := server.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(buf[:l]))
+	_, err = server.WriteTo([]byte("world"), &snet.UDPAddr{
+		IA:   ia,
+		Host: from.(*snet.UDPAddr).Host,
+	})
+	require.NoError(t, err)
+	l, err = client.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "world", string(buf[:l]))
+	assert.Equal(t, snet.Fingerprint(primary),
+		snet.Fingerprint(client.Selector(ia).Paths()[0]))
+
+	// An SCMP interface down message for the active path is consumed by the
+	// client, and the client fails over to the backup path.
+	clientAddr := client.LocalAddr().(*net.UDPAddr)
+	scmp := &snet.Packet{
+		PacketInfo: snet.PacketInfo{
+			Destination: snet.SCIONAddress{IA: ia, Host: addr.HostFromIP(clientAddr.IP)},
+			Source:      snet.SCIONAddress{IA: ia, Host: addr.HostFromIP(localhost)},
+			Payload: snet.SCMPExternalInterfaceDown{
+				IA:        other,
+				Interface: 2,
+			},
+		},
+	}
+	scmpSender := &snet.DirectPacketDispatcherService{
+		StartPort: testStartPort,
+		EndPort:   testEndPort,
+	}
+	scmpConn, _, err := scmpSender.Register(ctx, ia, &net.UDPAddr{IP: localhost},
+		addr.SvcNone)
+	require.NoError(t, err)
+	defer scmpConn.Close()
+	require.NoError(t, scmpConn.WriteTo(scmp, clientAddr))
+
+	_, err = server.WriteTo([]byte("again"), from)
+	require.NoError(t, err)
+	l, err = client.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "again", string(buf[:l]))
+	assert.Equal(t, snet.Fingerprint(backup),
+		snet.Fingerprint(client.Selector(ia).Paths()[0]))
+}
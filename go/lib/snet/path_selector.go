@@ -0,0 +1,283 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+const (
+	// DefaultPathRefreshInterval is the default interval after which the
+	// paths of a PathSelector are fetched again.
+	DefaultPathRefreshInterval = 30 * time.Second
+	// DefaultPathExpiryMargin is the default margin before the expiration of a
+	// path at which a PathSelector stops using the path.
+	DefaultPathExpiryMargin = 10 * time.Second
+)
+
+// PathPolicy filters the set of paths a PathSelector chooses from. It is
+// implemented by *pathpol.Policy.
+type PathPolicy interface {
+	Filter(paths []Path) []Path
+}
+
+// PathSelector keeps track of the paths to a destination AS and selects the
+// path to send on. The paths are fetched from the querier, filtered with the
+// policy, and refreshed periodically. The selector switches away from the
+// active path as soon as it is revoked or about to expire.
+//
+// PathSelector is safe for concurrent use.
+type PathSelector struct {
+	// Querier is used to fetch the paths to the destination.
+	Querier PathQuerier
+	// Policy restricts the paths that can be selected. If nil, all paths are
+	// eligible.
+	Policy PathPolicy
+	// Destination is the destination AS.
+	Destination addr.IA
+	// RefreshInterval is the interval after which the paths are fetched
+	// again. If zero, DefaultPathRefreshInterval is used.
+	RefreshInterval time.Duration
+	// ExpiryMargin is the margin before the expiration of a path at which it
+	// is no longer used. If zero, DefaultPathExpiryMargin is used.
+	ExpiryMargin time.Duration
+
+	mtx         sync.Mutex
+	paths       []Path
+	active      PathFingerprint
+	revocations []*path_mgmt.RevInfo
+	lastRefresh time.Time
+	// refreshing is closed when the refresh in progress completes. It is nil
+	// if no refresh is in progress.
+	refreshing chan struct{}
+	// refreshErr is the result of the last completed refresh.
+	refreshErr error
+}
+
+// Path returns the currently active path. If the paths are stale, or none of
+// the known paths is usable, the paths are fetched again. The paths are
+// fetched without holding the lock, i.e., Paths and Revoke do not block while
+// a query is in progress. Concurrent callers share a single query.
+func (s *PathSelector) Path(ctx context.Context) (Path, error) {
+	now := time.Now()
+	if s.stale(now) {
+		if err := s.refresh(ctx, now); err != nil && !s.hasPaths() {
+			return nil, err
+		}
+	}
+	if p := s.current(now); p != nil {
+		return p, nil
+	}
+	// All known paths are revoked or expired, fetch fresh ones.
+	if err := s.refresh(ctx, now); err != nil {
+		return nil, err
+	}
+	if p := s.current(now); p != nil {
+		return p, nil
+	}
+	return nil, serrors.New("no usable path", "dst", s.Destination)
+}
+
+// Paths returns the usable paths to the destination without refreshing them.
+// The active path is the first entry.
+func (s *PathSelector) Paths() []Path {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	now := time.Now()
+	s.selectPath(now)
+	var paths []Path
+	for _, p := range s.paths {
+		if !s.usable(p, now) {
+			continue
+		}
+		if Fingerprint(p) == s.active {
+			paths = append([]Path{p}, paths...)
+			continue
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// Revoke marks the interface in the revocation as down. Paths that traverse
+// the interface are not used until the revocation expires. It returns true
+// if the active path was affected.
+func (s *PathSelector) Revoke(revInfo *path_mgmt.RevInfo) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.revocations = append(s.revocations, revInfo)
+	for _, p := range s.paths {
+		if Fingerprint(p) == s.active && revoked(p, revInfo) {
+			s.active = ""
+			return true
+		}
+	}
+	return false
+}
+
+// refresh fetches the paths. If a refresh is already in progress, it waits for
+// that refresh to complete and returns its result instead.
+func (s *PathSelector) refresh(ctx context.Context, now time.Time) error {
+	s.mtx.Lock()
+	if done := s.refreshing; done != nil {
+		s.mtx.Unlock()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		s.mtx.Lock()
+		defer s.mtx.Unlock()
+		return s.refreshErr
+	}
+	done := make(chan struct{})
+	s.refreshing = done
+	s.lastRefresh = now
+	s.mtx.Unlock()
+
+	paths, err := s.Querier.Query(ctx, s.Destination)
+	if err != nil {
+		err = serrors.WrapStr("fetching paths", err, "dst", s.Destination)
+	}
+	if err == nil && s.Policy != nil {
+		paths = s.Policy.Filter(paths)
+	}
+	sort.SliceStable(paths, func(i, j int) bool {
+		return better(paths[i], paths[j])
+	})
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if err == nil {
+		s.paths = paths
+	}
+	s.refreshErr = err
+	s.refreshing = nil
+	close(done)
+	return err
+}
+
+func (s *PathSelector) stale(now time.Time) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return now.Sub(s.lastRefresh) > s.refreshInterval()
+}
+
+func (s *PathSelector) hasPaths() bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return len(s.paths) > 0
+}
+
+func (s *PathSelector) current(now time.Time) Path {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.selectPath(now)
+}
+
+// selectPath returns the active path, if it is still usable. Otherwise, the
+// best usable path becomes the active path. If there is no usable path, nil
+// is returned.
+func (s *PathSelector) selectPath(now time.Time) Path {
+	s.expireRevocations(now)
+	var best Path
+	for _, p := range s.paths {
+		if !s.usable(p, now) {
+			continue
+		}
+		if Fingerprint(p) == s.active {
+			return p
+		}
+		if best == nil {
+			best = p
+		}
+	}
+	if best == nil {
+		s.active = ""
+		return nil
+	}
+	s.active = Fingerprint(best)
+	return best
+}
+
+func (s *PathSelector) usable(p Path, now time.Time) bool {
+	if md := p.Metadata(); md != nil && !md.Expiry.IsZero() &&
+		md.Expiry.Before(now.Add(s.expiryMargin())) {
+		return false
+	}
+	for _, rev := range s.revocations {
+		if revoked(p, rev) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *PathSelector) expireRevocations(now time.Time) {
+	active := s.revocations[:0]
+	for _, rev := range s.revocations {
+		if rev.Expiration().After(now) {
+			active = append(active, rev)
+		}
+	}
+	s.revocations = active
+}
+
+func (s *PathSelector) refreshInterval() time.Duration {
+	if s.RefreshInterval == 0 {
+		return DefaultPathRefreshInterval
+	}
+	return s.RefreshInterval
+}
+
+func (s *PathSelector) expiryMargin() time.Duration {
+	if s.ExpiryMargin == 0 {
+		return DefaultPathExpiryMargin
+	}
+	return s.ExpiryMargin
+}
+
+// revoked checks whether the path traverses the revoked interface.
+func revoked(p Path, revInfo *path_mgmt.RevInfo) bool {
+	md := p.Metadata()
+	if md == nil {
+		return false
+	}
+	for _, intf := range md.Interfaces {
+		if intf.IA.Equal(revInfo.IA()) && intf.ID == revInfo.IfID {
+			return true
+		}
+	}
+	return false
+}
+
+// better orders paths by the number of hops, and prefers paths that are valid
+// for longer.
+func better(a, b Path) bool {
+	mdA, mdB := a.Metadata(), b.Metadata()
+	if mdA == nil || mdB == nil {
+		return mdA != nil
+	}
+	if len(mdA.Interfaces) != len(mdB.Interfaces) {
+		return len(mdA.Interfaces) < len(mdB.Interfaces)
+	}
+	return mdA.Expiry.After(mdB.Expiry)
+}
@@ -0,0 +1,212 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/snet/mock_snet"
+	"github.com/scionproto/scion/go/lib/snet/path"
+	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/lib/xtest/graph"
+	"github.com/scionproto/scion/go/lib/xtest/graph/pathprovider"
+)
+
+func TestPathSelectorPath(t *testing.T) {
+	local := xtest.MustParseIA("1-ff00:0:111")
+	dst := xtest.MustParseIA("1-ff00:0:112")
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	paths := pathprovider.New(graph.NewDefaultGraph(ctrl)).GetPaths(local, dst)
+	require.True(t, len(paths) >= 2)
+	// The shortest path takes the direct link 1-ff00:0:111#1417 to
+	// 1-ff00:0:112#1714, the long path goes through 1-ff00:0:130.
+	short, long := paths[0], paths[1]
+	expiring := testPath(dst, nil, time.Now().Add(time.Second), local, 7, dst, 8)
+
+	t.Run("best path", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		querier := mock_snet.NewMockPathQuerier(ctrl)
+		querier.EXPECT().Query(gomock.Any(), dst).Return([]snet.Path{long, expiring, short}, nil)
+
+		s := &snet.PathSelector{Querier: querier, Destination: dst}
+		p, err := s.Path(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, snet.Fingerprint(short), snet.Fingerprint(p))
+		assert.Equal(t, []snet.Path{short, long}, s.Paths())
+	})
+	t.Run("policy", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		querier := mock_snet.NewMockPathQuerier(ctrl)
+		querier.EXPECT().Query(gomock.Any(), dst).Return([]snet.Path{long, short}, nil)
+
+		s := &snet.PathSelector{
+			Querier:     querier,
+			Policy:      policyFunc(func(paths []snet.Path) []snet.Path { return paths[:1] }),
+			Destination: dst,
+		}
+		p, err := s.Path(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, snet.Fingerprint(long), snet.Fingerprint(p))
+	})
+	t.Run("revocation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		querier := mock_snet.NewMockPathQuerier(ctrl)
+		querier.EXPECT().Query(gomock.Any(), dst).Return([]snet.Path{long, short}, nil)
+
+		s := &snet.PathSelector{Querier: querier, Destination: dst}
+		_, err := s.Path(context.Background())
+		require.NoError(t, err)
+
+		assert.False(t, s.Revoke(testRevInfo(local, 9)))
+		assert.True(t, s.Revoke(testRevInfo(dst, 1714)))
+		p, err := s.Path(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, snet.Fingerprint(long), snet.Fingerprint(p))
+	})
+	t.Run("all paths revoked", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		querier := mock_snet.NewMockPathQuerier(ctrl)
+		querier.EXPECT().Query(gomock.Any(), dst).Return([]snet.Path{short}, nil).Times(2)
+
+		s := &snet.PathSelector{Querier: querier, Destination: dst}
+		_, err := s.Path(context.Background())
+		require.NoError(t, err)
+		s.Revoke(testRevInfo(local, 1417))
+		_, err = s.Path(context.Background())
+		assert.Error(t, err)
+	})
+	t.Run("refresh", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		querier := mock_snet.NewMockPathQuerier(ctrl)
+		gomock.InOrder(
+			querier.EXPECT().Query(gomock.Any(), dst).Return([]snet.Path{short}, nil),
+			querier.EXPECT().Query(gomock.Any(), dst).Return([]snet.Path{long}, nil),
+			querier.EXPECT().Query(gomock.Any(), dst).Return(nil, serrors.New("internal")),
+		)
+
+		s := &snet.PathSelector{
+			Querier:         querier,
+			Destination:     dst,
+			RefreshInterval: time.Millisecond,
+		}
+		p, err := s.Path(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, snet.Fingerprint(short), snet.Fingerprint(p))
+		time.Sleep(2 * time.Millisecond)
+		p, err = s.Path(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, snet.Fingerprint(long), snet.Fingerprint(p))
+		// A failed refresh keeps the previous paths.
+		time.Sleep(2 * time.Millisecond)
+		p, err = s.Path(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, snet.Fingerprint(long), snet.Fingerprint(p))
+	})
+	t.Run("concurrent refresh", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		release := make(chan struct{})
+		querier := mock_snet.NewMockPathQuerier(ctrl)
+		querier.EXPECT().Query(gomock.Any(), dst).DoAndReturn(
+			func(context.Context, addr.IA) ([]snet.Path, error) {
+				<-release
+				return []snet.Path{short}, nil
+			},
+		)
+
+		// All callers share the single query.
+		s := &snet.PathSelector{Querier: querier, Destination: dst}
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				p, err := s.Path(context.Background())
+				assert.NoError(t, err)
+				assert.Equal(t, snet.Fingerprint(short), snet.Fingerprint(p))
+			}()
+		}
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+	})
+	t.Run("no paths", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		querier := mock_snet.NewMockPathQuerier(ctrl)
+		querier.EXPECT().Query(gomock.Any(), dst).Return(nil, nil).Times(2)
+
+		s := &snet.PathSelector{Querier: querier, Destination: dst}
+		_, err := s.Path(context.Background())
+		assert.Error(t, err)
+	})
+}
+
+type policyFunc func([]snet.Path) []snet.Path
+
+func (f policyFunc) Filter(paths []snet.Path) []snet.Path {
+	return f(paths)
+}
+
+// testPath creates a path with the given interfaces, which are specified as
+// alternating IA and interface ID arguments.
+func testPath(dst addr.IA, nextHop *net.UDPAddr, expiry time.Time,
+	intfs ...interface{}) snet.Path {
+
+	var pathIntfs []snet.PathInterface
+	for i := 0; i < len(intfs); i += 2 {
+		pathIntfs = append(pathIntfs, snet.PathInterface{
+			IA: intfs[i].(addr.IA),
+			ID: common.IFIDType(intfs[i+1].(int)),
+		})
+	}
+	return path.Path{
+		Dst:     dst,
+		NextHop: nextHop,
+		Meta: snet.PathMetadata{
+			Interfaces: pathIntfs,
+			Expiry:     expiry,
+		},
+	}
+}
+
+func testRevInfo(ia addr.IA, ifID common.IFIDType) *path_mgmt.RevInfo {
+	return &path_mgmt.RevInfo{
+		IfID:         ifID,
+		RawIsdas:     ia.IAInt(),
+		RawTimestamp: util.TimeToSecs(time.Now()),
+		RawTTL:       10,
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet
+
+import (
+	"container/list"
+	"time"
+)
+
+const (
+	// DefaultMaxReplyPaths is the default number of remote addresses for which
+	// a PathAwareConn remembers the reply path.
+	DefaultMaxReplyPaths = 1024
+	// DefaultReplyPathTTL is the default duration for which a PathAwareConn
+	// uses the reply path of the most recently received packet of a remote.
+	DefaultReplyPathTTL = 5 * time.Minute
+)
+
+// replyPathCache is a least recently used cache of reply paths. Entries
+// expire after the TTL, and the least recently used entry is evicted if the
+// cache is full. It is not safe for concurrent use.
+type replyPathCache struct {
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	// order contains the entries, the most recently used entry first.
+	order *list.List
+}
+
+type replyPathEntry struct {
+	key    string
+	addr   *UDPAddr
+	expiry time.Time
+}
+
+func newReplyPathCache(size int, ttl time.Duration) *replyPathCache {
+	return &replyPathCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get returns the reply path of the key, if it is present and not expired.
+func (c *replyPathCache) Get(key string, now time.Time) (*UDPAddr, bool) {
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*replyPathEntry)
+	if !now.Before(entry.expiry) {
+		c.remove(elem)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.addr, true
+}
+
+// Put stores the reply path of the key and evicts the least recently used
+// entries if the cache exceeds its size.
+func (c *replyPathCache) Put(key string, addr *UDPAddr, now time.Time) {
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*replyPathEntry)
+		entry.addr, entry.expiry = addr, now.Add(c.ttl)
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&replyPathEntry{
+		key:    key,
+		addr:   addr,
+		expiry: now.Add(c.ttl),
+	})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// Len returns the number of entries in the cache, including expired entries
+// that were not evicted yet.
+func (c *replyPathCache) Len() int {
+	return c.order.Len()
+}
+
+func (c *replyPathCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*replyPathEntry).key)
+}
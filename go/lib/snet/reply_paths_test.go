@@ -0,0 +1,70 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snet_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/snet"
+)
+
+func TestReplyPathCache(t *testing.T) {
+	now := time.Now()
+	addr := func(i int) *snet.UDPAddr {
+		a, err := snet.ParseUDPAddr(fmt.Sprintf("1-ff00:0:110,127.0.0.%d:80", i))
+		require.NoError(t, err)
+		return a
+	}
+
+	t.Run("expired entries are not returned", func(t *testing.T) {
+		c := snet.NewReplyPathCache(2, time.Minute)
+		c.Put("a", addr(1), now)
+		got, ok := c.Get("a", now.Add(59*time.Second))
+		assert.True(t, ok)
+		assert.Equal(t, addr(1), got)
+		_, ok = c.Get("a", now.Add(time.Minute))
+		assert.False(t, ok)
+		assert.Equal(t, 0, c.Len())
+	})
+	t.Run("put refreshes the entry", func(t *testing.T) {
+		c := snet.NewReplyPathCache(2, time.Minute)
+		c.Put("a", addr(1), now)
+		c.Put("a", addr(2), now.Add(30*time.Second))
+		got, ok := c.Get("a", now.Add(80*time.Second))
+		assert.True(t, ok)
+		assert.Equal(t, addr(2), got)
+		assert.Equal(t, 1, c.Len())
+	})
+	t.Run("least recently used entry is evicted", func(t *testing.T) {
+		c := snet.NewReplyPathCache(2, time.Minute)
+		c.Put("a", addr(1), now)
+		c.Put("b", addr(2), now)
+		_, ok := c.Get("a", now)
+		assert.True(t, ok)
+		c.Put("c", addr(3), now)
+		assert.Equal(t, 2, c.Len())
+		_, ok = c.Get("b", now)
+		assert.False(t, ok)
+		_, ok = c.Get("a", now)
+		assert.True(t, ok)
+		_, ok = c.Get("c", now)
+		assert.True(t, ok)
+	})
+}
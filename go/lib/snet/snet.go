@@ -51,6 +51,7 @@ import (
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/snet/internal/metrics"
 	"github.com/scionproto/scion/go/lib/sock/reliable"
+	"github.com/scionproto/scion/go/lib/topology/underlay"
 )
 
 var _ Network = (*SCIONNetwork)(nil)
@@ -59,6 +60,13 @@ var _ Network = (*SCIONNetwork)(nil)
 type SCIONNetwork struct {
 	LocalIA    addr.IA
 	Dispatcher PacketDispatcherService
+	// DispatchedPortStart and DispatchedPortEnd are the inclusive range of
+	// ports in the local AS that routers deliver to directly. Packets to
+	// local end hosts listening on such a port are sent to that port instead
+	// of the dispatcher. If DispatchedPortStart is zero, all local packets
+	// are sent via the dispatcher.
+	DispatchedPortStart uint16
+	DispatchedPortEnd   uint16
 }
 
 // NewNetwork creates a new networking context.
@@ -76,6 +84,36 @@ func NewNetwork(ia addr.IA, dispatcher reliable.Dispatcher,
 	}
 }
 
+// NewDirectNetwork creates a new networking context that does not depend on
+// the dispatcher. Connections open their own UDP socket on a port in the
+// range [start, end], which must be the dispatched port range of the local AS.
+func NewDirectNetwork(ia addr.IA, start, end uint16,
+	revHandler RevocationHandler) *SCIONNetwork {
+
+	return &SCIONNetwork{
+		LocalIA: ia,
+		Dispatcher: &DirectPacketDispatcherService{
+			StartPort: start,
+			EndPort:   end,
+			SCMPHandler: &DefaultSCMPHandler{
+				RevocationHandler: revHandler,
+			},
+		},
+		DispatchedPortStart: start,
+		DispatchedPortEnd:   end,
+	}
+}
+
+// endhostPort returns the underlay port to which packets destined to port on
+// a local end host are sent.
+func (n *SCIONNetwork) endhostPort(port int) int {
+	if n.DispatchedPortStart != 0 && port >= int(n.DispatchedPortStart) &&
+		port <= int(n.DispatchedPortEnd) {
+		return port
+	}
+	return underlay.EndhostPort
+}
+
 // Dial returns a SCION connection to remote. Nil values for listen are not
 // supported yet. Parameter network must be "udp". The returned connection's
 // Read and Write methods can be used to receive and send SCION packets.
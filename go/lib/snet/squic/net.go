@@ -107,7 +107,8 @@ func acceptStream(ctx context.Context, session quic.Session) (net.Conn, error) {
 type ConnDialer struct {
 	// Conn is the connection to initiate QUIC Sessions on. It can be shared
 	// between clients and servers, because QUIC connection IDs are used to
-	// demux the packets.
+	// demux the packets. To keep QUIC sessions alive across path failures,
+	// use a multipath.Conn.
 	Conn net.PacketConn
 	// TLSConfig is the client's TLS configuration for starting QUIC connections.
 	TLSConfig *tls.Config
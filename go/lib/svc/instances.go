@@ -0,0 +1,153 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svc
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/periodic"
+	"github.com/scionproto/scion/go/lib/snet"
+	snetpath "github.com/scionproto/scion/go/lib/snet/path"
+)
+
+// maxReplyInstances is the maximum number of instances that are announced in
+// a reply, such that the reply fits within a UDP datagram.
+const maxReplyInstances = 32
+
+// ReplySource provides the reply to SVC resolution requests.
+type ReplySource interface {
+	// Reply returns the current reply.
+	Reply() *Reply
+}
+
+var _ ReplySource = (*Instances)(nil)
+
+// Instances keeps track of the healthy instances of a service, and announces
+// them in the SVC resolution replies. The local instance is always considered
+// healthy.
+type Instances struct {
+	// Local contains the transports of the local instance.
+	Local map[Transport]string
+
+	mu    sync.Mutex
+	peers map[string]map[Transport]string
+}
+
+// SetPeers replaces the set of healthy peer instances. The keys are the
+// instance names.
+func (s *Instances) SetPeers(peers map[string]map[Transport]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.peers = peers
+}
+
+// Reply returns a reply that contains the transports of the local instance,
+// and the transports of all healthy instances. The local instance is listed
+// first, the peers are sorted by name.
+func (s *Instances) Reply() *Reply {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.peers))
+	for name := range s.peers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	instances := []Instance{{Transports: s.Local}}
+	for _, name := range names {
+		if len(instances) >= maxReplyInstances {
+			break
+		}
+		instances = append(instances, Instance{Transports: s.peers[name]})
+	}
+	return &Reply{
+		Transports: s.Local,
+		Instances:  instances,
+	}
+}
+
+// Lookuper performs SVC resolution.
+type Lookuper interface {
+	// LookupSVC resolves the SVC address for the AS terminating the path.
+	LookupSVC(ctx context.Context, p snet.Path, svc addr.HostSVC) (*Reply, error)
+}
+
+var _ periodic.Task = (*InstanceProber)(nil)
+
+// InstanceProber is a periodic task that probes the other instances of a
+// service in the local AS with SVC resolution requests. The instances that
+// reply are recorded as healthy in Instances.
+type InstanceProber struct {
+	// Instances is updated with the healthy peers after every run.
+	Instances *Instances
+	// Lookuper is used to send the SVC resolution requests.
+	Lookuper Lookuper
+	// LocalIA is the local AS.
+	LocalIA addr.IA
+	// SVC is the service type of the instances.
+	SVC addr.HostSVC
+	// Peers returns the underlay addresses of the other instances of the
+	// service, keyed by instance name.
+	Peers func() map[string]*net.UDPAddr
+	// Timeout is the timeout for a single probe.
+	Timeout time.Duration
+	// TaskName is the name of the task.
+	TaskName string
+}
+
+// Name returns the task name.
+func (p *InstanceProber) Name() string {
+	return p.TaskName
+}
+
+// Run probes all peers concurrently and updates the healthy instances.
+func (p *InstanceProber) Run(ctx context.Context) {
+	logger := log.FromCtx(ctx)
+	peers := p.Peers()
+
+	var mu sync.Mutex
+	healthy := make(map[string]map[Transport]string, len(peers))
+	var wg sync.WaitGroup
+	wg.Add(len(peers))
+	for name, underlay := range peers {
+		name, underlay := name, underlay
+		go func() {
+			defer log.HandlePanic()
+			defer wg.Done()
+			reply, err := p.probe(ctx, underlay)
+			if err != nil {
+				logger.Debug("Service instance unhealthy", "svc", p.SVC, "instance", name,
+					"err", err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			healthy[name] = reply.Transports
+		}()
+	}
+	wg.Wait()
+	p.Instances.SetPeers(healthy)
+}
+
+func (p *InstanceProber) probe(ctx context.Context, underlay *net.UDPAddr) (*Reply, error) {
+	ctx, cancelF := context.WithTimeout(ctx, p.Timeout)
+	defer cancelF()
+	return p.Lookuper.LookupSVC(ctx, snetpath.Path{Dst: p.LocalIA, NextHop: underlay}, p.SVC)
+}
@@ -0,0 +1,92 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/svc"
+	"github.com/scionproto/scion/go/lib/svc/mock_svc"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+func TestInstancesReply(t *testing.T) {
+	local := map[svc.Transport]string{svc.QUIC: "10.0.0.1:30252"}
+	peerA := map[svc.Transport]string{svc.QUIC: "10.0.0.2:30252"}
+	peerB := map[svc.Transport]string{svc.QUIC: "10.0.0.3:30252"}
+
+	instances := &svc.Instances{Local: local}
+	assert.Equal(t, &svc.Reply{
+		Transports: local,
+		Instances:  []svc.Instance{{Transports: local}},
+	}, instances.Reply())
+
+	instances.SetPeers(map[string]map[svc.Transport]string{"cs-b": peerB, "cs-a": peerA})
+	assert.Equal(t, &svc.Reply{
+		Transports: local,
+		Instances: []svc.Instance{
+			{Transports: local},
+			{Transports: peerA},
+			{Transports: peerB},
+		},
+	}, instances.Reply())
+}
+
+func TestInstanceProberRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ia := xtest.MustParseIA("1-ff00:0:110")
+	healthyAddr := &net.UDPAddr{IP: net.IP{10, 0, 0, 2}, Port: 30252}
+	unhealthyAddr := &net.UDPAddr{IP: net.IP{10, 0, 0, 3}, Port: 30252}
+	healthy := map[svc.Transport]string{svc.QUIC: "10.0.0.2:31000"}
+
+	lookuper := mock_svc.NewMockLookuper(ctrl)
+	lookuper.EXPECT().LookupSVC(gomock.Any(), gomock.Any(), addr.SvcCS).DoAndReturn(
+		func(_ context.Context, p snet.Path, _ addr.HostSVC) (*svc.Reply, error) {
+			assert.Equal(t, ia, p.Destination())
+			if p.UnderlayNextHop().String() == healthyAddr.String() {
+				return &svc.Reply{Transports: healthy}, nil
+			}
+			return nil, serrors.New("timeout")
+		},
+	).Times(2)
+
+	local := map[svc.Transport]string{svc.QUIC: "10.0.0.1:31000"}
+	instances := &svc.Instances{Local: local}
+	instances.SetPeers(map[string]map[svc.Transport]string{"cs-c": healthy})
+	prober := &svc.InstanceProber{
+		Instances: instances,
+		Lookuper:  lookuper,
+		LocalIA:   ia,
+		SVC:       addr.SvcCS,
+		Peers: func() map[string]*net.UDPAddr {
+			return map[string]*net.UDPAddr{"cs-a": healthyAddr, "cs-b": unhealthyAddr}
+		},
+		Timeout: time.Second,
+	}
+	prober.Run(context.Background())
+	assert.Equal(t, []svc.Instance{{Transports: local}, {Transports: healthy}},
+		instances.Reply().Instances)
+}
@@ -27,6 +27,10 @@ type Reply struct {
 	// (e.g., "192.168.1.1:80"). Applications should check if the transport keys
 	// are acceptable and must parse the address strings accordingly.
 	Transports map[Transport]string
+	// Instances contains the transports of all healthy instances of the
+	// service, including the replying one. It is empty if the server does not
+	// announce the instances.
+	Instances []Instance
 	// ReturnPath contains the reversed and initialized path the SVC resolution
 	// message arrived on. This can be used to communicate across paths
 	// bootstrapped via One-Hop Path communication.
@@ -61,14 +65,16 @@ func (r *Reply) Marshal() ([]byte, error) {
 //
 // Elements of the slice are always sorted by Key in ascending order.
 func (r *Reply) toProtoFormat() *cppb.ServiceResolutionResponse {
-	if r == nil || len(r.Transports) == 0 {
+	if r == nil {
 		return &cppb.ServiceResolutionResponse{}
 	}
 	rep := &cppb.ServiceResolutionResponse{
-		Transports: make(map[string]*cppb.Transport, len(r.Transports)),
+		Transports: transportsToProto(r.Transports),
 	}
-	for key, addr := range r.Transports {
-		rep.Transports[string(key)] = &cppb.Transport{Address: addr}
+	for _, instance := range r.Instances {
+		rep.Instances = append(rep.Instances, &cppb.ServiceInstance{
+			Transports: transportsToProto(instance.Transports),
+		})
 	}
 	return rep
 }
@@ -85,14 +91,40 @@ func (r *Reply) toProtoFormat() *cppb.ServiceResolutionResponse {
 // Calling this function always resets the internal state of the Reply, even if
 // an error is returned.
 func (r *Reply) fromProtoFormat(protoReply *cppb.ServiceResolutionResponse) error {
-	r.Transports = make(map[Transport]string)
-	if protoReply == nil || len(protoReply.Transports) == 0 {
+	r.Transports = transportsFromProto(protoReply.GetTransports())
+	r.Instances = nil
+	for _, instance := range protoReply.GetInstances() {
+		r.Instances = append(r.Instances, Instance{
+			Transports: transportsFromProto(instance.Transports),
+		})
+	}
+	return nil
+}
+
+func transportsToProto(transports map[Transport]string) map[string]*cppb.Transport {
+	if len(transports) == 0 {
 		return nil
 	}
-	for key, transport := range protoReply.Transports {
-		r.Transports[Transport(key)] = transport.Address
+	ret := make(map[string]*cppb.Transport, len(transports))
+	for key, addr := range transports {
+		ret[string(key)] = &cppb.Transport{Address: addr}
 	}
-	return nil
+	return ret
+}
+
+func transportsFromProto(transports map[string]*cppb.Transport) map[Transport]string {
+	ret := make(map[Transport]string, len(transports))
+	for key, transport := range transports {
+		ret[Transport(key)] = transport.GetAddress()
+	}
+	return ret
+}
+
+// Instance is an instance of a service announced in an SVC resolution reply.
+type Instance struct {
+	// Transports maps transport keys to network address strings, in the same
+	// format as Reply.Transports.
+	Transports map[Transport]string
 }
 
 // Transport contains constants for common transport keys.
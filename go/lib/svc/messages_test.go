@@ -30,6 +30,10 @@ func TestSVCResolutionSerialization(t *testing.T) {
 				"foo": "bar",
 				"bar": "baz",
 			},
+			Instances: []Instance{
+				{Transports: map[Transport]string{"foo": "bar"}},
+				{Transports: map[Transport]string{"foo": "qux"}},
+			},
 		}
 
 		raw, err := message.Marshal()
@@ -78,6 +82,23 @@ func TestReplyToProtoFormat(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "reply with instances",
+			Reply: &Reply{
+				Transports: map[Transport]string{"foo": "bar"},
+				Instances: []Instance{
+					{Transports: map[Transport]string{"foo": "bar"}},
+					{Transports: map[Transport]string{"foo": "baz"}},
+				},
+			},
+			ExpectedProtoReply: &cppb.ServiceResolutionResponse{
+				Transports: map[string]*cppb.Transport{"foo": {Address: "bar"}},
+				Instances: []*cppb.ServiceInstance{
+					{Transports: map[string]*cppb.Transport{"foo": {Address: "bar"}}},
+					{Transports: map[string]*cppb.Transport{"foo": {Address: "baz"}}},
+				},
+			},
+		},
 	}
 
 	t.Run("Replies should be converted to the correct proto objects", func(t *testing.T) {
@@ -153,6 +174,24 @@ func TestReplyFromProtoFormat(t *testing.T) {
 			},
 			Error: assert.NoError,
 		},
+		{
+			Name: "reply with instances",
+			ProtoReply: &cppb.ServiceResolutionResponse{
+				Transports: map[string]*cppb.Transport{"foo": {Address: "bar"}},
+				Instances: []*cppb.ServiceInstance{
+					{Transports: map[string]*cppb.Transport{"foo": {Address: "bar"}}},
+					{Transports: map[string]*cppb.Transport{"foo": {Address: "baz"}}},
+				},
+			},
+			ExpectedReply: &Reply{
+				Transports: map[Transport]string{"foo": "bar"},
+				Instances: []Instance{
+					{Transports: map[Transport]string{"foo": "bar"}},
+					{Transports: map[Transport]string{"foo": "baz"}},
+				},
+			},
+			Error: assert.NoError,
+		},
 	}
 	t.Run("Proto objects should be converted to the correct reply", func(t *testing.T) {
 		for _, tc := range testCases {
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/scionproto/scion/go/lib/svc (interfaces: RequestHandler,RoundTripper)
+// Source: github.com/scionproto/scion/go/lib/svc (interfaces: Lookuper,RequestHandler,RoundTripper)
 
 // Package mock_svc is a generated GoMock package.
 package mock_svc
@@ -7,12 +7,51 @@ package mock_svc
 import (
 	context "context"
 	gomock "github.com/golang/mock/gomock"
+	addr "github.com/scionproto/scion/go/lib/addr"
 	snet "github.com/scionproto/scion/go/lib/snet"
 	svc "github.com/scionproto/scion/go/lib/svc"
 	net "net"
 	reflect "reflect"
 )
 
+// MockLookuper is a mock of Lookuper interface
+type MockLookuper struct {
+	ctrl     *gomock.Controller
+	recorder *MockLookuperMockRecorder
+}
+
+// MockLookuperMockRecorder is the mock recorder for MockLookuper
+type MockLookuperMockRecorder struct {
+	mock *MockLookuper
+}
+
+// NewMockLookuper creates a new mock instance
+func NewMockLookuper(ctrl *gomock.Controller) *MockLookuper {
+	mock := &MockLookuper{ctrl: ctrl}
+	mock.recorder = &MockLookuperMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLookuper) EXPECT() *MockLookuperMockRecorder {
+	return m.recorder
+}
+
+// LookupSVC mocks base method
+func (m *MockLookuper) LookupSVC(arg0 context.Context, arg1 snet.Path, arg2 addr.HostSVC) (*svc.Reply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupSVC", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*svc.Reply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LookupSVC indicates an expected call of LookupSVC
+func (mr *MockLookuperMockRecorder) LookupSVC(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupSVC", reflect.TypeOf((*MockLookuper)(nil).LookupSVC), arg0, arg1, arg2)
+}
+
 // MockRequestHandler is a mock of RequestHandler interface
 type MockRequestHandler struct {
 	ctrl     *gomock.Controller
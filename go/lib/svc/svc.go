@@ -167,6 +167,9 @@ type BaseHandler struct {
 	// Message is the payload data to send in the reply. Nil and zero-length
 	// payloads are supported.
 	Message []byte
+	// Source, if set, provides the reply for every request. In that case,
+	// Message is ignored.
+	Source ReplySource
 }
 
 func (h *BaseHandler) Handle(request *Request) (Result, error) {
@@ -179,6 +182,12 @@ func (h *BaseHandler) Handle(request *Request) (Result, error) {
 		return Error, serrors.New("invalid payload in request",
 			"expected", "UDP", "type", common.TypeOf(request.Packet.Payload))
 	}
+	message := h.Message
+	if h.Source != nil {
+		if message, err = h.Source.Reply().Marshal(); err != nil {
+			return Error, serrors.WrapStr("building reply", err)
+		}
+	}
 	replyPacket := &snet.Packet{
 		PacketInfo: snet.PacketInfo{
 			Destination: request.Packet.Source,
@@ -187,7 +196,7 @@ func (h *BaseHandler) Handle(request *Request) (Result, error) {
 			Payload: snet.UDPPayload{
 				DstPort: udp.SrcPort,
 				SrcPort: udp.DstPort,
-				Payload: h.Message,
+				Payload: message,
 			},
 		},
 	}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/serrors"
@@ -334,4 +335,36 @@ func TestDefaultHandler(t *testing.T) {
 		_, err := sender.Handle(request)
 		assert.NoError(t, err)
 	})
+
+	t.Run("Reply is built from the source", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		conn := mock_snet.NewMockPacketConn(ctrl)
+		packet := &snet.Packet{
+			PacketInfo: snet.PacketInfo{
+				Payload: snet.UDPPayload{},
+			},
+		}
+		local := map[svc.Transport]string{svc.QUIC: "192.168.0.1:30252"}
+		peer := map[svc.Transport]string{svc.QUIC: "192.168.0.2:30252"}
+		instances := &svc.Instances{Local: local}
+		instances.SetPeers(map[string]map[svc.Transport]string{"cs1-2": peer})
+
+		var written *snet.Packet
+		conn.EXPECT().WriteTo(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(pkt *snet.Packet, _ *net.UDPAddr) error {
+				written = pkt
+				return nil
+			},
+		)
+		sender := &svc.BaseHandler{Message: []byte("ignored"), Source: instances}
+		_, err := sender.Handle(&svc.Request{Conn: conn, Packet: packet})
+		require.NoError(t, err)
+
+		var reply svc.Reply
+		require.NoError(t, reply.Unmarshal(written.Payload.(snet.UDPPayload).Payload))
+		assert.Equal(t, local, reply.Transports)
+		assert.Equal(t, []svc.Instance{{Transports: local}, {Transports: peer}}, reply.Instances)
+	})
 }
@@ -47,6 +47,10 @@ type Topology interface {
 	Core() bool
 	// CA returns whether the local AS is a CA.
 	CA() bool
+	// PortRange returns the inclusive range of underlay ports to which the
+	// routers deliver packets directly. If both values are zero, all packets
+	// are delivered to the dispatcher.
+	PortRange() (uint16, uint16)
 	// InterfaceIDs returns all interface IDS from the local AS.
 	InterfaceIDs() []common.IFIDType
 
@@ -166,6 +170,10 @@ func (t *topologyS) MTU() uint16 {
 	return uint16(t.Topology.MTU)
 }
 
+func (t *topologyS) PortRange() (uint16, uint16) {
+	return t.Topology.DispatchedPortStart, t.Topology.DispatchedPortEnd
+}
+
 func (t *topologyS) InterfaceIDs() []common.IFIDType {
 	intfs := make([]common.IFIDType, 0, len(t.Topology.IFInfoMap))
 	for ifid := range t.Topology.IFInfoMap {
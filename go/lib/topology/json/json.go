@@ -66,6 +66,10 @@ type Topology struct {
 	TimestampHuman string `json:"timestamp_human,omitempty"`
 	IA             string `json:"isd_as"`
 	MTU            int    `json:"mtu"`
+	// DispatchedPorts is the inclusive range of underlay ports, in the format
+	// "start-end", to which the routers deliver packets directly. Packets to
+	// ports outside the range are delivered to the dispatcher.
+	DispatchedPorts string `json:"dispatched_ports,omitempty"`
 	// Attributes are the primary AS attributes as described in
 	// https://github.com/scionproto/scion/blob/master/doc/ControlPlanePKI.md#primary-ases
 	Attributes          []Attribute             `json:"attributes"`
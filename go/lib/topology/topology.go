@@ -22,6 +22,8 @@ import (
 	"math/rand"
 	"net"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/scionproto/scion/go/lib/addr"
@@ -62,6 +64,11 @@ type (
 		IA         addr.IA
 		Attributes []jsontopo.Attribute
 		MTU        int
+		// DispatchedPortStart and DispatchedPortEnd define the inclusive range
+		// of underlay ports to which the routers deliver packets directly. If
+		// both are zero, all packets are delivered to the dispatcher.
+		DispatchedPortStart uint16
+		DispatchedPortEnd   uint16
 
 		BR        map[string]BRInfo
 		BRNames   []string
@@ -204,9 +211,35 @@ func (t *RWTopology) populateMeta(raw *jsontopo.Topology) error {
 	}
 	t.MTU = raw.MTU
 	t.Attributes = raw.Attributes
+	if raw.DispatchedPorts != "" {
+		if t.DispatchedPortStart, t.DispatchedPortEnd, err = parsePortRange(
+			raw.DispatchedPorts); err != nil {
+
+			return serrors.WrapStr("parsing dispatched ports", err)
+		}
+	}
 	return nil
 }
 
+func parsePortRange(raw string) (uint16, uint16, error) {
+	parts := strings.Split(raw, "-")
+	if len(parts) != 2 {
+		return 0, 0, serrors.New("invalid format, expected start-end", "ports", raw)
+	}
+	start, err := strconv.ParseUint(strings.TrimSpace(parts[0]), 10, 16)
+	if err != nil {
+		return 0, 0, serrors.WrapStr("parsing start port", err, "ports", raw)
+	}
+	end, err := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 16)
+	if err != nil {
+		return 0, 0, serrors.WrapStr("parsing end port", err, "ports", raw)
+	}
+	if start == 0 || start > end {
+		return 0, 0, serrors.New("invalid port range", "ports", raw)
+	}
+	return uint16(start), uint16(end), nil
+}
+
 func (t *RWTopology) populateBR(raw *jsontopo.Topology) error {
 	for name, rawBr := range raw.BorderRouters {
 		if rawBr.CtrlAddr == "" {
@@ -391,6 +424,9 @@ func (t *RWTopology) Copy() *RWTopology {
 		MTU:        t.MTU,
 		Attributes: append(t.Attributes[:0:0], t.Attributes...),
 
+		DispatchedPortStart: t.DispatchedPortStart,
+		DispatchedPortEnd:   t.DispatchedPortEnd,
+
 		BR:        copyBRMap(t.BR),
 		BRNames:   append(t.BRNames[:0:0], t.BRNames...),
 		IFInfoMap: t.IFInfoMap.copy(),
@@ -38,6 +38,64 @@ func TestMeta(t *testing.T) {
 	assert.Empty(t, c.Attributes, "Field 'Attributes'")
 }
 
+func TestDispatchedPorts(t *testing.T) {
+	testCases := map[string]struct {
+		Raw           string
+		ExpectedStart uint16
+		ExpectedEnd   uint16
+		ErrAssertion  assert.ErrorAssertionFunc
+	}{
+		"unset": {
+			ErrAssertion: assert.NoError,
+		},
+		"valid": {
+			Raw:           "31000-32767",
+			ExpectedStart: 31000,
+			ExpectedEnd:   32767,
+			ErrAssertion:  assert.NoError,
+		},
+		"single port": {
+			Raw:           "40000-40000",
+			ExpectedStart: 40000,
+			ExpectedEnd:   40000,
+			ErrAssertion:  assert.NoError,
+		},
+		"reversed": {
+			Raw:          "32767-31000",
+			ErrAssertion: assert.Error,
+		},
+		"zero start": {
+			Raw:          "0-100",
+			ErrAssertion: assert.Error,
+		},
+		"out of range": {
+			Raw:          "31000-70000",
+			ErrAssertion: assert.Error,
+		},
+		"missing end": {
+			Raw:          "31000",
+			ErrAssertion: assert.Error,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			topo := NewRWTopology()
+			err := topo.populateMeta(&jsontopo.Topology{
+				IA:              "1-ff00:0:110",
+				DispatchedPorts: tc.Raw,
+			})
+			tc.ErrAssertion(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.ExpectedStart, topo.DispatchedPortStart)
+			assert.Equal(t, tc.ExpectedEnd, topo.DispatchedPortEnd)
+		})
+	}
+}
+
 func Test_Active(t *testing.T) {
 	t.Run("positive TTL", func(t *testing.T) {
 		c := MustLoadTopo(t, "testdata/basic.json")
@@ -114,6 +114,13 @@ var DefaultGraphDescription = &Description{
 		"2-ff00:0:221", // X = 24
 		"2-ff00:0:222", // X = 26
 	},
+	Core: []string{
+		"1-ff00:0:110",
+		"1-ff00:0:120",
+		"1-ff00:0:130",
+		"2-ff00:0:210",
+		"2-ff00:0:220",
+	},
 	Edges: []EdgeDesc{
 		{"1-ff00:0:110", If_110_X_120_A, "1-ff00:0:120", If_120_A_110_X, false},
 		{"1-ff00:0:110", If_110_X_130_A, "1-ff00:0:130", If_130_A_110_X, false},
@@ -13,12 +13,14 @@
 // limitations under the License.
 
 // Package graph implements a multigraph model of a SCION network for use in
-// tests. The default Mock SCIOND implementation uses the graph to simulate
-// path lookups.
+// tests.
 //
-// Note that the graph always returns the shortest paths, regardless whether
-// they are valid SCION paths (e.g., the path might cross multiple peering
-// links).
+// Segments simulates beaconing over the graph and returns real up, core and
+// down segments, including peer entries. Valid SCION paths are constructed
+// from these segments with the combinator, see the pathprovider package.
+//
+// GetPaths returns the shortest paths instead, regardless whether they are
+// valid SCION paths (e.g., the path might cross multiple peering links).
 package graph
 
 import (
@@ -55,10 +57,16 @@ type Graph struct {
 	links map[common.IFIDType]common.IFIDType
 	// specifies whether an IFID is on a peering link
 	isPeer map[common.IFIDType]bool
+	// specifies whether an IFID is on the parent side of a link
+	isParent map[common.IFIDType]bool
 	// maps IFIDs to the AS they belong to
 	parents map[common.IFIDType]addr.IA
 	// maps ASes to a structure containing a slice of their IFIDs
 	ases map[addr.IA]*AS
+	// specifies whether an AS is a core AS
+	core map[addr.IA]bool
+	// caches the segments created by beaconing, indexed by the egress IFIDs
+	segments map[string]*seg.PathSegment
 
 	signers map[addr.IA]Signer
 
@@ -69,19 +77,30 @@ type Graph struct {
 // New allocates a new empty graph.
 func New(ctrl *gomock.Controller) *Graph {
 	return &Graph{
-		ctrl:    ctrl,
-		links:   make(map[common.IFIDType]common.IFIDType),
-		isPeer:  make(map[common.IFIDType]bool),
-		parents: make(map[common.IFIDType]addr.IA),
-		ases:    make(map[addr.IA]*AS),
-		signers: make(map[addr.IA]Signer),
+		ctrl:     ctrl,
+		links:    make(map[common.IFIDType]common.IFIDType),
+		isPeer:   make(map[common.IFIDType]bool),
+		isParent: make(map[common.IFIDType]bool),
+		parents:  make(map[common.IFIDType]addr.IA),
+		ases:     make(map[addr.IA]*AS),
+		core:     make(map[addr.IA]bool),
+		segments: make(map[string]*seg.PathSegment),
+		signers:  make(map[addr.IA]Signer),
 	}
 }
 
 // NewFromDescription initializes a new graph from description desc.
 func NewFromDescription(ctrl *gomock.Controller, desc *Description) *Graph {
 	graph := New(ctrl)
+	core := make(map[string]bool, len(desc.Core))
+	for _, node := range desc.Core {
+		core[node] = true
+	}
 	for _, node := range desc.Nodes {
+		if core[node] {
+			graph.AddCore(node)
+			continue
+		}
 		graph.Add(node)
 	}
 	for _, edge := range desc.Edges {
@@ -100,10 +119,22 @@ func (g *Graph) Add(ia string) {
 		IFIDs: make(map[common.IFIDType]struct{}),
 	}
 	g.signers[isdas] = NewSigner()
+	g.segments = make(map[string]*seg.PathSegment)
+}
+
+// AddCore adds a new core AS to the graph. If ia is not a valid string
+// representation of an ISD-AS, AddCore panics.
+func (g *Graph) AddCore(ia string) {
+	g.Add(ia)
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.core[MustParseIA(ia)] = true
 }
 
 // AddLink adds a new edge between the ASes described by xIA and yIA, with
-// xIFID in xIA and yIFID in yIA. If xIA or yIA are not valid string
+// xIFID in xIA and yIFID in yIA. A link between two core ASes that is not a
+// peering link is a core link. For all other links that are not peering
+// links, xIA is the parent of yIA. If xIA or yIA are not valid string
 // representations of an ISD-AS, AddLink panics.
 func (g *Graph) AddLink(xIA string, xIFID common.IFIDType,
 	yIA string, yIFID common.IFIDType, peer bool) {
@@ -128,10 +159,12 @@ func (g *Graph) AddLink(xIA string, xIFID common.IFIDType,
 	g.links[yIFID] = xIFID
 	g.isPeer[xIFID] = peer
 	g.isPeer[yIFID] = peer
+	g.isParent[xIFID] = !peer
 	g.parents[xIFID] = x
 	g.parents[yIFID] = y
 	g.ases[x].IFIDs[xIFID] = struct{}{}
 	g.ases[y].IFIDs[yIFID] = struct{}{}
+	g.segments = make(map[string]*seg.PathSegment)
 }
 
 // RemoveLink deletes the edge containing ifid from the graph.
@@ -146,10 +179,13 @@ func (g *Graph) RemoveLink(ifid common.IFIDType) {
 	delete(g.links, neighborIFID)
 	delete(g.isPeer, ifid)
 	delete(g.isPeer, neighborIFID)
+	delete(g.isParent, ifid)
+	delete(g.isParent, neighborIFID)
 	delete(g.parents, ifid)
 	delete(g.parents, neighborIFID)
 	g.ases[ia].Delete(ifid)
 	g.ases[neighborIA].Delete(neighborIFID)
+	g.segments = make(map[string]*seg.PathSegment)
 }
 
 // GetParent returns the parent AS of ifid.
@@ -163,8 +199,9 @@ func (g *Graph) GetParent(ifid common.IFIDType) addr.IA {
 // slice containing an empty path is returned. If no path exists between xIA
 // and yIA, a 0-length slice is returned.
 //
-// Note that this always returns shortest length paths, even if they might not
-// be valid SCION paths.
+// The paths are the shortest paths in the graph and ignore the link types.
+// Hence, they might not be valid SCION paths. Use the pathprovider package to
+// get valid paths.
 func (g *Graph) GetPaths(xIA string, yIA string) [][]common.IFIDType {
 	g.lock.Lock()
 	defer g.lock.Unlock()
@@ -320,6 +357,7 @@ func (g *Graph) beacon(ifids []common.IFIDType, addStaticInfo bool) *seg.PathSeg
 // counterpart. This is useful for testing IFID misconfigurations.
 func (g *Graph) DeleteInterface(ifid common.IFIDType) {
 	delete(g.links, ifid)
+	g.segments = make(map[string]*seg.PathSegment)
 }
 
 // Latency returns an arbitrary test latency value between two interfaces. The
@@ -484,10 +522,13 @@ func MustParseIA(ia string) addr.IA {
 // one shot initilizations.
 type Description struct {
 	Nodes []string
+	// Core lists the nodes that are core ASes.
+	Core  []string
 	Edges []EdgeDesc
 }
 
-// EdgeDesc is used in Descriptions to describe the links between ASes.
+// EdgeDesc is used in Descriptions to describe the links between ASes. See
+// Graph.AddLink for how the link type is determined.
 type EdgeDesc struct {
 	Xia   string
 	Xifid common.IFIDType
@@ -0,0 +1,57 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pathprovider constructs valid SCION paths in a test graph. The
+// paths are combined from the segments that are created by simulating
+// beaconing over the graph, in the same way as the SCION Daemon combines the
+// segments it fetches. Hence, the paths contain shortcuts and peering links
+// only where SCION allows them.
+package pathprovider
+
+import (
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/infra/modules/combinator"
+	"github.com/scionproto/scion/go/lib/snet"
+	snetpath "github.com/scionproto/scion/go/lib/snet/path"
+	"github.com/scionproto/scion/go/lib/xtest/graph"
+)
+
+// PathProvider provides the paths between the ASes of a test graph.
+type PathProvider struct {
+	g *graph.Graph
+}
+
+// New returns a path provider for the graph.
+func New(g *graph.Graph) PathProvider {
+	return PathProvider{g: g}
+}
+
+// GetPaths returns the paths from src to dst, sorted from best to worst. If
+// src and dst are equal, a single empty path is returned.
+func (p PathProvider) GetPaths(src, dst addr.IA) []snet.Path {
+	if src.Equal(dst) {
+		return []snet.Path{snetpath.Path{Dst: dst}}
+	}
+	ups, cores, downs := p.g.Segments(src, dst)
+	combined := combinator.Combine(src, dst, ups, cores, downs, false)
+	paths := make([]snet.Path, 0, len(combined))
+	for _, path := range combined {
+		paths = append(paths, snetpath.Path{
+			Dst:   dst,
+			SPath: path.SPath,
+			Meta:  path.Metadata,
+		})
+	}
+	return paths
+}
@@ -0,0 +1,130 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+)
+
+// Segments simulates beaconing over the graph, and returns the segments that
+// are required to construct the paths from src to dst: the up segments that
+// end in src, all core segments, and the down segments that end in dst. Core
+// ASes do not have up or down segments.
+//
+// Intra-ISD beacons are originated by every core AS and propagated from
+// parents to children. Core beacons are originated by every core AS and
+// propagated over core links, without visiting an AS twice. The segments
+// contain the peer entries of all peering links, and static info metadata.
+// The same segment is returned for the same beacon, until the graph is
+// modified.
+func (g *Graph) Segments(src, dst addr.IA) (ups, cores, downs []*seg.PathSegment) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	for _, trail := range g.beaconTrails(false) {
+		end := g.parents[g.links[trail[len(trail)-1]]]
+		if end.Equal(src) && !g.core[src] {
+			ups = append(ups, g.segment(trail))
+		}
+		if end.Equal(dst) && !g.core[dst] {
+			downs = append(downs, g.segment(trail))
+		}
+	}
+	for _, trail := range g.beaconTrails(true) {
+		cores = append(cores, g.segment(trail))
+	}
+	return ups, cores, downs
+}
+
+// segment returns the segment for the beacon that is propagated across the
+// egress IFIDs.
+func (g *Graph) segment(trail []common.IFIDType) *seg.PathSegment {
+	key := fmt.Sprint(trail)
+	if segment, ok := g.segments[key]; ok {
+		return segment
+	}
+	segment := g.beacon(trail, true)
+	g.segments[key] = segment
+	return segment
+}
+
+// beaconTrails returns the egress IFIDs of all beacons that are originated by
+// the core ASes. If core is set, the beacons are propagated over core links,
+// otherwise from parents to children.
+func (g *Graph) beaconTrails(core bool) [][]common.IFIDType {
+	var trails [][]common.IFIDType
+	var propagate func(ia addr.IA, trail []common.IFIDType, visited map[addr.IA]bool)
+	propagate = func(ia addr.IA, trail []common.IFIDType, visited map[addr.IA]bool) {
+		for _, ifid := range g.sortedIFIDs(ia) {
+			if !g.propagates(ifid, core) {
+				continue
+			}
+			next := g.parents[g.links[ifid]]
+			if visited[next] {
+				continue
+			}
+			// Copy to avoid mutating the trails of other beacons.
+			nextTrail := append(append([]common.IFIDType{}, trail...), ifid)
+			trails = append(trails, nextTrail)
+			visited[next] = true
+			propagate(next, nextTrail, visited)
+			delete(visited, next)
+		}
+	}
+	var origins []addr.IA
+	for ia := range g.core {
+		origins = append(origins, ia)
+	}
+	sort.Slice(origins, func(i, j int) bool {
+		return origins[i].IAInt() < origins[j].IAInt()
+	})
+	for _, origin := range origins {
+		propagate(origin, nil, map[addr.IA]bool{origin: true})
+	}
+	return trails
+}
+
+// propagates indicates whether beacons are propagated across the egress
+// ifid. If core is set, only core links propagate beacons, otherwise only
+// links to children.
+func (g *Graph) propagates(ifid common.IFIDType, core bool) bool {
+	remote, ok := g.links[ifid]
+	if !ok || g.isPeer[ifid] {
+		return false
+	}
+	coreLink := g.core[g.parents[ifid]] && g.core[g.parents[remote]]
+	if core {
+		return coreLink
+	}
+	return !coreLink && g.isParent[ifid]
+}
+
+func (g *Graph) sortedIFIDs(ia addr.IA) []common.IFIDType {
+	as, ok := g.ases[ia]
+	if !ok {
+		return nil
+	}
+	ifids := make([]common.IFIDType, 0, len(as.IFIDs))
+	for ifid := range as.IFIDs {
+		ifids = append(ifids, ifid)
+	}
+	sort.Slice(ifids, func(i, j int) bool { return ifids[i] < ifids[j] })
+	return ifids
+}
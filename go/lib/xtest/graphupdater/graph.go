@@ -70,15 +70,17 @@ type Graph struct {
 	sortedIfaces []iface
 	IfaceIds     map[iface]int
 	links        []link
+	core         []string
 }
 
-func newGraph(links []link, staticIfaceIds map[string]int) *Graph {
+func newGraph(links []link, core []string, staticIfaceIds map[string]int) *Graph {
 	ifaces, links := extractIfaces(links)
 	ifaces = sortIfaces(ifaces)
 	g := &Graph{
 		sortedIfaces: ifaces,
 		IfaceIds:     generateIfaceIds(ifaces, staticIfaceIds),
 		links:        links,
+		core:         core,
 	}
 	return g
 }
@@ -95,6 +97,7 @@ func (g *Graph) Write(w io.Writer) (int, error) {
 		fmt.Sprintf("var (\n%s\n)\n", strings.Join(g.interfaces(), "\n")),
 		"var DefaultGraphDescription = &Description{",
 		fmt.Sprintf("Nodes: []string{\n%v\n},", strings.Join(g.nodes(), "\n")),
+		fmt.Sprintf("Core: []string{\n%v\n},", strings.Join(g.coreNodes(), "\n")),
 		fmt.Sprintf("Edges: []EdgeDesc{\n%v\n},", strings.Join(g.edges(), "\n")),
 		"}",
 	}
@@ -126,6 +129,14 @@ func (g *Graph) nodes() []string {
 	return res
 }
 
+func (g *Graph) coreNodes() []string {
+	res := make([]string, 0, len(g.core))
+	for _, ia := range g.core {
+		res = append(res, fmt.Sprintf("%q,", ia))
+	}
+	return res
+}
+
 func (g *Graph) edges() []string {
 	res := make([]string, 0, len(g.links))
 	for _, l := range g.links {
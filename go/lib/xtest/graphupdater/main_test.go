@@ -32,4 +32,6 @@ func TestGeneratedUpToDate(t *testing.T) {
 	}
 	assert.Equal(t, graph.StaticIfaceIdMapping, graphMapping,
 		"Generated graph is out of date, run graphupdater")
+	assert.Equal(t, graph.DefaultGraphDescription.Core, g.core,
+		"Generated graph is out of date, run graphupdater")
 }
@@ -16,9 +16,11 @@ package main
 
 import (
 	"bytes"
+	"fmt"
 	"go/format"
 	"io/ioutil"
 	"os"
+	"sort"
 
 	yaml "gopkg.in/yaml.v2"
 
@@ -54,7 +56,25 @@ func LoadGraph(topoFile string) (*Graph, error) {
 	if err != nil {
 		return nil, serrors.WrapStr("Failed to load Topo", err)
 	}
-	return newGraph(t.Links, graph.StaticIfaceIdMapping), nil
+	return newGraph(t.Links, t.coreASes(), graph.StaticIfaceIdMapping), nil
+}
+
+// coreASes returns the ASes that are marked as core in the topology.
+func (t *topo) coreASes() []string {
+	var core []string
+	for _, item := range t.ASes {
+		attrs, ok := item.Value.(yaml.MapSlice)
+		if !ok {
+			continue
+		}
+		for _, attr := range attrs {
+			if attr.Key == "core" && attr.Value == true {
+				core = append(core, fmt.Sprint(item.Key))
+			}
+		}
+	}
+	sort.Strings(core)
+	return core
 }
 
 // WriteGraphToFile writes the default graph from topoFile to the destFile.
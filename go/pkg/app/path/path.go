@@ -78,6 +78,42 @@ func Choose(
 ) (snet.Path, error) {
 
 	o := applyOption(opts)
+	paths, err := fetch(ctx, conn, remote, o)
+	if err != nil {
+		return nil, err
+	}
+	if o.interactive {
+		return printAndChoose(paths, remote, o.colorScheme)
+	}
+
+	return paths[rand.Intn(len(paths))], nil
+}
+
+// Fetch fetches all paths to the remote that match the sequence. If probing is
+// enabled, only the healthy paths are returned. The paths are sorted. The
+// interactive option is ignored.
+func Fetch(
+	ctx context.Context,
+	conn sciond.Connector,
+	remote addr.IA,
+	opts ...Option,
+) ([]snet.Path, error) {
+
+	paths, err := fetch(ctx, conn, remote, applyOption(opts))
+	if err != nil {
+		return nil, err
+	}
+	Sort(paths)
+	return paths, nil
+}
+
+func fetch(
+	ctx context.Context,
+	conn sciond.Connector,
+	remote addr.IA,
+	o options,
+) ([]snet.Path, error) {
+
 	paths, err := fetchPaths(ctx, conn, remote, o.refresh, o.seq)
 	if err != nil {
 		return nil, serrors.WrapStr("fetching paths", err)
@@ -88,11 +124,7 @@ func Choose(
 			return nil, serrors.WrapStr("probing paths", err)
 		}
 	}
-	if o.interactive {
-		return printAndChoose(paths, remote, o.colorScheme)
-	}
-
-	return paths[rand.Intn(len(paths))], nil
+	return paths, nil
 }
 
 func filterUnhealthy(
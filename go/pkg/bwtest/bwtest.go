@@ -0,0 +1,315 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bwtest implements a bandwidth test between a client and a server.
+//
+// The client negotiates the test parameters for both directions with the
+// server using control messages, which are retransmitted until they are
+// answered. Before accepting a test, the server sends a cookie to the client
+// address, which the client must echo in its request. Thus, the server only
+// sends data packets to addresses that take part in the test. Afterwards, the
+// client and the server concurrently send data packets at the negotiated rate,
+// and the receivers measure the achieved rate, the loss and the inter-arrival
+// jitter. Finally, the client fetches the statistics measured by the server.
+//
+// Control and data packets are exchanged on the same connection. The package
+// works on any net.PacketConn, in particular on SCION connections created with
+// snet.
+package bwtest
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+const (
+	// MinPacketSize is the minimum size of a data packet.
+	MinPacketSize = dataHdrLen
+	// MaxPacketSize is the maximum size of a data packet.
+	MaxPacketSize = 65000
+	// MaxDuration is the maximum duration of a test in one direction.
+	MaxDuration = 5 * time.Minute
+)
+
+// Parameters are the parameters of a test in one direction.
+type Parameters struct {
+	// Duration is the time during which data packets are sent.
+	Duration time.Duration `json:"duration"`
+	// PacketSize is the payload size of the data packets in bytes.
+	PacketSize int `json:"packet_size"`
+	// Rate is the target rate in bits per second.
+	Rate int64 `json:"rate"`
+}
+
+// ParseParameters parses parameters of the form
+// "<duration>,<packet size>,<rate>", e.g., "3s,1000,10Mbps".
+func ParseParameters(s string) (Parameters, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 3 {
+		return Parameters{}, serrors.New("expected <duration>,<packet size>,<rate>",
+			"input", s)
+	}
+	duration, err := time.ParseDuration(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return Parameters{}, serrors.WrapStr("parsing duration", err)
+	}
+	size, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return Parameters{}, serrors.WrapStr("parsing packet size", err)
+	}
+	rate, err := ParseRate(strings.TrimSpace(parts[2]))
+	if err != nil {
+		return Parameters{}, err
+	}
+	p := Parameters{Duration: duration, PacketSize: size, Rate: rate}
+	if err := p.Validate(); err != nil {
+		return Parameters{}, err
+	}
+	return p, nil
+}
+
+// ParseRate parses a rate in bits per second. The rate must have one of the
+// units bps, kbps, Mbps or Gbps, e.g., "10Mbps".
+func ParseRate(s string) (int64, error) {
+	units := []struct {
+		suffix string
+		factor float64
+	}{
+		{"Gbps", 1e9},
+		{"Mbps", 1e6},
+		{"kbps", 1e3},
+		{"bps", 1},
+	}
+	for _, u := range units {
+		if !strings.HasSuffix(s, u.suffix) {
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimSuffix(s, u.suffix), 64)
+		if err != nil {
+			return 0, serrors.WrapStr("parsing rate", err, "input", s)
+		}
+		return int64(v * u.factor), nil
+	}
+	return 0, serrors.New("rate without unit (bps, kbps, Mbps or Gbps)", "input", s)
+}
+
+// Validate checks that the parameters are within the supported bounds.
+func (p Parameters) Validate() error {
+	if p.Duration <= 0 || p.Duration > MaxDuration {
+		return serrors.New("invalid duration", "duration", p.Duration, "max", MaxDuration)
+	}
+	if p.PacketSize < MinPacketSize || p.PacketSize > MaxPacketSize {
+		return serrors.New("invalid packet size", "size", p.PacketSize,
+			"min", MinPacketSize, "max", MaxPacketSize)
+	}
+	if p.Rate <= 0 {
+		return serrors.New("invalid rate", "rate", p.Rate)
+	}
+	if p.interval() <= 0 {
+		return serrors.New("rate too high for packet size", "rate", p.Rate,
+			"size", p.PacketSize)
+	}
+	return nil
+}
+
+// interval returns the time between two data packets.
+func (p Parameters) interval() time.Duration {
+	return time.Duration(float64(p.PacketSize*8) / float64(p.Rate) * float64(time.Second))
+}
+
+// Stats contains the statistics of a test in one direction.
+type Stats struct {
+	// Sent is the number of data packets sent.
+	Sent int64 `json:"sent"`
+	// Received is the number of data packets received.
+	Received int64 `json:"received"`
+	// ReceivedBytes is the number of payload bytes received.
+	ReceivedBytes int64 `json:"received_bytes"`
+	// Rate is the achieved rate in bits per second.
+	Rate int64 `json:"rate"`
+	// Loss is the fraction of data packets that were not received.
+	Loss float64 `json:"loss"`
+	// Jitter is the inter-arrival jitter as defined in RFC 3550.
+	Jitter time.Duration `json:"jitter"`
+}
+
+// Result contains the statistics of a test in both directions.
+type Result struct {
+	ClientToServer Stats `json:"client_to_server"`
+	ServerToClient Stats `json:"server_to_client"`
+}
+
+// Message types.
+const (
+	msgRequest byte = iota + 1
+	msgAccept
+	msgData
+	msgResultRequest
+	msgResult
+	msgChallenge
+)
+
+// control is the payload of all control messages.
+type control struct {
+	ID             uint64      `json:"id"`
+	ClientToServer *Parameters `json:"client_to_server,omitempty"`
+	ServerToClient *Parameters `json:"server_to_client,omitempty"`
+	// Stats are the statistics measured by the server.
+	Stats *receiverStats `json:"stats,omitempty"`
+	// Sent is the number of data packets sent by the server.
+	Sent  int64  `json:"sent,omitempty"`
+	Error string `json:"error,omitempty"`
+	// Cookie is sent by the server in a challenge and echoed by the client in
+	// its request to prove that it receives packets at its address.
+	Cookie []byte `json:"cookie,omitempty"`
+}
+
+func encodeControl(t byte, c control) ([]byte, error) {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{t}, raw...), nil
+}
+
+func decodeControl(b []byte) (control, error) {
+	var c control
+	if err := json.Unmarshal(b[1:], &c); err != nil {
+		return control{}, serrors.WrapStr("decoding control message", err)
+	}
+	return c, nil
+}
+
+// dataHdrLen is the length of the data packet header, consisting of the type,
+// the test ID, the sequence number and the send timestamp.
+const dataHdrLen = 1 + 8 + 8 + 8
+
+type dataHdr struct {
+	ID       uint64
+	Sequence uint64
+	Sent     time.Time
+}
+
+func (h dataHdr) encode(b []byte) {
+	b[0] = msgData
+	binary.BigEndian.PutUint64(b[1:], h.ID)
+	binary.BigEndian.PutUint64(b[9:], h.Sequence)
+	binary.BigEndian.PutUint64(b[17:], uint64(h.Sent.UnixNano()))
+}
+
+func decodeDataHdr(b []byte) (dataHdr, error) {
+	if len(b) < dataHdrLen {
+		return dataHdr{}, serrors.New("data packet too short", "len", len(b))
+	}
+	return dataHdr{
+		ID:       binary.BigEndian.Uint64(b[1:]),
+		Sequence: binary.BigEndian.Uint64(b[9:]),
+		Sent:     time.Unix(0, int64(binary.BigEndian.Uint64(b[17:]))),
+	}, nil
+}
+
+// send sends data packets to the destination at the rate given by the
+// parameters. It returns the number of data packets sent.
+func send(ctx context.Context, conn net.PacketConn, dst net.Addr, id uint64,
+	p Parameters) (int64, error) {
+
+	buf := make([]byte, p.PacketSize)
+	interval := p.interval()
+	start := time.Now()
+	var sent int64
+	for {
+		// The send time of every packet is derived from the start time, such
+		// that delays in sending are compensated by sending in bursts.
+		next := start.Add(time.Duration(sent) * interval)
+		if next.Sub(start) >= p.Duration {
+			return sent, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+		if d := time.Until(next); d > 0 {
+			time.Sleep(d)
+		}
+		dataHdr{ID: id, Sequence: uint64(sent), Sent: time.Now()}.encode(buf)
+		if _, err := conn.WriteTo(buf, dst); err != nil {
+			return sent, serrors.WrapStr("sending data packet", err)
+		}
+		sent++
+	}
+}
+
+// receiverStats are the statistics measured by a receiver.
+type receiverStats struct {
+	Received      int64         `json:"received"`
+	ReceivedBytes int64         `json:"received_bytes"`
+	First         time.Time     `json:"first"`
+	Last          time.Time     `json:"last"`
+	Jitter        time.Duration `json:"jitter"`
+}
+
+// stats computes the statistics of a direction based on the number of sent
+// packets and the parameters.
+func (r receiverStats) stats(sent int64, p Parameters) Stats {
+	s := Stats{
+		Sent:          sent,
+		Received:      r.Received,
+		ReceivedBytes: r.ReceivedBytes,
+		Jitter:        r.Jitter,
+	}
+	if sent > 0 {
+		s.Loss = math.Max(1-float64(r.Received)/float64(sent), 0)
+	}
+	// The achieved rate is measured over the test duration, unless receiving
+	// took longer than that.
+	duration := p.Duration
+	if span := r.Last.Sub(r.First); span > duration {
+		duration = span
+	}
+	if duration > 0 {
+		s.Rate = int64(float64(r.ReceivedBytes*8) / duration.Seconds())
+	}
+	return s
+}
+
+// receiver measures the statistics of received data packets.
+type receiver struct {
+	stats       receiverStats
+	lastTransit time.Duration
+	jitter      float64
+}
+
+func (r *receiver) record(hdr dataHdr, size int, now time.Time) {
+	// The transit time includes the clock offset between sender and receiver,
+	// which cancels out in the difference between consecutive packets.
+	transit := now.Sub(hdr.Sent)
+	if r.stats.Received > 0 {
+		d := math.Abs(float64(transit - r.lastTransit))
+		r.jitter += (d - r.jitter) / 16
+	} else {
+		r.stats.First = now
+	}
+	r.lastTransit = transit
+	r.stats.Last = now
+	r.stats.Received++
+	r.stats.ReceivedBytes += int64(size)
+	r.stats.Jitter = time.Duration(r.jitter)
+}
@@ -0,0 +1,281 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bwtest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/pkg/bwtest"
+)
+
+func TestParseParameters(t *testing.T) {
+	testCases := map[string]struct {
+		Input     string
+		Expected  bwtest.Parameters
+		AssertErr assert.ErrorAssertionFunc
+	}{
+		"valid": {
+			Input: "3s,1000,10Mbps",
+			Expected: bwtest.Parameters{
+				Duration:   3 * time.Second,
+				PacketSize: 1000,
+				Rate:       10_000_000,
+			},
+			AssertErr: assert.NoError,
+		},
+		"fractional rate": {
+			Input: "500ms, 100, 1.5kbps",
+			Expected: bwtest.Parameters{
+				Duration:   500 * time.Millisecond,
+				PacketSize: 100,
+				Rate:       1500,
+			},
+			AssertErr: assert.NoError,
+		},
+		"missing part": {
+			Input:     "3s,1000",
+			AssertErr: assert.Error,
+		},
+		"rate without unit": {
+			Input:     "3s,1000,1000",
+			AssertErr: assert.Error,
+		},
+		"packet too small": {
+			Input:     "3s,10,1Mbps",
+			AssertErr: assert.Error,
+		},
+		"duration too long": {
+			Input:     "1h,1000,1Mbps",
+			AssertErr: assert.Error,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			p, err := bwtest.ParseParameters(tc.Input)
+			tc.AssertErr(t, err)
+			assert.Equal(t, tc.Expected, p)
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	serverConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer serverConn.Close()
+	server := &bwtest.Server{Conn: serverConn}
+	serverDone := make(chan error)
+	go func() { serverDone <- server.Serve(ctx) }()
+
+	clientConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer clientConn.Close()
+
+	params := bwtest.Parameters{
+		Duration:   200 * time.Millisecond,
+		PacketSize: 100,
+		Rate:       400_000,
+	}
+	res, err := bwtest.Run(ctx, bwtest.Config{
+		Conn:           clientConn,
+		Server:         serverConn.LocalAddr(),
+		ClientToServer: params,
+		ServerToClient: params,
+		Timeout:        200 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	for name, stats := range map[string]bwtest.Stats{
+		"client to server": res.ClientToServer,
+		"server to client": res.ServerToClient,
+	} {
+		// 400kbps with 100 byte packets results in a packet every 2ms.
+		assert.Equal(t, int64(100), stats.Sent, name)
+		assert.Equal(t, stats.Sent, stats.Received, name)
+		assert.Equal(t, stats.Received*100, stats.ReceivedBytes, name)
+		assert.Equal(t, 0.0, stats.Loss, name)
+		assert.InDelta(t, 400_000, stats.Rate, 100_000, name)
+	}
+
+	cancel()
+	assert.NoError(t, <-serverDone)
+}
+
+func TestRunErrors(t *testing.T) {
+	valid := bwtest.Parameters{
+		Duration:   10 * time.Millisecond,
+		PacketSize: 100,
+		Rate:       1_000_000,
+	}
+	testCases := map[string]struct {
+		ClientToServer bwtest.Parameters
+		ServerToClient bwtest.Parameters
+	}{
+		"invalid client to server": {
+			ServerToClient: valid,
+		},
+		"invalid server to client": {
+			ClientToServer: valid,
+		},
+		"no server": {
+			ClientToServer: valid,
+			ServerToClient: valid,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			// Nobody answers on the server address.
+			server, err := net.ListenPacket("udp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer server.Close()
+			conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer conn.Close()
+
+			_, err = bwtest.Run(ctx, bwtest.Config{
+				Conn:           conn,
+				Server:         server.LocalAddr(),
+				ClientToServer: tc.ClientToServer,
+				ServerToClient: tc.ServerToClient,
+				Timeout:        10 * time.Millisecond,
+				Attempts:       2,
+			})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestServerLimits(t *testing.T) {
+	params := bwtest.Parameters{
+		Duration:   300 * time.Millisecond,
+		PacketSize: 100,
+		Rate:       100_000,
+	}
+	testCases := map[string]struct {
+		Server         bwtest.Server
+		ServerToClient bwtest.Parameters
+		Concurrent     bool
+		ErrContains    string
+	}{
+		"rate above limit": {
+			Server:         bwtest.Server{MaxRate: 50_000},
+			ServerToClient: params,
+			ErrContains:    "rate exceeds server limit",
+		},
+		"duration above limit": {
+			Server:         bwtest.Server{MaxDuration: 100 * time.Millisecond},
+			ServerToClient: params,
+			ErrContains:    "duration exceeds server limit",
+		},
+		"too many sessions": {
+			Server:         bwtest.Server{MaxSessions: 1},
+			ServerToClient: params,
+			Concurrent:     true,
+			ErrContains:    "too many tests",
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			serverConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer serverConn.Close()
+			server := tc.Server
+			server.Conn = serverConn
+			go server.Serve(ctx)
+
+			run := func() error {
+				conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+				require.NoError(t, err)
+				defer conn.Close()
+				_, err = bwtest.Run(ctx, bwtest.Config{
+					Conn:           conn,
+					Server:         serverConn.LocalAddr(),
+					ClientToServer: params,
+					ServerToClient: tc.ServerToClient,
+					Timeout:        100 * time.Millisecond,
+				})
+				return err
+			}
+			if tc.Concurrent {
+				first := make(chan error, 1)
+				go func() { first <- run() }()
+				// Give the first test time to be accepted.
+				time.Sleep(100 * time.Millisecond)
+				err := run()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.ErrContains)
+				assert.NoError(t, <-first)
+				return
+			}
+			err = run()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.ErrContains)
+		})
+	}
+}
+
+func TestServerChallenge(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	serverConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer serverConn.Close()
+	server := &bwtest.Server{Conn: serverConn}
+	go server.Serve(ctx)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// A request without a cookie is answered with a challenge, and no data
+	// packets are sent.
+	params := `{"duration":100000000,"packet_size":100,"rate":100000}`
+	req := append([]byte{1}, fmt.Sprintf(
+		`{"id":1,"client_to_server":%s,"server_to_client":%s}`, params, params)...)
+	_, err = conn.WriteTo(req, serverConn.LocalAddr())
+	require.NoError(t, err)
+
+	buf := make([]byte, bwtest.MaxPacketSize)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(300*time.Millisecond)))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.Equal(t, byte(6), buf[0], "challenge expected")
+	assert.Less(t, n, len(req), "challenge must not amplify the request")
+	_, _, err = conn.ReadFrom(buf)
+	var netErr net.Error
+	require.True(t, errors.As(err, &netErr) && netErr.Timeout(), "no data expected: %v", err)
+}
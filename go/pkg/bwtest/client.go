@@ -0,0 +1,228 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bwtest
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+const (
+	// DefaultTimeout is the default time to wait for the answer to a control
+	// message.
+	DefaultTimeout = time.Second
+	// DefaultAttempts is the default number of attempts to send a control
+	// message.
+	DefaultAttempts = 5
+)
+
+// Config configures the bandwidth test of a client.
+type Config struct {
+	// Conn is the connection the client sends the test packets on.
+	Conn net.PacketConn
+	// Server is the address of the server.
+	Server net.Addr
+	// ClientToServer are the parameters of the test from the client to the
+	// server.
+	ClientToServer Parameters
+	// ServerToClient are the parameters of the test from the server to the
+	// client.
+	ServerToClient Parameters
+
+	// Timeout is the time to wait for the answer to a control message before
+	// it is retransmitted. If zero, DefaultTimeout is used.
+	Timeout time.Duration
+	// Attempts is the number of attempts to send a control message. If zero,
+	// DefaultAttempts is used.
+	Attempts int
+}
+
+type message struct {
+	t byte
+	c control
+}
+
+type client struct {
+	Config
+	id       uint64
+	controls chan message
+
+	mtx      sync.Mutex
+	receiver receiver
+}
+
+// Run runs a bandwidth test with the server in both directions.
+func Run(ctx context.Context, cfg Config) (Result, error) {
+	if err := cfg.ClientToServer.Validate(); err != nil {
+		return Result{}, serrors.WrapStr("client to server", err)
+	}
+	if err := cfg.ServerToClient.Validate(); err != nil {
+		return Result{}, serrors.WrapStr("server to client", err)
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.Attempts == 0 {
+		cfg.Attempts = DefaultAttempts
+	}
+	c := &client{
+		Config:   cfg,
+		id:       rand.Uint64(),
+		controls: make(chan message, 10),
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer log.HandlePanic()
+		defer wg.Done()
+		c.read(done)
+	}()
+	defer func() {
+		close(done)
+		c.Conn.SetReadDeadline(time.Now())
+		wg.Wait()
+		c.Conn.SetReadDeadline(time.Time{})
+	}()
+
+	req := control{
+		ID:             c.id,
+		ClientToServer: &cfg.ClientToServer,
+		ServerToClient: &cfg.ServerToClient,
+	}
+	accept, err := c.exchange(ctx, msgRequest, req, msgAccept, msgChallenge)
+	if err != nil {
+		return Result{}, serrors.WrapStr("negotiating test", err)
+	}
+	if accept.t == msgChallenge {
+		// Echo the cookie to prove that we receive packets at our address.
+		req.Cookie = accept.c.Cookie
+		if accept, err = c.exchange(ctx, msgRequest, req, msgAccept); err != nil {
+			return Result{}, serrors.WrapStr("negotiating test", err)
+		}
+	}
+	if accept.c.Error != "" {
+		return Result{}, serrors.New("test rejected by server", "reason", accept.c.Error)
+	}
+	start := time.Now()
+	sent, err := send(ctx, c.Conn, c.Server, c.id, cfg.ClientToServer)
+	if err != nil {
+		return Result{}, err
+	}
+	// Wait for the packets of the server, and the packets in flight.
+	wait := time.Until(start.Add(cfg.ServerToClient.Duration + cfg.Timeout))
+	select {
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	case <-time.After(wait):
+	}
+
+	msg, err := c.exchange(ctx, msgResultRequest, control{ID: c.id}, msgResult)
+	if err != nil {
+		return Result{}, serrors.WrapStr("fetching result", err)
+	}
+	res := msg.c
+	if res.Error != "" || res.Stats == nil {
+		return Result{}, serrors.New("fetching result failed", "reason", res.Error)
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return Result{
+		ClientToServer: res.Stats.stats(sent, cfg.ClientToServer),
+		ServerToClient: c.receiver.stats.stats(res.Sent, cfg.ServerToClient),
+	}, nil
+}
+
+// exchange sends the control message until an answer of one of the expected
+// types is received.
+func (c *client) exchange(ctx context.Context, t byte, req control,
+	expected ...byte) (message, error) {
+
+	raw, err := encodeControl(t, req)
+	if err != nil {
+		return message{}, err
+	}
+	for i := 0; i < c.Attempts; i++ {
+		if _, err := c.Conn.WriteTo(raw, c.Server); err != nil {
+			return message{}, serrors.WrapStr("sending control message", err)
+		}
+		timeout := time.After(c.Timeout)
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				return message{}, ctx.Err()
+			case <-timeout:
+				break wait
+			case msg := <-c.controls:
+				if msg.c.ID != c.id {
+					continue
+				}
+				for _, e := range expected {
+					if msg.t == e {
+						return msg, nil
+					}
+				}
+			}
+		}
+	}
+	return message{}, serrors.New("no answer from server", "attempts", c.Attempts)
+}
+
+// read reads from the connection until done is closed.
+func (c *client) read(done <-chan struct{}) {
+	buf := make([]byte, MaxPacketSize)
+	for {
+		n, _, err := c.Conn.ReadFrom(buf)
+		select {
+		case <-done:
+			return
+		default:
+		}
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil || n == 0 {
+			continue
+		}
+		switch buf[0] {
+		case msgData:
+			hdr, err := decodeDataHdr(buf[:n])
+			if err != nil || hdr.ID != c.id {
+				continue
+			}
+			c.mtx.Lock()
+			c.receiver.record(hdr, n, time.Now())
+			c.mtx.Unlock()
+		case msgAccept, msgResult, msgChallenge:
+			ctrl, err := decodeControl(buf[:n])
+			if err != nil {
+				continue
+			}
+			select {
+			case c.controls <- message{t: buf[0], c: ctrl}:
+			default:
+			}
+		}
+	}
+}
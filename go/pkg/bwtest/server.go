@@ -0,0 +1,269 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bwtest
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+const (
+	// DefaultMaxRate is the default maximum rate in bits per second of a test
+	// in one direction that the server accepts.
+	DefaultMaxRate = 100 * 1000 * 1000
+	// DefaultMaxSessions is the default maximum number of concurrent tests
+	// that the server accepts.
+	DefaultMaxSessions = 10
+
+	// sessionTimeout is the time a session is kept after the end of the test,
+	// such that the client can fetch the results.
+	sessionTimeout = time.Minute
+	// cookieLen is the length of the cookies sent in challenges.
+	cookieLen = 16
+)
+
+// Server answers the bandwidth tests of clients.
+type Server struct {
+	// Conn is the connection the server receives the test packets on.
+	Conn net.PacketConn
+	// ErrHandler is invoked for every error that does not cause the server to
+	// stop. Execution time must be small, as it is run synchronously.
+	ErrHandler func(error)
+	// MaxRate is the maximum rate in bits per second of a test in one
+	// direction. If zero, DefaultMaxRate is used.
+	MaxRate int64
+	// MaxDuration is the maximum duration of a test in one direction. If
+	// zero, or larger than MaxDuration, MaxDuration is used.
+	MaxDuration time.Duration
+	// MaxSessions is the maximum number of concurrent tests, including the
+	// tests whose results were not fetched yet. If zero, DefaultMaxSessions is
+	// used.
+	MaxSessions int
+}
+
+// session is the state of a test with a single client.
+type session struct {
+	params   control
+	receiver receiver
+	expiry   time.Time
+
+	mtx  sync.Mutex
+	sent int64
+}
+
+// Serve answers tests until the context is canceled or the connection fails.
+func (s *Server) Serve(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		defer log.HandlePanic()
+		<-ctx.Done()
+		// Unblock the reading of the connection.
+		s.Conn.SetReadDeadline(time.Now())
+	}()
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return serrors.WrapStr("generating cookie secret", err)
+	}
+	sessions := make(map[uint64]*session)
+	buf := make([]byte, MaxPacketSize)
+	for {
+		n, remote, err := s.Conn.ReadFrom(buf)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return err
+			}
+			s.handleErr(serrors.WrapStr("reading packet", err))
+			continue
+		}
+		if n == 0 {
+			continue
+		}
+		now := time.Now()
+		switch buf[0] {
+		case msgData:
+			hdr, err := decodeDataHdr(buf[:n])
+			if err != nil {
+				s.handleErr(err)
+				continue
+			}
+			if sess, ok := sessions[hdr.ID]; ok {
+				sess.receiver.record(hdr, n, now)
+			}
+		case msgRequest:
+			req, err := decodeControl(buf[:n])
+			if err != nil {
+				s.handleErr(err)
+				continue
+			}
+			for id, sess := range sessions {
+				if now.After(sess.expiry) {
+					delete(sessions, id)
+				}
+			}
+			// Retransmitted requests are answered again, without restarting
+			// the test.
+			if _, ok := sessions[req.ID]; !ok {
+				if err := s.validateRequest(req); err != nil {
+					s.reply(msgAccept, control{ID: req.ID, Error: err.Error()}, remote)
+					continue
+				}
+				// Data packets are only sent to clients that proved that they
+				// receive packets at their address.
+				expected := cookie(secret, req.ID, remote)
+				if !hmac.Equal(req.Cookie, expected) {
+					s.reply(msgChallenge, control{ID: req.ID, Cookie: expected}, remote)
+					continue
+				}
+				if len(sessions) >= s.maxSessions() {
+					s.reply(msgAccept, control{ID: req.ID, Error: "too many tests"}, remote)
+					continue
+				}
+				sess := &session{
+					params: req,
+					expiry: now.Add(maxDuration(req) + sessionTimeout),
+				}
+				sessions[req.ID] = sess
+				go func() {
+					defer log.HandlePanic()
+					s.send(ctx, sess, remote)
+				}()
+			}
+			s.reply(msgAccept, control{ID: req.ID}, remote)
+		case msgResultRequest:
+			req, err := decodeControl(buf[:n])
+			if err != nil {
+				s.handleErr(err)
+				continue
+			}
+			sess, ok := sessions[req.ID]
+			if !ok {
+				s.reply(msgResult, control{ID: req.ID, Error: "unknown test"}, remote)
+				continue
+			}
+			sess.mtx.Lock()
+			sent := sess.sent
+			sess.mtx.Unlock()
+			stats := sess.receiver.stats
+			s.reply(msgResult, control{ID: req.ID, Stats: &stats, Sent: sent}, remote)
+		}
+	}
+}
+
+func (s *Server) send(ctx context.Context, sess *session, remote net.Addr) {
+	sent, err := send(ctx, s.Conn, remote, sess.params.ID, *sess.params.ServerToClient)
+	sess.mtx.Lock()
+	defer sess.mtx.Unlock()
+	sess.sent = sent
+	if err != nil && ctx.Err() == nil {
+		s.handleErr(err)
+	}
+}
+
+func (s *Server) reply(t byte, c control, remote net.Addr) {
+	raw, err := encodeControl(t, c)
+	if err != nil {
+		s.handleErr(err)
+		return
+	}
+	if _, err := s.Conn.WriteTo(raw, remote); err != nil {
+		s.handleErr(serrors.WrapStr("sending control message", err))
+	}
+}
+
+func (s *Server) handleErr(err error) {
+	if s.ErrHandler != nil {
+		s.ErrHandler(err)
+	}
+}
+
+func (s *Server) validateRequest(req control) error {
+	if req.ClientToServer == nil || req.ServerToClient == nil {
+		return serrors.New("parameters missing")
+	}
+	if err := s.validateParameters(*req.ClientToServer); err != nil {
+		return serrors.WrapStr("client to server", err)
+	}
+	if err := s.validateParameters(*req.ServerToClient); err != nil {
+		return serrors.WrapStr("server to client", err)
+	}
+	return nil
+}
+
+func (s *Server) validateParameters(p Parameters) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if max := s.maxDuration(); p.Duration > max {
+		return serrors.New("duration exceeds server limit", "duration", p.Duration,
+			"max", max)
+	}
+	if max := s.maxRate(); p.Rate > max {
+		return serrors.New("rate exceeds server limit", "rate", p.Rate, "max", max)
+	}
+	return nil
+}
+
+func (s *Server) maxRate() int64 {
+	if s.MaxRate == 0 {
+		return DefaultMaxRate
+	}
+	return s.MaxRate
+}
+
+func (s *Server) maxDuration() time.Duration {
+	if s.MaxDuration == 0 || s.MaxDuration > MaxDuration {
+		return MaxDuration
+	}
+	return s.MaxDuration
+}
+
+func (s *Server) maxSessions() int {
+	if s.MaxSessions == 0 {
+		return DefaultMaxSessions
+	}
+	return s.MaxSessions
+}
+
+// cookie computes the cookie of a test request from the remote address.
+func cookie(secret []byte, id uint64, remote net.Addr) []byte {
+	mac := hmac.New(sha256.New, secret)
+	var raw [8]byte
+	binary.BigEndian.PutUint64(raw[:], id)
+	mac.Write(raw[:])
+	mac.Write([]byte(remote.String()))
+	return mac.Sum(nil)[:cookieLen]
+}
+
+func maxDuration(req control) time.Duration {
+	if req.ClientToServer.Duration > req.ServerToClient.Duration {
+		return req.ClientToServer.Duration
+	}
+	return req.ServerToClient.Duration
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"crypto/x509"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/scrypto/cms/protocol"
+	"github.com/scionproto/scion/go/lib/scrypto/cppki"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/pkg/grpc"
+	cppb "github.com/scionproto/scion/go/pkg/proto/control_plane"
+)
+
+// Requester requests certificate chain renewals from a CA using gRPC.
+type Requester struct {
+	// Dialer dials a new gRPC connection.
+	Dialer grpc.Dialer
+}
+
+// RequestChain sends the renewal request to the control service of the CA and
+// returns the renewed certificate chain. Only CMS responses are supported.
+//
+// The signature on the response is not verified. The caller is expected to
+// verify the returned chain against the TRC.
+func (r Requester) RequestChain(ctx context.Context, ca addr.IA,
+	req *cppb.ChainRenewalRequest) ([]*x509.Certificate, error) {
+
+	conn, err := r.Dialer.Dial(ctx, &snet.SVCAddr{IA: ca, SVC: addr.SvcCS})
+	if err != nil {
+		return nil, serrors.WrapStr("dialing", err, "isd_as", ca)
+	}
+	defer conn.Close()
+	client := cppb.NewChainRenewalServiceClient(conn)
+	rep, err := client.ChainRenewal(ctx, req, grpc.RetryProfile...)
+	if err != nil {
+		return nil, serrors.WrapStr("requesting chain renewal", err, "isd_as", ca)
+	}
+	if len(rep.CmsSignedResponse) == 0 {
+		return nil, serrors.New("response does not contain CMS signed chain")
+	}
+	return extractChainFromResponse(rep.CmsSignedResponse)
+}
+
+func extractChainFromResponse(raw []byte) ([]*x509.Certificate, error) {
+	ci, err := protocol.ParseContentInfo(raw)
+	if err != nil {
+		return nil, serrors.WrapStr("parsing ContentInfo", err)
+	}
+	sd, err := ci.SignedDataContent()
+	if err != nil {
+		return nil, serrors.WrapStr("parsing SignedData", err)
+	}
+	content, err := sd.EncapContentInfo.DataEContent()
+	if err != nil {
+		return nil, serrors.WrapStr("reading content", err)
+	}
+	chain, err := x509.ParseCertificates(content)
+	if err != nil {
+		return nil, serrors.WrapStr("parsing certificate chain", err)
+	}
+	if err := cppki.ValidateChain(chain); err != nil {
+		return nil, serrors.WrapStr("validating chain", err)
+	}
+	return chain, nil
+}
@@ -28,6 +28,7 @@ import (
 
 	"google.golang.org/protobuf/proto"
 
+	"github.com/scionproto/scion/go/cs/beaconing"
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/ctrl/seg"
 	"github.com/scionproto/scion/go/lib/pathdb/query"
@@ -46,16 +47,23 @@ type SegmentsStore interface {
 	Get(context.Context, *query.Params) (query.Results, error)
 }
 
+// RegistrationStore provides the state of the segment registrations at the
+// remote ASes.
+type RegistrationStore interface {
+	States(now time.Time) []beaconing.RegistrationState
+}
+
 // Server implements the Control Service API.
 type Server struct {
-	Segments SegmentsStore
-	CA       renewal.ChainBuilder
-	Config   http.HandlerFunc
-	Info     http.HandlerFunc
-	LogLevel http.HandlerFunc
-	Signer   cstrust.RenewingSigner
-	Topology http.HandlerFunc
-	TrustDB  storage.TrustDB
+	Segments      SegmentsStore
+	Registrations RegistrationStore
+	CA            renewal.ChainBuilder
+	Config        http.HandlerFunc
+	Info          http.HandlerFunc
+	LogLevel      http.HandlerFunc
+	Signer        cstrust.RenewingSigner
+	Topology      http.HandlerFunc
+	TrustDB       storage.TrustDB
 }
 
 // GetSegments gets the stored in the PathDB.
@@ -227,6 +235,49 @@ func (s *Server) GetSegmentBlob(w http.ResponseWriter, r *http.Request, segmentI
 	io.Copy(w, &buf)
 }
 
+// GetRegistrations lists the state of the segment registrations per remote AS.
+func (s *Server) GetRegistrations(w http.ResponseWriter, r *http.Request) {
+	rep := []RegistrationState{}
+	if s.Registrations != nil {
+		for _, state := range s.Registrations.States(time.Now()) {
+			segments := make([]RegisteredSegment, 0, len(state.Segments))
+			for _, registered := range state.Segments {
+				segments = append(segments, RegisteredSegment{
+					Id:         SegmentID(fmt.Sprintf("%x", registered.ID)),
+					Type:       registered.Type.String(),
+					Registered: registered.Registered.UTC(),
+					Expiration: registered.Expiry.UTC(),
+				})
+			}
+			rs := RegistrationState{
+				RemoteIsdAs:         IsdAs(state.Remote.String()),
+				StateId:             fmt.Sprintf("%016x", state.StateID),
+				ConsecutiveFailures: state.ConsecutiveFailures,
+				Segments:            segments,
+			}
+			if !state.LastSuccess.IsZero() {
+				rs.LastSuccess = timeRef(state.LastSuccess.UTC())
+			}
+			if !state.LastFailure.IsZero() {
+				rs.LastFailure = timeRef(state.LastFailure.UTC())
+				rs.LastError = api.StringRef(state.LastError)
+			}
+			rep = append(rep, rs)
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(rep); err != nil {
+		Error(w, Problem{
+			Detail: api.StringRef(err.Error()),
+			Status: http.StatusInternalServerError,
+			Title:  "unable to marshal response",
+			Type:   api.StringRef(api.InternalError),
+		})
+		return
+	}
+}
+
 // GetCertificates lists the certificate chains
 func (s *Server) GetCertificates(w http.ResponseWriter,
 	r *http.Request, params GetCertificatesParams) {
@@ -763,3 +814,7 @@ func decodeSegmentIDs(ids SegmentIDs) ([][]byte, error) {
 	}
 	return b, nil
 }
+
+func timeRef(t time.Time) *time.Time {
+	return &t
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/scionproto/scion/go/cs/beaconing"
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/ctrl/seg"
 	"github.com/scionproto/scion/go/lib/ctrl/seg/mock_seg"
@@ -90,6 +91,43 @@ func TestAPI(t *testing.T) {
 			RequestURL:   "/segments",
 			Status:       200,
 		},
+		"registrations": {
+			Handler: func(t *testing.T, ctrl *gomock.Controller) http.Handler {
+				registrations := mock_api.NewMockRegistrationStore(ctrl)
+				s := &Server{
+					Registrations: registrations,
+				}
+				registered := time.Date(2021, 1, 4, 9, 59, 33, 0, time.UTC)
+				registrations.EXPECT().States(gomock.Any()).Return(
+					[]beaconing.RegistrationState{
+						{
+							Remote:      xtest.MustParseIA("1-ff00:0:110"),
+							StateID:     0x4a3f2b1c0d9e8f7a,
+							LastSuccess: registered,
+							Segments: []beaconing.RegisteredSegment{
+								{
+									ID:         xtest.MustParseHexString(id1),
+									Type:       seg.TypeDown,
+									Registered: registered,
+									Expiry:     registered.Add(6 * time.Hour),
+								},
+							},
+						},
+						{
+							Remote:              xtest.MustParseIA("1-ff00:0:120"),
+							LastFailure:         registered,
+							LastError:           "connection refused",
+							ConsecutiveFailures: 3,
+							Segments:            []beaconing.RegisteredSegment{},
+						},
+					},
+				)
+				return Handler(s)
+			},
+			ResponseFile: "testdata/registrations.json",
+			RequestURL:   "/registrations",
+			Status:       200,
+		},
 		"segments error": {
 			Handler: func(t *testing.T, ctrl *gomock.Controller) http.Handler {
 				seg := mock_api.NewMockSegmentsStore(ctrl)
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/scionproto/scion/go/pkg/cs/api (interfaces: SegmentsStore)
+// Source: github.com/scionproto/scion/go/pkg/cs/api (interfaces: RegistrationStore,SegmentsStore)
 
 // Package mock_api is a generated GoMock package.
 package mock_api
@@ -7,10 +7,49 @@ package mock_api
 import (
 	context "context"
 	gomock "github.com/golang/mock/gomock"
+	beaconing "github.com/scionproto/scion/go/cs/beaconing"
 	query "github.com/scionproto/scion/go/lib/pathdb/query"
 	reflect "reflect"
+	time "time"
 )
 
+// MockRegistrationStore is a mock of RegistrationStore interface
+type MockRegistrationStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRegistrationStoreMockRecorder
+}
+
+// MockRegistrationStoreMockRecorder is the mock recorder for MockRegistrationStore
+type MockRegistrationStoreMockRecorder struct {
+	mock *MockRegistrationStore
+}
+
+// NewMockRegistrationStore creates a new mock instance
+func NewMockRegistrationStore(ctrl *gomock.Controller) *MockRegistrationStore {
+	mock := &MockRegistrationStore{ctrl: ctrl}
+	mock.recorder = &MockRegistrationStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRegistrationStore) EXPECT() *MockRegistrationStoreMockRecorder {
+	return m.recorder
+}
+
+// States mocks base method
+func (m *MockRegistrationStore) States(arg0 time.Time) []beaconing.RegistrationState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "States", arg0)
+	ret0, _ := ret[0].([]beaconing.RegistrationState)
+	return ret0
+}
+
+// States indicates an expected call of States
+func (mr *MockRegistrationStoreMockRecorder) States(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "States", reflect.TypeOf((*MockRegistrationStore)(nil).States), arg0)
+}
+
 // MockSegmentsStore is a mock of SegmentsStore interface
 type MockSegmentsStore struct {
 	ctrl     *gomock.Controller
@@ -37,6 +37,9 @@ type ServerInterface interface {
 	// Set logging level
 	// (PUT /log/level)
 	SetLogLevel(w http.ResponseWriter, r *http.Request)
+	// List the segment registration state
+	// (GET /registrations)
+	GetRegistrations(w http.ResponseWriter, r *http.Request)
 	// List the SCION path segments
 	// (GET /segments)
 	GetSegments(w http.ResponseWriter, r *http.Request, params GetSegmentsParams)
@@ -254,6 +257,21 @@ func (siw *ServerInterfaceWrapper) SetLogLevel(w http.ResponseWriter, r *http.Re
 	handler(w, r.WithContext(ctx))
 }
 
+// GetRegistrations operation middleware
+func (siw *ServerInterfaceWrapper) GetRegistrations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRegistrations(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
 // GetSegments operation middleware
 func (siw *ServerInterfaceWrapper) GetSegments(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -584,6 +602,9 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Put(options.BaseURL+"/log/level", wrapper.SetLogLevel)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/registrations", wrapper.GetRegistrations)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/segments", wrapper.GetSegments)
 	})
@@ -18,63 +18,69 @@ import (
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xbeXPbtrb/KmfY/vE61eYlTa3/FNlJNS+Lx1L7ZlrnaSDySEQCAiwAOtb11Xe/A4Ck",
-	"uEmi7CRN77TTyZgkcHDwOwvOAj14vohiwZFr5Q0fPIkqFlyhfXhBghv8M0GlzZMvuEZu/yRxzKhPNBW8",
-	"/0EJbt4pP8SImL++l7j0ht53/S3pvvuq+lNNeEBkcCWlkN5ms+l4ASpf0tgQ84ZmTZDpouZrOtHQHY/M",
-	"v7EUMUpNHYs+Sj2/I4wGVK8PLf5bNm7T8WLBqH9wxrUbZfhIFh/Q1wf3lw7bzph/xPWcBi0n/i+uJ5d2",
-	"4wYEKjHwhn/ki9eI5vvoVJB43/H0OkZv6ImcobGBbWnkhnUgA6o05auEqhCDOSeRHZPSUFpSvjI0qArm",
-	"Thr7NjNRwUhVMSBsJcxEvCdRzAzZq/HldOR16qs8BbqOd7w6VOBuwCLfeYF8w/ZqrBs5UG13W4Afijrf",
-	"JKmQUF6XEVUqQXloW0Uxt1fc0qyd6pdysGNXvmG71d5eSGpYqW3woKztbCfmdmhUVbH1+CdrkTXPGnQF",
-	"wgUULR7gPwrLyWXZqpbk2RkZnBOv4y2FjIj2hl6I993UvPaJbhIgN68snzWr/EXEDSLjGuWS+Fhi4vw0",
-	"n28GrFAe7TyqaGbmt12wgN810SEoXEXINYQibgLL0S1BddJdLgeD4WB4cjIwvpRojdKcQv9/exv82P2f",
-	"P0h3OehevH846Zxvhj88nG7Kr374txn3fQHTyfSyO5oeAPK1WL3GO2R1NFn2unwovharFeUrcJ87HvIk",
-	"so4KF8nKYrI0vgftofq+U9hh+qXCQgVbR7bpyLjOT8nKsWvUZc7oEjWNyqL3np+Gg2igDq5aodG4vBQL",
-	"hlHDaYWa0AagRhAmEeEgkQRkwRDwPmaE20gFVIy+0XfQAnRIFQjfT6RE7iOIJegQIXYLgg6JBqogRBYv",
-	"E2ZmMGENpTiK8ABW9A6BBHfUEOEQik9mcCyFjxj04P8k1Ro5UA5XfMWoCu2snL+lkIB8RTmiVB1IVEIY",
-	"WwMXGlRCNQZ2BBccNPohpz5hoDT5iKFgAUplqZnRhj1G/4VBzyvKfyw4R99uXwsIiCYLohAM4gGIRDep",
-	"J+VKE+4sugrvrzcTkLhEh5qDKdN1ZcHJUd6Jbgewt+rBYg0kCIxaE1hK4mw3JyZBSFDJohsb07YSK4hn",
-	"HWMP3pA1LBAShUFFQFII7RalKp9EueNPJNJH8EWAZaj66cC+n2PWtRb1nRYfkXeNKXWN4LoWva5DL3ex",
-	"iaTdHJnGoEYTnag6qLMQ4ZfZ7BrcAMsZrJCjJEb+i7VlW0i6ohwUyjuUVin2q3Bpb88GZ+bJZ4mid/iG",
-	"3NPIOBAtE+x4Ufb402DQ8SLK3dOJear78NTR1TVDhUIapY0iItc1e7IC+6uNYYrS2umvnNwRysyaTYJy",
-	"L8wOlyRhRrZkIRI9XDDCP3qdNjaRcPpngmxdNY4iHiC4GeC00iZX97qA2x0NMIDR9aQH7+JYpEpetDDn",
-	"1SiHm5fj7vOfB887QK3X4kh1iBIk+iKKkAdu7sLEnRmjFnCDVywo1+Yzcb6zm4sjEH5ijNKtw4WEFRML",
-	"KxK3v1QNK2JuZ1RHmE41GnV2lKli07kxdZFA/dzA+5hK4iT3sGUgIBqtVTepQyhiF+lojA4GLyZGylXI",
-	"I1ISmz22SGIcyy60ZUTpeRIbtoL2jJr3SpMobjulKWDdEukU0arwlKJSiMCm48m7txAX47ADwWu64x2p",
-	"APJgfmSyeSzIyFc6bAi27PvMEtPNlLT6pMkxKk2knj8pxLUJQ5FMpwhDznEtb3g09rXUYXH+LDg/Dw6m",
-	"Dun8A3Fuvkp7+ylJKKJ84iad7Fu6EGrmBjelK+6Sw7JaETX3y2WQI1Lpsvcoa41bELZDgEbOay/WaXZl",
-	"vO3sZgxZAlj2lKeD09Pu4KQ7OJ8NLobPLoZnZ73BYPB7URb7zV/6LYols5uxA9cO5/OVJD7OY5RUBA2x",
-	"yc3YxVdEgZaJ0i60osocO3YquKkduztjMIxoVNpu1CecC33LsyCtRKR3W9DMhRAMCa+ZRMkDVWSX77h5",
-	"L8UaheBaCgYmFUBQTlImnDOw7rKQUqGy7p6y12W87GiIUCmyOuxw83ytvvq2WFEtAT05hd6zniuhlVzC",
-	"zxfw4gLOL2B8Cqcvzf8XY7i8hMElnI7g2XMYXcDlFfx8ZT89g5dnMLiAkwFcnhQ1V8XEx6BbdiZVDZ7d",
-	"jBssNtGhkNREIXc4J2lpupUzyU+GqnfwhfxcpErybyqYtrfIz1NxKpQnt9vsNMFYZr5gL8Z2Dxwgs5vx",
-	"o2t46YbrzNcOtnaMOJUtc2Gy3DlPooU7BPYf3VQFLapXCiUlrInoWX143fS8TompKr0K/E0Ha2HTIhZM",
-	"rKymmBzagEPYdQEBl9rVJv5WULEyYFzoOVnqys6efioZugtcCok1widPIFzBt7BKp7CVAqjZztPzqo7q",
-	"ZpPW0eq57fUkz3RcqJUdKGlC6dWPmizVHF1PjE2iVI7WoDfonRhcRIycxNQbeme9Qe/UVR9DK4q+b9to",
-	"K7T+3wjJnlKTwBt6r1CPidcp9+lOB4PP1qAbj5q6ctPE91GpZcLgXcaP2cW5W7mJYM5hv9BGtB09VyLw",
-	"ht5kewSDTbEtxOORCYw0WSkjWRsweO/NxH7h9FcFhCrhO1WOjiuMaLYG4hunVy+vK5esE4nwkYtPPE2w",
-	"b7mfSlE5KfZgFiJIVAnTJqgxmfSSMo3ShXeu6NuDl4k0eXckJHZuueBoB8dEKSAQE6mpnzAi05TbZP40",
-	"QiAaPoXUDx3TWx5vecqk4c96dRNAUR4nugcjSEOmjJ+8YqAFSNSJ5EAYu+VFzDogcUVkwFCpNL+hMjUI",
-	"83xHaBqZ2tisrnlF/I3CShKhNgH48I8Hjxr0/0xQmqPHNRC3Vft2ypfHLc3ULAhzokv02nmLZoKEsRKt",
-	"WjT6/omW1iq2KHTEagFG3RStfouGZpEqGOQOBtNizI/HuYSsCt/AzIQ7xZSN9r01xTqvhy28/2CHdmmw",
-	"2Wnsr3DHAtZhE1ud55C2yQ5r9Q6lNp55qzQZV17xCHLnbUsXm/Uwn6xeB1dpklmt7ffN6c1OqR6nNf0F",
-	"E4tHqA5yXwQuXb2+egOLtUYFhtbjlOqF4eKbVqz7boxRd0lZJUbrmv9eXL2avIXx1c1s8nIyHs2u7Ntb",
-	"PpoWFanX691y++Xq7WXD6L2kxqNjSHktVNqK6++j147dHcot+JKu9gaEbsRBkWu81/2YpVdLaqdeflh+",
-	"pejvWlKuXVtk9u7Na3AbTdLqmdHGYhzoiygyWarFJIvTdyEycV3vvxceL4iifrEsBTFZYSEwrkSlrsms",
-	"1E6UmFj18wsFu6DK7yJ8waMoX+OrYWksjVUuTdQw6nhx0gDKtAKKpf9CBOuvgkd21aO4/vYk2PxXSWna",
-	"RkpGk9OuRoukr94K2ZHkwZ4cTzUlebYZpInUttKNPIDRtNokgglXMfqOFcoDekeDhLAtKy6AMAkiuCss",
-	"GMAdxU+9phhimu26FjxUhGO5Sm8eiWVjy6p61akpG6q0no5O2SqtA5QR5YTBHqZOM6ZOdzJVaoAdx9JX",
-	"Sd5KXcwj0reIaD80it+gsb1vN5Nr4LZgtOmritX2H9K/WqVyhbcGJ7K9UFRfe5/Z1K0G72MmAvSGS8IU",
-	"dppC8C2jjw7CC01P2xVeW01X1Kr8F834so03+e96h7gQaPS+2Uj5cGe7vea1SwcbVtyZD+5Tv+as7++n",
-	"gi1yw+vR7BeYXr16c/V2luZoFsVrosOsW19O6hpmeK2U9ptO63bxu1NL8zsKu4Lz9BbDl/QZboWvnfTR",
-	"xsr/aArFTD672GlwKoZsXdfHT7vsu5oFDt3H1oBsuads+DsqPQ7BcVqe+qfq8nmSt6PLJLrQEt1lTnnb",
-	"9AsaVL7GX1FHSXeQNXeMQWW47C+oaOm3SK7Sqz3Oz83sTZ4bITSMi5Ubl+Qg8UOTkhyffO1osPXgXeza",
-	"3GzdAcIYzG7GeaKWOmYMgHKlkdh2lr1fW+BbcFSNR/bM7L7dWV3vb5XSkjxlaLg4Xbk4kh3LxhF633aD",
-	"Kr/scUR+ky67YGgF1Xu6nudqaOjt8gLSV32qggeqgk138bAgCjdd9eDuWmxaRn+7VHvHCTCTfqv6vlOW",
-	"3SHd3vsnuYaUaZoNtiN60pqmA6sd1aarL18yx5ndjJu0bnYz7n2+g8cs8ij9OibF2KVkWZqRBR8m3XDZ",
-	"xk7ta91h+kcDHxmIzW7GaRz0+4fRp3cfRj+9mV19mlSipu0or1FFP3N8lFNs0FV3X+0u04VEMm/ohVrH",
-	"w37/IRRKb4YPsZB6Y28MSmoctYXKfCv/+MX+mMa+tj+bl5XPZ4PzZ6fGJt/nbNRuxd6hXGtb7ZLI7K+a",
-	"tGgufFXTYK9eWNxHzV0vjoi2ClQg54CpExvbKAhG1xPA+/y+tiOWBidFrtKgafN+858AAAD//1ZGDMQ5",
-	"QQAA",
+	"H4sIAAAAAAAC/+xce3PbNpD/Khi2f1ynlEQ/0sT6T5GdVHN5eCy1N9M6p4HIlYSEBFgAtKPz6bvfACAp",
+	"kAQlyk7S9KadTscigcXit7vAvtgHL2RJyihQKbzhg8dBpIwK0D9e4ugG/spASPUrZFQC1X/iNI1JiCVh",
+	"dPBRMKqeiXANCVZ//chh6Q29HwY70gPzVgymEtMI8+iKc8a97XbrexGIkJNUEfOGak3E80XV23yiojse",
+	"qf+mnKXAJTEshsDl/A7HJCJyc2jx34txW99LWUzCgzOuzSjFR7b4CKE8uL982G7G/BNs5iTqOPE/YTO5",
+	"1BtXIBAOkTf8s1y8QbTch19D4oPvyU0K3tBjJUNjBdtSyQ2aQEZESEJXGRFriOYUJ3pMTkNITuhK0SAi",
+	"mmNxaDMTEY1EHQMcr5iaCJ9xksaK7NX4cjry/OYqT4HO945XhxrcDizKnVvkHdtrsK7kQKTerQU/snXe",
+	"Jak1JrQpIyJEBvzQtmwxd1fcyqxW9cs5aNlVqNjutLeXnMDSscGDstazjZi7oVFXxc7jn6xF2jwb0FmE",
+	"LRQ1Hih8FJaTy6pVLfGzMxycY8/3lownWHpDbw2fe7l57RPdJAKqHgHfrbazyl9Z6hAZlcCXOIQKE+en",
+	"5Xw1YAX86MOjjmZhfrsFLfyusVwjAasEqERrlrrAMnQrUJ30lssgGAbDk5PA870USwmcekPvv29vo597",
+	"//En7i2D3sWHhxP/fDv86eF0W3300/+qcT9amE6ml73R9ACQb9jqDdxB3EQzLh5XL8U3bLUidIXMa98D",
+	"miX6oIJFttKYLJl6rC/VD761w/xNjYUatoas68q4Lm/J2rWr1GUekyVIklRF7z0/XQdJIA6uWqPhXJ6z",
+	"RQyJ47YCiYkDqBFaZwmmiAOO8CIGBJ/TGFPtqSCRQqj0HUmG5JoIxMIw4xxoCIgtkVwDSs2CSK6xRESg",
+	"NcTpMovVjJhpQ7FHYRqhFbkDhKM7oohQtGb3anDKWQgQ9dF/cSIlUEQouqKrmIi1nlXyt2QcAV0RCsCF",
+	"jzKR4TjeIMokEhmREOkRlFEkIVxTEuIYCYk/wZrFEXChqanRir2Y/A9Efc+W/5hRCqHevmQowhIvsACk",
+	"EI8Qy6RLPQkVEtMQXPD+djNBHJZgUDMwFbouNDglyq3o+gj6qz5abBCOIqXWGC05NrZbEuOIcSSyRS9V",
+	"pi2ZTQAplvvoLd6gBaBMQFQTEGdMmkWJKCcRavhjGQ8BhSyCKlSDfOAgLDHraYv6QbJPQHvKlHpKcD2N",
+	"Xs+gVx6xGSe9EhmnUyOxzEQT1Nka0K+z2TUyAzRnaAUUOFbyX2w024yTFaFIAL8DrpVivwpX9vYsOFO/",
+	"wjgT5A7e4s8kUQeI5Bn4XlL8/CUIfC8h1Pw6CYJyE9YZnh90Tc0Qa8aV0iYJ5puGPWmB/d3GMAWu7fQ3",
+	"iu8widWaLkGZB2qHS5zFSrZ4wTI5XMSYfvL8LjaRUfJXBvGmbhw2HojReFNopQ6uPksLtzsSQYRG15M+",
+	"ep+mLFdy28LMqUYounk17j1/ETz3EdGnFgUi18ARh5AlCdDIzF0AiqBgVAOu8EoZoVK9xubs7JXiiFiY",
+	"KaM061DG0SpmCy0Ss79cDWti7mZUR5hO3Rs1dlSoouveuIEVERI4RFPjEzRvEPicEo6NDB92rERYgrZv",
+	"58F4OBQxyxkHlZdcOIyeJKXFxlgoBQ9DEEJdNmaeYa7v+R2Z22ltZZ1NWq6T+0dVAUXsnh6EnETFkMqu",
+	"fBtFyxvLUUC7oQhLhBGHhElAo6nXKjNDbCqdMWrIqIAwk+QO5ktM4oyD4zx9lyULdX0skRoDUQVPgQQx",
+	"VtrEndGq8jrPPzVpbjS4sbJOalSk6uCg7xKepppvqYOytJDtpih6rXzb30IxjdTnx2YOjA7pCURCcnBm",
+	"0+ZLo/Aw53hT3MGQpxSq+7bVT9/EYPsiGgkOKePWlWw21kcTqcInugKB7tdArXcoZiI/+S1bKLbWR38A",
+	"Z4jQSMdgwlwd1uyIgdD3mVkZESkMa1UTPsdny9PFSRhEF/Bi+XxP4LfXyKtysrDy3YZnyajV9it4Hj4D",
+	"vuhpvWZpd+1Rsa1DX4488bVpZaliK+rOqHouJE7SrlOcp3NJpHIq13jKUbHlNZ68f4dSO34+kHTId9yS",
+	"wgEaHWvqx4IMdCXXjiBZP9932Z24DnQhMZfzJ6UmIq9GxrdhKDlu5HsejX0j5bM4fxadn0cHUz6Fde7P",
+	"T5SrdLefioQSQidm0sm+pYXXNLgpWVHgTbXCYh5W09dHpECrp0dVa8yCaDcEkcR424tNnhVTXvLsZoyK",
+	"xF319D0NTk97wUkvOJ8FF8NnF8Ozs34QBH90d9x42CHJPbsZTy7L4XS+4jiEeQqcMJd7eTM2cTEWSPJM",
+	"SBMSE6HCBT0Vmam+3p257CUIqTcaYkqZvKULcBDp31qauWAsBkwbJlE5gWqyK3fs3oudW2ZUchYjlcIB",
+	"JIykCDWwtllIpcDUPJ72eW4JCIFXhw/cMs/WXH2XZK6n7p+c+tyznil9VI6EFxfo5QU6v0DjU3T6Sv17",
+	"MUaXlyi4RKcj9Ow5Gl2gyyv04kq/eoZenaHgAp0E6PLE1lyR4hCi3j43wlfa5rDYTK4ZJxJrrwEL6H6Y",
+	"lDdD/XQIGf9SpCryf3hMrFda5JepFFhlpd02fReMVeYte1G2e+ACmd2MH117yTfcZL5xsXVjZHLZ5EJl",
+	"J+dUR3AVfT5pqSd0qDoI4ATHLqJnzeFN0/P8ClN1ejX4XRertWmWspittKao3KcCB8fXFgImJdeY+Lul",
+	"YlXAKJNzvJS1nT39VlJ0F7BkHBqET55AuIavtYpvbcUCtdh5fl81Ud1u8/pHMyd5PSkzVMbVKi6UPBHo",
+	"Na+a/I3KuymbBC4MraAf9E8ULiwFilPiDb2zftA/NVWjtRbFINTtDyvQ578Skr6lJpE39F6DHGPPr/ZX",
+	"nAbBF2usGI9c3RTTXfD+vuBH7eI8CNoIlhwOrPaPrS6g6tSuN/QmuysY6dSohng80qkNvBJKstph8D6o",
+	"iQPr9hcWQjX3nQhDxyS0ZbxBOFSHXrMsmkfKmAP6RNk9zROjtzTMpSiMFPtopoNpkcVSOTUqA7oksYnC",
+	"FxtkinV99Crjcg08YRz8W8oo6MEpFgJhlGIuSZjFmOepUkJ1+USFs/drEq4N0zseb2nOpOJPn+rKgSI0",
+	"zWQfjVDuMhX8lJleyRAHmXGKcBzfUhszX0XTmEcxCJHHN4TnBqF+q2S28Uy1b9bUPBt/pbAcJyCBK0E9",
+	"eESh/1cGXF09pvFjV23tpnyl3+KmpkGYY1mh1+20cBPEcVyh1fBGPzzR0jr5FlYnQ8PBaJqi1m/mKPIL",
+	"yyBbGMyT6D8fdyQU1VMHMxNqFJM77Xtnik1eD1v44EEP7ZFo22rsr6FlAX1gY11VpShvbzis1S1KrU7m",
+	"ndIUXHn2FWTu245HbNF78mT1OriKS2aNdo3vTm9apXqc1gwWMVs8QnWAqtKpPm2vr96ixUaCQIrW45Tq",
+	"peLiu1asz70Ukt6SxDUfraf+eXn1evIOja9uZpNXk/FodqWf3tLR1Fakfr9/S/Wbq3eXjtF7SY1Hx5Dy",
+	"Oqi0Ftc/R68Nuy3KzeiSrPY6hGbEQZGrwvAgjfOWwMatV16W38j7u+aESlPUmL1/+waZjWaGvPKvwPYD",
+	"VQVaRakak8JPb0NEOZb/ODxeYkFCOy2FUrwCyzGueaWmOUiIVpRithqUjWBtUJU9ZF/xKirX+GZYKkuL",
+	"a81uDYx8L80coExroGj6L1m0+SZ4FC169vq7m2D7/0pK0y5SUppcqbwfjvxMmTAv4KjGhLIsUiGkIi/A",
+	"4bqoJYaMq4JiH71iHMEd8E3+xrcrQVbQuIsw7Q6FPA/OMhmyXQ2cQ1hnAGEpIUmlifBiRUA3GjVs9Kay",
+	"/W8RkjSbKDpEJntKtinwHEsF8PcbqIjWLViqmQ/KddNuMNivls0yXUsCAu3JPwhXAiJXei619gGN0Gha",
+	"L2CiCRUphIYVQiNyR6IMx8V7kTu3iTIC0xYLEbojcO9UyWmx64ZjW9MJzVXezcyW7t6hWvu0K1KvlUWP",
+	"TifUylrAE0JxjPYwdVowddrKVKU4exxL3ySxUKmwH5FaSLAM1+pQdmjsd2y8Dm4PWu3gIf+rU5rBeqpw",
+	"wrsm5eba+8ymaTWqv5xF4A2XOBbgu8LDHaOPDhCtgryCUG60pguiVf6rZiOKjbuujQZ0thPc/26juMNd",
+	"F901r1uqwrFia65in/q5MxL/PBXskLe4Hs1+RdOr12+v3s3y/IFGUX3oU/gr1YSDY4bXSWm/65RDG7+t",
+	"Wlr2z7QFjnmHzdc8M8wK3zohQZxVqdEU2Vmm4mMRhZPtsvVMj0neAdJWyDLoPjY/qabVDL8lC2kQHOep",
+	"038zgl8msXB0Ck9a5fo2cypL+l/RoMo1/o4cX76DovCoDKrAZX+yT/KwQ3CVt52Zc26mwEc3jEk0trOK",
+	"JsjRMf9kenl88NVS/FVf1pgWjHjjq7KralwpA7X8YIYIESokYF1q1d/sWHwzCsJ5Zc/U7rvd1c3aq+e7",
+	"QgbHx1iN9nZzLauD0Pu+i6dlI9IR8U2+rPo2SQmq/3Q9L9VQ0Ws7BXgoBkRED0RE297iYYEFbHviwfQB",
+	"bTt6f22q3XIDzHjYqfZklKXdpdvbG7X1nTTVBrsRPelM04DVjaqrLetrxjiqfdGhdbObcf/LXTxqkUfp",
+	"1zEhRpuSFWFG4XyocMNEG63a17n6+a8GPtIRm92Mcz/oj4+j+/cfR7+8nV3dT2pe026U51TRL+wflRQd",
+	"urrVvY93hS5kPPaG3lrKdDgYPKyZkNvhQ8q43OpuVk7UQa2hUu+qH9TqD3T1Y/2/4uG112fB+bNTZZMf",
+	"SjYaHdsqzy91totDrL+Ulsyd+KqHwd7WP4aa3r/KrWkFssjpFw5iY+0FqRZC9VlB8S2BIZY7JzZXudO0",
+	"/bD9vwEAq6lPW41JAAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
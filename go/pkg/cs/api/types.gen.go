@@ -97,6 +97,39 @@ type Problem struct {
 	Type *string `json:"type,omitempty"`
 }
 
+// RegisteredSegment defines model for RegisteredSegment.
+type RegisteredSegment struct {
+	Expiration time.Time `json:"expiration"`
+	Id         SegmentID `json:"id"`
+
+	// Time of the last successful registration.
+	Registered time.Time `json:"registered"`
+
+	// Type of the segment.
+	Type string `json:"type"`
+}
+
+// RegistrationState defines model for RegistrationState.
+type RegistrationState struct {
+
+	// Number of failed registrations since the last successful one.
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// Error of the last failed registration.
+	LastError *string `json:"last_error,omitempty"`
+
+	// Time of the last failed registration.
+	LastFailure *time.Time `json:"last_failure,omitempty"`
+
+	// Time of the last successful registration.
+	LastSuccess *time.Time          `json:"last_success,omitempty"`
+	RemoteIsdAs IsdAs               `json:"remote_isd_as"`
+	Segments    []RegisteredSegment `json:"segments"`
+
+	// Registration state identifier last reported by the remote. It changes when the remote loses the registered segments. Zero indicates that the remote does not report its state.
+	StateId string `json:"state_id"`
+}
+
 // Segment defines model for Segment.
 type Segment struct {
 	Expiration  time.Time `json:"expiration"`
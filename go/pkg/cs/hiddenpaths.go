@@ -23,6 +23,7 @@ import (
 	"github.com/scionproto/scion/go/lib/infra"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/pathdb"
+	"github.com/scionproto/scion/go/lib/periodic"
 	libgrpc "github.com/scionproto/scion/go/pkg/grpc"
 	"github.com/scionproto/scion/go/pkg/hiddenpath"
 	hpgrpc "github.com/scionproto/scion/go/pkg/hiddenpath/grpc"
@@ -44,35 +45,65 @@ type HiddenPathConfigurator struct {
 // Setup sets up the hidden paths servers using the configuration at the given
 // location. An empty location will not enable any hidden path behavior. It
 // returns the configuration for the hidden segment writer. The return value can
-// be nil if this AS isn't a writer.
-func (c HiddenPathConfigurator) Setup(location string) (*HiddenPathRegistrationCfg, error) {
+// be nil if this AS isn't a writer. Furthermore, it starts a periodic task that
+// keeps the hidden path groups up to date. The returned runner is nil if no
+// hidden path behavior is enabled, otherwise the caller must stop it.
+func (c HiddenPathConfigurator) Setup(
+	location string,
+) (*HiddenPathRegistrationCfg, *periodic.Runner, error) {
+
 	if location == "" {
-		return nil, nil
+		return nil, nil, nil
 	}
 	groups, regPolicy, err := hiddenpath.LoadConfiguration(location)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	roles := groups.Roles(c.LocalIA)
 	if roles.None() {
-		return nil, nil
+		return nil, nil, nil
+	}
+	store := hiddenpath.NewGroupStore(groups, regPolicy)
+	groupServer := &hpgrpc.GroupServer{
+		Groups:  store,
+		LocalIA: c.LocalIA,
+		Signer:  c.Signer,
+	}
+	hspb.RegisterHiddenSegmentGroupServiceServer(c.IntraASTCPServer, groupServer)
+	hspb.RegisterHiddenSegmentGroupServiceServer(c.InterASQUICServer, groupServer)
+	resolver := hiddenpath.LookupResolver{
+		Router: segreq.NewRouter(c.FetcherConfig),
+		Discoverer: &hpgrpc.Discoverer{
+			Dialer: c.Dialer,
+		},
 	}
+	log.Info("Starting hidden path group updater")
+	updater := periodic.Start(
+		&hiddenpath.GroupUpdater{
+			Store: store,
+			RPC: hpgrpc.GroupRequester{
+				Dialer:   c.Dialer,
+				Verifier: c.Verifier,
+			},
+			LocalIA:  c.LocalIA,
+			Resolver: resolver,
+			Location: location,
+		},
+		hiddenpath.DefaultGroupUpdateInterval,
+		hiddenpath.DefaultGroupUpdateInterval,
+	)
+
 	log.Info("Starting hidden path forward server")
 	hspb.RegisterHiddenSegmentLookupServiceServer(c.IntraASTCPServer, &hpgrpc.SegmentServer{
 		Lookup: hiddenpath.ForwardServer{
-			Groups:    groups,
-			LocalAuth: c.localAuthServer(groups),
+			Groups:    store,
+			LocalAuth: c.localAuthServer(roles, store),
 			LocalIA:   c.LocalIA,
 			RPC: &hpgrpc.AuthoritativeRequester{
 				Dialer: c.Dialer,
 				Signer: c.Signer,
 			},
-			Resolver: hiddenpath.LookupResolver{
-				Router: segreq.NewRouter(c.FetcherConfig),
-				Discoverer: &hpgrpc.Discoverer{
-					Dialer: c.Dialer,
-				},
-			},
+			Resolver: resolver,
 			Verifier: hiddenpath.VerifierAdapter{
 				Verifier: c.Verifier,
 			},
@@ -82,13 +113,13 @@ func (c HiddenPathConfigurator) Setup(location string) (*HiddenPathRegistrationC
 		log.Info("Starting hidden path authoritative and registration server")
 		hspb.RegisterAuthoritativeHiddenSegmentLookupServiceServer(c.InterASQUICServer,
 			&hpgrpc.AuthoritativeSegmentServer{
-				Lookup:   c.localAuthServer(groups),
+				Lookup:   c.localAuthServer(roles, store),
 				Verifier: c.Verifier,
 			})
 		hspb.RegisterHiddenSegmentRegistrationServiceServer(c.InterASQUICServer,
 			&hpgrpc.RegistrationServer{
 				Registry: hiddenpath.RegistryServer{
-					Groups: groups,
+					Groups: store,
 					DB: &hiddenpath.Storer{
 						DB: c.PathDB,
 					},
@@ -102,11 +133,11 @@ func (c HiddenPathConfigurator) Setup(location string) (*HiddenPathRegistrationC
 		)
 	}
 	if !roles.Writer {
-		return nil, nil
+		return nil, updater, nil
 	}
 	log.Info("Using hidden path beacon writer")
 	return &HiddenPathRegistrationCfg{
-		Policy: regPolicy,
+		Policy: store,
 		Router: segreq.NewRouter(c.FetcherConfig),
 		Discoverer: &hpgrpc.Discoverer{
 			Dialer: c.Dialer,
@@ -116,11 +147,12 @@ func (c HiddenPathConfigurator) Setup(location string) (*HiddenPathRegistrationC
 			RegularRegistration: beaconinggrpc.Registrar{Dialer: c.Dialer},
 			Signer:              c.Signer,
 		},
-	}, nil
+	}, updater, nil
 }
 
-func (c HiddenPathConfigurator) localAuthServer(groups hiddenpath.Groups) hiddenpath.Lookuper {
-	roles := groups.Roles(c.LocalIA)
+func (c HiddenPathConfigurator) localAuthServer(roles hiddenpath.Roles,
+	groups hiddenpath.GroupProvider) hiddenpath.Lookuper {
+
 	if !roles.Registry {
 		return nil
 	}
@@ -60,6 +60,7 @@ type Metrics struct {
 	BeaconingReceivedTotal                 *prometheus.CounterVec
 	BeaconingRegisteredTotal               *prometheus.CounterVec
 	BeaconingRegistrarInternalErrorsTotal  *prometheus.CounterVec
+	BeaconingRegistrationFailures          *prometheus.GaugeVec
 	DiscoveryRequestsTotal                 *prometheus.CounterVec
 	RenewalServerRequestsTotal             *prometheus.CounterVec
 	RenewalHandledRequestsTotal            *prometheus.CounterVec
@@ -68,6 +69,8 @@ type Metrics struct {
 	SegmentLookupSegmentsSentTotal         *prometheus.CounterVec
 	SegmentRegistrationsTotal              *prometheus.CounterVec
 	TrustDBQueriesTotal                    *prometheus.CounterVec
+	UpstreamRequestsTotal                  *prometheus.CounterVec
+	UpstreamHedgedRequestsTotal            *prometheus.CounterVec
 	TrustLatestTRCNotBefore                prometheus.Gauge
 	TrustLatestTRCNotAfter                 prometheus.Gauge
 	TrustLatestTRCSerial                   prometheus.Gauge
@@ -117,6 +120,13 @@ func NewMetrics() *Metrics {
 			},
 			[]string{"seg_type"},
 		),
+		BeaconingRegistrationFailures: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "control_beaconing_registration_consecutive_failures",
+				Help: "Number of consecutive failed segment registrations per remote AS.",
+			},
+			[]string{"remote_isd_as"},
+		),
 		DiscoveryRequestsTotal: promauto.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "discovery_requests_total",
@@ -174,6 +184,21 @@ func NewMetrics() *Metrics {
 			},
 			[]string{"driver", "operation", prom.LabelResult},
 		),
+		UpstreamRequestsTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "control_upstream_requests_total",
+				Help: "Total number of requests sent to remote control service instances.",
+			},
+			[]string{"upstream", prom.LabelResult},
+		),
+		UpstreamHedgedRequestsTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "control_upstream_hedged_requests_total",
+				Help: "Total number of hedged requests sent to remote control service " +
+					"instances, because an earlier instance did not reply in time.",
+			},
+			[]string{"upstream"},
+		),
 		TrustLatestTRCNotBefore: promauto.NewGauge(
 			prometheus.GaugeOpts{
 				Name: "trustengine_latest_trc_not_before_time_seconds",
@@ -0,0 +1,51 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cs
+
+import (
+	"context"
+	"sync"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+)
+
+// PathQuerier queries the paths with the router of the control service. It is
+// used by the multipath QUIC sockets. The sockets are created before the
+// router, because the router fetches segments over these sockets. Thus, the
+// router is set once it is initialized.
+type PathQuerier struct {
+	mtx    sync.RWMutex
+	router snet.Router
+}
+
+// SetRouter sets the router that is used to query paths.
+func (q *PathQuerier) SetRouter(router snet.Router) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	q.router = router
+}
+
+// Query returns the paths to dst.
+func (q *PathQuerier) Query(ctx context.Context, dst addr.IA) ([]snet.Path, error) {
+	q.mtx.RLock()
+	router := q.router
+	q.mtx.RUnlock()
+	if router == nil {
+		return nil, serrors.New("router not initialized")
+	}
+	return router.AllRoutes(ctx, dst)
+}
@@ -23,9 +23,13 @@ import (
 )
 
 // RevocationHandler handles raw revocations from the snet stack and inserts
-// them into the
+// them into the revocation cache.
 type RevocationHandler struct {
 	RevCache revcache.RevCache
+	// Revocations, if set, is notified about every revocation that is newly
+	// inserted into the cache. The notification is dropped if the channel is
+	// full.
+	Revocations chan<- *path_mgmt.SignedRevInfo
 }
 
 func (h RevocationHandler) RevokeRaw(ctx context.Context, rawSRevInfo []byte) {
@@ -33,9 +37,23 @@ func (h RevocationHandler) RevokeRaw(ctx context.Context, rawSRevInfo []byte) {
 	sRev, err := path_mgmt.NewSignedRevInfoFromRaw(rawSRevInfo)
 	if err != nil {
 		logger.Debug("Unparsable revocation received", "err", err)
+		return
 	}
-	_, err = h.RevCache.Insert(ctx, sRev)
+	if _, err := sRev.RevInfo(); err != nil {
+		logger.Debug("Unparsable revocation info received", "err", err)
+		return
+	}
+	inserted, err := h.RevCache.Insert(ctx, sRev)
 	if err != nil {
 		logger.Debug("Failed to insert revocation from snet", "err", err)
+		return
+	}
+	if !inserted || h.Revocations == nil {
+		return
+	}
+	select {
+	case h.Revocations <- sRev:
+	default:
+		logger.Debug("Dropping revocation notification, queue is full")
 	}
 }
@@ -38,7 +38,9 @@ import (
 	"github.com/scionproto/scion/go/lib/snet/addrutil"
 	"github.com/scionproto/scion/go/lib/topology"
 	"github.com/scionproto/scion/go/pkg/cs/drkey"
+	cstrust "github.com/scionproto/scion/go/pkg/cs/trust"
 	"github.com/scionproto/scion/go/pkg/hiddenpath"
+	"github.com/scionproto/scion/go/pkg/linklatency"
 	"github.com/scionproto/scion/go/pkg/trust"
 )
 
@@ -71,8 +73,31 @@ type TasksConfig struct {
 	// hidden paths down segment registration. If it is nil, normal path
 	// registration is used instead.
 	HiddenPathRegistrationCfg *HiddenPathRegistrationCfg
+	// ASRenewalCfg contains the required options to automatically renew the
+	// AS certificate chain. If it is nil, the chain is not renewed.
+	ASRenewalCfg *ASRenewalCfg
+	// LinkLatencyCfg contains the required options to include the link
+	// latencies measured by the border routers in the beacons. If it is nil,
+	// only the static info configuration is used.
+	LinkLatencyCfg *LinkLatencyCfg
+	// IntfConfigs overrides the origination and propagation intervals, the
+	// best set size, and the beacon rate per link type and per interface.
+	IntfConfigs beaconing.IntfConfigs
+	// PropagationTrigger requests triggered propagations. If it is nil,
+	// beacons are only propagated according to the propagation intervals.
+	PropagationTrigger *beaconing.Trigger
+	// Registrations keeps track of the down segments registered at the remote
+	// core ASes. It is used to register the segments again early if a core
+	// lost them or failed. If it is nil, the registrations are not tracked.
+	Registrations *beaconing.RegistrationTracker
 
 	AllowIsdLoop bool
+
+	// linkLatencies holds the link latencies fetched from the border routers.
+	linkLatencies *linklatency.Store
+	// rateLimiter caps the beacons per second on each interface. It is shared
+	// by the originator and the propagator.
+	rateLimiter *beaconing.RateLimiter
 }
 
 // Originator starts a periodic beacon origination task. For non-core ASes, no
@@ -90,6 +115,8 @@ func (t *TasksConfig) Originator() *periodic.Runner {
 		IA:           topo.IA(),
 		Intfs:        t.Intfs,
 		Signer:       t.Signer,
+		IntfConfigs:  t.IntfConfigs,
+		RateLimiter:  t.rateLimiter,
 		Tick:         beaconing.NewTick(t.OriginationInterval),
 	}
 	if t.Metrics != nil {
@@ -112,6 +139,9 @@ func (t *TasksConfig) Propagator() *periodic.Runner {
 		Intfs:        t.Intfs,
 		AllowIsdLoop: t.AllowIsdLoop,
 		Core:         topo.Core(),
+		IntfConfigs:  t.IntfConfigs,
+		RateLimiter:  t.rateLimiter,
+		Trigger:      t.PropagationTrigger,
 		Tick:         beaconing.NewTick(t.PropagationInterval),
 	}
 	if t.Metrics != nil {
@@ -142,6 +172,7 @@ func (t *TasksConfig) segmentWriter(topo topology.Topology, segType seg.Type,
 		registered = metrics.NewPromCounter(t.Metrics.BeaconingRegisteredTotal)
 	}
 	var writer beaconing.Writer
+	var registrations *beaconing.RegistrationTracker
 	switch {
 	case segType != seg.TypeDown:
 		writer = &beaconing.LocalWriter{
@@ -190,14 +221,17 @@ func (t *TasksConfig) segmentWriter(topo topology.Topology, segType seg.Type,
 					return t.TopoProvider.Get().UnderlayNextHop2(common.IFIDType(ifID))
 				},
 			},
+			Registrations: t.Registrations,
 		}
+		registrations = t.Registrations
 	}
 	r := &beaconing.WriteScheduler{
-		Provider: t.BeaconStore,
-		Intfs:    t.Intfs,
-		Type:     segType,
-		Writer:   writer,
-		Tick:     beaconing.NewTick(t.RegistrationInterval),
+		Provider:      t.BeaconStore,
+		Intfs:         t.Intfs,
+		Type:          segType,
+		Writer:        writer,
+		Registrations: registrations,
+		Tick:          beaconing.NewTick(t.RegistrationInterval),
 	}
 	return periodic.Start(r, 500*time.Millisecond, t.RegistrationInterval)
 }
@@ -205,7 +239,7 @@ func (t *TasksConfig) segmentWriter(topo topology.Topology, segType seg.Type,
 func (t *TasksConfig) extender(task string, ia addr.IA, mtu uint16,
 	maxExp func() uint8) beaconing.Extender {
 
-	return &beaconing.DefaultExtender{
+	e := &beaconing.DefaultExtender{
 		IA:         ia,
 		Signer:     t.Signer,
 		MAC:        t.MACGen,
@@ -216,6 +250,10 @@ func (t *TasksConfig) extender(task string, ia addr.IA, mtu uint16,
 		Task:       task,
 		EPIC:       false,
 	}
+	if t.linkLatencies != nil {
+		e.LinkLatencies = t.linkLatencies
+	}
+	return e
 }
 
 func (t *TasksConfig) DRKeyCleaner() *periodic.Runner {
@@ -244,12 +282,51 @@ func (t *TasksConfig) DRKeyPrefetcher() *periodic.Runner {
 		prefetchPeriod, prefetchPeriod)
 }
 
+// ChainRenewer starts a periodic AS certificate chain renewal task. If the
+// renewal is not configured, no periodic runner is started.
+func (t *TasksConfig) ChainRenewer() *periodic.Runner {
+	if t.ASRenewalCfg == nil {
+		return nil
+	}
+	cfg := t.ASRenewalCfg
+	return periodic.Start(
+		&cstrust.ChainRenewer{
+			IA:               t.TopoProvider.Get().IA(),
+			SignerGen:        cfg.SignerGen,
+			DB:               t.TrustDB,
+			Requester:        cfg.Requester,
+			CA:               cfg.CA,
+			Dir:              cfg.Dir,
+			LifetimeFraction: cfg.LifetimeFraction,
+			ReuseKey:         cfg.ReuseKey,
+		},
+		cfg.Interval, cfg.Interval)
+}
+
+// LinkLatencyFetcher starts a periodic task that fetches the link latencies
+// measured by the border routers. If the measured link latencies are not
+// configured, no periodic runner is started.
+func (t *TasksConfig) LinkLatencyFetcher() *periodic.Runner {
+	if t.LinkLatencyCfg == nil || t.linkLatencies == nil {
+		return nil
+	}
+	cfg := t.LinkLatencyCfg
+	return periodic.Start(
+		&linklatency.Fetcher{
+			URLs:  cfg.Routers,
+			Store: t.linkLatencies,
+		},
+		cfg.FetchInterval, cfg.FetchInterval)
+}
+
 // Tasks keeps track of the running tasks.
 type Tasks struct {
-	Originator      *periodic.Runner
-	Propagator      *periodic.Runner
-	Registrars      []*periodic.Runner
-	DRKeyPrefetcher *periodic.Runner
+	Originator         *periodic.Runner
+	Propagator         *periodic.Runner
+	Registrars         []*periodic.Runner
+	DRKeyPrefetcher    *periodic.Runner
+	ChainRenewer       *periodic.Runner
+	LinkLatencyFetcher *periodic.Runner
 
 	BeaconCleaner *periodic.Runner
 	PathCleaner   *periodic.Runner
@@ -257,16 +334,22 @@ type Tasks struct {
 }
 
 func StartTasks(cfg TasksConfig) (*Tasks, error) {
+	if cfg.LinkLatencyCfg != nil {
+		cfg.linkLatencies = &linklatency.Store{MaxAge: cfg.LinkLatencyCfg.MaxAge}
+	}
+	cfg.rateLimiter = &beaconing.RateLimiter{Configs: cfg.IntfConfigs}
 	beaconCleaner := newBeaconCleaner(cfg.BeaconStore)
 	revCleaner := newRevocationCleaner(cfg.BeaconStore)
 
 	segCleaner := pathdb.NewCleaner(cfg.PathDB, "control_pathstorage_segments")
 	segRevCleaner := revcache.NewCleaner(cfg.RevCache, "control_pathstorage_revocation")
 	return &Tasks{
-		Originator:      cfg.Originator(),
-		Propagator:      cfg.Propagator(),
-		Registrars:      cfg.SegmentWriters(),
-		DRKeyPrefetcher: cfg.DRKeyPrefetcher(),
+		Originator:         cfg.Originator(),
+		Propagator:         cfg.Propagator(),
+		Registrars:         cfg.SegmentWriters(),
+		DRKeyPrefetcher:    cfg.DRKeyPrefetcher(),
+		ChainRenewer:       cfg.ChainRenewer(),
+		LinkLatencyFetcher: cfg.LinkLatencyFetcher(),
 		BeaconCleaner: periodic.Start(
 			periodic.Func{
 				Task: func(ctx context.Context) {
@@ -303,6 +386,8 @@ func (t *Tasks) Kill() {
 		t.Originator,
 		t.Propagator,
 		t.DRKeyPrefetcher,
+		t.ChainRenewer,
+		t.LinkLatencyFetcher,
 		t.BeaconCleaner,
 		t.PathCleaner,
 		t.DRKeyCleaner,
@@ -311,6 +396,8 @@ func (t *Tasks) Kill() {
 	t.Originator = nil
 	t.Propagator = nil
 	t.DRKeyPrefetcher = nil
+	t.ChainRenewer = nil
+	t.LinkLatencyFetcher = nil
 	t.BeaconCleaner = nil
 	t.PathCleaner = nil
 	t.DRKeyCleaner = nil
@@ -326,12 +413,45 @@ func killRunners(runners []*periodic.Runner) {
 // HiddenPathRegistrationCfg contains the required options to configure hidden
 // paths down segment registration.
 type HiddenPathRegistrationCfg struct {
-	Policy     hiddenpath.RegistrationPolicy
+	Policy     hiddenpath.RegistrationPolicyProvider
 	Router     snet.Router
 	Discoverer hiddenpath.Discoverer
 	RPC        hiddenpath.Register
 }
 
+// ASRenewalCfg contains the required options to configure the automatic AS
+// certificate chain renewal.
+type ASRenewalCfg struct {
+	// SignerGen generates the active signer.
+	SignerGen cstrust.SignerGen
+	// Requester sends the renewal requests to the CA.
+	Requester cstrust.ChainRequester
+	// CA is the ISD-AS of the CA. If it is zero, the issuer of the active AS
+	// certificate is used.
+	CA addr.IA
+	// Dir is the directory that the renewed chain and key are written to.
+	Dir string
+	// LifetimeFraction is the fraction of the AS certificate validity period
+	// after which the chain is renewed.
+	LifetimeFraction float64
+	// ReuseKey indicates that the private key of the active chain is reused.
+	ReuseKey bool
+	// Interval is the interval between checks whether the renewal is due.
+	Interval time.Duration
+}
+
+// LinkLatencyCfg contains the required options to include the link latencies
+// measured by the border routers in the beacons.
+type LinkLatencyCfg struct {
+	// Routers are the URLs of the link latency status pages of the border
+	// routers.
+	Routers []string
+	// FetchInterval is the interval between fetching the link latencies.
+	FetchInterval time.Duration
+	// MaxAge is the maximum age of a link latency that is still used.
+	MaxAge time.Duration
+}
+
 // Store is the interface to interact with the beacon store.
 type Store interface {
 	// PreFilter indicates whether the beacon will be filtered on insert by
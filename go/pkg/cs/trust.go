@@ -20,11 +20,13 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/scionproto/scion/go/cs/config"
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/pkg/ca/renewal"
 	cstrust "github.com/scionproto/scion/go/pkg/cs/trust"
+	"github.com/scionproto/scion/go/pkg/cs/trust/pkcs11"
 	"github.com/scionproto/scion/go/pkg/trust"
 )
 
@@ -63,8 +65,73 @@ func LoadTrustMaterial(configDir string, db trust.DB, logger log.Logger) error {
 	return nil
 }
 
-// NewSigner creates a renewing signer backed by a certificate chain..
-func NewSigner(ia addr.IA, db trust.DB, cfgDir string) (cstrust.RenewingSigner, error) {
+// KeyRings contains the key rings that provide the private keys of the control
+// service.
+type KeyRings struct {
+	// AS provides the AS signing keys.
+	AS trust.KeyRing
+	// CA provides the CA signing keys.
+	CA trust.KeyRing
+
+	close func() error
+}
+
+// Close releases the resources held by the key rings.
+func (r KeyRings) Close() error {
+	if r.close == nil {
+		return nil
+	}
+	return r.close()
+}
+
+// NewKeyRings creates the key rings for the configured key backend.
+func NewKeyRings(cfg config.Crypto, cfgDir string) (KeyRings, error) {
+	switch cfg.Backend {
+	case config.PKCS11Backend:
+		pin, err := cfg.PKCS11.PIN()
+		if err != nil {
+			return KeyRings{}, err
+		}
+		module, err := pkcs11.OpenModule(cfg.PKCS11.Module)
+		if err != nil {
+			return KeyRings{}, err
+		}
+		as := &pkcs11.Ring{
+			Module:     module,
+			TokenLabel: cfg.PKCS11.TokenLabel,
+			PIN:        pin,
+			KeyIDs:     cfg.PKCS11.ASKeys(),
+		}
+		ca := &pkcs11.Ring{
+			Module:     module,
+			TokenLabel: cfg.PKCS11.TokenLabel,
+			PIN:        pin,
+			KeyIDs:     cfg.PKCS11.CAKeys(),
+		}
+		return KeyRings{
+			AS: as,
+			CA: ca,
+			close: func() error {
+				as.Close()
+				ca.Close()
+				return module.Close()
+			},
+		}, nil
+	case config.FileBackend, "":
+		return KeyRings{
+			AS: cstrust.LoadingRing{Dir: filepath.Join(cfgDir, "crypto/as")},
+			CA: cstrust.LoadingRing{Dir: filepath.Join(cfgDir, "crypto/ca")},
+		}, nil
+	default:
+		return KeyRings{}, serrors.New("unsupported key backend", "backend", cfg.Backend)
+	}
+}
+
+// NewSigner creates a renewing signer backed by a certificate chain. The
+// private keys are provided by the key ring.
+func NewSigner(ia addr.IA, db trust.DB, keyRing trust.KeyRing,
+	cfgDir string) (cstrust.RenewingSigner, error) {
+
 	ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
 	defer cancelF()
 	gen := trust.SignerGen{
@@ -73,9 +140,7 @@ func NewSigner(ia addr.IA, db trust.DB, cfgDir string) (cstrust.RenewingSigner,
 			Dir: filepath.Join(cfgDir, "crypto/as"),
 			DB:  db,
 		},
-		KeyRing: cstrust.LoadingRing{
-			Dir: filepath.Join(cfgDir, "crypto/as"),
-		},
+		KeyRing: keyRing,
 	}
 	cachingGen := &cstrust.CachingSignerGen{
 		SignerGen: gen,
@@ -104,6 +169,8 @@ type ChainBuilderConfig struct {
 	DB          trust.DB
 	MaxValidity time.Duration
 	ConfigDir   string
+	// KeyRing provides the CA private keys.
+	KeyRing trust.KeyRing
 
 	// ForceECDSAWithSHA512 forces the CA policy to use ECDSAWithSHA512 as the
 	// signature algorithm for signing the issued certificate. This field
@@ -126,9 +193,7 @@ func NewChainBuilder(cfg ChainBuilderConfig) renewal.ChainBuilder {
 					DB:  cfg.DB,
 					Dir: filepath.Join(cfg.ConfigDir, "crypto/ca"),
 				},
-				KeyRing: cstrust.LoadingRing{
-					Dir: filepath.Join(cfg.ConfigDir, "crypto/ca"),
-				},
+				KeyRing:              cfg.KeyRing,
 				ForceECDSAWithSHA512: cfg.ForceECDSAWithSHA512,
 			},
 		},
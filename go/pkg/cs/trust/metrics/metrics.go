@@ -42,8 +42,13 @@ const (
 const (
 	Success = prom.Success
 
+	ErrCrypto   = prom.ErrCrypto
+	ErrDB       = prom.ErrDB
 	ErrInternal = prom.ErrInternal
+	ErrNetwork  = prom.ErrNetwork
+	ErrNotFound = prom.ErrNotFound
 	ErrParse    = prom.ErrParse
+	ErrVerify   = prom.ErrVerify
 )
 
 // Triggers
@@ -57,6 +62,8 @@ var (
 	Handler = newHandler()
 	// Signer exposes the signer metrics.
 	Signer = newSigner()
+	// Renewal exposes the AS certificate renewal metrics.
+	Renewal = newRenewal()
 )
 
 // PeerToLabel converts the peer address to a peer metric label.
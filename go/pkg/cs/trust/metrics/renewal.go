@@ -0,0 +1,76 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/scionproto/scion/go/lib/prom"
+)
+
+// RenewalLabels defines the AS certificate renewal labels.
+type RenewalLabels struct {
+	Result string
+}
+
+// Labels returns the list of labels.
+func (l RenewalLabels) Labels() []string {
+	return []string{prom.LabelResult}
+}
+
+// Values returns the label values in the order defined by Labels.
+func (l RenewalLabels) Values() []string {
+	return []string{l.Result}
+}
+
+// WithResult returns the renewal labels with the modified result.
+func (l RenewalLabels) WithResult(result string) RenewalLabels {
+	l.Result = result
+	return l
+}
+
+type renewal struct {
+	renewals    *prometheus.CounterVec
+	lastRenewal prometheus.Gauge
+	nextRenewal prometheus.Gauge
+}
+
+func newRenewal() renewal {
+	return renewal{
+		renewals: prom.NewCounterVecWithLabels(Namespace, "",
+			"as_certificate_renewals_total",
+			"Number of AS certificate renewal attempts", RenewalLabels{}),
+		lastRenewal: prom.NewGauge(Namespace, "",
+			"last_as_certificate_renewal_time_second",
+			"The last time the AS certificate was successfully renewed",
+		),
+		nextRenewal: prom.NewGauge(Namespace, "",
+			"as_certificate_renewal_time_second",
+			"The time at which the renewal of the active AS certificate is due",
+		),
+	}
+}
+
+func (r *renewal) Renewals(l RenewalLabels) prometheus.Counter {
+	return r.renewals.WithLabelValues(l.Values()...)
+}
+
+func (r *renewal) LastRenewal() prometheus.Gauge {
+	return r.lastRenewal
+}
+
+func (r *renewal) NextRenewal() prometheus.Gauge {
+	return r.nextRenewal
+}
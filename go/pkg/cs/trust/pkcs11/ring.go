@@ -0,0 +1,341 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pkcs11 provides a key ring that is backed by a PKCS#11 token, e.g.,
+// a hardware security module.
+//
+// The private keys never leave the token. The key ring only returns
+// crypto.Signer implementations that delegate the signing operation to the
+// token. Keys are selected by the token label and the key ID (CKA_ID). For
+// every selected private key, the token must also hold the corresponding
+// public key object with the same key ID.
+package pkcs11
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+
+	p11 "github.com/miekg/pkcs11"
+
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/pkg/trust"
+)
+
+var _ trust.KeyRing = (*Ring)(nil)
+
+// Module is a loaded and initialized PKCS#11 module.
+type Module struct {
+	ctx *p11.Ctx
+}
+
+// OpenModule loads and initializes the PKCS#11 module at the given path.
+func OpenModule(path string) (*Module, error) {
+	ctx := p11.New(path)
+	if ctx == nil {
+		return nil, serrors.New("loading PKCS#11 module", "path", path)
+	}
+	if err := ctx.Initialize(); err != nil {
+		ctx.Destroy()
+		return nil, serrors.WrapStr("initializing PKCS#11 module", err, "path", path)
+	}
+	return &Module{ctx: ctx}, nil
+}
+
+// Close finalizes and unloads the module. All sessions that are opened by
+// key rings using this module are invalidated.
+func (m *Module) Close() error {
+	err := m.ctx.Finalize()
+	m.ctx.Destroy()
+	return err
+}
+
+// findSlot returns the slot that holds the token with the given label.
+func (m *Module) findSlot(label string) (uint, error) {
+	slots, err := m.ctx.GetSlotList(true)
+	if err != nil {
+		return 0, serrors.WrapStr("listing slots", err)
+	}
+	for _, slot := range slots {
+		info, err := m.ctx.GetTokenInfo(slot)
+		if err != nil {
+			continue
+		}
+		// The token label is blank padded to 32 bytes.
+		if strings.TrimRight(info.Label, " \x00") == label {
+			return slot, nil
+		}
+	}
+	return 0, serrors.New("token not found", "label", label)
+}
+
+// Ring is a key ring that provides the private keys stored on a PKCS#11
+// token. The key ring keeps a logged in session open. It is safe for
+// concurrent use.
+type Ring struct {
+	// Module is the PKCS#11 module used to access the token.
+	Module *Module
+	// TokenLabel is the label of the token that holds the keys.
+	TokenLabel string
+	// PIN is the user PIN of the token.
+	PIN string
+	// KeyIDs restricts the key ring to the private keys with the given key
+	// IDs. If empty, all private keys on the token are considered.
+	KeyIDs [][]byte
+
+	mtx     sync.Mutex
+	session *session
+}
+
+// PrivateKeys returns a signer for every selected private key on the token.
+func (r *Ring) PrivateKeys(ctx context.Context) ([]crypto.Signer, error) {
+	s, err := r.openSession()
+	if err != nil {
+		return nil, err
+	}
+	signers, err := s.signers(r, r.KeyIDs)
+	if err != nil {
+		// The session might have been invalidated, e.g., because the token was
+		// removed. Force a new session on the next attempt.
+		if isSessionError(err) {
+			r.resetSession(s)
+		}
+		return nil, err
+	}
+	log.FromCtx(ctx).Debug("available keys:", "token", r.TokenLabel, "keys", len(signers))
+	return signers, nil
+}
+
+// Close closes the session that is held by the key ring. The key ring can
+// still be used afterwards, in which case a new session is opened.
+func (r *Ring) Close() error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.session == nil {
+		return nil
+	}
+	err := r.session.close()
+	r.session = nil
+	return err
+}
+
+func (r *Ring) openSession() (*session, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.session != nil {
+		return r.session, nil
+	}
+	if r.Module == nil {
+		return nil, serrors.New("PKCS#11 module not set")
+	}
+	slot, err := r.Module.findSlot(r.TokenLabel)
+	if err != nil {
+		return nil, err
+	}
+	ctx := r.Module.ctx
+	handle, err := ctx.OpenSession(slot, p11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return nil, serrors.WrapStr("opening session", err, "token", r.TokenLabel)
+	}
+	// The login state is shared between all sessions of the application.
+	// Thus, a previous session might have already logged in.
+	err = ctx.Login(handle, p11.CKU_USER, r.PIN)
+	if err != nil && !errors.Is(err, p11.Error(p11.CKR_USER_ALREADY_LOGGED_IN)) {
+		ctx.CloseSession(handle)
+		return nil, serrors.WrapStr("logging in", err, "token", r.TokenLabel)
+	}
+	r.session = &session{
+		ctx:    ctx,
+		handle: handle,
+		keys:   make(map[string]p11.ObjectHandle),
+	}
+	return r.session, nil
+}
+
+func (r *Ring) resetSession(s *session) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.session != s {
+		return
+	}
+	s.close()
+	r.session = nil
+}
+
+// session is a logged in session on a token. PKCS#11 sessions must not be
+// used concurrently, all operations are serialized by the mutex.
+type session struct {
+	mtx    sync.Mutex
+	ctx    *p11.Ctx
+	handle p11.SessionHandle
+	// keys caches the private key handles of this session by key ID.
+	keys map[string]p11.ObjectHandle
+}
+
+func (s *session) close() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.ctx.CloseSession(s.handle)
+}
+
+// signers creates a signer for every private key that matches one of the key
+// IDs. If ids is empty, a signer for every private key is created. The signers
+// are bound to the key ring and not to the session, such that they keep
+// working after the key ring opened a new session.
+func (s *session) signers(r *Ring, ids [][]byte) ([]crypto.Signer, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	keys, err := s.findObjects([]*p11.Attribute{
+		p11.NewAttribute(p11.CKA_CLASS, p11.CKO_PRIVATE_KEY),
+		p11.NewAttribute(p11.CKA_KEY_TYPE, p11.CKK_EC),
+		p11.NewAttribute(p11.CKA_SIGN, true),
+	})
+	if err != nil {
+		return nil, serrors.WrapStr("searching private keys", err)
+	}
+	var signers []crypto.Signer
+	for _, key := range keys {
+		attrs, err := s.ctx.GetAttributeValue(s.handle, key, []*p11.Attribute{
+			p11.NewAttribute(p11.CKA_ID, nil),
+		})
+		if err != nil {
+			return nil, serrors.WrapStr("reading key ID", err)
+		}
+		id := attrs[0].Value
+		if !selected(id, ids) {
+			continue
+		}
+		pub, err := s.publicKey(id)
+		if err != nil {
+			return nil, serrors.WrapStr("loading public key", err, "id", hex.EncodeToString(id))
+		}
+		s.keys[string(id)] = key
+		signers = append(signers, &ecdsaSigner{
+			ring: r,
+			id:   id,
+			pub:  pub,
+		})
+	}
+	return signers, nil
+}
+
+// sign signs the digest with the private key with the given key ID.
+func (s *session) sign(id, digest []byte) ([]byte, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	key, ok := s.keys[string(id)]
+	if !ok {
+		keys, err := s.findObjects([]*p11.Attribute{
+			p11.NewAttribute(p11.CKA_CLASS, p11.CKO_PRIVATE_KEY),
+			p11.NewAttribute(p11.CKA_KEY_TYPE, p11.CKK_EC),
+			p11.NewAttribute(p11.CKA_ID, id),
+		})
+		if err != nil {
+			return nil, serrors.WrapStr("searching private key", err)
+		}
+		if len(keys) != 1 {
+			return nil, serrors.New("unexpected number of private keys", "count", len(keys))
+		}
+		key = keys[0]
+		s.keys[string(id)] = key
+	}
+	mech := []*p11.Mechanism{p11.NewMechanism(p11.CKM_ECDSA, nil)}
+	if err := s.ctx.SignInit(s.handle, mech, key); err != nil {
+		return nil, serrors.WrapStr("initializing signature", err)
+	}
+	raw, err := s.ctx.Sign(s.handle, digest)
+	if err != nil {
+		return nil, serrors.WrapStr("signing", err)
+	}
+	return raw, nil
+}
+
+// publicKey loads the public key with the given key ID.
+func (s *session) publicKey(id []byte) (crypto.PublicKey, error) {
+	objs, err := s.findObjects([]*p11.Attribute{
+		p11.NewAttribute(p11.CKA_CLASS, p11.CKO_PUBLIC_KEY),
+		p11.NewAttribute(p11.CKA_KEY_TYPE, p11.CKK_EC),
+		p11.NewAttribute(p11.CKA_ID, id),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(objs) != 1 {
+		return nil, serrors.New("unexpected number of public keys", "count", len(objs))
+	}
+	attrs, err := s.ctx.GetAttributeValue(s.handle, objs[0], []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_EC_PARAMS, nil),
+		p11.NewAttribute(p11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseECPublicKey(attrs[0].Value, attrs[1].Value)
+}
+
+func (s *session) findObjects(template []*p11.Attribute) ([]p11.ObjectHandle, error) {
+	if err := s.ctx.FindObjectsInit(s.handle, template); err != nil {
+		return nil, err
+	}
+	var all []p11.ObjectHandle
+	for {
+		objs, _, err := s.ctx.FindObjects(s.handle, 16)
+		if err != nil {
+			s.ctx.FindObjectsFinal(s.handle)
+			return nil, err
+		}
+		if len(objs) == 0 {
+			break
+		}
+		all = append(all, objs...)
+	}
+	if err := s.ctx.FindObjectsFinal(s.handle); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+func selected(id []byte, ids [][]byte) bool {
+	if len(ids) == 0 {
+		return true
+	}
+	for _, candidate := range ids {
+		if bytes.Equal(id, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSessionError(err error) bool {
+	for _, code := range []uint{
+		p11.CKR_SESSION_HANDLE_INVALID,
+		p11.CKR_SESSION_CLOSED,
+		p11.CKR_TOKEN_NOT_PRESENT,
+		p11.CKR_DEVICE_REMOVED,
+		p11.CKR_USER_NOT_LOGGED_IN,
+	} {
+		if errors.Is(err, p11.Error(code)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkcs11_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"os"
+	"strings"
+	"testing"
+
+	p11 "github.com/miekg/pkcs11"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/pkg/cs/trust/pkcs11"
+)
+
+// The test requires an initialized token, e.g., created with SoftHSM:
+//
+//	softhsm2-util --init-token --free --label scion --pin 1234 --so-pin 1234
+//
+// It is skipped unless the following environment variables are set.
+const (
+	envModule = "SCION_TEST_PKCS11_MODULE"
+	envToken  = "SCION_TEST_PKCS11_TOKEN"
+	envPIN    = "SCION_TEST_PKCS11_PIN"
+)
+
+func TestRing(t *testing.T) {
+	path, label, pin := os.Getenv(envModule), os.Getenv(envToken), os.Getenv(envPIN)
+	if path == "" || label == "" {
+		t.Skipf("%s and %s not set", envModule, envToken)
+	}
+	module, err := pkcs11.OpenModule(path)
+	require.NoError(t, err)
+	defer module.Close()
+
+	generateKey(t, path, label, pin, []byte("scion-test-1"))
+	generateKey(t, path, label, pin, []byte("scion-test-2"))
+
+	t.Run("selected key", func(t *testing.T) {
+		ring := &pkcs11.Ring{
+			Module:     module,
+			TokenLabel: label,
+			PIN:        pin,
+			KeyIDs:     [][]byte{[]byte("scion-test-1")},
+		}
+		defer ring.Close()
+		keys, err := ring.PrivateKeys(context.Background())
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+
+		digest := sha256.Sum256([]byte("message"))
+		sig, err := keys[0].Sign(rand.Reader, digest[:], crypto.SHA256)
+		require.NoError(t, err)
+		pub, ok := keys[0].Public().(*ecdsa.PublicKey)
+		require.True(t, ok)
+		assert.True(t, ecdsa.VerifyASN1(pub, digest[:], sig))
+	})
+	t.Run("sign after close", func(t *testing.T) {
+		ring := &pkcs11.Ring{
+			Module:     module,
+			TokenLabel: label,
+			PIN:        pin,
+			KeyIDs:     [][]byte{[]byte("scion-test-1")},
+		}
+		defer ring.Close()
+		keys, err := ring.PrivateKeys(context.Background())
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+
+		// Signers that are cached by the caller must keep working after the
+		// session was closed.
+		require.NoError(t, ring.Close())
+		digest := sha256.Sum256([]byte("message"))
+		sig, err := keys[0].Sign(rand.Reader, digest[:], crypto.SHA256)
+		require.NoError(t, err)
+		pub, ok := keys[0].Public().(*ecdsa.PublicKey)
+		require.True(t, ok)
+		assert.True(t, ecdsa.VerifyASN1(pub, digest[:], sig))
+	})
+	t.Run("all keys", func(t *testing.T) {
+		ring := &pkcs11.Ring{
+			Module:     module,
+			TokenLabel: label,
+			PIN:        pin,
+		}
+		defer ring.Close()
+		keys, err := ring.PrivateKeys(context.Background())
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, len(keys), 2)
+	})
+	t.Run("unknown token", func(t *testing.T) {
+		ring := &pkcs11.Ring{
+			Module:     module,
+			TokenLabel: "unknown-" + label,
+			PIN:        pin,
+		}
+		_, err := ring.PrivateKeys(context.Background())
+		assert.Error(t, err)
+	})
+}
+
+// generateKey generates a P-256 key pair with the given ID on the token, and
+// removes it at the end of the test.
+func generateKey(t *testing.T, path, label, pin string, id []byte) {
+	ctx := p11.New(path)
+	require.NotNil(t, ctx)
+	t.Cleanup(ctx.Destroy)
+	err := ctx.Initialize()
+	if err != nil && !strings.Contains(err.Error(), "CKR_CRYPTOKI_ALREADY_INITIALIZED") {
+		require.NoError(t, err)
+	}
+
+	slots, err := ctx.GetSlotList(true)
+	require.NoError(t, err)
+	var slot uint
+	var found bool
+	for _, s := range slots {
+		info, err := ctx.GetTokenInfo(s)
+		require.NoError(t, err)
+		if strings.TrimRight(info.Label, " \x00") == label {
+			slot, found = s, true
+			break
+		}
+	}
+	require.True(t, found, "token %q not found", label)
+
+	session, err := ctx.OpenSession(slot, p11.CKF_SERIAL_SESSION|p11.CKF_RW_SESSION)
+	require.NoError(t, err)
+	t.Cleanup(func() { ctx.CloseSession(session) })
+	err = ctx.Login(session, p11.CKU_USER, pin)
+	if err != nil && !strings.Contains(err.Error(), "CKR_USER_ALREADY_LOGGED_IN") {
+		require.NoError(t, err)
+	}
+
+	params, err := asn1.Marshal(asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7})
+	require.NoError(t, err)
+	pub, priv, err := ctx.GenerateKeyPair(session,
+		[]*p11.Mechanism{p11.NewMechanism(p11.CKM_EC_KEY_PAIR_GEN, nil)},
+		[]*p11.Attribute{
+			p11.NewAttribute(p11.CKA_TOKEN, false),
+			p11.NewAttribute(p11.CKA_VERIFY, true),
+			p11.NewAttribute(p11.CKA_EC_PARAMS, params),
+			p11.NewAttribute(p11.CKA_ID, id),
+		},
+		[]*p11.Attribute{
+			p11.NewAttribute(p11.CKA_TOKEN, false),
+			p11.NewAttribute(p11.CKA_SIGN, true),
+			p11.NewAttribute(p11.CKA_SENSITIVE, true),
+			p11.NewAttribute(p11.CKA_EXTRACTABLE, false),
+			p11.NewAttribute(p11.CKA_ID, id),
+		},
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		ctx.DestroyObject(session, pub)
+		ctx.DestroyObject(session, priv)
+	})
+}
@@ -0,0 +1,113 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkcs11
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/asn1"
+	"io"
+	"math/big"
+
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+var (
+	oidNamedCurveP256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+	oidNamedCurveP384 = asn1.ObjectIdentifier{1, 3, 132, 0, 34}
+	oidNamedCurveP521 = asn1.ObjectIdentifier{1, 3, 132, 0, 35}
+)
+
+// ecdsaSigner is a crypto.Signer that signs with an ECDSA private key on the
+// token. The private key is only referenced by its key ID. The session is
+// resolved through the key ring on every signature, such that a signer stays
+// usable after the key ring was closed or its session was reset.
+type ecdsaSigner struct {
+	ring *Ring
+	id   []byte
+	pub  crypto.PublicKey
+}
+
+// Public returns the public key corresponding to the private key on the token.
+func (s *ecdsaSigner) Public() crypto.PublicKey {
+	return s.pub
+}
+
+// Sign signs the digest on the token. The random source and the signer options
+// are ignored, the caller is expected to hash the message beforehand. The
+// signature is returned in the ASN.1 encoding that is also used by
+// crypto/ecdsa.
+func (s *ecdsaSigner) Sign(_ io.Reader, digest []byte, _ crypto.SignerOpts) ([]byte, error) {
+	sess, err := s.ring.openSession()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := sess.sign(s.id, digest)
+	if err != nil {
+		// Force a new session on the next attempt, see Ring.PrivateKeys.
+		if isSessionError(err) {
+			s.ring.resetSession(sess)
+		}
+		return nil, err
+	}
+	return encodeECDSASignature(raw)
+}
+
+// encodeECDSASignature converts the PKCS#11 signature format r || s to the
+// ASN.1 encoding.
+func encodeECDSASignature(raw []byte) ([]byte, error) {
+	if len(raw) == 0 || len(raw)%2 != 0 {
+		return nil, serrors.New("invalid signature length", "len", len(raw))
+	}
+	half := len(raw) / 2
+	return asn1.Marshal(struct {
+		R, S *big.Int
+	}{
+		R: new(big.Int).SetBytes(raw[:half]),
+		S: new(big.Int).SetBytes(raw[half:]),
+	})
+}
+
+// parseECPublicKey parses the public key from the DER encoded CKA_EC_PARAMS
+// and CKA_EC_POINT attributes.
+func parseECPublicKey(params, point []byte) (*ecdsa.PublicKey, error) {
+	var oid asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(params, &oid); err != nil {
+		return nil, serrors.WrapStr("parsing EC parameters", err)
+	}
+	var curve elliptic.Curve
+	switch {
+	case oid.Equal(oidNamedCurveP256):
+		curve = elliptic.P256()
+	case oid.Equal(oidNamedCurveP384):
+		curve = elliptic.P384()
+	case oid.Equal(oidNamedCurveP521):
+		curve = elliptic.P521()
+	default:
+		return nil, serrors.New("unsupported curve", "oid", oid)
+	}
+	// The point should be wrapped in an octet string. Some modules return
+	// the raw point instead, thus fall back to it if unwrapping fails.
+	var unwrapped []byte
+	if rest, err := asn1.Unmarshal(point, &unwrapped); err == nil && len(rest) == 0 {
+		point = unwrapped
+	}
+	x, y := elliptic.Unmarshal(curve, point)
+	if x == nil {
+		return nil, serrors.New("invalid EC point")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkcs11
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeECDSASignature(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte("message"))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+
+	// PKCS#11 encodes the signature as r || s with fixed size integers.
+	raw := make([]byte, 64)
+	r.FillBytes(raw[:32])
+	s.FillBytes(raw[32:])
+
+	sig, err := encodeECDSASignature(raw)
+	require.NoError(t, err)
+	assert.True(t, ecdsa.VerifyASN1(&key.PublicKey, digest[:], sig))
+
+	_, err = encodeECDSASignature(raw[:63])
+	assert.Error(t, err)
+}
+
+func TestParseECPublicKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	params, err := asn1.Marshal(oidNamedCurveP256)
+	require.NoError(t, err)
+	rawPoint := elliptic.Marshal(elliptic.P256(), key.X, key.Y)
+	wrappedPoint, err := asn1.Marshal(rawPoint)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		Params       []byte
+		Point        []byte
+		ErrAssertion assert.ErrorAssertionFunc
+	}{
+		"wrapped point": {
+			Params:       params,
+			Point:        wrappedPoint,
+			ErrAssertion: assert.NoError,
+		},
+		"raw point": {
+			Params:       params,
+			Point:        rawPoint,
+			ErrAssertion: assert.NoError,
+		},
+		"unknown curve": {
+			Params:       mustMarshal(t, asn1.ObjectIdentifier{1, 2, 3}),
+			Point:        wrappedPoint,
+			ErrAssertion: assert.Error,
+		},
+		"invalid point": {
+			Params:       params,
+			Point:        []byte{0x04, 0x01},
+			ErrAssertion: assert.Error,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			pub, err := parseECPublicKey(tc.Params, tc.Point)
+			tc.ErrAssertion(t, err)
+			if err != nil {
+				return
+			}
+			assert.True(t, key.PublicKey.Equal(pub))
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	raw, err := asn1.Marshal(v)
+	require.NoError(t, err)
+	return raw
+}
@@ -0,0 +1,300 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trust
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/scrypto/cppki"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/pkg/ca/renewal"
+	"github.com/scionproto/scion/go/pkg/cs/trust/metrics"
+	cppb "github.com/scionproto/scion/go/pkg/proto/control_plane"
+	"github.com/scionproto/scion/go/pkg/trust"
+)
+
+// ChainRequester requests a renewed certificate chain from a CA.
+type ChainRequester interface {
+	RequestChain(ctx context.Context, ca addr.IA,
+		req *cppb.ChainRenewalRequest) ([]*x509.Certificate, error)
+}
+
+// ChainRenewer is a periodic task that renews the AS certificate chain before
+// it expires. The renewed chain is verified against the active TRCs, written
+// to the crypto directory together with its private key, and inserted into the
+// trust DB, where it is picked up by the signer generator.
+type ChainRenewer struct {
+	// IA is the ISD-AS of the local AS.
+	IA addr.IA
+	// SignerGen generates the active signer. The certificate chain of the
+	// signer determines when the renewal is due, and the signer authenticates
+	// the renewal request.
+	SignerGen SignerGen
+	// DB provides the TRCs that the renewed chain is verified against, and
+	// stores the renewed chain.
+	DB trust.DB
+	// Requester sends the renewal request to the CA.
+	Requester ChainRequester
+	// CA is the ISD-AS of the CA. If it is zero, the renewal request is sent
+	// to the issuer of the active AS certificate.
+	CA addr.IA
+	// Dir is the directory that the renewed chain and key are written to.
+	Dir string
+	// LifetimeFraction is the fraction of the AS certificate validity period
+	// after which the chain is renewed.
+	LifetimeFraction float64
+	// ReuseKey indicates that the private key of the active signer is reused
+	// for the renewed chain. Otherwise, a fresh key is generated.
+	ReuseKey bool
+
+	timeNowFcn func() time.Time
+}
+
+// Name returns the task name.
+func (r *ChainRenewer) Name() string {
+	return "control_as_certificate_renewer"
+}
+
+// Run renews the AS certificate chain if the renewal is due.
+func (r *ChainRenewer) Run(ctx context.Context) {
+	logger := log.FromCtx(ctx)
+	l := metrics.RenewalLabels{}
+
+	signer, err := r.SignerGen.Generate(ctx)
+	if err != nil {
+		metrics.Renewal.Renewals(l.WithResult(metrics.ErrNotFound)).Inc()
+		logger.Error("No active AS certificate chain, cannot renew", "err", err)
+		return
+	}
+	renewAt := renewalTime(signer.ChainValidity, r.LifetimeFraction)
+	metrics.Renewal.NextRenewal().Set(metrics.Timestamp(renewAt))
+	now := r.now()
+	if now.Before(renewAt) {
+		return
+	}
+	logger.Info("Renewing AS certificate chain",
+		"subject_key_id", fmt.Sprintf("%x", signer.SubjectKeyID),
+		"validity", signer.ChainValidity,
+	)
+	chain, result, err := r.renew(ctx, signer, now)
+	metrics.Renewal.Renewals(l.WithResult(result)).Inc()
+	if err != nil {
+		logger.Error("Failed to renew AS certificate chain", "err", err,
+			"expires_in", signer.ChainValidity.NotAfter.Sub(now).Round(time.Second),
+		)
+		return
+	}
+	metrics.Renewal.LastRenewal().SetToCurrentTime()
+	logger.Info("Renewed AS certificate chain",
+		"subject_key_id", fmt.Sprintf("%x", chain[0].SubjectKeyId),
+		"validity", cppki.Validity{
+			NotBefore: chain[0].NotBefore,
+			NotAfter:  chain[0].NotAfter,
+		},
+	)
+}
+
+func (r *ChainRenewer) renew(ctx context.Context, signer trust.Signer,
+	now time.Time) ([]*x509.Certificate, string, error) {
+
+	key := signer.PrivateKey
+	if !r.ReuseKey {
+		var err error
+		if key, err = generateKey(signer.PrivateKey.Public()); err != nil {
+			return nil, metrics.ErrCrypto, serrors.WrapStr("generating private key", err)
+		}
+	}
+	subject := signer.Subject
+	subject.ExtraNames = signer.Subject.Names
+	csr, err := x509.CreateCertificateRequest(rand.Reader,
+		&x509.CertificateRequest{Subject: subject}, key)
+	if err != nil {
+		return nil, metrics.ErrCrypto, serrors.WrapStr("creating CSR", err)
+	}
+	req, err := renewal.NewChainRenewalRequest(ctx, csr, signer)
+	if err != nil {
+		return nil, metrics.ErrCrypto, serrors.WrapStr("signing renewal request", err)
+	}
+	ca := r.CA
+	if ca.IsZero() {
+		if ca, err = cppki.ExtractIA(signer.Chain[0].Issuer); err != nil {
+			return nil, metrics.ErrInternal, serrors.WrapStr("extracting issuer", err)
+		}
+	}
+	chain, err := r.Requester.RequestChain(ctx, ca, req)
+	if err != nil {
+		return nil, metrics.ErrNetwork, err
+	}
+	if result, err := r.verify(ctx, chain, key.Public(), now); err != nil {
+		return nil, result, serrors.WrapStr("verifying renewed chain", err)
+	}
+	if err := r.write(chain, key); err != nil {
+		return nil, metrics.ErrInternal, serrors.WrapStr("writing renewed chain", err)
+	}
+	if _, err := r.DB.InsertChain(ctx, chain); err != nil {
+		return nil, metrics.ErrDB, serrors.WrapStr("inserting renewed chain", err)
+	}
+	return chain, metrics.Success, nil
+}
+
+// verify checks that the chain authenticates the given key for the local AS,
+// and that it is verifiable with the active TRCs.
+func (r *ChainRenewer) verify(ctx context.Context, chain []*x509.Certificate,
+	pub crypto.PublicKey, now time.Time) (string, error) {
+
+	ia, err := cppki.ExtractIA(chain[0].Subject)
+	if err != nil {
+		return metrics.ErrVerify, err
+	}
+	if !ia.Equal(r.IA) {
+		return metrics.ErrVerify, serrors.New("chain for wrong ISD-AS", "isd_as", ia)
+	}
+	skid, err := cppki.SubjectKeyID(pub)
+	if err != nil {
+		return metrics.ErrCrypto, err
+	}
+	if !bytes.Equal(skid, chain[0].SubjectKeyId) {
+		return metrics.ErrVerify, serrors.New("chain does not authenticate requested key",
+			"expected", fmt.Sprintf("%x", skid),
+			"actual", fmt.Sprintf("%x", chain[0].SubjectKeyId),
+		)
+	}
+	trc, err := r.DB.SignedTRC(ctx, cppki.TRCID{
+		ISD:    r.IA.I,
+		Base:   scrypto.LatestVer,
+		Serial: scrypto.LatestVer,
+	})
+	if err != nil {
+		return metrics.ErrDB, serrors.WrapStr("loading TRC", err)
+	}
+	if trc.IsZero() {
+		return metrics.ErrNotFound, serrors.New("TRC not found", "isd", r.IA.I)
+	}
+	trcs := []*cppki.TRC{&trc.TRC}
+	if trc.TRC.InGracePeriod(now) {
+		grace, err := r.DB.SignedTRC(ctx, cppki.TRCID{
+			ISD:    r.IA.I,
+			Base:   trc.TRC.ID.Base,
+			Serial: trc.TRC.ID.Serial - 1,
+		})
+		if err != nil {
+			return metrics.ErrDB, serrors.WrapStr("loading grace TRC", err)
+		}
+		if !grace.IsZero() {
+			trcs = append(trcs, &grace.TRC)
+		}
+	}
+	opts := cppki.VerifyOptions{TRC: trcs, CurrentTime: now}
+	if err := cppki.VerifyChain(chain, opts); err != nil {
+		return metrics.ErrVerify, err
+	}
+	return metrics.Success, nil
+}
+
+// write writes the key and the chain to the crypto directory. The key is
+// written first, such that the chain is never loaded without its key.
+func (r *ChainRenewer) write(chain []*x509.Certificate, key crypto.Signer) error {
+	ia, err := cppki.ExtractIA(chain[0].Subject)
+	if err != nil {
+		return err
+	}
+	serial := chain[0].SerialNumber.Bytes()
+	if !r.ReuseKey {
+		raw, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return serrors.WrapStr("encoding private key", err)
+		}
+		keyFile := filepath.Join(r.Dir, fmt.Sprintf("cp-as.%x.key", serial))
+		pemKey := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: raw})
+		if err := writeFileAtomic(keyFile, pemKey, 0600); err != nil {
+			return err
+		}
+	}
+	var pemChain []byte
+	for _, c := range chain {
+		pemChain = append(pemChain, pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: c.Raw,
+		})...)
+	}
+	chainFile := filepath.Join(r.Dir, fmt.Sprintf("ISD%d-AS%s.%x.pem",
+		ia.I, ia.A.FileFmt(), serial))
+	return writeFileAtomic(chainFile, pemChain, 0644)
+}
+
+func (r *ChainRenewer) now() time.Time {
+	if r.timeNowFcn != nil {
+		return r.timeNowFcn()
+	}
+	return time.Now()
+}
+
+// renewalTime returns the point in time after which the chain with the given
+// validity should be renewed.
+func renewalTime(validity cppki.Validity, fraction float64) time.Time {
+	lifetime := validity.NotAfter.Sub(validity.NotBefore)
+	return validity.NotBefore.Add(time.Duration(float64(lifetime) * fraction))
+}
+
+// generateKey generates a new private key. If the current key is an ECDSA
+// key, the same curve is used. Otherwise, a P-256 key is generated.
+func generateKey(current crypto.PublicKey) (crypto.Signer, error) {
+	curve := elliptic.P256()
+	if pub, ok := current.(*ecdsa.PublicKey); ok {
+		curve = pub.Curve
+	}
+	return ecdsa.GenerateKey(curve, rand.Reader)
+}
+
+// writeFileAtomic writes the data to a temporary file in the same directory
+// and renames it to the target file. The temporary file does not match the
+// patterns of the key and chain loaders.
+func writeFileAtomic(file string, data []byte, perm os.FileMode) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(file), "."+filepath.Base(file)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
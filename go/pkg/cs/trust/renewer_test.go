@@ -0,0 +1,228 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trust_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/scrypto/cms/protocol"
+	"github.com/scionproto/scion/go/lib/scrypto/cppki"
+	"github.com/scionproto/scion/go/lib/scrypto/signed"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/xtest"
+	cstrust "github.com/scionproto/scion/go/pkg/cs/trust"
+	"github.com/scionproto/scion/go/pkg/cs/trust/mock_trust"
+	cppb "github.com/scionproto/scion/go/pkg/proto/control_plane"
+	"github.com/scionproto/scion/go/pkg/trust"
+	libmock_trust "github.com/scionproto/scion/go/pkg/trust/mock_trust"
+)
+
+func TestChainRenewerRun(t *testing.T) {
+	ia := xtest.MustParseIA("1-ff00:0:111")
+	ca := xtest.MustParseIA("1-ff00:0:110")
+	trc := xtest.LoadTRC(t, "testdata/common/trcs/ISD1-B1-S1.trc")
+	chain := xtest.LoadChain(t,
+		"testdata/common/ISD1/ASff00_0_111/crypto/as/ISD1-ASff00_0_111.pem")
+	key := loadKey(t, "testdata/common/ISD1/ASff00_0_111/crypto/as/cp-as.key")
+	caPolicy := cppki.CAPolicy{
+		Validity: 3 * 24 * time.Hour,
+		Certificate: xtest.LoadChain(t,
+			"testdata/common/ISD1/ASff00_0_110/crypto/ca/ISD1-ASff00_0_110.ca.crt")[0],
+		Signer: loadKey(t, "testdata/common/ISD1/ASff00_0_110/crypto/ca/cp-ca.key"),
+	}
+	algo, err := signed.SelectSignatureAlgorithm(key.Public())
+	require.NoError(t, err)
+	// The signer expiration is checked against the wall clock when signing,
+	// the renewer only considers the chain validity.
+	signer := trust.Signer{
+		PrivateKey:   key,
+		Algorithm:    algo,
+		IA:           ia,
+		TRCID:        trc.TRC.ID,
+		Subject:      chain[0].Subject,
+		Chain:        chain,
+		SubjectKeyID: chain[0].SubjectKeyId,
+		Expiration:   time.Now().Add(time.Hour),
+		ChainValidity: cppki.Validity{
+			NotBefore: chain[0].NotBefore,
+			NotAfter:  chain[0].NotAfter,
+		},
+	}
+	// The testdata chain is valid for one year. With a lifetime fraction of
+	// 0.75, the renewal is due in February 2021.
+	due := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	caPolicy.CurrentTime = due
+
+	testCases := map[string]struct {
+		Now       time.Time
+		ReuseKey  bool
+		Requester func(t *testing.T) cstrust.ChainRequester
+		Renewed   bool
+	}{
+		"not due": {
+			Now: time.Date(2020, 12, 1, 0, 0, 0, 0, time.UTC),
+			Requester: func(t *testing.T) cstrust.ChainRequester {
+				return requesterFunc(func([]byte) ([]*x509.Certificate, error) {
+					t.Fatal("unexpected renewal request")
+					return nil, nil
+				})
+			},
+		},
+		"new key": {
+			Now: due,
+			Requester: func(t *testing.T) cstrust.ChainRequester {
+				return requesterFunc(func(csr []byte) ([]*x509.Certificate, error) {
+					return caPolicy.CreateChain(parseCSR(t, csr))
+				})
+			},
+			Renewed: true,
+		},
+		"reuse key": {
+			Now:      due,
+			ReuseKey: true,
+			Requester: func(t *testing.T) cstrust.ChainRequester {
+				return requesterFunc(func(csr []byte) ([]*x509.Certificate, error) {
+					parsed := parseCSR(t, csr)
+					assert.Equal(t, key.Public(), parsed.PublicKey)
+					return caPolicy.CreateChain(parsed)
+				})
+			},
+			Renewed: true,
+		},
+		"request fails": {
+			Now: due,
+			Requester: func(t *testing.T) cstrust.ChainRequester {
+				return requesterFunc(func([]byte) ([]*x509.Certificate, error) {
+					return nil, serrors.New("internal")
+				})
+			},
+		},
+		"chain for other key": {
+			Now: due,
+			Requester: func(t *testing.T) cstrust.ChainRequester {
+				return requesterFunc(func([]byte) ([]*x509.Certificate, error) {
+					return chain, nil
+				})
+			},
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			dir, cleanF := xtest.MustTempDir("", "renewer")
+			defer cleanF()
+
+			gen := mock_trust.NewMockSignerGen(ctrl)
+			gen.EXPECT().Generate(gomock.Any()).Return(signer, nil)
+			db := libmock_trust.NewMockDB(ctrl)
+			db.EXPECT().SignedTRC(gomock.Any(), gomock.Any()).Return(trc, nil).AnyTimes()
+			var inserted []*x509.Certificate
+			if tc.Renewed {
+				db.EXPECT().InsertChain(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, c []*x509.Certificate) (bool, error) {
+						inserted = c
+						return true, nil
+					},
+				)
+			}
+
+			renewer := &cstrust.ChainRenewer{
+				IA:               ia,
+				SignerGen:        gen,
+				DB:               db,
+				Requester:        tc.Requester(t),
+				CA:               ca,
+				Dir:              dir,
+				LifetimeFraction: 0.75,
+				ReuseKey:         tc.ReuseKey,
+			}
+			renewer.SetTimeNowFunction(func() time.Time { return tc.Now })
+			renewer.Run(context.Background())
+
+			keys, err := filepath.Glob(filepath.Join(dir, "*.key"))
+			require.NoError(t, err)
+			chains, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+			require.NoError(t, err)
+			if !tc.Renewed {
+				assert.Empty(t, keys)
+				assert.Empty(t, chains)
+				return
+			}
+			require.Len(t, chains, 1)
+			written := xtest.LoadChain(t, chains[0])
+			assert.Equal(t, inserted, written)
+			if tc.ReuseKey {
+				assert.Empty(t, keys)
+				return
+			}
+			require.Len(t, keys, 1)
+			skid, err := cppki.SubjectKeyID(loadKey(t, keys[0]).Public())
+			require.NoError(t, err)
+			assert.Equal(t, written[0].SubjectKeyId, skid)
+			assert.NotEqual(t, chain[0].SubjectKeyId, skid)
+		})
+	}
+}
+
+// requesterFunc issues a chain for the CSR in the renewal request.
+type requesterFunc func(csr []byte) ([]*x509.Certificate, error)
+
+func (f requesterFunc) RequestChain(_ context.Context, _ addr.IA,
+	req *cppb.ChainRenewalRequest) ([]*x509.Certificate, error) {
+
+	ci, err := protocol.ParseContentInfo(req.CmsSignedRequest)
+	if err != nil {
+		return nil, err
+	}
+	sd, err := ci.SignedDataContent()
+	if err != nil {
+		return nil, err
+	}
+	csr, err := sd.EncapContentInfo.DataEContent()
+	if err != nil {
+		return nil, err
+	}
+	return f(csr)
+}
+
+func parseCSR(t *testing.T, raw []byte) *x509.CertificateRequest {
+	csr, err := x509.ParseCertificateRequest(raw)
+	require.NoError(t, err)
+	return csr
+}
+
+func loadKey(t *testing.T, file string) crypto.Signer {
+	raw, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	block, _ := pem.Decode(raw)
+	require.NotNil(t, block)
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	require.NoError(t, err)
+	return key.(crypto.Signer)
+}
@@ -0,0 +1,146 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/scionproto/scion/go/dispatcher/dispatcher"
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/pkg/api"
+)
+
+// Registrations gives access to the application registrations of the
+// dispatcher.
+type Registrations interface {
+	Registrations() []dispatcher.Registration
+	Registration(id uint64) (dispatcher.Registration, bool)
+	CloseRegistration(id uint64) bool
+}
+
+// Server implements the Dispatcher API.
+type Server struct {
+	Registrations Registrations
+	Config        http.HandlerFunc
+	Info          http.HandlerFunc
+	LogLevel      http.HandlerFunc
+}
+
+// GetRegistrations lists the active application registrations.
+func (s *Server) GetRegistrations(w http.ResponseWriter, r *http.Request) {
+	regs := s.Registrations.Registrations()
+	rep := make([]Registration, 0, len(regs))
+	for _, reg := range regs {
+		rep = append(rep, translateRegistration(reg))
+	}
+	writeJSON(w, rep)
+}
+
+// GetRegistration describes the application registration with the given ID.
+func (s *Server) GetRegistration(w http.ResponseWriter, r *http.Request, id RegistrationID) {
+	reg, ok := s.Registrations.Registration(uint64(id))
+	if !ok {
+		Error(w, Problem{
+			Detail: api.StringRef(fmt.Sprintf("registration %d does not exist", id)),
+			Status: http.StatusNotFound,
+			Title:  "registration not found",
+			Type:   api.StringRef(api.NotFound),
+		})
+		return
+	}
+	writeJSON(w, translateRegistration(reg))
+}
+
+// DeleteRegistration forcibly closes the application registration with the
+// given ID.
+func (s *Server) DeleteRegistration(w http.ResponseWriter, r *http.Request, id RegistrationID) {
+	if !s.Registrations.CloseRegistration(uint64(id)) {
+		Error(w, Problem{
+			Detail: api.StringRef(fmt.Sprintf("registration %d does not exist", id)),
+			Status: http.StatusNotFound,
+			Title:  "registration not found",
+			Type:   api.StringRef(api.NotFound),
+		})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetConfig is an indirection to the http handler.
+func (s *Server) GetConfig(w http.ResponseWriter, r *http.Request) {
+	s.Config(w, r)
+}
+
+// GetInfo is an indirection to the http handler.
+func (s *Server) GetInfo(w http.ResponseWriter, r *http.Request) {
+	s.Info(w, r)
+}
+
+// GetLogLevel is an indirection to the http handler.
+func (s *Server) GetLogLevel(w http.ResponseWriter, r *http.Request) {
+	s.LogLevel(w, r)
+}
+
+// SetLogLevel is an indirection to the http handler.
+func (s *Server) SetLogLevel(w http.ResponseWriter, r *http.Request) {
+	s.LogLevel(w, r)
+}
+
+// Error creates an detailed error response.
+func Error(w http.ResponseWriter, p Problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(int(p.Status))
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	// no point in catching error here, there is nothing we can do about it anymore.
+	enc.Encode(p)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(v); err != nil {
+		Error(w, Problem{
+			Detail: api.StringRef(err.Error()),
+			Status: http.StatusInternalServerError,
+			Title:  "unable to marshal response",
+			Type:   api.StringRef(api.InternalError),
+		})
+	}
+}
+
+func translateRegistration(reg dispatcher.Registration) Registration {
+	rep := Registration{
+		Id:         RegistrationID(reg.ID),
+		IsdAs:      IsdAs(reg.IA.String()),
+		Public:     reg.Public.String(),
+		Registered: reg.Registered.UTC(),
+		Stats: RegistrationStats{
+			DeliveredPackets: int64(reg.Stats.Delivered),
+			DroppedPackets:   int64(reg.Stats.Dropped),
+			ScmpErrors:       int64(reg.Stats.SCMPErrors),
+		},
+	}
+	if reg.Bind != nil {
+		rep.Bind = api.StringRef(reg.Bind.String())
+	}
+	if reg.SVC != addr.SvcNone {
+		rep.Svc = api.StringRef(reg.SVC.BaseString())
+	}
+	return rep
+}
@@ -0,0 +1,149 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"flag"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/dispatcher/dispatcher"
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/pkg/dispatcher/api/mock_api"
+)
+
+// update is a cmd line flag that enables golden file updates. To update the
+// golden files simply run 'go test -update ./...'.
+var update = flag.Bool("update", false, "set to true to regenerate golden files")
+
+func TestAPI(t *testing.T) {
+	registered := time.Date(2021, 1, 4, 9, 59, 33, 0, time.UTC)
+	app := dispatcher.Registration{
+		ID:         1,
+		IA:         xtest.MustParseIA("1-ff00:0:110"),
+		Public:     &net.UDPAddr{IP: net.ParseIP("192.0.2.1"), Port: 32768},
+		SVC:        addr.SvcNone,
+		Registered: registered,
+		Stats: dispatcher.RegistrationStats{
+			Delivered:  42,
+			Dropped:    3,
+			SCMPErrors: 1,
+		},
+	}
+	cs := dispatcher.Registration{
+		ID:         2,
+		IA:         xtest.MustParseIA("1-ff00:0:110"),
+		Public:     &net.UDPAddr{IP: net.ParseIP("192.0.2.2"), Port: 30252},
+		Bind:       &net.UDPAddr{IP: net.ParseIP("192.0.2.2"), Port: 30252},
+		SVC:        addr.SvcCS,
+		Registered: registered,
+	}
+
+	testCases := map[string]struct {
+		Handler      func(t *testing.T, ctrl *gomock.Controller) http.Handler
+		Method       string
+		RequestURL   string
+		ResponseFile string
+		Status       int
+	}{
+		"registrations": {
+			Handler: func(t *testing.T, ctrl *gomock.Controller) http.Handler {
+				regs := mock_api.NewMockRegistrations(ctrl)
+				regs.EXPECT().Registrations().Return([]dispatcher.Registration{app, cs})
+				return Handler(&Server{Registrations: regs})
+			},
+			Method:       http.MethodGet,
+			RequestURL:   "/registrations",
+			ResponseFile: "testdata/registrations.json",
+			Status:       http.StatusOK,
+		},
+		"registration": {
+			Handler: func(t *testing.T, ctrl *gomock.Controller) http.Handler {
+				regs := mock_api.NewMockRegistrations(ctrl)
+				regs.EXPECT().Registration(uint64(2)).Return(cs, true)
+				return Handler(&Server{Registrations: regs})
+			},
+			Method:       http.MethodGet,
+			RequestURL:   "/registrations/2",
+			ResponseFile: "testdata/registration.json",
+			Status:       http.StatusOK,
+		},
+		"registration not found": {
+			Handler: func(t *testing.T, ctrl *gomock.Controller) http.Handler {
+				regs := mock_api.NewMockRegistrations(ctrl)
+				regs.EXPECT().Registration(uint64(3)).Return(dispatcher.Registration{}, false)
+				return Handler(&Server{Registrations: regs})
+			},
+			Method:       http.MethodGet,
+			RequestURL:   "/registrations/3",
+			ResponseFile: "testdata/registration-not-found.json",
+			Status:       http.StatusNotFound,
+		},
+		"delete registration": {
+			Handler: func(t *testing.T, ctrl *gomock.Controller) http.Handler {
+				regs := mock_api.NewMockRegistrations(ctrl)
+				regs.EXPECT().CloseRegistration(uint64(1)).Return(true)
+				return Handler(&Server{Registrations: regs})
+			},
+			Method:       http.MethodDelete,
+			RequestURL:   "/registrations/1",
+			ResponseFile: "testdata/delete-registration.txt",
+			Status:       http.StatusNoContent,
+		},
+		"delete registration not found": {
+			Handler: func(t *testing.T, ctrl *gomock.Controller) http.Handler {
+				regs := mock_api.NewMockRegistrations(ctrl)
+				regs.EXPECT().CloseRegistration(uint64(3)).Return(false)
+				return Handler(&Server{Registrations: regs})
+			},
+			Method:       http.MethodDelete,
+			RequestURL:   "/registrations/3",
+			ResponseFile: "testdata/registration-not-found.json",
+			Status:       http.StatusNotFound,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			req, err := http.NewRequest(tc.Method, tc.RequestURL, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			tc.Handler(t, ctrl).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.Status, rr.Result().StatusCode)
+
+			if *update {
+				require.NoError(t, ioutil.WriteFile(tc.ResponseFile, rr.Body.Bytes(), 0666))
+			}
+			golden, err := ioutil.ReadFile(tc.ResponseFile)
+			require.NoError(t, err)
+			assert.Equal(t, string(golden), rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/scionproto/scion/go/pkg/dispatcher/api (interfaces: Registrations)
+
+// Package mock_api is a generated GoMock package.
+package mock_api
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	dispatcher "github.com/scionproto/scion/go/dispatcher/dispatcher"
+	reflect "reflect"
+)
+
+// MockRegistrations is a mock of Registrations interface
+type MockRegistrations struct {
+	ctrl     *gomock.Controller
+	recorder *MockRegistrationsMockRecorder
+}
+
+// MockRegistrationsMockRecorder is the mock recorder for MockRegistrations
+type MockRegistrationsMockRecorder struct {
+	mock *MockRegistrations
+}
+
+// NewMockRegistrations creates a new mock instance
+func NewMockRegistrations(ctrl *gomock.Controller) *MockRegistrations {
+	mock := &MockRegistrations{ctrl: ctrl}
+	mock.recorder = &MockRegistrationsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRegistrations) EXPECT() *MockRegistrationsMockRecorder {
+	return m.recorder
+}
+
+// CloseRegistration mocks base method
+func (m *MockRegistrations) CloseRegistration(arg0 uint64) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseRegistration", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// CloseRegistration indicates an expected call of CloseRegistration
+func (mr *MockRegistrationsMockRecorder) CloseRegistration(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseRegistration", reflect.TypeOf((*MockRegistrations)(nil).CloseRegistration), arg0)
+}
+
+// Registration mocks base method
+func (m *MockRegistrations) Registration(arg0 uint64) (dispatcher.Registration, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Registration", arg0)
+	ret0, _ := ret[0].(dispatcher.Registration)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Registration indicates an expected call of Registration
+func (mr *MockRegistrationsMockRecorder) Registration(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Registration", reflect.TypeOf((*MockRegistrations)(nil).Registration), arg0)
+}
+
+// Registrations mocks base method
+func (m *MockRegistrations) Registrations() []dispatcher.Registration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Registrations")
+	ret0, _ := ret[0].([]dispatcher.Registration)
+	return ret0
+}
+
+// Registrations indicates an expected call of Registrations
+func (mr *MockRegistrationsMockRecorder) Registrations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Registrations", reflect.TypeOf((*MockRegistrations)(nil).Registrations))
+}
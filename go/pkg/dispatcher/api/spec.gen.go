@@ -0,0 +1,126 @@
+// Package api provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/deepmap/oapi-codegen DO NOT EDIT.
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Base64 encoded, gzipped, json marshaled Swagger object
+var swaggerSpec = []string{
+
+	"H4sIAAAAAAAC/+xZbZPbthH+KztIZtpMKIm6uyS1vtlnJ9WMHd9Yl3amydUDEUsSMQkwAChbveq/dxag",
+	"eHyT767NuGkn30S87j777Bt0yxJdVlqhcpatbplBW2ll0X884+IN/lKjdfSVaOVQ+Z+8qgqZcCe1Wvxs",
+	"taIxm+RYcvr1ucGUrdhni7ujF2HWLjaOK8GNeGGMNuxwOERMoE2MrOgwtqI7wTSX0myzkc5dW/HU/8AP",
+	"vKwKZCu2nKVpHK/i1XIZs4hV3Dk0dMzff/pJfDn74498lsazJze3y+jisPri9uzQH/rin7TucxYxJ50/",
+	"cb15Pnu6gbVA5WQq0dDcvqIp64xUGTtE7KXOXuIOCxKmMrpC42SArDgO97V6qbNMqgzCdMRQ1SVb/cgE",
+	"buuMRUyqVNOwR+Um6mjYzAxEOESMQJIGBR0Tjr1pl+ntz5g4kvTK6G2B5VhQgY7LCUmfQl6XXIFBLvi2",
+	"QMAPVcGVtzXYChOZygScBpdLCzpJamNQJQg6BZcjVOFCcDl3IC3kWFRpXdCOQifcYW8VVwIyuUPgYifp",
+	"EAW5fk+LK6MTRDGHvxrpHCqQCl6orJA297ta+VJtAFUmFaKxEdS25kWxB6Ud2Fo6FH6F0gocJrmSCS/A",
+	"Ov4Oc10INNafRqtJvEL+A8WcdQ1wqZXCxKvvNAju+JZbBCdLFKBrN8UPqazjKsEpeH94swaDKQbUAkxH",
+	"slkPTovySXQjwHk2h+0euBDEKw6p4VmJqnOYAW3A1ttZxV0eLNYxz77CObzie9gi1BbFwEBGaxculbbd",
+	"JFWQT9cmQUi0wD5Ui2bhImkxm3lKf+b0O1Qz4vKMDDfz6M0Ceqk2JXdsxWojZy0yU7Bax11tx6Be5wh/",
+	"vr6+grDASwYZKjSc7L/de7G1kZlUYNHs0HhSfJzCPd2+is/pKylqK3f4in+QJXmwMzVGrDx+fh3HESul",
+	"Cl/LOG6VkMphhoYd2kgzZobNtSHSliU3+5E/eYP9t51hg8b76Q+K77gs6M4pQ4UB0jDldUG25Vtdu9W2",
+	"4Oodix7iE7WSv9RY7IfO0cUDtCr2R1b69PTBdXDbSYECnl6t5/C6qnRD8q6HhagmFbz59nL2zZ/ibyKQ",
+	"PmoplC5HAwYTXZaoRNi7RRB4FNQDTnhVWipH0zzEzllrDqGTmpwy3KO0gazQW2+SoF9Dw4GZH+ZUj3Cd",
+	"Qbpo/OhIxam88QYzaZ3hwUTD5LGVSowZ/EwqQQHJoLVHM2z+cgmmc1YEMgWu9n0dl0/O5vH8bL5cnS/j",
+	"OJ4MqeK+0qIr8vq532PFW27v2xfKikPEqnpbyGSs15UfH2rW1YootL5aVeS/SgdzP1rDcCB6G40inCwR",
+	"uIP3uUxyf32nBIO7nf1Lz+Kz5SxezuKL6/jJ6qsnq/Pzv3VZI7hDH4pPBVv7GMw3fgPt3E2ASEQ4IjiU",
+	"X9qOCuQS0yy53NzLbClYa/bWoD1oj4rdR/r1c9KhhUoq9/UF6wT3+K5k7G6bLBw7wX8M2ERlVsgdyfq2",
+	"4sk7dBMJ7/u63FJ+T6FZAu2mY4jr4Dtn0ViRsWjC6Kp65K1hC2wx4bXF4cV/ILsmSPXdtk5TNPCeW0jr",
+	"onigSDYpq7c+2H1UnM3lqyvwy6BEa3mGvwIgA2aNrTJGrC/wFMP67c/I9Hgc7mv6oqvavT7QNhGD2w+H",
+	"ps8Ylx5X6zYRbS7Xr7+H59JW3CV5YHHD87tBSqosYjs0NpwQz+P5khTUFSpeSbZi5xTvQk+We+UogaUy",
+	"o58Z+jaSVA/OJtiKfYfuMqyI+m3oWRwP+k9K9Iuq4HLQeQ6BGXWXmzpJ0FpqSF4fLyexL+L4VKhrRVl0",
+	"2mE6uSnUKEEYqVwIa9evX72EoGjdRIRUFj6lO55Zsg9VFFqxGzpjcTTIKUTWof3738LjGbcyAamCjxEG",
+	"Fc8QfA3Y1mpGF2CbctI3e9aeRKnQ2aLtrE9B1Tbl98L17z9ftHd8Miy/QyrG+68HI4wo1U2AshmA4s9/",
+	"psX+k+BxfPPo3h9iFHVNh/8rK20eYiVicrdqtB02D6CT1g2Tlg19ETcIoWF1xb5bNr2XLlSGog3TETid",
+	"oW9k/GyFZtYrW0Pi8m2ztE4m3gNHjvWmJ/N/aDfpsHxUWXnXTzJuDN9PUo3w0ulEUdxIPe+Y+ISoTZv1",
+	"5eOodnxfmxBqrXa8kIPXzJYxUybuy9yhUHd8ikiL2+7nTIpDU0iim3hp+FabRG6LPSSFtgi8d+0crkOA",
+	"7jx6TdTrfqvw7e9dM+R5eCzyfVFfILcoxqx67kXrmZkqBcNLdGhI51smSVaqHljEFC+RrdhAy1FQiR5o",
+	"tWGreLgZsfpijFt3VwPAb4JXJMHFp5SgB4TSDlJdKzEg+KUn18cYfprg0XRYpFzoI9zdqPf6uxfTU1fd",
+	"G9d+a/T79ZJhP5aOjfn0BGTd+u13mp+k+ZGTjye5P8c/RgfC1aZgK5Y7V60Wi9tcW3dY3VbauAO1WtxI",
+	"elL09KC5/vOqf671w1QLajOYPo8vLpak4k0rzajH3KHZu5wKGIOFfzd3+mMJddIr2CEaHnzpyx/qGOmv",
+	"JJ81tvumzWzq/+5pTbV0uDn8awCE6sBqmxwAAA==",
+}
+
+// GetSwagger returns the content of the embedded swagger specification file
+// or error if failed to decode
+func decodeSpec() ([]byte, error) {
+	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %s", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %s", err)
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %s", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cached of a decoded swagger spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	var res = make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSwagger returns the Swagger specification corresponding to the generated code
+// in this file. The external references of Swagger specification are resolved.
+// The logic of resolving external references is tightly connected to "import-mapping" feature.
+// Externally referenced files must be embedded in the corresponding golang packages.
+// Urls can be supported but this task was out of the scope.
+func GetSwagger() (swagger *openapi3.Swagger, err error) {
+	var resolvePath = PathToRawSpec("")
+
+	loader := openapi3.NewSwaggerLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.SwaggerLoader, url *url.URL) ([]byte, error) {
+		var pathToFile = url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadSwaggerFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
@@ -0,0 +1,100 @@
+// Package api provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/deepmap/oapi-codegen DO NOT EDIT.
+package api
+
+import (
+	"time"
+)
+
+// Defines values for LogLevelLevel.
+const (
+	LogLevelLevelDebug LogLevelLevel = "debug"
+
+	LogLevelLevelError LogLevelLevel = "error"
+
+	LogLevelLevelInfo LogLevelLevel = "info"
+)
+
+// IsdAs defines model for IsdAs.
+type IsdAs string
+
+// LogLevel defines model for LogLevel.
+type LogLevel struct {
+
+	// Logging level
+	Level LogLevelLevel `json:"level"`
+}
+
+// Logging level
+type LogLevelLevel string
+
+// Problem defines model for Problem.
+type Problem struct {
+
+	// A human readable explanation specific to this occurrence of the problem that is helpful to locate the problem and give advice on how to proceed. Written in English and readable for engineers, usually not suited for non technical stakeholders and not localized.
+	Detail *string `json:"detail,omitempty"`
+
+	// A URI reference that identifies the specific occurrence of the problem, e.g. by adding a fragment identifier or sub-path to the problem type. May be used to locate the root of this problem in the source code.
+	Instance *string `json:"instance,omitempty"`
+
+	// The HTTP status code generated by the origin server for this occurrence of the problem.
+	Status int `json:"status"`
+
+	// A short summary of the problem type. Written in English and readable for engineers, usually not suited for non technical stakeholders and not localized.
+	Title string `json:"title"`
+
+	// A URI reference that uniquely identifies the problem type only in the context of the provided API. Opposed to the specification in RFC-7807, it is neither recommended to be dereferencable and point to a human-readable documentation nor globally unique for the problem type.
+	Type *string `json:"type,omitempty"`
+}
+
+// Registration defines model for Registration.
+type Registration struct {
+
+	// Bind address of the SVC registration, if any.
+	Bind  *string        `json:"bind,omitempty"`
+	Id    RegistrationID `json:"id"`
+	IsdAs IsdAs          `json:"isd_as"`
+
+	// Public address of the registration in IP:port notation.
+	Public string `json:"public"`
+
+	// Time at which the application registered.
+	Registered time.Time         `json:"registered"`
+	Stats      RegistrationStats `json:"stats"`
+
+	// SVC address the application is registered for, if any.
+	Svc *string `json:"svc,omitempty"`
+}
+
+// RegistrationID defines model for RegistrationID.
+type RegistrationID int64
+
+// RegistrationStats defines model for RegistrationStats.
+type RegistrationStats struct {
+
+	// Number of packets delivered to the application.
+	DeliveredPackets int64 `json:"delivered_packets"`
+
+	// Number of packets dropped because the application's receive buffer was full.
+	DroppedPackets int64 `json:"dropped_packets"`
+
+	// Number of SCMP error messages delivered to the application.
+	ScmpErrors int64 `json:"scmp_errors"`
+}
+
+// StandardError defines model for StandardError.
+type StandardError struct {
+
+	// Error message
+	Error string `json:"error"`
+}
+
+// BadRequest defines model for BadRequest.
+type BadRequest StandardError
+
+// SetLogLevelJSONBody defines parameters for SetLogLevel.
+type SetLogLevelJSONBody LogLevel
+
+// SetLogLevelJSONRequestBody defines body for SetLogLevel for application/json ContentType.
+type SetLogLevelJSONRequestBody SetLogLevelJSONBody
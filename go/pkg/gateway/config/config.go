@@ -18,8 +18,11 @@ import (
 	"io"
 	"net"
 	"strconv"
+	"time"
 
 	"github.com/scionproto/scion/go/lib/config"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/util"
 )
 
 // Defaults.
@@ -31,6 +34,11 @@ const (
 
 	DefaultTunnelName           = "sig"
 	DefaultTunnelRoutingTableID = 11
+
+	DefaultRekeyInterval = time.Hour
+
+	DefaultBGPPort     = 179
+	DefaultBGPHoldTime = 90 * time.Second
 )
 
 // Gateway holds the gateway specific configuration.
@@ -47,6 +55,9 @@ type Gateway struct {
 	CtrlAddr string `toml:"ctrl_addr,omitempty"`
 	// Data plane address, for frames.
 	DataAddr string `toml:"data_addr,omitempty"`
+	// RekeyInterval is the interval in which the keys of encrypted tunnels are
+	// rotated.
+	RekeyInterval util.DurWrap `toml:"rekey_interval,omitempty"`
 }
 
 func (cfg *Gateway) Validate() error {
@@ -58,6 +69,9 @@ func (cfg *Gateway) Validate() error {
 	}
 	cfg.CtrlAddr = DefaultAddress(cfg.CtrlAddr, defaultCtrlPort)
 	cfg.DataAddr = DefaultAddress(cfg.DataAddr, defaultDataPort)
+	if cfg.RekeyInterval.Duration == 0 {
+		cfg.RekeyInterval.Duration = DefaultRekeyInterval
+	}
 	return nil
 }
 
@@ -96,6 +110,75 @@ func (cfg *Tunnel) ConfigName() string {
 	return "tunnel"
 }
 
+// BGP holds the configuration of the embedded BGP speaker.
+type BGP struct {
+	config.NoDefaulter
+
+	// LocalAS is the AS number of the BGP speaker. If zero, the BGP speaker is
+	// disabled.
+	LocalAS uint32 `toml:"local_as,omitempty"`
+	// RouterID is the BGP identifier of the speaker.
+	RouterID net.IP `toml:"router_id,omitempty"`
+	// ListenAddr is the address on which connections from neighbors are
+	// accepted. If empty, the speaker only initiates connections.
+	ListenAddr string `toml:"listen_addr,omitempty"`
+	// HoldTime is the hold time proposed to the neighbors.
+	HoldTime util.DurWrap `toml:"hold_time,omitempty"`
+	// NextHopIPv4 is the next hop announced for IPv4 prefixes. If not set, the
+	// local address of the BGP session is used.
+	NextHopIPv4 net.IP `toml:"next_hop_ipv4,omitempty"`
+	// NextHopIPv6 is the next hop announced for IPv6 prefixes. If not set, the
+	// local address of the BGP session is used.
+	NextHopIPv6 net.IP `toml:"next_hop_ipv6,omitempty"`
+	// Neighbors is the list of BGP neighbors.
+	Neighbors []BGPNeighbor `toml:"neighbors,omitempty"`
+}
+
+// BGPNeighbor is the configuration of a BGP neighbor.
+type BGPNeighbor struct {
+	// Address is the address of the neighbor.
+	Address string `toml:"address,omitempty"`
+	// AS is the AS number of the neighbor.
+	AS uint32 `toml:"as,omitempty"`
+	// Passive indicates that no connections are initiated to the neighbor.
+	Passive bool `toml:"passive,omitempty"`
+}
+
+// Enabled indicates whether the BGP speaker is enabled.
+func (cfg *BGP) Enabled() bool {
+	return cfg.LocalAS != 0
+}
+
+func (cfg *BGP) Validate() error {
+	if !cfg.Enabled() {
+		return nil
+	}
+	if cfg.RouterID.To4() == nil {
+		return serrors.New("router_id must be an IPv4 address", "router_id", cfg.RouterID)
+	}
+	if cfg.ListenAddr != "" {
+		cfg.ListenAddr = DefaultAddress(cfg.ListenAddr, DefaultBGPPort)
+	}
+	if cfg.HoldTime.Duration == 0 {
+		cfg.HoldTime.Duration = DefaultBGPHoldTime
+	}
+	for i, n := range cfg.Neighbors {
+		if n.Address == "" || n.AS == 0 {
+			return serrors.New("neighbor address and as must be set", "index", i)
+		}
+		cfg.Neighbors[i].Address = DefaultAddress(n.Address, DefaultBGPPort)
+	}
+	return nil
+}
+
+func (cfg *BGP) Sample(dst io.Writer, path config.Path, ctx config.CtxMap) {
+	config.WriteString(dst, bgpSample)
+}
+
+func (cfg *BGP) ConfigName() string {
+	return "bgp"
+}
+
 // DefaultAddress determines the default address. If port is not specified, or
 // is zero, it is set to the default port. If the input is garbage, the output
 // is garbage as well.
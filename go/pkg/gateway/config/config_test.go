@@ -16,10 +16,12 @@ package config_test
 
 import (
 	"bytes"
+	"net"
 	"testing"
 
 	"github.com/pelletier/go-toml"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/scionproto/scion/go/pkg/gateway/config"
 	"github.com/scionproto/scion/go/pkg/gateway/config/configtest"
@@ -47,6 +49,36 @@ func TestTunnelSample(t *testing.T) {
 	configtest.CheckTunnel(t, &cfg)
 }
 
+func TestBGPSample(t *testing.T) {
+	var sample bytes.Buffer
+	var cfg config.BGP
+	cfg.Sample(&sample, nil, nil)
+
+	configtest.InitBGP(&cfg)
+	err := toml.NewDecoder(bytes.NewReader(sample.Bytes())).Strict(true).Decode(&cfg)
+	assert.NoError(t, err)
+	configtest.CheckBGP(t, &cfg)
+}
+
+func TestBGPValidate(t *testing.T) {
+	cfg := config.BGP{
+		LocalAS:    65002,
+		RouterID:   net.ParseIP("192.0.2.100"),
+		ListenAddr: "127.0.0.1",
+		Neighbors:  []config.BGPNeighbor{{Address: "192.0.2.1", AS: 65001}},
+	}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, "127.0.0.1:179", cfg.ListenAddr)
+	assert.Equal(t, "192.0.2.1:179", cfg.Neighbors[0].Address)
+	assert.Equal(t, config.DefaultBGPHoldTime, cfg.HoldTime.Duration)
+
+	cfg.RouterID = net.ParseIP("2001:db8::1")
+	assert.Error(t, cfg.Validate())
+	cfg.RouterID = net.ParseIP("192.0.2.100")
+	cfg.Neighbors = append(cfg.Neighbors, config.BGPNeighbor{Address: "192.0.2.2"})
+	assert.Error(t, cfg.Validate())
+}
+
 func TestDefaultAddress(t *testing.T) {
 	testCases := map[string]struct {
 		Input    string
@@ -30,6 +30,7 @@ func CheckGateway(t *testing.T, cfg *config.Gateway) {
 	assert.Empty(t, cfg.IPRoutingPolicy)
 	assert.Equal(t, config.DefaultCtrlAddr, cfg.CtrlAddr)
 	assert.Equal(t, config.DefaultDataAddr, cfg.DataAddr)
+	assert.Equal(t, config.DefaultRekeyInterval, cfg.RekeyInterval.Duration)
 }
 
 func InitTunnel(cfg *config.Tunnel) {}
@@ -37,3 +38,11 @@ func InitTunnel(cfg *config.Tunnel) {}
 func CheckTunnel(t *testing.T, cfg *config.Tunnel) {
 	assert.Equal(t, config.DefaultTunnelName, cfg.Name)
 }
+
+func InitBGP(cfg *config.BGP) {}
+
+func CheckBGP(t *testing.T, cfg *config.BGP) {
+	assert.False(t, cfg.Enabled())
+	assert.Equal(t, config.DefaultBGPHoldTime, cfg.HoldTime.Duration)
+	assert.Equal(t, []config.BGPNeighbor{{Address: "192.0.2.1", AS: 65001}}, cfg.Neighbors)
+}
@@ -50,6 +50,12 @@ ctrl_addr = ":30256"
 #
 # (default ":30056")
 data_addr = ":30056"
+
+# The interval in which the keys of encrypted tunnels are rotated. Tunnels are
+# only encrypted towards remote ASes for which encryption is enabled in the
+# traffic policy. Both gateways of a tunnel must use the same interval.
+# (default "1h")
+rekey_interval = "1h"
 `
 
 const tunnelSample = `
@@ -62,3 +68,35 @@ src_ipv4 = "192.0.2.100"
 # (default "")
 src_ipv6 = "2001:db8::2:1"
 `
+
+const bgpSample = `
+# The AS number of the embedded BGP speaker. Prefixes learned from remote
+# gateways are announced to the BGP neighbors, and prefixes learned from the
+# BGP neighbors are advertised to remote gateways according to the
+# redistribute-bgp rules of the IP routing policy. If zero, the BGP speaker is
+# disabled. (default 0)
+local_as = 0
+
+# The BGP identifier of the speaker. It must be an IPv4 address. (default "")
+router_id = "192.0.2.100"
+
+# The address on which connections from BGP neighbors are accepted. If the port
+# is empty, or zero, the default port 179 is used. If empty, the speaker only
+# initiates connections. (default "")
+listen_addr = ""
+
+# The hold time proposed to the BGP neighbors. (default "90s")
+hold_time = "90s"
+
+# The next hops announced for IPv4 and IPv6 prefixes. If not set, the local
+# address of the BGP session is used. (default "")
+next_hop_ipv4 = "192.0.2.100"
+next_hop_ipv6 = "2001:db8::2:1"
+
+# The BGP neighbors. If the port of the address is empty, or zero, the default
+# port 179 is used. If passive is set, no connections are initiated to the
+# neighbor. (default [])
+neighbors = [
+    { address = "192.0.2.1", as = 65001, passive = false },
+]
+`
@@ -23,6 +23,7 @@ import (
 
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/pkg/gateway/routing"
 	"github.com/scionproto/scion/go/pkg/worker"
 )
 
@@ -42,12 +43,17 @@ type RemoteGateway struct {
 	Gateway Gateway
 	// Prefixes is the list of prefixes served by this gateway.
 	Prefixes []*net.IPNet
+	// Attributes are the attributes of the prefixes, keyed by the string
+	// representation of the prefix. Prefixes without an entry have no
+	// attributes.
+	Attributes map[string]routing.PrefixAttributes
 }
 
 type gatewayEntry struct {
 	IA          addr.IA
 	Gateway     Gateway
 	Prefixes    []*net.IPNet
+	Attributes  map[string]routing.PrefixAttributes
 	LastUpdated time.Time
 }
 
@@ -57,7 +63,9 @@ type Aggregator struct {
 	// RoutingUpdateChan is the channel that the routing updates will be pushed to.
 	RoutingUpdateChan chan (RemoteGateways)
 	// ReportingInterval is the interval between producing the reports. If there
-	// are no changes, the actual interval may be longer.
+	// are no changes, the actual interval may be longer. Withdrawals are
+	// reported immediately, such that no traffic is sent to a gateway that no
+	// longer serves a prefix.
 	ReportingInterval time.Duration
 	// ExpiryInterval means for how long will a gateway instance be reported if it
 	// is not renewed.
@@ -66,6 +74,8 @@ type Aggregator struct {
 	mutex    sync.Mutex
 	gateways map[string]gatewayEntry
 	changed  bool
+	// flush is notified if a report should be produced immediately.
+	flush chan struct{}
 
 	workerBase worker.Base
 }
@@ -85,23 +95,33 @@ func (a *Aggregator) setup() error {
 	if a.ExpiryInterval == 0 {
 		a.ExpiryInterval = defaultExpiryInterval
 	}
+	a.init()
+	return nil
+}
+
+// init initializes the internal state. The caller must hold the mutex.
+func (a *Aggregator) init() {
 	if a.gateways == nil {
 		a.gateways = make(map[string]gatewayEntry)
 	}
-
-	return nil
+	if a.flush == nil {
+		a.flush = make(chan struct{}, 1)
+	}
 }
 
 func (a *Aggregator) run() error {
 	go func() {
 		defer log.HandlePanic()
 		ticker := time.NewTicker(a.ReportingInterval)
+		defer ticker.Stop()
 		for {
 			select {
 			case <-ticker.C:
 				a.report()
+			case <-a.flush:
+				a.report()
 			case <-a.workerBase.GetDoneChan():
-				break
+				return
 			}
 		}
 	}()
@@ -114,23 +134,46 @@ func (a *Aggregator) Close() {
 }
 
 // Prefixes pushes new set of prefixes for a specific gateway.
-func (a *Aggregator) Prefixes(remote addr.IA, gateway Gateway, prefixes []*net.IPNet) {
+func (a *Aggregator) Prefixes(remote addr.IA, gateway Gateway, prefixes []*net.IPNet,
+	attributes map[string]routing.PrefixAttributes) {
+
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
-	if a.gateways == nil {
-		a.gateways = make(map[string]gatewayEntry)
-	}
+	a.init()
 	key := fmt.Sprintf("%s/%s", remote.String(), gateway.Control.String())
+	if prev, ok := a.gateways[key]; ok && withdrawn(prev.Prefixes, prefixes) {
+		select {
+		case a.flush <- struct{}{}:
+		default:
+			// A flush is already pending.
+		}
+	}
 	a.gateways[key] = gatewayEntry{
 		IA:          remote,
 		Gateway:     gateway,
 		Prefixes:    prefixes,
+		Attributes:  attributes,
 		LastUpdated: time.Now(),
 	}
 	a.changed = true
 }
 
+// withdrawn indicates whether any of the previous prefixes is missing in the
+// current prefixes.
+func withdrawn(previous, current []*net.IPNet) bool {
+	keys := make(map[string]struct{}, len(current))
+	for _, prefix := range current {
+		keys[prefix.String()] = struct{}{}
+	}
+	for _, prefix := range previous {
+		if _, ok := keys[prefix.String()]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *Aggregator) report() {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
@@ -157,8 +200,9 @@ func (a *Aggregator) report() {
 	for _, key := range keys {
 		entry := a.gateways[key]
 		ru.Gateways[entry.IA] = append(ru.Gateways[entry.IA], RemoteGateway{
-			Gateway:  entry.Gateway,
-			Prefixes: entry.Prefixes,
+			Gateway:    entry.Gateway,
+			Prefixes:   entry.Prefixes,
+			Attributes: entry.Attributes,
 		})
 	}
 	select {
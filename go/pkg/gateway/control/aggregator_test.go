@@ -52,14 +52,14 @@ func TestAggregator(t *testing.T) {
 	}
 
 	// Test adding prefixes before run is called.
-	a.Prefixes(ia1, gateway1, []*net.IPNet{prefix1, prefix2})
+	a.Prefixes(ia1, gateway1, []*net.IPNet{prefix1, prefix2}, nil)
 
 	err := a.Run()
 	require.NoError(t, err)
 
 	// Test adding some more prefixes.
-	a.Prefixes(ia2, gateway2, []*net.IPNet{prefix3})
-	a.Prefixes(ia1, gateway3, []*net.IPNet{})
+	a.Prefixes(ia2, gateway2, []*net.IPNet{prefix3}, nil)
+	a.Prefixes(ia1, gateway3, []*net.IPNet{}, nil)
 	ru := <-updateChan
 	expected := control.RemoteGateways{
 		Gateways: map[addr.IA][]control.RemoteGateway{
@@ -84,7 +84,7 @@ func TestAggregator(t *testing.T) {
 	require.Equal(t, expected, ru)
 
 	// Test updating prefixes for one Gateway.
-	a.Prefixes(ia1, gateway1, []*net.IPNet{prefix1})
+	a.Prefixes(ia1, gateway1, []*net.IPNet{prefix1}, nil)
 	ru = <-updateChan
 	expected = control.RemoteGateways{
 		Gateways: map[addr.IA][]control.RemoteGateway{
@@ -123,3 +123,45 @@ func TestAggregator(t *testing.T) {
 
 	a.Close()
 }
+
+func TestAggregatorWithdrawal(t *testing.T) {
+	prefix1 := xtest.MustParseCIDR(t, "192.168.0.0/24")
+	prefix2 := xtest.MustParseCIDR(t, "192.168.100.128/25")
+
+	updateChan := make(chan (control.RemoteGateways), 10)
+	a := control.Aggregator{
+		RoutingUpdateChan: updateChan,
+		// Only withdrawals are reported within the test.
+		ReportingInterval: time.Hour,
+		ExpiryInterval:    time.Hour,
+	}
+	require.NoError(t, a.Run())
+	defer a.Close()
+
+	// Announcements wait for the next report.
+	a.Prefixes(ia1, gateway1, []*net.IPNet{prefix1, prefix2}, nil)
+	a.Prefixes(ia1, gateway1, []*net.IPNet{prefix1, prefix2}, nil)
+	select {
+	case ru := <-updateChan:
+		t.Fatalf("unexpected report: %v", ru)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Withdrawals are reported immediately.
+	a.Prefixes(ia1, gateway1, []*net.IPNet{prefix1}, nil)
+	select {
+	case ru := <-updateChan:
+		require.Equal(t, control.RemoteGateways{
+			Gateways: map[addr.IA][]control.RemoteGateway{
+				ia1: {
+					control.RemoteGateway{
+						Gateway:  gateway1,
+						Prefixes: []*net.IPNet{prefix1},
+					},
+				},
+			},
+		}, ru)
+	case <-time.After(time.Second):
+		t.Fatal("withdrawal not reported")
+	}
+}
@@ -33,6 +33,8 @@ type ConfigPublisher struct {
 
 	sessionPoliciesSubscribers []chan SessionPolicies
 	remoteIAsSubscribers       []chan []addr.IA
+	// routingPolicyChanged is closed when a new routing policy is published.
+	routingPolicyChanged chan struct{}
 }
 
 // Publish notifies clients of the Publisher about new configurations. Nil
@@ -66,6 +68,10 @@ func (n *ConfigPublisher) Publish(sp SessionPolicies, rp *routing.Policy) {
 	}
 	if rp != nil {
 		n.routingPolicy = rp.Copy()
+		if n.routingPolicyChanged != nil {
+			close(n.routingPolicyChanged)
+			n.routingPolicyChanged = nil
+		}
 	}
 	wg.Wait()
 }
@@ -102,6 +108,18 @@ func (n *ConfigPublisher) SubscribeRemoteIAs() <-chan []addr.IA {
 	return c
 }
 
+// RoutingPolicyChanges returns a channel that is closed the next time a routing
+// policy is published. Every call after a publish returns a new channel.
+func (n *ConfigPublisher) RoutingPolicyChanges() <-chan struct{} {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if n.routingPolicyChanged == nil {
+		n.routingPolicyChanged = make(chan struct{})
+	}
+	return n.routingPolicyChanged
+}
+
 // RoutingPolicy returns the last routing policy that was published.
 // The returned object is a deep-copy, and can be edited by the caller.
 func (n *ConfigPublisher) RoutingPolicy() *routing.Policy {
@@ -105,6 +105,19 @@ func TestConfigPublisherPublish(t *testing.T) {
 			}()
 			xtest.AssertReadReturnsBefore(t, doneCh, time.Second)
 		},
+		"publish routing pol notifies changes": func(t *testing.T) {
+			n := control.ConfigPublisher{}
+			changes := n.RoutingPolicyChanges()
+			n.Publish(expectedSP, nil)
+			select {
+			case <-changes:
+				t.Fatal("session policy publish must not signal routing policy change")
+			default:
+			}
+			n.Publish(nil, &routing.Policy{DefaultAction: routing.Reject})
+			xtest.AssertReadReturnsBefore(t, changes, time.Second)
+			assert.NotEqual(t, changes, n.RoutingPolicyChanges())
+		},
 	}
 	for name, tc := range testCases {
 		t.Run(name, tc)
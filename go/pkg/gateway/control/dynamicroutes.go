@@ -0,0 +1,105 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package control
+
+import (
+	"bytes"
+	"net"
+	"sort"
+	"sync"
+
+	"github.com/scionproto/scion/go/lib/routemgr"
+)
+
+// DynamicRoutes keeps track of the IP prefixes of the routes received from a
+// route consumer, e.g., the routes learned from local BGP neighbors.
+type DynamicRoutes struct {
+	mtx      sync.Mutex
+	prefixes map[string]*dynamicPrefix
+	changed  chan struct{}
+}
+
+type dynamicPrefix struct {
+	prefix   *net.IPNet
+	refCount int
+}
+
+// Run processes the route updates of the consumer until its update channel is
+// closed.
+func (r *DynamicRoutes) Run(consumer routemgr.Consumer) {
+	for update := range consumer.Updates() {
+		r.update(update)
+	}
+}
+
+func (r *DynamicRoutes) update(update routemgr.RouteUpdate) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.prefixes == nil {
+		r.prefixes = make(map[string]*dynamicPrefix)
+	}
+	// Multiple routes with different next hops can exist for the same prefix.
+	key := update.Prefix.String()
+	p, ok := r.prefixes[key]
+	switch {
+	case update.IsAdd && ok:
+		p.refCount++
+		return
+	case update.IsAdd:
+		r.prefixes[key] = &dynamicPrefix{prefix: update.Prefix, refCount: 1}
+	case !ok:
+		return
+	default:
+		p.refCount--
+		if p.refCount > 0 {
+			return
+		}
+		delete(r.prefixes, key)
+	}
+	if r.changed != nil {
+		close(r.changed)
+		r.changed = nil
+	}
+}
+
+// Prefixes returns the sorted list of the current prefixes.
+func (r *DynamicRoutes) Prefixes() []*net.IPNet {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	prefixes := make([]*net.IPNet, 0, len(r.prefixes))
+	for _, p := range r.prefixes {
+		prefixes = append(prefixes, p.prefix)
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		if c := bytes.Compare(prefixes[i].IP.To16(), prefixes[j].IP.To16()); c != 0 {
+			return c < 0
+		}
+		a, _ := prefixes[i].Mask.Size()
+		b, _ := prefixes[j].Mask.Size()
+		return a < b
+	})
+	return prefixes
+}
+
+// Changes returns a channel that is closed the next time the set of prefixes
+// changes.
+func (r *DynamicRoutes) Changes() <-chan struct{} {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.changed == nil {
+		r.changed = make(chan struct{})
+	}
+	return r.changed
+}
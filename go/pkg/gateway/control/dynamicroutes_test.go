@@ -0,0 +1,73 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package control_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/routemgr"
+	"github.com/scionproto/scion/go/lib/routemgr/mock_routemgr"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/pkg/gateway/control"
+)
+
+func TestDynamicRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prefix1 := xtest.MustParseCIDR(t, "10.1.0.0/16")
+	prefix2 := xtest.MustParseCIDR(t, "10.0.0.0/16")
+	updates := make(chan routemgr.RouteUpdate)
+	consumer := mock_routemgr.NewMockConsumer(ctrl)
+	consumer.EXPECT().Updates().Return(updates).AnyTimes()
+
+	r := &control.DynamicRoutes{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.Run(consumer)
+	}()
+
+	changes := r.Changes()
+	updates <- routemgr.RouteUpdate{IsAdd: true, Route: routemgr.Route{Prefix: prefix1}}
+	xtest.AssertReadReturnsBefore(t, changes, time.Second)
+	changes = r.Changes()
+	updates <- routemgr.RouteUpdate{IsAdd: true, Route: routemgr.Route{Prefix: prefix2}}
+	xtest.AssertReadReturnsBefore(t, changes, time.Second)
+	assert.Equal(t, []*net.IPNet{prefix2, prefix1}, r.Prefixes())
+
+	// A second route for the same prefix does not change the set of prefixes.
+	changes = r.Changes()
+	route := routemgr.Route{Prefix: prefix1, NextHop: net.ParseIP("192.0.2.1")}
+	updates <- routemgr.RouteUpdate{IsAdd: true, Route: route}
+	updates <- routemgr.RouteUpdate{IsAdd: false, Route: route}
+	select {
+	case <-changes:
+		t.Fatal("unexpected change")
+	default:
+	}
+
+	updates <- routemgr.RouteUpdate{IsAdd: false, Route: routemgr.Route{Prefix: prefix1}}
+	xtest.AssertReadReturnsBefore(t, changes, time.Second)
+	assert.Equal(t, []*net.IPNet{prefix2}, r.Prefixes())
+
+	close(updates)
+	xtest.AssertReadReturnsBefore(t, done, time.Second)
+}
@@ -284,12 +284,13 @@ func (e *Engine) initWorkers() error {
 
 	for _, config := range e.SessionConfigs {
 		dataplaneSession := e.DataplaneSessionFactory.New(config.ID, config.PolicyID,
-			config.IA, config.Gateway.Data)
+			config.IA, config.Gateway.Data, config.Encryption, config.Multipath)
 		remoteIA := config.IA
 		pathMonitorRegistration := e.PathMonitor.Register(remoteIA, &policies.Policies{
 			PathPolicy: config.PathPolicy,
 			PerfPolicy: config.PerfPolicy,
 			PathCount:  config.PathCount,
+			Disjoint:   config.Multipath == MultipathRedundant,
 		}, config.PolicyID)
 		probeConn, err := e.ProbeConnFactory.New()
 		if err != nil {
@@ -417,9 +418,11 @@ type PktWriter interface {
 }
 
 // DataplaneSessionFactory is used to construct a data-plane session with a specific ID towards a
-// remote.
+// remote. If encrypt is set, the frames sent in the session are encrypted. The
+// multipath mode defines how the traffic is distributed among the paths.
 type DataplaneSessionFactory interface {
-	New(sessID uint8, policyID int, remoteIA addr.IA, remoteAddr net.Addr) DataplaneSession
+	New(sessID uint8, policyID int, remoteIA addr.IA, remoteAddr net.Addr,
+		encrypt bool, multipath MultipathMode) DataplaneSession
 }
 
 // PathMonitor is used to construct registrations for path discovery.
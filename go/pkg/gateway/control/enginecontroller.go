@@ -252,14 +252,23 @@ type Worker interface {
 	Close() error
 }
 
-type gatewaySet map[string]struct{}
-
+// gatewaySet is a set of gateways that serve a prefix. It maps the control
+// address of every gateway to the metric the gateway announced for the prefix.
+type gatewaySet map[string]uint32
+
+// buildRoutingChains builds the routing chains for the session configurations.
+// If multiple sessions serve the same prefixes, the sessions are ordered by the
+// metric their remote gateway announced for the prefixes, with the lowest
+// metric first.
 func buildRoutingChains(sessionConfigs []*SessionConfig) ([]*RoutingChain, map[int][]uint8) {
 	if len(sessionConfigs) == 0 {
 		return nil, nil
 	}
 	routingChains := []*RoutingChain{}
 	sessionMap := make(map[int][]uint8)
+	// sessionMetrics maps the traffic matcher ID and the session ID to the
+	// metric of the prefixes served by the session.
+	sessionMetrics := make(map[int]map[uint8]uint32)
 	trafficMatcherID := 1
 
 	// first we group by IA:
@@ -306,9 +315,19 @@ func buildRoutingChains(sessionConfigs []*SessionConfig) ([]*RoutingChain, map[i
 					trafficMatcherID++
 				}
 				sessionMap[tmID] = nonDuplicateAppendID(sessionMap[tmID], sc.ID)
+				if sessionMetrics[tmID] == nil {
+					sessionMetrics[tmID] = make(map[uint8]uint32)
+				}
+				sessionMetrics[tmID][sc.ID] = sc.PrefixAttributes[prefix.String()].Metric
 			}
 		}
 	}
+	for tmID, ids := range sessionMap {
+		tmMetrics := sessionMetrics[tmID]
+		sort.SliceStable(ids, func(i, j int) bool {
+			return tmMetrics[ids[i]] < tmMetrics[ids[j]]
+		})
+	}
 	return routingChains, sessionMap
 }
 
@@ -320,7 +339,8 @@ func buildPrefixToGatewayMapping(iaSessions []*SessionConfig) map[string]gateway
 			if _, ok := prefixToGWs[prefixKey]; !ok {
 				prefixToGWs[prefixKey] = make(gatewaySet)
 			}
-			prefixToGWs[prefixKey][sc.Gateway.Control.String()] = struct{}{}
+			prefixToGWs[prefixKey][sc.Gateway.Control.String()] =
+				sc.PrefixAttributes[prefixKey].Metric
 		}
 	}
 	return prefixToGWs
@@ -397,12 +417,12 @@ func nonDuplicateAppendID(ids []uint8, add uint8) []uint8 {
 	return append(ids, add)
 }
 
-func equalSet(a, b map[string]struct{}) bool {
+func equalSet(a, b gatewaySet) bool {
 	if len(a) != len(b) {
 		return false
 	}
-	for k := range a {
-		if _, ok := b[k]; !ok {
+	for k, metricA := range a {
+		if metricB, ok := b[k]; !ok || metricA != metricB {
 			return false
 		}
 	}
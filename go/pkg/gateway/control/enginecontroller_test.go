@@ -26,6 +26,7 @@ import (
 	"github.com/scionproto/scion/go/lib/xtest"
 	"github.com/scionproto/scion/go/pkg/gateway/control"
 	"github.com/scionproto/scion/go/pkg/gateway/control/mock_control"
+	"github.com/scionproto/scion/go/pkg/gateway/routing"
 )
 
 func TestEngineControllerRun(t *testing.T) {
@@ -536,6 +537,50 @@ func TestBuildRoutingChains(t *testing.T) {
 				2: {42},
 			},
 		},
+		"sessions ordered by metric": {
+			Input: []*control.SessionConfig{
+				{
+					ID:             10,
+					PolicyID:       0,
+					IA:             xtest.MustParseIA("1-ff00:0:110"),
+					TrafficMatcher: pktcls.CondTrue,
+					PerfPolicy:     control.DefaultPerfPolicy,
+					PathPolicy:     control.DefaultPathPolicy,
+					Gateway: control.Gateway{
+						Control: xtest.MustParseUDPAddr(t, "10.1.0.1:30256"),
+					},
+					Prefixes: xtest.MustParseCIDRs(t, "10.99.0.0/16"),
+					PrefixAttributes: map[string]routing.PrefixAttributes{
+						"10.99.0.0/16": {Metric: 20},
+					},
+				},
+				{
+					ID:             11,
+					PolicyID:       0,
+					IA:             xtest.MustParseIA("1-ff00:0:110"),
+					TrafficMatcher: pktcls.CondTrue,
+					PerfPolicy:     control.DefaultPerfPolicy,
+					PathPolicy:     control.DefaultPathPolicy,
+					Gateway: control.Gateway{
+						Control: xtest.MustParseUDPAddr(t, "10.1.0.2:30256"),
+					},
+					Prefixes: xtest.MustParseCIDRs(t, "10.99.0.0/16"),
+					PrefixAttributes: map[string]routing.PrefixAttributes{
+						"10.99.0.0/16": {Metric: 5},
+					},
+				},
+			},
+			Chains: []*control.RoutingChain{
+				{
+					RemoteIA:        xtest.MustParseIA("1-ff00:0:110"),
+					Prefixes:        xtest.MustParseCIDRs(t, "10.99.0.0/16"),
+					TrafficMatchers: []control.TrafficMatcher{{ID: 1, Matcher: pktcls.CondTrue}},
+				},
+			},
+			SessionMapping: map[int][]uint8{
+				1: {11, 10},
+			},
+		},
 	}
 	for name, tc := range testCases {
 		name, tc := name, tc
@@ -76,7 +76,8 @@ func (g *Gateway) Run() error {
 		newSessions := make(map[int]control.DataplaneSession, len(c.Sessions))
 		for _, s := range c.Sessions {
 			newSessions[s.ID] = g.DataplaneSessionFactory.
-				New(uint8(s.ID), s.PolicyID, s.RemoteIA, s.RemoteAddr)
+				New(uint8(s.ID), s.PolicyID, s.RemoteIA, s.RemoteAddr, false,
+					control.MultipathLoadBalance)
 			if err := newSessions[s.ID].SetPaths(s.Paths); err != nil {
 				return err
 			}
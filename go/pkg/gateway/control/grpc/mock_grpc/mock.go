@@ -7,6 +7,7 @@ package mock_grpc
 import (
 	gomock "github.com/golang/mock/gomock"
 	addr "github.com/scionproto/scion/go/lib/addr"
+	routing "github.com/scionproto/scion/go/pkg/gateway/routing"
 	net "net"
 	reflect "reflect"
 )
@@ -47,3 +48,31 @@ func (mr *MockAdvertiserMockRecorder) AdvertiseList(arg0, arg1 interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvertiseList", reflect.TypeOf((*MockAdvertiser)(nil).AdvertiseList), arg0, arg1)
 }
+
+// Advertisements mocks base method
+func (m *MockAdvertiser) Advertisements(arg0, arg1 addr.IA) []routing.Advertisement {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Advertisements", arg0, arg1)
+	ret0, _ := ret[0].([]routing.Advertisement)
+	return ret0
+}
+
+// Advertisements indicates an expected call of Advertisements
+func (mr *MockAdvertiserMockRecorder) Advertisements(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Advertisements", reflect.TypeOf((*MockAdvertiser)(nil).Advertisements), arg0, arg1)
+}
+
+// Changes mocks base method
+func (m *MockAdvertiser) Changes() <-chan struct{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Changes")
+	ret0, _ := ret[0].(<-chan struct{})
+	return ret0
+}
+
+// Changes indicates an expected call of Changes
+func (mr *MockAdvertiserMockRecorder) Changes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Changes", reflect.TypeOf((*MockAdvertiser)(nil).Changes))
+}
@@ -16,12 +16,17 @@ package grpc
 
 import (
 	"context"
+	"io"
 	"net"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/snet"
 	"github.com/scionproto/scion/go/pkg/gateway/control"
+	"github.com/scionproto/scion/go/pkg/gateway/routing"
 	"github.com/scionproto/scion/go/pkg/grpc"
 	gpb "github.com/scionproto/scion/go/pkg/proto/gateway"
 )
@@ -34,35 +39,109 @@ type PrefixFetcher struct {
 }
 
 func (f PrefixFetcher) Prefixes(ctx context.Context, gateway *net.UDPAddr) ([]*net.IPNet, error) {
-	paths := f.Pather.Get().Paths
-	if len(paths) == 0 {
-		return nil, serrors.New("no path available")
-	}
-	conn, err := f.Dialer.Dial(ctx, &snet.UDPAddr{
-		IA:      f.Remote,
-		Path:    paths[0].Path(),
-		NextHop: paths[0].UnderlayNextHop(),
-		Host:    gateway,
-	})
+	client, conn, err := f.dial(ctx, gateway)
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Close()
-	client := gpb.NewIPPrefixesServiceClient(conn)
 	rep, err := client.Prefixes(ctx, &gpb.PrefixesRequest{}, grpc.RetryProfile...)
 	if err != nil {
 		return nil, serrors.WrapStr("receiving IP prefixes", err)
 	}
 	prefixes := make([]*net.IPNet, 0, len(rep.Prefixes))
 	for _, pb := range rep.Prefixes {
-		mask := net.CIDRMask(int(pb.Mask), len(pb.Prefix)*8)
-		if mask == nil {
-			continue
+		if prefix := prefixFromPB(pb); prefix != nil {
+			prefixes = append(prefixes, prefix)
 		}
-		prefixes = append(prefixes, &net.IPNet{
-			IP:   pb.Prefix,
-			Mask: mask,
-		})
 	}
 	return prefixes, nil
 }
+
+// StreamPrefixes streams the IP prefix updates from the remote gateway. If the
+// remote gateway does not implement streaming, control.ErrStreamingUnsupported
+// is returned.
+func (f PrefixFetcher) StreamPrefixes(ctx context.Context, gateway *net.UDPAddr,
+	handler func(control.PrefixUpdate)) error {
+
+	client, conn, err := f.dial(ctx, gateway)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stream, err := client.PrefixesStream(ctx, &gpb.PrefixesStreamRequest{})
+	if err != nil {
+		return streamError(err)
+	}
+	for {
+		rep, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return streamError(err)
+		}
+		update := control.PrefixUpdate{
+			Full:       rep.Full,
+			Attributes: make(map[string]routing.PrefixAttributes),
+		}
+		for _, pb := range rep.Announced {
+			prefix := prefixFromPB(pb)
+			if prefix == nil {
+				continue
+			}
+			update.Announced = append(update.Announced, prefix)
+			if pb.Attributes != nil {
+				update.Attributes[prefix.String()] = routing.PrefixAttributes{
+					Metric: pb.Attributes.Metric,
+					Tags:   pb.Attributes.Tags,
+				}
+			}
+		}
+		for _, pb := range rep.Withdrawn {
+			if prefix := prefixFromPB(pb); prefix != nil {
+				update.Withdrawn = append(update.Withdrawn, prefix)
+			}
+		}
+		handler(update)
+	}
+}
+
+// dial dials the remote gateway. The returned closer must be closed by the
+// caller.
+func (f PrefixFetcher) dial(ctx context.Context,
+	gateway *net.UDPAddr) (gpb.IPPrefixesServiceClient, io.Closer, error) {
+
+	paths := f.Pather.Get().Paths
+	if len(paths) == 0 {
+		return nil, nil, serrors.New("no path available")
+	}
+	conn, err := f.Dialer.Dial(ctx, &snet.UDPAddr{
+		IA:      f.Remote,
+		Path:    paths[0].Path(),
+		NextHop: paths[0].UnderlayNextHop(),
+		Host:    gateway,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return gpb.NewIPPrefixesServiceClient(conn), conn, nil
+}
+
+func streamError(err error) error {
+	if status.Code(err) == codes.Unimplemented {
+		return control.ErrStreamingUnsupported
+	}
+	return serrors.WrapStr("receiving IP prefix updates", err)
+}
+
+// prefixFromPB parses the prefix. If the prefix is invalid, nil is returned.
+func prefixFromPB(pb *gpb.Prefix) *net.IPNet {
+	mask := net.CIDRMask(int(pb.Mask), len(pb.Prefix)*8)
+	if mask == nil {
+		return nil
+	}
+	return &net.IPNet{
+		IP:   pb.Prefix,
+		Mask: mask,
+	}
+}
@@ -25,12 +25,19 @@ import (
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/metrics"
 	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/pkg/gateway/routing"
 	gpb "github.com/scionproto/scion/go/pkg/proto/gateway"
 )
 
 // Advertiser returns a list of IP prefixes to advertise.
 type Advertiser interface {
 	AdvertiseList(from, to addr.IA) []*net.IPNet
+	// Advertisements returns the IP prefixes to advertise together with their
+	// attributes.
+	Advertisements(from, to addr.IA) []routing.Advertisement
+	// Changes returns a channel that is closed when the advertised prefixes
+	// might have changed.
+	Changes() <-chan struct{}
 }
 
 // IPPrefixServer serves IP prefix requests.
@@ -47,34 +54,115 @@ type IPPrefixServer struct {
 func (s IPPrefixServer) Prefixes(ctx context.Context,
 	req *gpb.PrefixesRequest) (*gpb.PrefixesResponse, error) {
 
-	remote, ok := peer.FromContext(ctx)
-	if !ok {
-		return nil, status.Error(codes.InvalidArgument, "peer required")
-	}
-	udp, ok := remote.Addr.(*snet.UDPAddr)
-	if !ok {
-		return nil, status.Error(codes.InvalidArgument, "SCION peer required")
+	remoteIA, err := peerIA(ctx)
+	if err != nil {
+		return nil, err
 	}
-	prefixes := s.Advertiser.AdvertiseList(s.LocalIA, udp.IA)
+	prefixes := s.Advertiser.AdvertiseList(s.LocalIA, remoteIA)
 	metrics.GaugeSet(metrics.GaugeWith(s.PrefixesAdvertised,
-		"remote_isd_as", udp.IA.String()), float64(len(prefixes)))
+		"remote_isd_as", remoteIA.String()), float64(len(prefixes)))
 
 	pb := make([]*gpb.Prefix, 0, len(prefixes))
 	for _, prefix := range prefixes {
-		ones, bits := prefix.Mask.Size()
-		if bits == 0 {
-			continue
+		if p := prefixToPB(prefix); p != nil {
+			pb = append(pb, p)
 		}
-		pb = append(pb, &gpb.Prefix{
-			Prefix: canonicalIP(prefix.IP),
-			Mask:   uint32(ones),
-		})
 	}
 	return &gpb.PrefixesResponse{
 		Prefixes: pb,
 	}, nil
 }
 
+// PrefixesStream streams the IP prefixes advertised to the remote. The first
+// update contains all advertised prefixes. Afterwards, an update is sent
+// whenever the advertised prefixes or their attributes change.
+func (s IPPrefixServer) PrefixesStream(req *gpb.PrefixesStreamRequest,
+	stream gpb.IPPrefixesService_PrefixesStreamServer) error {
+
+	remoteIA, err := peerIA(stream.Context())
+	if err != nil {
+		return err
+	}
+	var sent map[string]routing.Advertisement
+	for {
+		// Get the change notification before computing the advertisements,
+		// such that no change is missed.
+		changes := s.Advertiser.Changes()
+		current := make(map[string]routing.Advertisement)
+		for _, a := range s.Advertiser.Advertisements(s.LocalIA, remoteIA) {
+			if prefixToPB(a.Prefix) == nil {
+				continue
+			}
+			current[a.Prefix.String()] = a
+		}
+		update := diffAdvertisements(sent, current)
+		if sent == nil || len(update.Announced) != 0 || len(update.Withdrawn) != 0 {
+			metrics.GaugeSet(metrics.GaugeWith(s.PrefixesAdvertised,
+				"remote_isd_as", remoteIA.String()), float64(len(current)))
+			if err := stream.Send(update); err != nil {
+				return err
+			}
+		}
+		sent = current
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-changes:
+		}
+	}
+}
+
+// diffAdvertisements computes the update from the previously sent to the
+// current advertisements. If nothing was sent before, a full update is
+// returned.
+func diffAdvertisements(sent, current map[string]routing.Advertisement) *gpb.PrefixesUpdate {
+	update := &gpb.PrefixesUpdate{Full: sent == nil}
+	for key, a := range current {
+		if prev, ok := sent[key]; ok && prev.Attributes.Equal(a.Attributes) {
+			continue
+		}
+		p := prefixToPB(a.Prefix)
+		if !a.Attributes.IsZero() {
+			p.Attributes = &gpb.PrefixAttributes{
+				Metric: a.Attributes.Metric,
+				Tags:   a.Attributes.Tags,
+			}
+		}
+		update.Announced = append(update.Announced, p)
+	}
+	for key, a := range sent {
+		if _, ok := current[key]; !ok {
+			update.Withdrawn = append(update.Withdrawn, prefixToPB(a.Prefix))
+		}
+	}
+	return update
+}
+
+func peerIA(ctx context.Context) (addr.IA, error) {
+	remote, ok := peer.FromContext(ctx)
+	if !ok {
+		return addr.IA{}, status.Error(codes.InvalidArgument, "peer required")
+	}
+	udp, ok := remote.Addr.(*snet.UDPAddr)
+	if !ok {
+		return addr.IA{}, status.Error(codes.InvalidArgument, "SCION peer required")
+	}
+	return udp.IA, nil
+}
+
+// prefixToPB converts the prefix to its protobuf representation. If the prefix
+// is invalid, nil is returned.
+func prefixToPB(prefix *net.IPNet) *gpb.Prefix {
+	ones, bits := prefix.Mask.Size()
+	if bits == 0 {
+		return nil
+	}
+	return &gpb.Prefix{
+		Prefix: canonicalIP(prefix.IP),
+		Mask:   uint32(ones),
+	}
+}
+
 func canonicalIP(ip net.IP) net.IP {
 	if v4 := ip.To4(); v4 != nil {
 		return v4
@@ -29,6 +29,7 @@ import (
 	"github.com/scionproto/scion/go/lib/xtest"
 	"github.com/scionproto/scion/go/pkg/gateway/control/grpc"
 	"github.com/scionproto/scion/go/pkg/gateway/control/grpc/mock_grpc"
+	"github.com/scionproto/scion/go/pkg/gateway/routing"
 	gpb "github.com/scionproto/scion/go/pkg/proto/gateway"
 )
 
@@ -150,3 +151,109 @@ func networksList(t *testing.T, networks string) []*net.IPNet {
 	}
 	return prefixes
 }
+
+func TestIPPrefixServerPrefixesStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	local := xtest.MustParseIA("1-ff00:0:110")
+	remote := xtest.MustParseIA("1-ff00:0:111")
+
+	changed, unchanged := make(chan struct{}), make(chan struct{})
+	close(changed)
+	a := mock_grpc.NewMockAdvertiser(ctrl)
+	gomock.InOrder(
+		a.EXPECT().Changes().Return(changed),
+		a.EXPECT().Advertisements(local, remote).Return([]routing.Advertisement{
+			{Prefix: xtest.MustParseCIDR(t, "127.0.0.0/24")},
+			{
+				Prefix:     xtest.MustParseCIDR(t, "127.0.1.0/24"),
+				Attributes: routing.PrefixAttributes{Metric: 1},
+			},
+		}),
+		// Spurious change notification does not result in an update.
+		a.EXPECT().Changes().Return(changed),
+		a.EXPECT().Advertisements(local, remote).Return([]routing.Advertisement{
+			{Prefix: xtest.MustParseCIDR(t, "127.0.0.0/24")},
+			{
+				Prefix:     xtest.MustParseCIDR(t, "127.0.1.0/24"),
+				Attributes: routing.PrefixAttributes{Metric: 1},
+			},
+		}),
+		a.EXPECT().Changes().Return(unchanged),
+		a.EXPECT().Advertisements(local, remote).Return([]routing.Advertisement{
+			{
+				Prefix:     xtest.MustParseCIDR(t, "127.0.1.0/24"),
+				Attributes: routing.PrefixAttributes{Metric: 2, Tags: []string{"backup"}},
+			},
+			{Prefix: xtest.MustParseCIDR(t, "::/64")},
+		}),
+	)
+
+	ctx, cancel := context.WithCancel(peer.NewContext(context.Background(),
+		&peer.Peer{Addr: &snet.UDPAddr{IA: remote}},
+	))
+	defer cancel()
+	stream := &fakePrefixesStream{ctx: ctx}
+	// Cancel the stream after the second update has been sent.
+	stream.onSend = func() {
+		if len(stream.updates) == 2 {
+			cancel()
+		}
+	}
+	s := grpc.IPPrefixServer{
+		LocalIA:    local,
+		Advertiser: a,
+	}
+	err := s.PrefixesStream(&gpb.PrefixesStreamRequest{}, stream)
+	require.NoError(t, err)
+	require.Len(t, stream.updates, 2)
+
+	assert.True(t, stream.updates[0].Full)
+	assert.ElementsMatch(t, []string{"127.0.0.0/24", "127.0.1.0/24 metric=1"},
+		prefixStrings(stream.updates[0].Announced))
+	assert.Empty(t, stream.updates[0].Withdrawn)
+
+	assert.False(t, stream.updates[1].Full)
+	assert.ElementsMatch(t, []string{"127.0.1.0/24 metric=2 tags=backup", "::/64"},
+		prefixStrings(stream.updates[1].Announced))
+	assert.ElementsMatch(t, []string{"127.0.0.0/24"},
+		prefixStrings(stream.updates[1].Withdrawn))
+}
+
+type fakePrefixesStream struct {
+	gpb.IPPrefixesService_PrefixesStreamServer
+	ctx     context.Context
+	updates []*gpb.PrefixesUpdate
+	onSend  func()
+}
+
+func (s *fakePrefixesStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakePrefixesStream) Send(update *gpb.PrefixesUpdate) error {
+	s.updates = append(s.updates, update)
+	s.onSend()
+	return nil
+}
+
+func prefixStrings(prefixes []*gpb.Prefix) []string {
+	var s []string
+	for _, pb := range prefixes {
+		prefix := &net.IPNet{
+			IP:   net.IP(pb.Prefix),
+			Mask: net.CIDRMask(int(pb.Mask), len(pb.Prefix)*8),
+		}
+		str := prefix.String()
+		if pb.Attributes != nil {
+			attrs := routing.PrefixAttributes{
+				Metric: pb.Attributes.Metric,
+				Tags:   pb.Attributes.Tags,
+			}
+			str += " " + attrs.String()
+		}
+		s = append(s, str)
+	}
+	return s
+}
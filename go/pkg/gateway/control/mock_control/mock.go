@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/scionproto/scion/go/pkg/gateway/control (interfaces: DataplaneSession,Discoverer,RoutingTable,RoutingTableSwapper,RoutingTableFactory,EngineFactory,PathMonitor,PathMonitorRegistration,PacketConnFactory,PrefixConsumer,PrefixFetcher,DataplaneSessionFactory,PktWriter,Worker,SessionPolicyParser,RoutingPolicyProvider,Runner,GatewayWatcherFactory)
+// Source: github.com/scionproto/scion/go/pkg/gateway/control (interfaces: DataplaneSession,Discoverer,RoutingTable,RoutingTableSwapper,RoutingTableFactory,EngineFactory,PathMonitor,PathMonitorRegistration,PacketConnFactory,PrefixConsumer,PrefixFetcher,PrefixStreamer,DataplaneSessionFactory,PktWriter,Worker,SessionPolicyParser,RoutingPolicyProvider,Runner,GatewayWatcherFactory)
 
 // Package mock_control is a generated GoMock package.
 package mock_control
@@ -479,15 +479,15 @@ func (m *MockPrefixConsumer) EXPECT() *MockPrefixConsumerMockRecorder {
 }
 
 // Prefixes mocks base method
-func (m *MockPrefixConsumer) Prefixes(arg0 addr.IA, arg1 control.Gateway, arg2 []*net.IPNet) {
+func (m *MockPrefixConsumer) Prefixes(arg0 addr.IA, arg1 control.Gateway, arg2 []*net.IPNet, arg3 map[string]routing.PrefixAttributes) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Prefixes", arg0, arg1, arg2)
+	m.ctrl.Call(m, "Prefixes", arg0, arg1, arg2, arg3)
 }
 
 // Prefixes indicates an expected call of Prefixes
-func (mr *MockPrefixConsumerMockRecorder) Prefixes(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockPrefixConsumerMockRecorder) Prefixes(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prefixes", reflect.TypeOf((*MockPrefixConsumer)(nil).Prefixes), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prefixes", reflect.TypeOf((*MockPrefixConsumer)(nil).Prefixes), arg0, arg1, arg2, arg3)
 }
 
 // MockPrefixFetcher is a mock of PrefixFetcher interface
@@ -528,6 +528,43 @@ func (mr *MockPrefixFetcherMockRecorder) Prefixes(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prefixes", reflect.TypeOf((*MockPrefixFetcher)(nil).Prefixes), arg0, arg1)
 }
 
+// MockPrefixStreamer is a mock of PrefixStreamer interface
+type MockPrefixStreamer struct {
+	ctrl     *gomock.Controller
+	recorder *MockPrefixStreamerMockRecorder
+}
+
+// MockPrefixStreamerMockRecorder is the mock recorder for MockPrefixStreamer
+type MockPrefixStreamerMockRecorder struct {
+	mock *MockPrefixStreamer
+}
+
+// NewMockPrefixStreamer creates a new mock instance
+func NewMockPrefixStreamer(ctrl *gomock.Controller) *MockPrefixStreamer {
+	mock := &MockPrefixStreamer{ctrl: ctrl}
+	mock.recorder = &MockPrefixStreamerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPrefixStreamer) EXPECT() *MockPrefixStreamerMockRecorder {
+	return m.recorder
+}
+
+// StreamPrefixes mocks base method
+func (m *MockPrefixStreamer) StreamPrefixes(arg0 context.Context, arg1 *net.UDPAddr, arg2 func(control.PrefixUpdate)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamPrefixes", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamPrefixes indicates an expected call of StreamPrefixes
+func (mr *MockPrefixStreamerMockRecorder) StreamPrefixes(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamPrefixes", reflect.TypeOf((*MockPrefixStreamer)(nil).StreamPrefixes), arg0, arg1, arg2)
+}
+
 // MockDataplaneSessionFactory is a mock of DataplaneSessionFactory interface
 type MockDataplaneSessionFactory struct {
 	ctrl     *gomock.Controller
@@ -552,17 +589,17 @@ func (m *MockDataplaneSessionFactory) EXPECT() *MockDataplaneSessionFactoryMockR
 }
 
 // New mocks base method
-func (m *MockDataplaneSessionFactory) New(arg0 byte, arg1 int, arg2 addr.IA, arg3 net.Addr) control.DataplaneSession {
+func (m *MockDataplaneSessionFactory) New(arg0 byte, arg1 int, arg2 addr.IA, arg3 net.Addr, arg4 bool, arg5 control.MultipathMode) control.DataplaneSession {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "New", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "New", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(control.DataplaneSession)
 	return ret0
 }
 
 // New indicates an expected call of New
-func (mr *MockDataplaneSessionFactoryMockRecorder) New(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockDataplaneSessionFactoryMockRecorder) New(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "New", reflect.TypeOf((*MockDataplaneSessionFactory)(nil).New), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "New", reflect.TypeOf((*MockDataplaneSessionFactory)(nil).New), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
 // MockPktWriter is a mock of PktWriter interface
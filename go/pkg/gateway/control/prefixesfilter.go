@@ -49,18 +49,27 @@ type PrefixesFilter struct {
 }
 
 // Prefixes consumes the prefixes, if they are accepted by the policy they are
-// forwarded to the registered consumer.
-func (f PrefixesFilter) Prefixes(remote addr.IA, gateway Gateway, prefixes []*net.IPNet) {
+// forwarded to the registered consumer together with their attributes.
+func (f PrefixesFilter) Prefixes(remote addr.IA, gateway Gateway, prefixes []*net.IPNet,
+	attributes map[string]routing.PrefixAttributes) {
+
 	rp := f.PolicyProvider.RoutingPolicy()
 	if rp == nil {
 		return
 	}
 	var allowedPrefixes []*net.IPNet
+	var allowedAttributes map[string]routing.PrefixAttributes
 	rejectedCount := 0
 	for _, prefix := range prefixes {
 		rule := rp.Match(remote, f.LocalIA, prefix)
 		if rule.Action == routing.Accept {
 			allowedPrefixes = append(allowedPrefixes, prefix)
+			if attrs, ok := attributes[prefix.String()]; ok {
+				if allowedAttributes == nil {
+					allowedAttributes = make(map[string]routing.PrefixAttributes)
+				}
+				allowedAttributes[prefix.String()] = attrs
+			}
 		} else {
 			rejectedCount++
 		}
@@ -69,5 +78,5 @@ func (f PrefixesFilter) Prefixes(remote addr.IA, gateway Gateway, prefixes []*ne
 		"remote_isd_as", remote.String()), float64(len(allowedPrefixes)))
 	metrics.GaugeSet(metrics.GaugeWith(f.Metrics.PrefixesRejected,
 		"remote_isd_as", remote.String()), float64(rejectedCount))
-	f.Consumer.Prefixes(remote, gateway, allowedPrefixes)
+	f.Consumer.Prefixes(remote, gateway, allowedPrefixes, allowedAttributes)
 }
@@ -30,9 +30,10 @@ import (
 
 func TestPrefixesFilterPrefixes(t *testing.T) {
 	type input struct {
-		IA       addr.IA
-		Gateway  control.Gateway
-		Prefixes []*net.IPNet
+		IA         addr.IA
+		Gateway    control.Gateway
+		Prefixes   []*net.IPNet
+		Attributes map[string]routing.PrefixAttributes
 	}
 	testCases := map[string]struct {
 		CreateFilter func(*testing.T, *gomock.Controller) control.PrefixesFilter
@@ -66,8 +67,10 @@ func TestPrefixesFilterPrefixes(t *testing.T) {
 		"deny all filters all": {
 			CreateFilter: func(_ *testing.T, ctrl *gomock.Controller) control.PrefixesFilter {
 				consumer := mock_control.NewMockPrefixConsumer(ctrl)
-				consumer.EXPECT().Prefixes(xtest.MustParseIA("1-ff00:0:111"), gomock.Any(), nil)
-				consumer.EXPECT().Prefixes(xtest.MustParseIA("1-ff00:0:112"), gomock.Any(), nil)
+				consumer.EXPECT().Prefixes(xtest.MustParseIA("1-ff00:0:111"), gomock.Any(),
+					nil, nil)
+				consumer.EXPECT().Prefixes(xtest.MustParseIA("1-ff00:0:112"), gomock.Any(),
+					nil, nil)
 				provider := mock_control.NewMockRoutingPolicyProvider(ctrl)
 				provider.EXPECT().RoutingPolicy().
 					Return(&routing.Policy{DefaultAction: routing.Reject}).Times(2)
@@ -95,9 +98,9 @@ func TestPrefixesFilterPrefixes(t *testing.T) {
 			CreateFilter: func(_ *testing.T, ctrl *gomock.Controller) control.PrefixesFilter {
 				consumer := mock_control.NewMockPrefixConsumer(ctrl)
 				consumer.EXPECT().Prefixes(xtest.MustParseIA("1-ff00:0:111"), gomock.Any(),
-					xtest.MustParseCIDRs(t, "10.1.0.0/25"))
+					xtest.MustParseCIDRs(t, "10.1.0.0/25"), nil)
 				consumer.EXPECT().Prefixes(xtest.MustParseIA("1-ff00:0:112"), gomock.Any(),
-					xtest.MustParseCIDRs(t, "10.4.0.0/25"))
+					xtest.MustParseCIDRs(t, "10.4.0.0/25"), nil)
 				provider := mock_control.NewMockRoutingPolicyProvider(ctrl)
 				provider.EXPECT().RoutingPolicy().
 					Return(&routing.Policy{DefaultAction: routing.Accept}).Times(2)
@@ -125,10 +128,11 @@ func TestPrefixesFilterPrefixes(t *testing.T) {
 			CreateFilter: func(t *testing.T, ctrl *gomock.Controller) control.PrefixesFilter {
 				consumer := mock_control.NewMockPrefixConsumer(ctrl)
 				consumer.EXPECT().Prefixes(xtest.MustParseIA("1-ff00:0:111"), gomock.Any(),
-					xtest.MustParseCIDRs(t, "10.1.0.0/25"))
+					xtest.MustParseCIDRs(t, "10.1.0.0/25"), nil)
 				consumer.EXPECT().Prefixes(xtest.MustParseIA("1-ff00:0:112"), gomock.Any(),
-					xtest.MustParseCIDRs(t, "10.4.0.0/25"))
-				consumer.EXPECT().Prefixes(xtest.MustParseIA("1-ff00:0:113"), gomock.Any(), nil)
+					xtest.MustParseCIDRs(t, "10.4.0.0/25"), nil)
+				consumer.EXPECT().Prefixes(xtest.MustParseIA("1-ff00:0:113"), gomock.Any(),
+					nil, nil)
 				pol := &routing.Policy{DefaultAction: routing.Reject}
 				err := pol.UnmarshalText(
 					[]byte(`accept    1-ff00:0:111    1-ff00:0:110    10.1.0.0/25
@@ -163,6 +167,37 @@ accept    1-ff00:0:113    1-ff00:0:110    10.3.0.0/25`))
 				},
 			},
 		},
+		"attributes of accepted prefixes are forwarded": {
+			CreateFilter: func(t *testing.T, ctrl *gomock.Controller) control.PrefixesFilter {
+				consumer := mock_control.NewMockPrefixConsumer(ctrl)
+				consumer.EXPECT().Prefixes(xtest.MustParseIA("1-ff00:0:111"), gomock.Any(),
+					xtest.MustParseCIDRs(t, "10.1.0.0/25"),
+					map[string]routing.PrefixAttributes{"10.1.0.0/25": {Metric: 5}})
+				pol := &routing.Policy{DefaultAction: routing.Reject}
+				err := pol.UnmarshalText(
+					[]byte(`accept    1-ff00:0:111    1-ff00:0:110    10.1.0.0/25`))
+				require.NoError(t, err)
+				provider := mock_control.NewMockRoutingPolicyProvider(ctrl)
+				provider.EXPECT().RoutingPolicy().Return(pol)
+				f := control.PrefixesFilter{
+					LocalIA:        xtest.MustParseIA("1-ff00:0:110"),
+					PolicyProvider: provider,
+					Consumer:       consumer,
+				}
+				return f
+			},
+			Inputs: []input{
+				{
+					IA:       xtest.MustParseIA("1-ff00:0:111"),
+					Gateway:  control.Gateway{},
+					Prefixes: xtest.MustParseCIDRs(t, "10.1.0.0/25", "127.0.0.0/8"),
+					Attributes: map[string]routing.PrefixAttributes{
+						"10.1.0.0/25": {Metric: 5},
+						"127.0.0.0/8": {Metric: 7},
+					},
+				},
+			},
+		},
 	}
 	for name, tc := range testCases {
 		name, tc := name, tc
@@ -173,7 +208,7 @@ accept    1-ff00:0:113    1-ff00:0:110    10.3.0.0/25`))
 
 			f := tc.CreateFilter(t, ctrl)
 			for _, input := range tc.Inputs {
-				f.Prefixes(input.IA, input.Gateway, input.Prefixes)
+				f.Prefixes(input.IA, input.Gateway, input.Prefixes, input.Attributes)
 			}
 		})
 	}
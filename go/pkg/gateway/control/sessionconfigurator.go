@@ -31,6 +31,7 @@ import (
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/snet"
 	"github.com/scionproto/scion/go/pkg/gateway/pathhealth/policies"
+	"github.com/scionproto/scion/go/pkg/gateway/routing"
 	"github.com/scionproto/scion/go/pkg/worker"
 )
 
@@ -60,6 +61,16 @@ type SessionConfig struct {
 	// Prefixes contains the network prefixes that are reachable through this
 	// session.
 	Prefixes []*net.IPNet
+	// PrefixAttributes are the attributes the remote gateway announced for the
+	// prefixes, keyed by the string representation of the prefix. Statically
+	// configured prefixes have no attributes.
+	PrefixAttributes map[string]routing.PrefixAttributes
+	// Encryption indicates whether the frames sent in this session are
+	// encrypted.
+	Encryption bool
+	// Multipath defines how the traffic is distributed among the paths of the
+	// session.
+	Multipath MultipathMode
 }
 
 // SessionConfigurator builds session configurations from the static traffic
@@ -222,6 +233,8 @@ func diffSessionPolicies(a, b SessionPolicies) bool {
 func diffSessionPolicy(a, b SessionPolicy) bool {
 	if a.TrafficMatcher.String() != b.TrafficMatcher.String() ||
 		a.PathCount != b.PathCount ||
+		a.Encryption != b.Encryption ||
+		a.Multipath != b.Multipath ||
 		// no better way than comparing pointers here:
 		a.PerfPolicy != b.PerfPolicy ||
 		prefixesKey(a.Prefixes) != prefixesKey(b.Prefixes) {
@@ -284,7 +297,21 @@ func diffRemoteGateways(a, b []RemoteGateway) bool {
 
 func diffRemoteGateway(a, b RemoteGateway) bool {
 	return !a.Gateway.Equal(b.Gateway) ||
-		prefixesKey(a.Prefixes) != prefixesKey(b.Prefixes)
+		prefixesKey(a.Prefixes) != prefixesKey(b.Prefixes) ||
+		diffPrefixAttributes(a.Attributes, b.Attributes)
+}
+
+func diffPrefixAttributes(a, b map[string]routing.PrefixAttributes) bool {
+	if len(a) != len(b) {
+		return true
+	}
+	for key, attrsA := range a {
+		attrsB, ok := b[key]
+		if !ok || !attrsA.Equal(attrsB) {
+			return true
+		}
+	}
+	return false
 }
 
 // buildSessionConfigs builds the session configurations from the static
@@ -326,8 +353,12 @@ func buildSessionConfigs(sessionPolicies SessionPolicies,
 				PerfPolicy:     sessionPolicy.PerfPolicy,
 				PathPolicy:     pathPol,
 				PathCount:      sessionPolicy.PathCount,
+				Encryption:     sessionPolicy.Encryption,
+				Multipath:      sessionPolicy.Multipath,
 				Gateway:        entry.Gateway,
 				Prefixes:       mergePrefixes(sessionPolicy.Prefixes, entry.Prefixes),
+				PrefixAttributes: dynamicAttributes(sessionPolicy.Prefixes,
+					entry.Attributes),
 			})
 			sessID++
 		}
@@ -368,6 +399,25 @@ func mergePrefixes(static, dynamic []*net.IPNet) []*net.IPNet {
 	return result
 }
 
+// dynamicAttributes returns the attributes of the dynamically announced
+// prefixes. Statically configured prefixes take precedence, thus their
+// attributes are dropped.
+func dynamicAttributes(static []*net.IPNet,
+	attributes map[string]routing.PrefixAttributes) map[string]routing.PrefixAttributes {
+
+	if len(attributes) == 0 {
+		return nil
+	}
+	result := make(map[string]routing.PrefixAttributes, len(attributes))
+	for key, attrs := range attributes {
+		result[key] = attrs
+	}
+	for _, n := range static {
+		delete(result, n.String())
+	}
+	return result
+}
+
 type conjuctionPathPol struct {
 	Pol1, Pol2 policies.PathPolicy
 }
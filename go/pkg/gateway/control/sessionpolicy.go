@@ -37,6 +37,39 @@ var (
 	DefaultPathCount  = 1
 )
 
+// MultipathMode defines how a session uses its paths.
+type MultipathMode string
+
+const (
+	// MultipathLoadBalance distributes the flows equally among the paths. It
+	// is the default mode.
+	MultipathLoadBalance MultipathMode = "load_balance"
+	// MultipathRedundant sends every frame on all the paths. The paths are
+	// selected such that they are disjoint whenever possible. The remote
+	// gateway drops the duplicated frames.
+	MultipathRedundant MultipathMode = "redundant"
+	// MultipathWeightedBandwidth distributes the flows among the paths in
+	// proportion to the bandwidth of the paths.
+	MultipathWeightedBandwidth MultipathMode = "weighted_bandwidth"
+	// MultipathWeightedLatency distributes the flows among the paths in inverse
+	// proportion to the measured latency of the paths.
+	MultipathWeightedLatency MultipathMode = "weighted_latency"
+)
+
+// ParseMultipathMode parses the multipath mode. The empty string is parsed as
+// the default mode.
+func ParseMultipathMode(s string) (MultipathMode, error) {
+	switch m := MultipathMode(s); m {
+	case "":
+		return MultipathLoadBalance, nil
+	case MultipathLoadBalance, MultipathRedundant, MultipathWeightedBandwidth,
+		MultipathWeightedLatency:
+		return m, nil
+	default:
+		return "", serrors.New("unknown multipath mode", "mode", s)
+	}
+}
+
 // LegacySessionPolicyAdapter parses the legacy gateway JSON configuration and
 // adapts it into the session policies format.
 type LegacySessionPolicyAdapter struct{}
@@ -45,8 +78,10 @@ type LegacySessionPolicyAdapter struct{}
 func (LegacySessionPolicyAdapter) Parse(raw []byte) (SessionPolicies, error) {
 	type JSONFormat struct {
 		ASes map[addr.IA]struct {
-			Nets      []string
-			PathCount int
+			Nets       []string
+			PathCount  int
+			Encryption bool
+			Multipath  string
 		}
 		ConfigVersion uint64
 	}
@@ -64,6 +99,10 @@ func (LegacySessionPolicyAdapter) Parse(raw []byte) (SessionPolicies, error) {
 		if asEntry.PathCount != 0 {
 			pathCount = asEntry.PathCount
 		}
+		multipath, err := ParseMultipathMode(asEntry.Multipath)
+		if err != nil {
+			return nil, serrors.WithCtx(err, "isd_as", ia)
+		}
 		policies = append(policies, SessionPolicy{
 			ID:             0,
 			IA:             ia,
@@ -72,6 +111,8 @@ func (LegacySessionPolicyAdapter) Parse(raw []byte) (SessionPolicies, error) {
 			PathPolicy:     DefaultPathPolicy,
 			PathCount:      pathCount,
 			Prefixes:       prefixes,
+			Encryption:     asEntry.Encryption,
+			Multipath:      multipath,
 		})
 	}
 	return policies, nil
@@ -130,6 +171,22 @@ func (p SessionPolicies) RemoteIAs() []addr.IA {
 	return result
 }
 
+// EncryptedIAs returns all IAs for which encryption is enabled in the session
+// policies.
+func (p SessionPolicies) EncryptedIAs() []addr.IA {
+	uniqueIAs := make(map[addr.IA]struct{}, len(p))
+	for _, s := range p {
+		if s.Encryption {
+			uniqueIAs[s.IA] = struct{}{}
+		}
+	}
+	result := make([]addr.IA, 0, len(uniqueIAs))
+	for ia := range uniqueIAs {
+		result = append(result, ia)
+	}
+	return result
+}
+
 // Copy creates a deep copy of the session policies.
 func (p SessionPolicies) Copy() SessionPolicies {
 	copy := make(SessionPolicies, 0, len(p))
@@ -146,9 +203,10 @@ func (p SessionPolicies) Copy() SessionPolicies {
 // - traffic class, defined by a traffic matcher,
 // - a path class defined by a path policy,
 // - a performance policy,
-// - a path count,
+// - a path count and a multipath mode,
 // - a remote IA,
-// - a set of prefixes.
+// - a set of prefixes,
+// - whether the traffic is encrypted.
 type SessionPolicy struct {
 	// IA is the ISD-AS number of the remote AS.
 	IA addr.IA
@@ -170,6 +228,13 @@ type SessionPolicy struct {
 	// Prefixes contains the network prefixes that are reachable through this
 	// session.
 	Prefixes []*net.IPNet
+	// Encryption indicates whether the traffic exchanged with the remote AS is
+	// encrypted. If set, frames sent to the remote AS are encrypted, and
+	// unencrypted frames received from it are dropped.
+	Encryption bool
+	// Multipath defines how the traffic is distributed among the paths of the
+	// session.
+	Multipath MultipathMode
 }
 
 // Copy creates a deep copy.
@@ -183,6 +248,8 @@ func (sp SessionPolicy) Copy() SessionPolicy {
 		PathPolicy: copyPathPolicy(sp.PathPolicy),
 		PathCount:  sp.PathCount,
 		Prefixes:   copyPrefixes(sp.Prefixes),
+		Encryption: sp.Encryption,
+		Multipath:  sp.Multipath,
 	}
 }
 
@@ -103,10 +103,86 @@ func TestLegacySessionPolicyAdapterParse(t *testing.T) {
 					PathPolicy:     control.DefaultPathPolicy,
 					PathCount:      1,
 					Prefixes:       []*net.IPNet{xtest.MustParseCIDR(t, "172.20.4.0/24")},
+					Multipath:      control.MultipathLoadBalance,
 				},
 			},
 			AssertErr: assert.NoError,
 		},
+		"encrypted AS": {
+			Input: []byte(`
+			{
+				"ASes": {
+				  "1-ff00:0:110": {
+					"Nets": [
+					  "172.20.4.0/24"
+					],
+					"Encryption": true
+				  }
+				},
+				"ConfigVersion": 300
+			}
+			`),
+			Expected: control.SessionPolicies{
+				control.SessionPolicy{
+					ID:             0,
+					IA:             xtest.MustParseIA("1-ff00:0:110"),
+					TrafficMatcher: pktcls.CondTrue,
+					PerfPolicy:     control.DefaultPerfPolicy,
+					PathPolicy:     control.DefaultPathPolicy,
+					PathCount:      1,
+					Prefixes:       []*net.IPNet{xtest.MustParseCIDR(t, "172.20.4.0/24")},
+					Encryption:     true,
+					Multipath:      control.MultipathLoadBalance,
+				},
+			},
+			AssertErr: assert.NoError,
+		},
+		"redundant AS": {
+			Input: []byte(`
+			{
+				"ASes": {
+				  "1-ff00:0:110": {
+					"Nets": [
+					  "172.20.4.0/24"
+					],
+					"PathCount": 2,
+					"Multipath": "redundant"
+				  }
+				},
+				"ConfigVersion": 300
+			}
+			`),
+			Expected: control.SessionPolicies{
+				control.SessionPolicy{
+					ID:             0,
+					IA:             xtest.MustParseIA("1-ff00:0:110"),
+					TrafficMatcher: pktcls.CondTrue,
+					PerfPolicy:     control.DefaultPerfPolicy,
+					PathPolicy:     control.DefaultPathPolicy,
+					PathCount:      2,
+					Prefixes:       []*net.IPNet{xtest.MustParseCIDR(t, "172.20.4.0/24")},
+					Multipath:      control.MultipathRedundant,
+				},
+			},
+			AssertErr: assert.NoError,
+		},
+		"unknown multipath mode": {
+			Input: []byte(`
+			{
+				"ASes": {
+				  "1-ff00:0:110": {
+					"Nets": [
+					  "172.20.4.0/24"
+					],
+					"Multipath": "random"
+				  }
+				},
+				"ConfigVersion": 300
+			}
+			`),
+			Expected:  nil,
+			AssertErr: assert.Error,
+		},
 	}
 	for name, tc := range testCases {
 		name, tc := name, tc
@@ -212,6 +288,16 @@ func TestSessionPoliciesRemoteIAs(t *testing.T) {
 	}
 }
 
+func TestSessionPoliciesEncryptedIAs(t *testing.T) {
+	policies := control.SessionPolicies{
+		control.SessionPolicy{IA: xtest.MustParseIA("1-ff00:0:110"), Encryption: true},
+		control.SessionPolicy{IA: xtest.MustParseIA("1-ff00:0:110"), ID: 1, Encryption: true},
+		control.SessionPolicy{IA: xtest.MustParseIA("1-ff00:0:111")},
+	}
+	assert.ElementsMatch(t, []addr.IA{xtest.MustParseIA("1-ff00:0:110")},
+		policies.EncryptedIAs())
+}
+
 func TestCopyPathPolicy(t *testing.T) {
 	input := &pathpol.Policy{
 		ACL: &pathpol.ACL{
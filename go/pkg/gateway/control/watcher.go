@@ -16,6 +16,7 @@ package control
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"sort"
@@ -29,6 +30,7 @@ import (
 	"github.com/scionproto/scion/go/lib/metrics"
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/pkg/gateway/routing"
 )
 
 const (
@@ -44,11 +46,17 @@ const (
 	// defaultGatewayPollTimeout is the default timeout for polling the remote
 	// gateway for prefixes.
 	defaultGatewayPollTimeout = 5 * time.Second
+	// defaultStreamRetryInterval is the default interval in which streaming is
+	// retried after the remote gateway did not support it.
+	defaultStreamRetryInterval = time.Minute
 )
 
 var (
 	// ErrAlreadyRunning is the error returned when attempting to run a task twice.
 	ErrAlreadyRunning = serrors.New("is running")
+	// ErrStreamingUnsupported is the error returned by a PrefixStreamer if the
+	// remote gateway does not support streaming the IP prefixes.
+	ErrStreamingUnsupported = serrors.New("prefix streaming not supported")
 )
 
 // Gateway represents a remote gateway instance.
@@ -142,6 +150,7 @@ type gatewayDiagnostics struct {
 	ProbeAddr  string    `json:"probe_address"`
 	Interfaces []uint64  `json:"interfaces"`
 	Prefixes   []string  `json:"prefixes"`
+	Streaming  bool      `json:"streaming"`
 	Timestamp  time.Time `json:"timestamp"`
 }
 
@@ -258,6 +267,7 @@ func (w *GatewayWatcher) diagnostics() (remoteDiagnostics, error) {
 			ProbeAddr:  watcher.gateway.Probe.String(),
 			Interfaces: interfaces,
 			Prefixes:   watcher.prefixes,
+			Streaming:  watcher.streaming,
 			Timestamp:  watcher.timestamp,
 		}
 	}
@@ -280,9 +290,12 @@ func (w *GatewayWatcher) validateParameters() error {
 	return nil
 }
 
-// PrefixConsumer consumes the prefixes fetched by the PrefixWatcher.
+// PrefixConsumer consumes the prefixes fetched by the PrefixWatcher. The
+// attributes of the prefixes are keyed by the string representation of the
+// prefix. Prefixes without an entry have no attributes.
 type PrefixConsumer interface {
-	Prefixes(remote addr.IA, gateway Gateway, prefixes []*net.IPNet)
+	Prefixes(remote addr.IA, gateway Gateway, prefixes []*net.IPNet,
+		attributes map[string]routing.PrefixAttributes)
 }
 
 // PrefixFetcher fetches the IP prefixes from a remote gateway.
@@ -290,22 +303,57 @@ type PrefixFetcher interface {
 	Prefixes(ctx context.Context, gateway *net.UDPAddr) ([]*net.IPNet, error)
 }
 
+// PrefixUpdate is an update of the IP prefixes announced by a remote gateway.
+type PrefixUpdate struct {
+	// Full indicates that Announced contains all the prefixes announced by the
+	// remote gateway. All previously announced prefixes that are not part of
+	// the update are withdrawn.
+	Full bool
+	// Announced are the prefixes that are newly announced or whose attributes
+	// changed.
+	Announced []*net.IPNet
+	// Withdrawn are the prefixes that are no longer announced.
+	Withdrawn []*net.IPNet
+	// Attributes are the attributes of the announced prefixes, keyed by the
+	// string representation of the prefix.
+	Attributes map[string]routing.PrefixAttributes
+}
+
+// PrefixStreamer streams the IP prefixes from a remote gateway.
+type PrefixStreamer interface {
+	// StreamPrefixes streams the prefix updates of the remote gateway to the
+	// handler. The handler is called synchronously. The method blocks until
+	// the stream terminates or the context is canceled. If the remote gateway
+	// does not support streaming, ErrStreamingUnsupported is returned.
+	StreamPrefixes(ctx context.Context, gateway *net.UDPAddr, handler func(PrefixUpdate)) error
+}
+
 // PrefixWatcherConfig configures a prefix watcher that watches IP prefixes
 // advertised by a remote gateway. The discovered IP prefixes are advertised to
-// the prefix consumer. The watcher is stateless and does not keep track of
-// previously discovered IP prefixes.
+// the prefix consumer.
 type PrefixWatcherConfig struct {
 	// Consumer consume the fetched prefixes. Its methods are called
 	// synchroniously and should return swiftly.
 	Consumer PrefixConsumer
 	// PrefixFetcher is used to fetch IP prefixes from the remote gateway.
 	Fetcher PrefixFetcher
-	// PollInterval is the time between consecutive poll attempts. If zero, this
-	// defaults to 5 seconds.
+	// Streamer is used to stream the IP prefixes from the remote gateway. If
+	// nil, or if the remote gateway does not support streaming, the prefixes
+	// are polled with the Fetcher.
+	Streamer PrefixStreamer
+	// PollInterval is the time between consecutive poll attempts. While
+	// streaming, it is the interval in which the current prefixes are
+	// refreshed at the consumer, and in which a failed stream is retried. If
+	// zero, this defaults to 5 seconds.
 	PollInterval time.Duration
 	// PollTimeout is the timout for an individual poll attempts. If zero, this
 	// defaults to 5 seconds.
 	PollTimeout time.Duration
+	// StreamRetryInterval is the interval in which streaming is retried after
+	// the remote gateway did not support it, e.g., because it was upgraded in
+	// the meantime. In between, the prefixes are polled. If zero, this
+	// defaults to 1 minute.
+	StreamRetryInterval time.Duration
 }
 
 func (c *PrefixWatcherConfig) validateParameters() error {
@@ -321,12 +369,16 @@ func (c *PrefixWatcherConfig) validateParameters() error {
 	if c.PollTimeout == 0 {
 		c.PollTimeout = defaultGatewayPollTimeout
 	}
+	if c.StreamRetryInterval == 0 {
+		c.StreamRetryInterval = defaultStreamRetryInterval
+	}
 	return nil
 }
 
 // prefixWatcher watches IP prefixes advertised by a remote gateway. The
-// discovered IP prefixes are advertised to the prefix consumer. The watcher is
-// stateless and does not keep track of previously discovered IP prefixes.
+// discovered IP prefixes are advertised to the prefix consumer. If the remote
+// gateway supports it, the prefixes are streamed, such that announcements and
+// withdrawals are forwarded immediately. Otherwise, the prefixes are polled.
 type prefixWatcher struct {
 	PrefixWatcherConfig
 
@@ -342,6 +394,8 @@ type prefixWatcher struct {
 	prefixes []string
 	// timestamp of last fetched prefixes
 	timestamp time.Time
+	// streaming indicates whether the prefixes are currently streamed.
+	streaming bool
 }
 
 func newPrefixWatcher(gateway Gateway, remote addr.IA, cfg PrefixWatcherConfig) *prefixWatcher {
@@ -370,17 +424,106 @@ func (w *prefixWatcher) Run(ctx context.Context) error {
 
 	ticker := time.NewTicker(w.PollInterval)
 	defer ticker.Stop()
-	w.run(ctx)
+	streaming := w.Streamer != nil
+	var streamRetry time.Time
 	for {
+		// A ready ticker can win the select below after the context is
+		// cancelled, thus check the context before every iteration.
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !streaming && w.Streamer != nil && !time.Now().Before(streamRetry) {
+			streaming = true
+		}
+		if streaming {
+			err := w.stream(ctx)
+			switch {
+			case errors.Is(err, ErrStreamingUnsupported):
+				logger.Debug("Remote gateway does not support streaming, falling back to polling",
+					"retry_in", w.StreamRetryInterval)
+				streaming = false
+				streamRetry = time.Now().Add(w.StreamRetryInterval)
+			case err != nil && ctx.Err() == nil:
+				logger.Debug("Streaming IP prefixes from remote gateway failed", "err", err)
+			}
+		}
+		if !streaming && ctx.Err() == nil {
+			w.run(ctx)
+		}
 		select {
 		case <-ctx.Done():
 			return nil
 		case <-ticker.C:
-			w.run(ctx)
 		}
 	}
 }
 
+// stream streams the prefixes from the remote gateway until the stream
+// terminates. While streaming, the current prefixes are periodically pushed to
+// the consumer to keep them from expiring.
+func (w *prefixWatcher) stream(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mtx sync.Mutex
+	var received bool
+	s := newPrefixState()
+	publish := func() {
+		prefixes, attributes := s.get()
+		w.Consumer.Prefixes(w.remote, w.gateway, prefixes, attributes)
+
+		w.stateMtx.Lock()
+		defer w.stateMtx.Unlock()
+		w.prefixes = fmtPrefixes(prefixes)
+		w.timestamp = time.Now()
+	}
+	// Wait for the publishing goroutine, such that no prefixes are published
+	// after the stream terminated.
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	wg.Add(1)
+	go func() {
+		defer log.HandlePanic()
+		defer wg.Done()
+		ticker := time.NewTicker(w.PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				mtx.Lock()
+				if received && ctx.Err() == nil {
+					publish()
+				}
+				mtx.Unlock()
+			}
+		}
+	}()
+
+	w.setStreaming(true)
+	defer w.setStreaming(false)
+	return w.Streamer.StreamPrefixes(ctx, w.gateway.Control, func(update PrefixUpdate) {
+		log.FromCtx(ctx).Debug("Received IP prefix update", "full", update.Full,
+			"announced", fmtPrefixes(update.Announced),
+			"withdrawn", fmtPrefixes(update.Withdrawn))
+		mtx.Lock()
+		defer mtx.Unlock()
+		s.apply(update)
+		received = true
+		publish()
+	})
+}
+
+func (w *prefixWatcher) setStreaming(streaming bool) {
+	w.stateMtx.Lock()
+	defer w.stateMtx.Unlock()
+	w.streaming = streaming
+}
+
 func (w *prefixWatcher) run(ctx context.Context) {
 	ctx, cancel := context.WithTimeout(ctx, w.PollTimeout)
 	defer cancel()
@@ -395,7 +538,7 @@ func (w *prefixWatcher) run(ctx context.Context) {
 	logger.Debug("Fetched prefixes successfully", "prefixes", fmtPrefixes(prefixes))
 
 	snapshot := fmtPrefixes(prefixes)
-	w.Consumer.Prefixes(w.remote, w.gateway, prefixes)
+	w.Consumer.Prefixes(w.remote, w.gateway, prefixes, nil)
 
 	w.stateMtx.Lock()
 	defer w.stateMtx.Unlock()
@@ -403,6 +546,59 @@ func (w *prefixWatcher) run(ctx context.Context) {
 	w.timestamp = time.Now()
 }
 
+// prefixState is the set of prefixes announced by a remote gateway, as
+// reconstructed from the stream of updates.
+type prefixState struct {
+	prefixes   map[string]*net.IPNet
+	attributes map[string]routing.PrefixAttributes
+}
+
+func newPrefixState() *prefixState {
+	return &prefixState{
+		prefixes:   make(map[string]*net.IPNet),
+		attributes: make(map[string]routing.PrefixAttributes),
+	}
+}
+
+func (s *prefixState) apply(update PrefixUpdate) {
+	if update.Full {
+		s.prefixes = make(map[string]*net.IPNet)
+		s.attributes = make(map[string]routing.PrefixAttributes)
+	}
+	for _, prefix := range update.Withdrawn {
+		delete(s.prefixes, prefix.String())
+		delete(s.attributes, prefix.String())
+	}
+	for _, prefix := range update.Announced {
+		key := prefix.String()
+		s.prefixes[key] = prefix
+		if attrs, ok := update.Attributes[key]; ok && !attrs.IsZero() {
+			s.attributes[key] = attrs
+		} else {
+			delete(s.attributes, key)
+		}
+	}
+}
+
+// get returns the current prefixes sorted by their string representation, and
+// a copy of their attributes.
+func (s *prefixState) get() ([]*net.IPNet, map[string]routing.PrefixAttributes) {
+	keys := make([]string, 0, len(s.prefixes))
+	for key := range s.prefixes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	prefixes := make([]*net.IPNet, 0, len(keys))
+	for _, key := range keys {
+		prefixes = append(prefixes, s.prefixes[key])
+	}
+	attributes := make(map[string]routing.PrefixAttributes, len(s.attributes))
+	for key, attrs := range s.attributes {
+		attributes[key] = attrs
+	}
+	return prefixes, attributes
+}
+
 func fmtPrefixes(prefixes []*net.IPNet) []string {
 	ret := []string{}
 	for _, p := range prefixes {
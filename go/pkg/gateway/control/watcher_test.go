@@ -29,6 +29,7 @@ import (
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/pkg/gateway/control"
 	"github.com/scionproto/scion/go/pkg/gateway/control/mock_control"
+	"github.com/scionproto/scion/go/pkg/gateway/routing"
 )
 
 func TestGatewayWatcherRun(t *testing.T) {
@@ -117,9 +118,11 @@ func TestPrefixWatcherRun(t *testing.T) {
 			return first, nil
 		},
 	)
-	consumer.EXPECT().Prefixes(gomock.Any(), gateway, first).Do(func(_, _, _ interface{}) {
-		consumerCounts.Add(1)
-	})
+	consumer.EXPECT().Prefixes(gomock.Any(), gateway, first, nil).Do(
+		func(_, _, _, _ interface{}) {
+			consumerCounts.Add(1)
+		},
+	)
 
 	afterwards := []*net.IPNet{cidr(t, "127.0.0.0/24"), cidr(t, "::/64")}
 	fetcher.EXPECT().Prefixes(gomock.Any(), gateway.Control).AnyTimes().DoAndReturn(
@@ -128,8 +131,8 @@ func TestPrefixWatcherRun(t *testing.T) {
 			return afterwards, nil
 		},
 	)
-	consumer.EXPECT().Prefixes(gomock.Any(), gateway, afterwards).AnyTimes().Do(
-		func(_, _, _ interface{}) {
+	consumer.EXPECT().Prefixes(gomock.Any(), gateway, afterwards, nil).AnyTimes().Do(
+		func(_, _, _, _ interface{}) {
 			consumerCounts.Add(1)
 		},
 	)
@@ -156,6 +159,132 @@ func TestPrefixWatcherRun(t *testing.T) {
 	assert.Equal(t, metrics.CounterValue(fetcherCounts), metrics.CounterValue(consumerCounts))
 }
 
+func TestPrefixWatcherStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gateway := control.Gateway{Control: udp(t, "127.0.0.1:30256")}
+	streamer := mock_control.NewMockPrefixStreamer(ctrl)
+	consumer := mock_control.NewMockPrefixConsumer(ctrl)
+
+	attrs := routing.PrefixAttributes{Metric: 10, Tags: []string{"primary"}}
+	streamer.EXPECT().StreamPrefixes(gomock.Any(), gateway.Control, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ *net.UDPAddr, handler func(control.PrefixUpdate)) error {
+			handler(control.PrefixUpdate{
+				Full:       true,
+				Announced:  []*net.IPNet{cidr(t, "127.0.1.0/24"), cidr(t, "127.0.0.0/24")},
+				Attributes: map[string]routing.PrefixAttributes{"127.0.0.0/24": attrs},
+			})
+			handler(control.PrefixUpdate{
+				Announced: []*net.IPNet{cidr(t, "::/64")},
+				Withdrawn: []*net.IPNet{cidr(t, "127.0.1.0/24")},
+			})
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	full := []*net.IPNet{cidr(t, "127.0.0.0/24"), cidr(t, "127.0.1.0/24")}
+	afterwards := []*net.IPNet{cidr(t, "127.0.0.0/24"), cidr(t, "::/64")}
+	expectedAttrs := map[string]routing.PrefixAttributes{"127.0.0.0/24": attrs}
+	// The watcher republishes the current state periodically. Stop the
+	// watcher once it was republished, the stream must not be restarted.
+	var published int
+	gomock.InOrder(
+		consumer.EXPECT().Prefixes(gomock.Any(), gateway, full, expectedAttrs),
+		consumer.EXPECT().Prefixes(gomock.Any(), gateway, afterwards, expectedAttrs).
+			Do(func(...interface{}) {
+				if published++; published == 2 {
+					cancel()
+				}
+			}).MinTimes(2),
+	)
+
+	cfg := control.PrefixWatcherConfig{
+		Consumer:     consumer,
+		Fetcher:      mock_control.NewMockPrefixFetcher(ctrl),
+		Streamer:     streamer,
+		PollInterval: 5 * time.Millisecond,
+	}
+	w := control.NewPrefixWatcher(gateway, addr.IA{}, cfg)
+	require.NoError(t, w.Run(ctx))
+}
+
+func TestPrefixWatcherStreamFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gateway := control.Gateway{Control: udp(t, "127.0.0.1:30256")}
+	streamer := mock_control.NewMockPrefixStreamer(ctrl)
+	fetcher := mock_control.NewMockPrefixFetcher(ctrl)
+	consumer := mock_control.NewMockPrefixConsumer(ctrl)
+
+	// The streamer is only tried once, afterwards the watcher polls.
+	streamer.EXPECT().StreamPrefixes(gomock.Any(), gateway.Control, gomock.Any()).
+		Return(serrors.WrapStr("unsupported", control.ErrStreamingUnsupported))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	prefixes := []*net.IPNet{cidr(t, "127.0.0.0/24")}
+	fetcher.EXPECT().Prefixes(gomock.Any(), gateway.Control).Return(prefixes, nil).MinTimes(2)
+	var published int
+	consumer.EXPECT().Prefixes(gomock.Any(), gateway, prefixes, nil).Do(
+		func(...interface{}) {
+			if published++; published == 2 {
+				cancel()
+			}
+		},
+	).MinTimes(2)
+
+	cfg := control.PrefixWatcherConfig{
+		Consumer:     consumer,
+		Fetcher:      fetcher,
+		Streamer:     streamer,
+		PollInterval: 5 * time.Millisecond,
+	}
+	w := control.NewPrefixWatcher(gateway, addr.IA{}, cfg)
+	require.NoError(t, w.Run(ctx))
+}
+
+func TestPrefixWatcherStreamRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gateway := control.Gateway{Control: udp(t, "127.0.0.1:30256")}
+	streamer := mock_control.NewMockPrefixStreamer(ctrl)
+	fetcher := mock_control.NewMockPrefixFetcher(ctrl)
+	consumer := mock_control.NewMockPrefixConsumer(ctrl)
+
+	// The watcher polls until streaming is retried, the retry succeeds.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	prefixes := []*net.IPNet{cidr(t, "127.0.0.0/24")}
+	gomock.InOrder(
+		streamer.EXPECT().StreamPrefixes(gomock.Any(), gateway.Control, gomock.Any()).
+			Return(serrors.WrapStr("unsupported", control.ErrStreamingUnsupported)),
+		streamer.EXPECT().StreamPrefixes(gomock.Any(), gateway.Control, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ *net.UDPAddr,
+				handler func(control.PrefixUpdate)) error {
+
+				handler(control.PrefixUpdate{Full: true, Announced: prefixes})
+				cancel()
+				return ctx.Err()
+			}),
+	)
+	fetcher.EXPECT().Prefixes(gomock.Any(), gateway.Control).Return(prefixes, nil).MinTimes(1)
+	consumer.EXPECT().Prefixes(gomock.Any(), gateway, prefixes, gomock.Any()).MinTimes(2)
+
+	cfg := control.PrefixWatcherConfig{
+		Consumer:            consumer,
+		Fetcher:             fetcher,
+		Streamer:            streamer,
+		PollInterval:        5 * time.Millisecond,
+		StreamRetryInterval: 20 * time.Millisecond,
+	}
+	w := control.NewPrefixWatcher(gateway, addr.IA{}, cfg)
+	require.NoError(t, w.Run(ctx))
+}
+
 func TestComputeDiff(t *testing.T) {
 	testCases := map[string]struct {
 		Previous []control.Gateway
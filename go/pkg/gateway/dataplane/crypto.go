@@ -0,0 +1,224 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+)
+
+// Encrypted frames carry the regular frame header with version set to
+// encryptedVersion, followed by the crypto header and the sealed payload:
+//
+//	| frame header (16B) | key ID (4B) | nonce (12B) | ciphertext | tag (16B) |
+//
+// The frame header and the key ID are authenticated as additional data. The
+// nonce consists of a random salt chosen by the sender and a counter, which is
+// used for replay protection.
+const (
+	// plainVersion is the frame version for unencrypted frames.
+	plainVersion = 0
+	// encryptedVersion is the frame version for encrypted frames.
+	encryptedVersion = 1
+	// keyIDLen is the length of the key identifier.
+	keyIDLen = 4
+	// nonceLen is the length of the AEAD nonce.
+	nonceLen = 12
+	// tagLen is the length of the AEAD authentication tag.
+	tagLen = 16
+	// cryptoHdrLen is the length of the crypto header that follows the frame
+	// header in encrypted frames.
+	cryptoHdrLen = keyIDLen + nonceLen
+	// cryptoOverhead is the number of bytes encryption adds to a frame.
+	cryptoOverhead = cryptoHdrLen + tagLen
+)
+
+// errNoKey indicates that no key is available to encrypt or decrypt a frame.
+var errNoKey = serrors.New("no key available")
+
+// MaxKeyAge is the maximum time an ingress key may be accepted after it was
+// last used. Replay protection state is kept for this long, thus IngressKeys
+// implementations must not accept older keys.
+const MaxKeyAge = 24 * time.Hour
+
+// EgressKeys provides the keys used to encrypt the frames sent in a session.
+// Implementations must be safe for concurrent use.
+type EgressKeys interface {
+	// EgressKey returns the key that should currently be used for sealing
+	// frames together with its identifier. If no key is available, ok is
+	// false and the frame must be dropped.
+	EgressKey() (id uint32, aead cipher.AEAD, ok bool)
+}
+
+// IngressKeys provides the keys used to decrypt the frames received from remote
+// gateways. Implementations must be safe for concurrent use.
+type IngressKeys interface {
+	// IngressKey returns the key with the given identifier for frames received
+	// from the remote gateway. If the key is not available, ok is false and
+	// the frame must be dropped.
+	IngressKey(remote *snet.UDPAddr, id uint32) (aead cipher.AEAD, ok bool)
+	// Required indicates whether the frames received from the remote AS must
+	// be encrypted. Unencrypted frames from such an AS are dropped.
+	Required(ia addr.IA) bool
+}
+
+// frameSealer encrypts frames for a single sender. It is not safe for
+// concurrent use.
+type frameSealer struct {
+	keys EgressKeys
+	// salt is the random prefix of every nonce generated by this sealer.
+	salt [4]byte
+	// counter is the nonce counter. It is initialized randomly, such that
+	// nonces of different senders do not collide even if the salt does.
+	counter uint64
+	// buf is the buffer the sealed frame is written to.
+	buf []byte
+}
+
+func newFrameSealer(keys EgressKeys, mtu int) (*frameSealer, error) {
+	s := &frameSealer{
+		keys: keys,
+		buf:  make([]byte, 0, mtu+cryptoOverhead),
+	}
+	var init [12]byte
+	if _, err := rand.Read(init[:]); err != nil {
+		return nil, serrors.WrapStr("initializing nonce", err)
+	}
+	copy(s.salt[:], init[:4])
+	s.counter = binary.BigEndian.Uint64(init[4:])
+	return s, nil
+}
+
+// Seal encrypts the frame. The returned buffer is only valid until the next
+// call to Seal.
+func (s *frameSealer) Seal(frame []byte) ([]byte, error) {
+	id, aead, ok := s.keys.EgressKey()
+	if !ok {
+		return nil, errNoKey
+	}
+	buf := s.buf[:hdrLen+cryptoHdrLen]
+	copy(buf, frame[:hdrLen])
+	buf[versionPos] = encryptedVersion
+	binary.BigEndian.PutUint32(buf[hdrLen:], id)
+	nonce := buf[hdrLen+keyIDLen : hdrLen+cryptoHdrLen]
+	copy(nonce, s.salt[:])
+	binary.BigEndian.PutUint64(nonce[4:], s.counter)
+	s.counter++
+	return aead.Seal(buf, nonce, frame[hdrLen:], buf[:hdrLen+keyIDLen]), nil
+}
+
+// sealInfo contains the crypto header fields of an opened frame.
+type sealInfo struct {
+	keyID   uint32
+	salt    [4]byte
+	counter uint64
+}
+
+// openFrame decrypts the encrypted frame in place. On success, the frame is
+// rewritten to an unencrypted frame and the length of the plain frame is
+// returned.
+func openFrame(raw []byte, keys IngressKeys, remote *snet.UDPAddr) (int, sealInfo, error) {
+	if len(raw) < hdrLen+cryptoOverhead {
+		return 0, sealInfo{}, serrors.New("encrypted frame too short", "len", len(raw))
+	}
+	if keys == nil {
+		return 0, sealInfo{}, errNoKey
+	}
+	info := sealInfo{keyID: binary.BigEndian.Uint32(raw[hdrLen:])}
+	aead, ok := keys.IngressKey(remote, info.keyID)
+	if !ok {
+		return 0, sealInfo{}, serrors.WithCtx(errNoKey, "key_id", info.keyID)
+	}
+	nonce := raw[hdrLen+keyIDLen : hdrLen+cryptoHdrLen]
+	copy(info.salt[:], nonce[:4])
+	info.counter = binary.BigEndian.Uint64(nonce[4:])
+	ciphertext := raw[hdrLen+cryptoHdrLen:]
+	plain, err := aead.Open(ciphertext[:0], nonce, ciphertext, raw[:hdrLen+keyIDLen])
+	if err != nil {
+		return 0, sealInfo{}, err
+	}
+	copy(raw[hdrLen:], plain)
+	raw[versionPos] = plainVersion
+	return hdrLen + len(plain), info, nil
+}
+
+// replayFilter detects replayed frames. Every sender uses a random nonce salt
+// and a monotonically increasing nonce counter. Thus, the filter tracks a
+// replay window over the nonce counter for every remote, key and salt. The
+// remote is identified by its ISD-AS and IP address, the same way the ingress
+// key is looked up, such that a frame replayed from a different port is still
+// detected. The filter is shared by all workers and is safe for concurrent
+// use.
+type replayFilter struct {
+	mtx     sync.Mutex
+	windows map[replayKey]*replayWindow
+}
+
+type replayKey struct {
+	ia    addr.IA
+	ip    string
+	keyID uint32
+	salt  [4]byte
+}
+
+// replayWindow is the window over the nonce counters of a single sender.
+type replayWindow struct {
+	seqWindow
+	// lastUsed is the last time the window was used.
+	lastUsed time.Time
+}
+
+func newReplayFilter() *replayFilter {
+	return &replayFilter{windows: make(map[replayKey]*replayWindow)}
+}
+
+// Check returns true if the frame was not seen before, and marks it as seen.
+func (f *replayFilter) Check(remote *snet.UDPAddr, info sealInfo, now time.Time) bool {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	key := replayKey{
+		ia:    remote.IA,
+		ip:    remote.Host.IP.String(),
+		keyID: info.keyID,
+		salt:  info.salt,
+	}
+	window, ok := f.windows[key]
+	if !ok {
+		window = &replayWindow{}
+		f.windows[key] = window
+	}
+	window.lastUsed = now
+	return window.Check(info.counter)
+}
+
+// Cleanup removes the windows that have not been used for MaxKeyAge. At that
+// point, the corresponding key is no longer accepted and replayed frames fail
+// authentication.
+func (f *replayFilter) Cleanup(now time.Time) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for key, window := range f.windows {
+		if now.Sub(window.lastUsed) > MaxKeyAge {
+			delete(f.windows, key)
+		}
+	}
+}
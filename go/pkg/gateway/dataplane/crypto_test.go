@@ -0,0 +1,198 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/xtest"
+)
+
+type staticKeys struct {
+	id   uint32
+	aead cipher.AEAD
+}
+
+func newStaticKeys(t *testing.T, id uint32, key byte) staticKeys {
+	raw := make([]byte, 16)
+	for i := range raw {
+		raw[i] = key
+	}
+	block, err := aes.NewCipher(raw)
+	require.NoError(t, err)
+	aead, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	return staticKeys{id: id, aead: aead}
+}
+
+func (k staticKeys) EgressKey() (uint32, cipher.AEAD, bool) {
+	return k.id, k.aead, k.aead != nil
+}
+
+func (k staticKeys) IngressKey(_ *snet.UDPAddr, id uint32) (cipher.AEAD, bool) {
+	return k.aead, id == k.id
+}
+
+func (k staticKeys) Required(addr.IA) bool {
+	return true
+}
+
+func TestSealOpen(t *testing.T) {
+	remote := &snet.UDPAddr{
+		IA:   xtest.MustParseIA("1-ff00:0:300"),
+		Host: &net.UDPAddr{IP: net.IP{192, 168, 1, 1}, Port: 80},
+	}
+	frame := []byte{
+		// SIG frame header.
+		0, 1, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1,
+		// IPv4 header.
+		0x40, 0, 0, 23, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		// Payload.
+		101, 102, 103,
+	}
+	keys := newStaticKeys(t, 42, 1)
+
+	seal := func(t *testing.T) []byte {
+		sealer, err := newFrameSealer(keys, len(frame))
+		require.NoError(t, err)
+		sealed, err := sealer.Seal(frame)
+		require.NoError(t, err)
+		assert.Len(t, sealed, len(frame)+cryptoOverhead)
+		assert.Equal(t, uint8(encryptedVersion), sealed[versionPos])
+		// Copy, the sealed buffer is reused by the sealer.
+		return append([]byte(nil), sealed...)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		sealed := seal(t)
+		n, info, err := openFrame(sealed, keys, remote)
+		require.NoError(t, err)
+		assert.Equal(t, frame, sealed[:n])
+		assert.Equal(t, uint32(42), info.keyID)
+	})
+	t.Run("modified header", func(t *testing.T) {
+		sealed := seal(t)
+		sealed[seqPos+7] ^= 0xff
+		_, _, err := openFrame(sealed, keys, remote)
+		assert.Error(t, err)
+	})
+	t.Run("modified payload", func(t *testing.T) {
+		sealed := seal(t)
+		sealed[len(sealed)-tagLen-1] ^= 0xff
+		_, _, err := openFrame(sealed, keys, remote)
+		assert.Error(t, err)
+	})
+	t.Run("wrong key", func(t *testing.T) {
+		sealed := seal(t)
+		_, _, err := openFrame(sealed, newStaticKeys(t, 42, 2), remote)
+		assert.Error(t, err)
+	})
+	t.Run("unknown key", func(t *testing.T) {
+		sealed := seal(t)
+		_, _, err := openFrame(sealed, newStaticKeys(t, 43, 1), remote)
+		assert.True(t, errors.Is(err, errNoKey))
+	})
+	t.Run("too short", func(t *testing.T) {
+		sealed := seal(t)
+		_, _, err := openFrame(sealed[:hdrLen+cryptoOverhead-1], keys, remote)
+		assert.Error(t, err)
+	})
+}
+
+func TestReplayFilter(t *testing.T) {
+	newRemote := func(ia string, ip net.IP, port int) *snet.UDPAddr {
+		return &snet.UDPAddr{
+			IA:   xtest.MustParseIA(ia),
+			Host: &net.UDPAddr{IP: ip, Port: port},
+		}
+	}
+	remote := newRemote("1-ff00:0:300", net.IP{192, 168, 1, 1}, 80)
+	f := newReplayFilter()
+	now := time.Now()
+	info := sealInfo{keyID: 1, salt: [4]byte{1, 2, 3, 4}, counter: 10}
+	assert.True(t, f.Check(remote, info, now))
+	assert.False(t, f.Check(remote, info, now))
+	// The port is not part of the remote identity.
+	assert.False(t, f.Check(newRemote("1-ff00:0:300", net.IP{192, 168, 1, 1}, 81), info, now))
+	// Different salt, key, IP or ISD-AS use separate windows.
+	assert.True(t, f.Check(remote, sealInfo{keyID: 1, counter: 10}, now))
+	assert.True(t, f.Check(remote, sealInfo{keyID: 2, salt: info.salt, counter: 10}, now))
+	assert.True(t, f.Check(newRemote("1-ff00:0:300", net.IP{192, 168, 1, 2}, 80), info, now))
+	assert.True(t, f.Check(newRemote("1-ff00:0:301", net.IP{192, 168, 1, 1}, 80), info, now))
+
+	f.Cleanup(now.Add(MaxKeyAge / 2))
+	assert.False(t, f.Check(remote, info, now))
+	f.Cleanup(now.Add(MaxKeyAge + time.Second))
+	assert.Empty(t, f.windows)
+}
+
+func TestWorkerEncrypted(t *testing.T) {
+	remote := &snet.UDPAddr{
+		IA:   xtest.MustParseIA("1-ff00:0:300"),
+		Host: &net.UDPAddr{IP: net.IP{192, 168, 1, 1}, Port: 80},
+	}
+	keys := newStaticKeys(t, 1, 1)
+	sealer, err := newFrameSealer(keys, 1500)
+	require.NoError(t, err)
+	mt := &MockTun{}
+	replay := newReplayFilter()
+	w := newWorker(remote, 1, mt, keys, replay, IngressMetrics{})
+
+	sealed, err := sealer.Seal([]byte{
+		// SIG frame header.
+		0, 1, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1,
+		// IPv4 header.
+		0x40, 0, 0, 23, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		// Payload.
+		101, 102, 103,
+	})
+	require.NoError(t, err)
+	sealed = append([]byte(nil), sealed...)
+
+	SendFrame(t, w, sealed)
+	mt.AssertPacket(t, []byte{
+		// IPv4 header.
+		0x40, 0, 0, 23, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		// Payload.
+		101, 102, 103,
+	})
+	mt.AssertDone(t)
+
+	// Replayed frames are dropped.
+	SendFrame(t, w, sealed)
+	mt.AssertDone(t)
+
+	// Replayed frames are dropped even if they are sent from another port,
+	// which is handled by another worker.
+	other := remote.Copy()
+	other.Host.Port = 81
+	SendFrame(t, newWorker(other, 1, mt, keys, replay, IngressMetrics{}), sealed)
+	mt.AssertDone(t)
+
+	// Frames that fail authentication are dropped.
+	sealed[len(sealed)-1] ^= 0xff
+	SendFrame(t, w, sealed)
+	mt.AssertDone(t)
+}
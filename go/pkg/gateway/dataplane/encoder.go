@@ -26,13 +26,16 @@ import (
 //  +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
 //  |     Version   |    Session    |            Index              |
 //  +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
-//  |   Reserved (12 bits)    |          Stream (20 bits)           |
+//  |R| Reserved (11 bits)  |          Stream (20 bits)           |
 //  +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
 //  |                                                               |
 //  +                       Sequence number                         +
 //  |                                                               |
 //  +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
 //
+// The R (redundant) flag indicates that the frame is sent via multiple paths.
+// The receiver drops the duplicates of such frames.
+//
 // The header is followed by raw IP packets (or parts thereof) one directly
 // following another with no intermediate padding.
 
@@ -45,6 +48,8 @@ const (
 	indexPos   = 2
 	streamPos  = 4
 	seqPos     = 8
+	// redundantFlag is the R flag in the first byte of the stream field.
+	redundantFlag = 0x80
 )
 
 // encoder reads packets from a ring buffer and transforms them into SIG frames.
@@ -54,6 +59,8 @@ type encoder struct {
 	// streamID is identifies a flow within the session. Only the frames from
 	// the same streams are, on the remote side, put into the same reassembly queue.
 	streamID uint32
+	// redundant indicates that the frames are sent via multiple paths.
+	redundant bool
 	// ring is used to pass packets from the writer goroutine to the sending goroutine.
 	ring *pktRing
 	// seq is the next frame sequence number to use.
@@ -98,6 +105,9 @@ func (e *encoder) Read() []byte {
 	e.frame[sessPos] = uint8(e.sessionID)
 	binary.BigEndian.PutUint16(e.frame[indexPos:indexPos+2], 0xffff)
 	binary.BigEndian.PutUint32(e.frame[streamPos:streamPos+4], e.streamID&0xfffff)
+	if e.redundant {
+		e.frame[streamPos] |= redundantFlag
+	}
 	binary.BigEndian.PutUint64(e.frame[seqPos:seqPos+8], e.seq)
 	// Increase the sequence number.
 	e.seq++
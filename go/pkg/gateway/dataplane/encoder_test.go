@@ -50,6 +50,27 @@ func TestEncoder(t *testing.T) {
 		assert.Nil(t, f)
 	})
 
+	t.Run("redundant flag", func(t *testing.T) {
+		e := newEncoder(1, 2, 1500)
+		e.redundant = true
+		e.Write([]byte{
+			// IPv4 header.
+			0x40, 0, 0, 23, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+			// Payload.
+			1, 2, 3,
+		})
+		e.Close()
+		f := e.Read()
+		assert.EqualValues(t, []byte{
+			// SIG frame header.
+			0, 1, 0, 0, 0x80, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0,
+			// IPv4 header.
+			0x40, 0, 0, 23, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+			// Payload.
+			1, 2, 3,
+		}, f)
+	})
+
 	t.Run("simple IPv6 packet", func(t *testing.T) {
 		e := newEncoder(1, 2, 1500)
 		e.Write([]byte{
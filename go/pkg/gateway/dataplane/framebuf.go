@@ -50,6 +50,9 @@ type frameBuf struct {
 	sessId uint8
 	// Sequence number of the frame.
 	seqNr uint64
+	// Whether the frame is sent via multiple paths, i.e., duplicates of the
+	// frame are expected.
+	redundant bool
 	// Index of the frame.
 	index int
 	// Total length of the frame (including 16-byte header).
@@ -86,6 +89,7 @@ func newFrameBuf() *frameBuf {
 func (fb *frameBuf) Reset() {
 	fb.sessId = 0
 	fb.seqNr = 0xffffffffffffffff
+	fb.redundant = false
 	fb.index = -1
 	fb.frameLen = 0
 	fb.frag0Start = 0
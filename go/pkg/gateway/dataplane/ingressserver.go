@@ -60,6 +60,9 @@ type IngressMetrics struct {
 	SendLocalError metrics.Counter
 	// ReceiveExternalError is the error count when reading frames from the external network.
 	ReceiveExternalError metrics.Counter
+	// FramesAuthFailed is the count of encrypted frames that were dropped
+	// because they could not be authenticated.
+	FramesAuthFailed metrics.Counter
 }
 
 // IngressServer reads new encapsulated packets, classifies the packet by
@@ -69,12 +72,17 @@ type IngressServer struct {
 	Conn    ReadConn
 	TUN     io.Writer
 	Metrics IngressMetrics
+	// Keys provides the keys to decrypt encrypted frames. If nil, only
+	// unencrypted frames are accepted.
+	Keys IngressKeys
 
 	workers map[string]*worker
+	replay  *replayFilter
 }
 
 func (d *IngressServer) Run() error {
 	d.workers = make(map[string]*worker)
+	d.replay = newReplayFilter()
 	return d.read()
 }
 
@@ -102,11 +110,22 @@ func (d *IngressServer) read() error {
 						return serrors.New("frame too short",
 							"expected", sigHdrSize, "actual", read)
 					}
-					if frame.raw[0] != 0 {
+					switch frame.raw[0] {
+					case plainVersion:
+						if d.Keys != nil && d.Keys.Required(v.IA) {
+							metrics.CounterInc(metrics.CounterWith(d.Metrics.FramesDiscarded,
+								"remote_isd_as", v.IA.String(), "reason", "unencrypted"))
+							frame.Release()
+							frames[i] = nil
+							continue
+						}
+					case encryptedVersion:
+					default:
 						metrics.CounterInc(metrics.CounterWith(d.Metrics.FramesDiscarded,
 							"remote_isd_as", v.IA.String(), "reason", "invalid"))
 						return serrors.New("unsupported SIG protocol version",
-							"supported", 0, "actual", frame.raw[0])
+							"supported", []int{plainVersion, encryptedVersion},
+							"actual", frame.raw[0])
 					}
 					frame.frameLen = read
 					frame.sessId = uint8((frame.raw[1]))
@@ -137,7 +156,7 @@ func (d *IngressServer) dispatch(frame *frameBuf, src *snet.UDPAddr) {
 	worker, ok := d.workers[dispatchStr]
 	if !ok {
 		metrics := createWorkerMetrics(d.Metrics, src.IA.String())
-		worker = newWorker(src, frame.sessId, d.TUN, metrics)
+		worker = newWorker(src, frame.sessId, d.TUN, d.Keys, d.replay, metrics)
 		d.workers[dispatchStr] = worker
 		go func() {
 			defer log.HandlePanic()
@@ -159,11 +178,13 @@ func createWorkerMetrics(in IngressMetrics, remoteIALabel string) IngressMetrics
 		FramesRecv:          metrics.CounterWith(in.FramesRecv, labels...),
 		FramesDiscarded:     metrics.CounterWith(in.FramesDiscarded, labels...),
 		SendLocalError:      in.SendLocalError,
+		FramesAuthFailed:    metrics.CounterWith(in.FramesAuthFailed, labels...),
 	}
 }
 
 // cleanup periodically stops and releases idle workers.
 func (d *IngressServer) cleanup() {
+	d.replay.Cleanup(time.Now())
 	for key := range d.workers {
 		worker := d.workers[key]
 		if worker.markedForCleanup {
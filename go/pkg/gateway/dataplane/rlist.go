@@ -38,6 +38,10 @@ type reassemblyList struct {
 	duplicate         metrics.Counter
 	evicted           metrics.Counter
 	invalid           metrics.Counter
+	// seen tracks the sequence numbers of the received redundant frames. It is
+	// used to detect the duplicates of frames the remote sends on multiple
+	// paths.
+	seen seqWindow
 }
 
 // newReassemblyList returns a ReassemblyList object for the given epoch and with
@@ -67,6 +71,13 @@ func newReassemblyList(epoch int, capacity int, s ingressSender,
 // that involve the newly added frame. Completely processed frames get removed from the
 // list and released to the pool of frame buffers.
 func (l *reassemblyList) Insert(frame *frameBuf) {
+	// Drop redundant frames that were already received, even if they were
+	// already processed and removed from the list.
+	if frame.redundant && !l.seen.Check(frame.seqNr) {
+		increaseCounterMetric(l.duplicate, 1)
+		frame.Release()
+		return
+	}
 	// If this is the first frame, write all complete packets to the wire and
 	// add the frame to the reassembly list if it contains a fragment at the end.
 	if l.entries.Len() == 0 {
@@ -31,42 +31,79 @@ const (
 	udpHdrLen = 8
 )
 
-// sender handles sending traffic via one particular path.
+// sender handles sending traffic via one particular path. A redundant sender
+// sends every frame via multiple paths.
 type sender struct {
 	encoder            *encoder
+	sealer             *frameSealer
 	conn               net.PacketConn
-	address            net.Addr
 	pathStatsPublisher PathStatsPublisher
-	path               snet.Path
-	pathFingerprint    snet.PathFingerprint
-	metrics            SessionMetrics
+	// paths are the paths the frames are sent on. The first path is the
+	// primary path of the sender.
+	paths   []senderPath
+	metrics SessionMetrics
 }
 
-func newSender(sessID uint8, conn net.PacketConn, path snet.Path,
+// senderPath is a path used by a sender.
+type senderPath struct {
+	address     net.Addr
+	path        snet.Path
+	fingerprint snet.PathFingerprint
+}
+
+func newSender(sessID uint8, conn net.PacketConn, paths []snet.Path,
 	gatewayAddr net.UDPAddr, pathStatsPublisher PathStatsPublisher,
-	metrics SessionMetrics) (*sender, error) {
+	metrics SessionMetrics, keys EgressKeys) (*sender, error) {
 
-	// MTU must account for the size of the SCION header.
+	if len(paths) == 0 {
+		return nil, serrors.New("no path")
+	}
+	// MTU must account for the size of the SCION header. The frames of a
+	// redundant sender must fit all paths.
 	localAddr := conn.LocalAddr().(*net.UDPAddr)
 	addrLen := addr.IABytes*2 + len(localAddr.IP) + len(gatewayAddr.IP)
-	pathLen := len(path.Path().Raw)
-	mtu := int(path.Metadata().MTU) - slayers.CmnHdrLen - addrLen - pathLen - udpHdrLen
+	mtu := -1
+	senderPaths := make([]senderPath, 0, len(paths))
+	for _, path := range paths {
+		pathLen := len(path.Path().Raw)
+		pathMTU := int(path.Metadata().MTU) - slayers.CmnHdrLen - addrLen - pathLen - udpHdrLen
+		if mtu == -1 || pathMTU < mtu {
+			mtu = pathMTU
+		}
+		senderPaths = append(senderPaths, senderPath{
+			address: &snet.UDPAddr{
+				IA:      path.Destination(),
+				Path:    path.Path(),
+				NextHop: path.UnderlayNextHop(),
+				Host:    &gatewayAddr,
+			},
+			path:        path,
+			fingerprint: snet.Fingerprint(path),
+		})
+	}
+	// Encryption adds the crypto header and the authentication tag.
+	if keys != nil {
+		mtu -= cryptoOverhead
+	}
 	if mtu < minMTU {
 		return nil, serrors.New("insufficient MTU", "mtu", mtu, "minMTU", minMTU)
 	}
+	var sealer *frameSealer
+	if keys != nil {
+		var err error
+		if sealer, err = newFrameSealer(keys, mtu); err != nil {
+			return nil, err
+		}
+	}
 
+	encoder := newEncoder(sessID, NewStreamID(), uint16(mtu))
+	encoder.redundant = len(paths) > 1
 	c := &sender{
-		encoder: newEncoder(sessID, NewStreamID(), uint16(mtu)),
-		conn:    conn,
-		address: &snet.UDPAddr{
-			IA:      path.Destination(),
-			Path:    path.Path(),
-			NextHop: path.UnderlayNextHop(),
-			Host:    &gatewayAddr,
-		},
+		encoder:            encoder,
+		sealer:             sealer,
+		conn:               conn,
 		pathStatsPublisher: pathStatsPublisher,
-		path:               path,
-		pathFingerprint:    snet.Fingerprint(path),
+		paths:              senderPaths,
 		metrics:            metrics,
 	}
 	go func() {
@@ -97,19 +134,55 @@ func (c *sender) run() {
 			// Sender was closed and all the buffered frames were sent.
 			break
 		}
-		_, err := c.conn.WriteTo(frame, c.address)
-		if err != nil {
-			increaseCounterMetric(c.metrics.SendExternalErrors, 1)
-			continue
+		for _, p := range c.paths {
+			c.send(frame, p)
 		}
-		increaseCounterMetric(c.metrics.FramesSent, 1)
-		increaseCounterMetric(c.metrics.FrameBytesSent, float64(len(frame)))
+	}
+}
 
-		if c.pathStatsPublisher != nil {
-			c.pathStatsPublisher.PublishEgressStats(c.pathFingerprint.String(),
-				1, int64(len(frame)))
+// send sends the frame via the path. Encrypted frames are sealed separately
+// for every path, such that the duplicates of a redundant sender are not
+// mistaken for replayed frames.
+func (c *sender) send(frame []byte, p senderPath) {
+	if c.sealer != nil {
+		var err error
+		if frame, err = c.sealer.Seal(frame); err != nil {
+			increaseCounterMetric(c.metrics.EncryptErrors, 1)
+			return
+		}
+	}
+	_, err := c.conn.WriteTo(frame, p.address)
+	if err != nil {
+		increaseCounterMetric(c.metrics.SendExternalErrors, 1)
+		return
+	}
+	increaseCounterMetric(c.metrics.FramesSent, 1)
+	increaseCounterMetric(c.metrics.FrameBytesSent, float64(len(frame)))
+
+	if c.pathStatsPublisher != nil {
+		c.pathStatsPublisher.PublishEgressStats(p.fingerprint.String(),
+			1, int64(len(frame)))
+	}
+}
+
+// usesPaths returns true if the sender sends via exactly the given paths.
+func (c *sender) usesPaths(paths []snet.Path) bool {
+	if len(paths) != len(c.paths) {
+		return false
+	}
+	for _, path := range paths {
+		found := false
+		for _, p := range c.paths {
+			if pathsEqual(path, p.path) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
 		}
 	}
+	return true
 }
 
 func addrHdrLen(src net.IP, dst net.IP) int {
@@ -27,6 +27,7 @@ import (
 
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/mocks/net/mock_net"
+	"github.com/scionproto/scion/go/lib/snet"
 )
 
 var testIA addr.IA = addr.IA{I: 1, A: 2}
@@ -110,7 +111,8 @@ func TestSender(t *testing.T) {
 				IP:   net.IP{192, 168, 1, 2},
 				Port: 30041,
 			}
-			c, err := newSender(1, conn, createMockPath(ctrl, 256), addr, nil, SessionMetrics{})
+			c, err := newSender(1, conn, []snet.Path{createMockPath(ctrl, 256)}, addr, nil,
+				SessionMetrics{}, nil)
 			require.NoError(t, err)
 			defer c.Close()
 			if test.ExpFrames != 0 {
@@ -0,0 +1,79 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+// seqWindowSize is the number of sequence numbers tracked by a seqWindow.
+const seqWindowSize = 1024
+
+// seqWindow is a sliding window over monotonically increasing sequence
+// numbers, e.g., frame sequence numbers or nonce counters. It detects
+// duplicated and too old sequence numbers.
+type seqWindow struct {
+	// highest is the highest sequence number seen so far.
+	highest uint64
+	// initialized indicates whether any sequence number was seen.
+	initialized bool
+	// seen is a bitmap of the sequence numbers in the window. Bit i (modulo
+	// the window size) is set if sequence number i was seen.
+	seen [seqWindowSize / 64]uint64
+}
+
+// Check checks whether the sequence number is new and marks it as seen. It
+// returns false for sequence numbers that were already seen or that are too
+// old to be tracked.
+func (w *seqWindow) Check(counter uint64) bool {
+	if !w.initialized {
+		w.initialized = true
+		w.highest = counter
+		w.set(counter)
+		return true
+	}
+	switch {
+	case counter > w.highest:
+		// Advance the window and clear the bits of the skipped counters.
+		if counter-w.highest >= seqWindowSize {
+			w.seen = [seqWindowSize / 64]uint64{}
+		} else {
+			for i := w.highest + 1; i < counter; i++ {
+				w.clear(i)
+			}
+		}
+		w.highest = counter
+		w.set(counter)
+		return true
+	case w.highest-counter >= seqWindowSize:
+		return false
+	case w.isSet(counter):
+		return false
+	default:
+		w.set(counter)
+		return true
+	}
+}
+
+func (w *seqWindow) set(counter uint64) {
+	bit := counter % seqWindowSize
+	w.seen[bit/64] |= 1 << (bit % 64)
+}
+
+func (w *seqWindow) clear(counter uint64) {
+	bit := counter % seqWindowSize
+	w.seen[bit/64] &^= 1 << (bit % 64)
+}
+
+func (w *seqWindow) isSet(counter uint64) bool {
+	bit := counter % seqWindowSize
+	return w.seen[bit/64]&(1<<(bit%64)) != 0
+}
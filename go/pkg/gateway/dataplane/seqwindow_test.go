@@ -0,0 +1,40 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeqWindow(t *testing.T) {
+	var w seqWindow
+	assert.True(t, w.Check(100))
+	assert.False(t, w.Check(100))
+	assert.True(t, w.Check(98))
+	assert.False(t, w.Check(98))
+	assert.True(t, w.Check(99))
+	assert.True(t, w.Check(101))
+	// Move the window forward, old sequence numbers are rejected.
+	assert.True(t, w.Check(100+seqWindowSize))
+	assert.False(t, w.Check(100))
+	assert.True(t, w.Check(101+seqWindowSize/2))
+	assert.False(t, w.Check(101+seqWindowSize/2))
+	// Jump far ahead.
+	assert.True(t, w.Check(10*seqWindowSize))
+	assert.True(t, w.Check(10*seqWindowSize-1))
+	assert.False(t, w.Check(10*seqWindowSize-1))
+}
@@ -22,12 +22,15 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/gopacket"
 	"github.com/google/gopacket/layers"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/metrics"
 	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/pkg/gateway/control"
 )
 
 var (
@@ -38,6 +41,13 @@ type PathStatsPublisher interface {
 	PublishEgressStats(fingerprint string, frames int64, bytes int64)
 }
 
+// PathLatencies provides the measured latencies of paths.
+type PathLatencies interface {
+	// PathLatency returns the measured one-way latency of the path to the
+	// remote AS. If no latency was measured yet, ok is false.
+	PathLatency(remote addr.IA, fingerprint snet.PathFingerprint) (latency time.Duration, ok bool)
+}
+
 // SessionMetrics report traffic and error counters for a session. They must be instantiated with
 // the labels "remote_isd_as" and "policy_id".
 type SessionMetrics struct {
@@ -51,6 +61,9 @@ type SessionMetrics struct {
 	FrameBytesSent metrics.Counter
 	// SendExternalError is the error count when sending frames to the external network.
 	SendExternalErrors metrics.Counter
+	// EncryptErrors is the count of frames that were dropped because they
+	// could not be encrypted.
+	EncryptErrors metrics.Counter
 }
 
 type Session struct {
@@ -59,10 +72,23 @@ type Session struct {
 	DataPlaneConn      net.PacketConn
 	PathStatsPublisher PathStatsPublisher
 	Metrics            SessionMetrics
+	// EgressKeys provides the keys to encrypt the frames. If nil, the frames
+	// are sent unencrypted.
+	EgressKeys EgressKeys
+	// Multipath is the mode in which the paths of the session are used. If it
+	// is empty, the traffic is load balanced over the paths.
+	Multipath control.MultipathMode
+	// PathLatencies provides the measured path latencies for the
+	// weighted_latency mode. If nil, or if no latency was measured for a path,
+	// the latency in the path metadata is used.
+	PathLatencies PathLatencies
 
 	mutex sync.Mutex
 	// senders is a list of currently used senders.
 	senders []*sender
+	// weights are the cumulative weights of the senders in the weighted
+	// modes. The sender i is chosen for hashes in [weights[i-1], weights[i]).
+	weights []uint64
 }
 
 // Close signals that the session should close up its internal Connections. Close returns as
@@ -88,6 +114,12 @@ func (s *Session) Write(packet gopacket.Packet) {
 	}
 	// Choose the path based on the packet's quintuple.
 	hash := crc64.Checksum(extractQuintuple(packet), crcTable)
+	if len(s.weights) == len(s.senders) {
+		point := hash % s.weights[len(s.weights)-1]
+		index := sort.Search(len(s.weights), func(i int) bool { return s.weights[i] > point })
+		s.senders[index].Write(packet.Data())
+		return
+	}
 	index := hash % uint64(len(s.senders))
 	s.senders[index].Write(packet.Data())
 }
@@ -97,7 +129,9 @@ func (s *Session) String() string {
 	defer s.mutex.Unlock()
 	res := fmt.Sprintf("ID: %d", s.SessionID)
 	for _, snd := range s.senders {
-		res += fmt.Sprintf("\n    %v", snd.path)
+		for _, p := range snd.paths {
+			res += fmt.Sprintf("\n    %v", p.path)
+		}
 	}
 	return res
 }
@@ -113,10 +147,17 @@ func (s *Session) String() string {
 // could cause packets to be delivered out of order. Using new sender with new stream
 // ID causes creation of new reassemby queue on the remote side, thus avoiding the
 // reordering issues.
+//
+// In redundant mode, a single sender sends every frame via all the paths. The
+// sender is only replaced if the set of paths changes.
 func (s *Session) SetPaths(paths []snet.Path) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
+	if s.Multipath == control.MultipathRedundant {
+		return s.setRedundantPaths(paths)
+	}
+
 	created := make([]*sender, 0, len(paths))
 	reused := make(map[*sender]bool, len(s.senders))
 	for _, existingSender := range s.senders {
@@ -134,10 +175,11 @@ func (s *Session) SetPaths(paths []snet.Path) error {
 		newSender, err := newSender(
 			s.SessionID,
 			s.DataPlaneConn,
-			path,
+			[]snet.Path{path},
 			s.GatewayAddr,
 			s.PathStatsPublisher,
 			s.Metrics,
+			s.EgressKeys,
 		)
 		if err != nil {
 			// Collect newly created senders to avoid go routine leak.
@@ -161,16 +203,142 @@ func (s *Session) SetPaths(paths []snet.Path) error {
 	// Sort the paths to get a minimal amount of consistency,
 	// at least in the case when new paths are the same as old paths.
 	sort.Slice(newSenders, func(x, y int) bool {
-		return strings.Compare(string(newSenders[x].pathFingerprint),
-			string(newSenders[y].pathFingerprint)) == -1
+		return strings.Compare(string(newSenders[x].paths[0].fingerprint),
+			string(newSenders[y].paths[0].fingerprint)) == -1
 	})
 	s.senders = newSenders
+	s.weights = s.computeWeights()
 	return nil
 }
 
+func (s *Session) setRedundantPaths(paths []snet.Path) error {
+	s.weights = nil
+	if len(s.senders) == 1 && s.senders[0].usesPaths(paths) {
+		return nil
+	}
+	var newSenders []*sender
+	if len(paths) > 0 {
+		newSender, err := newSender(
+			s.SessionID,
+			s.DataPlaneConn,
+			paths,
+			s.GatewayAddr,
+			s.PathStatsPublisher,
+			s.Metrics,
+			s.EgressKeys,
+		)
+		if err != nil {
+			return err
+		}
+		newSenders = []*sender{newSender}
+	}
+	for _, existingSender := range s.senders {
+		existingSender.Close()
+	}
+	s.senders = newSenders
+	return nil
+}
+
+// computeWeights computes the cumulative weights of the senders for the
+// weighted modes. Senders for which no weight is known get the average weight
+// of the other senders. If no weight is known at all, or the mode is not
+// weighted, nil is returned and the traffic is balanced equally.
+func (s *Session) computeWeights() []uint64 {
+	var weightOf func(p senderPath) (uint64, bool)
+	switch s.Multipath {
+	case control.MultipathWeightedBandwidth:
+		weightOf = pathBandwidth
+	case control.MultipathWeightedLatency:
+		weightOf = s.inverseLatency
+	default:
+		return nil
+	}
+	if len(s.senders) < 2 {
+		return nil
+	}
+	weights := make([]uint64, len(s.senders))
+	var total, known uint64
+	for i, snd := range s.senders {
+		if w, ok := weightOf(snd.paths[0]); ok && w > 0 {
+			weights[i] = w
+			total += w
+			known++
+		}
+	}
+	if known == 0 {
+		return nil
+	}
+	var sum uint64
+	for i := range weights {
+		if weights[i] == 0 {
+			weights[i] = total / known
+		}
+		sum += weights[i]
+		weights[i] = sum
+	}
+	return weights
+}
+
+// pathBandwidth returns the bottleneck bandwidth of the path in kbit/s, as
+// announced in the path metadata.
+func pathBandwidth(p senderPath) (uint64, bool) {
+	meta := p.path.Metadata()
+	if meta == nil || len(meta.Bandwidth) == 0 {
+		return 0, false
+	}
+	var min uint64
+	for _, bw := range meta.Bandwidth {
+		if bw == 0 {
+			// Unknown bandwidth of a hop.
+			return 0, false
+		}
+		if min == 0 || bw < min {
+			min = bw
+		}
+	}
+	return min, true
+}
+
+// inverseLatency returns a weight that is inversely proportional to the
+// latency of the path. The measured latency is preferred over the latency in
+// the path metadata.
+func (s *Session) inverseLatency(p senderPath) (uint64, bool) {
+	latency, ok := time.Duration(0), false
+	if s.PathLatencies != nil {
+		latency, ok = s.PathLatencies.PathLatency(p.path.Destination(), p.fingerprint)
+	}
+	if !ok {
+		latency, ok = metadataLatency(p.path)
+	}
+	if !ok {
+		return 0, false
+	}
+	if latency < time.Microsecond {
+		latency = time.Microsecond
+	}
+	return uint64(time.Second / latency), true
+}
+
+// metadataLatency returns the sum of the hop latencies in the path metadata.
+func metadataLatency(path snet.Path) (time.Duration, bool) {
+	meta := path.Metadata()
+	if meta == nil || len(meta.Latency) == 0 {
+		return 0, false
+	}
+	var sum time.Duration
+	for _, l := range meta.Latency {
+		if l < 0 {
+			// Unknown latency of a hop.
+			return 0, false
+		}
+		sum += l
+	}
+	return sum, true
+}
+
 func findSenderWithPath(senders []*sender, path snet.Path) (*sender, bool) {
 	for _, s := range senders {
-		if pathsEqual(path, s.path) {
+		if pathsEqual(path, s.paths[0].path) {
 			return s, true
 		}
 	}
@@ -23,13 +23,16 @@ import (
 	"github.com/google/gopacket"
 	"github.com/google/gopacket/layers"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/mocks/net/mock_net"
 	"github.com/scionproto/scion/go/lib/snet"
 	"github.com/scionproto/scion/go/lib/snet/mock_snet"
 	"github.com/scionproto/scion/go/lib/spath"
 	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/pkg/gateway/control"
 )
 
 func TestNoPath(t *testing.T) {
@@ -83,6 +86,79 @@ func TestTwoPaths(t *testing.T) {
 	sess.Close()
 }
 
+func TestRedundantPaths(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	frameChan := make(chan ([]byte))
+	sess := createSession(t, ctrl, frameChan)
+	sess.Multipath = control.MultipathRedundant
+
+	paths := []snet.Path{createMockPath(ctrl, 200), createMockPath(ctrl, 202)}
+	assert.NoError(t, sess.SetPaths(paths))
+	require.Len(t, sess.senders, 1)
+	redundant := sess.senders[0]
+	// Setting the same paths keeps the sender.
+	assert.NoError(t, sess.SetPaths(paths))
+	assert.Same(t, redundant, sess.senders[0])
+
+	// Every frame is sent via both paths.
+	sendPackets(t, sess, 22, 10)
+	waitFrames(t, frameChan, 22, 20)
+	sess.Close()
+}
+
+func TestWeightedPaths(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	frameChan := make(chan ([]byte))
+	sess := createSession(t, ctrl, frameChan)
+
+	t.Run("bandwidth", func(t *testing.T) {
+		sess.Multipath = control.MultipathWeightedBandwidth
+		fast := createMockPathWithMetadata(ctrl, &snet.PathMetadata{
+			MTU:       200,
+			Bandwidth: []uint64{3000, 4000},
+		})
+		slow := createMockPathWithMetadata(ctrl, &snet.PathMetadata{
+			MTU:       201,
+			Bandwidth: []uint64{1000, 5000},
+		})
+		unknown := createMockPathWithMetadata(ctrl, &snet.PathMetadata{MTU: 202})
+		assert.NoError(t, sess.SetPaths([]snet.Path{fast, slow, unknown}))
+		assert.Equal(t, []uint64{3000, 1000, 2000}, senderWeights(sess, fast, slow, unknown))
+	})
+	t.Run("latency", func(t *testing.T) {
+		sess.Multipath = control.MultipathWeightedLatency
+		ia := xtest.MustParseIA("1-ff00:0:300")
+		measured := createMockPathWithMetadata(ctrl, &snet.PathMetadata{
+			MTU:        200,
+			Interfaces: []snet.PathInterface{{IA: ia, ID: 1}},
+			Latency:    []time.Duration{time.Second},
+		})
+		announced := createMockPathWithMetadata(ctrl, &snet.PathMetadata{
+			MTU:        201,
+			Interfaces: []snet.PathInterface{{IA: ia, ID: 2}},
+			Latency:    []time.Duration{10 * time.Millisecond, 15 * time.Millisecond},
+		})
+		sess.PathLatencies = staticLatencies{
+			snet.Fingerprint(measured): 100 * time.Millisecond,
+		}
+		assert.NoError(t, sess.SetPaths([]snet.Path{measured, announced}))
+		assert.Equal(t, []uint64{10, 40}, senderWeights(sess, measured, announced))
+	})
+	t.Run("no weights", func(t *testing.T) {
+		sess.Multipath = control.MultipathWeightedBandwidth
+		assert.NoError(t, sess.SetPaths([]snet.Path{
+			createMockPath(ctrl, 210),
+			createMockPath(ctrl, 211),
+		}))
+		assert.Nil(t, sess.weights)
+	})
+	sess.Close()
+}
+
 func TestNoLeak(t *testing.T) {
 	defer goleak.VerifyNone(t)
 
@@ -170,9 +246,12 @@ Top:
 }
 
 func createMockPath(ctrl *gomock.Controller, mtu uint16) snet.Path {
-	meta := &snet.PathMetadata{
+	return createMockPathWithMetadata(ctrl, &snet.PathMetadata{
 		MTU: mtu,
-	}
+	})
+}
+
+func createMockPathWithMetadata(ctrl *gomock.Controller, meta *snet.PathMetadata) snet.Path {
 	path := mock_snet.NewMockPath(ctrl)
 	path.EXPECT().Destination().Return(xtest.MustParseIA("1-ff00:0:300")).AnyTimes()
 	path.EXPECT().Metadata().Return(meta).AnyTimes()
@@ -181,3 +260,31 @@ func createMockPath(ctrl *gomock.Controller, mtu uint16) snet.Path {
 	path.EXPECT().Copy().Return(path).AnyTimes()
 	return path
 }
+
+// senderWeights returns the individual weights of the senders of the paths, in
+// the order of the paths.
+func senderWeights(sess *Session, paths ...snet.Path) []uint64 {
+	var weights []uint64
+	for _, path := range paths {
+		for i, snd := range sess.senders {
+			if !pathsEqual(snd.paths[0].path, path) {
+				continue
+			}
+			w := sess.weights[i]
+			if i > 0 {
+				w -= sess.weights[i-1]
+			}
+			weights = append(weights, w)
+		}
+	}
+	return weights
+}
+
+type staticLatencies map[snet.PathFingerprint]time.Duration
+
+func (l staticLatencies) PathLatency(_ addr.IA,
+	fingerprint snet.PathFingerprint) (time.Duration, bool) {
+
+	latency, ok := l[fingerprint]
+	return latency, ok
+}
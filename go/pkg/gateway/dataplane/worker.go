@@ -17,11 +17,13 @@ package dataplane
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"time"
 
 	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/metrics"
 	"github.com/scionproto/scion/go/lib/ringbuf"
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/snet"
@@ -50,10 +52,14 @@ type worker struct {
 	rlists           map[int]*reassemblyList
 	markedForCleanup bool
 	tunIO            io.Writer
+	// keys provides the keys to decrypt encrypted frames.
+	keys IngressKeys
+	// replay detects replayed encrypted frames.
+	replay *replayFilter
 }
 
-func newWorker(remote *snet.UDPAddr, sessID uint8,
-	tunIO io.Writer, metrics IngressMetrics) *worker {
+func newWorker(remote *snet.UDPAddr, sessID uint8, tunIO io.Writer, keys IngressKeys,
+	replay *replayFilter, metrics IngressMetrics) *worker {
 
 	worker := &worker{
 		Logger:  log.New("ingress", remote.String(), "sessId", sessID),
@@ -63,6 +69,9 @@ func newWorker(remote *snet.UDPAddr, sessID uint8,
 		rlists:  make(map[int]*reassemblyList),
 		tunIO:   tunIO,
 		Metrics: metrics,
+
+		keys:   keys,
+		replay: replay,
 	}
 
 	return worker
@@ -101,10 +110,32 @@ func (w *worker) Run() {
 // packets to the wire and then adding the frame to the corresponding reassembly
 // list if needed.
 func (w *worker) processFrame(frame *frameBuf) {
+	if frame.raw[versionPos] == encryptedVersion {
+		frameLen, info, err := openFrame(frame.raw[:frame.frameLen], w.keys, w.Remote)
+		switch {
+		case errors.Is(err, errNoKey):
+			metrics.CounterInc(metrics.CounterWith(w.Metrics.FramesDiscarded,
+				"reason", "no_key"))
+			frame.Release()
+			return
+		case err != nil:
+			increaseCounterMetric(w.Metrics.FramesAuthFailed, 1)
+			frame.Release()
+			return
+		}
+		if !w.replay.Check(w.Remote, info, time.Now()) {
+			metrics.CounterInc(metrics.CounterWith(w.Metrics.FramesDiscarded,
+				"reason", "replay"))
+			frame.Release()
+			return
+		}
+		frame.frameLen = frameLen
+	}
 	index := int(binary.BigEndian.Uint16(frame.raw[2:4]))
 	epoch := int(binary.BigEndian.Uint32(frame.raw[4:8]) & 0xfffff)
 	seqNr := binary.BigEndian.Uint64(frame.raw[8:16])
 	frame.seqNr = seqNr
+	frame.redundant = frame.raw[streamPos]&redundantFlag != 0
 	frame.index = index
 	frame.snd = w
 	// If index == 0 then we can be sure that there is no fragment at the beginning
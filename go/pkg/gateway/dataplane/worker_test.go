@@ -73,7 +73,7 @@ func TestParsing(t *testing.T) {
 		},
 	}
 	mt := &MockTun{}
-	w := newWorker(addr, 1, mt, IngressMetrics{})
+	w := newWorker(addr, 1, mt, nil, nil, IngressMetrics{})
 
 	// Single frame with a single IPv4 packet inside.
 	SendFrame(t, w, []byte{
@@ -318,4 +318,23 @@ func TestParsing(t *testing.T) {
 		51, 52, 53, 54, 55, 56, 57, 58, 59, 60,
 	})
 	mt.AssertDone(t)
+
+	// Duplicated frames with the redundant flag set are dropped.
+	for i := 0; i < 2; i++ {
+		SendFrame(t, w, []byte{
+			// SIG frame header.
+			0, 1, 0, 0, 0x80, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 19,
+			// IPv4 header.
+			0x40, 0, 0, 23, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+			// Payload.
+			101, 102, 103,
+		})
+	}
+	mt.AssertPacket(t, []byte{
+		// IPv4 header.
+		0x40, 0, 0, 23, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		// Payload.
+		101, 102, 103,
+	})
+	mt.AssertDone(t)
 }
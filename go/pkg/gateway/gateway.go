@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	quic "github.com/lucas-clemente/quic-go"
@@ -36,6 +37,7 @@ import (
 	"github.com/scionproto/scion/go/lib/sciond"
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/snet/addrutil"
 	"github.com/scionproto/scion/go/lib/snet/squic"
 	"github.com/scionproto/scion/go/lib/sock/reliable"
 	"github.com/scionproto/scion/go/lib/sock/reliable/reconnect"
@@ -48,6 +50,7 @@ import (
 	"github.com/scionproto/scion/go/pkg/gateway/pathhealth"
 	"github.com/scionproto/scion/go/pkg/gateway/pathhealth/policies"
 	"github.com/scionproto/scion/go/pkg/gateway/routing"
+	"github.com/scionproto/scion/go/pkg/gateway/tunnelkeys"
 	libgrpc "github.com/scionproto/scion/go/pkg/grpc"
 	gatewaypb "github.com/scionproto/scion/go/pkg/proto/gateway"
 	"github.com/scionproto/scion/go/pkg/service"
@@ -64,7 +67,11 @@ func (wf *WatcherFactory) New(remote addr.IA,
 	metrics control.GatewayWatcherMetrics) control.Runner {
 
 	pather := wf.PathMonitor.Register(remote, wf.Policies, 0)
-
+	fetcher := &controlgrpc.PrefixFetcher{
+		Remote: remote,
+		Dialer: wf.Dialer,
+		Pather: pather,
+	}
 	return &control.GatewayWatcher{
 		Remote: remote,
 		Discoverer: controlgrpc.Discoverer{
@@ -74,11 +81,8 @@ func (wf *WatcherFactory) New(remote addr.IA,
 		},
 		Template: control.PrefixWatcherConfig{
 			Consumer: wf.Aggregator,
-			Fetcher: &controlgrpc.PrefixFetcher{
-				Remote: remote,
-				Dialer: wf.Dialer,
-				Pather: pather,
-			},
+			Fetcher:  fetcher,
+			Streamer: fetcher,
 		},
 		Metrics: metrics,
 	}
@@ -88,10 +92,16 @@ type DataplaneSessionFactory struct {
 	PacketConnFactory  PacketConnFactory
 	PathStatsPublisher dataplane.PathStatsPublisher
 	Metrics            dataplane.SessionMetrics
+	// TunnelKeys provides the keys for encrypted sessions.
+	TunnelKeys *tunnelkeys.Store
+	// PathLatencies provides the measured path latencies for sessions in the
+	// weighted_latency multipath mode.
+	PathLatencies dataplane.PathLatencies
 }
 
 func (dpf DataplaneSessionFactory) New(id uint8, policyID int,
-	remoteIA addr.IA, remoteAddr net.Addr) control.DataplaneSession {
+	remoteIA addr.IA, remoteAddr net.Addr, encrypt bool,
+	multipath control.MultipathMode) control.DataplaneSession {
 
 	conn, err := dpf.PacketConnFactory.New()
 	if err != nil {
@@ -104,6 +114,7 @@ func (dpf DataplaneSessionFactory) New(id uint8, policyID int,
 		FrameBytesSent:     metrics.CounterWith(dpf.Metrics.FrameBytesSent, labels...),
 		FramesSent:         metrics.CounterWith(dpf.Metrics.FramesSent, labels...),
 		SendExternalErrors: dpf.Metrics.SendExternalErrors,
+		EncryptErrors:      metrics.CounterWith(dpf.Metrics.EncryptErrors, labels...),
 	}
 	sess := &dataplane.Session{
 		SessionID:          id,
@@ -111,6 +122,11 @@ func (dpf DataplaneSessionFactory) New(id uint8, policyID int,
 		DataPlaneConn:      conn,
 		PathStatsPublisher: dpf.PathStatsPublisher,
 		Metrics:            metrics,
+		Multipath:          multipath,
+		PathLatencies:      dpf.PathLatencies,
+	}
+	if encrypt {
+		sess.EgressKeys = dpf.TunnelKeys.Egress(remoteIA, sess.GatewayAddr.IP)
 	}
 	return sess
 }
@@ -155,10 +171,70 @@ func (ignoreSCMP) Handle(pkt *snet.Packet) error {
 // depending on the state of the last published routing policy file.
 type SelectAdvertisedRoutes struct {
 	ConfigPublisher *control.ConfigPublisher
+	// DynamicRoutes are the routes learned from BGP. They are advertised
+	// according to the redistribute-bgp rules of the routing policy. If nil, no
+	// routes are redistributed.
+	DynamicRoutes *control.DynamicRoutes
+
+	mtx sync.Mutex
+	// changes is closed on the next routing policy or dynamic route change. It
+	// is shared by all callers of Changes.
+	changes chan struct{}
 }
 
 func (a *SelectAdvertisedRoutes) AdvertiseList(from, to addr.IA) []*net.IPNet {
-	return routing.AdvertiseList(a.ConfigPublisher.RoutingPolicy(), from, to)
+	pol := a.ConfigPublisher.RoutingPolicy()
+	return append(routing.AdvertiseList(pol, from, to), a.redistributed(pol, from, to)...)
+}
+
+func (a *SelectAdvertisedRoutes) Advertisements(from, to addr.IA) []routing.Advertisement {
+	pol := a.ConfigPublisher.RoutingPolicy()
+	advertisements := routing.Advertisements(pol, from, to)
+	for _, prefix := range a.redistributed(pol, from, to) {
+		advertisements = append(advertisements, routing.Advertisement{Prefix: prefix})
+	}
+	return advertisements
+}
+
+// Changes returns a channel that is closed on the next change of the routing
+// policy or the dynamic routes. Until then, all callers get the same channel,
+// such that at most one goroutine waits for the changes, no matter how many
+// callers stop waiting.
+func (a *SelectAdvertisedRoutes) Changes() <-chan struct{} {
+	policyChanges := a.ConfigPublisher.RoutingPolicyChanges()
+	if a.DynamicRoutes == nil {
+		return policyChanges
+	}
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	if a.changes != nil {
+		select {
+		case <-a.changes:
+		default:
+			return a.changes
+		}
+	}
+	routeChanges := a.DynamicRoutes.Changes()
+	changes := make(chan struct{})
+	a.changes = changes
+	go func() {
+		defer log.HandlePanic()
+		select {
+		case <-policyChanges:
+		case <-routeChanges:
+		}
+		close(changes)
+	}()
+	return changes
+}
+
+func (a *SelectAdvertisedRoutes) redistributed(pol *routing.Policy,
+	from, to addr.IA) []*net.IPNet {
+
+	if a.DynamicRoutes == nil {
+		return nil
+	}
+	return routing.RedistributedBGP(pol, from, to, a.DynamicRoutes.Prefixes())
 }
 
 type RoutingPolicyPublisherAdapter struct {
@@ -198,6 +274,9 @@ type Gateway struct {
 	DataServerAddr *net.UDPAddr
 	// DataClientIP is the IP from which encapsulated data traffic is sent to other gateways.
 	DataClientIP net.IP
+	// TunnelRekeyInterval is the interval in which the keys of encrypted
+	// tunnels are rotated. If zero, the default interval is used.
+	TunnelRekeyInterval time.Duration
 
 	// DataIP is the IP that should be used for dataplane traffic.
 	DataAddr *net.UDPAddr
@@ -323,11 +402,46 @@ func (g *Gateway) Run() error {
 
 	legacySessionPolicyAdapter := &control.LegacySessionPolicyAdapter{}
 
-	// We know we have two subscribers, so we initialize the subscriptions right from the start.
-	// Once subscribed, publish immediately.
+	// We know we have three subscribers, so we initialize the subscriptions right from the
+	// start. Once subscribed, publish immediately.
 	configPublisher := &control.ConfigPublisher{}
 	remoteIAsChannel := configPublisher.SubscribeRemoteIAs()
 	sessionPoliciesChannel := configPublisher.SubscribeSessionPolicies()
+	encryptionPoliciesChannel := configPublisher.SubscribeSessionPolicies()
+
+	// The tunnel keys are derived from DRKeys provided by the Daemon. The
+	// session policies determine from which remote ASes encryption is required.
+	// The keys are bound to the host addresses, thus unspecified data-plane
+	// addresses are resolved to the address that remote gateways observe if
+	// the initial traffic policy enables encryption. Otherwise, the configured
+	// addresses are used as they are.
+	dataServerAddr, dataClientIP := g.DataServerAddr, g.DataClientIP
+	if g.encryptionEnabled(legacySessionPolicyAdapter) {
+		dataServerAddr = snet.CopyUDPAddr(g.DataServerAddr)
+		if len(dataServerAddr.IP) == 0 || dataServerAddr.IP.IsUnspecified() {
+			dataServerAddr.IP, err = addrutil.DefaultLocalIP(context.Background(), g.Daemon)
+			if err != nil {
+				return serrors.WrapStr("determining data server IP", err)
+			}
+		}
+		if len(dataClientIP) == 0 || dataClientIP.IsUnspecified() {
+			dataClientIP = dataServerAddr.IP
+		}
+	}
+	tunnelKeys := &tunnelkeys.Store{
+		Fetcher:       g.Daemon,
+		LocalIA:       localIA,
+		LocalIP:       dataServerAddr.IP,
+		EgressIP:      dataClientIP,
+		RekeyInterval: g.TunnelRekeyInterval,
+		Metrics:       CreateTunnelKeyMetrics(g.Metrics),
+	}
+	go func() {
+		defer log.HandlePanic()
+		for policies := range encryptionPoliciesChannel {
+			tunnelKeys.SetRequired(policies.EncryptedIAs())
+		}
+	}()
 
 	configLoader := config.Loader{
 		SessionPoliciesFile: g.TrafficPolicyFile,
@@ -509,6 +623,18 @@ func (g *Gateway) Run() error {
 	// Wrap in net.Listener for use with gRPC
 	quicServerListener := squic.NewConnListener(internalQUICServerListener)
 
+	// Routes received from the route consumer, e.g., learned from BGP, are
+	// advertised according to the redistribute-bgp rules.
+	var dynamicRoutes *control.DynamicRoutes
+	if g.RouteConsumerFactory != nil {
+		dynamicRoutes = &control.DynamicRoutes{}
+		consumer := g.RouteConsumerFactory.NewConsumer()
+		go func() {
+			defer log.HandlePanic()
+			dynamicRoutes.Run(consumer)
+		}()
+	}
+
 	var paMetric metrics.Gauge
 	if g.Metrics != nil {
 		paMetric = metrics.NewPromGauge(g.Metrics.PrefixesAdvertised)
@@ -520,6 +646,7 @@ func (g *Gateway) Run() error {
 			LocalIA: localIA,
 			Advertiser: &SelectAdvertisedRoutes{
 				ConfigPublisher: configPublisher,
+				DynamicRoutes:   dynamicRoutes,
 			},
 			PrefixesAdvertised: paMetric,
 		},
@@ -557,7 +684,7 @@ func (g *Gateway) Run() error {
 	dataplaneServerConn, err := scionNetwork.Listen(
 		context.TODO(),
 		"udp",
-		g.DataServerAddr,
+		dataServerAddr,
 		addr.SvcNone,
 	)
 	if err != nil {
@@ -567,6 +694,7 @@ func (g *Gateway) Run() error {
 	ingressServer := &dataplane.IngressServer{
 		Conn:    dataplaneServerConn,
 		TUN:     g.InternalDevice,
+		Keys:    tunnelKeys,
 		Metrics: ingressMetrics,
 	}
 	go func() {
@@ -618,9 +746,11 @@ func (g *Gateway) Run() error {
 			DataplaneSessionFactory: DataplaneSessionFactory{
 				PacketConnFactory: PacketConnFactory{
 					Network: scionNetwork,
-					Addr:    &net.UDPAddr{IP: g.DataClientIP},
+					Addr:    &net.UDPAddr{IP: dataClientIP},
 				},
-				Metrics: CreateSessionMetrics(g.Metrics),
+				Metrics:       CreateSessionMetrics(g.Metrics),
+				TunnelKeys:    tunnelKeys,
+				PathLatencies: pathMonitor.Monitor,
 			},
 			Logger: g.Logger,
 		},
@@ -646,7 +776,7 @@ func (g *Gateway) Run() error {
 	g.HTTPEndpoints["diagnostics/prefixwatcher"] = func(w http.ResponseWriter, _ *http.Request) {
 		remoteMonitor.DiagnosticsWrite(w)
 	}
-	g.HTTPEndpoints["diagnostics/sgrp"] = g.diagnosticsSGRP(configPublisher)
+	g.HTTPEndpoints["diagnostics/sgrp"] = g.diagnosticsSGRP(configPublisher, dynamicRoutes)
 	var fwMetrics dataplane.IPForwarderMetrics
 	if g.Metrics != nil {
 		fwMetrics.IPPktBytesLocalRecv = metrics.NewPromCounter(
@@ -686,11 +816,25 @@ func (g *Gateway) Run() error {
 	select {}
 }
 
-func (g *Gateway) diagnosticsSGRP(pub *control.ConfigPublisher) http.HandlerFunc {
+// encryptionEnabled indicates whether the traffic policy enables encryption
+// for any remote AS. If the traffic policy cannot be loaded, encryption is
+// considered disabled; the error is reported by the config loader.
+func (g *Gateway) encryptionEnabled(parser control.SessionPolicyParser) bool {
+	policies, err := control.LoadSessionPolicies(g.TrafficPolicyFile, parser)
+	if err != nil {
+		return false
+	}
+	return len(policies.EncryptedIAs()) > 0
+}
+
+func (g *Gateway) diagnosticsSGRP(pub *control.ConfigPublisher,
+	dynamicRoutes *control.DynamicRoutes) http.HandlerFunc {
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		var d struct {
 			Advertise struct {
-				Static []string `json:"static"`
+				Static  []string `json:"static"`
+				Dynamic []string `json:"dynamic"`
 			} `json:"advertise"`
 			Learned struct {
 				Dynamic []string `json:"dynamic"`
@@ -698,11 +842,17 @@ func (g *Gateway) diagnosticsSGRP(pub *control.ConfigPublisher) http.HandlerFunc
 		}
 		// Avoid null in json output.
 		d.Advertise.Static = []string{}
+		d.Advertise.Dynamic = []string{}
 		d.Learned.Dynamic = []string{}
 
 		for _, s := range routing.StaticAdvertised(pub.RoutingPolicy()) {
 			d.Advertise.Static = append(d.Advertise.Static, s.String())
 		}
+		if dynamicRoutes != nil {
+			for _, s := range dynamicRoutes.Prefixes() {
+				d.Advertise.Dynamic = append(d.Advertise.Dynamic, s.String())
+			}
+		}
 		if p, ok := g.RoutePublisherFactory.(interface{ Diagnostics() routemgr.Diagnostics }); ok {
 			for _, r := range p.Diagnostics().Routes {
 				d.Learned.Dynamic = append(d.Learned.Dynamic, r.Prefix.String())
@@ -742,6 +892,7 @@ func CreateIngressMetrics(m *Metrics) dataplane.IngressMetrics {
 		FramesDiscarded:      metrics.NewPromCounter(m.FramesDiscardedTotal),
 		SendLocalError:       metrics.NewPromCounter(m.SendLocalErrorsTotal),
 		ReceiveExternalError: metrics.NewPromCounter(m.ReceiveExternalErrorsTotal),
+		FramesAuthFailed:     metrics.NewPromCounter(m.FramesAuthFailedTotal),
 	}
 }
 
@@ -755,5 +906,16 @@ func CreateSessionMetrics(m *Metrics) dataplane.SessionMetrics {
 		FrameBytesSent:     metrics.NewPromCounter(m.FrameBytesSentTotal),
 		FramesSent:         metrics.NewPromCounter(m.FramesSentTotal),
 		SendExternalErrors: metrics.NewPromCounter(m.SendExternalErrorsTotal),
+		EncryptErrors:      metrics.NewPromCounter(m.FramesEncryptErrorsTotal),
+	}
+}
+
+// CreateTunnelKeyMetrics creates the metrics of the tunnel key store.
+func CreateTunnelKeyMetrics(m *Metrics) *tunnelkeys.Metrics {
+	if m == nil {
+		return nil
+	}
+	return &tunnelkeys.Metrics{
+		KeyFetches: metrics.NewPromCounter(m.TunnelKeyFetchesTotal),
 	}
 }
@@ -86,6 +86,21 @@ var (
 		Help:   "Total number of discarded frames received from remote gateways.",
 		Labels: []string{"remote_isd_as", "reason"},
 	}
+	FramesAuthFailedTotalMeta = MetricMeta{
+		Name:   "gateway_frames_auth_failed_total",
+		Help:   "Total number of encrypted frames from remote gateways that failed authentication.",
+		Labels: []string{"remote_isd_as"},
+	}
+	FramesEncryptErrorsTotalMeta = MetricMeta{
+		Name:   "gateway_frames_encrypt_errors_total",
+		Help:   "Total number of frames that were dropped because they could not be encrypted.",
+		Labels: []string{"remote_isd_as", "policy_id"},
+	}
+	TunnelKeyFetchesTotalMeta = MetricMeta{
+		Name:   "gateway_tunnel_key_fetches_total",
+		Help:   "Total number of tunnel key fetches.",
+		Labels: []string{"result"},
+	}
 	IPPktsDiscardedTotalMeta = MetricMeta{
 		Name:   "gateway_ippkts_discarded_total",
 		Help:   "Total number of discarded IP packets received from the local network.",
@@ -203,6 +218,11 @@ type Metrics struct {
 	ReceiveExternalErrorsTotal *prometheus.CounterVec
 	ReceiveLocalErrorsTotal    *prometheus.CounterVec
 
+	// Encryption Metrics
+	FramesAuthFailedTotal    *prometheus.CounterVec
+	FramesEncryptErrorsTotal *prometheus.CounterVec
+	TunnelKeyFetchesTotal    *prometheus.CounterVec
+
 	// Path Monitoring Metrics
 	PathsMonitored        *prometheus.GaugeVec
 	SessionPathsAvailable *prometheus.GaugeVec
@@ -237,6 +257,9 @@ func NewMetrics() *Metrics {
 		SendLocalErrorsTotal:         SendLocalErrorsTotalMeta.NewCounterVec(),
 		ReceiveExternalErrorsTotal:   ReceiveExternalErrorsTotalMeta.NewCounterVec(),
 		ReceiveLocalErrorsTotal:      ReceiveLocalErrorsTotalMeta.NewCounterVec(),
+		FramesAuthFailedTotal:        FramesAuthFailedTotalMeta.NewCounterVec(),
+		FramesEncryptErrorsTotal:     FramesEncryptErrorsTotalMeta.NewCounterVec(),
+		TunnelKeyFetchesTotal:        TunnelKeyFetchesTotalMeta.NewCounterVec(),
 		PathsMonitored:               PathsMonitoredMeta.NewGaugeVec(),
 		PathProbesSent:               PathProbesSentMeta.NewCounterVec(),
 		PathProbesReceived:           PathProbesReceivedMeta.NewCounterVec(),
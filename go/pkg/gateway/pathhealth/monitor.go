@@ -35,6 +35,8 @@ const (
 	defaultPathUpdateInterval = 10 * time.Second
 	// defaultProbeInterval specifies how often should path probes be sent.
 	defaultProbeInterval = 500 * time.Millisecond
+	// maxProbeRTT is the time after which a probe reply is no longer expected.
+	maxProbeRTT = 5 * time.Second
 )
 
 // RemoteWatcher watches multiple paths to a given remote.
@@ -161,6 +163,28 @@ func (m *Monitor) Register(remote addr.IA, selector PathSelector) *Registration
 	}
 }
 
+// PathLatency returns the measured one-way latency of the path with the given
+// fingerprint to the remote AS. If the path is not monitored or no latency was
+// measured yet, false is returned.
+func (m *Monitor) PathLatency(remote addr.IA,
+	fingerprint snet.PathFingerprint) (time.Duration, bool) {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	remoteWatcher := m.remoteWatchers[remote]
+	if remoteWatcher == nil {
+		return 0, false
+	}
+	for _, watcher := range remoteWatcher.Watchers() {
+		if snet.Fingerprint(watcher.Path()) != fingerprint {
+			continue
+		}
+		latency := watcher.State().Latency
+		return latency, latency != 0
+	}
+	return 0, false
+}
+
 // Close stops the path monitor.
 func (m *Monitor) Close() {
 	close(m.stopChannel)
@@ -97,13 +97,13 @@ func (pw *DefaultPathWatcher) SendProbe(conn snet.PacketConn, localAddr snet.SCI
 		log.SafeError(pw.logger, "Failed to send path probe", "err", err)
 		return
 	}
-	pw.pathState.sendProbe(time.Now())
+	pw.pathState.sendProbe(pw.nextSeq, time.Now())
 	pw.nextSeq++
 }
 
 // HandleProbeReply dispatches a single probe reply packet.
 func (pw *DefaultPathWatcher) HandleProbeReply(seq uint16) {
-	pw.pathState.receiveProbe(time.Now())
+	pw.pathState.receiveProbe(seq, time.Now())
 }
 
 // Path returns a fresh copy of the monitored path.
@@ -122,6 +122,7 @@ func (pw *DefaultPathWatcher) State() State {
 	}
 	return State{
 		IsAlive: pw.pathState.active(),
+		Latency: pw.pathState.latency(),
 	}
 }
 
@@ -174,11 +175,25 @@ type pathState struct {
 	mu                sync.Mutex
 	consecutiveProbes int
 	lastReceived      time.Time
+	// sent contains the send time of the outstanding probes by sequence number.
+	sent map[uint16]time.Time
+	// rtt is the smoothed round-trip time of the probes.
+	rtt time.Duration
 }
 
-func (s *pathState) sendProbe(now time.Time) {
+func (s *pathState) sendProbe(seq uint16, now time.Time) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if s.sent == nil {
+		s.sent = make(map[uint16]time.Time)
+	}
+	// Forget about probes that are not going to be answered anymore.
+	for k, t := range s.sent {
+		if now.Sub(t) > maxProbeRTT {
+			delete(s.sent, k)
+		}
+	}
+	s.sent[seq] = now
 	// Probe timed out.
 	if s.lastReceived.Add(defaultProbeInterval * 2).Before(now) {
 		s.consecutiveProbes = 0
@@ -186,13 +201,33 @@ func (s *pathState) sendProbe(now time.Time) {
 	}
 }
 
-func (s *pathState) receiveProbe(now time.Time) {
+func (s *pathState) receiveProbe(seq uint16, now time.Time) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.lastReceived = now
 	if s.consecutiveProbes < 3 {
 		s.consecutiveProbes++
 	}
+	sent, ok := s.sent[seq]
+	if !ok {
+		return
+	}
+	delete(s.sent, seq)
+	// Smooth the round-trip time the same way TCP does (RFC 6298).
+	sample := now.Sub(sent)
+	if s.rtt == 0 {
+		s.rtt = sample
+	} else {
+		s.rtt = s.rtt - s.rtt/8 + sample/8
+	}
+}
+
+// latency returns the estimated one-way latency of the path. It is zero if no
+// probe reply was received yet.
+func (s *pathState) latency() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rtt / 2
 }
 
 func (s *pathState) active() bool {
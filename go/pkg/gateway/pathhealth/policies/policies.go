@@ -60,4 +60,7 @@ type Policies struct {
 	PerfPolicy PerfPolicy
 	// PathCount is the max number of paths to return to the user. Defaults to 1.
 	PathCount int
+	// Disjoint indicates that paths that do not share any interface should be
+	// preferred.
+	Disjoint bool
 }
@@ -16,6 +16,7 @@ package pathhealth
 
 import (
 	"sync"
+	"time"
 
 	"github.com/scionproto/scion/go/lib/snet"
 )
@@ -27,6 +28,9 @@ type State struct {
 	// IsExpired indicates that the path is expired. IsExpired == true implies IsAlive == false but
 	// not vice versa.
 	IsExpired bool
+	// Latency is the one-way latency of the path estimated from the round-trip
+	// time of the probes. It is zero if it is not known yet.
+	Latency time.Duration
 }
 
 // Selectable is a subset of the PathWatcher that is used for path selection.
@@ -19,6 +19,8 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
 	"github.com/scionproto/scion/go/lib/snet"
 )
 
@@ -42,6 +44,10 @@ type FilteringPathSelector struct {
 	RevocationStore
 	// PathCount is the max number of paths to return to the user. Defaults to 1.
 	PathCount int
+	// Disjoint indicates that paths that do not share any interface with the
+	// already selected paths are preferred. Paths sharing interfaces are only
+	// selected if not enough disjoint paths are available.
+	Disjoint bool
 }
 
 // Select selects the best paths.
@@ -121,9 +127,15 @@ func (f *FilteringPathSelector) Select(selectables []Selectable, current Fingerp
 		pathCount = len(allowed)
 	}
 
-	paths := make([]snet.Path, 0, pathCount)
-	for i := 0; i < pathCount; i++ {
-		paths = append(paths, allowed[i].Path)
+	candidates := make([]snet.Path, 0, len(allowed))
+	for _, a := range allowed {
+		candidates = append(candidates, a.Path)
+	}
+	var paths []snet.Path
+	if f.Disjoint {
+		paths = selectDisjoint(candidates, pathCount)
+	} else {
+		paths = candidates[:pathCount]
 	}
 	return Selection{
 		Paths:         paths,
@@ -134,6 +146,52 @@ func (f *FilteringPathSelector) Select(selectables []Selectable, current Fingerp
 	}
 }
 
+// selectDisjoint selects count paths from the ordered candidates. Paths that do
+// not share an interface with the already selected paths are preferred, the
+// remaining paths are filled up in the order of the candidates.
+func selectDisjoint(candidates []snet.Path, count int) []snet.Path {
+	type intf struct {
+		ia addr.IA
+		id common.IFIDType
+	}
+	used := make(map[intf]struct{})
+	selected := make([]bool, len(candidates))
+	paths := make([]snet.Path, 0, count)
+	for i, path := range candidates {
+		if len(paths) == count {
+			break
+		}
+		meta := path.Metadata()
+		if meta == nil {
+			continue
+		}
+		disjoint := true
+		for _, pi := range meta.Interfaces {
+			if _, ok := used[intf{ia: pi.IA, id: pi.ID}]; ok {
+				disjoint = false
+				break
+			}
+		}
+		if !disjoint {
+			continue
+		}
+		for _, pi := range meta.Interfaces {
+			used[intf{ia: pi.IA, id: pi.ID}] = struct{}{}
+		}
+		selected[i] = true
+		paths = append(paths, path)
+	}
+	for i, path := range candidates {
+		if len(paths) == count {
+			break
+		}
+		if !selected[i] {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
 // isPathAllowed returns true is path is allowed by the policy.
 func isPathAllowed(policy PathPolicy, path snet.Path) bool {
 	if policy == nil {
@@ -0,0 +1,96 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathhealth_test
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/lib/xtest/graph"
+	"github.com/scionproto/scion/go/lib/xtest/graph/pathprovider"
+	"github.com/scionproto/scion/go/pkg/gateway/pathhealth"
+)
+
+type selectable struct {
+	path snet.Path
+}
+
+func (s selectable) Path() snet.Path {
+	return s.path
+}
+
+func (s selectable) State() pathhealth.State {
+	return pathhealth.State{IsAlive: true}
+}
+
+func TestFilteringPathSelectorDisjoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	paths := pathprovider.New(graph.NewDefaultGraph(ctrl)).GetPaths(
+		xtest.MustParseIA("1-ff00:0:110"), xtest.MustParseIA("1-ff00:0:111"))
+	require.True(t, len(paths) > 2)
+	// The second shortest path is left out, such that the shortest paths
+	// after the first one all share an interface with it. Only longer paths
+	// through 2-ff00:0:210 are disjoint.
+	selectables := []pathhealth.Selectable{selectable{path: paths[0]}}
+	for _, path := range paths[2:] {
+		selectables = append(selectables, selectable{path: path})
+	}
+	shareInterface := func(a, b snet.Path) bool {
+		for _, x := range a.Metadata().Interfaces {
+			for _, y := range b.Metadata().Interfaces {
+				if x == y {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	t.Run("shortest paths without disjoint", func(t *testing.T) {
+		s := &pathhealth.FilteringPathSelector{
+			RevocationStore: &pathhealth.MemoryRevocationStore{},
+			PathCount:       2,
+		}
+		sel := s.Select(selectables, nil)
+		require.Len(t, sel.Paths, 2)
+		assert.True(t, shareInterface(sel.Paths[0], sel.Paths[1]))
+	})
+	t.Run("disjoint paths are preferred", func(t *testing.T) {
+		s := &pathhealth.FilteringPathSelector{
+			RevocationStore: &pathhealth.MemoryRevocationStore{},
+			PathCount:       2,
+			Disjoint:        true,
+		}
+		sel := s.Select(selectables, nil)
+		require.Len(t, sel.Paths, 2)
+		assert.False(t, shareInterface(sel.Paths[0], sel.Paths[1]))
+	})
+	t.Run("filled up with overlapping paths", func(t *testing.T) {
+		s := &pathhealth.FilteringPathSelector{
+			RevocationStore: &pathhealth.MemoryRevocationStore{},
+			PathCount:       3,
+			Disjoint:        true,
+		}
+		sel := s.Select(selectables, nil)
+		require.Len(t, sel.Paths, 3)
+		assert.False(t, shareInterface(sel.Paths[0], sel.Paths[1]))
+	})
+}
@@ -26,12 +26,64 @@ func AdvertiseList(pol *Policy, from, to addr.IA) []*net.IPNet {
 	return extractList(pol, from, to, Advertise)
 }
 
+// Advertisement is an advertised prefix together with its attributes.
+type Advertisement struct {
+	Prefix     *net.IPNet
+	Attributes PrefixAttributes
+}
+
+// Advertisements returns the prefixes to advertise for the given policy and
+// ISD-ASes, together with the attributes of the rules that advertise them.
+func Advertisements(pol *Policy, from, to addr.IA) []Advertisement {
+	if pol == nil {
+		return []Advertisement{}
+	}
+	var advertisements []Advertisement
+	for _, r := range pol.Rules {
+		if r.Action != Advertise || !r.From.Match(from) || !r.To.Match(to) {
+			continue
+		}
+		m, ok := r.Network.(allowedNetworkMatcher)
+		if !ok {
+			continue
+		}
+		for _, prefix := range m.Allowed {
+			advertisements = append(advertisements, Advertisement{
+				Prefix:     prefix,
+				Attributes: r.Attributes,
+			})
+		}
+	}
+	return advertisements
+}
+
 // AllowedPrefixesBGP returns the list of prefixes that are allowed to be
 // redistributed from BGP.
 func AllowedPrefixesBGP(pol *Policy, from, to addr.IA) []*net.IPNet {
 	return extractList(pol, from, to, RedistributeBGP)
 }
 
+// RedistributedBGP returns the prefixes learned from BGP that are advertised to
+// the remote according to the redistribute-bgp rules of the policy. A prefix is
+// advertised if it matches the network matcher of any matching rule.
+func RedistributedBGP(pol *Policy, from, to addr.IA, learned []*net.IPNet) []*net.IPNet {
+	if pol == nil {
+		return []*net.IPNet{}
+	}
+	var nets []*net.IPNet
+	for _, prefix := range learned {
+		for _, r := range pol.Rules {
+			if r.Action == RedistributeBGP && r.From.Match(from) && r.To.Match(to) &&
+				r.Network.Match(prefix) {
+
+				nets = append(nets, prefix)
+				break
+			}
+		}
+	}
+	return nets
+}
+
 func extractList(pol *Policy, from, to addr.IA, action Action) []*net.IPNet {
 	if pol == nil {
 		return []*net.IPNet{}
@@ -52,6 +52,53 @@ func TestAdvertiseList(t *testing.T) {
 	assert.Empty(t, routing.AdvertiseList(&policy, to, from))
 }
 
+func TestAdvertisements(t *testing.T) {
+	from := addr.IA{I: 1}
+	to := addr.IA{I: 2}
+
+	assert.Empty(t, routing.Advertisements(nil, from, to))
+
+	policy := routing.Policy{
+		DefaultAction: routing.Reject,
+		Rules: []routing.Rule{
+			{
+				Action:     routing.Advertise,
+				From:       routing.NewIAMatcher(t, "1-0"),
+				To:         routing.NewIAMatcher(t, "2-0"),
+				Network:    routing.NewNetworkMatcher(t, "127.1.0.0/30,10.0.0.0/16"),
+				Attributes: routing.PrefixAttributes{Metric: 5, Tags: []string{"a"}},
+			},
+			{
+				Action:  routing.Advertise,
+				From:    routing.NewIAMatcher(t, "1-0"),
+				To:      routing.NewIAMatcher(t, "2-0"),
+				Network: routing.NewNetworkMatcher(t, "10.1.0.0/16"),
+			},
+			{
+				Action:     routing.Advertise,
+				From:       routing.NewIAMatcher(t, "2-0"),
+				To:         routing.NewIAMatcher(t, "1-0"),
+				Network:    routing.NewNetworkMatcher(t, "10.2.0.0/16"),
+				Attributes: routing.PrefixAttributes{Metric: 1},
+			},
+		},
+	}
+	attrs := routing.PrefixAttributes{Metric: 5, Tags: []string{"a"}}
+	assert.ElementsMatch(t, []routing.Advertisement{
+		{
+			Prefix:     &net.IPNet{IP: net.ParseIP("127.1.0.0").To4(), Mask: net.CIDRMask(30, 32)},
+			Attributes: attrs,
+		},
+		{
+			Prefix:     &net.IPNet{IP: net.ParseIP("10.0.0.0").To4(), Mask: net.CIDRMask(16, 32)},
+			Attributes: attrs,
+		},
+		{
+			Prefix: &net.IPNet{IP: net.ParseIP("10.1.0.0").To4(), Mask: net.CIDRMask(16, 32)},
+		},
+	}, routing.Advertisements(&policy, from, to))
+}
+
 func TestRedistributeBGPList(t *testing.T) {
 	from := addr.IA{I: 1}
 	to := addr.IA{I: 2}
@@ -80,6 +127,43 @@ func TestRedistributeBGPList(t *testing.T) {
 	assert.Empty(t, routing.AllowedPrefixesBGP(&policy, to, from))
 }
 
+func TestRedistributedBGP(t *testing.T) {
+	from := addr.IA{I: 1}
+	to := addr.IA{I: 2}
+	learned := []*net.IPNet{
+		{IP: net.ParseIP("10.0.1.0").To4(), Mask: net.CIDRMask(24, 32)},
+		{IP: net.ParseIP("10.1.0.0").To4(), Mask: net.CIDRMask(16, 32)},
+		{IP: net.ParseIP("127.1.0.0").To4(), Mask: net.CIDRMask(30, 32)},
+	}
+
+	assert.Empty(t, routing.RedistributedBGP(nil, from, to, learned))
+
+	policy := routing.Policy{DefaultAction: routing.Reject}
+	policy.Rules = append(policy.Rules, routing.Rule{
+		Action:  routing.Advertise,
+		From:    routing.NewIAMatcher(t, "1-0"),
+		To:      routing.NewIAMatcher(t, "2-0"),
+		Network: routing.NewNetworkMatcher(t, "10.0.0.0/8"),
+	})
+	assert.Empty(t, routing.RedistributedBGP(&policy, from, to, learned))
+
+	policy.Rules = append(policy.Rules, routing.Rule{
+		Action:  routing.RedistributeBGP,
+		From:    routing.NewIAMatcher(t, "1-0"),
+		To:      routing.NewIAMatcher(t, "2-0"),
+		Network: routing.NewNetworkMatcher(t, "10.0.0.0/16"),
+	})
+	policy.Rules = append(policy.Rules, routing.Rule{
+		Action:  routing.RedistributeBGP,
+		From:    routing.NewIAMatcher(t, "1-0"),
+		To:      routing.NewIAMatcher(t, "2-0"),
+		Network: routing.NewNetworkMatcher(t, "!10.0.0.0/8"),
+	})
+	assert.Equal(t, []*net.IPNet{learned[0], learned[2]},
+		routing.RedistributedBGP(&policy, from, to, learned))
+	assert.Empty(t, routing.RedistributedBGP(&policy, to, from, learned))
+}
+
 func TestStaticAdvertiseList(t *testing.T) {
 	policy := routing.Policy{DefaultAction: routing.Reject}
 
@@ -34,6 +34,8 @@
 //  accept    <a> <b> <prefixes>: <b> accepts the IP prefixes <prefixes> from <a>.
 //  reject    <a> <b> <prefixes>: <b> rejects the IP prefixes <prefixes> from <a>.
 //  advertise <a> <b> <prefixes>: <a> advertists the IP prefixes <prefixes> to <b>.
+//  redistribute-bgp <a> <b> <prefixes>: <a> advertises the IP prefixes learned from
+//                                       its BGP neighbors that match <prefixes> to <b>.
 //
 // The remaining three columns define the matchers of a rule. The second and
 // third column are ISD-AS matchers, the forth column is a prefix matcher.
@@ -61,4 +63,14 @@
 //  !10.0.1.0/24,10.0.2.0/24   Matches all IP prefixes that are not a subset of 10.0.1.0/24 and
 //                             not a subset of 10.0.2.0/24.
 //
+// Advertise rules can optionally carry attributes for the advertised prefixes.
+// The attributes are appended as key=value columns after the network prefix
+// matcher:
+//
+//  advertise    1-ff00:0:112    1-ff00:0:110    10.0.9.0/8    metric=10 tags=dc1,backup
+//
+// The metric is the cost of reaching the prefixes via the advertising gateway.
+// If multiple gateways advertise the same prefix, the remote gateway prefers
+// the one with the lowest metric. Tags are opaque labels.
+//
 package routing
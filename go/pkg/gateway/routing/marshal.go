@@ -19,6 +19,7 @@ import (
 	"bytes"
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 
@@ -31,8 +32,17 @@ func (p Policy) MarshalText() ([]byte, error) {
 	var buf bytes.Buffer
 	writer := tabwriter.NewWriter(&buf, 0, 0, 4, ' ', 0)
 
+	// The attributes column is only written if any rule has attributes, such
+	// that policies without attributes keep their layout.
+	var withAttributes bool
+	for _, rule := range p.Rules {
+		withAttributes = withAttributes || !rule.Attributes.IsZero()
+	}
 	for _, rule := range p.Rules {
 		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t", rule.Action, rule.From, rule.To, rule.Network)
+		if withAttributes {
+			fmt.Fprintf(writer, "%s\t", rule.Attributes)
+		}
 		if len(rule.Comment) != 0 {
 			fmt.Fprintf(writer, "# %s", rule.Comment)
 		}
@@ -71,7 +81,7 @@ func parseRule(b []byte) (Rule, error) {
 		b = b[:commentIndex]
 	}
 	columns := bytes.Fields(b)
-	if len(columns) != 4 {
+	if len(columns) < 4 {
 		return Rule{}, serrors.New("invalid number of columns", "columns", len(columns))
 	}
 
@@ -91,15 +101,50 @@ func parseRule(b []byte) (Rule, error) {
 	if err != nil {
 		return Rule{}, serrors.WrapStr("parsing 'network'", err, "input", string(columns[3]))
 	}
+	attributes, err := parseAttributes(columns[4:])
+	if err != nil {
+		return Rule{}, serrors.WrapStr("parsing attributes", err)
+	}
+	if !attributes.IsZero() && action != Advertise {
+		return Rule{}, serrors.New("attributes are only allowed for advertise rules",
+			"action", action)
+	}
 	return Rule{
-		Action:  action,
-		To:      toMatcher,
-		From:    fromMatcher,
-		Network: networkMatcher,
-		Comment: comment,
+		Action:     action,
+		To:         toMatcher,
+		From:       fromMatcher,
+		Network:    networkMatcher,
+		Attributes: attributes,
+		Comment:    comment,
 	}, nil
 }
 
+// parseAttributes parses the optional attribute columns. Every column has the
+// form key=value.
+func parseAttributes(columns [][]byte) (PrefixAttributes, error) {
+	var attributes PrefixAttributes
+	for _, column := range columns {
+		kv := strings.SplitN(string(column), "=", 2)
+		if len(kv) != 2 {
+			return PrefixAttributes{}, serrors.New("attribute must be key=value",
+				"input", string(column))
+		}
+		switch kv[0] {
+		case "metric":
+			metric, err := strconv.ParseUint(kv[1], 10, 32)
+			if err != nil {
+				return PrefixAttributes{}, serrors.WrapStr("parsing metric", err)
+			}
+			attributes.Metric = uint32(metric)
+		case "tags":
+			attributes.Tags = strings.Split(kv[1], ",")
+		default:
+			return PrefixAttributes{}, serrors.New("unknown attribute", "key", kv[0])
+		}
+	}
+	return attributes, nil
+}
+
 func parseIAMatcher(b []byte) (IAMatcher, error) {
 	var negative bool
 	if bytes.HasPrefix(b, []byte("!")) {
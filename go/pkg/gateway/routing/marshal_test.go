@@ -104,6 +104,37 @@ func testPolicies(t *testing.T) map[string]routing.Policy {
 				},
 			},
 		},
+		"attributes.policy": {
+			Rules: []routing.Rule{
+				{
+					Action:  routing.Advertise,
+					From:    routing.NewIAMatcher(t, "1-ff00:0:110"),
+					To:      routing.NewIAMatcher(t, "1-0"),
+					Network: routing.NewNetworkMatcher(t, "10.1.0.0/16"),
+					Attributes: routing.PrefixAttributes{
+						Metric: 10,
+						Tags:   []string{"primary", "site-a"},
+					},
+					Comment: "Preferred",
+				},
+				{
+					Action:  routing.Advertise,
+					From:    routing.NewIAMatcher(t, "1-ff00:0:110"),
+					To:      routing.NewIAMatcher(t, "1-0"),
+					Network: routing.NewNetworkMatcher(t, "10.2.0.0/16"),
+					Attributes: routing.PrefixAttributes{
+						Metric: 100,
+					},
+				},
+				{
+					Action:  routing.Accept,
+					From:    routing.NewIAMatcher(t, "1-0"),
+					To:      routing.NewIAMatcher(t, "1-ff00:0:110"),
+					Network: routing.NewNetworkMatcher(t, "10.3.0.0/16"),
+					Comment: "No attributes",
+				},
+			},
+		},
 		"ipv6.policy": {
 			Rules: []routing.Rule{
 				{
@@ -152,6 +183,33 @@ func TestParseRule(t *testing.T) {
 			},
 			ErrAssertion: assert.NoError,
 		},
+		"advertise with attributes": {
+			Input: []byte("advertise 1-ff00:0:110 1-ff00:0:111 10.0.0.0/8 metric=10 tags=a,b # C"),
+			Expected: routing.Rule{
+				Action:  routing.Advertise,
+				From:    routing.NewIAMatcher(t, "1-ff00:0:110"),
+				To:      routing.NewIAMatcher(t, "1-ff00:0:111"),
+				Network: routing.NewNetworkMatcher(t, "10.0.0.0/8"),
+				Attributes: routing.PrefixAttributes{
+					Metric: 10,
+					Tags:   []string{"a", "b"},
+				},
+				Comment: "C",
+			},
+			ErrAssertion: assert.NoError,
+		},
+		"attributes on accept": {
+			Input:        []byte("accept 1-ff00:0:110 1-ff00:0:111 10.0.0.0/8 metric=10"),
+			ErrAssertion: assert.Error,
+		},
+		"unknown attribute": {
+			Input:        []byte("advertise 1-ff00:0:110 1-ff00:0:111 10.0.0.0/8 color=red"),
+			ErrAssertion: assert.Error,
+		},
+		"invalid metric": {
+			Input:        []byte("advertise 1-ff00:0:110 1-ff00:0:111 10.0.0.0/8 metric=-1"),
+			ErrAssertion: assert.Error,
+		},
 		"missing column": {
 			Input:        []byte("reject 1-ff00:0:110 1-ff00:0:111"),
 			ErrAssertion: assert.Error,
@@ -17,6 +17,7 @@ package routing
 import (
 	"fmt"
 	"net"
+	"strings"
 
 	"github.com/scionproto/scion/go/lib/addr"
 )
@@ -72,7 +73,10 @@ type Rule struct {
 	From    IAMatcher
 	To      IAMatcher
 	Network NetworkMatcher
-	Comment string
+	// Attributes are the attributes of the advertised prefixes. They are only
+	// set for advertise rules.
+	Attributes PrefixAttributes
+	Comment    string
 }
 
 // Match indicates if this rule matches the input.
@@ -80,6 +84,45 @@ func (r Rule) Match(from, to addr.IA, network *net.IPNet) bool {
 	return r.From.Match(from) && r.To.Match(to) && r.Network.Match(network)
 }
 
+// PrefixAttributes are the optional attributes of advertised prefixes.
+type PrefixAttributes struct {
+	// Metric is the cost of reaching the prefix via the advertising gateway.
+	// If multiple gateways advertise the same prefix, the one with the lowest
+	// metric is preferred.
+	Metric uint32
+	// Tags are opaque labels attached to the prefix.
+	Tags []string
+}
+
+// IsZero indicates whether no attribute is set.
+func (a PrefixAttributes) IsZero() bool {
+	return a.Metric == 0 && len(a.Tags) == 0
+}
+
+// Equal indicates whether the attributes are equal.
+func (a PrefixAttributes) Equal(other PrefixAttributes) bool {
+	if a.Metric != other.Metric || len(a.Tags) != len(other.Tags) {
+		return false
+	}
+	for i := range a.Tags {
+		if a.Tags[i] != other.Tags[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (a PrefixAttributes) String() string {
+	var parts []string
+	if a.Metric != 0 {
+		parts = append(parts, fmt.Sprintf("metric=%d", a.Metric))
+	}
+	if len(a.Tags) != 0 {
+		parts = append(parts, "tags="+strings.Join(a.Tags, ","))
+	}
+	return strings.Join(parts, " ")
+}
+
 // IAMatcher matches ISD-AS.
 type IAMatcher interface {
 	Match(addr.IA) bool
@@ -0,0 +1,395 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tunnelkeys provides the keys used to encrypt the tunnels between
+// SCION IP gateways.
+//
+// The keys are derived from DRKey Host2Host keys for the "gateway" protocol.
+// The key protecting the frames sent from gateway A to gateway B is derived
+// from the level 2 key with A as source and B as destination. Both gateways
+// can therefore derive the key without any further interaction.
+//
+// Keys are rotated every rekey interval. The identifier of a key is the start
+// of the rekey period it was created for, in seconds since the Unix epoch. The
+// receiver uses the identifier to derive the same key. The identifier is not
+// authenticated, thus the receiver only accepts the keys of the current and
+// the two adjacent rekey periods, and only from remote ASes for which
+// encryption is enabled. Both gateways must use the same rekey interval.
+package tunnelkeys
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/drkey"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/metrics"
+	"github.com/scionproto/scion/go/lib/prom"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/pkg/gateway/dataplane"
+)
+
+const (
+	// Protocol is the DRKey protocol the keys are derived for.
+	Protocol = "gateway"
+	// DefaultRekeyInterval is the default interval in which keys are rotated.
+	DefaultRekeyInterval = time.Hour
+	// fetchTimeout is the timeout for fetching a single DRKey.
+	fetchTimeout = 5 * time.Second
+	// retryInterval is the minimum time between two attempts to fetch the same
+	// key.
+	retryInterval = time.Second
+	// prefetchFraction is the fraction of the rekey interval before the end of
+	// the current period in which the key for the next period is fetched.
+	prefetchFraction = 10
+	// maxFetchesPerIA is the maximum number of concurrent fetches for the
+	// ingress keys of a single remote AS.
+	maxFetchesPerIA = 4
+	// maxKeysPerIA is the maximum number of cached ingress keys of a single
+	// remote AS.
+	maxKeysPerIA = 64
+)
+
+var (
+	_ dataplane.IngressKeys = (*Store)(nil)
+	_ dataplane.EgressKeys  = egressKeys{}
+)
+
+// Fetcher fetches DRKey level 2 keys. It is usually implemented by the
+// connector to the SCION Daemon.
+type Fetcher interface {
+	DRKeyGetLvl2Key(ctx context.Context, meta drkey.Lvl2Meta,
+		valTime time.Time) (drkey.Lvl2Key, error)
+}
+
+// Metrics are the metrics of the key store.
+type Metrics struct {
+	// KeyFetches counts the DRKey fetches, labeled by result.
+	KeyFetches metrics.Counter
+}
+
+// Store caches the tunnel keys. Keys are fetched asynchronously, such that the
+// data-plane is never blocked waiting for a key; frames for which no key is
+// available yet are dropped. The store is safe for concurrent use.
+type Store struct {
+	// Fetcher is used to fetch the DRKeys.
+	Fetcher Fetcher
+	// LocalIA is the ISD-AS of the local gateway.
+	LocalIA addr.IA
+	// LocalIP is the data-plane IP address of the local gateway. It is used
+	// as the destination address of ingress keys, and as the source address
+	// of egress keys unless EgressIP is set. It must not be unspecified.
+	LocalIP net.IP
+	// EgressIP is the IP address the frames are sent from. If nil, LocalIP is
+	// used.
+	EgressIP net.IP
+	// RekeyInterval is the interval in which the egress keys are rotated. If
+	// it is zero, DefaultRekeyInterval is used.
+	RekeyInterval time.Duration
+	// Metrics are the metrics of the store. If nil, no metrics are reported.
+	Metrics *Metrics
+
+	// now is the clock of the store. If nil, time.Now is used.
+	now func() time.Time
+
+	mtx         sync.Mutex
+	keys        map[keyRef]*entry
+	ingress     map[addr.IA]*ingressCount
+	required    map[addr.IA]struct{}
+	lastCleanup time.Time
+}
+
+// keyRef identifies a tunnel key.
+type keyRef struct {
+	srcIA, dstIA addr.IA
+	srcIP, dstIP string
+	id           uint32
+}
+
+type entry struct {
+	aead cipher.AEAD
+	// ingress indicates that the key is used for frames received from the
+	// remote gateway.
+	ingress bool
+	// fetching indicates that a fetch for the key is in progress.
+	fetching bool
+	// lastAttempt is the time of the last fetch attempt.
+	lastAttempt time.Time
+}
+
+// ingressCount counts the ingress keys of a remote AS.
+type ingressCount struct {
+	keys     int
+	fetching int
+}
+
+// Egress returns the egress keys for the tunnel to the gateway with the given
+// address.
+func (s *Store) Egress(remoteIA addr.IA, remoteIP net.IP) dataplane.EgressKeys {
+	return egressKeys{store: s, remoteIA: remoteIA, remoteIP: remoteIP}
+}
+
+// IngressKey returns the key with the given identifier for frames received from
+// the remote gateway. Only the keys of the current, the previous and the next
+// rekey period are accepted, and only from remote ASes for which encryption is
+// required.
+func (s *Store) IngressKey(remote *snet.UDPAddr, id uint32) (cipher.AEAD, bool) {
+	if !s.Required(remote.IA) {
+		return nil, false
+	}
+	now := s.clock()
+	interval := s.rekeyInterval()
+	start := time.Unix(int64(id), 0)
+	if !start.Truncate(interval).Equal(start) {
+		return nil, false
+	}
+	switch start.Sub(now.Truncate(interval)) {
+	case -interval, 0, interval:
+	default:
+		return nil, false
+	}
+	return s.lookup(keyRef{
+		srcIA: remote.IA,
+		dstIA: s.LocalIA,
+		srcIP: remote.Host.IP.String(),
+		dstIP: s.LocalIP.String(),
+		id:    id,
+	}, now, true)
+}
+
+// Required indicates whether encryption is required for the frames received
+// from the remote AS.
+func (s *Store) Required(ia addr.IA) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	_, ok := s.required[ia]
+	return ok
+}
+
+// SetRequired sets the remote ASes for which encryption is required. It
+// replaces the previously set ASes.
+func (s *Store) SetRequired(ias []addr.IA) {
+	required := make(map[addr.IA]struct{}, len(ias))
+	for _, ia := range ias {
+		required[ia] = struct{}{}
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.required = required
+}
+
+// lookup returns the cached key. If the key is not cached, it is fetched in the
+// background. The number of ingress keys and concurrent fetches per remote AS
+// is limited, such that remote gateways cannot exhaust the memory of the store
+// or flood the control service with requests.
+func (s *Store) lookup(ref keyRef, now time.Time, ingress bool) (cipher.AEAD, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.keys == nil {
+		s.keys = make(map[keyRef]*entry)
+		s.ingress = make(map[addr.IA]*ingressCount)
+	}
+	if now.Sub(s.lastCleanup) >= retryInterval {
+		s.cleanupLocked(now)
+	}
+	e, ok := s.keys[ref]
+	if e != nil && e.aead != nil {
+		return e.aead, true
+	}
+	var count *ingressCount
+	if ingress {
+		if count = s.ingress[ref.srcIA]; count == nil {
+			count = &ingressCount{}
+			s.ingress[ref.srcIA] = count
+		}
+		if count.fetching >= maxFetchesPerIA || (!ok && count.keys >= maxKeysPerIA) {
+			return nil, false
+		}
+	}
+	if !ok {
+		e = &entry{ingress: ingress}
+		s.keys[ref] = e
+		if count != nil {
+			count.keys++
+		}
+	}
+	if !e.fetching && now.Sub(e.lastAttempt) >= retryInterval {
+		e.fetching = true
+		e.lastAttempt = now
+		if count != nil {
+			count.fetching++
+		}
+		go func() {
+			defer log.HandlePanic()
+			s.fetch(ref)
+		}()
+	}
+	return nil, false
+}
+
+// prefetch fetches the key in the background if it is not cached yet.
+func (s *Store) prefetch(ref keyRef, now time.Time) {
+	s.lookup(ref, now, false)
+}
+
+func (s *Store) fetch(ref keyRef) {
+	aead, err := s.derive(ref)
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	e := s.keys[ref]
+	e.fetching = false
+	if e.ingress {
+		s.ingress[ref.srcIA].fetching--
+	}
+	if err != nil {
+		s.incFetches(prom.ErrNotClassified)
+		log.Info("Failed to fetch tunnel key", "src_isd_as", ref.srcIA, "src_ip", ref.srcIP,
+			"dst_isd_as", ref.dstIA, "dst_ip", ref.dstIP, "key_id", ref.id, "err", err)
+		return
+	}
+	s.incFetches(prom.Success)
+	e.aead = aead
+}
+
+// derive fetches the DRKey and derives the AEAD for the key reference.
+func (s *Store) derive(ref keyRef) (cipher.AEAD, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	meta := drkey.Lvl2Meta{
+		KeyType:  drkey.Host2Host,
+		Protocol: Protocol,
+		SrcIA:    ref.srcIA,
+		DstIA:    ref.dstIA,
+		SrcHost:  addr.HostFromIPStr(ref.srcIP),
+		DstHost:  addr.HostFromIPStr(ref.dstIP),
+	}
+	key, err := s.Fetcher.DRKeyGetLvl2Key(ctx, meta, time.Unix(int64(ref.id), 0))
+	if err != nil {
+		return nil, err
+	}
+	return deriveAEAD(key.Key, ref.id)
+}
+
+// cleanupLocked removes the keys of the periods before the previous rekey
+// period, and the keys that failed to be fetched and are due for a retry. The
+// caller must hold the lock.
+func (s *Store) cleanupLocked(now time.Time) {
+	s.lastCleanup = now
+	interval := s.rekeyInterval()
+	oldest := now.Truncate(interval).Add(-interval)
+	for ref, e := range s.keys {
+		if e.fetching {
+			continue
+		}
+		failed := e.aead == nil && now.Sub(e.lastAttempt) >= retryInterval
+		if !failed && !time.Unix(int64(ref.id), 0).Before(oldest) {
+			continue
+		}
+		delete(s.keys, ref)
+		if !e.ingress {
+			continue
+		}
+		count := s.ingress[ref.srcIA]
+		if count.keys--; count.keys == 0 && count.fetching == 0 {
+			delete(s.ingress, ref.srcIA)
+		}
+	}
+}
+
+func (s *Store) rekeyInterval() time.Duration {
+	if s.RekeyInterval == 0 {
+		return DefaultRekeyInterval
+	}
+	return s.RekeyInterval
+}
+
+func (s *Store) egressIP() net.IP {
+	if s.EgressIP == nil {
+		return s.LocalIP
+	}
+	return s.EgressIP
+}
+
+func (s *Store) clock() time.Time {
+	if s.now == nil {
+		return time.Now()
+	}
+	return s.now()
+}
+
+func (s *Store) incFetches(result string) {
+	if s.Metrics == nil {
+		return
+	}
+	metrics.CounterInc(metrics.CounterWith(s.Metrics.KeyFetches, "result", result))
+}
+
+// egressKeys are the keys for the tunnel to a remote gateway.
+type egressKeys struct {
+	store    *Store
+	remoteIA addr.IA
+	remoteIP net.IP
+}
+
+// EgressKey returns the key of the current rekey period. Shortly before the
+// period ends, the key of the next period is fetched in the background.
+func (k egressKeys) EgressKey() (uint32, cipher.AEAD, bool) {
+	now := k.store.clock()
+	interval := k.store.rekeyInterval()
+	start := now.Truncate(interval)
+	ref := k.ref(start)
+	aead, ok := k.store.lookup(ref, now, false)
+	if start.Add(interval).Sub(now) < interval/prefetchFraction {
+		k.store.prefetch(k.ref(start.Add(interval)), now)
+	}
+	return ref.id, aead, ok
+}
+
+func (k egressKeys) ref(start time.Time) keyRef {
+	return keyRef{
+		srcIA: k.store.LocalIA,
+		dstIA: k.remoteIA,
+		srcIP: k.store.egressIP().String(),
+		dstIP: k.remoteIP.String(),
+		id:    uint32(start.Unix()),
+	}
+}
+
+// deriveAEAD derives the AES-GCM AEAD for the key identifier from the DRKey.
+// Deriving a separate key for every identifier ensures that keys are rotated
+// even if the DRKey epoch is longer than the rekey interval.
+func deriveAEAD(key drkey.DRKey, id uint32) (cipher.AEAD, error) {
+	if len(key) == 0 {
+		return nil, serrors.New("empty DRKey")
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(Protocol))
+	var raw [4]byte
+	binary.BigEndian.PutUint32(raw[:], id)
+	mac.Write(raw[:])
+	block, err := aes.NewCipher(mac.Sum(nil)[:16])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,306 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunnelkeys
+
+import (
+	"context"
+	"crypto/cipher"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/drkey"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/pkg/gateway/dataplane"
+)
+
+// fakeFetcher returns a key that only depends on the source and destination,
+// and records the requests.
+type fakeFetcher struct {
+	mtx      sync.Mutex
+	requests []time.Time
+	fail     bool
+}
+
+func (f *fakeFetcher) DRKeyGetLvl2Key(_ context.Context, meta drkey.Lvl2Meta,
+	valTime time.Time) (drkey.Lvl2Key, error) {
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.requests = append(f.requests, valTime)
+	if f.fail {
+		return drkey.Lvl2Key{}, serrors.New("test error")
+	}
+	key := []byte(meta.SrcIA.String() + meta.SrcHost.String() + meta.DstIA.String() +
+		meta.DstHost.String())
+	return drkey.Lvl2Key{Lvl2Meta: meta, Key: key}, nil
+}
+
+func (f *fakeFetcher) numRequests() int {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return len(f.requests)
+}
+
+// blockingFetcher blocks the requests until block is closed.
+type blockingFetcher struct {
+	fakeFetcher
+	block chan struct{}
+}
+
+func (f *blockingFetcher) DRKeyGetLvl2Key(ctx context.Context, meta drkey.Lvl2Meta,
+	valTime time.Time) (drkey.Lvl2Key, error) {
+
+	f.mtx.Lock()
+	f.requests = append(f.requests, valTime)
+	f.mtx.Unlock()
+	<-f.block
+	f.mtx.Lock()
+	fail := f.fail
+	f.mtx.Unlock()
+	if fail {
+		return drkey.Lvl2Key{}, serrors.New("test error")
+	}
+	return drkey.Lvl2Key{Lvl2Meta: meta, Key: []byte(meta.SrcHost.String())}, nil
+}
+
+func (f *fakeFetcher) setFail(fail bool) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.fail = fail
+}
+
+// testClock is a clock that can be adjusted by the test.
+type testClock struct {
+	mtx sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestStoreEgressIngress(t *testing.T) {
+	ia1, ip1 := xtest.MustParseIA("1-ff00:0:110"), net.IP{10, 0, 0, 1}
+	ia2, ip2 := xtest.MustParseIA("1-ff00:0:111"), net.IP{10, 0, 0, 2}
+	now := time.Date(2021, 5, 1, 10, 30, 0, 0, time.UTC)
+
+	fetcher := &fakeFetcher{}
+	sender := &Store{
+		Fetcher:       fetcher,
+		LocalIA:       ia1,
+		LocalIP:       ip1,
+		RekeyInterval: time.Hour,
+		now:           func() time.Time { return now },
+	}
+	receiver := &Store{
+		Fetcher: fetcher,
+		LocalIA: ia2,
+		LocalIP: ip2,
+		now:     func() time.Time { return now },
+	}
+	receiver.SetRequired([]addr.IA{ia1})
+	egress := sender.Egress(ia2, ip2)
+	id, _, ok := egress.EgressKey()
+	assert.False(t, ok, "key is fetched asynchronously")
+	assert.Equal(t, uint32(now.Truncate(time.Hour).Unix()), id)
+
+	egressAEAD := waitEgress(t, egress)
+	ingressAEAD := waitIngress(t, receiver,
+		&snet.UDPAddr{IA: ia1, Host: &net.UDPAddr{IP: ip1, Port: 30056}}, id)
+
+	nonce := make([]byte, egressAEAD.NonceSize())
+	sealed := egressAEAD.Seal(nil, nonce, []byte("payload"), nil)
+	plain, err := ingressAEAD.Open(nil, nonce, sealed, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("payload"), plain)
+
+	t.Run("key of other source does not match", func(t *testing.T) {
+		other := waitIngress(t, receiver,
+			&snet.UDPAddr{IA: ia1, Host: &net.UDPAddr{IP: net.IP{10, 0, 0, 3}}}, id)
+		_, err := other.Open(nil, nonce, sealed, nil)
+		assert.Error(t, err)
+	})
+	t.Run("adjacent periods are accepted", func(t *testing.T) {
+		remote := &snet.UDPAddr{IA: ia1, Host: &net.UDPAddr{IP: ip1}}
+		waitIngress(t, receiver, remote, id-3600)
+		waitIngress(t, receiver, remote, id+3600)
+	})
+	t.Run("other periods are rejected", func(t *testing.T) {
+		remote := &snet.UDPAddr{IA: ia1, Host: &net.UDPAddr{IP: ip1}}
+		for _, other := range []uint32{id - 2*3600, id + 2*3600, id + 60,
+			uint32(now.Add(-dataplane.MaxKeyAge).Unix())} {
+
+			_, ok := receiver.IngressKey(remote, other)
+			assert.False(t, ok, other)
+		}
+	})
+	t.Run("not required is rejected", func(t *testing.T) {
+		ia3 := xtest.MustParseIA("1-ff00:0:112")
+		_, ok := receiver.IngressKey(&snet.UDPAddr{IA: ia3, Host: &net.UDPAddr{IP: ip1}}, id)
+		assert.False(t, ok)
+	})
+}
+
+func TestStoreIngressLimits(t *testing.T) {
+	clock := &testClock{now: time.Date(2021, 5, 1, 10, 30, 0, 0, time.UTC)}
+	remoteIA := xtest.MustParseIA("1-ff00:0:111")
+	id := uint32(clock.Now().Truncate(time.Hour).Unix())
+	block := make(chan struct{})
+	fetcher := &blockingFetcher{block: block}
+	s := &Store{
+		Fetcher: fetcher,
+		LocalIA: xtest.MustParseIA("1-ff00:0:110"),
+		LocalIP: net.IP{10, 0, 0, 1},
+		now:     clock.Now,
+	}
+	s.SetRequired([]addr.IA{remoteIA})
+	remote := func(i int) *snet.UDPAddr {
+		return &snet.UDPAddr{IA: remoteIA, Host: &net.UDPAddr{IP: net.IP{10, 1, 0, byte(i)}}}
+	}
+
+	// Concurrent fetches are limited per remote AS.
+	for i := 0; i < 2*maxFetchesPerIA; i++ {
+		_, ok := s.IngressKey(remote(i), id)
+		assert.False(t, ok)
+	}
+	require.Eventually(t, func() bool { return fetcher.numRequests() == maxFetchesPerIA },
+		time.Second, 10*time.Millisecond)
+	s.mtx.Lock()
+	assert.Len(t, s.keys, maxFetchesPerIA)
+	s.mtx.Unlock()
+
+	// Failed keys are evicted once they are due for a retry.
+	fetcher.setFail(true)
+	close(block)
+	require.Eventually(t, func() bool {
+		s.mtx.Lock()
+		defer s.mtx.Unlock()
+		return s.ingress[remoteIA].fetching == 0
+	}, time.Second, 10*time.Millisecond)
+	clock.Add(retryInterval)
+	fetcher.setFail(false)
+	_, ok := s.IngressKey(remote(0), id)
+	assert.False(t, ok)
+	s.mtx.Lock()
+	assert.Len(t, s.keys, 1)
+	s.mtx.Unlock()
+
+	// Keys of old periods are evicted.
+	waitIngress(t, s, remote(0), id)
+	clock.Add(2 * time.Hour)
+	_, ok = s.IngressKey(remote(0), id+2*3600)
+	assert.False(t, ok)
+	s.mtx.Lock()
+	_, found := s.keys[keyRef{srcIA: remoteIA, dstIA: s.LocalIA, srcIP: "10.1.0.0",
+		dstIP: "10.0.0.1", id: id}]
+	s.mtx.Unlock()
+	assert.False(t, found)
+}
+
+func TestStorePrefetch(t *testing.T) {
+	clock := &testClock{now: time.Date(2021, 5, 1, 10, 55, 0, 0, time.UTC)}
+	fetcher := &fakeFetcher{}
+	s := &Store{
+		Fetcher: fetcher,
+		LocalIA: xtest.MustParseIA("1-ff00:0:110"),
+		LocalIP: net.IP{10, 0, 0, 1},
+		now:     clock.Now,
+	}
+	egress := s.Egress(xtest.MustParseIA("1-ff00:0:111"), net.IP{10, 0, 0, 2})
+	waitEgress(t, egress)
+	require.Eventually(t, func() bool { return fetcher.numRequests() == 2 },
+		time.Second, 10*time.Millisecond)
+
+	// The key of the next period is available immediately.
+	clock.Add(10 * time.Minute)
+	id, _, ok := egress.EgressKey()
+	assert.True(t, ok)
+	assert.Equal(t, uint32(time.Date(2021, 5, 1, 11, 0, 0, 0, time.UTC).Unix()), id)
+}
+
+func TestStoreRetry(t *testing.T) {
+	clock := &testClock{now: time.Date(2021, 5, 1, 10, 30, 0, 0, time.UTC)}
+	fetcher := &fakeFetcher{fail: true}
+	s := &Store{
+		Fetcher: fetcher,
+		LocalIA: xtest.MustParseIA("1-ff00:0:110"),
+		LocalIP: net.IP{10, 0, 0, 1},
+		now:     clock.Now,
+	}
+	egress := s.Egress(xtest.MustParseIA("1-ff00:0:111"), net.IP{10, 0, 0, 2})
+	_, _, ok := egress.EgressKey()
+	assert.False(t, ok)
+	require.Eventually(t, func() bool { return fetcher.numRequests() == 1 },
+		time.Second, 10*time.Millisecond)
+
+	// No new attempt before the retry interval passed.
+	_, _, ok = egress.EgressKey()
+	assert.False(t, ok)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, fetcher.numRequests())
+
+	clock.Add(retryInterval)
+	_, _, ok = egress.EgressKey()
+	assert.False(t, ok)
+	require.Eventually(t, func() bool { return fetcher.numRequests() == 2 },
+		time.Second, 10*time.Millisecond)
+}
+
+func TestStoreRequired(t *testing.T) {
+	s := &Store{}
+	ia1, ia2 := xtest.MustParseIA("1-ff00:0:110"), xtest.MustParseIA("1-ff00:0:111")
+	assert.False(t, s.Required(ia1))
+	s.SetRequired([]addr.IA{ia1})
+	assert.True(t, s.Required(ia1))
+	assert.False(t, s.Required(ia2))
+	s.SetRequired(nil)
+	assert.False(t, s.Required(ia1))
+}
+
+func waitEgress(t *testing.T, keys dataplane.EgressKeys) cipher.AEAD {
+	var aead cipher.AEAD
+	require.Eventually(t, func() bool {
+		var ok bool
+		_, aead, ok = keys.EgressKey()
+		return ok
+	}, time.Second, 10*time.Millisecond)
+	return aead
+}
+
+func waitIngress(t *testing.T, s *Store, remote *snet.UDPAddr, id uint32) cipher.AEAD {
+	var aead cipher.AEAD
+	require.Eventually(t, func() bool {
+		var ok bool
+		aead, ok = s.IngressKey(remote, id)
+		return ok
+	}, time.Second, 10*time.Millisecond)
+	return aead
+}
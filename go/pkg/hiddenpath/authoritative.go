@@ -35,8 +35,8 @@ type SegmentRequest struct {
 
 // AuthoritativeServer serves segments from the database.
 type AuthoritativeServer struct {
-	// Groups is the current set of groups.
-	Groups map[GroupID]*Group
+	// Groups provides the current set of groups.
+	Groups GroupProvider
 	// DB is used to read hidden segments.
 	DB Store
 	// LocalIA is the ISD-AS this server is run in.
@@ -51,8 +51,9 @@ func (s AuthoritativeServer) Segments(ctx context.Context,
 	if len(req.GroupIDs) == 0 {
 		return nil, serrors.New("no group IDs provided")
 	}
+	groups := s.Groups.CurrentGroups()
 	for _, id := range req.GroupIDs {
-		group, ok := s.Groups[id]
+		group, ok := groups[id]
 		if !ok {
 			return nil, serrors.New("request for unknown group", "group_id", id)
 		}
@@ -221,7 +221,7 @@ func TestAuthoritativeServerSegments(t *testing.T) {
 			defer ctrl.Finish()
 
 			server := hiddenpath.AuthoritativeServer{
-				Groups:  tc.groups(),
+				Groups:  hiddenpath.Groups(tc.groups()),
 				DB:      tc.db(ctrl),
 				LocalIA: local,
 			}
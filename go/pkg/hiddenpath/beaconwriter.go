@@ -62,8 +62,8 @@ type BeaconWriter struct {
 	RPC Register
 	// Pather is used to construct paths to the originator of a beacon.
 	Pather beaconing.Pather
-	// RegistrationPolicy is the hidden path registration policy.
-	RegistrationPolicy RegistrationPolicy
+	// RegistrationPolicy provides the hidden path registration policy.
+	RegistrationPolicy RegistrationPolicyProvider
 	// AddressResolver is used to resolve remote ASes.
 	AddressResolver AddressResolver
 }
@@ -79,6 +79,7 @@ func (w *BeaconWriter) Write(ctx context.Context, segments <-chan beacon.BeaconO
 	summary := newSummary()
 	var expected int
 	var wg sync.WaitGroup
+	policy := w.RegistrationPolicy.CurrentRegistrationPolicy()
 
 	for bOrErr := range segments {
 		if bOrErr.Err != nil {
@@ -92,7 +93,7 @@ func (w *BeaconWriter) Write(ctx context.Context, segments <-chan beacon.BeaconO
 			metrics.CounterInc(w.InternalErrors)
 			continue
 		}
-		regPolicy, ok := w.RegistrationPolicy[uint64(bOrErr.Beacon.InIfId)]
+		regPolicy, ok := policy[uint64(bOrErr.Beacon.InIfId)]
 		if !ok {
 			logger.Info("no HP nor public registration policy for beacon",
 				"interface", bOrErr.Beacon.InIfId)
@@ -115,7 +115,7 @@ func TestForwardServerSegments(t *testing.T) {
 				AnyTimes()
 
 			server := hiddenpath.ForwardServer{
-				Groups:    tc.groups(),
+				Groups:    hiddenpath.Groups(tc.groups()),
 				RPC:       tc.rpc(ctrl),
 				LocalAuth: tc.lookuper(ctrl),
 				LocalIA:   local,
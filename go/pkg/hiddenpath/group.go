@@ -92,6 +92,9 @@ type Group struct {
 	// Registries contains all ASes in the group at which Writers register hidden
 	// paths.
 	Registries map[addr.IA]struct{}
+	// Version is the version of the group configuration. The Owner increments
+	// the version whenever it changes the group configuration.
+	Version uint64
 }
 
 // Validate validates the group.
@@ -137,9 +140,22 @@ func (r Roles) None() bool {
 	return !r.Owner && !r.Registry && !r.Reader && !r.Writer
 }
 
+// GroupProvider provides the current set of hidden path groups.
+type GroupProvider interface {
+	// CurrentGroups returns the current set of hidden path groups. The returned
+	// groups must not be modified.
+	CurrentGroups() Groups
+}
+
 // Groups is a list of hidden path groups.
 type Groups map[GroupID]*Group
 
+// CurrentGroups returns the groups itself. It implements the GroupProvider
+// interface for a static set of groups.
+func (g Groups) CurrentGroups() Groups {
+	return g
+}
+
 // Validate validates all groups in the map.
 func (g Groups) Validate() error {
 	for _, group := range g {
@@ -217,6 +233,7 @@ type groupInfo struct {
 	Writers    []string `yaml:"writers,omitempty"`
 	Readers    []string `yaml:"readers,omitempty"`
 	Registries []string `yaml:"registries,omitempty"`
+	Version    uint64   `yaml:"version,omitempty"`
 }
 
 func parseGroups(groups map[string]*groupInfo) (Groups, error) {
@@ -248,6 +265,7 @@ func parseGroups(groups map[string]*groupInfo) (Groups, error) {
 			Writers:    writers,
 			Readers:    readers,
 			Registries: registries,
+			Version:    rawGroup.Version,
 		}
 	}
 	return result, nil
@@ -261,6 +279,7 @@ func marshalGroups(groups Groups) map[string]*groupInfo {
 			Writers:    iaSetToStrings(group.Writers),
 			Readers:    iaSetToStrings(group.Readers),
 			Registries: iaSetToStrings(group.Registries),
+			Version:    group.Version,
 		}
 	}
 	return result
@@ -93,6 +93,7 @@ func TestNewGroup(t *testing.T) {
 					Registries: map[addr.IA]struct{}{
 						xtest.MustParseIA("1-ff00:0:115"): {},
 					},
+					Version: 3,
 				},
 			},
 		},
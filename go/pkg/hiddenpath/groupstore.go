@@ -0,0 +1,149 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hiddenpath
+
+import (
+	"sync"
+
+	"github.com/scionproto/scion/go/lib/serrors"
+	cryptopb "github.com/scionproto/scion/go/pkg/proto/crypto"
+)
+
+// SignedGroup is a hidden path group configuration together with the signed
+// message that was created by the owner of the group.
+type SignedGroup struct {
+	// Group is the group configuration.
+	Group *Group
+	// Signed is the signed group configuration. Its body is the encoded group
+	// configuration.
+	Signed *cryptopb.SignedMessage
+}
+
+// GroupStore holds the hidden path groups and the registration policy of the
+// local AS. Groups can be replaced by newer versions at runtime, the
+// registration policy is kept consistent with the replaced groups. The store
+// is safe for concurrent use.
+type GroupStore struct {
+	mtx    sync.RWMutex
+	groups Groups
+	policy RegistrationPolicy
+	signed map[GroupID]*cryptopb.SignedMessage
+}
+
+// NewGroupStore creates a store with the given initial groups and registration
+// policy. The policy must only refer to the given groups. The store takes
+// ownership of the groups and the policy, the caller must not modify them
+// anymore.
+func NewGroupStore(groups Groups, policy RegistrationPolicy) *GroupStore {
+	if groups == nil {
+		groups = make(Groups)
+	}
+	return &GroupStore{
+		groups: groups,
+		policy: policy,
+		signed: make(map[GroupID]*cryptopb.SignedMessage),
+	}
+}
+
+// CurrentGroups returns the current set of groups.
+func (s *GroupStore) CurrentGroups() Groups {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.groups
+}
+
+// CurrentRegistrationPolicy returns the current registration policy.
+func (s *GroupStore) CurrentRegistrationPolicy() RegistrationPolicy {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.policy
+}
+
+// Signed returns the signed configuration of the current version of the
+// group. It returns nil if no signed configuration is known for the group.
+func (s *GroupStore) Signed(id GroupID) *cryptopb.SignedMessage {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.signed[id]
+}
+
+// Update replaces the group with the given configuration if its version is
+// higher than the version of the current configuration. Only groups that are
+// known to the store can be updated and the owner of the group must not change.
+// The signed configuration is optional, if it is set it is stored alongside
+// the group. The return value indicates whether the group was replaced.
+func (s *GroupStore) Update(update SignedGroup) (bool, error) {
+	group := update.Group
+	if group == nil {
+		return false, serrors.New("group not set")
+	}
+	if err := group.Validate(); err != nil {
+		return false, serrors.WrapStr("validating group", err, "group_id", group.ID)
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	current, ok := s.groups[group.ID]
+	if !ok {
+		return false, serrors.New("unknown group", "group_id", group.ID)
+	}
+	if !current.Owner.Equal(group.Owner) {
+		return false, serrors.New("owner mismatch", "group_id", group.ID,
+			"expected", current.Owner, "actual", group.Owner)
+	}
+	switch {
+	case group.Version < current.Version:
+		return false, nil
+	case group.Version == current.Version:
+		// Keep the signed configuration of the current version if none is known
+		// yet, so that it can be distributed further.
+		if update.Signed != nil && s.signed[group.ID] == nil {
+			s.signed[group.ID] = update.Signed
+		}
+		return false, nil
+	}
+
+	// Copy on write, so that the previously returned groups and policies can
+	// still be used concurrently.
+	groups := make(Groups, len(s.groups))
+	for id, g := range s.groups {
+		groups[id] = g
+	}
+	groups[group.ID] = group
+	var policy RegistrationPolicy
+	if s.policy != nil {
+		policy = make(RegistrationPolicy, len(s.policy))
+		for ifID, ifPolicy := range s.policy {
+			ifGroups := make(map[GroupID]*Group, len(ifPolicy.Groups))
+			for id, g := range ifPolicy.Groups {
+				if id == group.ID {
+					g = group
+				}
+				ifGroups[id] = g
+			}
+			policy[ifID] = InterfacePolicy{
+				Public: ifPolicy.Public,
+				Groups: ifGroups,
+			}
+		}
+	}
+	s.groups = groups
+	s.policy = policy
+	if update.Signed != nil {
+		s.signed[group.ID] = update.Signed
+	} else {
+		delete(s.signed, group.ID)
+	}
+	return true, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hiddenpath_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/pkg/hiddenpath"
+	cryptopb "github.com/scionproto/scion/go/pkg/proto/crypto"
+)
+
+func TestGroupStoreUpdate(t *testing.T) {
+	id := hiddenpath.GroupID{OwnerAS: xtest.MustParseAS("ff00:0:110"), Suffix: 0x42}
+	group := func(version uint64, writer string) *hiddenpath.Group {
+		return &hiddenpath.Group{
+			ID:         id,
+			Owner:      xtest.MustParseIA("1-ff00:0:110"),
+			Writers:    map[addr.IA]struct{}{xtest.MustParseIA(writer): {}},
+			Registries: map[addr.IA]struct{}{xtest.MustParseIA("1-ff00:0:111"): {}},
+			Version:    version,
+		}
+	}
+	newStore := func() *hiddenpath.GroupStore {
+		initial := group(2, "1-ff00:0:112")
+		return hiddenpath.NewGroupStore(
+			hiddenpath.Groups{id: initial},
+			hiddenpath.RegistrationPolicy{
+				1: hiddenpath.InterfacePolicy{
+					Public: true,
+					Groups: map[hiddenpath.GroupID]*hiddenpath.Group{id: initial},
+				},
+			},
+		)
+	}
+
+	t.Run("newer version", func(t *testing.T) {
+		store := newStore()
+		oldGroups := store.CurrentGroups()
+		oldPolicy := store.CurrentRegistrationPolicy()
+		signed := &cryptopb.SignedMessage{HeaderAndBody: []byte("signed")}
+
+		updated, err := store.Update(hiddenpath.SignedGroup{
+			Group:  group(3, "1-ff00:0:113"),
+			Signed: signed,
+		})
+		require.NoError(t, err)
+		assert.True(t, updated)
+		assert.Equal(t, group(3, "1-ff00:0:113"), store.CurrentGroups()[id])
+		policy := store.CurrentRegistrationPolicy()
+		assert.True(t, policy[1].Public)
+		assert.Equal(t, group(3, "1-ff00:0:113"), policy[1].Groups[id])
+		assert.Equal(t, signed, store.Signed(id))
+
+		// Previously returned values are not modified.
+		assert.Equal(t, group(2, "1-ff00:0:112"), oldGroups[id])
+		assert.Equal(t, group(2, "1-ff00:0:112"), oldPolicy[1].Groups[id])
+	})
+	t.Run("older version", func(t *testing.T) {
+		store := newStore()
+		updated, err := store.Update(hiddenpath.SignedGroup{Group: group(1, "1-ff00:0:113")})
+		require.NoError(t, err)
+		assert.False(t, updated)
+		assert.Equal(t, group(2, "1-ff00:0:112"), store.CurrentGroups()[id])
+	})
+	t.Run("same version keeps signed", func(t *testing.T) {
+		store := newStore()
+		signed := &cryptopb.SignedMessage{HeaderAndBody: []byte("signed")}
+		updated, err := store.Update(hiddenpath.SignedGroup{
+			Group:  group(2, "1-ff00:0:112"),
+			Signed: signed,
+		})
+		require.NoError(t, err)
+		assert.False(t, updated)
+		assert.Equal(t, signed, store.Signed(id))
+	})
+	t.Run("unknown group", func(t *testing.T) {
+		store := newStore()
+		unknown := group(3, "1-ff00:0:113")
+		unknown.ID.Suffix = 0x43
+		_, err := store.Update(hiddenpath.SignedGroup{Group: unknown})
+		assert.Error(t, err)
+	})
+	t.Run("owner mismatch", func(t *testing.T) {
+		store := newStore()
+		other := group(3, "1-ff00:0:113")
+		other.Owner = xtest.MustParseIA("2-ff00:0:110")
+		_, err := store.Update(hiddenpath.SignedGroup{Group: other})
+		assert.Error(t, err)
+	})
+	t.Run("invalid group", func(t *testing.T) {
+		store := newStore()
+		invalid := group(3, "1-ff00:0:113")
+		invalid.Registries = nil
+		_, err := store.Update(hiddenpath.SignedGroup{Group: invalid})
+		assert.Error(t, err)
+	})
+}
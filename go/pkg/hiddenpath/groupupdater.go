@@ -0,0 +1,160 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hiddenpath
+
+import (
+	"context"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/log"
+)
+
+// DefaultGroupUpdateInterval is the default interval in which the latest
+// hidden path group configurations are fetched.
+const DefaultGroupUpdateInterval = 5 * time.Minute
+
+// GroupRPC fetches signed hidden path group configurations from a remote.
+type GroupRPC interface {
+	// Groups fetches the latest configurations of the given groups from the
+	// server. Only configurations that are verified to be signed by the owner
+	// of the respective group are returned. The returned error indicates
+	// configurations that could not be fetched or verified, it can be
+	// non-nil even if some configurations are returned.
+	Groups(ctx context.Context, groups []*Group, server net.Addr) ([]SignedGroup, error)
+}
+
+// GroupUpdater fetches the latest configurations of the hidden path groups
+// and applies newer versions to the store. It is meant to be run as a periodic
+// task.
+type GroupUpdater struct {
+	// Store is the store that is updated.
+	Store *GroupStore
+	// RPC is used to fetch the signed group configurations.
+	RPC GroupRPC
+	// LocalIA is the ISD-AS of the local AS.
+	LocalIA addr.IA
+	// Server is the server from which all groups are fetched, e.g., the local
+	// control service. If it is nil, the groups are fetched from their owners
+	// and registries, which are resolved using the Resolver.
+	Server net.Addr
+	// Resolver resolves the address of the owners and registries of a group.
+	Resolver AddressResolver
+	// Location is the location of the local hidden path group configuration.
+	// If set, newer versions of the groups owned by the local AS are applied
+	// from the local configuration as well. This allows the owner of a group to
+	// publish a new version without a restart. The local configuration is not
+	// signed, thus, groups owned by other ASes are only updated from signed
+	// remote configurations.
+	Location string
+}
+
+// Name returns the task name.
+func (u *GroupUpdater) Name() string {
+	return "hp_group_updater"
+}
+
+// Run fetches the latest group configurations and applies them to the store.
+func (u *GroupUpdater) Run(ctx context.Context) {
+	logger := log.FromCtx(ctx)
+	if u.Location != "" {
+		groups, err := LoadHiddenPathGroups(u.Location)
+		if err != nil {
+			logger.Info("Failed to reload hidden path groups", "err", err)
+		}
+		for _, group := range groups {
+			if !group.Owner.Equal(u.LocalIA) {
+				continue
+			}
+			u.apply(ctx, SignedGroup{Group: group}, u.Location)
+		}
+	}
+	for server, groups := range u.sources(ctx) {
+		signed, err := u.RPC.Groups(ctx, groups, server)
+		if err != nil {
+			logger.Info("Failed to fetch hidden path groups", "server", server, "err", err)
+		}
+		for _, g := range signed {
+			u.apply(ctx, g, server)
+		}
+	}
+}
+
+// sources returns the groups that should be fetched, keyed by the server they
+// are fetched from.
+func (u *GroupUpdater) sources(ctx context.Context) map[net.Addr][]*Group {
+	groups := u.Store.CurrentGroups()
+	ids := make([]GroupID, 0, len(groups))
+	for id := range groups {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].ToUint64() < ids[j].ToUint64() })
+
+	if u.Server != nil {
+		all := make([]*Group, 0, len(ids))
+		for _, id := range ids {
+			all = append(all, groups[id])
+		}
+		if len(all) == 0 {
+			return nil
+		}
+		return map[net.Addr][]*Group{u.Server: all}
+	}
+
+	perIA := make(map[addr.IA][]*Group)
+	for _, id := range ids {
+		group := groups[id]
+		// The owner is the source of truth for its own groups.
+		if group.Owner.Equal(u.LocalIA) {
+			continue
+		}
+		remotes := map[addr.IA]struct{}{group.Owner: {}}
+		for registry := range group.Registries {
+			remotes[registry] = struct{}{}
+		}
+		for ia := range remotes {
+			if ia.Equal(u.LocalIA) {
+				continue
+			}
+			perIA[ia] = append(perIA[ia], group)
+		}
+	}
+	result := make(map[net.Addr][]*Group, len(perIA))
+	for ia, groups := range perIA {
+		a, err := u.Resolver.Resolve(ctx, ia)
+		if err != nil {
+			log.FromCtx(ctx).Debug("Failed to resolve hidden path group source",
+				"isd_as", ia, "err", err)
+			continue
+		}
+		result[a] = groups
+	}
+	return result
+}
+
+func (u *GroupUpdater) apply(ctx context.Context, g SignedGroup, source interface{}) {
+	updated, err := u.Store.Update(g)
+	if err != nil {
+		log.FromCtx(ctx).Info("Ignoring hidden path group configuration",
+			"source", source, "err", err)
+		return
+	}
+	if updated {
+		log.FromCtx(ctx).Info("Updated hidden path group", "group_id", g.Group.ID,
+			"version", g.Group.Version, "source", source)
+	}
+}
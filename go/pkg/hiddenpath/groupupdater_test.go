@@ -0,0 +1,157 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hiddenpath_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/pkg/hiddenpath"
+	"github.com/scionproto/scion/go/pkg/hiddenpath/mock_hiddenpath"
+)
+
+func TestGroupUpdaterRun(t *testing.T) {
+	local := xtest.MustParseIA("1-ff00:0:111")
+	owner := xtest.MustParseIA("1-ff00:0:110")
+	registry := xtest.MustParseIA("1-ff00:0:113")
+	remoteID := hiddenpath.GroupID{OwnerAS: owner.A, Suffix: 1}
+	ownedID := hiddenpath.GroupID{OwnerAS: local.A, Suffix: 2}
+	group := func(id hiddenpath.GroupID, owner addr.IA, version uint64) *hiddenpath.Group {
+		return &hiddenpath.Group{
+			ID:         id,
+			Owner:      owner,
+			Writers:    map[addr.IA]struct{}{local: {}},
+			Registries: map[addr.IA]struct{}{registry: {}, local: {}},
+			Version:    version,
+		}
+	}
+	newStore := func() *hiddenpath.GroupStore {
+		return hiddenpath.NewGroupStore(hiddenpath.Groups{
+			remoteID: group(remoteID, owner, 1),
+			ownedID:  group(ownedID, local, 1),
+		}, nil)
+	}
+	ownerAddr := &net.UDPAddr{IP: net.ParseIP("10.0.0.1")}
+	registryAddr := &net.UDPAddr{IP: net.ParseIP("10.0.0.3")}
+
+	t.Run("fetch from owner and registries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		store := newStore()
+
+		resolver := mock_hiddenpath.NewMockAddressResolver(ctrl)
+		resolver.EXPECT().Resolve(gomock.Any(), owner).Return(ownerAddr, nil)
+		resolver.EXPECT().Resolve(gomock.Any(), registry).Return(registryAddr, nil)
+		rpc := mock_hiddenpath.NewMockGroupRPC(ctrl)
+		// The owned group is never fetched, only the remote one.
+		remoteOnly := []*hiddenpath.Group{group(remoteID, owner, 1)}
+		rpc.EXPECT().Groups(gomock.Any(), remoteOnly, ownerAddr).Return(
+			[]hiddenpath.SignedGroup{{Group: group(remoteID, owner, 3)}}, nil,
+		)
+		rpc.EXPECT().Groups(gomock.Any(), remoteOnly, registryAddr).Return(
+			[]hiddenpath.SignedGroup{{Group: group(remoteID, owner, 2)}},
+			serrors.New("partial failure"),
+		)
+
+		updater := &hiddenpath.GroupUpdater{
+			Store:    store,
+			RPC:      rpc,
+			LocalIA:  local,
+			Resolver: resolver,
+		}
+		updater.Run(context.Background())
+		assert.Equal(t, uint64(3), store.CurrentGroups()[remoteID].Version)
+		assert.Equal(t, uint64(1), store.CurrentGroups()[ownedID].Version)
+	})
+	t.Run("fetch from fixed server", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		store := newStore()
+
+		server := &net.TCPAddr{IP: net.ParseIP("127.0.0.1")}
+		rpc := mock_hiddenpath.NewMockGroupRPC(ctrl)
+		rpc.EXPECT().Groups(gomock.Any(), gomock.Len(2), server).Return(
+			[]hiddenpath.SignedGroup{{Group: group(ownedID, local, 2)}}, nil,
+		)
+
+		updater := &hiddenpath.GroupUpdater{
+			Store:   store,
+			RPC:     rpc,
+			LocalIA: local,
+			Server:  server,
+		}
+		updater.Run(context.Background())
+		assert.Equal(t, uint64(1), store.CurrentGroups()[remoteID].Version)
+		assert.Equal(t, uint64(2), store.CurrentGroups()[ownedID].Version)
+	})
+	t.Run("reload local configuration", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		store := newStore()
+
+		dir, cleanup := xtest.MustTempDir("", "hp-groups")
+		defer cleanup()
+		location := filepath.Join(dir, "groups.yml")
+		// The local configuration contains newer versions of both groups, only
+		// the version of the owned group is applied.
+		raw := fmt.Sprintf(localGroups, ownedID, local, local, local,
+			remoteID, owner, local, local)
+		require.NoError(t, ioutil.WriteFile(location, []byte(raw), 0644))
+
+		server := &net.TCPAddr{IP: net.ParseIP("127.0.0.1")}
+		rpc := mock_hiddenpath.NewMockGroupRPC(ctrl)
+		rpc.EXPECT().Groups(gomock.Any(), gomock.Len(2), server)
+
+		updater := &hiddenpath.GroupUpdater{
+			Store:    store,
+			RPC:      rpc,
+			LocalIA:  local,
+			Server:   server,
+			Location: location,
+		}
+		updater.Run(context.Background())
+		assert.Equal(t, uint64(1), store.CurrentGroups()[remoteID].Version)
+		assert.Equal(t, uint64(4), store.CurrentGroups()[ownedID].Version)
+	})
+}
+
+const localGroups = `
+groups:
+  %s:
+    owner: %s
+    writers:
+    - %s
+    registries:
+    - %s
+    version: 4
+  %s:
+    owner: %s
+    writers:
+    - %s
+    registries:
+    - %s
+    version: 4
+`
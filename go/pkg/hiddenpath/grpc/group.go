@@ -0,0 +1,243 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"net"
+	"sort"
+
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/infra"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/scrypto/signed"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+	libgrpc "github.com/scionproto/scion/go/pkg/grpc"
+	"github.com/scionproto/scion/go/pkg/hiddenpath"
+	cryptopb "github.com/scionproto/scion/go/pkg/proto/crypto"
+	hspb "github.com/scionproto/scion/go/pkg/proto/hidden_segment"
+)
+
+// GroupServer serves the latest signed hidden path group configurations.
+type GroupServer struct {
+	// Groups is the store of the groups known to this server.
+	Groups *hiddenpath.GroupStore
+	// LocalIA is the ISD-AS of the local AS.
+	LocalIA addr.IA
+	// Signer signs the configurations of the groups that are owned by the
+	// local AS. If it is nil, no configurations of owned groups are served.
+	Signer Signer
+}
+
+// HiddenSegmentGroups serves the signed configurations of the requested
+// groups. Groups for which no signed configuration is available are omitted.
+// Remote ASes are only served the configurations of groups they are a member
+// of.
+func (s GroupServer) HiddenSegmentGroups(ctx context.Context,
+	req *hspb.HiddenSegmentGroupsRequest) (*hspb.HiddenSegmentGroupsResponse, error) {
+
+	logger := log.FromCtx(ctx)
+	remote, isRemote := remoteIA(ctx)
+	groups := s.Groups.CurrentGroups()
+	rep := &hspb.HiddenSegmentGroupsResponse{}
+	for _, rawID := range req.GroupIds {
+		id := hiddenpath.GroupIDFromUint64(rawID)
+		group, ok := groups[id]
+		if !ok {
+			continue
+		}
+		if isRemote && !isMember(remote, group) {
+			continue
+		}
+		if !group.Owner.Equal(s.LocalIA) {
+			if signedGroup := s.Groups.Signed(id); signedGroup != nil {
+				rep.Groups = append(rep.Groups, signedGroup)
+			}
+			continue
+		}
+		if s.Signer == nil {
+			continue
+		}
+		signedGroup, err := SignGroup(ctx, s.Signer, group)
+		if err != nil {
+			logger.Info("Failed to sign hidden path group", "group_id", id, "err", err)
+			continue
+		}
+		rep.Groups = append(rep.Groups, signedGroup)
+	}
+	return rep, nil
+}
+
+// GroupRequester fetches signed hidden path group configurations from a
+// remote using gRPC.
+type GroupRequester struct {
+	// Dialer dials a new gRPC connection.
+	Dialer libgrpc.Dialer
+	// Verifier verifies the signatures of the group configurations.
+	Verifier infra.Verifier
+}
+
+// Groups fetches the latest configurations of the given groups from the server
+// and verifies that they are signed by the owners of the groups.
+func (r GroupRequester) Groups(ctx context.Context, groups []*hiddenpath.Group,
+	server net.Addr) ([]hiddenpath.SignedGroup, error) {
+
+	known := make(map[hiddenpath.GroupID]*hiddenpath.Group, len(groups))
+	ids := make([]uint64, 0, len(groups))
+	for _, group := range groups {
+		known[group.ID] = group
+		ids = append(ids, group.ID.ToUint64())
+	}
+	conn, err := r.Dialer.Dial(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	client := hspb.NewHiddenSegmentGroupServiceClient(conn)
+	rep, err := client.HiddenSegmentGroups(ctx, &hspb.HiddenSegmentGroupsRequest{
+		GroupIds: ids,
+	}, libgrpc.RetryProfile...)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		result []hiddenpath.SignedGroup
+		errs   serrors.List
+	)
+	for i, signedGroup := range rep.Groups {
+		group, err := r.verify(ctx, known, signedGroup, server)
+		if err != nil {
+			errs = append(errs, serrors.WithCtx(err, "index", i))
+			continue
+		}
+		result = append(result, hiddenpath.SignedGroup{Group: group, Signed: signedGroup})
+	}
+	return result, errs.ToError()
+}
+
+func (r GroupRequester) verify(ctx context.Context, known map[hiddenpath.GroupID]*hiddenpath.Group,
+	signedGroup *cryptopb.SignedMessage, server net.Addr) (*hiddenpath.Group, error) {
+
+	// The unverified body is only used to find the owner whose signature is
+	// expected. The owner is taken from the locally known configuration.
+	rawBody, err := signed.ExtractUnverifiedBody(signedGroup)
+	if err != nil {
+		return nil, serrors.WrapStr("extracting body", err)
+	}
+	var unverified hspb.HiddenSegmentGroup
+	if err := proto.Unmarshal(rawBody, &unverified); err != nil {
+		return nil, serrors.WrapStr("parsing body", err)
+	}
+	id := hiddenpath.GroupIDFromUint64(unverified.GroupId)
+	expected, ok := known[id]
+	if !ok {
+		return nil, serrors.New("unexpected group", "group_id", id)
+	}
+	msg, err := r.Verifier.WithIA(expected.Owner).WithServer(server).Verify(ctx, signedGroup)
+	if err != nil {
+		return nil, serrors.WrapStr("verifying signature", err, "group_id", id)
+	}
+	var body hspb.HiddenSegmentGroup
+	if err := proto.Unmarshal(msg.Body, &body); err != nil {
+		return nil, serrors.WrapStr("parsing verified body", err, "group_id", id)
+	}
+	group := groupFromPB(&body)
+	if group.ID != id {
+		return nil, serrors.New("group ID mismatch", "expected", id, "actual", group.ID)
+	}
+	if !group.Owner.Equal(expected.Owner) {
+		return nil, serrors.New("owner mismatch", "group_id", id,
+			"expected", expected.Owner, "actual", group.Owner)
+	}
+	if err := group.Validate(); err != nil {
+		return nil, serrors.WrapStr("validating group", err, "group_id", id)
+	}
+	return group, nil
+}
+
+// SignGroup encodes the group configuration and signs it with the signer.
+func SignGroup(ctx context.Context, signer Signer,
+	group *hiddenpath.Group) (*cryptopb.SignedMessage, error) {
+
+	raw, err := proto.Marshal(groupToPB(group))
+	if err != nil {
+		return nil, serrors.WrapStr("marshaling group", err)
+	}
+	return signer.Sign(ctx, raw)
+}
+
+func groupToPB(group *hiddenpath.Group) *hspb.HiddenSegmentGroup {
+	return &hspb.HiddenSegmentGroup{
+		GroupId:    group.ID.ToUint64(),
+		Version:    group.Version,
+		OwnerIsdAs: uint64(group.Owner.IAInt()),
+		Writers:    iaSetToPB(group.Writers),
+		Readers:    iaSetToPB(group.Readers),
+		Registries: iaSetToPB(group.Registries),
+	}
+}
+
+func groupFromPB(pb *hspb.HiddenSegmentGroup) *hiddenpath.Group {
+	return &hiddenpath.Group{
+		ID:         hiddenpath.GroupIDFromUint64(pb.GroupId),
+		Version:    pb.Version,
+		Owner:      addr.IAInt(pb.OwnerIsdAs).IA(),
+		Writers:    iaSetFromPB(pb.Writers),
+		Readers:    iaSetFromPB(pb.Readers),
+		Registries: iaSetFromPB(pb.Registries),
+	}
+}
+
+func iaSetToPB(ias map[addr.IA]struct{}) []uint64 {
+	result := make([]uint64, 0, len(ias))
+	for ia := range ias {
+		result = append(result, uint64(ia.IAInt()))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+func iaSetFromPB(ias []uint64) map[addr.IA]struct{} {
+	result := make(map[addr.IA]struct{}, len(ias))
+	for _, ia := range ias {
+		result[addr.IAInt(ia).IA()] = struct{}{}
+	}
+	return result
+}
+
+// remoteIA returns the ISD-AS of the peer if the request was received from a
+// remote AS.
+func remoteIA(ctx context.Context) (addr.IA, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return addr.IA{}, false
+	}
+	a, ok := p.Addr.(*snet.UDPAddr)
+	if !ok {
+		return addr.IA{}, false
+	}
+	return a.IA, true
+}
+
+func isMember(ia addr.IA, group *hiddenpath.Group) bool {
+	_, registry := group.Registries[ia]
+	_, writer := group.Writers[ia]
+	_, reader := group.Readers[ia]
+	return group.Owner.Equal(ia) || registry || writer || reader
+}
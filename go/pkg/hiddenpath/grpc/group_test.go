@@ -0,0 +1,184 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/peer"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/infra/mock_infra"
+	"github.com/scionproto/scion/go/lib/scrypto/signed"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/lib/xtest/graph"
+	"github.com/scionproto/scion/go/pkg/ca/renewal/grpc/mock_grpc"
+	"github.com/scionproto/scion/go/pkg/hiddenpath"
+	hpgrpc "github.com/scionproto/scion/go/pkg/hiddenpath/grpc"
+	cryptopb "github.com/scionproto/scion/go/pkg/proto/crypto"
+	hspb "github.com/scionproto/scion/go/pkg/proto/hidden_segment"
+)
+
+func TestGroups(t *testing.T) {
+	local := xtest.MustParseIA("1-ff00:0:110")
+	remote := xtest.MustParseIA("1-ff00:0:120")
+	member := xtest.MustParseIA("1-ff00:0:111")
+	group := func(owner addr.IA, suffix uint16, version uint64) *hiddenpath.Group {
+		return &hiddenpath.Group{
+			ID:         hiddenpath.GroupID{OwnerAS: owner.A, Suffix: suffix},
+			Owner:      owner,
+			Writers:    map[addr.IA]struct{}{member: {}},
+			Readers:    map[addr.IA]struct{}{},
+			Registries: map[addr.IA]struct{}{local: {}},
+			Version:    version,
+		}
+	}
+	owned := group(local, 1, 4)
+	learned := group(remote, 2, 7)
+
+	localSigner := graph.NewSigner()
+	remoteSigner := graph.NewSigner()
+	keys := map[addr.IA]graph.Signer{local: localSigner, remote: remoteSigner}
+
+	newStore := func(t *testing.T) *hiddenpath.GroupStore {
+		store := hiddenpath.NewGroupStore(hiddenpath.Groups{
+			owned.ID:   owned,
+			learned.ID: group(remote, 2, 6),
+		}, nil)
+		signedLearned, err := hpgrpc.SignGroup(context.Background(), remoteSigner, learned)
+		require.NoError(t, err)
+		_, err = store.Update(hiddenpath.SignedGroup{Group: learned, Signed: signedLearned})
+		require.NoError(t, err)
+		return store
+	}
+	// verifier verifies the signatures with the key of the expected owner.
+	verifier := func(ctrl *gomock.Controller) *mock_infra.MockVerifier {
+		v := mock_infra.NewMockVerifier(ctrl)
+		var expected addr.IA
+		v.EXPECT().WithIA(gomock.Any()).DoAndReturn(func(ia addr.IA) *mock_infra.MockVerifier {
+			expected = ia
+			return v
+		}).AnyTimes()
+		v.EXPECT().WithServer(gomock.Any()).Return(v).AnyTimes()
+		v.EXPECT().Verify(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, msg *cryptopb.SignedMessage,
+				_ ...[]byte) (*signed.Message, error) {
+
+				return signed.Verify(msg, keys[expected].PrivateKey.Public())
+			},
+		).AnyTimes()
+		return v
+	}
+
+	t.Run("fetch and verify", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		svc := xtest.NewGRPCService()
+		hspb.RegisterHiddenSegmentGroupServiceServer(svc.Server(), hpgrpc.GroupServer{
+			Groups:  newStore(t),
+			LocalIA: local,
+			Signer:  localSigner,
+		})
+		svc.Start(t)
+
+		requester := hpgrpc.GroupRequester{
+			Dialer:   svc,
+			Verifier: verifier(ctrl),
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		unknown := group(remote, 3, 1)
+		got, err := requester.Groups(ctx, []*hiddenpath.Group{owned, learned, unknown},
+			&net.UDPAddr{})
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, owned, got[0].Group)
+		assert.NotNil(t, got[0].Signed)
+		assert.Equal(t, learned, got[1].Group)
+		assert.NotNil(t, got[1].Signed)
+	})
+	t.Run("signature by non-owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// The local AS signs the configuration of a group it does not own.
+		impostor := hiddenpath.NewGroupStore(hiddenpath.Groups{learned.ID: learned}, nil)
+		svc := xtest.NewGRPCService()
+		hspb.RegisterHiddenSegmentGroupServiceServer(svc.Server(), hpgrpc.GroupServer{
+			Groups:  impostor,
+			LocalIA: remote,
+			Signer:  localSigner,
+		})
+		svc.Start(t)
+
+		requester := hpgrpc.GroupRequester{
+			Dialer:   svc,
+			Verifier: verifier(ctrl),
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		got, err := requester.Groups(ctx, []*hiddenpath.Group{learned}, &net.UDPAddr{})
+		assert.Error(t, err)
+		assert.Empty(t, got)
+	})
+	t.Run("remote non-member", func(t *testing.T) {
+		server := hpgrpc.GroupServer{
+			Groups:  newStore(t),
+			LocalIA: local,
+			Signer:  localSigner,
+		}
+		req := &hspb.HiddenSegmentGroupsRequest{
+			GroupIds: []uint64{owned.ID.ToUint64(), learned.ID.ToUint64()},
+		}
+		peerCtx := func(ia addr.IA) context.Context {
+			return peer.NewContext(context.Background(), &peer.Peer{
+				Addr: &snet.UDPAddr{IA: ia},
+			})
+		}
+
+		rep, err := server.HiddenSegmentGroups(peerCtx(xtest.MustParseIA("1-ff00:0:999")), req)
+		require.NoError(t, err)
+		assert.Empty(t, rep.Groups)
+
+		rep, err = server.HiddenSegmentGroups(peerCtx(member), req)
+		require.NoError(t, err)
+		assert.Len(t, rep.Groups, 2)
+	})
+	t.Run("sign error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		signer := mock_grpc.NewMockSigner(ctrl)
+		signer.EXPECT().Sign(gomock.Any(), gomock.Any()).Return(nil, serrors.New("test"))
+		server := hpgrpc.GroupServer{
+			Groups:  newStore(t),
+			LocalIA: local,
+			Signer:  signer,
+		}
+		rep, err := server.HiddenSegmentGroups(context.Background(),
+			&hspb.HiddenSegmentGroupsRequest{GroupIds: []uint64{owned.ID.ToUint64()}})
+		require.NoError(t, err)
+		assert.Empty(t, rep.Groups)
+	})
+}
@@ -56,7 +56,8 @@ func (s Registerer) RegisterSegment(ctx context.Context,
 		return serrors.New("no segments to register")
 	}
 	if reg.GroupID.ToUint64() == 0 { // do regular public registration
-		return s.RegularRegistration.RegisterSegment(ctx, reg.Seg, remote)
+		_, err := s.RegularRegistration.RegisterSegment(ctx, reg.Seg, remote)
+		return err
 	}
 
 	conn, err := s.Dialer.Dial(ctx, remote)
@@ -35,7 +35,7 @@ type Requester struct {
 	Dialer libgrpc.Dialer
 	// HPGroups is used to fetch hidden segments when the destination IA belongs
 	// to the writers of a group configuration.
-	HPGroups hiddenpath.Groups
+	HPGroups hiddenpath.GroupProvider
 	// RegularLookup is the regular segment lookup.
 	RegularLookup segfetcher.RPC
 }
@@ -84,7 +84,7 @@ func (f *Requester) hiddenSegments(ctx context.Context, req segfetcher.Request,
 	defer conn.Close()
 
 	groups := []uint64{}
-	for _, g := range f.HPGroups {
+	for _, g := range f.HPGroups.CurrentGroups() {
 		if _, ok := g.Writers[req.Dst]; ok {
 			groups = append(groups, g.ID.ToUint64())
 		}
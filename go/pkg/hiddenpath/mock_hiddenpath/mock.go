@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/scionproto/scion/go/pkg/hiddenpath (interfaces: Store,RPC,Verifier,Lookuper,AddressResolver,Discoverer,Registry,Register)
+// Source: github.com/scionproto/scion/go/pkg/hiddenpath (interfaces: Store,RPC,Verifier,Lookuper,AddressResolver,Discoverer,Registry,Register,GroupRPC)
 
 // Package mock_hiddenpath is a generated GoMock package.
 package mock_hiddenpath
@@ -328,3 +328,41 @@ func (mr *MockRegisterMockRecorder) RegisterSegment(arg0, arg1, arg2 interface{}
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterSegment", reflect.TypeOf((*MockRegister)(nil).RegisterSegment), arg0, arg1, arg2)
 }
+
+// MockGroupRPC is a mock of GroupRPC interface
+type MockGroupRPC struct {
+	ctrl     *gomock.Controller
+	recorder *MockGroupRPCMockRecorder
+}
+
+// MockGroupRPCMockRecorder is the mock recorder for MockGroupRPC
+type MockGroupRPCMockRecorder struct {
+	mock *MockGroupRPC
+}
+
+// NewMockGroupRPC creates a new mock instance
+func NewMockGroupRPC(ctrl *gomock.Controller) *MockGroupRPC {
+	mock := &MockGroupRPC{ctrl: ctrl}
+	mock.recorder = &MockGroupRPCMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockGroupRPC) EXPECT() *MockGroupRPCMockRecorder {
+	return m.recorder
+}
+
+// Groups mocks base method
+func (m *MockGroupRPC) Groups(arg0 context.Context, arg1 []*hiddenpath.Group, arg2 net.Addr) ([]hiddenpath.SignedGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Groups", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]hiddenpath.SignedGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Groups indicates an expected call of Groups
+func (mr *MockGroupRPCMockRecorder) Groups(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Groups", reflect.TypeOf((*MockGroupRPC)(nil).Groups), arg0, arg1, arg2)
+}
@@ -31,10 +31,23 @@ type InterfacePolicy struct {
 	Groups map[GroupID]*Group
 }
 
+// RegistrationPolicyProvider provides the current registration policy.
+type RegistrationPolicyProvider interface {
+	// CurrentRegistrationPolicy returns the current registration policy. The
+	// returned policy must not be modified.
+	CurrentRegistrationPolicy() RegistrationPolicy
+}
+
 // RegistrationPolicy describes the policy for registering segments. The map is
 // keyed by ingress interface ID.
 type RegistrationPolicy map[uint64]InterfacePolicy
 
+// CurrentRegistrationPolicy returns the policy itself. It implements the
+// RegistrationPolicyProvider interface for a static policy.
+func (p RegistrationPolicy) CurrentRegistrationPolicy() RegistrationPolicy {
+	return p
+}
+
 // Validate validates the registration policy.
 func (p RegistrationPolicy) Validate() error {
 	for ifID, p := range p {
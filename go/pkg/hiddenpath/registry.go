@@ -41,8 +41,8 @@ type Registration struct {
 
 // RegistryServer handles hidden segment registrations.
 type RegistryServer struct {
-	// Groups is the current set of groups.
-	Groups map[GroupID]*Group
+	// Groups provides the current set of groups.
+	Groups GroupProvider
 	// DB is used to write received segments.
 	DB Store
 	// Verifier is used to verify the received segments.
@@ -54,7 +54,7 @@ type RegistryServer struct {
 // Register registers the given registration.
 func (h RegistryServer) Register(ctx context.Context, reg Registration) error {
 	// validate first
-	group, ok := h.Groups[reg.GroupID]
+	group, ok := h.Groups.CurrentGroups()[reg.GroupID]
 	if !ok {
 		return serrors.New("unknown group")
 	}
@@ -0,0 +1,168 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package linklatency makes the round-trip times that the border routers
+// measure on their external links available to the control service.
+//
+// The router serves its measurements as JSON on a status page (see
+// NewHandler). The control service periodically fetches the measurements of
+// all border routers of the AS (see Fetcher), and keeps the recent ones in a
+// Store, which provides the link latencies for the StaticInfo extension of the
+// beacons.
+package linklatency
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/util"
+)
+
+// Measurement is the round-trip time measured on the external link attached
+// to an interface.
+type Measurement struct {
+	// Interface is the ID of the interface.
+	Interface common.IFIDType `json:"interface_id"`
+	// RTT is the measured round-trip time.
+	RTT util.DurWrap `json:"rtt"`
+	// Timestamp is the time of the measurement.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Source provides the measurements of a border router.
+type Source interface {
+	LinkLatencies() []Measurement
+}
+
+// NewHandler returns an HTTP handler that serves the measurements of the
+// source as JSON.
+func NewHandler(src Source) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		ms := src.LinkLatencies()
+		if ms == nil {
+			ms = []Measurement{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		if err := enc.Encode(ms); err != nil {
+			http.Error(w, "unable to marshal response", http.StatusInternalServerError)
+		}
+	}
+}
+
+// Fetch fetches the measurements from the given URL.
+func Fetch(ctx context.Context, client *http.Client, url string) ([]Measurement, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	rep, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rep.Body.Close()
+	if rep.StatusCode != http.StatusOK {
+		return nil, serrors.New("unexpected status code", "status", rep.Status)
+	}
+	var ms []Measurement
+	if err := json.NewDecoder(rep.Body).Decode(&ms); err != nil {
+		return nil, serrors.WrapStr("decoding measurements", err)
+	}
+	return ms, nil
+}
+
+// Fetcher is a periodic task that fetches the measurements of the border
+// routers and stores them.
+type Fetcher struct {
+	// URLs are the URLs of the link latency status pages of the border
+	// routers.
+	URLs []string
+	// Client is the HTTP client used for fetching. If it is nil, the default
+	// client is used.
+	Client *http.Client
+	// Store stores the fetched measurements.
+	Store *Store
+}
+
+// Name returns the task name.
+func (f *Fetcher) Name() string {
+	return "control_link_latency_fetcher"
+}
+
+// Run fetches the measurements from all border routers. Failures are logged,
+// the measurements of the other border routers are still stored.
+func (f *Fetcher) Run(ctx context.Context) {
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	logger := log.FromCtx(ctx)
+	for _, url := range f.URLs {
+		ms, err := Fetch(ctx, client, url)
+		if err != nil {
+			logger.Info("Failed to fetch link latencies", "url", url, "err", err)
+			continue
+		}
+		f.Store.Update(ms)
+	}
+}
+
+// Store keeps the most recent measurement per interface.
+type Store struct {
+	// MaxAge is the maximum age of a measurement that is still considered.
+	MaxAge time.Duration
+
+	mu           sync.RWMutex
+	measurements map[common.IFIDType]Measurement
+}
+
+// Update stores the measurements. Measurements that are older than the ones
+// already stored are ignored.
+func (s *Store) Update(ms []Measurement) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.measurements == nil {
+		s.measurements = make(map[common.IFIDType]Measurement)
+	}
+	for _, m := range ms {
+		if m.Interface == 0 || m.RTT.Duration <= 0 {
+			continue
+		}
+		if old, ok := s.measurements[m.Interface]; ok && old.Timestamp.After(m.Timestamp) {
+			continue
+		}
+		s.measurements[m.Interface] = m
+	}
+}
+
+// LinkLatency returns the one-way latency of the external link attached to
+// the interface. It is half the most recently measured round-trip time. The
+// second return value indicates whether a measurement that is not older than
+// MaxAge is available.
+func (s *Store) LinkLatency(ifid common.IFIDType) (time.Duration, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	m, ok := s.measurements[ifid]
+	if !ok || time.Since(m.Timestamp) > s.MaxAge {
+		return 0, false
+	}
+	return m.RTT.Duration / 2, true
+}
@@ -0,0 +1,129 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linklatency_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/pkg/linklatency"
+)
+
+type sourceFunc func() []linklatency.Measurement
+
+func (f sourceFunc) LinkLatencies() []linklatency.Measurement {
+	return f()
+}
+
+func TestFetch(t *testing.T) {
+	now := time.Now().UTC().Round(time.Second)
+	ms := []linklatency.Measurement{
+		{
+			Interface: 1,
+			RTT:       util.DurWrap{Duration: 1234 * time.Microsecond},
+			Timestamp: now,
+		},
+		{
+			Interface: 2,
+			RTT:       util.DurWrap{Duration: 20 * time.Millisecond},
+			Timestamp: now.Add(-time.Second),
+		},
+	}
+	srv := httptest.NewServer(linklatency.NewHandler(sourceFunc(
+		func() []linklatency.Measurement { return ms },
+	)))
+	defer srv.Close()
+
+	fetched, err := linklatency.Fetch(context.Background(), srv.Client(), srv.URL)
+	require.NoError(t, err)
+	assert.Len(t, fetched, len(ms))
+	for i := range ms {
+		assert.Equal(t, ms[i].Interface, fetched[i].Interface)
+		assert.Equal(t, ms[i].RTT, fetched[i].RTT)
+		assert.True(t, ms[i].Timestamp.Equal(fetched[i].Timestamp))
+	}
+
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+	_, err = linklatency.Fetch(context.Background(), notFound.Client(), notFound.URL)
+	assert.Error(t, err)
+}
+
+func TestFetcherRun(t *testing.T) {
+	now := time.Now()
+	srv := httptest.NewServer(linklatency.NewHandler(sourceFunc(
+		func() []linklatency.Measurement {
+			return []linklatency.Measurement{{
+				Interface: 1,
+				RTT:       util.DurWrap{Duration: 10 * time.Millisecond},
+				Timestamp: now,
+			}}
+		},
+	)))
+	defer srv.Close()
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+
+	store := &linklatency.Store{MaxAge: time.Minute}
+	fetcher := &linklatency.Fetcher{
+		URLs:  []string{notFound.URL, srv.URL},
+		Store: store,
+	}
+	fetcher.Run(context.Background())
+	latency, ok := store.LinkLatency(1)
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Millisecond, latency)
+}
+
+func TestStore(t *testing.T) {
+	now := time.Now()
+	measurement := func(ifid common.IFIDType, rtt time.Duration,
+		ts time.Time) linklatency.Measurement {
+
+		return linklatency.Measurement{
+			Interface: ifid,
+			RTT:       util.DurWrap{Duration: rtt},
+			Timestamp: ts,
+		}
+	}
+	store := &linklatency.Store{MaxAge: time.Minute}
+	store.Update([]linklatency.Measurement{
+		measurement(1, 10*time.Millisecond, now),
+		measurement(2, 20*time.Millisecond, now.Add(-2*time.Minute)),
+		measurement(3, 0, now),
+	})
+	// Older measurements do not replace newer ones.
+	store.Update([]linklatency.Measurement{
+		measurement(1, 30*time.Millisecond, now.Add(-time.Second)),
+	})
+
+	latency, ok := store.LinkLatency(1)
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Millisecond, latency)
+	_, ok = store.LinkLatency(2)
+	assert.False(t, ok, "expired measurement")
+	_, ok = store.LinkLatency(3)
+	assert.False(t, ok, "invalid measurement")
+	_, ok = store.LinkLatency(4)
+	assert.False(t, ok, "unknown interface")
+}
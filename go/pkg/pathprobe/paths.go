@@ -19,7 +19,9 @@ package pathprobe
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"net"
+	"time"
 
 	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/log"
@@ -50,6 +52,9 @@ type Status struct {
 	Status         StatusName
 	LocalIP        net.IP
 	AdditionalInfo string
+	// RTT is the round trip time of the probe. It is only set if a reply was
+	// received.
+	RTT time.Duration
 }
 
 // Predefined path status
@@ -122,6 +127,7 @@ func (p Prober) GetStatuses(ctx context.Context, paths []snet.Path) (map[string]
 	// each different underlay network. The key is the source IP.
 	conns := make(map[string]snet.PacketConn)
 	statuses := make(map[string]Status, len(paths))
+	sent := make(map[string]time.Time, len(paths))
 	scmpReplies := make(chan reply, 10)
 	var sendErrors serrors.List
 
@@ -156,6 +162,7 @@ func (p Prober) GetStatuses(ctx context.Context, paths []snet.Path) (map[string]
 		localAddr := snet.SCIONAddress{IA: p.LocalIA, Host: addr.HostFromIP(localIP)}
 		timeout.LocalIP = localIP
 		statuses[PathKey(path)] = timeout
+		sent[PathKey(path)] = time.Now()
 		if err := p.sendProbe(conn, localAddr, path, uint16(i)); err != nil {
 			sendErrors = append(sendErrors, err)
 		}
@@ -181,6 +188,9 @@ func (p Prober) GetStatuses(ctx context.Context, paths []snet.Path) (map[string]
 				receiveErrors = append(receiveErrors, reply.Error)
 				continue
 			}
+			if start, ok := sent[reply.PathKey]; ok {
+				reply.Status.RTT = reply.Received.Sub(start)
+			}
 			statuses[reply.PathKey] = reply.Status
 		case err := <-drainError:
 			return nil, err
@@ -194,6 +204,43 @@ func (p Prober) GetStatuses(ctx context.Context, paths []snet.Path) (map[string]
 	return statuses, nil
 }
 
+// PathProber probes paths with the Prober and reports the round trip times of
+// the paths that are alive. It can be used to probe the paths of a
+// multipath.Conn.
+type PathProber struct {
+	// LocalIA is the source ISD-AS.
+	LocalIA addr.IA
+	// LocalIP is the local IP endpoint to be used when probing. See
+	// Prober.LocalIP.
+	LocalIP net.IP
+}
+
+// Probe probes the paths to dst. It returns the round trip time of every path
+// that is alive, keyed by the path fingerprint. The context must have a
+// deadline.
+func (p PathProber) Probe(ctx context.Context, dst addr.IA,
+	paths []snet.Path) (map[snet.PathFingerprint]time.Duration, error) {
+
+	prober := Prober{
+		DstIA:   dst,
+		LocalIA: p.LocalIA,
+		LocalIP: p.LocalIP,
+		ID:      uint16(rand.Uint32()),
+	}
+	statuses, err := prober.GetStatuses(ctx, FilterEmptyPaths(paths))
+	if err != nil {
+		return nil, err
+	}
+	rtts := make(map[snet.PathFingerprint]time.Duration)
+	for _, path := range paths {
+		status, ok := statuses[PathKey(path)]
+		if ok && status.Status == StatusAlive {
+			rtts[snet.Fingerprint(path)] = status.RTT
+		}
+	}
+	return rtts, nil
+}
+
 func (p Prober) resolveLocalIP(target *net.UDPAddr) (net.IP, error) {
 	if p.LocalIP != nil {
 		return p.LocalIP, nil
@@ -236,9 +283,10 @@ func (p Prober) sendProbe(
 }
 
 type reply struct {
-	Status  Status
-	Error   error
-	PathKey string
+	Status   Status
+	Error    error
+	PathKey  string
+	Received time.Time
 }
 
 type scmpHandler struct {
@@ -269,11 +317,13 @@ func (h *scmpHandler) Handle(pkt *snet.Packet) error {
 	if err := setAlertFlag(&reversePath, false); err != nil {
 		return err
 	}
+	received := time.Now()
 	s, err := h.handle(pkt)
 	h.replies <- reply{
-		Status:  s,
-		Error:   err,
-		PathKey: string(reversePath.Raw),
+		Status:   s,
+		Error:    err,
+		PathKey:  string(reversePath.Raw),
+		Received: received,
 	}
 	return nil
 }
@@ -0,0 +1,37 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ping
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/snet"
+)
+
+// Receive passes echo replies with the given sequence numbers to a pinger and
+// returns the resulting stats.
+func Receive(timeout time.Duration, seqs ...uint16) Stats {
+	p := &pinger{timeout: timeout, sentSequence: -1, receivedSequence: -1}
+	for _, seq := range seqs {
+		pld := make([]byte, 8)
+		binary.BigEndian.PutUint64(pld, uint64(time.Now().UnixNano()))
+		p.receive(reply{
+			Received: time.Now(),
+			Reply:    snet.SCMPEchoReply{SeqNumber: seq, Payload: pld},
+		})
+	}
+	return p.stats
+}
@@ -18,6 +18,7 @@ package ping
 import (
 	"context"
 	"encoding/binary"
+	"math"
 	"math/rand"
 	"net"
 	"time"
@@ -35,6 +36,56 @@ import (
 type Stats struct {
 	Sent     int
 	Received int
+	// RTTs contains the round trip time of every reply, excluding
+	// duplicates, in the order the replies were received.
+	RTTs []time.Duration
+	// Reordered is the number of replies that were received out of order.
+	Reordered int
+	// Duplicates is the number of duplicate replies.
+	Duplicates int
+}
+
+// Loss returns the fraction of echo requests that were not answered.
+func (s Stats) Loss() float64 {
+	if s.Sent == 0 {
+		return 0
+	}
+	loss := 1 - float64(s.Received-s.Duplicates)/float64(s.Sent)
+	return math.Max(loss, 0)
+}
+
+// RTTSummary summarizes the round trip times of a ping run.
+type RTTSummary struct {
+	Min time.Duration
+	Avg time.Duration
+	Max time.Duration
+	// MDev is the standard deviation of the round trip times.
+	MDev time.Duration
+}
+
+// RTT summarizes the round trip times. If no reply was received, the zero
+// value is returned.
+func (s Stats) RTT() RTTSummary {
+	if len(s.RTTs) == 0 {
+		return RTTSummary{}
+	}
+	summary := RTTSummary{Min: s.RTTs[0], Max: s.RTTs[0]}
+	var sum, sumSquares float64
+	for _, rtt := range s.RTTs {
+		if rtt < summary.Min {
+			summary.Min = rtt
+		}
+		if rtt > summary.Max {
+			summary.Max = rtt
+		}
+		sum += float64(rtt)
+		sumSquares += float64(rtt) * float64(rtt)
+	}
+	n := float64(len(s.RTTs))
+	avg := sum / n
+	summary.Avg = time.Duration(avg)
+	summary.MDev = time.Duration(math.Sqrt(math.Max(sumSquares/n-avg*avg, 0)))
+	return summary
 }
 
 // Update contains intermediary information about a received echo reply
@@ -145,11 +196,15 @@ type pinger struct {
 	pld              []byte
 	sentSequence     int
 	receivedSequence int
-	stats            Stats
+	// received tracks the sequence numbers replies were received for. At most
+	// 2^16 requests are sent per Ping, thus, the sequence numbers do not wrap.
+	received seqSet
+	stats    Stats
 }
 
 func (p *pinger) Ping(ctx context.Context, remote *snet.UDPAddr) (Stats, error) {
 	p.sentSequence, p.receivedSequence = -1, -1
+	p.received = seqSet{}
 	send := time.NewTicker(p.interval)
 	defer send.Stop()
 
@@ -233,17 +288,22 @@ func (p *pinger) receive(reply reply) {
 		Round(time.Microsecond)
 	var state State
 	switch {
+	case !p.received.Add(reply.Reply.SeqNumber):
+		state = Duplicate
+		p.stats.Duplicates++
 	case rtt > p.timeout:
 		state = AfterTimeout
 	case int(reply.Reply.SeqNumber) < p.receivedSequence:
 		state = OutOfOrder
-	case int(reply.Reply.SeqNumber) == p.receivedSequence:
-		state = Duplicate
+		p.stats.Reordered++
 	default:
 		state = Success
 		p.receivedSequence = int(reply.Reply.SeqNumber)
 	}
 	p.stats.Received++
+	if state != Duplicate {
+		p.stats.RTTs = append(p.stats.RTTs, rtt)
+	}
 	if p.updateHandler != nil {
 		p.updateHandler(Update{
 			RTT:      rtt,
@@ -275,6 +335,20 @@ func (p *pinger) drain(ctx context.Context) {
 	}
 }
 
+// seqSet is a set of sequence numbers.
+type seqSet [1 << 16 / 64]uint64
+
+// Add adds the sequence number to the set. It returns false if the sequence
+// number was already in the set.
+func (s *seqSet) Add(seq uint16) bool {
+	word, bit := seq/64, uint64(1)<<(seq%64)
+	if s[word]&bit != 0 {
+		return false
+	}
+	s[word] |= bit
+	return true
+}
+
 type reply struct {
 	Received time.Time
 	Source   snet.SCIONAddress
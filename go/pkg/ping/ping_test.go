@@ -0,0 +1,108 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ping_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/pkg/ping"
+)
+
+func TestStats(t *testing.T) {
+	testCases := map[string]struct {
+		Stats        ping.Stats
+		ExpectedLoss float64
+		ExpectedRTT  ping.RTTSummary
+	}{
+		"nothing sent": {},
+		"nothing received": {
+			Stats:        ping.Stats{Sent: 4},
+			ExpectedLoss: 1,
+		},
+		"replies": {
+			Stats: ping.Stats{
+				Sent:     4,
+				Received: 3,
+				RTTs:     []time.Duration{2 * time.Millisecond, 4 * time.Millisecond},
+				// One of the received replies is a duplicate.
+				Duplicates: 1,
+			},
+			ExpectedLoss: 0.5,
+			ExpectedRTT: ping.RTTSummary{
+				Min:  2 * time.Millisecond,
+				Avg:  3 * time.Millisecond,
+				Max:  4 * time.Millisecond,
+				MDev: time.Millisecond,
+			},
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.ExpectedLoss, tc.Stats.Loss())
+			assert.Equal(t, tc.ExpectedRTT, tc.Stats.RTT())
+		})
+	}
+}
+
+func TestReceive(t *testing.T) {
+	testCases := map[string]struct {
+		Sequences          []uint16
+		ExpectedDuplicates int
+		ExpectedReordered  int
+		ExpectedRTTs       int
+	}{
+		"in order": {
+			Sequences:    []uint16{0, 1, 2},
+			ExpectedRTTs: 3,
+		},
+		"duplicate of latest": {
+			Sequences:          []uint16{0, 1, 1},
+			ExpectedDuplicates: 1,
+			ExpectedRTTs:       2,
+		},
+		"duplicate of older": {
+			Sequences:          []uint16{0, 1, 2, 0, 1},
+			ExpectedDuplicates: 2,
+			ExpectedRTTs:       3,
+		},
+		"reordered": {
+			Sequences:         []uint16{1, 0, 2},
+			ExpectedReordered: 1,
+			ExpectedRTTs:      3,
+		},
+		"duplicate of reordered": {
+			Sequences:          []uint16{1, 0, 0},
+			ExpectedDuplicates: 1,
+			ExpectedReordered:  1,
+			ExpectedRTTs:       2,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			stats := ping.Receive(time.Minute, tc.Sequences...)
+			assert.Equal(t, len(tc.Sequences), stats.Received)
+			assert.Equal(t, tc.ExpectedDuplicates, stats.Duplicates)
+			assert.Equal(t, tc.ExpectedReordered, stats.Reordered)
+			assert.Len(t, stats.RTTs, tc.ExpectedRTTs)
+		})
+	}
+}
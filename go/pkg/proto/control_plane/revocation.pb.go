@@ -0,0 +1,307 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.13.0
+// source: proto/control_plane/v1/revocation.proto
+
+package control_plane
+
+import (
+	context "context"
+	proto "github.com/golang/protobuf/proto"
+	crypto "github.com/scionproto/scion/go/pkg/proto/crypto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type RevocationsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SignedRevocations []*crypto.SignedMessage `protobuf:"bytes,1,rep,name=signed_revocations,json=signedRevocations,proto3" json:"signed_revocations,omitempty"`
+}
+
+func (x *RevocationsRequest) Reset() {
+	*x = RevocationsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_control_plane_v1_revocation_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevocationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevocationsRequest) ProtoMessage() {}
+
+func (x *RevocationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_control_plane_v1_revocation_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevocationsRequest.ProtoReflect.Descriptor instead.
+func (*RevocationsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_control_plane_v1_revocation_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RevocationsRequest) GetSignedRevocations() []*crypto.SignedMessage {
+	if x != nil {
+		return x.SignedRevocations
+	}
+	return nil
+}
+
+type RevocationsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *RevocationsResponse) Reset() {
+	*x = RevocationsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_control_plane_v1_revocation_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevocationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevocationsResponse) ProtoMessage() {}
+
+func (x *RevocationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_control_plane_v1_revocation_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevocationsResponse.ProtoReflect.Descriptor instead.
+func (*RevocationsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_control_plane_v1_revocation_proto_rawDescGZIP(), []int{1}
+}
+
+var File_proto_control_plane_v1_revocation_proto protoreflect.FileDescriptor
+
+var file_proto_control_plane_v1_revocation_proto_rawDesc = []byte{
+	0x0a, 0x27, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f,
+	0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x72, 0x65, 0x76, 0x6f, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x16, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76,
+	0x31, 0x1a, 0x1c, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2f,
+	0x76, 0x31, 0x2f, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
+	0x63, 0x0a, 0x12, 0x52, 0x65, 0x76, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x4d, 0x0a, 0x12, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f,
+	0x72, 0x65, 0x76, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x52, 0x11, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x52, 0x65, 0x76, 0x6f, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x22, 0x15, 0x0a, 0x13, 0x52, 0x65, 0x76, 0x6f, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x7d, 0x0a, 0x11, 0x52,
+	0x65, 0x76, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x12, 0x68, 0x0a, 0x0b, 0x52, 0x65, 0x76, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12,
+	0x2a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f,
+	0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x38, 0x5a, 0x36, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x63, 0x69, 0x6f, 0x6e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x63, 0x69, 0x6f, 0x6e, 0x2f, 0x67, 0x6f, 0x2f, 0x70, 0x6b, 0x67,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70,
+	0x6c, 0x61, 0x6e, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_control_plane_v1_revocation_proto_rawDescOnce sync.Once
+	file_proto_control_plane_v1_revocation_proto_rawDescData = file_proto_control_plane_v1_revocation_proto_rawDesc
+)
+
+func file_proto_control_plane_v1_revocation_proto_rawDescGZIP() []byte {
+	file_proto_control_plane_v1_revocation_proto_rawDescOnce.Do(func() {
+		file_proto_control_plane_v1_revocation_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_control_plane_v1_revocation_proto_rawDescData)
+	})
+	return file_proto_control_plane_v1_revocation_proto_rawDescData
+}
+
+var file_proto_control_plane_v1_revocation_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_proto_control_plane_v1_revocation_proto_goTypes = []interface{}{
+	(*RevocationsRequest)(nil),   // 0: proto.control_plane.v1.RevocationsRequest
+	(*RevocationsResponse)(nil),  // 1: proto.control_plane.v1.RevocationsResponse
+	(*crypto.SignedMessage)(nil), // 2: proto.crypto.v1.SignedMessage
+}
+var file_proto_control_plane_v1_revocation_proto_depIdxs = []int32{
+	2, // 0: proto.control_plane.v1.RevocationsRequest.signed_revocations:type_name -> proto.crypto.v1.SignedMessage
+	0, // 1: proto.control_plane.v1.RevocationService.Revocations:input_type -> proto.control_plane.v1.RevocationsRequest
+	1, // 2: proto.control_plane.v1.RevocationService.Revocations:output_type -> proto.control_plane.v1.RevocationsResponse
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_proto_control_plane_v1_revocation_proto_init() }
+func file_proto_control_plane_v1_revocation_proto_init() {
+	if File_proto_control_plane_v1_revocation_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_control_plane_v1_revocation_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevocationsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_control_plane_v1_revocation_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevocationsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_control_plane_v1_revocation_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_control_plane_v1_revocation_proto_goTypes,
+		DependencyIndexes: file_proto_control_plane_v1_revocation_proto_depIdxs,
+		MessageInfos:      file_proto_control_plane_v1_revocation_proto_msgTypes,
+	}.Build()
+	File_proto_control_plane_v1_revocation_proto = out.File
+	file_proto_control_plane_v1_revocation_proto_rawDesc = nil
+	file_proto_control_plane_v1_revocation_proto_goTypes = nil
+	file_proto_control_plane_v1_revocation_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// RevocationServiceClient is the client API for RevocationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type RevocationServiceClient interface {
+	Revocations(ctx context.Context, in *RevocationsRequest, opts ...grpc.CallOption) (*RevocationsResponse, error)
+}
+
+type revocationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRevocationServiceClient(cc grpc.ClientConnInterface) RevocationServiceClient {
+	return &revocationServiceClient{cc}
+}
+
+func (c *revocationServiceClient) Revocations(ctx context.Context, in *RevocationsRequest, opts ...grpc.CallOption) (*RevocationsResponse, error) {
+	out := new(RevocationsResponse)
+	err := c.cc.Invoke(ctx, "/proto.control_plane.v1.RevocationService/Revocations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RevocationServiceServer is the server API for RevocationService service.
+type RevocationServiceServer interface {
+	Revocations(context.Context, *RevocationsRequest) (*RevocationsResponse, error)
+}
+
+// UnimplementedRevocationServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedRevocationServiceServer struct {
+}
+
+func (*UnimplementedRevocationServiceServer) Revocations(context.Context, *RevocationsRequest) (*RevocationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Revocations not implemented")
+}
+
+func RegisterRevocationServiceServer(s *grpc.Server, srv RevocationServiceServer) {
+	s.RegisterService(&_RevocationService_serviceDesc, srv)
+}
+
+func _RevocationService_Revocations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevocationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RevocationServiceServer).Revocations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.control_plane.v1.RevocationService/Revocations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RevocationServiceServer).Revocations(ctx, req.(*RevocationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _RevocationService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.control_plane.v1.RevocationService",
+	HandlerType: (*RevocationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Revocations",
+			Handler:    _RevocationService_Revocations_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/control_plane/v1/revocation.proto",
+}
@@ -243,6 +243,8 @@ type SegmentsRegistrationResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	StateId uint64 `protobuf:"varint,1,opt,name=state_id,json=stateId,proto3" json:"state_id,omitempty"`
 }
 
 func (x *SegmentsRegistrationResponse) Reset() {
@@ -277,12 +279,20 @@ func (*SegmentsRegistrationResponse) Descriptor() ([]byte, []int) {
 	return file_proto_control_plane_v1_seg_proto_rawDescGZIP(), []int{3}
 }
 
+func (x *SegmentsRegistrationResponse) GetStateId() uint64 {
+	if x != nil {
+		return x.StateId
+	}
+	return 0
+}
+
 type BeaconRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Segment *PathSegment `protobuf:"bytes,1,opt,name=segment,proto3" json:"segment,omitempty"`
+	Segment   *PathSegment `protobuf:"bytes,1,opt,name=segment,proto3" json:"segment,omitempty"`
+	LatestTrc *TRCID       `protobuf:"bytes,2,opt,name=latest_trc,json=latestTrc,proto3" json:"latest_trc,omitempty"`
 }
 
 func (x *BeaconRequest) Reset() {
@@ -324,6 +334,76 @@ func (x *BeaconRequest) GetSegment() *PathSegment {
 	return nil
 }
 
+func (x *BeaconRequest) GetLatestTrc() *TRCID {
+	if x != nil {
+		return x.LatestTrc
+	}
+	return nil
+}
+
+type TRCID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Isd    uint32 `protobuf:"varint,1,opt,name=isd,proto3" json:"isd,omitempty"`
+	Base   uint64 `protobuf:"varint,2,opt,name=base,proto3" json:"base,omitempty"`
+	Serial uint64 `protobuf:"varint,3,opt,name=serial,proto3" json:"serial,omitempty"`
+}
+
+func (x *TRCID) Reset() {
+	*x = TRCID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TRCID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TRCID) ProtoMessage() {}
+
+func (x *TRCID) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TRCID.ProtoReflect.Descriptor instead.
+func (*TRCID) Descriptor() ([]byte, []int) {
+	return file_proto_control_plane_v1_seg_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TRCID) GetIsd() uint32 {
+	if x != nil {
+		return x.Isd
+	}
+	return 0
+}
+
+func (x *TRCID) GetBase() uint64 {
+	if x != nil {
+		return x.Base
+	}
+	return 0
+}
+
+func (x *TRCID) GetSerial() uint64 {
+	if x != nil {
+		return x.Serial
+	}
+	return 0
+}
+
 type BeaconResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -333,7 +413,7 @@ type BeaconResponse struct {
 func (x *BeaconResponse) Reset() {
 	*x = BeaconResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[5]
+		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -346,7 +426,7 @@ func (x *BeaconResponse) String() string {
 func (*BeaconResponse) ProtoMessage() {}
 
 func (x *BeaconResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[5]
+	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -359,7 +439,7 @@ func (x *BeaconResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BeaconResponse.ProtoReflect.Descriptor instead.
 func (*BeaconResponse) Descriptor() ([]byte, []int) {
-	return file_proto_control_plane_v1_seg_proto_rawDescGZIP(), []int{5}
+	return file_proto_control_plane_v1_seg_proto_rawDescGZIP(), []int{6}
 }
 
 type PathSegment struct {
@@ -374,7 +454,7 @@ type PathSegment struct {
 func (x *PathSegment) Reset() {
 	*x = PathSegment{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[6]
+		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -387,7 +467,7 @@ func (x *PathSegment) String() string {
 func (*PathSegment) ProtoMessage() {}
 
 func (x *PathSegment) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[6]
+	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -400,7 +480,7 @@ func (x *PathSegment) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PathSegment.ProtoReflect.Descriptor instead.
 func (*PathSegment) Descriptor() ([]byte, []int) {
-	return file_proto_control_plane_v1_seg_proto_rawDescGZIP(), []int{6}
+	return file_proto_control_plane_v1_seg_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *PathSegment) GetSegmentInfo() []byte {
@@ -429,7 +509,7 @@ type SegmentInformation struct {
 func (x *SegmentInformation) Reset() {
 	*x = SegmentInformation{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[7]
+		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -442,7 +522,7 @@ func (x *SegmentInformation) String() string {
 func (*SegmentInformation) ProtoMessage() {}
 
 func (x *SegmentInformation) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[7]
+	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -455,7 +535,7 @@ func (x *SegmentInformation) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SegmentInformation.ProtoReflect.Descriptor instead.
 func (*SegmentInformation) Descriptor() ([]byte, []int) {
-	return file_proto_control_plane_v1_seg_proto_rawDescGZIP(), []int{7}
+	return file_proto_control_plane_v1_seg_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *SegmentInformation) GetTimestamp() int64 {
@@ -484,7 +564,7 @@ type ASEntry struct {
 func (x *ASEntry) Reset() {
 	*x = ASEntry{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[8]
+		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -497,7 +577,7 @@ func (x *ASEntry) String() string {
 func (*ASEntry) ProtoMessage() {}
 
 func (x *ASEntry) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[8]
+	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -510,7 +590,7 @@ func (x *ASEntry) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ASEntry.ProtoReflect.Descriptor instead.
 func (*ASEntry) Descriptor() ([]byte, []int) {
-	return file_proto_control_plane_v1_seg_proto_rawDescGZIP(), []int{8}
+	return file_proto_control_plane_v1_seg_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ASEntry) GetSigned() *crypto.SignedMessage {
@@ -543,7 +623,7 @@ type ASEntrySignedBody struct {
 func (x *ASEntrySignedBody) Reset() {
 	*x = ASEntrySignedBody{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[9]
+		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -556,7 +636,7 @@ func (x *ASEntrySignedBody) String() string {
 func (*ASEntrySignedBody) ProtoMessage() {}
 
 func (x *ASEntrySignedBody) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[9]
+	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -569,7 +649,7 @@ func (x *ASEntrySignedBody) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ASEntrySignedBody.ProtoReflect.Descriptor instead.
 func (*ASEntrySignedBody) Descriptor() ([]byte, []int) {
-	return file_proto_control_plane_v1_seg_proto_rawDescGZIP(), []int{9}
+	return file_proto_control_plane_v1_seg_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ASEntrySignedBody) GetIsdAs() uint64 {
@@ -626,7 +706,7 @@ type HopEntry struct {
 func (x *HopEntry) Reset() {
 	*x = HopEntry{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[10]
+		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -639,7 +719,7 @@ func (x *HopEntry) String() string {
 func (*HopEntry) ProtoMessage() {}
 
 func (x *HopEntry) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[10]
+	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -652,7 +732,7 @@ func (x *HopEntry) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HopEntry.ProtoReflect.Descriptor instead.
 func (*HopEntry) Descriptor() ([]byte, []int) {
-	return file_proto_control_plane_v1_seg_proto_rawDescGZIP(), []int{10}
+	return file_proto_control_plane_v1_seg_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *HopEntry) GetHopField() *HopField {
@@ -683,7 +763,7 @@ type PeerEntry struct {
 func (x *PeerEntry) Reset() {
 	*x = PeerEntry{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[11]
+		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -696,7 +776,7 @@ func (x *PeerEntry) String() string {
 func (*PeerEntry) ProtoMessage() {}
 
 func (x *PeerEntry) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[11]
+	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -709,7 +789,7 @@ func (x *PeerEntry) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PeerEntry.ProtoReflect.Descriptor instead.
 func (*PeerEntry) Descriptor() ([]byte, []int) {
-	return file_proto_control_plane_v1_seg_proto_rawDescGZIP(), []int{11}
+	return file_proto_control_plane_v1_seg_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *PeerEntry) GetPeerIsdAs() uint64 {
@@ -754,7 +834,7 @@ type HopField struct {
 func (x *HopField) Reset() {
 	*x = HopField{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[12]
+		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -767,7 +847,7 @@ func (x *HopField) String() string {
 func (*HopField) ProtoMessage() {}
 
 func (x *HopField) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[12]
+	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -780,7 +860,7 @@ func (x *HopField) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HopField.ProtoReflect.Descriptor instead.
 func (*HopField) Descriptor() ([]byte, []int) {
-	return file_proto_control_plane_v1_seg_proto_rawDescGZIP(), []int{12}
+	return file_proto_control_plane_v1_seg_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *HopField) GetIngress() uint64 {
@@ -822,7 +902,7 @@ type SegmentsResponse_Segments struct {
 func (x *SegmentsResponse_Segments) Reset() {
 	*x = SegmentsResponse_Segments{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[13]
+		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -835,7 +915,7 @@ func (x *SegmentsResponse_Segments) String() string {
 func (*SegmentsResponse_Segments) ProtoMessage() {}
 
 func (x *SegmentsResponse_Segments) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[13]
+	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -869,7 +949,7 @@ type SegmentsRegistrationRequest_Segments struct {
 func (x *SegmentsRegistrationRequest_Segments) Reset() {
 	*x = SegmentsRegistrationRequest_Segments{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[15]
+		mi := &file_proto_control_plane_v1_seg_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -882,7 +962,7 @@ func (x *SegmentsRegistrationRequest_Segments) String() string {
 func (*SegmentsRegistrationRequest_Segments) ProtoMessage() {}
 
 func (x *SegmentsRegistrationRequest_Segments) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[15]
+	mi := &file_proto_control_plane_v1_seg_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -964,117 +1044,127 @@ var file_proto_control_plane_v1_seg_proto_rawDesc = []byte{
 	0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52,
 	0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
 	0x73, 0x74, 0x2e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x1e, 0x0a, 0x1c, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x39, 0x0a, 0x1c, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e,
 	0x74, 0x73, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x4e, 0x0a, 0x0d, 0x42, 0x65, 0x61, 0x63, 0x6f, 0x6e,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3d, 0x0a, 0x07, 0x73, 0x65, 0x67, 0x6d, 0x65,
-	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76,
-	0x31, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x73,
-	0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x10, 0x0a, 0x0e, 0x42, 0x65, 0x61, 0x63, 0x6f, 0x6e,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x70, 0x0a, 0x0b, 0x50, 0x61, 0x74, 0x68,
-	0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x65, 0x67, 0x6d, 0x65,
-	0x6e, 0x74, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x73,
-	0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x3e, 0x0a, 0x0a, 0x61, 0x73,
-	0x5f, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70,
-	0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x53, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
-	0x09, 0x61, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0x51, 0x0a, 0x12, 0x53, 0x65,
-	0x67, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1d,
-	0x0a, 0x0a, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x09, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x94, 0x01,
-	0x0a, 0x07, 0x41, 0x53, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x36, 0x0a, 0x06, 0x73, 0x69, 0x67,
-	0x6e, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e,
-	0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x06, 0x73, 0x69, 0x67, 0x6e, 0x65,
-	0x64, 0x12, 0x51, 0x0a, 0x08, 0x75, 0x6e, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x35, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x6f, 0x6e, 0x74,
-	0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74,
-	0x68, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x55, 0x6e, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64,
-	0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x08, 0x75, 0x6e, 0x73, 0x69,
-	0x67, 0x6e, 0x65, 0x64, 0x22, 0xb0, 0x02, 0x0a, 0x11, 0x41, 0x53, 0x45, 0x6e, 0x74, 0x72, 0x79,
-	0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x42, 0x6f, 0x64, 0x79, 0x12, 0x15, 0x0a, 0x06, 0x69, 0x73,
-	0x64, 0x5f, 0x61, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x69, 0x73, 0x64, 0x41,
-	0x73, 0x12, 0x1e, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x69, 0x73, 0x64, 0x5f, 0x61, 0x73,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x6e, 0x65, 0x78, 0x74, 0x49, 0x73, 0x64, 0x41,
-	0x73, 0x12, 0x3d, 0x0a, 0x09, 0x68, 0x6f, 0x70, 0x5f, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x6f, 0x6e,
-	0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x6f,
-	0x70, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x68, 0x6f, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79,
-	0x12, 0x44, 0x0a, 0x0c, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73,
-	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x73, 0x74, 0x61, 0x74, 0x65, 0x49,
+	0x64, 0x22, 0x8c, 0x01, 0x0a, 0x0d, 0x42, 0x65, 0x61, 0x63, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x3d, 0x0a, 0x07, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x6f, 0x6e,
+	0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61,
+	0x74, 0x68, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x73, 0x65, 0x67, 0x6d, 0x65,
+	0x6e, 0x74, 0x12, 0x3c, 0x0a, 0x0a, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x74, 0x72, 0x63,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63,
 	0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e,
-	0x50, 0x65, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x70, 0x65, 0x65, 0x72, 0x45,
-	0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x74, 0x75, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x0d, 0x52, 0x03, 0x6d, 0x74, 0x75, 0x12, 0x4d, 0x0a, 0x0a, 0x65, 0x78, 0x74, 0x65,
-	0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61,
-	0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e,
-	0x74, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x0a, 0x65, 0x78, 0x74,
-	0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x6a, 0x0a, 0x08, 0x48, 0x6f, 0x70, 0x45, 0x6e,
-	0x74, 0x72, 0x79, 0x12, 0x3d, 0x0a, 0x09, 0x68, 0x6f, 0x70, 0x5f, 0x66, 0x69, 0x65, 0x6c, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63,
-	0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e,
-	0x48, 0x6f, 0x70, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x08, 0x68, 0x6f, 0x70, 0x46, 0x69, 0x65,
-	0x6c, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x73, 0x73, 0x5f, 0x6d, 0x74,
-	0x75, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x73, 0x73,
-	0x4d, 0x74, 0x75, 0x22, 0xac, 0x01, 0x0a, 0x09, 0x50, 0x65, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72,
-	0x79, 0x12, 0x1e, 0x0a, 0x0b, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x69, 0x73, 0x64, 0x5f, 0x61, 0x73,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x70, 0x65, 0x65, 0x72, 0x49, 0x73, 0x64, 0x41,
-	0x73, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66,
-	0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x70, 0x65, 0x65, 0x72, 0x49,
-	0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x65, 0x65, 0x72,
-	0x5f, 0x6d, 0x74, 0x75, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x70, 0x65, 0x65, 0x72,
-	0x4d, 0x74, 0x75, 0x12, 0x3d, 0x0a, 0x09, 0x68, 0x6f, 0x70, 0x5f, 0x66, 0x69, 0x65, 0x6c, 0x64,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63,
-	0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e,
-	0x48, 0x6f, 0x70, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x08, 0x68, 0x6f, 0x70, 0x46, 0x69, 0x65,
-	0x6c, 0x64, 0x22, 0x69, 0x0a, 0x08, 0x48, 0x6f, 0x70, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x18,
-	0x0a, 0x07, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x07, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x67, 0x72, 0x65,
-	0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x65, 0x67, 0x72, 0x65, 0x73, 0x73,
-	0x12, 0x19, 0x0a, 0x08, 0x65, 0x78, 0x70, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x07, 0x65, 0x78, 0x70, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6d,
-	0x61, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6d, 0x61, 0x63, 0x2a, 0x6e, 0x0a,
-	0x0b, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x18,
-	0x53, 0x45, 0x47, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53,
-	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x45,
-	0x47, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x50, 0x10, 0x01, 0x12,
-	0x15, 0x0a, 0x11, 0x53, 0x45, 0x47, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
-	0x44, 0x4f, 0x57, 0x4e, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x45, 0x47, 0x4d, 0x45, 0x4e,
-	0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x43, 0x4f, 0x52, 0x45, 0x10, 0x03, 0x32, 0x77, 0x0a,
-	0x14, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x5f, 0x0a, 0x08, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74,
-	0x73, 0x12, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f,
-	0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x67, 0x6d, 0x65,
-	0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65,
-	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0xa2, 0x01, 0x0a, 0x1a, 0x53, 0x65, 0x67, 0x6d, 0x65,
-	0x6e, 0x74, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x83, 0x01, 0x0a, 0x14, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e,
-	0x74, 0x73, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x33,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70,
-	0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73,
-	0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x34, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x6f, 0x6e, 0x74,
-	0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x67,
-	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0x73, 0x0a, 0x16, 0x53,
-	0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x43, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x59, 0x0a, 0x06, 0x42, 0x65, 0x61, 0x63, 0x6f, 0x6e, 0x12,
-	0x25, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f,
-	0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x65, 0x61, 0x63, 0x6f, 0x6e, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63,
-	0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e,
-	0x42, 0x65, 0x61, 0x63, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
-	0x42, 0x38, 0x5a, 0x36, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73,
-	0x63, 0x69, 0x6f, 0x6e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x63, 0x69, 0x6f, 0x6e, 0x2f,
-	0x67, 0x6f, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e,
-	0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x54, 0x52, 0x43, 0x49, 0x44, 0x52, 0x09, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x74, 0x54, 0x72, 0x63,
+	0x22, 0x45, 0x0a, 0x05, 0x54, 0x52, 0x43, 0x49, 0x44, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x73, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x69, 0x73, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x62,
+	0x61, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x62, 0x61, 0x73, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x06, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x22, 0x10, 0x0a, 0x0e, 0x42, 0x65, 0x61, 0x63, 0x6f,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x70, 0x0a, 0x0b, 0x50, 0x61, 0x74,
+	0x68, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x65, 0x67, 0x6d,
+	0x65, 0x6e, 0x74, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b,
+	0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x3e, 0x0a, 0x0a, 0x61,
+	0x73, 0x5f, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f,
+	0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x53, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x09, 0x61, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0x51, 0x0a, 0x12, 0x53,
+	0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12,
+	0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x09, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x94,
+	0x01, 0x0a, 0x07, 0x41, 0x53, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x36, 0x0a, 0x06, 0x73, 0x69,
+	0x67, 0x6e, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67,
+	0x6e, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x06, 0x73, 0x69, 0x67, 0x6e,
+	0x65, 0x64, 0x12, 0x51, 0x0a, 0x08, 0x75, 0x6e, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x35, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x6f, 0x6e,
+	0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61,
+	0x74, 0x68, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x55, 0x6e, 0x73, 0x69, 0x67, 0x6e, 0x65,
+	0x64, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x08, 0x75, 0x6e, 0x73,
+	0x69, 0x67, 0x6e, 0x65, 0x64, 0x22, 0xb0, 0x02, 0x0a, 0x11, 0x41, 0x53, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x42, 0x6f, 0x64, 0x79, 0x12, 0x15, 0x0a, 0x06, 0x69,
+	0x73, 0x64, 0x5f, 0x61, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x69, 0x73, 0x64,
+	0x41, 0x73, 0x12, 0x1e, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x69, 0x73, 0x64, 0x5f, 0x61,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x6e, 0x65, 0x78, 0x74, 0x49, 0x73, 0x64,
+	0x41, 0x73, 0x12, 0x3d, 0x0a, 0x09, 0x68, 0x6f, 0x70, 0x5f, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x6f,
+	0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x48,
+	0x6f, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x68, 0x6f, 0x70, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x44, 0x0a, 0x0c, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x65, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x70, 0x65, 0x65, 0x72,
+	0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x74, 0x75, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6d, 0x74, 0x75, 0x12, 0x4d, 0x0a, 0x0a, 0x65, 0x78, 0x74,
+	0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2d, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c,
+	0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x53, 0x65, 0x67, 0x6d, 0x65,
+	0x6e, 0x74, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x0a, 0x65, 0x78,
+	0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x6a, 0x0a, 0x08, 0x48, 0x6f, 0x70, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x3d, 0x0a, 0x09, 0x68, 0x6f, 0x70, 0x5f, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x48, 0x6f, 0x70, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x08, 0x68, 0x6f, 0x70, 0x46, 0x69,
+	0x65, 0x6c, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x73, 0x73, 0x5f, 0x6d,
+	0x74, 0x75, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x73,
+	0x73, 0x4d, 0x74, 0x75, 0x22, 0xac, 0x01, 0x0a, 0x09, 0x50, 0x65, 0x65, 0x72, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x1e, 0x0a, 0x0b, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x69, 0x73, 0x64, 0x5f, 0x61,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x70, 0x65, 0x65, 0x72, 0x49, 0x73, 0x64,
+	0x41, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x66, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x70, 0x65, 0x65, 0x72,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x65, 0x65,
+	0x72, 0x5f, 0x6d, 0x74, 0x75, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x70, 0x65, 0x65,
+	0x72, 0x4d, 0x74, 0x75, 0x12, 0x3d, 0x0a, 0x09, 0x68, 0x6f, 0x70, 0x5f, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x48, 0x6f, 0x70, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x08, 0x68, 0x6f, 0x70, 0x46, 0x69,
+	0x65, 0x6c, 0x64, 0x22, 0x69, 0x0a, 0x08, 0x48, 0x6f, 0x70, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12,
+	0x18, 0x0a, 0x07, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x07, 0x69, 0x6e, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x67, 0x72,
+	0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x65, 0x67, 0x72, 0x65, 0x73,
+	0x73, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x78, 0x70, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x07, 0x65, 0x78, 0x70, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03,
+	0x6d, 0x61, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6d, 0x61, 0x63, 0x2a, 0x6e,
+	0x0a, 0x0b, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a,
+	0x18, 0x53, 0x45, 0x47, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e,
+	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x53,
+	0x45, 0x47, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x50, 0x10, 0x01,
+	0x12, 0x15, 0x0a, 0x11, 0x53, 0x45, 0x47, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45,
+	0x5f, 0x44, 0x4f, 0x57, 0x4e, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x45, 0x47, 0x4d, 0x45,
+	0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x43, 0x4f, 0x52, 0x45, 0x10, 0x03, 0x32, 0x77,
+	0x0a, 0x14, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x5f, 0x0a, 0x08, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e,
+	0x74, 0x73, 0x12, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x72,
+	0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x67, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0xa2, 0x01, 0x0a, 0x1a, 0x53, 0x65, 0x67, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x83, 0x01, 0x0a, 0x14, 0x53, 0x65, 0x67, 0x6d, 0x65,
+	0x6e, 0x74, 0x73, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x33, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f,
+	0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74,
+	0x73, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x34, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x6f, 0x6e,
+	0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65,
+	0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0x73, 0x0a, 0x16,
+	0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x43, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x59, 0x0a, 0x06, 0x42, 0x65, 0x61, 0x63, 0x6f, 0x6e,
+	0x12, 0x25, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x65, 0x61, 0x63, 0x6f, 0x6e,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x42, 0x65, 0x61, 0x63, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x42, 0x38, 0x5a, 0x36, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x73, 0x63, 0x69, 0x6f, 0x6e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x63, 0x69, 0x6f, 0x6e,
+	0x2f, 0x67, 0x6f, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f,
+	0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x70, 0x6c, 0x61, 0x6e, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1090,7 +1180,7 @@ func file_proto_control_plane_v1_seg_proto_rawDescGZIP() []byte {
 }
 
 var file_proto_control_plane_v1_seg_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_control_plane_v1_seg_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_proto_control_plane_v1_seg_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_proto_control_plane_v1_seg_proto_goTypes = []interface{}{
 	(SegmentType)(0),                             // 0: proto.control_plane.v1.SegmentType
 	(*SegmentsRequest)(nil),                      // 1: proto.control_plane.v1.SegmentsRequest
@@ -1098,49 +1188,51 @@ var file_proto_control_plane_v1_seg_proto_goTypes = []interface{}{
 	(*SegmentsRegistrationRequest)(nil),          // 3: proto.control_plane.v1.SegmentsRegistrationRequest
 	(*SegmentsRegistrationResponse)(nil),         // 4: proto.control_plane.v1.SegmentsRegistrationResponse
 	(*BeaconRequest)(nil),                        // 5: proto.control_plane.v1.BeaconRequest
-	(*BeaconResponse)(nil),                       // 6: proto.control_plane.v1.BeaconResponse
-	(*PathSegment)(nil),                          // 7: proto.control_plane.v1.PathSegment
-	(*SegmentInformation)(nil),                   // 8: proto.control_plane.v1.SegmentInformation
-	(*ASEntry)(nil),                              // 9: proto.control_plane.v1.ASEntry
-	(*ASEntrySignedBody)(nil),                    // 10: proto.control_plane.v1.ASEntrySignedBody
-	(*HopEntry)(nil),                             // 11: proto.control_plane.v1.HopEntry
-	(*PeerEntry)(nil),                            // 12: proto.control_plane.v1.PeerEntry
-	(*HopField)(nil),                             // 13: proto.control_plane.v1.HopField
-	(*SegmentsResponse_Segments)(nil),            // 14: proto.control_plane.v1.SegmentsResponse.Segments
-	nil,                                          // 15: proto.control_plane.v1.SegmentsResponse.SegmentsEntry
-	(*SegmentsRegistrationRequest_Segments)(nil), // 16: proto.control_plane.v1.SegmentsRegistrationRequest.Segments
-	nil,                                   // 17: proto.control_plane.v1.SegmentsRegistrationRequest.SegmentsEntry
-	(*crypto.SignedMessage)(nil),          // 18: proto.crypto.v1.SignedMessage
-	(*PathSegmentUnsignedExtensions)(nil), // 19: proto.control_plane.v1.PathSegmentUnsignedExtensions
-	(*PathSegmentExtensions)(nil),         // 20: proto.control_plane.v1.PathSegmentExtensions
+	(*TRCID)(nil),                                // 6: proto.control_plane.v1.TRCID
+	(*BeaconResponse)(nil),                       // 7: proto.control_plane.v1.BeaconResponse
+	(*PathSegment)(nil),                          // 8: proto.control_plane.v1.PathSegment
+	(*SegmentInformation)(nil),                   // 9: proto.control_plane.v1.SegmentInformation
+	(*ASEntry)(nil),                              // 10: proto.control_plane.v1.ASEntry
+	(*ASEntrySignedBody)(nil),                    // 11: proto.control_plane.v1.ASEntrySignedBody
+	(*HopEntry)(nil),                             // 12: proto.control_plane.v1.HopEntry
+	(*PeerEntry)(nil),                            // 13: proto.control_plane.v1.PeerEntry
+	(*HopField)(nil),                             // 14: proto.control_plane.v1.HopField
+	(*SegmentsResponse_Segments)(nil),            // 15: proto.control_plane.v1.SegmentsResponse.Segments
+	nil,                                          // 16: proto.control_plane.v1.SegmentsResponse.SegmentsEntry
+	(*SegmentsRegistrationRequest_Segments)(nil), // 17: proto.control_plane.v1.SegmentsRegistrationRequest.Segments
+	nil,                                   // 18: proto.control_plane.v1.SegmentsRegistrationRequest.SegmentsEntry
+	(*crypto.SignedMessage)(nil),          // 19: proto.crypto.v1.SignedMessage
+	(*PathSegmentUnsignedExtensions)(nil), // 20: proto.control_plane.v1.PathSegmentUnsignedExtensions
+	(*PathSegmentExtensions)(nil),         // 21: proto.control_plane.v1.PathSegmentExtensions
 }
 var file_proto_control_plane_v1_seg_proto_depIdxs = []int32{
-	15, // 0: proto.control_plane.v1.SegmentsResponse.segments:type_name -> proto.control_plane.v1.SegmentsResponse.SegmentsEntry
-	17, // 1: proto.control_plane.v1.SegmentsRegistrationRequest.segments:type_name -> proto.control_plane.v1.SegmentsRegistrationRequest.SegmentsEntry
-	7,  // 2: proto.control_plane.v1.BeaconRequest.segment:type_name -> proto.control_plane.v1.PathSegment
-	9,  // 3: proto.control_plane.v1.PathSegment.as_entries:type_name -> proto.control_plane.v1.ASEntry
-	18, // 4: proto.control_plane.v1.ASEntry.signed:type_name -> proto.crypto.v1.SignedMessage
-	19, // 5: proto.control_plane.v1.ASEntry.unsigned:type_name -> proto.control_plane.v1.PathSegmentUnsignedExtensions
-	11, // 6: proto.control_plane.v1.ASEntrySignedBody.hop_entry:type_name -> proto.control_plane.v1.HopEntry
-	12, // 7: proto.control_plane.v1.ASEntrySignedBody.peer_entries:type_name -> proto.control_plane.v1.PeerEntry
-	20, // 8: proto.control_plane.v1.ASEntrySignedBody.extensions:type_name -> proto.control_plane.v1.PathSegmentExtensions
-	13, // 9: proto.control_plane.v1.HopEntry.hop_field:type_name -> proto.control_plane.v1.HopField
-	13, // 10: proto.control_plane.v1.PeerEntry.hop_field:type_name -> proto.control_plane.v1.HopField
-	7,  // 11: proto.control_plane.v1.SegmentsResponse.Segments.segments:type_name -> proto.control_plane.v1.PathSegment
-	14, // 12: proto.control_plane.v1.SegmentsResponse.SegmentsEntry.value:type_name -> proto.control_plane.v1.SegmentsResponse.Segments
-	7,  // 13: proto.control_plane.v1.SegmentsRegistrationRequest.Segments.segments:type_name -> proto.control_plane.v1.PathSegment
-	16, // 14: proto.control_plane.v1.SegmentsRegistrationRequest.SegmentsEntry.value:type_name -> proto.control_plane.v1.SegmentsRegistrationRequest.Segments
-	1,  // 15: proto.control_plane.v1.SegmentLookupService.Segments:input_type -> proto.control_plane.v1.SegmentsRequest
-	3,  // 16: proto.control_plane.v1.SegmentRegistrationService.SegmentsRegistration:input_type -> proto.control_plane.v1.SegmentsRegistrationRequest
-	5,  // 17: proto.control_plane.v1.SegmentCreationService.Beacon:input_type -> proto.control_plane.v1.BeaconRequest
-	2,  // 18: proto.control_plane.v1.SegmentLookupService.Segments:output_type -> proto.control_plane.v1.SegmentsResponse
-	4,  // 19: proto.control_plane.v1.SegmentRegistrationService.SegmentsRegistration:output_type -> proto.control_plane.v1.SegmentsRegistrationResponse
-	6,  // 20: proto.control_plane.v1.SegmentCreationService.Beacon:output_type -> proto.control_plane.v1.BeaconResponse
-	18, // [18:21] is the sub-list for method output_type
-	15, // [15:18] is the sub-list for method input_type
-	15, // [15:15] is the sub-list for extension type_name
-	15, // [15:15] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+	16, // 0: proto.control_plane.v1.SegmentsResponse.segments:type_name -> proto.control_plane.v1.SegmentsResponse.SegmentsEntry
+	18, // 1: proto.control_plane.v1.SegmentsRegistrationRequest.segments:type_name -> proto.control_plane.v1.SegmentsRegistrationRequest.SegmentsEntry
+	8,  // 2: proto.control_plane.v1.BeaconRequest.segment:type_name -> proto.control_plane.v1.PathSegment
+	6,  // 3: proto.control_plane.v1.BeaconRequest.latest_trc:type_name -> proto.control_plane.v1.TRCID
+	10, // 4: proto.control_plane.v1.PathSegment.as_entries:type_name -> proto.control_plane.v1.ASEntry
+	19, // 5: proto.control_plane.v1.ASEntry.signed:type_name -> proto.crypto.v1.SignedMessage
+	20, // 6: proto.control_plane.v1.ASEntry.unsigned:type_name -> proto.control_plane.v1.PathSegmentUnsignedExtensions
+	12, // 7: proto.control_plane.v1.ASEntrySignedBody.hop_entry:type_name -> proto.control_plane.v1.HopEntry
+	13, // 8: proto.control_plane.v1.ASEntrySignedBody.peer_entries:type_name -> proto.control_plane.v1.PeerEntry
+	21, // 9: proto.control_plane.v1.ASEntrySignedBody.extensions:type_name -> proto.control_plane.v1.PathSegmentExtensions
+	14, // 10: proto.control_plane.v1.HopEntry.hop_field:type_name -> proto.control_plane.v1.HopField
+	14, // 11: proto.control_plane.v1.PeerEntry.hop_field:type_name -> proto.control_plane.v1.HopField
+	8,  // 12: proto.control_plane.v1.SegmentsResponse.Segments.segments:type_name -> proto.control_plane.v1.PathSegment
+	15, // 13: proto.control_plane.v1.SegmentsResponse.SegmentsEntry.value:type_name -> proto.control_plane.v1.SegmentsResponse.Segments
+	8,  // 14: proto.control_plane.v1.SegmentsRegistrationRequest.Segments.segments:type_name -> proto.control_plane.v1.PathSegment
+	17, // 15: proto.control_plane.v1.SegmentsRegistrationRequest.SegmentsEntry.value:type_name -> proto.control_plane.v1.SegmentsRegistrationRequest.Segments
+	1,  // 16: proto.control_plane.v1.SegmentLookupService.Segments:input_type -> proto.control_plane.v1.SegmentsRequest
+	3,  // 17: proto.control_plane.v1.SegmentRegistrationService.SegmentsRegistration:input_type -> proto.control_plane.v1.SegmentsRegistrationRequest
+	5,  // 18: proto.control_plane.v1.SegmentCreationService.Beacon:input_type -> proto.control_plane.v1.BeaconRequest
+	2,  // 19: proto.control_plane.v1.SegmentLookupService.Segments:output_type -> proto.control_plane.v1.SegmentsResponse
+	4,  // 20: proto.control_plane.v1.SegmentRegistrationService.SegmentsRegistration:output_type -> proto.control_plane.v1.SegmentsRegistrationResponse
+	7,  // 21: proto.control_plane.v1.SegmentCreationService.Beacon:output_type -> proto.control_plane.v1.BeaconResponse
+	19, // [19:22] is the sub-list for method output_type
+	16, // [16:19] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_proto_control_plane_v1_seg_proto_init() }
@@ -1211,7 +1303,7 @@ func file_proto_control_plane_v1_seg_proto_init() {
 			}
 		}
 		file_proto_control_plane_v1_seg_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BeaconResponse); i {
+			switch v := v.(*TRCID); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1223,7 +1315,7 @@ func file_proto_control_plane_v1_seg_proto_init() {
 			}
 		}
 		file_proto_control_plane_v1_seg_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PathSegment); i {
+			switch v := v.(*BeaconResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1235,7 +1327,7 @@ func file_proto_control_plane_v1_seg_proto_init() {
 			}
 		}
 		file_proto_control_plane_v1_seg_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SegmentInformation); i {
+			switch v := v.(*PathSegment); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1247,7 +1339,7 @@ func file_proto_control_plane_v1_seg_proto_init() {
 			}
 		}
 		file_proto_control_plane_v1_seg_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ASEntry); i {
+			switch v := v.(*SegmentInformation); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1259,7 +1351,7 @@ func file_proto_control_plane_v1_seg_proto_init() {
 			}
 		}
 		file_proto_control_plane_v1_seg_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ASEntrySignedBody); i {
+			switch v := v.(*ASEntry); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1271,7 +1363,7 @@ func file_proto_control_plane_v1_seg_proto_init() {
 			}
 		}
 		file_proto_control_plane_v1_seg_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HopEntry); i {
+			switch v := v.(*ASEntrySignedBody); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1283,7 +1375,7 @@ func file_proto_control_plane_v1_seg_proto_init() {
 			}
 		}
 		file_proto_control_plane_v1_seg_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PeerEntry); i {
+			switch v := v.(*HopEntry); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1295,7 +1387,7 @@ func file_proto_control_plane_v1_seg_proto_init() {
 			}
 		}
 		file_proto_control_plane_v1_seg_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HopField); i {
+			switch v := v.(*PeerEntry); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1307,6 +1399,18 @@ func file_proto_control_plane_v1_seg_proto_init() {
 			}
 		}
 		file_proto_control_plane_v1_seg_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HopField); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_control_plane_v1_seg_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SegmentsResponse_Segments); i {
 			case 0:
 				return &v.state
@@ -1318,7 +1422,7 @@ func file_proto_control_plane_v1_seg_proto_init() {
 				return nil
 			}
 		}
-		file_proto_control_plane_v1_seg_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+		file_proto_control_plane_v1_seg_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SegmentsRegistrationRequest_Segments); i {
 			case 0:
 				return &v.state
@@ -1337,7 +1441,7 @@ func file_proto_control_plane_v1_seg_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_control_plane_v1_seg_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   17,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   3,
 		},
@@ -64,6 +64,21 @@ func (mr *MockDaemonServiceServerMockRecorder) DRKeyLvl2(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DRKeyLvl2", reflect.TypeOf((*MockDaemonServiceServer)(nil).DRKeyLvl2), arg0, arg1)
 }
 
+// ExplainPaths mocks base method
+func (m *MockDaemonServiceServer) ExplainPaths(arg0 context.Context, arg1 *daemon.ExplainPathsRequest) (*daemon.ExplainPathsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExplainPaths", arg0, arg1)
+	ret0, _ := ret[0].(*daemon.ExplainPathsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExplainPaths indicates an expected call of ExplainPaths
+func (mr *MockDaemonServiceServerMockRecorder) ExplainPaths(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExplainPaths", reflect.TypeOf((*MockDaemonServiceServer)(nil).ExplainPaths), arg0, arg1)
+}
+
 // Interfaces mocks base method
 func (m *MockDaemonServiceServer) Interfaces(arg0 context.Context, arg1 *daemon.InterfacesRequest) (*daemon.InterfacesResponse, error) {
 	m.ctrl.T.Helper()
@@ -67,6 +67,107 @@ func (*PrefixesRequest) Descriptor() ([]byte, []int) {
 	return file_proto_gateway_v1_prefix_proto_rawDescGZIP(), []int{0}
 }
 
+type PrefixesStreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *PrefixesStreamRequest) Reset() {
+	*x = PrefixesStreamRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_gateway_v1_prefix_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PrefixesStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrefixesStreamRequest) ProtoMessage() {}
+
+func (x *PrefixesStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gateway_v1_prefix_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrefixesStreamRequest.ProtoReflect.Descriptor instead.
+func (*PrefixesStreamRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gateway_v1_prefix_proto_rawDescGZIP(), []int{1}
+}
+
+type PrefixesUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Full      bool      `protobuf:"varint,1,opt,name=full,proto3" json:"full,omitempty"`
+	Announced []*Prefix `protobuf:"bytes,2,rep,name=announced,proto3" json:"announced,omitempty"`
+	Withdrawn []*Prefix `protobuf:"bytes,3,rep,name=withdrawn,proto3" json:"withdrawn,omitempty"`
+}
+
+func (x *PrefixesUpdate) Reset() {
+	*x = PrefixesUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_gateway_v1_prefix_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PrefixesUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrefixesUpdate) ProtoMessage() {}
+
+func (x *PrefixesUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gateway_v1_prefix_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrefixesUpdate.ProtoReflect.Descriptor instead.
+func (*PrefixesUpdate) Descriptor() ([]byte, []int) {
+	return file_proto_gateway_v1_prefix_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PrefixesUpdate) GetFull() bool {
+	if x != nil {
+		return x.Full
+	}
+	return false
+}
+
+func (x *PrefixesUpdate) GetAnnounced() []*Prefix {
+	if x != nil {
+		return x.Announced
+	}
+	return nil
+}
+
+func (x *PrefixesUpdate) GetWithdrawn() []*Prefix {
+	if x != nil {
+		return x.Withdrawn
+	}
+	return nil
+}
+
 type PrefixesResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -78,7 +179,7 @@ type PrefixesResponse struct {
 func (x *PrefixesResponse) Reset() {
 	*x = PrefixesResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_gateway_v1_prefix_proto_msgTypes[1]
+		mi := &file_proto_gateway_v1_prefix_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -91,7 +192,7 @@ func (x *PrefixesResponse) String() string {
 func (*PrefixesResponse) ProtoMessage() {}
 
 func (x *PrefixesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gateway_v1_prefix_proto_msgTypes[1]
+	mi := &file_proto_gateway_v1_prefix_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -104,7 +205,7 @@ func (x *PrefixesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PrefixesResponse.ProtoReflect.Descriptor instead.
 func (*PrefixesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_gateway_v1_prefix_proto_rawDescGZIP(), []int{1}
+	return file_proto_gateway_v1_prefix_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *PrefixesResponse) GetPrefixes() []*Prefix {
@@ -119,14 +220,15 @@ type Prefix struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Prefix []byte `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
-	Mask   uint32 `protobuf:"varint,2,opt,name=mask,proto3" json:"mask,omitempty"`
+	Prefix     []byte            `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Mask       uint32            `protobuf:"varint,2,opt,name=mask,proto3" json:"mask,omitempty"`
+	Attributes *PrefixAttributes `protobuf:"bytes,3,opt,name=attributes,proto3" json:"attributes,omitempty"`
 }
 
 func (x *Prefix) Reset() {
 	*x = Prefix{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_gateway_v1_prefix_proto_msgTypes[2]
+		mi := &file_proto_gateway_v1_prefix_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -139,7 +241,7 @@ func (x *Prefix) String() string {
 func (*Prefix) ProtoMessage() {}
 
 func (x *Prefix) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_gateway_v1_prefix_proto_msgTypes[2]
+	mi := &file_proto_gateway_v1_prefix_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -152,7 +254,7 @@ func (x *Prefix) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Prefix.ProtoReflect.Descriptor instead.
 func (*Prefix) Descriptor() ([]byte, []int) {
-	return file_proto_gateway_v1_prefix_proto_rawDescGZIP(), []int{2}
+	return file_proto_gateway_v1_prefix_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *Prefix) GetPrefix() []byte {
@@ -169,6 +271,68 @@ func (x *Prefix) GetMask() uint32 {
 	return 0
 }
 
+func (x *Prefix) GetAttributes() *PrefixAttributes {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+type PrefixAttributes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Metric uint32   `protobuf:"varint,1,opt,name=metric,proto3" json:"metric,omitempty"`
+	Tags   []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+}
+
+func (x *PrefixAttributes) Reset() {
+	*x = PrefixAttributes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_gateway_v1_prefix_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PrefixAttributes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrefixAttributes) ProtoMessage() {}
+
+func (x *PrefixAttributes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gateway_v1_prefix_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrefixAttributes.ProtoReflect.Descriptor instead.
+func (*PrefixAttributes) Descriptor() ([]byte, []int) {
+	return file_proto_gateway_v1_prefix_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PrefixAttributes) GetMetric() uint32 {
+	if x != nil {
+		return x.Metric
+	}
+	return 0
+}
+
+func (x *PrefixAttributes) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
 var File_proto_gateway_v1_prefix_proto protoreflect.FileDescriptor
 
 var file_proto_gateway_v1_prefix_proto_rawDesc = []byte{
@@ -176,25 +340,50 @@ var file_proto_gateway_v1_prefix_proto_rawDesc = []byte{
 	0x76, 0x31, 0x2f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
 	0x10, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x76,
 	0x31, 0x22, 0x11, 0x0a, 0x0f, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65, 0x73, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x22, 0x48, 0x0a, 0x10, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65, 0x73,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0x17, 0x0a, 0x15, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65, 0x73,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x94, 0x01,
+	0x0a, 0x0e, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65, 0x73, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x66, 0x75, 0x6c, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04,
+	0x66, 0x75, 0x6c, 0x6c, 0x12, 0x36, 0x0a, 0x09, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65,
+	0x64, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x65, 0x66, 0x69,
+	0x78, 0x52, 0x09, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x64, 0x12, 0x36, 0x0a, 0x09,
+	0x77, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x6e, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x52, 0x09, 0x77, 0x69, 0x74, 0x68, 0x64,
+	0x72, 0x61, 0x77, 0x6e, 0x22, 0x48, 0x0a, 0x10, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65, 0x73,
 	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x34, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x66,
 	0x69, 0x78, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72,
-	0x65, 0x66, 0x69, 0x78, 0x52, 0x08, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65, 0x73, 0x22, 0x34,
+	0x65, 0x66, 0x69, 0x78, 0x52, 0x08, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65, 0x73, 0x22, 0x78,
 	0x0a, 0x06, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66,
 	0x69, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78,
 	0x12, 0x12, 0x0a, 0x04, 0x6d, 0x61, 0x73, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04,
-	0x6d, 0x61, 0x73, 0x6b, 0x32, 0x68, 0x0a, 0x11, 0x49, 0x50, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78,
-	0x65, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x53, 0x0a, 0x08, 0x50, 0x72, 0x65,
-	0x66, 0x69, 0x78, 0x65, 0x73, 0x12, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x67, 0x61,
-	0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65,
-	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x6d, 0x61, 0x73, 0x6b, 0x12, 0x42, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 	0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x65, 0x66,
-	0x69, 0x78, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x32,
-	0x5a, 0x30, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x63, 0x69,
-	0x6f, 0x6e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x63, 0x69, 0x6f, 0x6e, 0x2f, 0x67, 0x6f,
-	0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x61, 0x74, 0x65, 0x77,
-	0x61, 0x79, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x69, 0x78, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x52, 0x0a, 0x61, 0x74,
+	0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x22, 0x3e, 0x0a, 0x10, 0x50, 0x72, 0x65, 0x66,
+	0x69, 0x78, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x6d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x32, 0xc9, 0x01, 0x0a, 0x11, 0x49, 0x50, 0x50,
+	0x72, 0x65, 0x66, 0x69, 0x78, 0x65, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x53,
+	0x0a, 0x08, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65, 0x73, 0x12, 0x21, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72,
+	0x65, 0x66, 0x69, 0x78, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x5f, 0x0a, 0x0e, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65, 0x73, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x67, 0x61,
+	0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65,
+	0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x65, 0x73, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x22, 0x00, 0x30, 0x01, 0x42, 0x32, 0x5a, 0x30, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x73, 0x63, 0x69, 0x6f, 0x6e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x63,
+	0x69, 0x6f, 0x6e, 0x2f, 0x67, 0x6f, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2f, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -209,21 +398,29 @@ func file_proto_gateway_v1_prefix_proto_rawDescGZIP() []byte {
 	return file_proto_gateway_v1_prefix_proto_rawDescData
 }
 
-var file_proto_gateway_v1_prefix_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_proto_gateway_v1_prefix_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_proto_gateway_v1_prefix_proto_goTypes = []interface{}{
-	(*PrefixesRequest)(nil),  // 0: proto.gateway.v1.PrefixesRequest
-	(*PrefixesResponse)(nil), // 1: proto.gateway.v1.PrefixesResponse
-	(*Prefix)(nil),           // 2: proto.gateway.v1.Prefix
+	(*PrefixesRequest)(nil),       // 0: proto.gateway.v1.PrefixesRequest
+	(*PrefixesStreamRequest)(nil), // 1: proto.gateway.v1.PrefixesStreamRequest
+	(*PrefixesUpdate)(nil),        // 2: proto.gateway.v1.PrefixesUpdate
+	(*PrefixesResponse)(nil),      // 3: proto.gateway.v1.PrefixesResponse
+	(*Prefix)(nil),                // 4: proto.gateway.v1.Prefix
+	(*PrefixAttributes)(nil),      // 5: proto.gateway.v1.PrefixAttributes
 }
 var file_proto_gateway_v1_prefix_proto_depIdxs = []int32{
-	2, // 0: proto.gateway.v1.PrefixesResponse.prefixes:type_name -> proto.gateway.v1.Prefix
-	0, // 1: proto.gateway.v1.IPPrefixesService.Prefixes:input_type -> proto.gateway.v1.PrefixesRequest
-	1, // 2: proto.gateway.v1.IPPrefixesService.Prefixes:output_type -> proto.gateway.v1.PrefixesResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	4, // 0: proto.gateway.v1.PrefixesUpdate.announced:type_name -> proto.gateway.v1.Prefix
+	4, // 1: proto.gateway.v1.PrefixesUpdate.withdrawn:type_name -> proto.gateway.v1.Prefix
+	4, // 2: proto.gateway.v1.PrefixesResponse.prefixes:type_name -> proto.gateway.v1.Prefix
+	5, // 3: proto.gateway.v1.Prefix.attributes:type_name -> proto.gateway.v1.PrefixAttributes
+	0, // 4: proto.gateway.v1.IPPrefixesService.Prefixes:input_type -> proto.gateway.v1.PrefixesRequest
+	1, // 5: proto.gateway.v1.IPPrefixesService.PrefixesStream:input_type -> proto.gateway.v1.PrefixesStreamRequest
+	3, // 6: proto.gateway.v1.IPPrefixesService.Prefixes:output_type -> proto.gateway.v1.PrefixesResponse
+	2, // 7: proto.gateway.v1.IPPrefixesService.PrefixesStream:output_type -> proto.gateway.v1.PrefixesUpdate
+	6, // [6:8] is the sub-list for method output_type
+	4, // [4:6] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_proto_gateway_v1_prefix_proto_init() }
@@ -245,7 +442,7 @@ func file_proto_gateway_v1_prefix_proto_init() {
 			}
 		}
 		file_proto_gateway_v1_prefix_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PrefixesResponse); i {
+			switch v := v.(*PrefixesStreamRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -257,6 +454,30 @@ func file_proto_gateway_v1_prefix_proto_init() {
 			}
 		}
 		file_proto_gateway_v1_prefix_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrefixesUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_gateway_v1_prefix_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrefixesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_gateway_v1_prefix_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Prefix); i {
 			case 0:
 				return &v.state
@@ -268,6 +489,18 @@ func file_proto_gateway_v1_prefix_proto_init() {
 				return nil
 			}
 		}
+		file_proto_gateway_v1_prefix_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrefixAttributes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -275,7 +508,7 @@ func file_proto_gateway_v1_prefix_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_gateway_v1_prefix_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -302,6 +535,7 @@ const _ = grpc.SupportPackageIsVersion6
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type IPPrefixesServiceClient interface {
 	Prefixes(ctx context.Context, in *PrefixesRequest, opts ...grpc.CallOption) (*PrefixesResponse, error)
+	PrefixesStream(ctx context.Context, in *PrefixesStreamRequest, opts ...grpc.CallOption) (IPPrefixesService_PrefixesStreamClient, error)
 }
 
 type iPPrefixesServiceClient struct {
@@ -321,9 +555,42 @@ func (c *iPPrefixesServiceClient) Prefixes(ctx context.Context, in *PrefixesRequ
 	return out, nil
 }
 
+func (c *iPPrefixesServiceClient) PrefixesStream(ctx context.Context, in *PrefixesStreamRequest, opts ...grpc.CallOption) (IPPrefixesService_PrefixesStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_IPPrefixesService_serviceDesc.Streams[0], "/proto.gateway.v1.IPPrefixesService/PrefixesStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &iPPrefixesServicePrefixesStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type IPPrefixesService_PrefixesStreamClient interface {
+	Recv() (*PrefixesUpdate, error)
+	grpc.ClientStream
+}
+
+type iPPrefixesServicePrefixesStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *iPPrefixesServicePrefixesStreamClient) Recv() (*PrefixesUpdate, error) {
+	m := new(PrefixesUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // IPPrefixesServiceServer is the server API for IPPrefixesService service.
 type IPPrefixesServiceServer interface {
 	Prefixes(context.Context, *PrefixesRequest) (*PrefixesResponse, error)
+	PrefixesStream(*PrefixesStreamRequest, IPPrefixesService_PrefixesStreamServer) error
 }
 
 // UnimplementedIPPrefixesServiceServer can be embedded to have forward compatible implementations.
@@ -333,6 +600,9 @@ type UnimplementedIPPrefixesServiceServer struct {
 func (*UnimplementedIPPrefixesServiceServer) Prefixes(context.Context, *PrefixesRequest) (*PrefixesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Prefixes not implemented")
 }
+func (*UnimplementedIPPrefixesServiceServer) PrefixesStream(*PrefixesStreamRequest, IPPrefixesService_PrefixesStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method PrefixesStream not implemented")
+}
 
 func RegisterIPPrefixesServiceServer(s *grpc.Server, srv IPPrefixesServiceServer) {
 	s.RegisterService(&_IPPrefixesService_serviceDesc, srv)
@@ -356,6 +626,27 @@ func _IPPrefixesService_Prefixes_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IPPrefixesService_PrefixesStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PrefixesStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IPPrefixesServiceServer).PrefixesStream(m, &iPPrefixesServicePrefixesStreamServer{stream})
+}
+
+type IPPrefixesService_PrefixesStreamServer interface {
+	Send(*PrefixesUpdate) error
+	grpc.ServerStream
+}
+
+type iPPrefixesServicePrefixesStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *iPPrefixesServicePrefixesStreamServer) Send(m *PrefixesUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _IPPrefixesService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "proto.gateway.v1.IPPrefixesService",
 	HandlerType: (*IPPrefixesServiceServer)(nil),
@@ -365,6 +656,12 @@ var _IPPrefixesService_serviceDesc = grpc.ServiceDesc{
 			Handler:    _IPPrefixesService_Prefixes_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PrefixesStream",
+			Handler:       _IPPrefixesService_PrefixesStream_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "proto/gateway/v1/prefix.proto",
 }
@@ -414,6 +414,187 @@ func (x *AuthoritativeHiddenSegmentsResponse) GetSegments() map[int32]*Segments
 	return nil
 }
 
+type HiddenSegmentGroupsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupIds []uint64 `protobuf:"varint,1,rep,packed,name=group_ids,json=groupIds,proto3" json:"group_ids,omitempty"`
+}
+
+func (x *HiddenSegmentGroupsRequest) Reset() {
+	*x = HiddenSegmentGroupsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_hidden_segment_v1_hidden_segment_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HiddenSegmentGroupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HiddenSegmentGroupsRequest) ProtoMessage() {}
+
+func (x *HiddenSegmentGroupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_hidden_segment_v1_hidden_segment_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HiddenSegmentGroupsRequest.ProtoReflect.Descriptor instead.
+func (*HiddenSegmentGroupsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_hidden_segment_v1_hidden_segment_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *HiddenSegmentGroupsRequest) GetGroupIds() []uint64 {
+	if x != nil {
+		return x.GroupIds
+	}
+	return nil
+}
+
+type HiddenSegmentGroupsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Groups []*crypto.SignedMessage `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+}
+
+func (x *HiddenSegmentGroupsResponse) Reset() {
+	*x = HiddenSegmentGroupsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_hidden_segment_v1_hidden_segment_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HiddenSegmentGroupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HiddenSegmentGroupsResponse) ProtoMessage() {}
+
+func (x *HiddenSegmentGroupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_hidden_segment_v1_hidden_segment_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HiddenSegmentGroupsResponse.ProtoReflect.Descriptor instead.
+func (*HiddenSegmentGroupsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_hidden_segment_v1_hidden_segment_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *HiddenSegmentGroupsResponse) GetGroups() []*crypto.SignedMessage {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type HiddenSegmentGroup struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupId    uint64   `protobuf:"varint,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Version    uint64   `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	OwnerIsdAs uint64   `protobuf:"varint,3,opt,name=owner_isd_as,json=ownerIsdAs,proto3" json:"owner_isd_as,omitempty"`
+	Writers    []uint64 `protobuf:"varint,4,rep,packed,name=writers,proto3" json:"writers,omitempty"`
+	Readers    []uint64 `protobuf:"varint,5,rep,packed,name=readers,proto3" json:"readers,omitempty"`
+	Registries []uint64 `protobuf:"varint,6,rep,packed,name=registries,proto3" json:"registries,omitempty"`
+}
+
+func (x *HiddenSegmentGroup) Reset() {
+	*x = HiddenSegmentGroup{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_hidden_segment_v1_hidden_segment_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HiddenSegmentGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HiddenSegmentGroup) ProtoMessage() {}
+
+func (x *HiddenSegmentGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_hidden_segment_v1_hidden_segment_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HiddenSegmentGroup.ProtoReflect.Descriptor instead.
+func (*HiddenSegmentGroup) Descriptor() ([]byte, []int) {
+	return file_proto_hidden_segment_v1_hidden_segment_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *HiddenSegmentGroup) GetGroupId() uint64 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *HiddenSegmentGroup) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *HiddenSegmentGroup) GetOwnerIsdAs() uint64 {
+	if x != nil {
+		return x.OwnerIsdAs
+	}
+	return 0
+}
+
+func (x *HiddenSegmentGroup) GetWriters() []uint64 {
+	if x != nil {
+		return x.Writers
+	}
+	return nil
+}
+
+func (x *HiddenSegmentGroup) GetReaders() []uint64 {
+	if x != nil {
+		return x.Readers
+	}
+	return nil
+}
+
+func (x *HiddenSegmentGroup) GetRegistries() []uint64 {
+	if x != nil {
+		return x.Registries
+	}
+	return nil
+}
+
 var File_proto_hidden_segment_v1_hidden_segment_proto protoreflect.FileDescriptor
 
 var file_proto_hidden_segment_v1_hidden_segment_proto_rawDesc = []byte{
@@ -496,45 +677,76 @@ var file_proto_hidden_segment_v1_hidden_segment_proto_rawDesc = []byte{
 	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x2e, 0x68, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x5f, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e,
 	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x05, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x32, 0xb9, 0x01, 0x0a, 0x20, 0x48, 0x69, 0x64,
-	0x64, 0x65, 0x6e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
-	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x94, 0x01,
-	0x0a, 0x19, 0x48, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x52,
-	0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x39, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x68, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x5f, 0x73, 0x65, 0x67, 0x6d, 0x65,
-	0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x53, 0x65, 0x67, 0x6d,
-	0x65, 0x6e, 0x74, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x3a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x68,
-	0x69, 0x64, 0x64, 0x65, 0x6e, 0x5f, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31,
-	0x2e, 0x48, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65,
-	0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x00, 0x32, 0x91, 0x01, 0x0a, 0x1a, 0x48, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x53,
-	0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x12, 0x73, 0x0a, 0x0e, 0x48, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x53, 0x65, 0x67,
-	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x2e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x68, 0x69,
-	0x64, 0x64, 0x65, 0x6e, 0x5f, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x39, 0x0a, 0x1a, 0x48, 0x69, 0x64, 0x64,
+	0x65, 0x6e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f,
+	0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x04, 0x52, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70,
+	0x49, 0x64, 0x73, 0x22, 0x55, 0x0a, 0x1b, 0x48, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x53, 0x65, 0x67,
+	0x6d, 0x65, 0x6e, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x36, 0x0a, 0x06, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x63, 0x72, 0x79, 0x70, 0x74,
+	0x6f, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x52, 0x06, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x22, 0xbf, 0x01, 0x0a, 0x12, 0x48,
+	0x69, 0x64, 0x64, 0x65, 0x6e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0c, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x5f,
+	0x69, 0x73, 0x64, 0x5f, 0x61, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x6f, 0x77,
+	0x6e, 0x65, 0x72, 0x49, 0x73, 0x64, 0x41, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x77, 0x72, 0x69, 0x74,
+	0x65, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x04, 0x52, 0x07, 0x77, 0x72, 0x69, 0x74, 0x65,
+	0x72, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x05, 0x20,
+	0x03, 0x28, 0x04, 0x52, 0x07, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x1e, 0x0a, 0x0a,
+	0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x04,
+	0x52, 0x0a, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x69, 0x65, 0x73, 0x32, 0xb9, 0x01, 0x0a,
+	0x20, 0x48, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65,
+	0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x94, 0x01, 0x0a, 0x19, 0x48, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x53, 0x65, 0x67, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x39, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x68, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x5f, 0x73,
+	0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x69, 0x64, 0x64, 0x65, 0x6e,
+	0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x3a, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x68, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x5f, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x53, 0x65, 0x67, 0x6d, 0x65,
+	0x6e, 0x74, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0x91, 0x01, 0x0a, 0x1a, 0x48, 0x69, 0x64,
+	0x64, 0x65, 0x6e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x73, 0x0a, 0x0e, 0x48, 0x69, 0x64, 0x64, 0x65,
+	0x6e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x2e, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x68, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x5f, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x48, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e,
+	0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2f, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x68, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x5f, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x48, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e,
+	0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0xc6, 0x01, 0x0a,
+	0x27, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x61, 0x74, 0x69, 0x76, 0x65, 0x48, 0x69,
+	0x64, 0x64, 0x65, 0x6e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x6f, 0x6f, 0x6b, 0x75,
+	0x70, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x9a, 0x01, 0x0a, 0x1b, 0x41, 0x75, 0x74,
+	0x68, 0x6f, 0x72, 0x69, 0x74, 0x61, 0x74, 0x69, 0x76, 0x65, 0x48, 0x69, 0x64, 0x64, 0x65, 0x6e,
+	0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x3b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x68, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x5f, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x61, 0x74, 0x69, 0x76, 0x65,
 	0x48, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x68, 0x69,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x3c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x68, 0x69,
 	0x64, 0x64, 0x65, 0x6e, 0x5f, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e,
-	0x48, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0xc6, 0x01, 0x0a, 0x27, 0x41, 0x75, 0x74,
-	0x68, 0x6f, 0x72, 0x69, 0x74, 0x61, 0x74, 0x69, 0x76, 0x65, 0x48, 0x69, 0x64, 0x64, 0x65, 0x6e,
-	0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x4c, 0x6f, 0x6f, 0x6b, 0x75, 0x70, 0x53, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x12, 0x9a, 0x01, 0x0a, 0x1b, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69,
-	0x74, 0x61, 0x74, 0x69, 0x76, 0x65, 0x48, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x53, 0x65, 0x67, 0x6d,
-	0x65, 0x6e, 0x74, 0x73, 0x12, 0x3b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x68, 0x69, 0x64,
-	0x64, 0x65, 0x6e, 0x5f, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x41,
-	0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x61, 0x74, 0x69, 0x76, 0x65, 0x48, 0x69, 0x64, 0x64,
-	0x65, 0x6e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x3c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x68, 0x69, 0x64, 0x64, 0x65, 0x6e,
-	0x5f, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x75, 0x74, 0x68,
-	0x6f, 0x72, 0x69, 0x74, 0x61, 0x74, 0x69, 0x76, 0x65, 0x48, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x53,
-	0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x42, 0x39, 0x5a, 0x37, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
-	0x73, 0x63, 0x69, 0x6f, 0x6e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x63, 0x69, 0x6f, 0x6e,
-	0x2f, 0x67, 0x6f, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x68, 0x69,
-	0x64, 0x64, 0x65, 0x6e, 0x5f, 0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+	0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x61, 0x74, 0x69, 0x76, 0x65, 0x48, 0x69, 0x64,
+	0x64, 0x65, 0x6e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0xa0, 0x01, 0x0a, 0x19, 0x48, 0x69, 0x64, 0x64, 0x65, 0x6e,
+	0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x82, 0x01, 0x0a, 0x13, 0x48, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x53, 0x65,
+	0x67, 0x6d, 0x65, 0x6e, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12, 0x33, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x68, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x5f, 0x73, 0x65, 0x67, 0x6d, 0x65,
+	0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x53, 0x65, 0x67, 0x6d,
+	0x65, 0x6e, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x34, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x68, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x5f,
+	0x73, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x69, 0x64, 0x64, 0x65,
+	0x6e, 0x53, 0x65, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x39, 0x5a, 0x37, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x63, 0x69, 0x6f, 0x6e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2f, 0x73, 0x63, 0x69, 0x6f, 0x6e, 0x2f, 0x67, 0x6f, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x68, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x5f, 0x73, 0x65, 0x67, 0x6d,
+	0x65, 0x6e, 0x74, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -549,7 +761,7 @@ func file_proto_hidden_segment_v1_hidden_segment_proto_rawDescGZIP() []byte {
 	return file_proto_hidden_segment_v1_hidden_segment_proto_rawDescData
 }
 
-var file_proto_hidden_segment_v1_hidden_segment_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_proto_hidden_segment_v1_hidden_segment_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_proto_hidden_segment_v1_hidden_segment_proto_goTypes = []interface{}{
 	(*Segments)(nil),                             // 0: proto.hidden_segment.v1.Segments
 	(*HiddenSegmentRegistrationRequest)(nil),     // 1: proto.hidden_segment.v1.HiddenSegmentRegistrationRequest
@@ -559,33 +771,39 @@ var file_proto_hidden_segment_v1_hidden_segment_proto_goTypes = []interface{}{
 	(*HiddenSegmentsResponse)(nil),               // 5: proto.hidden_segment.v1.HiddenSegmentsResponse
 	(*AuthoritativeHiddenSegmentsRequest)(nil),   // 6: proto.hidden_segment.v1.AuthoritativeHiddenSegmentsRequest
 	(*AuthoritativeHiddenSegmentsResponse)(nil),  // 7: proto.hidden_segment.v1.AuthoritativeHiddenSegmentsResponse
-	nil,                               // 8: proto.hidden_segment.v1.HiddenSegmentRegistrationRequestBody.SegmentsEntry
-	nil,                               // 9: proto.hidden_segment.v1.HiddenSegmentsResponse.SegmentsEntry
-	nil,                               // 10: proto.hidden_segment.v1.AuthoritativeHiddenSegmentsResponse.SegmentsEntry
-	(*control_plane.PathSegment)(nil), // 11: proto.control_plane.v1.PathSegment
-	(*crypto.SignedMessage)(nil),      // 12: proto.crypto.v1.SignedMessage
+	(*HiddenSegmentGroupsRequest)(nil),           // 8: proto.hidden_segment.v1.HiddenSegmentGroupsRequest
+	(*HiddenSegmentGroupsResponse)(nil),          // 9: proto.hidden_segment.v1.HiddenSegmentGroupsResponse
+	(*HiddenSegmentGroup)(nil),                   // 10: proto.hidden_segment.v1.HiddenSegmentGroup
+	nil,                                          // 11: proto.hidden_segment.v1.HiddenSegmentRegistrationRequestBody.SegmentsEntry
+	nil,                                          // 12: proto.hidden_segment.v1.HiddenSegmentsResponse.SegmentsEntry
+	nil,                                          // 13: proto.hidden_segment.v1.AuthoritativeHiddenSegmentsResponse.SegmentsEntry
+	(*control_plane.PathSegment)(nil),            // 14: proto.control_plane.v1.PathSegment
+	(*crypto.SignedMessage)(nil),                 // 15: proto.crypto.v1.SignedMessage
 }
 var file_proto_hidden_segment_v1_hidden_segment_proto_depIdxs = []int32{
-	11, // 0: proto.hidden_segment.v1.Segments.segments:type_name -> proto.control_plane.v1.PathSegment
-	12, // 1: proto.hidden_segment.v1.HiddenSegmentRegistrationRequest.signed_request:type_name -> proto.crypto.v1.SignedMessage
-	8,  // 2: proto.hidden_segment.v1.HiddenSegmentRegistrationRequestBody.segments:type_name -> proto.hidden_segment.v1.HiddenSegmentRegistrationRequestBody.SegmentsEntry
-	9,  // 3: proto.hidden_segment.v1.HiddenSegmentsResponse.segments:type_name -> proto.hidden_segment.v1.HiddenSegmentsResponse.SegmentsEntry
-	12, // 4: proto.hidden_segment.v1.AuthoritativeHiddenSegmentsRequest.signed_request:type_name -> proto.crypto.v1.SignedMessage
-	10, // 5: proto.hidden_segment.v1.AuthoritativeHiddenSegmentsResponse.segments:type_name -> proto.hidden_segment.v1.AuthoritativeHiddenSegmentsResponse.SegmentsEntry
-	0,  // 6: proto.hidden_segment.v1.HiddenSegmentRegistrationRequestBody.SegmentsEntry.value:type_name -> proto.hidden_segment.v1.Segments
-	0,  // 7: proto.hidden_segment.v1.HiddenSegmentsResponse.SegmentsEntry.value:type_name -> proto.hidden_segment.v1.Segments
-	0,  // 8: proto.hidden_segment.v1.AuthoritativeHiddenSegmentsResponse.SegmentsEntry.value:type_name -> proto.hidden_segment.v1.Segments
-	1,  // 9: proto.hidden_segment.v1.HiddenSegmentRegistrationService.HiddenSegmentRegistration:input_type -> proto.hidden_segment.v1.HiddenSegmentRegistrationRequest
-	4,  // 10: proto.hidden_segment.v1.HiddenSegmentLookupService.HiddenSegments:input_type -> proto.hidden_segment.v1.HiddenSegmentsRequest
-	6,  // 11: proto.hidden_segment.v1.AuthoritativeHiddenSegmentLookupService.AuthoritativeHiddenSegments:input_type -> proto.hidden_segment.v1.AuthoritativeHiddenSegmentsRequest
-	3,  // 12: proto.hidden_segment.v1.HiddenSegmentRegistrationService.HiddenSegmentRegistration:output_type -> proto.hidden_segment.v1.HiddenSegmentRegistrationResponse
-	5,  // 13: proto.hidden_segment.v1.HiddenSegmentLookupService.HiddenSegments:output_type -> proto.hidden_segment.v1.HiddenSegmentsResponse
-	7,  // 14: proto.hidden_segment.v1.AuthoritativeHiddenSegmentLookupService.AuthoritativeHiddenSegments:output_type -> proto.hidden_segment.v1.AuthoritativeHiddenSegmentsResponse
-	12, // [12:15] is the sub-list for method output_type
-	9,  // [9:12] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	14, // 0: proto.hidden_segment.v1.Segments.segments:type_name -> proto.control_plane.v1.PathSegment
+	15, // 1: proto.hidden_segment.v1.HiddenSegmentRegistrationRequest.signed_request:type_name -> proto.crypto.v1.SignedMessage
+	11, // 2: proto.hidden_segment.v1.HiddenSegmentRegistrationRequestBody.segments:type_name -> proto.hidden_segment.v1.HiddenSegmentRegistrationRequestBody.SegmentsEntry
+	12, // 3: proto.hidden_segment.v1.HiddenSegmentsResponse.segments:type_name -> proto.hidden_segment.v1.HiddenSegmentsResponse.SegmentsEntry
+	15, // 4: proto.hidden_segment.v1.AuthoritativeHiddenSegmentsRequest.signed_request:type_name -> proto.crypto.v1.SignedMessage
+	13, // 5: proto.hidden_segment.v1.AuthoritativeHiddenSegmentsResponse.segments:type_name -> proto.hidden_segment.v1.AuthoritativeHiddenSegmentsResponse.SegmentsEntry
+	15, // 6: proto.hidden_segment.v1.HiddenSegmentGroupsResponse.groups:type_name -> proto.crypto.v1.SignedMessage
+	0,  // 7: proto.hidden_segment.v1.HiddenSegmentRegistrationRequestBody.SegmentsEntry.value:type_name -> proto.hidden_segment.v1.Segments
+	0,  // 8: proto.hidden_segment.v1.HiddenSegmentsResponse.SegmentsEntry.value:type_name -> proto.hidden_segment.v1.Segments
+	0,  // 9: proto.hidden_segment.v1.AuthoritativeHiddenSegmentsResponse.SegmentsEntry.value:type_name -> proto.hidden_segment.v1.Segments
+	1,  // 10: proto.hidden_segment.v1.HiddenSegmentRegistrationService.HiddenSegmentRegistration:input_type -> proto.hidden_segment.v1.HiddenSegmentRegistrationRequest
+	4,  // 11: proto.hidden_segment.v1.HiddenSegmentLookupService.HiddenSegments:input_type -> proto.hidden_segment.v1.HiddenSegmentsRequest
+	6,  // 12: proto.hidden_segment.v1.AuthoritativeHiddenSegmentLookupService.AuthoritativeHiddenSegments:input_type -> proto.hidden_segment.v1.AuthoritativeHiddenSegmentsRequest
+	8,  // 13: proto.hidden_segment.v1.HiddenSegmentGroupService.HiddenSegmentGroups:input_type -> proto.hidden_segment.v1.HiddenSegmentGroupsRequest
+	3,  // 14: proto.hidden_segment.v1.HiddenSegmentRegistrationService.HiddenSegmentRegistration:output_type -> proto.hidden_segment.v1.HiddenSegmentRegistrationResponse
+	5,  // 15: proto.hidden_segment.v1.HiddenSegmentLookupService.HiddenSegments:output_type -> proto.hidden_segment.v1.HiddenSegmentsResponse
+	7,  // 16: proto.hidden_segment.v1.AuthoritativeHiddenSegmentLookupService.AuthoritativeHiddenSegments:output_type -> proto.hidden_segment.v1.AuthoritativeHiddenSegmentsResponse
+	9,  // 17: proto.hidden_segment.v1.HiddenSegmentGroupService.HiddenSegmentGroups:output_type -> proto.hidden_segment.v1.HiddenSegmentGroupsResponse
+	14, // [14:18] is the sub-list for method output_type
+	10, // [10:14] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_proto_hidden_segment_v1_hidden_segment_proto_init() }
@@ -690,6 +908,42 @@ func file_proto_hidden_segment_v1_hidden_segment_proto_init() {
 				return nil
 			}
 		}
+		file_proto_hidden_segment_v1_hidden_segment_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HiddenSegmentGroupsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_hidden_segment_v1_hidden_segment_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HiddenSegmentGroupsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_hidden_segment_v1_hidden_segment_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HiddenSegmentGroup); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -697,9 +951,9 @@ func file_proto_hidden_segment_v1_hidden_segment_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_hidden_segment_v1_hidden_segment_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   14,
 			NumExtensions: 0,
-			NumServices:   3,
+			NumServices:   4,
 		},
 		GoTypes:           file_proto_hidden_segment_v1_hidden_segment_proto_goTypes,
 		DependencyIndexes: file_proto_hidden_segment_v1_hidden_segment_proto_depIdxs,
@@ -934,3 +1188,75 @@ var _AuthoritativeHiddenSegmentLookupService_serviceDesc = grpc.ServiceDesc{
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/hidden_segment/v1/hidden_segment.proto",
 }
+
+// HiddenSegmentGroupServiceClient is the client API for HiddenSegmentGroupService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type HiddenSegmentGroupServiceClient interface {
+	HiddenSegmentGroups(ctx context.Context, in *HiddenSegmentGroupsRequest, opts ...grpc.CallOption) (*HiddenSegmentGroupsResponse, error)
+}
+
+type hiddenSegmentGroupServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHiddenSegmentGroupServiceClient(cc grpc.ClientConnInterface) HiddenSegmentGroupServiceClient {
+	return &hiddenSegmentGroupServiceClient{cc}
+}
+
+func (c *hiddenSegmentGroupServiceClient) HiddenSegmentGroups(ctx context.Context, in *HiddenSegmentGroupsRequest, opts ...grpc.CallOption) (*HiddenSegmentGroupsResponse, error) {
+	out := new(HiddenSegmentGroupsResponse)
+	err := c.cc.Invoke(ctx, "/proto.hidden_segment.v1.HiddenSegmentGroupService/HiddenSegmentGroups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HiddenSegmentGroupServiceServer is the server API for HiddenSegmentGroupService service.
+type HiddenSegmentGroupServiceServer interface {
+	HiddenSegmentGroups(context.Context, *HiddenSegmentGroupsRequest) (*HiddenSegmentGroupsResponse, error)
+}
+
+// UnimplementedHiddenSegmentGroupServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedHiddenSegmentGroupServiceServer struct {
+}
+
+func (*UnimplementedHiddenSegmentGroupServiceServer) HiddenSegmentGroups(context.Context, *HiddenSegmentGroupsRequest) (*HiddenSegmentGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HiddenSegmentGroups not implemented")
+}
+
+func RegisterHiddenSegmentGroupServiceServer(s *grpc.Server, srv HiddenSegmentGroupServiceServer) {
+	s.RegisterService(&_HiddenSegmentGroupService_serviceDesc, srv)
+}
+
+func _HiddenSegmentGroupService_HiddenSegmentGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HiddenSegmentGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HiddenSegmentGroupServiceServer).HiddenSegmentGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.hidden_segment.v1.HiddenSegmentGroupService/HiddenSegmentGroups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HiddenSegmentGroupServiceServer).HiddenSegmentGroups(ctx, req.(*HiddenSegmentGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _HiddenSegmentGroupService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.hidden_segment.v1.HiddenSegmentGroupService",
+	HandlerType: (*HiddenSegmentGroupServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "HiddenSegmentGroups",
+			Handler:    _HiddenSegmentGroupService_HiddenSegmentGroups_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/hidden_segment/v1/hidden_segment.proto",
+}
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/scionproto/scion/go/pkg/proto/hidden_segment (interfaces: AuthoritativeHiddenSegmentLookupServiceServer,HiddenSegmentRegistrationServiceServer,HiddenSegmentLookupServiceServer)
+// Source: github.com/scionproto/scion/go/pkg/proto/hidden_segment (interfaces: AuthoritativeHiddenSegmentLookupServiceServer,HiddenSegmentRegistrationServiceServer,HiddenSegmentLookupServiceServer,HiddenSegmentGroupServiceServer)
 
 // Package mock_hidden_segment is a generated GoMock package.
 package mock_hidden_segment
@@ -124,3 +124,41 @@ func (mr *MockHiddenSegmentLookupServiceServerMockRecorder) HiddenSegments(arg0,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HiddenSegments", reflect.TypeOf((*MockHiddenSegmentLookupServiceServer)(nil).HiddenSegments), arg0, arg1)
 }
+
+// MockHiddenSegmentGroupServiceServer is a mock of HiddenSegmentGroupServiceServer interface
+type MockHiddenSegmentGroupServiceServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockHiddenSegmentGroupServiceServerMockRecorder
+}
+
+// MockHiddenSegmentGroupServiceServerMockRecorder is the mock recorder for MockHiddenSegmentGroupServiceServer
+type MockHiddenSegmentGroupServiceServerMockRecorder struct {
+	mock *MockHiddenSegmentGroupServiceServer
+}
+
+// NewMockHiddenSegmentGroupServiceServer creates a new mock instance
+func NewMockHiddenSegmentGroupServiceServer(ctrl *gomock.Controller) *MockHiddenSegmentGroupServiceServer {
+	mock := &MockHiddenSegmentGroupServiceServer{ctrl: ctrl}
+	mock.recorder = &MockHiddenSegmentGroupServiceServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockHiddenSegmentGroupServiceServer) EXPECT() *MockHiddenSegmentGroupServiceServerMockRecorder {
+	return m.recorder
+}
+
+// HiddenSegmentGroups mocks base method
+func (m *MockHiddenSegmentGroupServiceServer) HiddenSegmentGroups(arg0 context.Context, arg1 *hidden_segment.HiddenSegmentGroupsRequest) (*hidden_segment.HiddenSegmentGroupsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HiddenSegmentGroups", arg0, arg1)
+	ret0, _ := ret[0].(*hidden_segment.HiddenSegmentGroupsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HiddenSegmentGroups indicates an expected call of HiddenSegmentGroups
+func (mr *MockHiddenSegmentGroupServiceServerMockRecorder) HiddenSegmentGroups(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HiddenSegmentGroups", reflect.TypeOf((*MockHiddenSegmentGroupServiceServer)(nil).HiddenSegmentGroups), arg0, arg1)
+}
@@ -31,4 +31,7 @@ type Metrics struct {
 	Up metrics.Gauge
 	// StateChanges reports the total number of state changes of the session.
 	StateChanges metrics.Counter
+	// RTT reports the most recently measured round-trip time of the session in seconds. It is
+	// only reported if the session initiates Poll sequences.
+	RTT metrics.Gauge
 }
@@ -59,6 +59,10 @@ var (
 //
 // Session does not support the BFD Echo function. Therefore, the Required Min Echo RX field is
 // always set to 0.
+//
+// Poll sequences are only used to measure the round-trip time of the session (see PollInterval).
+// A received packet with the Poll bit set is always answered with a packet with the Final bit
+// set, as required by RFC 5880.
 type Session struct {
 	// Sender is used by the Session to send BFD messages to the other end of the point to point
 	// link.
@@ -116,6 +120,16 @@ type Session struct {
 	// must be non-zero.
 	DetectMult layers.BFDDetectMultiplier
 
+	// PollInterval is the interval between Poll sequences that the session initiates while it
+	// is Up. The round-trip time of the session is measured as the time between sending a
+	// packet with the Poll bit set and receiving the answer with the Final bit set. If it is
+	// zero, no Poll sequences are initiated.
+	//
+	// Unlike the Poll sequences described in RFC 5880, only a single periodic packet has the
+	// Poll bit set. If no answer is received, the sequence is abandoned. This way, remote
+	// systems that discard Poll packets only miss one packet per interval.
+	PollInterval time.Duration
+
 	// Logger to which the session should send logging entries. If nil, logging is disabled.
 	Logger log.Logger
 
@@ -152,6 +166,19 @@ type Session struct {
 	// remote system in a BFD Control packet.
 	remoteMinRxInterval time.Duration
 
+	// pollPending is set if the next periodic packet should have the Poll bit set.
+	pollPending bool
+	// pollSent is the time the last unanswered packet with the Poll bit set was sent. It is
+	// zero if no such packet is outstanding.
+	pollSent time.Time
+
+	// rttLock protects access to the round-trip time measurement.
+	rttLock sync.RWMutex
+	// rtt is the most recently measured round-trip time.
+	rtt time.Duration
+	// rttTimestamp is the time rtt was measured.
+	rttTimestamp time.Time
+
 	// Metrics is used by the session to report information about internal operation.
 	//
 	// If a metric is not initialized, it is not reported.
@@ -191,6 +218,15 @@ func (s *Session) Run() error {
 
 	s.desiredMinTXInterval = defaultTransmissionInterval
 	sendTimer := time.NewTimer(s.desiredMinTXInterval)
+
+	// pollTimer triggers the Poll sequences that measure the round-trip time. If the
+	// measurement is disabled, the timer never fires.
+	var pollC <-chan time.Time
+	if s.PollInterval > 0 {
+		pollTicker := time.NewTicker(s.PollInterval)
+		defer pollTicker.Stop()
+		pollC = pollTicker.C
+	}
 MainLoop:
 	for {
 		select {
@@ -242,31 +278,33 @@ MainLoop:
 				}
 				sendTimer.Reset(s.computeNextSendInterval())
 			}
+
+			if msg.Final && !s.pollSent.IsZero() {
+				s.setRTT(time.Since(s.pollSent))
+				s.pollSent = time.Time{}
+			}
+			// The answer to a Poll is sent immediately, without respecting the transmission
+			// timer (see RFC 5880, Section 6.5).
+			if msg.Poll {
+				pkt := s.controlPacket()
+				pkt.Final = true
+				s.send(pkt)
+			}
+		case <-pollC:
+			if s.getLocalState() == stateUp {
+				s.pollPending = true
+			}
 		case <-sendTimer.C:
 			// Send timer guaranteed to be expired, so we can reset.
 			sendTimer.Reset(s.computeNextSendInterval())
 
-			// These conversions are guaranteed to not return an error, because the input has been
-			// sanitized.
-			desiredMinTxInterval, _ := durationToBFDInterval(s.desiredMinTXInterval)
-			requiredMinRxInterval, _ := durationToBFDInterval(s.RequiredMinRxInterval)
-
-			pkt := layers.BFD{
-				Version:               1,
-				State:                 layers.BFDState(s.getLocalState()),
-				DetectMultiplier:      s.DetectMult,
-				MyDiscriminator:       s.LocalDiscriminator,
-				YourDiscriminator:     s.remoteDiscriminator,
-				DesiredMinTxInterval:  desiredMinTxInterval,
-				RequiredMinRxInterval: requiredMinRxInterval,
+			pkt := s.controlPacket()
+			if s.pollPending && s.getLocalState() == stateUp {
+				pkt.Poll = true
 			}
-
-			if err := s.Sender.Send(&pkt); err != nil {
-				s.debug("error sending message", "err", err)
-				continue
-			}
-			if s.Metrics.PacketsSent != nil {
-				s.Metrics.PacketsSent.Add(1)
+			if s.send(pkt) && pkt.Poll {
+				s.pollPending = false
+				s.pollSent = time.Now()
 			}
 		case <-detectionTimer.C:
 			// detection timer guaranteed to be expired, so we can reset. We reset s.t. if some
@@ -279,12 +317,44 @@ MainLoop:
 				// Change the desired interval back to the default transmission interval, to
 				// avoid flooding the network while the session is down.
 				s.desiredMinTXInterval = defaultTransmissionInterval
+				s.pollPending = false
+				s.pollSent = time.Time{}
 			}
 		}
 	}
 	return nil
 }
 
+// controlPacket returns a BFD control packet reflecting the current session state.
+func (s *Session) controlPacket() *layers.BFD {
+	// These conversions are guaranteed to not return an error, because the input has been
+	// sanitized.
+	desiredMinTxInterval, _ := durationToBFDInterval(s.desiredMinTXInterval)
+	requiredMinRxInterval, _ := durationToBFDInterval(s.RequiredMinRxInterval)
+
+	return &layers.BFD{
+		Version:               1,
+		State:                 layers.BFDState(s.getLocalState()),
+		DetectMultiplier:      s.DetectMult,
+		MyDiscriminator:       s.LocalDiscriminator,
+		YourDiscriminator:     s.remoteDiscriminator,
+		DesiredMinTxInterval:  desiredMinTxInterval,
+		RequiredMinRxInterval: requiredMinRxInterval,
+	}
+}
+
+// send sends the packet and reports whether sending was successful.
+func (s *Session) send(pkt *layers.BFD) bool {
+	if err := s.Sender.Send(pkt); err != nil {
+		s.debug("error sending message", "err", err)
+		return false
+	}
+	if s.Metrics.PacketsSent != nil {
+		s.Metrics.PacketsSent.Add(1)
+	}
+	return true
+}
+
 func (s *Session) runOnceCheck() error {
 	s.runMarkerLock.Lock()
 	defer s.runMarkerLock.Unlock()
@@ -319,6 +389,9 @@ func (s *Session) validateParameters() error {
 	if s.Sender == nil {
 		return serrors.New("sender must not be nil")
 	}
+	if s.PollInterval < 0 {
+		return serrors.New("poll interval must not be negative")
+	}
 	return nil
 }
 
@@ -333,6 +406,25 @@ func (s *Session) IsUp() bool {
 	return s.getLocalState() == stateUp
 }
 
+// RTT returns the round-trip time measured by the most recent Poll sequence, and the time of
+// the measurement. If no round-trip time has been measured yet, the zero values are returned.
+// It is safe to call RTT while Run is executed.
+func (s *Session) RTT() (time.Duration, time.Time) {
+	s.rttLock.RLock()
+	defer s.rttLock.RUnlock()
+	return s.rtt, s.rttTimestamp
+}
+
+func (s *Session) setRTT(rtt time.Duration) {
+	s.rttLock.Lock()
+	defer s.rttLock.Unlock()
+	s.rtt = rtt
+	s.rttTimestamp = time.Now()
+	if s.Metrics.RTT != nil {
+		s.Metrics.RTT.Set(rtt.Seconds())
+	}
+}
+
 // getLocalState is a concurrency-safe getter for local state.
 func (s *Session) getLocalState() state {
 	s.localStateLock.RLock()
@@ -488,12 +580,9 @@ func shouldDiscard(localState state, pkt *layers.BFD) (bool, string) {
 				"Packet will be discarded."
 	}
 
-	// Poll/final sequences are not supported (see Anapaya/scion#3281).
-	if pkt.Poll {
-		return true, "Received Poll packet, but poll mechanism is not supported."
-	}
-	if pkt.Final {
-		return true, "Received Final packet, but poll mechanism is not supported."
+	// The Poll and Final bits must never both be set (see RFC 5880, Section 4.1).
+	if pkt.Poll && pkt.Final {
+		return true, "Received packet with both Poll and Final bit set."
 	}
 
 	// Echo function is not supported. We discard such packets to ensure that the
@@ -433,6 +433,57 @@ func TestSessionDebootstrap(t *testing.T) {
 	wg.Wait()
 }
 
+func TestSessionRTT(t *testing.T) {
+	sessionA := &bfd.Session{
+		DetectMult:            3,
+		DesiredMinTxInterval:  20 * time.Millisecond,
+		RequiredMinRxInterval: 20 * time.Millisecond,
+		PollInterval:          50 * time.Millisecond,
+		Logger:                log.New(),
+		LocalDiscriminator:    1,
+		ReceiveQueueSize:      10,
+	}
+	sessionB := &bfd.Session{
+		DetectMult:            3,
+		DesiredMinTxInterval:  20 * time.Millisecond,
+		RequiredMinRxInterval: 20 * time.Millisecond,
+		Logger:                log.New(),
+		LocalDiscriminator:    2,
+		ReceiveQueueSize:      10,
+	}
+	linkAToB := &redirectSender{Destination: sessionB.Messages(), shouldSend: true}
+	linkBToA := &redirectSender{Destination: sessionA.Messages(), shouldSend: true}
+	sessionA.Sender = linkAToB
+	sessionB.Sender = linkBToA
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, sessionA.Run())
+	}()
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, sessionB.Run())
+	}()
+
+	assert.Eventually(t, func() bool {
+		_, ts := sessionA.RTT()
+		return !ts.IsZero()
+	}, 2*time.Second, 10*time.Millisecond)
+	rtt, ts := sessionA.RTT()
+	assert.Greater(t, int64(rtt), int64(0))
+	assert.WithinDuration(t, time.Now(), ts, 2*time.Second)
+	// Session B does not initiate Poll sequences, it only answers them.
+	rtt, ts = sessionB.RTT()
+	assert.Zero(t, rtt)
+	assert.True(t, ts.IsZero())
+
+	linkAToB.Close()
+	linkBToA.Close()
+	wg.Wait()
+}
+
 func TestSessionRun(t *testing.T) {
 	testCases := map[string]struct {
 		session *bfd.Session
@@ -768,8 +819,8 @@ func TestShouldDiscard(t *testing.T) {
 				return pkt
 			},
 			localState:    bfd.StateUp,
-			shouldDiscard: true,
-			hasReason:     assert.NotEmpty,
+			shouldDiscard: false,
+			hasReason:     assert.Empty,
 		},
 		"final bit set": {
 			packetEdit: func(pkt layers.BFD) layers.BFD {
@@ -777,6 +828,16 @@ func TestShouldDiscard(t *testing.T) {
 				return pkt
 			},
 			localState:    bfd.StateUp,
+			shouldDiscard: false,
+			hasReason:     assert.Empty,
+		},
+		"poll and final bit set": {
+			packetEdit: func(pkt layers.BFD) layers.BFD {
+				pkt.Poll = true
+				pkt.Final = true
+				return pkt
+			},
+			localState:    bfd.StateUp,
 			shouldDiscard: true,
 			hasReason:     assert.NotEmpty,
 		},
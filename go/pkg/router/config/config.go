@@ -27,10 +27,12 @@ import (
 const idSample = "router-1"
 
 type Config struct {
-	General  env.General  `toml:"general,omitempty"`
-	Features env.Features `toml:"features,omitempty"`
-	Logging  log.Config   `toml:"log,omitempty"`
-	Metrics  env.Metrics  `toml:"metrics,omitempty"`
+	General     env.General  `toml:"general,omitempty"`
+	Features    env.Features `toml:"features,omitempty"`
+	Logging     log.Config   `toml:"log,omitempty"`
+	Metrics     env.Metrics  `toml:"metrics,omitempty"`
+	RateLimit   RateLimit    `toml:"rate_limit,omitempty"`
+	LinkLatency LinkLatency  `toml:"link_latency,omitempty"`
 }
 
 func (cfg *Config) InitDefaults() {
@@ -39,6 +41,8 @@ func (cfg *Config) InitDefaults() {
 		&cfg.Features,
 		&cfg.Logging,
 		&cfg.Metrics,
+		&cfg.RateLimit,
+		&cfg.LinkLatency,
 	)
 }
 
@@ -48,6 +52,8 @@ func (cfg *Config) Validate() error {
 		&cfg.Features,
 		&cfg.Logging,
 		&cfg.Metrics,
+		&cfg.RateLimit,
+		&cfg.LinkLatency,
 	)
 }
 
@@ -57,5 +63,7 @@ func (cfg *Config) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
 		&cfg.Features,
 		&cfg.Logging,
 		&cfg.Metrics,
+		&cfg.RateLimit,
+		&cfg.LinkLatency,
 	)
 }
@@ -17,12 +17,16 @@ package config_test
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/pelletier/go-toml"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/scionproto/scion/go/lib/addr"
 	"github.com/scionproto/scion/go/lib/env/envtest"
 	"github.com/scionproto/scion/go/lib/log/logtest"
+	"github.com/scionproto/scion/go/lib/xtest"
 	"github.com/scionproto/scion/go/pkg/router/config"
 )
 
@@ -37,6 +41,89 @@ func TestConfigSample(t *testing.T) {
 	CheckTestConfig(t, &cfg, config.IDSample)
 }
 
+func TestRateLimit(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		var cfg config.RateLimit
+		err := toml.Unmarshal([]byte(`
+[scmp]
+rate = 100
+per_destination_burst = 5
+
+[ingress.interfaces]
+"1" = { rate = 1000000000 }
+"2" = { rate = 1000, burst = 20000 }
+
+[ingress.source_isd_as]
+"1-ff00:0:110" = { rate = 8000 }
+
+[ingress.default_source_isd_as]
+rate = 80000
+`), &cfg)
+		require.NoError(t, err)
+		cfg.InitDefaults()
+		require.NoError(t, cfg.Validate())
+
+		assert.Equal(t, uint64(100), cfg.SCMP.Burst)
+		assert.Equal(t, uint64(5), cfg.SCMP.PerDestinationBurst)
+		intfs, err := cfg.Ingress.InterfaceLimits()
+		require.NoError(t, err)
+		assert.Equal(t, map[uint16]config.Limit{
+			1: {Rate: 1000000000, Burst: 12500000},
+			2: {Rate: 1000, Burst: 20000},
+		}, intfs)
+		sources, err := cfg.Ingress.SourceLimits()
+		require.NoError(t, err)
+		assert.Equal(t, map[addr.IA]config.Limit{
+			xtest.MustParseIA("1-ff00:0:110"): {Rate: 8000, Burst: config.MinIngressBurst},
+		}, sources)
+		assert.Equal(t, config.Limit{Rate: 80000, Burst: config.MinIngressBurst},
+			cfg.Ingress.DefaultSourceISDAS)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := map[string]config.RateLimit{
+			"invalid interface": {
+				Ingress: config.IngressRateLimit{
+					Interfaces: map[string]config.Limit{"a": {Rate: 1}},
+				},
+			},
+			"interface without rate": {
+				Ingress: config.IngressRateLimit{
+					Interfaces: map[string]config.Limit{"1": {}},
+				},
+			},
+			"invalid isd as": {
+				Ingress: config.IngressRateLimit{
+					SourceISDASes: map[string]config.Limit{"1-ff00": {Rate: 1}},
+				},
+			},
+			"default source without rate": {
+				Ingress: config.IngressRateLimit{
+					DefaultSourceISDAS: config.Limit{Burst: 10000},
+				},
+			},
+		}
+		for name, cfg := range testCases {
+			cfg := cfg
+			cfg.InitDefaults()
+			assert.Error(t, cfg.Validate(), name)
+		}
+	})
+}
+
+func TestLinkLatency(t *testing.T) {
+	var cfg config.LinkLatency
+	cfg.InitDefaults()
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, config.DefaultLinkLatencyInterval, cfg.PollInterval())
+
+	cfg.Disable = true
+	assert.Zero(t, cfg.PollInterval())
+
+	cfg = config.LinkLatency{}
+	cfg.Interval.Duration = -time.Second
+	assert.Error(t, cfg.Validate())
+}
+
 func InitTestConfig(cfg *config.Config) {
 	envtest.InitTest(&cfg.General, &cfg.Metrics, nil, nil)
 	logtest.InitTestLogging(&cfg.Logging)
@@ -45,4 +132,6 @@ func InitTestConfig(cfg *config.Config) {
 func CheckTestConfig(t *testing.T, cfg *config.Config, id string) {
 	envtest.CheckTest(t, &cfg.General, &cfg.Metrics, nil, nil, id)
 	logtest.CheckTestLogging(t, &cfg.Logging, id)
+	assert.False(t, cfg.LinkLatency.Disable)
+	assert.Equal(t, config.DefaultLinkLatencyInterval, cfg.LinkLatency.Interval.Duration)
 }
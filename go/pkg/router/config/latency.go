@@ -0,0 +1,81 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"io"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/config"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/util"
+)
+
+// DefaultLinkLatencyInterval is the default interval between round-trip time
+// measurements on an external link.
+const DefaultLinkLatencyInterval = 10 * time.Second
+
+var _ config.Config = (*LinkLatency)(nil)
+
+// LinkLatency contains the configuration of the round-trip time measurements
+// on the external links. The measurements use BFD poll sequences, they are
+// only done on links with BFD enabled.
+type LinkLatency struct {
+	// Disable disables the round-trip time measurements.
+	Disable bool `toml:"disable,omitempty"`
+	// Interval is the interval between measurements on an external link.
+	Interval util.DurWrap `toml:"interval,omitempty"`
+}
+
+func (cfg *LinkLatency) InitDefaults() {
+	if cfg.Interval.Duration == 0 {
+		cfg.Interval.Duration = DefaultLinkLatencyInterval
+	}
+}
+
+func (cfg *LinkLatency) Validate() error {
+	if cfg.Interval.Duration < 0 {
+		return serrors.New("interval must not be negative", "interval", cfg.Interval)
+	}
+	return nil
+}
+
+// PollInterval returns the interval between BFD poll sequences that measure
+// the round-trip time. It is zero if the measurements are disabled.
+func (cfg *LinkLatency) PollInterval() time.Duration {
+	if cfg.Disable {
+		return 0
+	}
+	return cfg.Interval.Duration
+}
+
+func (cfg *LinkLatency) Sample(dst io.Writer, _ config.Path, _ config.CtxMap) {
+	config.WriteString(dst, linkLatencySample)
+}
+
+func (cfg *LinkLatency) ConfigName() string {
+	return "link_latency"
+}
+
+const linkLatencySample = `
+# Disable the round-trip time measurements on the external links. The
+# measurements use BFD poll sequences, they are only done on links with BFD
+# enabled. (default false)
+disable = false
+
+# The interval between round-trip time measurements on an external link.
+# (default 10s)
+interval = "10s"
+`
@@ -0,0 +1,238 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/config"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+// MinIngressBurst is the minimum burst size in bytes of ingress rate limits.
+// It corresponds to the maximum packet size handled by the router, such that
+// every packet can pass an idle rate limiter.
+const MinIngressBurst = 9000
+
+var _ config.Config = (*RateLimit)(nil)
+
+// RateLimit contains the rate limits enforced by the router. Limits that are
+// not set are not enforced.
+type RateLimit struct {
+	// SCMP contains the rate limits for the generation of SCMP messages.
+	SCMP SCMPRateLimit `toml:"scmp,omitempty"`
+	// Ingress contains the rate limits for the traffic received on external
+	// interfaces.
+	Ingress IngressRateLimit `toml:"ingress,omitempty"`
+}
+
+func (cfg *RateLimit) InitDefaults() {
+	config.InitAll(&cfg.SCMP, &cfg.Ingress)
+}
+
+func (cfg *RateLimit) Validate() error {
+	return config.ValidateAll(&cfg.SCMP, &cfg.Ingress)
+}
+
+func (cfg *RateLimit) Sample(dst io.Writer, path config.Path, ctx config.CtxMap) {
+	config.WriteSample(dst, path, ctx, &cfg.SCMP, &cfg.Ingress)
+}
+
+func (cfg *RateLimit) ConfigName() string {
+	return "rate_limit"
+}
+
+var _ config.Config = (*SCMPRateLimit)(nil)
+
+// SCMPRateLimit contains the rate limits for the generation of SCMP messages.
+// The rates are in messages per second.
+type SCMPRateLimit struct {
+	config.NoValidator
+	// Rate is the maximum rate of SCMP messages generated by the router.
+	Rate uint64 `toml:"rate,omitempty"`
+	// Burst is the maximum burst of SCMP messages. (default: rate, at least 1)
+	Burst uint64 `toml:"burst,omitempty"`
+	// PerDestinationRate is the maximum rate of SCMP messages sent to a
+	// single destination host.
+	PerDestinationRate uint64 `toml:"per_destination_rate,omitempty"`
+	// PerDestinationBurst is the maximum burst of SCMP messages sent to a
+	// single destination host. (default: per_destination_rate, at least 1)
+	PerDestinationBurst uint64 `toml:"per_destination_burst,omitempty"`
+}
+
+func (cfg *SCMPRateLimit) InitDefaults() {
+	if cfg.Burst == 0 {
+		cfg.Burst = cfg.Rate
+	}
+	if cfg.Burst == 0 {
+		cfg.Burst = 1
+	}
+	if cfg.PerDestinationBurst == 0 {
+		cfg.PerDestinationBurst = cfg.PerDestinationRate
+	}
+	if cfg.PerDestinationBurst == 0 {
+		cfg.PerDestinationBurst = 1
+	}
+}
+
+func (cfg *SCMPRateLimit) Sample(dst io.Writer, _ config.Path, _ config.CtxMap) {
+	config.WriteString(dst, scmpRateLimitSample)
+}
+
+func (cfg *SCMPRateLimit) ConfigName() string {
+	return "scmp"
+}
+
+var _ config.Config = (*IngressRateLimit)(nil)
+
+// IngressRateLimit contains the rate limits for the traffic received on
+// external interfaces. Packets exceeding a limit are dropped.
+type IngressRateLimit struct {
+	// Interfaces contains the rate limits per external interface, keyed by
+	// the interface ID.
+	Interfaces map[string]Limit `toml:"interfaces,omitempty"`
+	// SourceISDASes contains the rate limits for the traffic originating from
+	// an ISD-AS, keyed by the ISD-AS. The limit applies to the traffic received
+	// on all external interfaces.
+	SourceISDASes map[string]Limit `toml:"source_isd_as,omitempty"`
+	// DefaultSourceISDAS is the rate limit for the traffic originating from
+	// each ISD-AS that is not listed in SourceISDASes. Every source ISD-AS is
+	// limited separately. If the rate is not set, the traffic is not limited.
+	DefaultSourceISDAS Limit `toml:"default_source_isd_as,omitempty"`
+}
+
+// Limit is a rate limit for traffic.
+type Limit struct {
+	// Rate is the maximum rate in bits per second.
+	Rate uint64 `toml:"rate,omitempty"`
+	// Burst is the maximum burst in bytes. (default: 100ms at the maximum
+	// rate, at least MinIngressBurst)
+	Burst uint64 `toml:"burst,omitempty"`
+}
+
+func (l *Limit) initDefaults() {
+	if l.Burst == 0 {
+		l.Burst = l.Rate / 8 / 10
+	}
+	if l.Burst < MinIngressBurst {
+		l.Burst = MinIngressBurst
+	}
+}
+
+func (cfg *IngressRateLimit) InitDefaults() {
+	for k, l := range cfg.Interfaces {
+		l.initDefaults()
+		cfg.Interfaces[k] = l
+	}
+	for k, l := range cfg.SourceISDASes {
+		l.initDefaults()
+		cfg.SourceISDASes[k] = l
+	}
+	if cfg.DefaultSourceISDAS.Rate > 0 {
+		cfg.DefaultSourceISDAS.initDefaults()
+	}
+}
+
+func (cfg *IngressRateLimit) Validate() error {
+	if _, err := cfg.InterfaceLimits(); err != nil {
+		return err
+	}
+	if _, err := cfg.SourceLimits(); err != nil {
+		return err
+	}
+	if cfg.DefaultSourceISDAS.Rate == 0 && cfg.DefaultSourceISDAS.Burst != 0 {
+		return serrors.New("default source ingress rate limit without rate")
+	}
+	return nil
+}
+
+// InterfaceLimits returns the rate limits keyed by the interface ID.
+func (cfg *IngressRateLimit) InterfaceLimits() (map[uint16]Limit, error) {
+	limits := make(map[uint16]Limit, len(cfg.Interfaces))
+	for k, l := range cfg.Interfaces {
+		ifID, err := strconv.ParseUint(k, 10, 16)
+		if err != nil || ifID == 0 {
+			return nil, serrors.New("invalid interface ID in ingress rate limits",
+				"interface", k)
+		}
+		if l.Rate == 0 {
+			return nil, serrors.New("ingress rate limit without rate", "interface", k)
+		}
+		limits[uint16(ifID)] = l
+	}
+	return limits, nil
+}
+
+// SourceLimits returns the rate limits keyed by the source ISD-AS.
+func (cfg *IngressRateLimit) SourceLimits() (map[addr.IA]Limit, error) {
+	limits := make(map[addr.IA]Limit, len(cfg.SourceISDASes))
+	for k, l := range cfg.SourceISDASes {
+		ia, err := addr.IAFromString(k)
+		if err != nil {
+			return nil, serrors.WrapStr("invalid ISD-AS in ingress rate limits", err,
+				"isd_as", k)
+		}
+		if l.Rate == 0 {
+			return nil, serrors.New("ingress rate limit without rate", "isd_as", k)
+		}
+		limits[ia] = l
+	}
+	return limits, nil
+}
+
+func (cfg *IngressRateLimit) Sample(dst io.Writer, _ config.Path, _ config.CtxMap) {
+	config.WriteString(dst, ingressRateLimitSample)
+}
+
+func (cfg *IngressRateLimit) ConfigName() string {
+	return "ingress"
+}
+
+const scmpRateLimitSample = `
+# The maximum rate of SCMP messages generated by the router in messages per
+# second. If not set, the rate is not limited. (default 0)
+rate = 0
+
+# The maximum burst of SCMP messages generated by the router. (default rate,
+# at least 1)
+burst = 0
+
+# The maximum rate of SCMP messages sent to a single destination host in
+# messages per second. If not set, the rate is not limited. (default 0)
+per_destination_rate = 0
+
+# The maximum burst of SCMP messages sent to a single destination host.
+# (default per_destination_rate, at least 1)
+per_destination_burst = 0
+`
+
+const ingressRateLimitSample = `
+# The rate limits for the traffic received on external interfaces, keyed by
+# the interface ID. The rate is in bits per second, the burst in bytes. Packets
+# exceeding the limit are dropped. (default burst: 100ms at the rate, at least
+# 9000 bytes)
+# interfaces = { "1" = { rate = 1000000000, burst = 1000000 } }
+
+# The rate limits for the traffic originating from an ISD-AS, keyed by the
+# ISD-AS. The limit applies to the traffic received on all external interfaces.
+# source_isd_as = { "1-ff00:0:110" = { rate = 100000000 } }
+
+# The rate limit for the traffic originating from each ISD-AS that is not
+# listed in source_isd_as. Every source ISD-AS is limited separately. If the
+# rate is not set, the traffic is not limited.
+# default_source_isd_as = { rate = 10000000 }
+`
@@ -49,6 +49,16 @@ func (c *Connector) CreateIACtx(ia addr.IA) error {
 	return c.DataPlane.SetIA(ia)
 }
 
+// SetPortRange sets the range of end host ports that packets are delivered to
+// directly.
+func (c *Connector) SetPortRange(ia addr.IA, start, end uint16) error {
+	log.Debug("Setting dispatched port range", "isd_as", ia, "start", start, "end", end)
+	if !c.ia.Equal(ia) {
+		return serrors.WithCtx(errMultiIA, "current", c.ia, "new", ia)
+	}
+	return c.DataPlane.SetPortRange(start, end)
+}
+
 // AddInternalInterface adds the internal interface.
 func (c *Connector) AddInternalInterface(ia addr.IA, local net.UDPAddr) error {
 	log.Debug("Adding internal interface", "isd_as", ia, "local", local)
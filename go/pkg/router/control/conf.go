@@ -37,6 +37,7 @@ type Dataplane interface {
 	AddSvc(ia addr.IA, svc addr.HostSVC, ip net.IP) error
 	DelSvc(ia addr.IA, svc addr.HostSVC, ip net.IP) error
 	SetKey(ia addr.IA, index int, key []byte) error
+	SetPortRange(ia addr.IA, start, end uint16) error
 
 	SetRevocation(ia addr.IA, ifid common.IFIDType, rev []byte) error
 	DelRevocation(ia addr.IA, ifid common.IFIDType) error
@@ -78,6 +79,14 @@ func ConfigDataplane(dp Dataplane, cfg *Config) error {
 			return err
 		}
 	}
+	// Set the range of end host ports that are delivered to directly.
+	if cfg.Topo != nil {
+		if start, end := cfg.Topo.PortRange(); start != 0 {
+			if err := dp.SetPortRange(cfg.IA, start, end); err != nil {
+				return err
+			}
+		}
+	}
 	// Add internal interfaces
 	if cfg.BR != nil {
 		if cfg.BR.InternalAddr != nil {
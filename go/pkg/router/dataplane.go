@@ -18,11 +18,13 @@ import (
 	"bytes"
 	"crypto/rand"
 	"crypto/subtle"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"hash"
 	"math/big"
 	"net"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -49,7 +51,9 @@ import (
 	"github.com/scionproto/scion/go/lib/underlay/conn"
 	underlayconn "github.com/scionproto/scion/go/lib/underlay/conn"
 	"github.com/scionproto/scion/go/lib/util"
+	"github.com/scionproto/scion/go/pkg/linklatency"
 	"github.com/scionproto/scion/go/pkg/router/bfd"
+	"github.com/scionproto/scion/go/pkg/router/config"
 	"github.com/scionproto/scion/go/pkg/router/control"
 )
 
@@ -72,6 +76,7 @@ type bfdSession interface {
 	Run() error
 	Messages() chan<- *layers.BFD
 	IsUp() bool
+	RTT() (time.Duration, time.Time)
 }
 
 // BatchConn is a connection that supports batch reads and writes.
@@ -89,20 +94,29 @@ type BatchConn interface {
 // Currently, only the following features are supported:
 //  - initializing connections; MUST be done prior to calling Run
 type DataPlane struct {
-	external          map[uint16]BatchConn
-	linkTypes         map[uint16]topology.LinkType
-	neighborIAs       map[uint16]addr.IA
-	internal          BatchConn
-	internalIP        net.IP
-	internalNextHops  map[uint16]net.Addr
-	svc               *services
-	macFactory        func() hash.Hash
-	bfdSessions       map[uint16]bfdSession
-	localIA           addr.IA
-	mtx               sync.Mutex
-	running           bool
-	Metrics           *Metrics
-	forwardingMetrics map[uint16]forwardingMetrics
+	external         map[uint16]BatchConn
+	linkTypes        map[uint16]topology.LinkType
+	neighborIAs      map[uint16]addr.IA
+	internal         BatchConn
+	internalIP       net.IP
+	internalNextHops map[uint16]net.Addr
+	svc              *services
+	macFactory       func() hash.Hash
+	bfdSessions      map[uint16]bfdSession
+	localIA          addr.IA
+	// dispatchedPortStart and dispatchedPortEnd define the inclusive range of
+	// end host ports that packets are delivered to directly. Packets to other
+	// ports are delivered to the dispatcher.
+	dispatchedPortStart uint16
+	dispatchedPortEnd   uint16
+	mtx                 sync.Mutex
+	running             bool
+	Metrics             *Metrics
+	forwardingMetrics   map[uint16]forwardingMetrics
+	rateLimits          rateLimits
+	// latencyInterval is the interval between round-trip time measurements
+	// on the external links. If it is zero, no measurements are done.
+	latencyInterval time.Duration
 }
 
 var (
@@ -128,6 +142,15 @@ func (e scmpError) Error() string {
 	return serrors.New("scmp", "typecode", e.TypeCode, "cause", e.Cause).Error()
 }
 
+// rateLimitError indicates that a packet is dropped because of a rate limit.
+type rateLimitError struct {
+	Reason string
+}
+
+func (e rateLimitError) Error() string {
+	return serrors.New("rate limited", "reason", e.Reason).Error()
+}
+
 // SetIA sets the local IA for the dataplane.
 func (d *DataPlane) SetIA(ia addr.IA) error {
 	d.mtx.Lock()
@@ -170,6 +193,80 @@ func (d *DataPlane) SetKey(key []byte) error {
 	return nil
 }
 
+// SetPortRange sets the inclusive range of end host ports to which packets are
+// delivered directly. Packets destined to ports outside of the range are
+// delivered to the dispatcher.
+func (d *DataPlane) SetPortRange(start, end uint16) error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	if d.running {
+		return modifyExisting
+	}
+	if start == 0 || start > end {
+		return serrors.New("invalid port range", "start", start, "end", end)
+	}
+	if d.dispatchedPortStart != 0 {
+		return alreadySet
+	}
+	d.dispatchedPortStart, d.dispatchedPortEnd = start, end
+	return nil
+}
+
+// SetRateLimits sets the rate limits enforced by the dataplane. This can only
+// be called on a not yet running dataplane.
+func (d *DataPlane) SetRateLimits(cfg config.RateLimit) error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	if d.running {
+		return modifyExisting
+	}
+	l, err := newRateLimits(cfg)
+	if err != nil {
+		return err
+	}
+	d.rateLimits = l
+	return nil
+}
+
+// SetLinkLatency configures the round-trip time measurements on the external
+// links. This can only be called on a not yet running dataplane, before the
+// external BFD sessions are added.
+func (d *DataPlane) SetLinkLatency(cfg config.LinkLatency) error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	if d.running {
+		return modifyExisting
+	}
+	d.latencyInterval = cfg.PollInterval()
+	return nil
+}
+
+// LinkLatencies returns the most recent round-trip times measured on the
+// external links. Links whose BFD session is not up, or that have not been
+// measured yet, are omitted.
+func (d *DataPlane) LinkLatencies() []linklatency.Measurement {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	var ms []linklatency.Measurement
+	for ifID := range d.external {
+		s, ok := d.bfdSessions[ifID]
+		if !ok || !s.IsUp() {
+			continue
+		}
+		rtt, ts := s.RTT()
+		if ts.IsZero() {
+			continue
+		}
+		ms = append(ms, linklatency.Measurement{
+			Interface: common.IFIDType(ifID),
+			RTT:       util.DurWrap{Duration: rtt},
+			Timestamp: ts,
+		})
+	}
+	sort.Slice(ms, func(i, j int) bool { return ms[i].Interface < ms[j].Interface })
+	return ms
+}
+
 // AddInternalInterface sets the interface the data-plane will use to
 // send/receive traffic in the local AS. This can only be called once; future
 // calls will return an error. This can only be called on a not yet running
@@ -277,6 +374,8 @@ func (d *DataPlane) AddExternalInterfaceBFD(ifID uint16, conn BatchConn,
 				With(labels...),
 			PacketsReceived: metrics.NewPromCounter(d.Metrics.BFDPacketsReceived).
 				With(labels...),
+			RTT: metrics.NewPromGauge(d.Metrics.BFDRoundTripTime).
+				With(labels...),
 		}
 	}
 	s := &bfdSend{
@@ -288,11 +387,11 @@ func (d *DataPlane) AddExternalInterfaceBFD(ifID uint16, conn BatchConn,
 		ifID:    ifID,
 		mac:     d.macFactory(),
 	}
-	return d.addBFDController(ifID, s, cfg, m)
+	return d.addBFDController(ifID, s, cfg, d.latencyInterval, m)
 }
 
 func (d *DataPlane) addBFDController(ifID uint16, s *bfdSend, cfg control.BFD,
-	metrics bfd.Metrics) error {
+	pollInterval time.Duration, metrics bfd.Metrics) error {
 
 	if cfg.Disable {
 		return errBFDDisabled
@@ -313,6 +412,7 @@ func (d *DataPlane) addBFDController(ifID uint16, s *bfdSend, cfg control.BFD,
 		Logger:                log.New("component", "BFD"),
 		DesiredMinTxInterval:  cfg.DesiredMinTxInterval,
 		RequiredMinRxInterval: cfg.RequiredMinRxInterval,
+		PollInterval:          pollInterval,
 		LocalDiscriminator:    disc,
 		ReceiveQueueSize:      10,
 		Metrics:               metrics,
@@ -429,7 +529,7 @@ func (d *DataPlane) AddNextHopBFD(ifID uint16, src, dst *net.UDPAddr, cfg contro
 		ifID:    0,
 		mac:     d.macFactory(),
 	}
-	return d.addBFDController(ifID, s, cfg, m)
+	return d.addBFDController(ifID, s, cfg, 0, m)
 }
 
 // Run starts running the dataplane. Note that configuration is not possible
@@ -449,9 +549,11 @@ func (d *DataPlane) Run() error {
 		mac := d.macFactory()
 
 		var scmpErr scmpError
+		var rateLimitErr rateLimitError
 		spkt := slayers.SCION{}
 		buffer := gopacket.NewSerializeBuffer()
 		origPacket := make([]byte, bufSize)
+		ports := newSCMPPortParser()
 		for d.running {
 			pkts, err := rd.ReadBatch(msgs)
 			if err != nil {
@@ -474,10 +576,13 @@ func (d *DataPlane) Run() error {
 				inputCounters.InputBytesTotal.Add(float64(p.N))
 
 				result, err := d.processPkt(ingressID, p.Buffers[0], p.Addr, spkt, origPacket,
-					buffer, mac)
+					buffer, mac, ports)
 
 				switch {
 				case err == nil:
+				case errors.As(err, &rateLimitErr):
+					inputCounters.RateLimitedPacketsTotal[rateLimitErr.Reason].Inc()
+					continue
 				case errors.As(err, &scmpErr):
 					if !scmpErr.TypeCode.InfoMsg() {
 						log.Debug("SCMP", "err", scmpErr, "dst_addr", p.Addr)
@@ -563,7 +668,8 @@ type processResult struct {
 }
 
 func (d *DataPlane) processPkt(ingressID uint16, rawPkt []byte, srcAddr net.Addr, s slayers.SCION,
-	origPacket []byte, buffer gopacket.SerializeBuffer, mac hash.Hash) (processResult, error) {
+	origPacket []byte, buffer gopacket.SerializeBuffer, mac hash.Hash,
+	ports *scmpPortParser) (processResult, error) {
 
 	if err := s.DecodeFromBytes(rawPkt, gopacket.NilDecodeFeedback); err != nil {
 		return processResult{}, err
@@ -571,6 +677,14 @@ func (d *DataPlane) processPkt(ingressID uint16, rawPkt []byte, srcAddr net.Addr
 	if err := buffer.Clear(); err != nil {
 		return processResult{}, serrors.WrapStr("Failed to clear buffer", err)
 	}
+	// Traffic received on external interfaces is policed, except for BFD
+	// packets that are required to keep the links up.
+	if _, external := d.external[ingressID]; external && s.NextHdr != common.L4BFD {
+		ok, reason := d.rateLimits.allowIngress(time.Now(), ingressID, s.SrcIA, len(rawPkt))
+		if !ok {
+			return processResult{}, rateLimitError{Reason: reason}
+		}
+	}
 
 	switch s.PathType {
 	case empty.PathType:
@@ -587,11 +701,11 @@ func (d *DataPlane) processPkt(ingressID uint16, rawPkt []byte, srcAddr net.Addr
 			}
 			return processResult{}, d.processInterBFD(ingressID, ohp, s.Payload)
 		}
-		return d.processOHP(ingressID, rawPkt, s, buffer, mac)
+		return d.processOHP(ingressID, rawPkt, s, buffer, mac, ports)
 	case scion.PathType:
-		return d.processSCION(ingressID, rawPkt, s, origPacket, buffer, mac)
+		return d.processSCION(ingressID, rawPkt, s, origPacket, buffer, mac, ports)
 	case epic.PathType:
-		return d.processEPIC(ingressID, rawPkt, s, origPacket, buffer, mac)
+		return d.processEPIC(ingressID, rawPkt, s, origPacket, buffer, mac, ports)
 	default:
 		return processResult{}, serrors.WithCtx(unsupportedPathType, "type", s.PathType)
 	}
@@ -652,7 +766,8 @@ func (d *DataPlane) processIntraBFD(src net.Addr, data []byte) error {
 }
 
 func (d *DataPlane) processSCION(ingressID uint16, rawPkt []byte, s slayers.SCION,
-	origPacket []byte, buffer gopacket.SerializeBuffer, mac hash.Hash) (processResult, error) {
+	origPacket []byte, buffer gopacket.SerializeBuffer, mac hash.Hash,
+	ports *scmpPortParser) (processResult, error) {
 
 	p := scionPacketProcessor{
 		d:          d,
@@ -662,6 +777,7 @@ func (d *DataPlane) processSCION(ingressID uint16, rawPkt []byte, s slayers.SCIO
 		origPacket: origPacket,
 		buffer:     buffer,
 		mac:        mac,
+		scmpPorts:  ports,
 	}
 
 	var ok bool
@@ -675,7 +791,8 @@ func (d *DataPlane) processSCION(ingressID uint16, rawPkt []byte, s slayers.SCIO
 }
 
 func (d *DataPlane) processEPIC(ingressID uint16, rawPkt []byte, s slayers.SCION,
-	origPacket []byte, buffer gopacket.SerializeBuffer, mac hash.Hash) (processResult, error) {
+	origPacket []byte, buffer gopacket.SerializeBuffer, mac hash.Hash,
+	ports *scmpPortParser) (processResult, error) {
 
 	path, ok := s.Path.(*epic.Path)
 	if !ok {
@@ -700,6 +817,7 @@ func (d *DataPlane) processEPIC(ingressID uint16, rawPkt []byte, s slayers.SCION
 		origPacket: origPacket,
 		buffer:     buffer,
 		mac:        mac,
+		scmpPorts:  ports,
 		path:       scionPath,
 	}
 	result, err := p.process()
@@ -761,6 +879,10 @@ type scionPacketProcessor struct {
 	// cachedMac contains the full 16 bytes of the MAC. Will be set during processing.
 	// For a hop performing an Xover, it is the MAC corresponding to the down segment.
 	cachedMac []byte
+
+	// scmpPorts is used to determine the destination port of SCMP messages
+	// that are delivered to end hosts.
+	scmpPorts *scmpPortParser
 }
 
 func (p *scionPacketProcessor) packSCMP(scmpH *slayers.SCMP, scmpP gopacket.SerializableLayer,
@@ -787,6 +909,13 @@ func (p *scionPacketProcessor) packSCMP(scmpH *slayers.SCMP, scmpP gopacket.Seri
 	if decoded[len(decoded)-1] == slayers.LayerTypeSCMP && !scmpLayer.TypeCode.InfoMsg() {
 		return processResult{}, serrors.WrapStr("SCMP error for SCMP error pkt -> DROP", cause)
 	}
+	// The SCMP message is sent to the source of the original packet.
+	dst := make([]byte, 8+len(p.scionLayer.RawSrcAddr))
+	binary.BigEndian.PutUint64(dst, uint64(p.scionLayer.SrcIA.IAInt()))
+	copy(dst[8:], p.scionLayer.RawSrcAddr)
+	if !p.d.rateLimits.allowSCMP(time.Now(), string(dst)) {
+		return processResult{}, rateLimitError{Reason: rateLimitSCMP}
+	}
 
 	// the quoted packet is the packet in its current state
 	if err := p.path.SetInfoField(p.infoField, int(p.path.PathMeta.CurrINF)); err != nil {
@@ -984,7 +1113,7 @@ func (p *scionPacketProcessor) verifyCurrentMAC() (processResult, error) {
 }
 
 func (p *scionPacketProcessor) resolveInbound() (net.Addr, processResult, error) {
-	a, err := p.d.resolveLocalDst(p.scionLayer)
+	a, err := p.d.resolveLocalDst(p.scionLayer, p.scmpPorts)
 	switch {
 	case errors.Is(err, noSVCBackend):
 		r, err := p.packSCMP(
@@ -1238,7 +1367,7 @@ func (p *scionPacketProcessor) process() (processResult, error) {
 }
 
 func (d *DataPlane) processOHP(ingressID uint16, rawPkt []byte, s slayers.SCION,
-	buffer gopacket.SerializeBuffer, mac hash.Hash) (processResult, error) {
+	buffer gopacket.SerializeBuffer, mac hash.Hash, ports *scmpPortParser) (processResult, error) {
 
 	p, ok := s.Path.(*onehop.Path)
 	if !ok {
@@ -1289,14 +1418,16 @@ func (d *DataPlane) processOHP(ingressID uint16, rawPkt []byte, s slayers.SCION,
 	if err := updateSCIONLayer(rawPkt, s, buffer); err != nil {
 		return processResult{}, err
 	}
-	a, err := d.resolveLocalDst(s)
+	a, err := d.resolveLocalDst(s, ports)
 	if err != nil {
 		return processResult{}, err
 	}
 	return processResult{OutConn: d.internal, OutAddr: a, OutPkt: rawPkt}, nil
 }
 
-func (d *DataPlane) resolveLocalDst(s slayers.SCION) (net.Addr, error) {
+func (d *DataPlane) resolveLocalDst(s slayers.SCION,
+	ports *scmpPortParser) (net.Addr, error) {
+
 	dst, err := s.DstAddr()
 	if err != nil {
 		// TODO parameter problem.
@@ -1311,14 +1442,150 @@ func (d *DataPlane) resolveLocalDst(s slayers.SCION) (net.Addr, error) {
 		}
 		return a, nil
 	}
-	return addEndhostPort(dst), nil
+	return d.addEndhostPort(s, dst, ports), nil
 }
 
-func addEndhostPort(dst net.Addr) net.Addr {
-	if ip, ok := dst.(*net.IPAddr); ok {
-		return &net.UDPAddr{IP: ip.IP, Port: topology.EndhostPort}
+func (d *DataPlane) addEndhostPort(s slayers.SCION, dst net.Addr,
+	ports *scmpPortParser) net.Addr {
+
+	ip, ok := dst.(*net.IPAddr)
+	if !ok {
+		return dst
 	}
-	return dst
+	port := topology.EndhostPort
+	if p, ok := d.dispatchedPort(s, ports); ok {
+		port = int(p)
+	}
+	return &net.UDPAddr{IP: ip.IP, Port: port}
+}
+
+// dispatchedPort returns the port of the end host application the packet is
+// destined to, if the port is in the range of ports that are delivered to
+// directly.
+func (d *DataPlane) dispatchedPort(s slayers.SCION, ports *scmpPortParser) (uint16, bool) {
+	if d.dispatchedPortStart == 0 {
+		return 0, false
+	}
+	var port uint16
+	switch s.NextHdr {
+	case common.L4UDP:
+		if len(s.Payload) < 8 {
+			return 0, false
+		}
+		port = binary.BigEndian.Uint16(s.Payload[2:4])
+	case common.L4SCMP:
+		var ok bool
+		if port, ok = ports.dstPort(s.Payload); !ok {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+	if port < d.dispatchedPortStart || port > d.dispatchedPortEnd {
+		return 0, false
+	}
+	return port, true
+}
+
+// scmpPortParser extracts the port of the application an SCMP message is
+// destined to. The layers and the parser are allocated once and reused for
+// every packet, so that no allocations are made on the forwarding path.
+type scmpPortParser struct {
+	scmp         slayers.SCMP
+	echo         slayers.SCMPEcho
+	traceroute   slayers.SCMPTraceroute
+	destUnreach  slayers.SCMPDestinationUnreachable
+	paramProblem slayers.SCMPParameterProblem
+	extIfDown    slayers.SCMPExternalInterfaceDown
+	intConnDown  slayers.SCMPInternalConnectivityDown
+
+	quoted       slayers.SCION
+	quotedUDP    slayers.UDP
+	quotedHBH    slayers.HopByHopExtn
+	quotedE2E    slayers.EndToEndExtn
+	quotedSCMP   slayers.SCMP
+	quotedParser *gopacket.DecodingLayerParser
+	decoded      []gopacket.LayerType
+}
+
+func newSCMPPortParser() *scmpPortParser {
+	p := &scmpPortParser{decoded: make([]gopacket.LayerType, 0, 5)}
+	p.quotedParser = gopacket.NewDecodingLayerParser(slayers.LayerTypeSCION,
+		&p.quoted, &p.quotedUDP, &p.quotedHBH, &p.quotedE2E, &p.quotedSCMP)
+	p.quotedParser.IgnoreUnsupported = true
+	return p
+}
+
+// dstPort returns the port of the application an SCMP message is destined to.
+// For echo and traceroute replies, the identifier is used. For error
+// messages, the source port, respectively the identifier, of the quoted packet
+// is used. SCMP requests are delivered to the dispatcher, which answers them.
+func (p *scmpPortParser) dstPort(raw []byte) (uint16, bool) {
+	if err := p.scmp.DecodeFromBytes(raw, gopacket.NilDecodeFeedback); err != nil {
+		return 0, false
+	}
+	if p.scmp.TypeCode.InfoMsg() {
+		switch p.scmp.TypeCode.Type() {
+		case slayers.SCMPTypeEchoReply:
+			return p.echoID(&p.echo, p.scmp.Payload)
+		case slayers.SCMPTypeTracerouteReply:
+			return p.tracerouteID(&p.traceroute, p.scmp.Payload)
+		}
+		return 0, false
+	}
+	// The quoted packet follows the type specific part of the SCMP error
+	// message.
+	var msg interface {
+		DecodeFromBytes(data []byte, df gopacket.DecodeFeedback) error
+		LayerPayload() []byte
+	}
+	switch p.scmp.TypeCode.Type() {
+	case slayers.SCMPTypeDestinationUnreachable:
+		msg = &p.destUnreach
+	case slayers.SCMPTypeParameterProblem:
+		msg = &p.paramProblem
+	case slayers.SCMPTypeExternalInterfaceDown:
+		msg = &p.extIfDown
+	case slayers.SCMPTypeInternalConnectivityDown:
+		msg = &p.intConnDown
+	default:
+		return 0, false
+	}
+	if err := msg.DecodeFromBytes(p.scmp.Payload, gopacket.NilDecodeFeedback); err != nil {
+		return 0, false
+	}
+	// Quoted packets are usually truncated, thus, decoding errors are expected.
+	// The layers that were decoded before the error are still used.
+	p.quotedParser.DecodeLayers(msg.LayerPayload(), &p.decoded)
+	if len(p.decoded) == 0 {
+		return 0, false
+	}
+	switch p.decoded[len(p.decoded)-1] {
+	case slayers.LayerTypeSCIONUDP:
+		return uint16(p.quotedUDP.SrcPort), p.quotedUDP.SrcPort != 0
+	case slayers.LayerTypeSCMP:
+		switch p.quotedSCMP.TypeCode.Type() {
+		case slayers.SCMPTypeEchoRequest, slayers.SCMPTypeEchoReply:
+			return p.echoID(&p.echo, p.quotedSCMP.Payload)
+		case slayers.SCMPTypeTracerouteRequest, slayers.SCMPTypeTracerouteReply:
+			return p.tracerouteID(&p.traceroute, p.quotedSCMP.Payload)
+		}
+	}
+	return 0, false
+}
+
+func (p *scmpPortParser) echoID(echo *slayers.SCMPEcho, raw []byte) (uint16, bool) {
+	if err := echo.DecodeFromBytes(raw, gopacket.NilDecodeFeedback); err != nil {
+		return 0, false
+	}
+	return echo.Identifier, true
+}
+
+func (p *scmpPortParser) tracerouteID(tr *slayers.SCMPTraceroute, raw []byte) (uint16, bool) {
+	if err := tr.DecodeFromBytes(raw, gopacket.NilDecodeFeedback); err != nil {
+		return 0, false
+	}
+	return tr.Identifier, true
 }
 
 func updateSCIONLayer(rawPkt []byte, s slayers.SCION, buffer gopacket.SerializeBuffer) error {
@@ -1536,6 +1803,9 @@ type forwardingMetrics struct {
 	InputPacketsTotal   prometheus.Counter
 	OutputPacketsTotal  prometheus.Counter
 	DroppedPacketsTotal prometheus.Counter
+	// RateLimitedPacketsTotal contains the counters of packets dropped because
+	// of rate limits, keyed by the reason.
+	RateLimitedPacketsTotal map[string]prometheus.Counter
 }
 
 func initForwardingMetrics(metrics *Metrics, labels prometheus.Labels) forwardingMetrics {
@@ -1551,6 +1821,15 @@ func initForwardingMetrics(metrics *Metrics, labels prometheus.Labels) forwardin
 	c.OutputBytesTotal.Add(0)
 	c.OutputPacketsTotal.Add(0)
 	c.DroppedPacketsTotal.Add(0)
+	c.RateLimitedPacketsTotal = make(map[string]prometheus.Counter)
+	for _, reason := range []string{rateLimitSCMP, rateLimitInterface, rateLimitSource} {
+		reasonLabels := prometheus.Labels{"reason": reason}
+		for k, v := range labels {
+			reasonLabels[k] = v
+		}
+		c.RateLimitedPacketsTotal[reason] = metrics.RateLimitedPacketsTotal.With(reasonLabels)
+		c.RateLimitedPacketsTotal[reason].Add(0)
+	}
 	return c
 }
 
@@ -46,6 +46,7 @@ import (
 	"github.com/scionproto/scion/go/lib/util"
 	"github.com/scionproto/scion/go/lib/xtest"
 	"github.com/scionproto/scion/go/pkg/router"
+	"github.com/scionproto/scion/go/pkg/router/config"
 	"github.com/scionproto/scion/go/pkg/router/control"
 	"github.com/scionproto/scion/go/pkg/router/mock_router"
 )
@@ -1158,6 +1159,150 @@ func TestProcessPkt(t *testing.T) {
 	}
 }
 
+func TestProcessPktRateLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	key := []byte("testkey_xxxxxxxx")
+	local := xtest.MustParseIA("1-ff00:0:110")
+	now := time.Now()
+	// inbound creates a packet received on interface 1 that is delivered in
+	// the local AS.
+	inbound := func(src addr.IA) *ipv4.Message {
+		spkt, dpath := prepBaseMsg(now)
+		spkt.SrcIA = src
+		spkt.DstIA = local
+		_ = spkt.SetDstAddr(&net.IPAddr{IP: net.ParseIP("10.0.100.100").To4()})
+		dpath.HopFields = []*path.HopField{
+			{ConsIngress: 41, ConsEgress: 40},
+			{ConsIngress: 31, ConsEgress: 30},
+			{ConsIngress: 1, ConsEgress: 0},
+		}
+		dpath.Base.PathMeta.CurrHF = 2
+		dpath.HopFields[2].Mac = computeMAC(t, key, dpath.InfoFields[0], dpath.HopFields[2])
+		return toMsg(t, spkt, dpath)
+	}
+	// invalid creates a packet with an unknown egress interface that is
+	// answered with an SCMP error.
+	invalid := func(srcHost string) *ipv4.Message {
+		spkt, dpath := prepBaseMsg(now)
+		_ = spkt.SetSrcAddr(&net.IPAddr{IP: net.ParseIP(srcHost).To4()})
+		spkt.DstIA = xtest.MustParseIA("1-ff00:0:f1")
+		dpath.HopFields = []*path.HopField{
+			{ConsIngress: 41, ConsEgress: 40},
+			{ConsIngress: 31, ConsEgress: 404},
+			{ConsIngress: 1, ConsEgress: 0},
+		}
+		dpath.Base.PathMeta.CurrHF = 1
+		dpath.HopFields[1].Mac = computeMAC(t, key, dpath.InfoFields[0], dpath.HopFields[1])
+		return toMsg(t, spkt, dpath)
+	}
+	process := func(dp *router.DataPlane, msg *ipv4.Message) error {
+		origMsg := make([]byte, len(msg.Buffers[0]))
+		copy(origMsg, msg.Buffers[0])
+		_, err := dp.ProcessPkt(1, msg, slayers.SCION{}, origMsg,
+			gopacket.NewSerializeBuffer())
+		return err
+	}
+	newDP := func(t *testing.T, cfg config.RateLimit) *router.DataPlane {
+		dp := router.NewDP(
+			map[uint16]router.BatchConn{1: mock_router.NewMockBatchConn(ctrl)},
+			map[uint16]topology.LinkType{1: topology.Child},
+			mock_router.NewMockBatchConn(ctrl), nil, nil, local, key)
+		cfg.InitDefaults()
+		require.NoError(t, cfg.Validate())
+		require.NoError(t, dp.SetRateLimits(cfg))
+		return dp
+	}
+
+	t.Run("interface", func(t *testing.T) {
+		// The rate of 8bps is negligible, only the burst is available.
+		dp := newDP(t, config.RateLimit{
+			Ingress: config.IngressRateLimit{
+				Interfaces: map[string]config.Limit{"1": {Rate: 8}},
+			},
+		})
+		size := len(inbound(local).Buffers[0])
+		for i := 0; i < config.MinIngressBurst/size; i++ {
+			require.NoError(t, process(dp, inbound(local)), i)
+		}
+		assert.Equal(t, "interface", router.RateLimitReason(process(dp, inbound(local))))
+	})
+	t.Run("source isd as", func(t *testing.T) {
+		limited := xtest.MustParseIA("1-ff00:0:111")
+		dp := newDP(t, config.RateLimit{
+			Ingress: config.IngressRateLimit{
+				SourceISDASes: map[string]config.Limit{limited.String(): {Rate: 8}},
+			},
+		})
+		size := len(inbound(limited).Buffers[0])
+		for i := 0; i < config.MinIngressBurst/size; i++ {
+			require.NoError(t, process(dp, inbound(limited)), i)
+		}
+		assert.Equal(t, "source_isd_as", router.RateLimitReason(process(dp, inbound(limited))))
+		assert.NoError(t, process(dp, inbound(xtest.MustParseIA("1-ff00:0:112"))))
+	})
+	t.Run("default source isd as", func(t *testing.T) {
+		listed := xtest.MustParseIA("1-ff00:0:111")
+		dp := newDP(t, config.RateLimit{
+			Ingress: config.IngressRateLimit{
+				SourceISDASes:      map[string]config.Limit{listed.String(): {Rate: 1e9}},
+				DefaultSourceISDAS: config.Limit{Rate: 8},
+			},
+		})
+		// Every source that is not listed has its own bucket.
+		for _, src := range []string{"1-ff00:0:112", "1-ff00:0:113"} {
+			ia := xtest.MustParseIA(src)
+			size := len(inbound(ia).Buffers[0])
+			for i := 0; i < config.MinIngressBurst/size; i++ {
+				require.NoError(t, process(dp, inbound(ia)), i)
+			}
+			assert.Equal(t, "source_isd_as", router.RateLimitReason(process(dp, inbound(ia))))
+		}
+		// Listed sources are only limited by their own limit.
+		size := len(inbound(listed).Buffers[0])
+		for i := 0; i <= config.MinIngressBurst/size; i++ {
+			require.NoError(t, process(dp, inbound(listed)), i)
+		}
+	})
+	t.Run("scmp", func(t *testing.T) {
+		dp := newDP(t, config.RateLimit{
+			SCMP: config.SCMPRateLimit{Rate: 1, Burst: 2},
+		})
+		for i := 0; i < 2; i++ {
+			err := process(dp, invalid("10.0.200.200"))
+			assert.Error(t, err)
+			assert.Empty(t, router.RateLimitReason(err))
+		}
+		err := process(dp, invalid("10.0.200.201"))
+		assert.Equal(t, "scmp", router.RateLimitReason(err))
+	})
+	t.Run("scmp per destination", func(t *testing.T) {
+		dp := newDP(t, config.RateLimit{
+			SCMP: config.SCMPRateLimit{PerDestinationRate: 1},
+		})
+		err := process(dp, invalid("10.0.200.200"))
+		assert.Empty(t, router.RateLimitReason(err))
+		err = process(dp, invalid("10.0.200.200"))
+		assert.Equal(t, "scmp", router.RateLimitReason(err))
+		err = process(dp, invalid("10.0.200.201"))
+		assert.Empty(t, router.RateLimitReason(err))
+	})
+	t.Run("scmp global limit does not consume destination tokens", func(t *testing.T) {
+		dp := newDP(t, config.RateLimit{
+			SCMP: config.SCMPRateLimit{Rate: 10, Burst: 1, PerDestinationRate: 1},
+		})
+		err := process(dp, invalid("10.0.200.200"))
+		assert.Empty(t, router.RateLimitReason(err))
+		// The global limit is exhausted, the destination keeps its token.
+		err = process(dp, invalid("10.0.200.201"))
+		assert.Equal(t, "scmp", router.RateLimitReason(err))
+		time.Sleep(150 * time.Millisecond)
+		err = process(dp, invalid("10.0.200.201"))
+		assert.Empty(t, router.RateLimitReason(err))
+	})
+}
+
 func toMsg(t *testing.T, spkt *slayers.SCION, dpath path.Path) *ipv4.Message {
 	t.Helper()
 	ret := &ipv4.Message{}
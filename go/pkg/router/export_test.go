@@ -15,7 +15,9 @@
 package router
 
 import (
+	"errors"
 	"net"
+	"time"
 
 	"github.com/google/gopacket"
 	"golang.org/x/net/ipv4"
@@ -59,10 +61,38 @@ func (d *DataPlane) FakeStart() {
 func (d *DataPlane) ProcessPkt(ifID uint16, m *ipv4.Message, s slayers.SCION,
 	origPacket []byte, b gopacket.SerializeBuffer) (ProcessResult, error) {
 
-	result, err := d.processPkt(ifID, m.Buffers[0], m.Addr, s, origPacket, b, d.macFactory())
+	result, err := d.processPkt(ifID, m.Buffers[0], m.Addr, s, origPacket, b, d.macFactory(),
+		newSCMPPortParser())
 	return ProcessResult{processResult: result}, err
 }
 
+// RateLimitReason returns the reason if the error indicates that a packet was
+// dropped because of a rate limit, and the empty string otherwise.
+func RateLimitReason(err error) string {
+	var rateLimitErr rateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return rateLimitErr.Reason
+	}
+	return ""
+}
+
 func ExtractServices(s *services) map[addr.HostSVC][]*net.UDPAddr {
 	return s.m
 }
+
+// BucketSet exposes the per-source rate limiters for testing.
+type BucketSet struct {
+	s *bucketSet
+}
+
+func NewBucketSet(rate, burst float64, max int) BucketSet {
+	return BucketSet{s: newBucketSet(rate, burst, max)}
+}
+
+func (s BucketSet) Allow(now time.Time, src addr.IA, n float64) bool {
+	return s.s.allow(now, bucketKey{ia: src}, n)
+}
+
+func (s BucketSet) Len() int {
+	return s.s.len()
+}
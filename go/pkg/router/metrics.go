@@ -26,10 +26,12 @@ type Metrics struct {
 	InputPacketsTotal         *prometheus.CounterVec
 	OutputPacketsTotal        *prometheus.CounterVec
 	DroppedPacketsTotal       *prometheus.CounterVec
+	RateLimitedPacketsTotal   *prometheus.CounterVec
 	InterfaceUp               *prometheus.GaugeVec
 	BFDInterfaceStateChanges  *prometheus.CounterVec
 	BFDPacketsSent            *prometheus.CounterVec
 	BFDPacketsReceived        *prometheus.CounterVec
+	BFDRoundTripTime          *prometheus.GaugeVec
 	ServiceInstanceCount      *prometheus.GaugeVec
 	ServiceInstanceChanges    *prometheus.CounterVec
 	SiblingReachable          *prometheus.GaugeVec
@@ -78,6 +80,15 @@ func NewMetrics() *Metrics {
 			},
 			[]string{"interface", "isd_as", "neighbor_isd_as"},
 		),
+		RateLimitedPacketsTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "router_rate_limited_pkts_total",
+				Help: "Total number of packets dropped by the router because of rate limits. " +
+					"For the reason 'scmp', the packets are SCMP messages that were not " +
+					"generated.",
+			},
+			[]string{"interface", "isd_as", "neighbor_isd_as", "reason"},
+		),
 		InterfaceUp: promauto.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name: "router_interface_up",
@@ -106,6 +117,13 @@ func NewMetrics() *Metrics {
 			},
 			[]string{"interface", "isd_as", "neighbor_isd_as"},
 		),
+		BFDRoundTripTime: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "router_bfd_rtt_seconds",
+				Help: "Most recently measured round-trip time of the external link in seconds.",
+			},
+			[]string{"interface", "isd_as", "neighbor_isd_as"},
+		),
 		ServiceInstanceCount: promauto.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name: "router_service_instance_count",
@@ -0,0 +1,254 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/pkg/router/config"
+)
+
+const (
+	// maxSCMPDestinations is the maximum number of destinations for which SCMP
+	// rate limiters are kept.
+	maxSCMPDestinations = 10000
+	// maxSources is the maximum number of source ISD-ASes for which the
+	// default ingress rate limiters are kept.
+	maxSources = 10000
+)
+
+// Reasons for dropping packets due to rate limits.
+const (
+	rateLimitSCMP      = "scmp"
+	rateLimitInterface = "interface"
+	rateLimitSource    = "source_isd_as"
+)
+
+// tokenBucket is a token bucket rate limiter. It is safe for concurrent use.
+type tokenBucket struct {
+	// rate is the number of tokens added per second.
+	rate float64
+	// burst is the maximum number of tokens in the bucket.
+	burst float64
+
+	mtx    sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate, burst float64, now time.Time) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: burst, tokens: burst, last: now}
+}
+
+// allow takes n tokens from the bucket. It returns false if there are not
+// enough tokens.
+func (b *tokenBucket) allow(now time.Time, n float64) bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.take(now, n)
+}
+
+func (b *tokenBucket) take(now time.Time, n float64) bool {
+	b.refill(now)
+	if b.tokens < n {
+		return false
+	}
+	b.tokens -= n
+	return true
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+}
+
+// refund puts n tokens back into the bucket, e.g., if a message was not sent
+// after all.
+func (b *tokenBucket) refund(n float64) {
+	b.tokens += n
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+// bucketKey is the key of a bucket in a bucketSet. The SCMP limits use the
+// destination host, the ingress limits the source ISD-AS.
+type bucketKey struct {
+	host string
+	ia   addr.IA
+}
+
+// bucketSet is a set of token buckets with the same parameters, indexed by a
+// key. The number of buckets is bounded, the least recently used bucket is
+// evicted when the bound is reached. Thus, buckets of keys that are actively
+// limited are kept, even if many new keys show up. It is safe for concurrent
+// use.
+type bucketSet struct {
+	rate  float64
+	burst float64
+	max   int
+
+	mtx     sync.Mutex
+	buckets map[bucketKey]*list.Element
+	// order contains the buckets, the most recently used bucket first.
+	order *list.List
+}
+
+type bucketEntry struct {
+	key    bucketKey
+	bucket *tokenBucket
+}
+
+func newBucketSet(rate, burst float64, max int) *bucketSet {
+	return &bucketSet{
+		rate:    rate,
+		burst:   burst,
+		max:     max,
+		buckets: make(map[bucketKey]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// allow takes n tokens from the bucket of the key. It returns false if there
+// are not enough tokens.
+func (s *bucketSet) allow(now time.Time, key bucketKey, n float64) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	elem, ok := s.buckets[key]
+	if ok {
+		s.order.MoveToFront(elem)
+	} else {
+		if s.order.Len() >= s.max {
+			oldest := s.order.Back()
+			s.order.Remove(oldest)
+			delete(s.buckets, oldest.Value.(*bucketEntry).key)
+		}
+		elem = s.order.PushFront(&bucketEntry{
+			key:    key,
+			bucket: newTokenBucket(s.rate, s.burst, now),
+		})
+		s.buckets[key] = elem
+	}
+	return elem.Value.(*bucketEntry).bucket.take(now, n)
+}
+
+// refund puts n tokens back into the bucket of the key.
+func (s *bucketSet) refund(key bucketKey, n float64) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if elem, ok := s.buckets[key]; ok {
+		elem.Value.(*bucketEntry).bucket.refund(n)
+	}
+}
+
+// len returns the number of buckets in the set.
+func (s *bucketSet) len() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.order.Len()
+}
+
+// rateLimits contains the rate limiters of the data plane. Limits that are not
+// configured are nil.
+type rateLimits struct {
+	scmp           *tokenBucket
+	scmpPerDst     *bucketSet
+	ingressIntf    map[uint16]*tokenBucket
+	ingressSources map[addr.IA]*tokenBucket
+	// ingressDefault limits the sources that are not in ingressSources.
+	ingressDefault *bucketSet
+}
+
+func newRateLimits(cfg config.RateLimit) (rateLimits, error) {
+	intfLimits, err := cfg.Ingress.InterfaceLimits()
+	if err != nil {
+		return rateLimits{}, err
+	}
+	sourceLimits, err := cfg.Ingress.SourceLimits()
+	if err != nil {
+		return rateLimits{}, err
+	}
+	now := time.Now()
+	var l rateLimits
+	if cfg.SCMP.Rate > 0 {
+		l.scmp = newTokenBucket(float64(cfg.SCMP.Rate), float64(cfg.SCMP.Burst), now)
+	}
+	if cfg.SCMP.PerDestinationRate > 0 {
+		l.scmpPerDst = newBucketSet(float64(cfg.SCMP.PerDestinationRate),
+			float64(cfg.SCMP.PerDestinationBurst), maxSCMPDestinations)
+	}
+	// The ingress buckets count bytes.
+	for ifID, limit := range intfLimits {
+		if l.ingressIntf == nil {
+			l.ingressIntf = make(map[uint16]*tokenBucket)
+		}
+		l.ingressIntf[ifID] = newTokenBucket(float64(limit.Rate)/8, float64(limit.Burst), now)
+	}
+	for ia, limit := range sourceLimits {
+		if l.ingressSources == nil {
+			l.ingressSources = make(map[addr.IA]*tokenBucket)
+		}
+		l.ingressSources[ia] = newTokenBucket(float64(limit.Rate)/8, float64(limit.Burst), now)
+	}
+	if limit := cfg.Ingress.DefaultSourceISDAS; limit.Rate > 0 {
+		l.ingressDefault = newBucketSet(float64(limit.Rate)/8, float64(limit.Burst), maxSources)
+	}
+	return l, nil
+}
+
+// allowSCMP checks whether an SCMP message to the destination may be sent.
+// Tokens are only consumed if the message may be sent, such that messages
+// dropped by the global limit do not count against the destination and vice
+// versa.
+func (l *rateLimits) allowSCMP(now time.Time, dst string) bool {
+	key := bucketKey{host: dst}
+	if l.scmpPerDst != nil && !l.scmpPerDst.allow(now, key, 1) {
+		return false
+	}
+	if l.scmp != nil && !l.scmp.allow(now, 1) {
+		if l.scmpPerDst != nil {
+			l.scmpPerDst.refund(key, 1)
+		}
+		return false
+	}
+	return true
+}
+
+// allowIngress checks whether a packet received on the external interface may
+// be processed. If not, the reason is returned.
+func (l *rateLimits) allowIngress(now time.Time, ifID uint16, src addr.IA,
+	size int) (bool, string) {
+
+	if b, ok := l.ingressIntf[ifID]; ok && !b.allow(now, float64(size)) {
+		return false, rateLimitInterface
+	}
+	if b, ok := l.ingressSources[src]; ok {
+		if !b.allow(now, float64(size)) {
+			return false, rateLimitSource
+		}
+	} else if l.ingressDefault != nil &&
+		!l.ingressDefault.allow(now, bucketKey{ia: src}, float64(size)) {
+		return false, rateLimitSource
+	}
+	return true, ""
+}
@@ -0,0 +1,47 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/pkg/router"
+)
+
+func TestBucketSetEviction(t *testing.T) {
+	now := time.Now()
+	limited := xtest.MustParseIA("1-ff00:0:110")
+	idle := xtest.MustParseIA("1-ff00:0:111")
+
+	s := router.NewBucketSet(1, 1, 3)
+	assert.True(t, s.Allow(now, idle, 1))
+	assert.True(t, s.Allow(now, limited, 1))
+	assert.False(t, s.Allow(now, limited, 1))
+
+	// Spoofed sources must only evict the least recently used bucket, the
+	// exhausted bucket of the limited source is kept as long as it is used.
+	for i := 0; i < 100; i++ {
+		s.Allow(now, addr.IA{I: 2, A: addr.AS(i)}, 1)
+		assert.False(t, s.Allow(now, limited, 1), "iteration %d", i)
+		assert.Equal(t, 3, s.Len())
+	}
+	// The idle source was evicted and starts with a full bucket again.
+	assert.True(t, s.Allow(now, idle, 1))
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/scionproto/scion/go/lib/config"
 	"github.com/scionproto/scion/go/lib/env"
+	"github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/sciond"
 	"github.com/scionproto/scion/go/lib/serrors"
@@ -126,6 +127,8 @@ type SDConfig struct {
 	// If HiddenPathGroups begins with http:// or https://, it will be fetched
 	// over the network from the specified URL instead.
 	HiddenPathGroups string `toml:"hidden_path_groups,omitempty"`
+	// Prefetch contains the configuration of the segment prefetcher.
+	Prefetch segfetcher.PrefetchConfig `toml:"prefetch,omitempty"`
 }
 
 func (cfg *SDConfig) InitDefaults() {
@@ -135,17 +138,19 @@ func (cfg *SDConfig) InitDefaults() {
 	if cfg.QueryInterval.Duration == 0 {
 		cfg.QueryInterval.Duration = DefaultQueryInterval
 	}
+	config.InitAll(&cfg.Prefetch)
 }
 
 func (cfg *SDConfig) Validate() error {
 	if cfg.QueryInterval.Duration == 0 {
 		return serrors.New("QueryInterval must not be zero")
 	}
-	return nil
+	return config.ValidateAll(&cfg.Prefetch)
 }
 
 func (cfg *SDConfig) Sample(dst io.Writer, path config.Path, ctx config.CtxMap) {
 	config.WriteString(dst, sdSample)
+	config.WriteSample(dst, path, ctx, &cfg.Prefetch)
 }
 
 func (cfg *SDConfig) ConfigName() string {
@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/scionproto/scion/go/lib/env/envtest"
+	"github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
 	"github.com/scionproto/scion/go/lib/log/logtest"
 	"github.com/scionproto/scion/go/lib/sciond"
 	storagetest "github.com/scionproto/scion/go/pkg/storage/test"
@@ -61,4 +62,9 @@ func CheckTestSDConfig(t *testing.T, cfg *SDConfig, id string) {
 	assert.Equal(t, sciond.DefaultAPIAddress, cfg.Address)
 	assert.False(t, cfg.DisableSegVerification)
 	assert.Equal(t, DefaultQueryInterval, cfg.QueryInterval.Duration)
+	assert.False(t, cfg.Prefetch.Enabled)
+	assert.Equal(t, segfetcher.DefaultPrefetchInterval, cfg.Prefetch.Interval.Duration)
+	assert.Equal(t, segfetcher.DefaultPrefetchLeadTime, cfg.Prefetch.LeadTime.Duration)
+	assert.Equal(t, segfetcher.DefaultPrefetchTopN, *cfg.Prefetch.TopN)
+	assert.Empty(t, cfg.Prefetch.Destinations)
 }
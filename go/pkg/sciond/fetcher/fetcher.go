@@ -44,11 +44,17 @@ type TrustStore interface {
 
 type Fetcher interface {
 	GetPaths(ctx context.Context, src, dst addr.IA, refresh bool) ([]snet.Path, error)
+	// ExplainPaths describes how the paths from src to dst are constructed,
+	// including the candidate paths that GetPaths filters.
+	ExplainPaths(ctx context.Context, src, dst addr.IA,
+		refresh bool) (*segfetcher.Explanation, error)
+	segfetcher.DstPrefetcher
 }
 
 type fetcher struct {
-	pather segfetcher.Pather
-	config config.SDConfig
+	pather     segfetcher.Pather
+	config     config.SDConfig
+	prefetcher *segfetcher.Prefetcher
 }
 
 type FetcherConfig struct {
@@ -61,6 +67,9 @@ type FetcherConfig struct {
 	Cfg      config.SDConfig
 
 	TopoProvider topology.Provider
+	// Prefetcher records the destinations of the path lookups. If it is nil,
+	// the destinations are not recorded.
+	Prefetcher *segfetcher.Prefetcher
 }
 
 func NewFetcher(cfg FetcherConfig) Fetcher {
@@ -95,7 +104,8 @@ func NewFetcher(cfg FetcherConfig) Fetcher {
 				Inspector: cfg.Inspector,
 			},
 		},
-		config: cfg.Cfg,
+		config:     cfg.Cfg,
+		prefetcher: cfg.Prefetcher,
 	}
 }
 
@@ -113,9 +123,33 @@ func (f *fetcher) GetPaths(ctx context.Context, src, dst addr.IA,
 	if !src.IsZero() && !src.Equal(local) {
 		return nil, serrors.New("bad source AS", "src", src)
 	}
+	f.prefetcher.Record(dst)
 	return f.pather.GetPaths(ctx, dst, refresh)
 }
 
+// ExplainPaths uses the pather to explain the path construction from src to
+// dst. src may be either zero or the local IA (nothing else).
+func (f *fetcher) ExplainPaths(ctx context.Context, src, dst addr.IA,
+	refresh bool) (*segfetcher.Explanation, error) {
+
+	if _, ok := ctx.Deadline(); !ok {
+		return nil, serrors.New("context must have deadline set")
+	}
+	local := f.pather.TopoProvider.Get().IA()
+	if !src.IsZero() && !src.Equal(local) {
+		return nil, serrors.New("bad source AS", "src", src)
+	}
+	return f.pather.Explain(ctx, dst, refresh)
+}
+
+// Prefetch refreshes the segments that are required to build paths to dst, if
+// their next query time is within the lead time.
+func (f *fetcher) Prefetch(ctx context.Context, dst addr.IA,
+	leadTime time.Duration) (int, error) {
+
+	return f.pather.Prefetch(ctx, dst, leadTime)
+}
+
 type dstProvider struct {
 }
 
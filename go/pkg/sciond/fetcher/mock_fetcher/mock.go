@@ -8,8 +8,10 @@ import (
 	context "context"
 	gomock "github.com/golang/mock/gomock"
 	addr "github.com/scionproto/scion/go/lib/addr"
+	segfetcher "github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
 	snet "github.com/scionproto/scion/go/lib/snet"
 	reflect "reflect"
+	time "time"
 )
 
 // MockFetcher is a mock of Fetcher interface
@@ -35,6 +37,21 @@ func (m *MockFetcher) EXPECT() *MockFetcherMockRecorder {
 	return m.recorder
 }
 
+// ExplainPaths mocks base method
+func (m *MockFetcher) ExplainPaths(arg0 context.Context, arg1, arg2 addr.IA, arg3 bool) (*segfetcher.Explanation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExplainPaths", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*segfetcher.Explanation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExplainPaths indicates an expected call of ExplainPaths
+func (mr *MockFetcherMockRecorder) ExplainPaths(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExplainPaths", reflect.TypeOf((*MockFetcher)(nil).ExplainPaths), arg0, arg1, arg2, arg3)
+}
+
 // GetPaths mocks base method
 func (m *MockFetcher) GetPaths(arg0 context.Context, arg1, arg2 addr.IA, arg3 bool) ([]snet.Path, error) {
 	m.ctrl.T.Helper()
@@ -49,3 +66,18 @@ func (mr *MockFetcherMockRecorder) GetPaths(arg0, arg1, arg2, arg3 interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaths", reflect.TypeOf((*MockFetcher)(nil).GetPaths), arg0, arg1, arg2, arg3)
 }
+
+// Prefetch mocks base method
+func (m *MockFetcher) Prefetch(arg0 context.Context, arg1 addr.IA, arg2 time.Duration) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prefetch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prefetch indicates an expected call of Prefetch
+func (mr *MockFetcherMockRecorder) Prefetch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prefetch", reflect.TypeOf((*MockFetcher)(nil).Prefetch), arg0, arg1, arg2)
+}
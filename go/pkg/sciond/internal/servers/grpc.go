@@ -29,15 +29,19 @@ import (
 	"github.com/scionproto/scion/go/lib/common"
 	ctrl_drkey "github.com/scionproto/scion/go/lib/ctrl/drkey"
 	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
 	"github.com/scionproto/scion/go/lib/drkey"
 	"github.com/scionproto/scion/go/lib/drkeystorage"
+	"github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/prom"
 	"github.com/scionproto/scion/go/lib/revcache"
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/snet/path"
 	"github.com/scionproto/scion/go/lib/topology"
 	"github.com/scionproto/scion/go/lib/util"
+	cppb "github.com/scionproto/scion/go/pkg/proto/control_plane"
 	sdpb "github.com/scionproto/scion/go/pkg/proto/daemon"
 	"github.com/scionproto/scion/go/pkg/sciond/fetcher"
 	"github.com/scionproto/scion/go/pkg/trust"
@@ -217,9 +221,11 @@ func (s *DaemonServer) as(ctx context.Context, req *sdpb.ASRequest) (*sdpb.ASRes
 	if reqIA.IsZero() {
 		reqIA = topo.IA()
 	}
-	mtu := uint32(0)
+	var mtu, portStart, portEnd uint32
 	if reqIA.Equal(topo.IA()) {
 		mtu = uint32(topo.MTU())
+		start, end := topo.PortRange()
+		portStart, portEnd = uint32(start), uint32(end)
 	}
 	core, err := s.ASInspector.HasAttributes(ctx, reqIA, trust.Core)
 	if err != nil {
@@ -230,6 +236,9 @@ func (s *DaemonServer) as(ctx context.Context, req *sdpb.ASRequest) (*sdpb.ASRes
 		IsdAs: uint64(reqIA.IAInt()),
 		Core:  core,
 		Mtu:   mtu,
+
+		DispatchedPortStart: portStart,
+		DispatchedPortEnd:   portEnd,
 	}
 	return reply, nil
 }
@@ -386,3 +395,65 @@ func keyToLvl2Resp(drkey drkey.Lvl2Key) (*sdpb.DRKeyLvl2Response, error) {
 		BaseRep: baseRep,
 	}, nil
 }
+
+// ExplainPaths serves the explain paths request.
+func (s *DaemonServer) ExplainPaths(ctx context.Context,
+	req *sdpb.ExplainPathsRequest) (*sdpb.ExplainPathsResponse, error) {
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancelF context.CancelFunc
+		ctx, cancelF = context.WithTimeout(ctx, 10*time.Second)
+		defer cancelF()
+	}
+	srcIA, dstIA := addr.IAInt(req.SourceIsdAs).IA(), addr.IAInt(req.DestinationIsdAs).IA()
+	explanation, err := s.Fetcher.ExplainPaths(ctx, srcIA, dstIA, req.Refresh)
+	if err != nil {
+		log.FromCtx(ctx).Debug("Explaining paths", "err", err,
+			"src", srcIA, "dst", dstIA, "refresh", req.Refresh)
+		return nil, err
+	}
+	return explanationToPB(explanation), nil
+}
+
+func explanationToPB(e *segfetcher.Explanation) *sdpb.ExplainPathsResponse {
+	reply := &sdpb.ExplainPathsResponse{}
+	if e.FetchErr != nil {
+		reply.FetchError = e.FetchErr.Error()
+	}
+	for _, s := range e.Segments {
+		reply.Segments = append(reply.Segments, &sdpb.ExplainedSegment{
+			Type:    cppb.SegmentType(s.Type),
+			Segment: seg.PathSegmentToPB(s.Segment),
+		})
+	}
+	for _, c := range e.Candidates {
+		segments := make([]*sdpb.CandidateSegment, 0, len(c.Segments))
+		for _, s := range c.Segments {
+			segments = append(segments, &sdpb.CandidateSegment{
+				Id:       s.ID,
+				Type:     cppb.SegmentType(s.Type),
+				Shortcut: uint32(s.Shortcut),
+				Peer:     uint32(s.Peer),
+			})
+		}
+		revoked := make([]*sdpb.PathInterface, 0, len(c.Revoked))
+		for _, intf := range c.Revoked {
+			revoked = append(revoked, &sdpb.PathInterface{
+				Id:    uint64(intf.ID),
+				IsdAs: uint64(intf.IA.IAInt()),
+			})
+		}
+		reply.Candidates = append(reply.Candidates, &sdpb.PathCandidate{
+			DestinationIsdAs: uint64(c.Dst.IAInt()),
+			Segments:         segments,
+			Path: pathToPB(path.Path{
+				Dst:   c.Dst,
+				SPath: c.Path.SPath,
+				Meta:  c.Path.Metadata,
+			}),
+			FilterReason: string(c.Filtered),
+			Revoked:      revoked,
+		})
+	}
+	return reply
+}
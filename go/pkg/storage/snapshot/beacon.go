@@ -0,0 +1,199 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/scionproto/scion/go/cs/beacon"
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/path_mgmt"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+// maxBeacons is the maximum number of beacons per source AS that can be
+// exported.
+const maxBeacons = 1 << 16
+
+// usages are the beacon usages that are exported.
+var usages = []beacon.Usage{
+	beacon.UsageUpReg,
+	beacon.UsageDownReg,
+	beacon.UsageCoreReg,
+	beacon.UsageProp,
+}
+
+// ExportBeacons writes a snapshot of all beacons and revocations in the beacon
+// database to w. Beacons that are revoked, or that are not allowed for any
+// usage, are not exported.
+func ExportBeacons(ctx context.Context, db beacon.DBRead, w io.Writer) (Stats, error) {
+	enc, err := newEncoder(w, DBBeacon)
+	if err != nil {
+		return Stats{}, err
+	}
+	stats := newStats(DBBeacon)
+	srcs, err := db.BeaconSources(ctx)
+	if err != nil {
+		return stats, serrors.WrapStr("reading beacon sources", err)
+	}
+	for _, src := range srcs {
+		recs, err := beaconRecords(ctx, db, src)
+		if err != nil {
+			return stats, serrors.WithCtx(err, "src", src)
+		}
+		for _, rec := range recs {
+			if err := enc.Encode(rec); err != nil {
+				return stats, serrors.WrapStr("writing beacon", err)
+			}
+			stats.add(rec)
+		}
+	}
+	revs, err := db.AllRevocations(ctx)
+	if err != nil {
+		return stats, serrors.WrapStr("reading revocations", err)
+	}
+	var errs serrors.List
+	for res := range revs {
+		// The channel must be drained, hence errors are only collected.
+		if res.Err != nil {
+			errs = append(errs, res.Err)
+			continue
+		}
+		if len(errs) != 0 {
+			continue
+		}
+		raw, err := res.Rev.Pack()
+		if err != nil {
+			errs = append(errs, serrors.WrapStr("packing revocation", err))
+			continue
+		}
+		rec := Record{Type: TypeRevocation, Revocation: raw}
+		if err := enc.Encode(rec); err != nil {
+			errs = append(errs, serrors.WrapStr("writing revocation", err))
+			continue
+		}
+		stats.add(rec)
+	}
+	if err := errs.ToError(); err != nil {
+		return stats, err
+	}
+	return stats, nil
+}
+
+// beaconRecords returns the records for all beacons that originate from src.
+// The database can only be queried by usage, thus the usages of a beacon are
+// collected from all queries.
+func beaconRecords(ctx context.Context, db beacon.DBRead, src addr.IA) ([]Record, error) {
+	type key struct {
+		id     string
+		inIfID common.IFIDType
+	}
+	var order []key
+	beacons := make(map[key]beacon.Beacon)
+	beaconUsages := make(map[key]beacon.Usage)
+	for _, usage := range usages {
+		results, err := db.CandidateBeacons(ctx, maxBeacons, usage, src)
+		if err != nil {
+			return nil, serrors.WrapStr("reading beacons", err, "usage", usage)
+		}
+		var errs serrors.List
+		count := 0
+		for res := range results {
+			// The channel must be drained, hence errors are only collected.
+			if res.Err != nil {
+				errs = append(errs, res.Err)
+				continue
+			}
+			count++
+			k := key{id: string(res.Beacon.Segment.FullID()), inIfID: res.Beacon.InIfId}
+			if _, ok := beacons[k]; !ok {
+				order = append(order, k)
+				beacons[k] = res.Beacon
+			}
+			beaconUsages[k] |= usage
+		}
+		if err := errs.ToError(); err != nil {
+			return nil, serrors.WrapStr("reading beacons", err, "usage", usage)
+		}
+		if count >= maxBeacons {
+			return nil, serrors.New("too many beacons", "usage", usage, "max", maxBeacons)
+		}
+	}
+	recs := make([]Record, 0, len(order))
+	for _, k := range order {
+		b := beacons[k]
+		raw, err := beacon.PackBeacon(b.Segment)
+		if err != nil {
+			return nil, serrors.WrapStr("encoding beacon", err, "beacon", b)
+		}
+		recs = append(recs, Record{
+			Type:             TypeBeacon,
+			Segment:          raw,
+			IngressInterface: uint64(b.InIfId),
+			Usage:            int(beaconUsages[k]),
+		})
+	}
+	return recs, nil
+}
+
+// ImportBeacons reads a snapshot of a beacon database from r and inserts the
+// beacons and revocations into the beacon database. If verifier is not nil,
+// beacons whose signatures cannot be verified are rejected. Otherwise, the
+// beacons are inserted without verification. Revocations are inserted as is.
+func ImportBeacons(ctx context.Context, db beacon.DBWrite, verifier seg.Verifier,
+	r io.Reader) (Stats, error) {
+
+	dec := json.NewDecoder(r)
+	if _, err := readHeader(dec, DBBeacon); err != nil {
+		return Stats{}, err
+	}
+	stats := newStats(DBBeacon)
+	err := readRecords(dec, func(rec Record) error {
+		switch rec.Type {
+		case TypeBeacon:
+			ps, err := beacon.UnpackBeacon(rec.Segment)
+			if err != nil {
+				return serrors.WrapStr("decoding beacon", err)
+			}
+			if verifier != nil {
+				if err := ps.Verify(ctx, verifier); err != nil {
+					stats.Rejected[rec.Type]++
+					return nil
+				}
+			}
+			b := beacon.Beacon{Segment: ps, InIfId: common.IFIDType(rec.IngressInterface)}
+			if _, err := db.InsertBeacon(ctx, b, beacon.Usage(rec.Usage)); err != nil {
+				return serrors.WrapStr("inserting beacon", err)
+			}
+		case TypeRevocation:
+			rev, err := path_mgmt.NewSignedRevInfoFromRaw(rec.Revocation)
+			if err != nil {
+				return serrors.WrapStr("decoding revocation", err)
+			}
+			if err := db.InsertRevocation(ctx, rev); err != nil {
+				return serrors.WrapStr("inserting revocation", err)
+			}
+		default:
+			return serrors.New("unexpected record type", "type", rec.Type)
+		}
+		stats.add(rec)
+		return nil
+	})
+	return stats, err
+}
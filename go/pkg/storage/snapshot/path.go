@@ -0,0 +1,147 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/pathdb"
+	"github.com/scionproto/scion/go/lib/pathdb/query"
+	"github.com/scionproto/scion/go/lib/serrors"
+)
+
+// ExportPaths writes a snapshot of all path segments in the path database to
+// w. The next query timestamps are not exported.
+func ExportPaths(ctx context.Context, db pathdb.Read, w io.Writer) (Stats, error) {
+	enc, err := newEncoder(w, DBPath)
+	if err != nil {
+		return Stats{}, err
+	}
+	results, err := db.GetAll(ctx)
+	if err != nil {
+		return Stats{}, serrors.WrapStr("reading path segments", err)
+	}
+	stats := newStats(DBPath)
+	var errs serrors.List
+	for res := range results {
+		// The channel must be drained, hence errors are only collected.
+		if res.Err != nil {
+			errs = append(errs, res.Err)
+			continue
+		}
+		if len(errs) != 0 {
+			continue
+		}
+		rec, err := pathRecord(res.Result)
+		if err == nil {
+			err = enc.Encode(rec)
+		}
+		if err != nil {
+			errs = append(errs, serrors.WrapStr("exporting path segment", err,
+				"segment", res.Result.Seg))
+			continue
+		}
+		stats.add(rec)
+	}
+	if err := errs.ToError(); err != nil {
+		return stats, err
+	}
+	return stats, nil
+}
+
+func pathRecord(res *query.Result) (Record, error) {
+	raw, err := encodeSegment(res.Seg)
+	if err != nil {
+		return Record{}, err
+	}
+	lastUpdated := res.LastUpdate.UTC()
+	rec := Record{
+		Type:        TypeSegment,
+		Segment:     raw,
+		SegmentType: res.Type.String(),
+		LastUpdated: &lastUpdated,
+	}
+	for _, id := range res.HpCfgIDs {
+		rec.HiddenPathGroups = append(rec.HiddenPathGroups, HiddenPathGroup{
+			IsdAs: id.IA.String(),
+			ID:    id.ID,
+		})
+	}
+	return rec, nil
+}
+
+// ImportPaths reads a snapshot of a path database from r and inserts the path
+// segments into the path database. If verifier is not nil, segments whose
+// signatures cannot be verified are rejected. Otherwise, the segments are
+// inserted without verification.
+func ImportPaths(ctx context.Context, db pathdb.Write, verifier seg.Verifier,
+	r io.Reader) (Stats, error) {
+
+	dec := json.NewDecoder(r)
+	if _, err := readHeader(dec, DBPath); err != nil {
+		return Stats{}, err
+	}
+	stats := newStats(DBPath)
+	err := readRecords(dec, func(rec Record) error {
+		if rec.Type != TypeSegment {
+			return serrors.New("unexpected record type", "type", rec.Type)
+		}
+		meta, ids, err := parsePathRecord(rec)
+		if err != nil {
+			return err
+		}
+		if verifier != nil {
+			if err := meta.Segment.Verify(ctx, verifier); err != nil {
+				stats.Rejected[rec.Type]++
+				return nil
+			}
+		}
+		if len(ids) == 0 {
+			_, err = db.Insert(ctx, meta)
+		} else {
+			_, err = db.InsertWithHPCfgIDs(ctx, meta, ids)
+		}
+		if err != nil {
+			return serrors.WrapStr("inserting path segment", err)
+		}
+		stats.add(rec)
+		return nil
+	})
+	return stats, err
+}
+
+func parsePathRecord(rec Record) (*seg.Meta, []*query.HPCfgID, error) {
+	ps, err := decodeSegment(rec.Segment)
+	if err != nil {
+		return nil, nil, serrors.WrapStr("decoding path segment", err)
+	}
+	segType, err := parseSegmentType(rec.SegmentType)
+	if err != nil {
+		return nil, nil, err
+	}
+	var ids []*query.HPCfgID
+	for _, group := range rec.HiddenPathGroups {
+		ia, err := addr.IAFromString(group.IsdAs)
+		if err != nil {
+			return nil, nil, serrors.WrapStr("parsing hidden path group", err)
+		}
+		ids = append(ids, &query.HPCfgID{IA: ia, ID: group.ID})
+	}
+	return &seg.Meta{Type: segType, Segment: ps}, ids, nil
+}
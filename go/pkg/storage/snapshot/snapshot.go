@@ -0,0 +1,220 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package snapshot exports the content of the beacon, path and trust databases
+// to a portable format, and imports it again.
+//
+// A snapshot consists of JSON objects, one per line. The first line is the
+// header, which contains the format version and the type of database. Every
+// following line is a record that holds one database entry. Path segments,
+// TRCs and certificates are stored in their raw encoding, such that their
+// signatures can be verified on import. Byte strings are base64 encoded.
+//
+// Snapshots are created and imported through the database interfaces, and are
+// thus independent of the database backend.
+package snapshot
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/serrors"
+	cppb "github.com/scionproto/scion/go/pkg/proto/control_plane"
+)
+
+// Version is the snapshot format version that is written. Snapshots with a
+// different version cannot be read.
+const Version = 1
+
+// DB is the type of database a snapshot is taken from.
+type DB string
+
+// Database types.
+const (
+	DBBeacon DB = "beacon"
+	DBPath   DB = "path"
+	DBTrust  DB = "trust"
+)
+
+// Record types.
+const (
+	TypeSegment    = "segment"
+	TypeBeacon     = "beacon"
+	TypeRevocation = "revocation"
+	TypeTRC        = "trc"
+	TypeChain      = "chain"
+)
+
+// Header is the first line of a snapshot.
+type Header struct {
+	// Version is the format version.
+	Version int `json:"version"`
+	// DB is the type of database the snapshot is taken from.
+	DB DB `json:"db"`
+	// Created is the time the snapshot was created.
+	Created time.Time `json:"created"`
+}
+
+// Record is a database entry in a snapshot. Only the fields that belong to
+// the record type are set.
+type Record struct {
+	// Type is the record type.
+	Type string `json:"type"`
+	// Segment is the raw path segment of segment and beacon records.
+	Segment []byte `json:"segment,omitempty"`
+	// SegmentType is the type of segment records, i.e., "up", "down" or
+	// "core".
+	SegmentType string `json:"segment_type,omitempty"`
+	// HiddenPathGroups are the hidden path configuration IDs of segment
+	// records.
+	HiddenPathGroups []HiddenPathGroup `json:"hidden_path_groups,omitempty"`
+	// LastUpdated is the time segment records were last updated in the
+	// database. It is informational, the time is not restored on import.
+	LastUpdated *time.Time `json:"last_updated,omitempty"`
+	// IngressInterface is the interface beacon records were received on.
+	IngressInterface uint64 `json:"ingress_interface,omitempty"`
+	// Usage is the usage bit mask of beacon records.
+	Usage int `json:"usage,omitempty"`
+	// Revocation is the raw signed revocation of revocation records.
+	Revocation []byte `json:"revocation,omitempty"`
+	// TRC is the raw signed TRC of TRC records.
+	TRC []byte `json:"trc,omitempty"`
+	// Chain are the raw AS and CA certificates of chain records.
+	Chain [][]byte `json:"chain,omitempty"`
+}
+
+// HiddenPathGroup identifies a hidden path configuration.
+type HiddenPathGroup struct {
+	IsdAs string `json:"isd_as"`
+	ID    uint64 `json:"id"`
+}
+
+// Stats counts the records in a snapshot.
+type Stats struct {
+	// DB is the type of database.
+	DB DB
+	// Records counts the exported or imported records per record type.
+	Records map[string]int
+	// Rejected counts the records per record type that were not imported,
+	// because the verification failed.
+	Rejected map[string]int
+	// Segments counts the exported or imported segment records per segment
+	// type.
+	Segments map[string]int
+}
+
+func newStats(db DB) Stats {
+	return Stats{
+		DB:       db,
+		Records:  make(map[string]int),
+		Rejected: make(map[string]int),
+		Segments: make(map[string]int),
+	}
+}
+
+func (s Stats) add(rec Record) {
+	s.Records[rec.Type]++
+	if rec.Type == TypeSegment {
+		s.Segments[rec.SegmentType]++
+	}
+}
+
+// ReadStats reads the snapshot from r and counts the records without
+// importing them.
+func ReadStats(r io.Reader) (Stats, error) {
+	dec := json.NewDecoder(r)
+	hdr, err := readHeader(dec, "")
+	if err != nil {
+		return Stats{}, err
+	}
+	stats := newStats(hdr.DB)
+	err = readRecords(dec, func(rec Record) error {
+		stats.add(rec)
+		return nil
+	})
+	return stats, err
+}
+
+// newEncoder writes the header to w and returns an encoder for the records.
+func newEncoder(w io.Writer, db DB) (*json.Encoder, error) {
+	enc := json.NewEncoder(w)
+	hdr := Header{
+		Version: Version,
+		DB:      db,
+		Created: time.Now().UTC(),
+	}
+	if err := enc.Encode(hdr); err != nil {
+		return nil, serrors.WrapStr("writing header", err)
+	}
+	return enc, nil
+}
+
+// readHeader reads and checks the header. If db is not empty, the snapshot
+// must be taken from that type of database.
+func readHeader(dec *json.Decoder, db DB) (Header, error) {
+	var hdr Header
+	if err := dec.Decode(&hdr); err != nil {
+		return Header{}, serrors.WrapStr("reading header", err)
+	}
+	if hdr.Version != Version {
+		return Header{}, serrors.New("unsupported snapshot version",
+			"expected", Version, "actual", hdr.Version)
+	}
+	if db != "" && hdr.DB != db {
+		return Header{}, serrors.New("snapshot of wrong database type",
+			"expected", db, "actual", hdr.DB)
+	}
+	return hdr, nil
+}
+
+// readRecords decodes the records and calls handle for every record.
+func readRecords(dec *json.Decoder, handle func(Record) error) error {
+	for line := 2; ; line++ {
+		var rec Record
+		if err := dec.Decode(&rec); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return serrors.WrapStr("decoding record", err, "line", line)
+		}
+		if err := handle(rec); err != nil {
+			return serrors.WithCtx(err, "line", line)
+		}
+	}
+}
+
+func encodeSegment(ps *seg.PathSegment) ([]byte, error) {
+	return proto.Marshal(seg.PathSegmentToPB(ps))
+}
+
+func decodeSegment(raw []byte) (*seg.PathSegment, error) {
+	var pb cppb.PathSegment
+	if err := proto.Unmarshal(raw, &pb); err != nil {
+		return nil, err
+	}
+	return seg.SegmentFromPB(&pb)
+}
+
+func parseSegmentType(s string) (seg.Type, error) {
+	for _, t := range []seg.Type{seg.TypeUp, seg.TypeDown, seg.TypeCore} {
+		if t.String() == s {
+			return t, nil
+		}
+	}
+	return 0, serrors.New("unknown segment type", "type", s)
+}
@@ -0,0 +1,288 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/cs/beacon"
+	"github.com/scionproto/scion/go/cs/beacon/beacondbsqlite"
+	"github.com/scionproto/scion/go/cs/beacon/beacondbtest"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/pathdb/query"
+	pathdbsqlite "github.com/scionproto/scion/go/lib/pathdb/sqlite"
+	"github.com/scionproto/scion/go/lib/scrypto/cppki"
+	"github.com/scionproto/scion/go/lib/scrypto/signed"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/lib/xtest/graph"
+	"github.com/scionproto/scion/go/pkg/proto/crypto"
+	"github.com/scionproto/scion/go/pkg/storage/snapshot"
+	truststorage "github.com/scionproto/scion/go/pkg/storage/trust"
+	trustsqlite "github.com/scionproto/scion/go/pkg/storage/trust/sqlite"
+	"github.com/scionproto/scion/go/pkg/trust"
+)
+
+type verifier struct {
+	err error
+}
+
+func (v verifier) Verify(context.Context, *crypto.SignedMessage,
+	...[]byte) (*signed.Message, error) {
+
+	return &signed.Message{}, v.err
+}
+
+func TestPaths(t *testing.T) {
+	mctrl := gomock.NewController(t)
+	defer mctrl.Finish()
+	g := graph.NewDefaultGraph(mctrl)
+	up := g.Beacon([]common.IFIDType{graph.If_120_X_111_B})
+	core := g.Beacon([]common.IFIDType{graph.If_130_B_120_A})
+	hpGroup := &query.HPCfgID{IA: xtest.MustParseIA("1-ff00:0:111"), ID: 42}
+
+	ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
+	defer cancelF()
+	src, err := pathdbsqlite.New("file::memory:")
+	require.NoError(t, err)
+	defer src.Close()
+	_, err = src.InsertWithHPCfgIDs(ctx, &seg.Meta{Type: seg.TypeUp, Segment: up},
+		[]*query.HPCfgID{hpGroup})
+	require.NoError(t, err)
+	_, err = src.Insert(ctx, &seg.Meta{Type: seg.TypeCore, Segment: core})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	stats, err := snapshot.ExportPaths(ctx, src, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot.DBPath, stats.DB)
+	assert.Equal(t, map[string]int{snapshot.TypeSegment: 2}, stats.Records)
+	assert.Equal(t, map[string]int{"up": 1, "core": 1}, stats.Segments)
+
+	t.Run("import", func(t *testing.T) {
+		dst, err := pathdbsqlite.New("file::memory:")
+		require.NoError(t, err)
+		defer dst.Close()
+		stats, err := snapshot.ImportPaths(ctx, dst, verifier{},
+			bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{snapshot.TypeSegment: 2}, stats.Records)
+		assert.Empty(t, stats.Rejected)
+
+		res, err := dst.Get(ctx, &query.Params{SegTypes: []seg.Type{seg.TypeUp}})
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		assert.Equal(t, up.ID(), res[0].Seg.ID())
+		assert.Equal(t, []*query.HPCfgID{hpGroup}, res[0].HpCfgIDs)
+		res, err = dst.Get(ctx, &query.Params{SegTypes: []seg.Type{seg.TypeCore}})
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		assert.Equal(t, core.ID(), res[0].Seg.ID())
+	})
+	t.Run("invalid signatures are rejected", func(t *testing.T) {
+		dst, err := pathdbsqlite.New("file::memory:")
+		require.NoError(t, err)
+		defer dst.Close()
+		stats, err := snapshot.ImportPaths(ctx, dst, verifier{err: serrors.New("invalid")},
+			bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err)
+		assert.Empty(t, stats.Records)
+		assert.Equal(t, map[string]int{snapshot.TypeSegment: 2}, stats.Rejected)
+
+		res, err := dst.Get(ctx, &query.Params{})
+		require.NoError(t, err)
+		assert.Empty(t, res)
+	})
+	t.Run("wrong database type", func(t *testing.T) {
+		db, err := beacondbsqlite.New("file::memory:", xtest.MustParseIA("1-ff00:0:111"))
+		require.NoError(t, err)
+		defer db.Close()
+		_, err = snapshot.ImportBeacons(ctx, db, nil, bytes.NewReader(buf.Bytes()))
+		assert.Error(t, err)
+	})
+}
+
+func TestBeacons(t *testing.T) {
+	mctrl := gomock.NewController(t)
+	defer mctrl.Finish()
+	b1, _ := beacondbtest.AllocBeacon(t, mctrl, beacondbtest.Info2, 12, 10)
+	b2, _ := beacondbtest.AllocBeacon(t, mctrl, beacondbtest.Info3, 12, 10)
+	local := xtest.MustParseIA("1-ff00:0:333")
+
+	ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
+	defer cancelF()
+	src, err := beacondbsqlite.New("file::memory:", local)
+	require.NoError(t, err)
+	defer src.Close()
+	_, err = src.InsertBeacon(ctx, b1, beacon.UsageUpReg|beacon.UsageProp)
+	require.NoError(t, err)
+	_, err = src.InsertBeacon(ctx, b2, beacon.UsageDownReg)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	stats, err := snapshot.ExportBeacons(ctx, src, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{snapshot.TypeBeacon: 2}, stats.Records)
+
+	dst, err := beacondbsqlite.New("file::memory:", local)
+	require.NoError(t, err)
+	defer dst.Close()
+	stats, err = snapshot.ImportBeacons(ctx, dst, verifier{}, bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{snapshot.TypeBeacon: 2}, stats.Records)
+
+	candidates := func(usage beacon.Usage) []*seg.PathSegment {
+		results, err := dst.CandidateBeacons(ctx, 10, usage, b1.Segment.FirstIA())
+		require.NoError(t, err)
+		var segs []*seg.PathSegment
+		for res := range results {
+			require.NoError(t, res.Err)
+			assert.Equal(t, common.IFIDType(12), res.Beacon.InIfId)
+			segs = append(segs, res.Beacon.Segment)
+		}
+		return segs
+	}
+	assert.Len(t, candidates(beacon.UsageUpReg|beacon.UsageProp), 1)
+	require.Len(t, candidates(beacon.UsageDownReg), 1)
+	assert.Equal(t, b2.Segment.ID(), candidates(beacon.UsageDownReg)[0].ID())
+	assert.Empty(t, candidates(beacon.UsageCoreReg))
+}
+
+func TestTrust(t *testing.T) {
+	base := xtest.LoadTRC(t, "../../trust/testdata/common/trcs/ISD1-B1-S1.trc")
+	update := xtest.LoadTRC(t, "../../trust/testdata/common/trcs/ISD1-B1-S2.trc")
+	chain := xtest.LoadChain(t, "../../trust/testdata/common/certs/ISD1-ASff00_0_110.pem")
+
+	ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
+	defer cancelF()
+	src, err := trustsqlite.New("file::memory:")
+	require.NoError(t, err)
+	defer src.Close()
+	_, err = src.InsertTRC(ctx, update)
+	require.NoError(t, err)
+	_, err = src.InsertTRC(ctx, base)
+	require.NoError(t, err)
+	_, err = src.InsertChain(ctx, chain)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	stats, err := snapshot.ExportTrust(ctx, src, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{snapshot.TypeTRC: 2, snapshot.TypeChain: 1}, stats.Records)
+
+	t.Run("import", func(t *testing.T) {
+		dst, err := trustsqlite.New("file::memory:")
+		require.NoError(t, err)
+		defer dst.Close()
+		stats, err := snapshot.ImportTrust(ctx, dst, []cppki.SignedTRC{base},
+			bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{snapshot.TypeTRC: 2, snapshot.TypeChain: 1},
+			stats.Records)
+		assert.Empty(t, stats.Rejected)
+
+		trc, err := dst.SignedTRC(ctx, update.TRC.ID)
+		require.NoError(t, err)
+		assert.Equal(t, update.Raw, trc.Raw)
+		chains, err := dst.Chains(ctx, trust.ChainQuery{})
+		require.NoError(t, err)
+		assert.Equal(t, [][]*x509.Certificate{chain}, chains)
+	})
+	t.Run("entries without anchor are rejected", func(t *testing.T) {
+		// Drop the base TRC from the snapshot, such that neither the TRC update
+		// nor the chain can be verified.
+		lines := strings.SplitN(buf.String(), "\n", 3)
+		stripped := lines[0] + "\n" + lines[2]
+
+		dst, err := trustsqlite.New("file::memory:")
+		require.NoError(t, err)
+		defer dst.Close()
+		stats, err := snapshot.ImportTrust(ctx, dst, nil, strings.NewReader(stripped))
+		require.NoError(t, err)
+		assert.Empty(t, stats.Records)
+		assert.Equal(t, map[string]int{snapshot.TypeTRC: 1, snapshot.TypeChain: 1},
+			stats.Rejected)
+	})
+	t.Run("base TRC that is not an anchor is rejected", func(t *testing.T) {
+		dst, err := trustsqlite.New("file::memory:")
+		require.NoError(t, err)
+		defer dst.Close()
+		// Only identical TRCs are anchors.
+		stats, err := snapshot.ImportTrust(ctx, dst, []cppki.SignedTRC{update},
+			bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err)
+		assert.Empty(t, stats.Records)
+		assert.Equal(t, map[string]int{snapshot.TypeTRC: 2, snapshot.TypeChain: 1},
+			stats.Rejected)
+		trcs, err := dst.SignedTRCs(ctx, truststorage.TRCsQuery{})
+		require.NoError(t, err)
+		assert.Empty(t, trcs)
+	})
+	t.Run("base TRC from database", func(t *testing.T) {
+		dst, err := trustsqlite.New("file::memory:")
+		require.NoError(t, err)
+		defer dst.Close()
+		_, err = dst.InsertTRC(ctx, base)
+		require.NoError(t, err)
+		stats, err := snapshot.ImportTrust(ctx, dst, nil, bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{snapshot.TypeTRC: 2, snapshot.TypeChain: 1},
+			stats.Records)
+		assert.Empty(t, stats.Rejected)
+	})
+	t.Run("anchor from database", func(t *testing.T) {
+		lines := strings.SplitN(buf.String(), "\n", 3)
+		stripped := lines[0] + "\n" + lines[2]
+
+		dst, err := trustsqlite.New("file::memory:")
+		require.NoError(t, err)
+		defer dst.Close()
+		_, err = dst.InsertTRC(ctx, base)
+		require.NoError(t, err)
+		stats, err := snapshot.ImportTrust(ctx, dst, nil, strings.NewReader(stripped))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{snapshot.TypeTRC: 1, snapshot.TypeChain: 1},
+			stats.Records)
+		assert.Empty(t, stats.Rejected)
+	})
+}
+
+func TestReadStats(t *testing.T) {
+	input := `{"version":1,"db":"path","created":"2021-01-01T00:00:00Z"}
+{"type":"segment","segment":"AA==","segment_type":"up"}
+{"type":"segment","segment":"AA==","segment_type":"down"}
+{"type":"segment","segment":"AA==","segment_type":"down"}
+`
+	stats, err := snapshot.ReadStats(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, snapshot.DBPath, stats.DB)
+	assert.Equal(t, map[string]int{snapshot.TypeSegment: 3}, stats.Records)
+	assert.Equal(t, map[string]int{"up": 1, "down": 2}, stats.Segments)
+
+	_, err = snapshot.ReadStats(strings.NewReader(`{"version":2,"db":"path"}`))
+	assert.Error(t, err)
+	_, err = snapshot.ReadStats(strings.NewReader(input + "garbage\n"))
+	assert.Error(t, err)
+}
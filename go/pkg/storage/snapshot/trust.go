@@ -0,0 +1,201 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/scrypto/cppki"
+	"github.com/scionproto/scion/go/lib/serrors"
+	truststorage "github.com/scionproto/scion/go/pkg/storage/trust"
+	"github.com/scionproto/scion/go/pkg/trust"
+)
+
+// TrustDB is the trust database a snapshot is taken from.
+type TrustDB interface {
+	trust.DB
+	// SignedTRCs returns the TRCs matching the query.
+	SignedTRCs(context.Context, truststorage.TRCsQuery) (cppki.SignedTRCs, error)
+}
+
+// ExportTrust writes a snapshot of all TRCs and certificate chains in the trust
+// database to w. The TRCs are sorted by ISD, base number and serial number,
+// such that every TRC follows its predecessor.
+func ExportTrust(ctx context.Context, db TrustDB, w io.Writer) (Stats, error) {
+	enc, err := newEncoder(w, DBTrust)
+	if err != nil {
+		return Stats{}, err
+	}
+	stats := newStats(DBTrust)
+	trcs, err := db.SignedTRCs(ctx, truststorage.TRCsQuery{})
+	if err != nil {
+		return stats, serrors.WrapStr("reading TRCs", err)
+	}
+	sort.Sort(trcs)
+	for _, trc := range trcs {
+		rec := Record{Type: TypeTRC, TRC: trc.Raw}
+		if err := enc.Encode(rec); err != nil {
+			return stats, serrors.WrapStr("writing TRC", err, "id", trc.TRC.ID)
+		}
+		stats.add(rec)
+	}
+	chains, err := db.Chains(ctx, trust.ChainQuery{})
+	if err != nil {
+		return stats, serrors.WrapStr("reading certificate chains", err)
+	}
+	for _, chain := range chains {
+		rec := Record{Type: TypeChain}
+		for _, cert := range chain {
+			rec.Chain = append(rec.Chain, cert.Raw)
+		}
+		if err := enc.Encode(rec); err != nil {
+			return stats, serrors.WrapStr("writing certificate chain", err)
+		}
+		stats.add(rec)
+	}
+	return stats, nil
+}
+
+// ImportTrust reads a snapshot of a trust database from r and inserts the TRCs
+// and certificate chains into the trust database. Base TRCs are trust anchors
+// and are only accepted if they are identical to a TRC in the database or to
+// one of the explicitly provided anchors. TRC updates must verify against
+// their predecessor, such that every accepted TRC is connected to an anchor by
+// a chain of verified updates. Certificate chains must verify against a TRC
+// of their ISD. The predecessors and TRCs are looked up in the database, which
+// includes the TRCs imported before. Entries that do not verify are rejected.
+func ImportTrust(ctx context.Context, db TrustDB, anchors []cppki.SignedTRC,
+	r io.Reader) (Stats, error) {
+
+	dec := json.NewDecoder(r)
+	if _, err := readHeader(dec, DBTrust); err != nil {
+		return Stats{}, err
+	}
+	stats := newStats(DBTrust)
+	err := readRecords(dec, func(rec Record) error {
+		var verified bool
+		var err error
+		switch rec.Type {
+		case TypeTRC:
+			verified, err = importTRC(ctx, db, anchors, rec)
+		case TypeChain:
+			verified, err = importChain(ctx, db, rec)
+		default:
+			return serrors.New("unexpected record type", "type", rec.Type)
+		}
+		if err != nil {
+			return err
+		}
+		if !verified {
+			stats.Rejected[rec.Type]++
+			return nil
+		}
+		stats.add(rec)
+		return nil
+	})
+	return stats, err
+}
+
+func importTRC(ctx context.Context, db TrustDB, anchors []cppki.SignedTRC,
+	rec Record) (bool, error) {
+
+	trc, err := cppki.DecodeSignedTRC(rec.TRC)
+	if err != nil {
+		return false, serrors.WrapStr("decoding TRC", err)
+	}
+	var predecessor *cppki.TRC
+	if id := trc.TRC.ID; id.IsBase() {
+		anchored, err := isAnchor(ctx, db, anchors, trc)
+		if err != nil || !anchored {
+			return false, err
+		}
+	} else {
+		predID := cppki.TRCID{ISD: id.ISD, Base: id.Base, Serial: id.Serial - 1}
+		pred, err := db.SignedTRC(ctx, predID)
+		if err != nil {
+			return false, serrors.WrapStr("reading predecessor TRC", err, "id", predID)
+		}
+		if pred.IsZero() {
+			return false, nil
+		}
+		predecessor = &pred.TRC
+	}
+	if err := trc.Verify(predecessor); err != nil {
+		return false, nil
+	}
+	if _, err := db.InsertTRC(ctx, trc); err != nil {
+		return false, serrors.WrapStr("inserting TRC", err, "id", trc.TRC.ID)
+	}
+	return true, nil
+}
+
+// isAnchor checks whether the base TRC is identical to one of the anchors or to
+// the TRC with the same ID in the database.
+func isAnchor(ctx context.Context, db TrustDB, anchors []cppki.SignedTRC,
+	trc cppki.SignedTRC) (bool, error) {
+
+	for _, anchor := range anchors {
+		if bytes.Equal(anchor.Raw, trc.Raw) {
+			return true, nil
+		}
+	}
+	known, err := db.SignedTRC(ctx, trc.TRC.ID)
+	if err != nil {
+		return false, serrors.WrapStr("reading base TRC", err, "id", trc.TRC.ID)
+	}
+	return !known.IsZero() && bytes.Equal(known.Raw, trc.Raw), nil
+}
+
+func importChain(ctx context.Context, db TrustDB, rec Record) (bool, error) {
+	chain := make([]*x509.Certificate, 0, len(rec.Chain))
+	for _, raw := range rec.Chain {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return false, serrors.WrapStr("parsing certificate", err)
+		}
+		chain = append(chain, cert)
+	}
+	if len(chain) == 0 {
+		return false, serrors.New("empty certificate chain")
+	}
+	ia, err := cppki.ExtractIA(chain[0].Subject)
+	if err != nil {
+		return false, serrors.WrapStr("extracting ISD-AS", err)
+	}
+	signed, err := db.SignedTRCs(ctx, truststorage.TRCsQuery{ISD: []addr.ISD{ia.I}})
+	if err != nil {
+		return false, serrors.WrapStr("reading TRCs", err, "isd", ia.I)
+	}
+	trcs := make([]*cppki.TRC, 0, len(signed))
+	for i := range signed {
+		trcs = append(trcs, &signed[i].TRC)
+	}
+	// The chain is verified at the time it became valid, such that chains
+	// that expired in the meantime can still be imported.
+	opts := cppki.VerifyOptions{TRC: trcs, CurrentTime: chain[0].NotBefore}
+	if err := cppki.VerifyChain(chain, opts); err != nil {
+		return false, nil
+	}
+	if _, err := db.InsertChain(ctx, chain); err != nil {
+		return false, serrors.WrapStr("inserting certificate chain", err)
+	}
+	return true, nil
+}
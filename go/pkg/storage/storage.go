@@ -159,7 +159,14 @@ func NewPathStorage(c DBConfig) (pathdb.PathDB, error) {
 	return db, nil
 }
 
-func NewRevocationStorage() revcache.RevCache {
+// NewRevocationStorage returns the revocation cache that is backed by the given
+// path database. Revocations are thus persisted alongside the path segments
+// and survive restarts. If the path database does not support storing
+// revocations, an in-memory cache is returned.
+func NewRevocationStorage(pathDB pathdb.PathDB) revcache.RevCache {
+	if db, ok := pathDB.(*sqlitepathdb.Backend); ok {
+		return db.RevCache()
+	}
 	return memrevcache.New()
 }
 
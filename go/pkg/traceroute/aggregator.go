@@ -0,0 +1,159 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceroute
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/snet"
+)
+
+// HopStats contains the statistics of a hop aggregated over multiple
+// traceroute runs.
+type HopStats struct {
+	// Index indicates the hop index in the path.
+	Index int
+	// Remote is the remote router that answered most recently.
+	Remote *snet.UDPAddr
+	// Interface is the interface ID of the remote router.
+	Interface uint64
+	// Sent is the number of probes sent to the hop.
+	Sent int
+	// Recv is the number of probes answered in time.
+	Recv int
+	// RTTs are the round trip times of the answered probes, in the order they
+	// were sent.
+	RTTs []time.Duration
+}
+
+// Loss returns the fraction of probes that were not answered in time.
+func (s HopStats) Loss() float64 {
+	if s.Sent == 0 {
+		return 0
+	}
+	return 1 - float64(s.Recv)/float64(s.Sent)
+}
+
+// RTTSummary summarizes the round trip times of a hop.
+type RTTSummary struct {
+	Last time.Duration
+	Min  time.Duration
+	Avg  time.Duration
+	Max  time.Duration
+	// StdDev is the standard deviation of the round trip times.
+	StdDev time.Duration
+	// Jitter is the mean difference between the round trip times of
+	// consecutive probes.
+	Jitter time.Duration
+}
+
+// RTT summarizes the round trip times. If no probe was answered, the zero
+// value is returned.
+func (s HopStats) RTT() RTTSummary {
+	if len(s.RTTs) == 0 {
+		return RTTSummary{}
+	}
+	summary := RTTSummary{
+		Last: s.RTTs[len(s.RTTs)-1],
+		Min:  s.RTTs[0],
+		Max:  s.RTTs[0],
+	}
+	var sum, sumSquares, sumDiffs float64
+	for i, rtt := range s.RTTs {
+		if rtt < summary.Min {
+			summary.Min = rtt
+		}
+		if rtt > summary.Max {
+			summary.Max = rtt
+		}
+		sum += float64(rtt)
+		sumSquares += float64(rtt) * float64(rtt)
+		if i > 0 {
+			sumDiffs += math.Abs(float64(rtt - s.RTTs[i-1]))
+		}
+	}
+	n := float64(len(s.RTTs))
+	avg := sum / n
+	summary.Avg = time.Duration(avg)
+	summary.StdDev = time.Duration(math.Sqrt(math.Max(sumSquares/n-avg*avg, 0)))
+	if len(s.RTTs) > 1 {
+		summary.Jitter = time.Duration(sumDiffs / (n - 1))
+	}
+	return summary
+}
+
+// Aggregator aggregates the updates of multiple traceroute runs over the same
+// path per hop. It is safe for concurrent use.
+type Aggregator struct {
+	// Timeout is the timeout of the traceroute runs. Probes with a larger
+	// round trip time are counted as lost.
+	Timeout time.Duration
+
+	mtx    sync.Mutex
+	hops   []*HopStats
+	rounds int
+}
+
+// Update adds the result of probing a hop. It can be used as the update
+// handler of a traceroute run.
+func (a *Aggregator) Update(u Update) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	for len(a.hops) <= u.Index {
+		a.hops = append(a.hops, &HopStats{Index: len(a.hops)})
+	}
+	hop := a.hops[u.Index]
+	if u.Remote != nil {
+		hop.Remote = u.Remote
+		hop.Interface = u.Interface
+	}
+	for _, rtt := range u.RTTs {
+		hop.Sent++
+		if rtt > a.Timeout {
+			continue
+		}
+		hop.Recv++
+		hop.RTTs = append(hop.RTTs, rtt)
+	}
+}
+
+// CompleteRound marks a traceroute run as completed.
+func (a *Aggregator) CompleteRound() {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.rounds++
+}
+
+// Rounds returns the number of completed traceroute runs.
+func (a *Aggregator) Rounds() int {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	return a.rounds
+}
+
+// Hops returns the statistics of all hops, ordered by the hop index.
+func (a *Aggregator) Hops() []HopStats {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	hops := make([]HopStats, 0, len(a.hops))
+	for _, hop := range a.hops {
+		h := *hop
+		h.RTTs = append([]time.Duration(nil), hop.RTTs...)
+		hops = append(hops, h)
+	}
+	return hops
+}
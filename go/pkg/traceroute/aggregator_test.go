@@ -0,0 +1,68 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package traceroute_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/pkg/traceroute"
+)
+
+func TestAggregator(t *testing.T) {
+	router := &snet.UDPAddr{
+		IA:   xtest.MustParseIA("1-ff00:0:110"),
+		Host: &net.UDPAddr{IP: net.IP{127, 0, 0, 1}},
+	}
+	a := traceroute.Aggregator{Timeout: time.Second}
+	a.Update(traceroute.Update{
+		Index:     1,
+		Remote:    router,
+		Interface: 2,
+		RTTs:      []time.Duration{2 * time.Millisecond, time.Second + 1},
+	})
+	a.CompleteRound()
+	a.Update(traceroute.Update{
+		Index:     1,
+		Remote:    router,
+		Interface: 2,
+		RTTs:      []time.Duration{6 * time.Millisecond, 4 * time.Millisecond},
+	})
+	a.CompleteRound()
+
+	hops := a.Hops()
+	assert.Equal(t, 2, a.Rounds())
+	assert.Len(t, hops, 2)
+	assert.Equal(t, traceroute.HopStats{Index: 0}, hops[0])
+	hop := hops[1]
+	assert.Equal(t, router, hop.Remote)
+	assert.Equal(t, uint64(2), hop.Interface)
+	assert.Equal(t, 4, hop.Sent)
+	assert.Equal(t, 3, hop.Recv)
+	assert.Equal(t, 0.25, hop.Loss())
+
+	rtt := hop.RTT()
+	assert.Equal(t, 4*time.Millisecond, rtt.Last)
+	assert.Equal(t, 2*time.Millisecond, rtt.Min)
+	assert.Equal(t, 4*time.Millisecond, rtt.Avg)
+	assert.Equal(t, 6*time.Millisecond, rtt.Max)
+	assert.InDelta(t, 1633*time.Microsecond, rtt.StdDev, float64(time.Microsecond))
+	assert.Equal(t, 3*time.Millisecond, rtt.Jitter)
+}
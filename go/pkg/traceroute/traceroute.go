@@ -29,6 +29,7 @@ import (
 	"github.com/scionproto/scion/go/lib/slayers/path/scion"
 	"github.com/scionproto/scion/go/lib/snet"
 	"github.com/scionproto/scion/go/lib/sock/reliable"
+	"github.com/scionproto/scion/go/lib/spath"
 )
 
 // Update contains the information for a single hop.
@@ -85,7 +86,9 @@ type tracerouter struct {
 
 	replies <-chan reply
 
-	path  snet.Path
+	// path is a copy of the raw path, such that setting the router alert
+	// flags does not modify the path of the caller.
+	path  spath.Path
 	id    uint16
 	index int
 
@@ -107,6 +110,7 @@ func Run(ctx context.Context, cfg Config) (Stats, error) {
 	if err != nil {
 		return Stats{}, err
 	}
+	defer conn.Close()
 	local := cfg.Local.Copy()
 	local.Host.Port = int(port)
 	t := tracerouter{
@@ -119,18 +123,18 @@ func Run(ctx context.Context, cfg Config) (Stats, error) {
 		errHandler:    cfg.ErrHandler,
 		updateHandler: cfg.UpdateHandler,
 		id:            uint16(id),
-		path:          cfg.PathEntry,
+		path:          cfg.PathEntry.Path().Copy(),
 	}
 	return t.Traceroute(ctx)
 }
 
 func (t *tracerouter) Traceroute(ctx context.Context) (Stats, error) {
 	pktPath := scion.Decoded{}
-	if err := pktPath.DecodeFromBytes(t.path.Path().Raw); err != nil {
+	if err := pktPath.DecodeFromBytes(t.path.Raw); err != nil {
 		return t.stats, serrors.WrapStr("decoding path", err)
 	}
 	idxPath := scion.Decoded{}
-	if err := idxPath.DecodeFromBytes(t.path.Path().Raw); err != nil {
+	if err := idxPath.DecodeFromBytes(t.path.Raw); err != nil {
 		return t.stats, serrors.WrapStr("decoding path", err)
 	}
 	ctx, cancel := context.WithCancel(ctx)
@@ -192,7 +196,7 @@ func (t *tracerouter) probeHop(ctx context.Context, dp *scion.Decoded,
 		hf.IngressRouterAlert = true
 		defer func() { hf.IngressRouterAlert = false }()
 	}
-	p := t.path.Path()
+	p := t.path
 	if err := dp.SerializeTo(p.Raw); err != nil {
 		return Update{}, serrors.WrapStr("serializing path", err)
 	}
@@ -260,7 +264,10 @@ func (t tracerouter) drain(ctx context.Context) {
 		default:
 			var pkt snet.Packet
 			var ov net.UDPAddr
-			if err := t.conn.ReadFrom(&pkt, &ov); err != nil && t.errHandler != nil {
+			err := t.conn.ReadFrom(&pkt, &ov)
+			// Errors caused by closing the connection at the end of the run
+			// are not reported.
+			if err != nil && ctx.Err() == nil && t.errHandler != nil {
 				// Rate limit the error reports.
 				if now := time.Now(); now.Sub(last) > 500*time.Millisecond {
 					t.errHandler(serrors.WrapStr("reading packet", err))
@@ -0,0 +1,216 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trust
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/scrypto/cppki"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/pkg/trust/internal/metrics"
+)
+
+// TRCAnnouncer provides the ID of the latest TRC of the local ISD. The ID is
+// announced to neighboring ASes, such that they learn about TRC updates
+// proactively.
+type TRCAnnouncer struct {
+	// ISD is the local ISD.
+	ISD addr.ISD
+	// DB provides the TRCs.
+	DB DB
+}
+
+// LatestTRC returns the ID of the latest TRC of the local ISD.
+func (a TRCAnnouncer) LatestTRC(ctx context.Context) (cppki.TRCID, error) {
+	trc, err := a.DB.SignedTRC(ctx, cppki.TRCID{
+		ISD:    a.ISD,
+		Base:   scrypto.LatestVer,
+		Serial: scrypto.LatestVer,
+	})
+	if err != nil {
+		return cppki.TRCID{}, err
+	}
+	if trc.IsZero() {
+		return cppki.TRCID{}, serrors.New("no TRC for ISD present", "isd", a.ISD)
+	}
+	return trc.TRC.ID, nil
+}
+
+// TRCAnnouncementHandler installs TRC updates that are announced by
+// neighboring ASes.
+type TRCAnnouncementHandler struct {
+	// ISD is the local ISD. Announcements for other ISDs are ignored.
+	ISD addr.ISD
+	// DB provides the latest locally available TRC.
+	DB DB
+	// Provider fetches, verifies, and inserts the announced TRC updates.
+	Provider Provider
+}
+
+// HandleTRCAnnouncement handles the announcement of the TRC with the given ID.
+// If the announced TRC is newer than the latest locally available TRC, the
+// missing TRC updates are fetched from the server, verified against their
+// predecessor, and inserted into the database. The predecessor remains
+// available for verification during the grace period of the update.
+func (h TRCAnnouncementHandler) HandleTRCAnnouncement(ctx context.Context, id cppki.TRCID,
+	server net.Addr) error {
+
+	if id.ISD != h.ISD {
+		return nil
+	}
+	trc, err := h.DB.SignedTRC(ctx, cppki.TRCID{
+		ISD:    id.ISD,
+		Base:   scrypto.LatestVer,
+		Serial: scrypto.LatestVer,
+	})
+	if err != nil {
+		return serrors.WrapStr("loading latest TRC", err)
+	}
+	if trc.IsZero() {
+		return serrors.New("no TRC for ISD present", "isd", id.ISD)
+	}
+	if trc.TRC.ID.Base != id.Base {
+		return serrors.New("base number mismatch",
+			"expected", trc.TRC.ID.Base, "actual", id.Base)
+	}
+	if id.Serial <= trc.TRC.ID.Serial {
+		return nil
+	}
+	ctx = metrics.CtxWith(ctx, metrics.TRCAnnouncement)
+	if err := h.Provider.NotifyTRC(ctx, id, Server(server)); err != nil {
+		return serrors.WrapStr("installing announced TRC", err, "id", id)
+	}
+	log.FromCtx(ctx).Info("Installed announced TRC update",
+		"previous", trc.TRC.ID, "id", id, "server", server)
+	return nil
+}
+
+const (
+	// DefaultAnnouncementQueueSize is the default number of TRC announcements
+	// that are queued for the background worker.
+	DefaultAnnouncementQueueSize = 16
+	// DefaultAnnouncementTimeout is the default timeout for handling a TRC
+	// announcement in the background worker.
+	DefaultAnnouncementTimeout = 10 * time.Second
+)
+
+// AsyncTRCAnnouncementHandler handles TRC announcements in a background
+// worker, such that the caller, e.g., the beacon RPC handler, is not blocked
+// while the TRC updates are fetched. Announcements of a TRC that is already
+// queued or being handled are dropped, as are announcements that do not fit
+// into the queue. Failures are only logged.
+type AsyncTRCAnnouncementHandler struct {
+	handler TRCAnnouncementHandler
+	timeout time.Duration
+	queue   chan trcAnnouncement
+	done    chan struct{}
+
+	mtx     sync.Mutex
+	pending map[cppki.TRCID]struct{}
+}
+
+type trcAnnouncement struct {
+	id     cppki.TRCID
+	server net.Addr
+	logger log.Logger
+}
+
+// NewAsyncTRCAnnouncementHandler starts the background worker of the handler.
+// If the queue size or the timeout are not positive, the defaults are used.
+// The worker is stopped with Close.
+func NewAsyncTRCAnnouncementHandler(handler TRCAnnouncementHandler, queueSize int,
+	timeout time.Duration) *AsyncTRCAnnouncementHandler {
+
+	if queueSize <= 0 {
+		queueSize = DefaultAnnouncementQueueSize
+	}
+	if timeout <= 0 {
+		timeout = DefaultAnnouncementTimeout
+	}
+	h := &AsyncTRCAnnouncementHandler{
+		handler: handler,
+		timeout: timeout,
+		queue:   make(chan trcAnnouncement, queueSize),
+		done:    make(chan struct{}),
+		pending: make(map[cppki.TRCID]struct{}),
+	}
+	go func() {
+		defer log.HandlePanic()
+		h.run()
+	}()
+	return h
+}
+
+// HandleTRCAnnouncement queues the announcement for the background worker. It
+// does not block and never returns an error, failures are logged by the
+// worker.
+func (h *AsyncTRCAnnouncementHandler) HandleTRCAnnouncement(ctx context.Context,
+	id cppki.TRCID, server net.Addr) error {
+
+	if id.ISD != h.handler.ISD {
+		return nil
+	}
+	logger := log.FromCtx(ctx)
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if _, ok := h.pending[id]; ok {
+		return nil
+	}
+	select {
+	case h.queue <- trcAnnouncement{id: id, server: server, logger: logger}:
+		h.pending[id] = struct{}{}
+	default:
+		logger.Debug("Dropped TRC announcement, queue is full", "id", id)
+	}
+	return nil
+}
+
+// Close stops the background worker. Queued announcements are dropped. Close
+// must only be called once.
+func (h *AsyncTRCAnnouncementHandler) Close() error {
+	close(h.done)
+	return nil
+}
+
+func (h *AsyncTRCAnnouncementHandler) run() {
+	for {
+		select {
+		case <-h.done:
+			return
+		case a := <-h.queue:
+			h.handle(a)
+		}
+	}
+}
+
+func (h *AsyncTRCAnnouncementHandler) handle(a trcAnnouncement) {
+	defer func() {
+		h.mtx.Lock()
+		defer h.mtx.Unlock()
+		delete(h.pending, a.id)
+	}()
+	ctx, cancel := context.WithTimeout(log.CtxWith(context.Background(), a.logger), h.timeout)
+	defer cancel()
+	if err := h.handler.HandleTRCAnnouncement(ctx, a.id, a.server); err != nil {
+		a.logger.Info("Failed to handle TRC announcement",
+			"id", a.id, "server", a.server, "err", err)
+	}
+}
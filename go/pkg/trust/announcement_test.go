@@ -0,0 +1,184 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trust_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/scrypto"
+	"github.com/scionproto/scion/go/lib/scrypto/cppki"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/xtest"
+	"github.com/scionproto/scion/go/pkg/trust"
+	"github.com/scionproto/scion/go/pkg/trust/mock_trust"
+)
+
+func TestTRCAnnouncerLatestTRC(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	latest := cppki.TRCID{ISD: 1, Base: 1, Serial: 3}
+	db := mock_trust.NewMockDB(ctrl)
+	db.EXPECT().SignedTRC(gomock.Any(), cppki.TRCID{
+		ISD:    1,
+		Base:   scrypto.LatestVer,
+		Serial: scrypto.LatestVer,
+	}).Return(cppki.SignedTRC{TRC: cppki.TRC{ID: latest}}, nil)
+	db.EXPECT().SignedTRC(gomock.Any(), gomock.Any()).Return(cppki.SignedTRC{}, nil)
+
+	a := trust.TRCAnnouncer{ISD: 1, DB: db}
+	id, err := a.LatestTRC(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, latest, id)
+
+	a = trust.TRCAnnouncer{ISD: 2, DB: db}
+	_, err = a.LatestTRC(context.Background())
+	assert.Error(t, err)
+}
+
+func TestTRCAnnouncementHandler(t *testing.T) {
+	local := cppki.SignedTRC{TRC: cppki.TRC{ID: cppki.TRCID{ISD: 1, Base: 1, Serial: 2}}}
+	server := &snet.SVCAddr{IA: xtest.MustParseIA("1-ff00:0:110"), SVC: addr.SvcCS}
+
+	testCases := map[string]struct {
+		ID           cppki.TRCID
+		Provider     func(ctrl *gomock.Controller) trust.Provider
+		ErrAssertion assert.ErrorAssertionFunc
+	}{
+		"other ISD": {
+			ID: cppki.TRCID{ISD: 2, Base: 1, Serial: 5},
+			Provider: func(ctrl *gomock.Controller) trust.Provider {
+				return mock_trust.NewMockProvider(ctrl)
+			},
+			ErrAssertion: assert.NoError,
+		},
+		"known TRC": {
+			ID: cppki.TRCID{ISD: 1, Base: 1, Serial: 2},
+			Provider: func(ctrl *gomock.Controller) trust.Provider {
+				return mock_trust.NewMockProvider(ctrl)
+			},
+			ErrAssertion: assert.NoError,
+		},
+		"older TRC": {
+			ID: cppki.TRCID{ISD: 1, Base: 1, Serial: 1},
+			Provider: func(ctrl *gomock.Controller) trust.Provider {
+				return mock_trust.NewMockProvider(ctrl)
+			},
+			ErrAssertion: assert.NoError,
+		},
+		"base mismatch": {
+			ID: cppki.TRCID{ISD: 1, Base: 3, Serial: 3},
+			Provider: func(ctrl *gomock.Controller) trust.Provider {
+				return mock_trust.NewMockProvider(ctrl)
+			},
+			ErrAssertion: assert.Error,
+		},
+		"update": {
+			ID: cppki.TRCID{ISD: 1, Base: 1, Serial: 3},
+			Provider: func(ctrl *gomock.Controller) trust.Provider {
+				p := mock_trust.NewMockProvider(ctrl)
+				// The update is fetched from the announcing server.
+				p.EXPECT().NotifyTRC(gomock.Any(), cppki.TRCID{ISD: 1, Base: 1, Serial: 3},
+					trust.OptionsMatcher{Server: server})
+				return p
+			},
+			ErrAssertion: assert.NoError,
+		},
+		"update fails": {
+			ID: cppki.TRCID{ISD: 1, Base: 1, Serial: 3},
+			Provider: func(ctrl *gomock.Controller) trust.Provider {
+				p := mock_trust.NewMockProvider(ctrl)
+				p.EXPECT().NotifyTRC(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(serrors.New("verification failed"))
+				return p
+			},
+			ErrAssertion: assert.Error,
+		},
+	}
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			db := mock_trust.NewMockDB(ctrl)
+			db.EXPECT().SignedTRC(gomock.Any(), gomock.Any()).Return(local, nil).AnyTimes()
+			h := trust.TRCAnnouncementHandler{
+				ISD:      1,
+				DB:       db,
+				Provider: tc.Provider(ctrl),
+			}
+			err := h.HandleTRCAnnouncement(context.Background(), tc.ID, server)
+			tc.ErrAssertion(t, err)
+		})
+	}
+}
+
+func TestAsyncTRCAnnouncementHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	local := cppki.SignedTRC{TRC: cppki.TRC{ID: cppki.TRCID{ISD: 1, Base: 1, Serial: 2}}}
+	server := &snet.SVCAddr{IA: xtest.MustParseIA("1-ff00:0:110"), SVC: addr.SvcCS}
+	id := cppki.TRCID{ISD: 1, Base: 1, Serial: 3}
+
+	db := mock_trust.NewMockDB(ctrl)
+	db.EXPECT().SignedTRC(gomock.Any(), gomock.Any()).Return(local, nil).AnyTimes()
+	started, release, handled := make(chan struct{}), make(chan struct{}), make(chan struct{})
+	provider := mock_trust.NewMockProvider(ctrl)
+	// The update is fetched once, even though it is announced repeatedly.
+	provider.EXPECT().NotifyTRC(gomock.Any(), id, trust.OptionsMatcher{Server: server}).
+		DoAndReturn(func(context.Context, cppki.TRCID, ...trust.Option) error {
+			close(started)
+			<-release
+			return nil
+		})
+	provider.EXPECT().NotifyTRC(gomock.Any(), cppki.TRCID{ISD: 1, Base: 1, Serial: 4},
+		gomock.Any()).DoAndReturn(func(context.Context, cppki.TRCID, ...trust.Option) error {
+		close(handled)
+		return nil
+	})
+
+	h := trust.NewAsyncTRCAnnouncementHandler(trust.TRCAnnouncementHandler{
+		ISD:      1,
+		DB:       db,
+		Provider: provider,
+	}, 0, 0)
+	defer h.Close()
+
+	// The caller is not blocked while the update is fetched.
+	require.NoError(t, h.HandleTRCAnnouncement(context.Background(), id, server))
+	<-started
+	for i := 0; i < 3; i++ {
+		require.NoError(t, h.HandleTRCAnnouncement(context.Background(), id, server))
+	}
+	require.NoError(t, h.HandleTRCAnnouncement(context.Background(),
+		cppki.TRCID{ISD: 1, Base: 1, Serial: 4}, server))
+	close(release)
+	select {
+	case <-handled:
+	case <-time.After(time.Second):
+		t.Fatal("announcement not handled")
+	}
+}
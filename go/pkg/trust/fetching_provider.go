@@ -25,6 +25,7 @@ import (
 	opentracingext "github.com/opentracing/opentracing-go/ext"
 
 	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/infra/modules/upstream"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/scrypto"
 	"github.com/scionproto/scion/go/lib/scrypto/cppki"
@@ -54,6 +55,10 @@ type FetchingProvider struct {
 	Recurser Recurser
 	Fetcher  Fetcher
 	Router   Router
+	// Balancer spreads the requests across the instances of the remote
+	// control service. If it is nil, the requests are sent to the server
+	// chosen by the router.
+	Balancer *upstream.Balancer
 }
 
 // GetChains returns certificate chains that match the chain query. If no chain
@@ -135,7 +140,7 @@ func (p FetchingProvider) GetChains(ctx context.Context, query ChainQuery,
 			return nil, serrors.WrapStr("choosing server", err)
 		}
 	}
-	if chains, err = p.Fetcher.Chains(ctx, query, o.server); err != nil {
+	if chains, err = p.fetchChains(ctx, query, o.server); err != nil {
 		setProviderMetric(span, l.WithResult(metrics.ErrInternal), err)
 		return nil, serrors.WrapStr("fetching chains from remote", err, "server", o.server)
 	}
@@ -220,7 +225,7 @@ func (p FetchingProvider) NotifyTRC(ctx context.Context, id cppki.TRCID, opts ..
 	// fetching should be fine here.
 	for serial := trc.TRC.ID.Serial + 1; serial <= id.Serial; serial++ {
 		toFetch := cppki.TRCID{ISD: id.ISD, Base: id.Base, Serial: serial}
-		fetched, err := p.Fetcher.TRC(ctx, toFetch, o.server)
+		fetched, err := p.fetchTRC(ctx, toFetch, o.server)
 		if err != nil {
 			setProviderMetric(span, l.WithResult(metrics.ErrInternal), err)
 			return serrors.WrapStr("resolving TRC update", err, "id", toFetch)
@@ -238,6 +243,34 @@ func (p FetchingProvider) NotifyTRC(ctx context.Context, id cppki.TRCID, opts ..
 	return nil
 }
 
+func (p FetchingProvider) fetchChains(ctx context.Context, query ChainQuery,
+	server net.Addr) ([][]*x509.Certificate, error) {
+
+	reply, _, err := p.Balancer.Do(ctx, server,
+		func(ctx context.Context, server net.Addr) (interface{}, error) {
+			return p.Fetcher.Chains(ctx, query, server)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return reply.([][]*x509.Certificate), nil
+}
+
+func (p FetchingProvider) fetchTRC(ctx context.Context, id cppki.TRCID,
+	server net.Addr) (cppki.SignedTRC, error) {
+
+	reply, _, err := p.Balancer.Do(ctx, server,
+		func(ctx context.Context, server net.Addr) (interface{}, error) {
+			return p.Fetcher.TRC(ctx, id, server)
+		},
+	)
+	if err != nil {
+		return cppki.SignedTRC{}, err
+	}
+	return reply.(cppki.SignedTRC), nil
+}
+
 func activeTRCs(ctx context.Context, db DB, isd addr.ISD) ([]cppki.SignedTRC, string, error) {
 	trc, err := db.SignedTRC(ctx, cppki.TRCID{
 		ISD:    isd,
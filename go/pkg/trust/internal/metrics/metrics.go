@@ -45,6 +45,7 @@ const (
 	SigVerification = "signature_verification"
 	ASInspector     = "trc_inspection"
 	App             = "application"
+	TRCAnnouncement = "trc_announcement"
 )
 
 // Result types
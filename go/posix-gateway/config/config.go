@@ -30,6 +30,7 @@ type Config struct {
 	Daemon   env.SCIONDClient      `toml:"sciond_connection,omitempty"`
 	Gateway  gatewayconfig.Gateway `toml:"gateway,omitempty"`
 	Tunnel   gatewayconfig.Tunnel  `toml:"tunnel,omitempty"`
+	BGP      gatewayconfig.BGP     `toml:"bgp,omitempty"`
 }
 
 func (cfg *Config) InitDefaults() {
@@ -40,6 +41,7 @@ func (cfg *Config) InitDefaults() {
 		&cfg.Daemon,
 		&cfg.Gateway,
 		&cfg.Tunnel,
+		&cfg.BGP,
 	)
 }
 
@@ -51,6 +53,7 @@ func (cfg *Config) Validate() error {
 		&cfg.Daemon,
 		&cfg.Gateway,
 		&cfg.Tunnel,
+		&cfg.BGP,
 	)
 }
 
@@ -62,5 +65,6 @@ func (cfg *Config) Sample(dst io.Writer, path config.Path, _ config.CtxMap) {
 		&cfg.Daemon,
 		&cfg.Gateway,
 		&cfg.Tunnel,
+		&cfg.BGP,
 	)
 }
@@ -43,6 +43,7 @@ func InitConfig(cfg *config.Config) {
 	logtest.InitTestLogging(&cfg.Logging)
 	configtest.InitGateway(&cfg.Gateway)
 	configtest.InitTunnel(&cfg.Tunnel)
+	configtest.InitBGP(&cfg.BGP)
 }
 
 func CheckConfig(t *testing.T, cfg *config.Config) {
@@ -50,4 +51,5 @@ func CheckConfig(t *testing.T, cfg *config.Config) {
 	logtest.CheckTestLogging(t, &cfg.Logging, "gateway")
 	configtest.CheckGateway(t, &cfg.Gateway)
 	configtest.CheckTunnel(t, &cfg.Tunnel)
+	configtest.CheckBGP(t, &cfg.BGP)
 }
@@ -26,12 +26,14 @@ import (
 	"github.com/scionproto/scion/go/lib/fatal"
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/routemgr"
+	"github.com/scionproto/scion/go/lib/routemgr/bgp"
 	"github.com/scionproto/scion/go/lib/sciond"
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/snet/addrutil"
 	"github.com/scionproto/scion/go/lib/sock/reliable"
 	"github.com/scionproto/scion/go/pkg/app/launcher"
 	"github.com/scionproto/scion/go/pkg/gateway"
+	gatewayconfig "github.com/scionproto/scion/go/pkg/gateway/config"
 	"github.com/scionproto/scion/go/pkg/gateway/xnet"
 	"github.com/scionproto/scion/go/pkg/service"
 	"github.com/scionproto/scion/go/posix-gateway/config"
@@ -90,7 +92,11 @@ func realMain() error {
 		"config":    service.NewConfigHandler(globalCfg),
 		"log/level": log.ConsoleLevel.ServeHTTP,
 	}
-	routePublisherFactory, routeConsumerFactory := createRouteManager(tunnelLink)
+	routePublisherFactory, routeConsumerFactory, err := createRouteManager(tunnelLink,
+		globalCfg.BGP)
+	if err != nil {
+		return serrors.WrapStr("creating route manager", err)
+	}
 	gw := &gateway.Gateway{
 		TrafficPolicyFile:        globalCfg.Gateway.TrafficPolicy,
 		RoutingPolicyFile:        globalCfg.Gateway.IPRoutingPolicy,
@@ -102,6 +108,7 @@ func realMain() error {
 		ProbeClientIP:            controlAddress.IP,
 		DataServerAddr:           dataAddress,
 		DataClientIP:             dataAddress.IP,
+		TunnelRekeyInterval:      globalCfg.Gateway.RekeyInterval.Duration,
 		Dispatcher:               reliable.NewDispatcher(""),
 		Daemon:                   daemon,
 		InternalDevice:           tunnelIO,
@@ -138,11 +145,55 @@ func realMain() error {
 	}
 }
 
-func createRouteManager(device netlink.Link) (routemgr.PublisherFactory, routemgr.ConsumerFactory) {
+func createRouteManager(device netlink.Link,
+	bgpCfg gatewayconfig.BGP) (routemgr.PublisherFactory, routemgr.ConsumerFactory, error) {
+
 	linux := &routemgr.Linux{Device: device}
 	go func() {
 		defer log.HandlePanic()
 		linux.Run()
 	}()
-	return linux, &routemgr.Dummy{}
+	if !bgpCfg.Enabled() {
+		return linux, &routemgr.Dummy{}, nil
+	}
+	speaker, err := createBGPSpeaker(bgpCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	go func() {
+		defer log.HandlePanic()
+		if err := speaker.Run(); err != nil {
+			fatal.Fatal(serrors.WrapStr("running BGP speaker", err))
+		}
+	}()
+	return routemgr.MultiPublisherFactory{linux, speaker}, speaker, nil
+}
+
+func createBGPSpeaker(cfg gatewayconfig.BGP) (*bgp.Speaker, error) {
+	speaker := &bgp.Speaker{
+		LocalAS:     cfg.LocalAS,
+		RouterID:    cfg.RouterID,
+		HoldTime:    cfg.HoldTime.Duration,
+		NextHopIPv4: cfg.NextHopIPv4,
+		NextHopIPv6: cfg.NextHopIPv6,
+	}
+	if cfg.ListenAddr != "" {
+		listenAddr, err := net.ResolveTCPAddr("tcp", cfg.ListenAddr)
+		if err != nil {
+			return nil, serrors.WrapStr("parsing BGP listen address", err)
+		}
+		speaker.ListenAddr = listenAddr
+	}
+	for _, n := range cfg.Neighbors {
+		address, err := net.ResolveTCPAddr("tcp", n.Address)
+		if err != nil {
+			return nil, serrors.WrapStr("parsing BGP neighbor address", err)
+		}
+		speaker.Neighbors = append(speaker.Neighbors, bgp.Neighbor{
+			Address: address,
+			AS:      n.AS,
+			Passive: n.Passive,
+		})
+	}
+	return speaker, nil
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/pkg/app/launcher"
+	"github.com/scionproto/scion/go/pkg/linklatency"
 	"github.com/scionproto/scion/go/pkg/router"
 	"github.com/scionproto/scion/go/pkg/router/config"
 	"github.com/scionproto/scion/go/pkg/router/control"
@@ -53,6 +54,12 @@ func realMain() error {
 			Metrics: metrics,
 		},
 	}
+	if err := dp.DataPlane.SetRateLimits(globalCfg.RateLimit); err != nil {
+		return serrors.WrapStr("setting rate limits", err)
+	}
+	if err := dp.DataPlane.SetLinkLatency(globalCfg.LinkLatency); err != nil {
+		return serrors.WrapStr("setting link latency measurements", err)
+	}
 	iaCtx := &control.IACtx{
 		Config: controlConfig,
 		DP:     dp,
@@ -61,7 +68,7 @@ func realMain() error {
 	if err := iaCtx.Start(wg); err != nil {
 		return serrors.WrapStr("starting dataplane", err)
 	}
-	if err := setupHTTPHandlers(); err != nil {
+	if err := setupHTTPHandlers(&dp.DataPlane); err != nil {
 		return serrors.WrapStr("starting HTTP endpoints", err)
 	}
 
@@ -99,11 +106,12 @@ func loadControlConfig() (*control.Config, error) {
 	return newConf, nil
 }
 
-func setupHTTPHandlers() error {
+func setupHTTPHandlers(dp *router.DataPlane) error {
 	statusPages := service.StatusPages{
-		"info":      service.NewInfoHandler(),
-		"config":    service.NewConfigHandler(globalCfg),
-		"log/level": log.ConsoleLevel.ServeHTTP,
+		"info":         service.NewInfoHandler(),
+		"config":       service.NewConfigHandler(globalCfg),
+		"log/level":    log.ConsoleLevel.ServeHTTP,
+		"link_latency": linklatency.NewHandler(dp),
 		// TODO: Add topology page
 	}
 	if err := statusPages.Register(http.DefaultServeMux, globalCfg.General.ID); err != nil {
@@ -0,0 +1,139 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/scionproto/scion/go/cs/beacon"
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/pathdb"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/pkg/storage"
+	"github.com/scionproto/scion/go/pkg/storage/snapshot"
+	"github.com/scionproto/scion/go/pkg/trust"
+)
+
+// dbFlags are the flags that select the database.
+type dbFlags struct {
+	db         string
+	connection string
+	isdAS      string
+}
+
+func (f *dbFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.db, "db", "",
+		"Database type (beacon|path|trust)")
+	cmd.Flags().StringVar(&f.connection, "connection", "",
+		"Connection string of the database, e.g., the path of the sqlite file")
+	cmd.Flags().StringVar(&f.isdAS, "isd-as", "",
+		"ISD-AS of the control service that owns the beacon database")
+	cmd.MarkFlagRequired("db")
+	cmd.MarkFlagRequired("connection")
+}
+
+func (f *dbFlags) validate() error {
+	switch snapshot.DB(f.db) {
+	case snapshot.DBBeacon:
+		if f.isdAS == "" {
+			return serrors.New("--isd-as is required for the beacon database")
+		}
+		if _, err := addr.IAFromString(f.isdAS); err != nil {
+			return serrors.WrapStr("parsing ISD-AS", err)
+		}
+	case snapshot.DBPath, snapshot.DBTrust:
+	default:
+		return serrors.New("unknown database type", "db", f.db)
+	}
+	return nil
+}
+
+// open opens the selected database. The flags must be validated.
+func (f *dbFlags) open() (*databases, error) {
+	cfg := storage.DBConfig{Connection: f.connection}
+	var dbs databases
+	var err error
+	switch snapshot.DB(f.db) {
+	case snapshot.DBBeacon:
+		ia, _ := addr.IAFromString(f.isdAS)
+		dbs.beacon, err = storage.NewBeaconStorage(cfg, ia)
+	case snapshot.DBPath:
+		dbs.path, err = storage.NewPathStorage(cfg)
+	case snapshot.DBTrust:
+		dbs.trust, err = storage.NewTrustStorage(cfg)
+	}
+	if err != nil {
+		return nil, serrors.WrapStr("opening database", err, "db", f.db,
+			"connection", f.connection)
+	}
+	return &dbs, nil
+}
+
+// databases holds the opened database. Exactly one of the fields is set.
+type databases struct {
+	beacon beacon.DB
+	path   pathdb.PathDB
+	trust  storage.TrustDB
+}
+
+func (d *databases) Close() error {
+	switch {
+	case d.beacon != nil:
+		return d.beacon.Close()
+	case d.path != nil:
+		return d.path.Close()
+	case d.trust != nil:
+		return d.trust.Close()
+	}
+	return nil
+}
+
+// noRecursion prevents the trust engine from fetching missing certificate
+// chains over the network. Only the chains in the trust database are used.
+type noRecursion struct{}
+
+func (noRecursion) AllowRecursion(net.Addr) error {
+	return trust.ErrRecursionNotAllowed
+}
+
+func printStats(w io.Writer, stats snapshot.Stats) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Database:\t%s\n", stats.DB)
+	printCounts(tw, "Records", stats.Records)
+	printCounts(tw, "Segments", stats.Segments)
+	printCounts(tw, "Rejected", stats.Rejected)
+	tw.Flush()
+}
+
+func printCounts(w io.Writer, title string, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Fprintf(w, "%s:\n", title)
+	for _, k := range keys {
+		fmt.Fprintf(w, "  %s:\t%d\n", k, counts[k])
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/pkg/storage/snapshot"
+)
+
+func newExport(pather CommandPather) *cobra.Command {
+	var flags dbFlags
+
+	var cmd = &cobra.Command{
+		Use:   "export [flags] <file>",
+		Short: "Export a database to a snapshot",
+		Example: fmt.Sprintf(`  %[1]s export --db path --connection cs.path.db path.snapshot
+  %[1]s export --db beacon --connection cs.beacon.db --isd-as 1-ff00:0:110 -
+  %[1]s export --db trust --connection cs.trust.db trust.snapshot`, pather.CommandPath()),
+		Long: `'export' writes a snapshot of all entries in the database to the file.
+If the file is '-', the snapshot is written to stdout.
+
+The snapshot contains one JSON object per line. The first line is a header that
+contains the format version and the database type. Path segments, TRCs and
+certificate chains are stored in their raw encoding, such that their signatures
+can be verified when the snapshot is imported.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := flags.validate(); err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			dbs, err := flags.open()
+			if err != nil {
+				return err
+			}
+			defer dbs.Close()
+
+			var w io.Writer = os.Stdout
+			report := os.Stdout
+			if args[0] != "-" {
+				file, err := os.Create(args[0])
+				if err != nil {
+					return serrors.WrapStr("creating snapshot file", err)
+				}
+				defer file.Close()
+				w = file
+			} else {
+				// Keep stdout clean for the snapshot.
+				report = os.Stderr
+			}
+			buf := bufio.NewWriter(w)
+
+			ctx := context.Background()
+			var stats snapshot.Stats
+			switch {
+			case dbs.beacon != nil:
+				stats, err = snapshot.ExportBeacons(ctx, dbs.beacon, buf)
+			case dbs.path != nil:
+				stats, err = snapshot.ExportPaths(ctx, dbs.path, buf)
+			case dbs.trust != nil:
+				stats, err = snapshot.ExportTrust(ctx, dbs.trust, buf)
+			}
+			if err != nil {
+				return serrors.WrapStr("exporting database", err)
+			}
+			if err := buf.Flush(); err != nil {
+				return serrors.WrapStr("writing snapshot", err)
+			}
+			printStats(report, stats)
+			return nil
+		},
+	}
+
+	flags.register(cmd)
+	return cmd
+}
@@ -0,0 +1,173 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/scrypto/cppki"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/pkg/storage"
+	"github.com/scionproto/scion/go/pkg/storage/snapshot"
+	"github.com/scionproto/scion/go/pkg/trust"
+)
+
+func newImport(pather CommandPather) *cobra.Command {
+	var flags struct {
+		dbFlags
+		trustDB          string
+		skipVerification bool
+		trustAnchors     []string
+	}
+
+	var cmd = &cobra.Command{
+		Use:   "import [flags] <file>",
+		Short: "Import a snapshot into a database",
+		Example: fmt.Sprintf(`  %[1]s import --db path --connection cs.path.db \
+    --trust-db cs.trust.db path.snapshot
+  %[1]s import --db beacon --connection cs.beacon.db --isd-as 1-ff00:0:110 \
+    --skip-verification beacon.snapshot
+  %[1]s import --db trust --connection cs.trust.db \
+    --trust-anchor ISD1-B1-S1.trc trust.snapshot`, pather.CommandPath()),
+		Long: `'import' reads the snapshot from the file and inserts the entries into the
+database. If the file is '-', the snapshot is read from stdin. The snapshot
+must be taken from the same type of database.
+
+The signatures of path segments and beacons are verified with the certificate
+chains in the trust database that is specified with --trust-db. Entries that
+cannot be verified are rejected. The verification can be skipped with
+--skip-verification, e.g., if the snapshot is from a trusted source.
+
+The TRCs and certificate chains in trust database snapshots are always
+verified. Base TRCs are trust anchors and are only imported if they are already
+in the database or are specified with --trust-anchor. TRC updates must verify
+against their predecessor, and certificate chains must verify against a TRC of
+their ISD. The predecessors and TRCs are taken from the snapshot or from the
+database.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := flags.validate(); err != nil {
+				return err
+			}
+			segments := snapshot.DB(flags.db) != snapshot.DBTrust
+			if segments && flags.trustDB == "" && !flags.skipVerification {
+				return serrors.New("either --trust-db or --skip-verification is required")
+			}
+			if flags.trustDB != "" && flags.skipVerification {
+				return serrors.New("--trust-db and --skip-verification are mutually exclusive")
+			}
+			if segments && len(flags.trustAnchors) > 0 {
+				return serrors.New("--trust-anchor is only supported for the trust database")
+			}
+			anchors, err := loadTRCs(flags.trustAnchors)
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			var r io.Reader = os.Stdin
+			if args[0] != "-" {
+				file, err := os.Open(args[0])
+				if err != nil {
+					return serrors.WrapStr("opening snapshot file", err)
+				}
+				defer file.Close()
+				r = file
+			}
+			r = bufio.NewReader(r)
+
+			var verifier seg.Verifier
+			if segments && flags.trustDB != "" {
+				trustDB, err := storage.NewTrustStorage(storage.DBConfig{
+					Connection: flags.trustDB,
+				})
+				if err != nil {
+					return serrors.WrapStr("opening trust database", err,
+						"connection", flags.trustDB)
+				}
+				defer trustDB.Close()
+				verifier = trust.Verifier{
+					Engine: trust.FetchingProvider{
+						DB:       trustDB,
+						Recurser: noRecursion{},
+					},
+				}
+			}
+
+			dbs, err := flags.open()
+			if err != nil {
+				return err
+			}
+			defer dbs.Close()
+
+			ctx := context.Background()
+			var stats snapshot.Stats
+			switch {
+			case dbs.beacon != nil:
+				stats, err = snapshot.ImportBeacons(ctx, dbs.beacon, verifier, r)
+			case dbs.path != nil:
+				stats, err = snapshot.ImportPaths(ctx, dbs.path, verifier, r)
+			case dbs.trust != nil:
+				stats, err = snapshot.ImportTrust(ctx, dbs.trust, anchors, r)
+			}
+			// Report the entries that were imported before a failure.
+			if stats.Records != nil {
+				printStats(os.Stdout, stats)
+			}
+			if err != nil {
+				return serrors.WrapStr("importing snapshot", err)
+			}
+			return nil
+		},
+	}
+
+	flags.register(cmd)
+	cmd.Flags().StringVar(&flags.trustDB, "trust-db", "",
+		"Connection string of the trust database used to verify segment signatures")
+	cmd.Flags().BoolVar(&flags.skipVerification, "skip-verification", false,
+		"Import path segments and beacons without verifying their signatures")
+	cmd.Flags().StringSliceVar(&flags.trustAnchors, "trust-anchor", nil,
+		"TRC files that are accepted as base TRCs in addition to the ones in the database")
+	return cmd
+}
+
+// loadTRCs loads the TRCs from the files. The files can be DER or PEM encoded.
+func loadTRCs(files []string) ([]cppki.SignedTRC, error) {
+	trcs := make([]cppki.SignedTRC, 0, len(files))
+	for _, file := range files {
+		raw, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, serrors.WrapStr("reading TRC", err, "file", file)
+		}
+		if block, _ := pem.Decode(raw); block != nil && block.Type == "TRC" {
+			raw = block.Bytes
+		}
+		trc, err := cppki.DecodeSignedTRC(raw)
+		if err != nil {
+			return nil, serrors.WrapStr("decoding TRC", err, "file", file)
+		}
+		trcs = append(trcs, trc)
+	}
+	return trcs, nil
+}
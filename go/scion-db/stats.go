@@ -0,0 +1,60 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/pkg/storage/snapshot"
+)
+
+func newStats(pather CommandPather) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "stats <file>",
+		Short: "Show statistics about a snapshot",
+		Example: fmt.Sprintf(`  %[1]s stats path.snapshot
+  %[1]s export --db path --connection cs.path.db - | %[1]s stats -`, pather.CommandPath()),
+		Long: `'stats' reads the snapshot from the file and shows the database type and the
+number of records per type. For path database snapshots, the number of segments
+per segment type is shown as well. If the file is '-', the snapshot is read
+from stdin.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			var r io.Reader = os.Stdin
+			if args[0] != "-" {
+				file, err := os.Open(args[0])
+				if err != nil {
+					return serrors.WrapStr("opening snapshot file", err)
+				}
+				defer file.Close()
+				r = file
+			}
+			stats, err := snapshot.ReadStats(bufio.NewReader(r))
+			if err != nil {
+				return serrors.WrapStr("reading snapshot", err)
+			}
+			printStats(os.Stdout, stats)
+			return nil
+		},
+	}
+	return cmd
+}
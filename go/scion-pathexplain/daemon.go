@@ -0,0 +1,102 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/serrors"
+	libgrpc "github.com/scionproto/scion/go/pkg/grpc"
+	sdpb "github.com/scionproto/scion/go/pkg/proto/daemon"
+)
+
+func newDaemon(pather CommandPather) *cobra.Command {
+	var flags struct {
+		sciond  string
+		timeout time.Duration
+		refresh bool
+		json    bool
+	}
+
+	var cmd = &cobra.Command{
+		Use:   "daemon [flags] <dst-isd-as>",
+		Short: "Explain the path construction of a running SCION Daemon",
+		Example: fmt.Sprintf(`  %[1]s daemon 1-ff00:0:110
+  %[1]s daemon --sciond 127.0.0.19:30255 --refresh 1-ff00:0:110`, pather.CommandPath()),
+		Long: `'daemon' asks the SCION Daemon to explain how it constructs the paths to the
+destination. The daemon fetches the segments like for a regular path request,
+and reports all candidate paths together with the reason why they are
+filtered.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dst, err := addr.IAFromString(args[0])
+			if err != nil {
+				return serrors.WrapStr("invalid destination ISD-AS", err)
+			}
+			cmd.SilenceUsage = true
+
+			ctx, cancelF := context.WithTimeout(context.Background(), flags.timeout)
+			defer cancelF()
+			daemonAddr, err := net.ResolveTCPAddr("tcp", flags.sciond)
+			if err != nil {
+				return serrors.WrapStr("resolving SCION Daemon address", err)
+			}
+			conn, err := libgrpc.SimpleDialer{}.Dial(ctx, daemonAddr)
+			if err != nil {
+				return serrors.WrapStr("connecting to SCION Daemon", err)
+			}
+			defer conn.Close()
+			client := sdpb.NewDaemonServiceClient(conn)
+			asInfo, err := client.AS(ctx, &sdpb.ASRequest{})
+			if err != nil {
+				return serrors.WrapStr("determining local ISD-AS", err)
+			}
+			rep, err := client.ExplainPaths(ctx, &sdpb.ExplainPathsRequest{
+				SourceIsdAs:      asInfo.IsdAs,
+				DestinationIsdAs: uint64(dst.IAInt()),
+				Refresh:          flags.refresh,
+			})
+			if err != nil {
+				return serrors.WrapStr("explaining paths", err)
+			}
+			res, err := fromPB(addr.IAInt(asInfo.IsdAs).IA(), dst, rep)
+			if err != nil {
+				return err
+			}
+			if flags.json {
+				return res.JSON(os.Stdout)
+			}
+			res.Human(os.Stdout)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.sciond, "sciond", sciond.DefaultAPIAddress,
+		"SCION Daemon address")
+	cmd.Flags().DurationVar(&flags.timeout, "timeout", 5*time.Second, "Timeout")
+	cmd.Flags().BoolVarP(&flags.refresh, "refresh", "r", false,
+		"Fetch fresh segments instead of using the cached ones")
+	cmd.Flags().BoolVarP(&flags.json, "json", "j", false,
+		"Write the output as machine readable json")
+	return cmd
+}
@@ -0,0 +1,180 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
+	"github.com/scionproto/scion/go/lib/pathdb"
+	"github.com/scionproto/scion/go/lib/pathdb/query"
+	"github.com/scionproto/scion/go/lib/revcache"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/pkg/storage"
+	"github.com/scionproto/scion/go/pkg/storage/snapshot"
+)
+
+func newDB(pather CommandPather) *cobra.Command {
+	var flags struct {
+		isdAS    string
+		pathDB   string
+		snapshot string
+		time     string
+		json     bool
+	}
+
+	var cmd = &cobra.Command{
+		Use:   "db [flags] <dst-isd-as>",
+		Short: "Explain the path construction from a path database or snapshot",
+		Example: fmt.Sprintf(`  %[1]s db --isd-as 1-ff00:0:111 --path-db sd.path.db 1-ff00:0:110
+  %[1]s db --isd-as 1-ff00:0:111 --snapshot path.snapshot \
+    --time 2021-03-01T12:00:00Z 1-ff00:0:110`, pather.CommandPath()),
+		Long: `'db' constructs the paths from the local AS to the destination offline, with
+the segments in a path database or in a path database snapshot taken with
+scion-db. It reports all candidate paths together with the reason why they are
+filtered.
+
+With --path-db, the revocations stored in the path database are considered.
+Snapshots do not contain revocations. The expiration of the paths is evaluated
+at the time given with --time, which defaults to the current time.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dst, err := addr.IAFromString(args[0])
+			if err != nil {
+				return serrors.WrapStr("invalid destination ISD-AS", err)
+			}
+			src, err := addr.IAFromString(flags.isdAS)
+			if err != nil {
+				return serrors.WrapStr("invalid local ISD-AS", err)
+			}
+			if (flags.pathDB == "") == (flags.snapshot == "") {
+				return serrors.New("exactly one of --path-db and --snapshot is required")
+			}
+			now := time.Now()
+			if flags.time != "" {
+				if now, err = time.Parse(time.RFC3339, flags.time); err != nil {
+					return serrors.WrapStr("parsing time", err)
+				}
+			}
+			cmd.SilenceUsage = true
+
+			ctx := context.Background()
+			var db pathdb.PathDB
+			var revCache revcache.RevCache
+			if flags.pathDB != "" {
+				if db, err = storage.NewPathStorage(storage.DBConfig{
+					Connection: flags.pathDB,
+				}); err != nil {
+					return serrors.WrapStr("opening path database", err,
+						"connection", flags.pathDB)
+				}
+				revCache = storage.NewRevocationStorage(db)
+			} else {
+				if db, err = loadSnapshot(ctx, flags.snapshot); err != nil {
+					return err
+				}
+			}
+			defer db.Close()
+
+			segs, err := loadSegments(ctx, db)
+			if err != nil {
+				return err
+			}
+			res := fromExplanation(segfetcher.ExplainPaths(ctx, src, dst, segs, revCache, now))
+			if flags.json {
+				return res.JSON(os.Stdout)
+			}
+			res.Human(os.Stdout)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.isdAS, "isd-as", "", "ISD-AS of the local AS")
+	cmd.Flags().StringVar(&flags.pathDB, "path-db", "",
+		"Connection string of the path database, e.g., the path of the sqlite file")
+	cmd.Flags().StringVar(&flags.snapshot, "snapshot", "",
+		"File of the path database snapshot")
+	cmd.Flags().StringVar(&flags.time, "time", "",
+		"Time at which the expiration is evaluated, in RFC 3339 format")
+	cmd.Flags().BoolVarP(&flags.json, "json", "j", false,
+		"Write the output as machine readable json")
+	cmd.MarkFlagRequired("isd-as")
+	return cmd
+}
+
+// loadSnapshot imports the path database snapshot into an in-memory path
+// database. The segments are not verified.
+func loadSnapshot(ctx context.Context, file string) (pathdb.PathDB, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, serrors.WrapStr("opening snapshot file", err)
+	}
+	defer f.Close()
+	db, err := storage.NewPathStorage(storage.DBConfig{Connection: "file::memory:"})
+	if err != nil {
+		return nil, serrors.WrapStr("creating in-memory path database", err)
+	}
+	if _, err := snapshot.ImportPaths(ctx, db, nil, bufio.NewReader(f)); err != nil {
+		db.Close()
+		return nil, serrors.WrapStr("importing snapshot", err, "file", file)
+	}
+	return db, nil
+}
+
+// loadSegments returns the public segments in the path database. Hidden
+// segments are not used for path construction in the SCION Daemon.
+func loadSegments(ctx context.Context, db pathdb.Read) (segfetcher.Segments, error) {
+	results, err := db.GetAll(ctx)
+	if err != nil {
+		return nil, serrors.WrapStr("reading path segments", err)
+	}
+	var segs segfetcher.Segments
+	var errs serrors.List
+	for res := range results {
+		// The channel must be drained, hence errors are only collected.
+		if res.Err != nil {
+			errs = append(errs, res.Err)
+			continue
+		}
+		if !isPublic(res.Result) {
+			continue
+		}
+		segs = append(segs, &seg.Meta{Segment: res.Result.Seg, Type: res.Result.Type})
+	}
+	if err := errs.ToError(); err != nil {
+		return nil, serrors.WrapStr("reading path segments", err)
+	}
+	return segs, nil
+}
+
+func isPublic(res *query.Result) bool {
+	if len(res.HpCfgIDs) == 0 {
+		return true
+	}
+	for _, id := range res.HpCfgIDs {
+		if id.Equal(&query.NullHpCfgID) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,333 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/common"
+	"github.com/scionproto/scion/go/lib/ctrl/seg"
+	"github.com/scionproto/scion/go/lib/infra/modules/segfetcher"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+	sdpb "github.com/scionproto/scion/go/pkg/proto/daemon"
+)
+
+// Result describes how the paths from the source to the destination are
+// constructed.
+type Result struct {
+	Source      addr.IA     `json:"source"`
+	Destination addr.IA     `json:"destination"`
+	FetchError  string      `json:"fetch_error,omitempty"`
+	Segments    []Segment   `json:"segments"`
+	Candidates  []Candidate `json:"candidates"`
+}
+
+// Segment is a segment that is available for path construction.
+type Segment struct {
+	ID     string    `json:"id"`
+	Type   string    `json:"type"`
+	Hops   string    `json:"hops"`
+	Expiry time.Time `json:"expiry"`
+}
+
+// Candidate is a path that was found in the segment graph.
+type Candidate struct {
+	Destination addr.IA            `json:"destination"`
+	Filtered    string             `json:"filtered,omitempty"`
+	Revoked     []Hop              `json:"revoked,omitempty"`
+	Segments    []CandidateSegment `json:"segments"`
+	Path        Path               `json:"path"`
+}
+
+// CandidateSegment describes how a segment is used in a candidate path.
+type CandidateSegment struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Shortcut int    `json:"shortcut,omitempty"`
+	Peer     int    `json:"peer,omitempty"`
+}
+
+// Path holds the metadata of a candidate path.
+type Path struct {
+	Hops         []Hop           `json:"hops"`
+	Expiry       time.Time       `json:"expiry"`
+	MTU          uint16          `json:"mtu"`
+	Latency      []time.Duration `json:"latency,omitempty"`
+	Bandwidth    []uint64        `json:"bandwidth,omitempty"`
+	InternalHops []uint32        `json:"internal_hops,omitempty"`
+	Notes        []string        `json:"notes,omitempty"`
+}
+
+// Hop represents an hop on the path.
+type Hop struct {
+	IfID common.IFIDType `json:"ifid"`
+	IA   addr.IA         `json:"isd_as"`
+}
+
+func (h Hop) String() string {
+	return fmt.Sprintf("%s#%d", h.IA, h.IfID)
+}
+
+// fromExplanation converts the explanation of the local path construction.
+func fromExplanation(e *segfetcher.Explanation) Result {
+	r := Result{
+		Source:      e.Src,
+		Destination: e.Dst,
+		Segments:    []Segment{},
+		Candidates:  []Candidate{},
+	}
+	if e.FetchErr != nil {
+		r.FetchError = e.FetchErr.Error()
+	}
+	for _, s := range e.Segments {
+		r.Segments = append(r.Segments, segmentFromSeg(s.Type, s.Segment))
+	}
+	for _, c := range e.Candidates {
+		candidate := Candidate{
+			Destination: c.Dst,
+			Filtered:    string(c.Filtered),
+			Revoked:     hopsFromIntfs(c.Revoked),
+			Segments:    []CandidateSegment{},
+			Path:        pathFromMetadata(c.Path.Metadata),
+		}
+		for _, s := range c.Segments {
+			candidate.Segments = append(candidate.Segments, CandidateSegment{
+				ID:       loggingID(s.ID),
+				Type:     s.Type.String(),
+				Shortcut: s.Shortcut,
+				Peer:     s.Peer,
+			})
+		}
+		r.Candidates = append(r.Candidates, candidate)
+	}
+	return r
+}
+
+// fromPB converts the explanation returned by the daemon.
+func fromPB(src, dst addr.IA, rep *sdpb.ExplainPathsResponse) (Result, error) {
+	r := Result{
+		Source:      src,
+		Destination: dst,
+		FetchError:  rep.FetchError,
+		Segments:    []Segment{},
+		Candidates:  []Candidate{},
+	}
+	for i, s := range rep.Segments {
+		ps, err := seg.SegmentFromPB(s.Segment)
+		if err != nil {
+			return Result{}, serrors.WrapStr("parsing segment", err, "index", i)
+		}
+		r.Segments = append(r.Segments, segmentFromSeg(seg.Type(s.Type), ps))
+	}
+	for _, c := range rep.Candidates {
+		candidate := Candidate{
+			Destination: addr.IAInt(c.DestinationIsdAs).IA(),
+			Filtered:    c.FilterReason,
+			Revoked:     hopsFromPB(c.Revoked),
+			Segments:    []CandidateSegment{},
+			Path:        pathFromPB(c.Path),
+		}
+		for _, s := range c.Segments {
+			candidate.Segments = append(candidate.Segments, CandidateSegment{
+				ID:       loggingID(s.Id),
+				Type:     seg.Type(s.Type).String(),
+				Shortcut: int(s.Shortcut),
+				Peer:     int(s.Peer),
+			})
+		}
+		r.Candidates = append(r.Candidates, candidate)
+	}
+	return r, nil
+}
+
+func segmentFromSeg(t seg.Type, ps *seg.PathSegment) Segment {
+	hops := make([]string, 0, len(ps.ASEntries))
+	for _, entry := range ps.ASEntries {
+		hops = append(hops, entry.Local.String())
+	}
+	return Segment{
+		ID:     ps.GetLoggingID(),
+		Type:   t.String(),
+		Hops:   strings.Join(hops, ">"),
+		Expiry: ps.MaxExpiry(),
+	}
+}
+
+// loggingID returns the segment ID in the same format as
+// seg.PathSegment.GetLoggingID.
+func loggingID(id []byte) string {
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	return fmt.Sprintf("%x", id)
+}
+
+func hopsFromIntfs(intfs []snet.PathInterface) []Hop {
+	hops := make([]Hop, 0, len(intfs))
+	for _, intf := range intfs {
+		hops = append(hops, Hop{IA: intf.IA, IfID: intf.ID})
+	}
+	return hops
+}
+
+func hopsFromPB(intfs []*sdpb.PathInterface) []Hop {
+	hops := make([]Hop, 0, len(intfs))
+	for _, intf := range intfs {
+		hops = append(hops, Hop{
+			IA:   addr.IAInt(intf.IsdAs).IA(),
+			IfID: common.IFIDType(intf.Id),
+		})
+	}
+	return hops
+}
+
+func pathFromMetadata(meta snet.PathMetadata) Path {
+	return Path{
+		Hops:         hopsFromIntfs(meta.Interfaces),
+		Expiry:       meta.Expiry,
+		MTU:          meta.MTU,
+		Latency:      meta.Latency,
+		Bandwidth:    meta.Bandwidth,
+		InternalHops: meta.InternalHops,
+		Notes:        meta.Notes,
+	}
+}
+
+func pathFromPB(p *sdpb.Path) Path {
+	if p == nil {
+		return Path{Hops: []Hop{}}
+	}
+	latency := make([]time.Duration, 0, len(p.Latency))
+	for _, v := range p.Latency {
+		latency = append(latency, time.Second*time.Duration(v.Seconds)+time.Duration(v.Nanos))
+	}
+	var expiry time.Time
+	if p.Expiration != nil {
+		expiry = time.Unix(p.Expiration.Seconds, int64(p.Expiration.Nanos))
+	}
+	return Path{
+		Hops:         hopsFromPB(p.Interfaces),
+		Expiry:       expiry,
+		MTU:          uint16(p.Mtu),
+		Latency:      latency,
+		Bandwidth:    p.Bandwidth,
+		InternalHops: p.InternalHops,
+		Notes:        p.Notes,
+	}
+}
+
+// Human writes human readable output to the writer.
+func (r Result) Human(w io.Writer) {
+	fmt.Fprintf(w, "Explaining paths from %s to %s\n", r.Source, r.Destination)
+	if r.FetchError != "" {
+		fmt.Fprintf(w, "Fetching segments failed: %s\n", r.FetchError)
+	}
+
+	fmt.Fprintf(w, "\n%d Segments:\n", len(r.Segments))
+	for _, s := range r.Segments {
+		fmt.Fprintf(w, "  %-4s %s Hops: %s Expiry: %s\n", s.Type, s.ID, s.Hops,
+			s.Expiry.UTC().Format(time.RFC3339))
+	}
+
+	var paths int
+	for _, c := range r.Candidates {
+		if c.Filtered == "" {
+			paths++
+		}
+	}
+	fmt.Fprintf(w, "\n%d Candidates, %d Paths:\n", len(r.Candidates), paths)
+	var dst addr.IA
+	for i, c := range r.Candidates {
+		if i == 0 || !c.Destination.Equal(dst) {
+			dst = c.Destination
+			fmt.Fprintf(w, "Destination %s:\n", dst)
+		}
+		status := "ok"
+		if c.Filtered != "" {
+			status = "filtered (" + c.Filtered + ")"
+		}
+		fmt.Fprintf(w, "  [%d] %s\n", i, status)
+		fmt.Fprintf(w, "      Segments: %s\n", humanSegments(c.Segments))
+		fmt.Fprintf(w, "      Hops: %s\n", humanHops(c.Path.Hops))
+		if len(c.Revoked) > 0 {
+			fmt.Fprintf(w, "      Revoked: %s\n", humanHops(c.Revoked))
+		}
+		fmt.Fprintf(w, "      MTU: %d Expiry: %s\n", c.Path.MTU,
+			c.Path.Expiry.UTC().Format(time.RFC3339))
+		// Only show the beacon metadata that contains meaningful values.
+		var latency, bandwidth, internalHops, notes bool
+		for _, v := range c.Path.Latency {
+			latency = latency || v > 0
+		}
+		for _, v := range c.Path.Bandwidth {
+			bandwidth = bandwidth || v > 0
+		}
+		for _, v := range c.Path.InternalHops {
+			internalHops = internalHops || v > 0
+		}
+		for _, v := range c.Path.Notes {
+			notes = notes || v != ""
+		}
+		if latency {
+			fmt.Fprintf(w, "      Latency: %v\n", c.Path.Latency)
+		}
+		if bandwidth {
+			fmt.Fprintf(w, "      Bandwidth: %v\n", c.Path.Bandwidth)
+		}
+		if internalHops {
+			fmt.Fprintf(w, "      InternalHops: %v\n", c.Path.InternalHops)
+		}
+		if notes {
+			fmt.Fprintf(w, "      Notes: %q\n", c.Path.Notes)
+		}
+	}
+}
+
+func humanSegments(segments []CandidateSegment) string {
+	parts := make([]string, 0, len(segments))
+	for _, s := range segments {
+		part := s.Type + " " + s.ID
+		if s.Shortcut != 0 {
+			part += fmt.Sprintf(" shortcut=%d", s.Shortcut)
+		}
+		if s.Peer != 0 {
+			part += fmt.Sprintf(" peer=%d", s.Peer)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func humanHops(hops []Hop) string {
+	parts := make([]string, 0, len(hops))
+	for _, h := range hops {
+		parts = append(parts, h.String())
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
+// JSON writes the result as a json object to the writer.
+func (r Result) JSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(r)
+}
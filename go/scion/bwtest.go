@@ -0,0 +1,373 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/snet/addrutil"
+	"github.com/scionproto/scion/go/lib/sock/reliable"
+	"github.com/scionproto/scion/go/lib/tracing"
+	"github.com/scionproto/scion/go/pkg/app"
+	"github.com/scionproto/scion/go/pkg/app/path"
+	"github.com/scionproto/scion/go/pkg/bwtest"
+	"github.com/scionproto/scion/go/pkg/command"
+	"github.com/scionproto/scion/go/pkg/showpaths"
+)
+
+func newBwtest(pather CommandPather) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "bwtest",
+		Short: "Measure the throughput to a remote SCION host",
+		Long: `'bwtest' measures the achievable throughput between a client and a
+server over a SCION path.
+
+The client negotiates the test parameters for both directions with the server.
+Afterwards, both sides send packets at the negotiated rate concurrently, and the
+achieved rate, the loss and the inter-arrival jitter are reported per direction.`,
+	}
+	joined := command.Join(pather, cmd)
+	cmd.AddCommand(
+		newBwtestClient(joined),
+		newBwtestServer(joined),
+	)
+	return cmd
+}
+
+func newBwtestServer(pather command.Pather) *cobra.Command {
+	var flags struct {
+		dispatcher  string
+		local       net.IP
+		logLevel    string
+		maxDuration time.Duration
+		maxRate     string
+		maxSessions int
+		port        uint16
+		sciond      string
+	}
+
+	var cmd = &cobra.Command{
+		Use:     "server [flags]",
+		Short:   "Answer the bandwidth tests of clients",
+		Example: fmt.Sprintf("  %[1]s server --port 30100", pather.CommandPath()),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := app.SetupLog(flags.logLevel); err != nil {
+				return serrors.WrapStr("setting up logging", err)
+			}
+			maxRate, err := bwtest.ParseRate(flags.maxRate)
+			if err != nil {
+				return serrors.WrapStr("parsing max rate", err)
+			}
+			cmd.SilenceUsage = true
+
+			ctx, cancelF := context.WithTimeout(context.Background(), time.Second)
+			defer cancelF()
+			sd, err := sciond.NewService(flags.sciond).Connect(ctx)
+			if err != nil {
+				return serrors.WrapStr("connecting to SCION Daemon", err)
+			}
+			info, err := app.QueryASInfo(ctx, sd)
+			if err != nil {
+				return err
+			}
+			localIP := flags.local
+			if localIP == nil {
+				if localIP, err = addrutil.DefaultLocalIP(ctx, sd); err != nil {
+					return serrors.WrapStr("resolving local address", err)
+				}
+			}
+			n := snet.NewNetwork(info.IA, reliable.NewDispatcher(flags.dispatcher), nil)
+			conn, err := n.Listen(ctx, "udp",
+				&net.UDPAddr{IP: localIP, Port: int(flags.port)}, addr.SvcNone)
+			if err != nil {
+				return serrors.WrapStr("listening", err)
+			}
+			defer conn.Close()
+			fmt.Printf("Listening on %s,%s\n", info.IA, conn.LocalAddr())
+
+			server := &bwtest.Server{
+				Conn: conn,
+				ErrHandler: func(err error) {
+					fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
+				},
+				MaxRate:     maxRate,
+				MaxDuration: flags.maxDuration,
+				MaxSessions: flags.maxSessions,
+			}
+			return server.Serve(app.WithSignal(context.Background(),
+				os.Interrupt, syscall.SIGTERM))
+		},
+	}
+
+	cmd.Flags().IPVar(&flags.local, "local", nil, "IP address to listen on")
+	cmd.Flags().Uint16Var(&flags.port, "port", 0, "port to listen on")
+	cmd.Flags().StringVar(&flags.maxRate, "max-rate", "100Mbps",
+		"maximum rate of a test in one direction")
+	cmd.Flags().DurationVar(&flags.maxDuration, "max-duration", bwtest.MaxDuration,
+		"maximum duration of a test in one direction")
+	cmd.Flags().IntVar(&flags.maxSessions, "max-sessions", bwtest.DefaultMaxSessions,
+		"maximum number of concurrent tests")
+	cmd.Flags().StringVar(&flags.dispatcher, "dispatcher", reliable.DefaultDispPath,
+		"dispatcher socket")
+	cmd.Flags().StringVar(&flags.sciond, "sciond", sciond.DefaultAPIAddress, "SCION Daemon address")
+	cmd.Flags().StringVar(&flags.logLevel, "log.level", "", app.LogLevelUsage)
+	return cmd
+}
+
+func newBwtestClient(pather command.Pather) *cobra.Command {
+	var flags struct {
+		clientToServer string
+		dispatcher     string
+		format         string
+		interactive    bool
+		local          net.IP
+		logLevel       string
+		noColor        bool
+		refresh        bool
+		sciond         string
+		sequence       string
+		serverToClient string
+		timeout        time.Duration
+		tracer         string
+	}
+
+	var cmd = &cobra.Command{
+		Use:   "client [flags] <server>",
+		Short: "Measure the throughput to a bandwidth test server",
+		Example: fmt.Sprintf(`  %[1]s client 1-ff00:0:110,10.0.0.1:30100
+  %[1]s client 1-ff00:0:110,10.0.0.1:30100 --cs 5s,1200,50Mbps --sc 5s,1200,10Mbps
+  %[1]s client 1-ff00:0:110,10.0.0.1:30100 --sequence="0* 1-ff00:0:112 0*" --format json`,
+			pather.CommandPath()),
+		Long: fmt.Sprintf(`'client' runs a bandwidth test with a server.
+
+The test parameters of each direction are specified as
+<duration>,<packet size>,<rate>, where the packet size is the UDP payload size in
+bytes and the rate is given in bps, kbps, Mbps or Gbps. Unless specified, the server
+to client direction uses the parameters of the client to server direction.
+
+If no test packet is received in either direction, client will exit with code 1.
+On other errors, client will exit with code 2.
+
+%s`, app.SequenceHelp),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			remote, err := snet.ParseUDPAddr(args[0])
+			if err != nil {
+				return serrors.WrapStr("parsing remote", err)
+			}
+			if err := app.SetupLog(flags.logLevel); err != nil {
+				return serrors.WrapStr("setting up logging", err)
+			}
+			if flags.format != "human" && flags.format != "json" {
+				return serrors.New("format not supported", "format", flags.format)
+			}
+			cs, err := bwtest.ParseParameters(flags.clientToServer)
+			if err != nil {
+				return serrors.WrapStr("parsing client to server parameters", err)
+			}
+			sc := cs
+			if cmd.Flags().Changed("sc") {
+				if sc, err = bwtest.ParseParameters(flags.serverToClient); err != nil {
+					return serrors.WrapStr("parsing server to client parameters", err)
+				}
+			}
+			closer, err := setupTracer("bwtest", flags.tracer)
+			if err != nil {
+				return serrors.WrapStr("setting up tracing", err)
+			}
+			defer closer()
+
+			cmd.SilenceUsage = true
+
+			span, traceCtx := tracing.CtxWith(context.Background(), "run")
+			span.SetTag("dst.isd_as", remote.IA)
+			span.SetTag("dst.host", remote.Host.IP)
+			defer span.Finish()
+
+			ctx, cancelF := context.WithTimeout(traceCtx, time.Second)
+			defer cancelF()
+			sd, err := sciond.NewService(flags.sciond).Connect(ctx)
+			if err != nil {
+				return serrors.WrapStr("connecting to SCION Daemon", err)
+			}
+			info, err := app.QueryASInfo(traceCtx, sd)
+			if err != nil {
+				return err
+			}
+			span.SetTag("src.isd_as", info.IA)
+
+			path, err := path.Choose(traceCtx, sd, remote.IA,
+				path.WithInteractive(flags.interactive),
+				path.WithRefresh(flags.refresh),
+				path.WithSequence(flags.sequence),
+				path.WithColorScheme(path.DefaultColorScheme(flags.noColor)),
+			)
+			if err != nil {
+				return err
+			}
+			remote.Path = path.Path()
+			remote.NextHop = path.UnderlayNextHop()
+
+			localIP := flags.local
+			if localIP == nil {
+				if localIP, err = resolveLocal(remote); err != nil {
+					return err
+				}
+			}
+			span.SetTag("src.host", localIP)
+			n := snet.NewNetwork(info.IA, reliable.NewDispatcher(flags.dispatcher), nil)
+			conn, err := n.Listen(traceCtx, "udp", &net.UDPAddr{IP: localIP}, addr.SvcNone)
+			if err != nil {
+				return serrors.WrapStr("listening", err)
+			}
+			defer conn.Close()
+			if flags.format == "human" {
+				fmt.Printf("Using path:\n  %s\n\n", path)
+				fmt.Printf("BWTEST %s cs=%s sc=%s\n", remote,
+					fmtParameters(cs), fmtParameters(sc))
+			}
+
+			ctx = app.WithSignal(traceCtx, os.Interrupt, syscall.SIGTERM)
+			stats, err := bwtest.Run(ctx, bwtest.Config{
+				Conn:           conn,
+				Server:         remote,
+				ClientToServer: cs,
+				ServerToClient: sc,
+				Timeout:        flags.timeout,
+			})
+			if err != nil {
+				return err
+			}
+			res := bwtestResult{
+				Server:      remote.String(),
+				Fingerprint: snet.Fingerprint(path).String(),
+				Hops:        []showpaths.Hop{},
+				ClientToServer: bwtestDirection{
+					Parameters: cs,
+					Stats:      stats.ClientToServer,
+				},
+				ServerToClient: bwtestDirection{
+					Parameters: sc,
+					Stats:      stats.ServerToClient,
+				},
+			}
+			if md := path.Metadata(); md != nil {
+				for _, intf := range md.Interfaces {
+					res.Hops = append(res.Hops, showpaths.Hop{IA: intf.IA, IfID: intf.ID})
+				}
+			}
+			if flags.format == "json" {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(res); err != nil {
+					return serrors.WrapStr("encoding JSON result", err)
+				}
+			} else {
+				res.Human(os.Stdout)
+			}
+			if stats.ClientToServer.Received == 0 && stats.ServerToClient.Received == 0 {
+				return app.WithExitCode(serrors.New("no test packet received"), 1)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.clientToServer, "cs", "3s,1000,1Mbps",
+		"test parameters from the client to the server (<duration>,<packet size>,<rate>)")
+	cmd.Flags().StringVar(&flags.serverToClient, "sc", "",
+		`test parameters from the server to the client (<duration>,<packet size>,<rate>);
+defaults to the client to server parameters`)
+	cmd.Flags().BoolVar(&flags.refresh, "refresh", false, "set refresh flag for path request")
+	cmd.Flags().BoolVarP(&flags.interactive, "interactive", "i", false, "interactive mode")
+	cmd.Flags().BoolVar(&flags.noColor, "no-color", false, "disable colored output")
+	cmd.Flags().DurationVar(&flags.timeout, "timeout", bwtest.DefaultTimeout,
+		"timeout per control message")
+	cmd.Flags().IPVar(&flags.local, "local", nil, "IP address to listen on")
+	cmd.Flags().StringVar(&flags.dispatcher, "dispatcher", reliable.DefaultDispPath,
+		"dispatcher socket")
+	cmd.Flags().StringVar(&flags.sciond, "sciond", sciond.DefaultAPIAddress, "SCION Daemon address")
+	cmd.Flags().StringVar(&flags.sequence, "sequence", "", app.SequenceUsage)
+	cmd.Flags().StringVar(&flags.format, "format", "human",
+		"output format of the statistics (human|json)")
+	cmd.Flags().StringVar(&flags.logLevel, "log.level", "", app.LogLevelUsage)
+	cmd.Flags().StringVar(&flags.tracer, "tracing.agent", "", "Tracing agent address")
+	return cmd
+}
+
+// bwtestResult contains the result of a bandwidth test.
+type bwtestResult struct {
+	Server         string          `json:"server"`
+	Fingerprint    string          `json:"fingerprint"`
+	Hops           []showpaths.Hop `json:"hops"`
+	ClientToServer bwtestDirection `json:"client_to_server"`
+	ServerToClient bwtestDirection `json:"server_to_client"`
+}
+
+// bwtestDirection contains the parameters and statistics of one direction.
+type bwtestDirection struct {
+	Parameters bwtest.Parameters `json:"parameters"`
+	Stats      bwtest.Stats      `json:"stats"`
+}
+
+// Human writes a table comparing the directions to the writer.
+func (r bwtestResult) Human(w io.Writer) {
+	fmt.Fprintf(w, "--- %s bandwidth test statistics ---\n", r.Server)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "DIRECTION\tTARGET\tACHIEVED\tSENT\tRECV\tLOSS\tJITTER")
+	for _, d := range []struct {
+		name string
+		bwtestDirection
+	}{
+		{"client->server", r.ClientToServer},
+		{"server->client", r.ServerToClient},
+	} {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%.1f%%\t%s\n",
+			d.name, fmtRate(d.Parameters.Rate), fmtRate(d.Stats.Rate),
+			d.Stats.Sent, d.Stats.Received, d.Stats.Loss*100, humanRTT(d.Stats.Jitter))
+	}
+	tw.Flush()
+}
+
+func fmtParameters(p bwtest.Parameters) string {
+	return fmt.Sprintf("%s,%d,%s", p.Duration, p.PacketSize, fmtRate(p.Rate))
+}
+
+func fmtRate(bps int64) string {
+	switch {
+	case bps >= 1e9:
+		return fmt.Sprintf("%.2fGbps", float64(bps)/1e9)
+	case bps >= 1e6:
+		return fmt.Sprintf("%.2fMbps", float64(bps)/1e6)
+	case bps >= 1e3:
+		return fmt.Sprintf("%.2fkbps", float64(bps)/1e3)
+	default:
+		return fmt.Sprintf("%dbps", bps)
+	}
+}
@@ -0,0 +1,447 @@
+// Copyright 2021 Anapaya Systems
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/log"
+	"github.com/scionproto/scion/go/lib/sciond"
+	"github.com/scionproto/scion/go/lib/serrors"
+	"github.com/scionproto/scion/go/lib/snet"
+	"github.com/scionproto/scion/go/lib/sock/reliable"
+	"github.com/scionproto/scion/go/lib/topology"
+	"github.com/scionproto/scion/go/lib/tracing"
+	"github.com/scionproto/scion/go/pkg/app"
+	"github.com/scionproto/scion/go/pkg/app/path"
+	"github.com/scionproto/scion/go/pkg/showpaths"
+	"github.com/scionproto/scion/go/pkg/traceroute"
+)
+
+func newMtr(pather CommandPather) *cobra.Command {
+	var flags struct {
+		allPaths    bool
+		count       uint
+		dispatcher  string
+		format      string
+		interactive bool
+		interval    time.Duration
+		local       net.IP
+		logLevel    string
+		maxPaths    int
+		noColor     bool
+		refresh     bool
+		sciond      string
+		sequence    string
+		timeout     time.Duration
+		tracer      string
+	}
+
+	var cmd = &cobra.Command{
+		Use:   "mtr [flags] <remote>",
+		Short: "Continuously monitor the quality of the SCION path to a remote SCION AS",
+		Example: fmt.Sprintf(`  %[1]s mtr 1-ff00:0:110,10.0.0.1
+  %[1]s mtr 1-ff00:0:110,10.0.0.1 --all-paths --sequence="0* 1-ff00:0:112 0*"
+  %[1]s mtr 1-ff00:0:110,10.0.0.1 -c 10 --format json`, pather.CommandPath()),
+		Long: fmt.Sprintf(`'mtr' repeatedly traces the SCION path to a remote AS using
+SCMP traceroute packets, and reports the loss, latency and jitter per hop.
+
+In every round, one probe is sent to each hop of the path. The rounds are repeated
+until the command is interrupted, or the number of rounds specified with --count is
+reached. If the standard output is a terminal, the per hop statistics are updated
+continuously. Otherwise, they are reported once the command terminates.
+
+When the --all-paths or --max-paths option is set, mtr monitors all paths that match
+the sequence concurrently. Use --format json to get the statistics in a machine
+readable format.
+
+If no reply packet is received at all, mtr will exit with code 1.
+On other errors, mtr will exit with code 2.
+
+%s`, app.SequenceHelp),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			remote, err := snet.ParseUDPAddr(args[0])
+			if err != nil {
+				return serrors.WrapStr("parsing remote", err)
+			}
+			if err := app.SetupLog(flags.logLevel); err != nil {
+				return serrors.WrapStr("setting up logging", err)
+			}
+			if flags.format != "human" && flags.format != "json" {
+				return serrors.New("format not supported", "format", flags.format)
+			}
+			closer, err := setupTracer("mtr", flags.tracer)
+			if err != nil {
+				return serrors.WrapStr("setting up tracing", err)
+			}
+			defer closer()
+
+			cmd.SilenceUsage = true
+
+			span, traceCtx := tracing.CtxWith(context.Background(), "run")
+			span.SetTag("dst.isd_as", remote.IA)
+			span.SetTag("dst.host", remote.Host.IP)
+			defer span.Finish()
+
+			ctx, cancelF := context.WithTimeout(traceCtx, time.Second)
+			defer cancelF()
+			sd, err := sciond.NewService(flags.sciond).Connect(ctx)
+			if err != nil {
+				return serrors.WrapStr("connecting to SCION Daemon", err)
+			}
+			info, err := app.QueryASInfo(traceCtx, sd)
+			if err != nil {
+				return err
+			}
+			span.SetTag("src.isd_as", info.IA)
+
+			opts := []path.Option{
+				path.WithInteractive(flags.interactive),
+				path.WithRefresh(flags.refresh),
+				path.WithSequence(flags.sequence),
+				path.WithColorScheme(path.DefaultColorScheme(flags.noColor)),
+			}
+			var paths []snet.Path
+			if flags.allPaths || cmd.Flags().Changed("max-paths") {
+				paths, err = path.Fetch(traceCtx, sd, remote.IA, opts...)
+				if err != nil {
+					return err
+				}
+				if flags.maxPaths > 0 && len(paths) > flags.maxPaths {
+					paths = paths[:flags.maxPaths]
+				}
+			} else {
+				p, err := path.Choose(traceCtx, sd, remote.IA, opts...)
+				if err != nil {
+					return err
+				}
+				paths = []snet.Path{p}
+			}
+
+			monitors := make([]*pathMonitor, 0, len(paths))
+			for _, p := range paths {
+				m, err := newPathMonitor(p, info.IA, flags.local, remote, flags.timeout)
+				if err != nil {
+					return err
+				}
+				monitors = append(monitors, m)
+			}
+
+			ctx = app.WithSignal(traceCtx, os.Interrupt, syscall.SIGTERM)
+			dispatcher := reliable.NewDispatcher(flags.dispatcher)
+			var wg sync.WaitGroup
+			for _, m := range monitors {
+				m := m
+				wg.Add(1)
+				go func() {
+					defer log.HandlePanic()
+					defer wg.Done()
+					m.Run(ctx, dispatcher, int(flags.count), flags.interval)
+				}()
+			}
+			done := make(chan struct{})
+			go func() {
+				defer log.HandlePanic()
+				wg.Wait()
+				close(done)
+			}()
+
+			live := flags.format == "human" && isTerminal(os.Stdout)
+			if live {
+				ticker := time.NewTicker(flags.interval)
+				defer ticker.Stop()
+			loop:
+				for {
+					// Clear the screen and move the cursor to the top left
+					// corner before redrawing the statistics.
+					fmt.Print("\033[H\033[2J")
+					mtrReport(remote, monitors).Human(os.Stdout, !flags.noColor)
+					select {
+					case <-done:
+						break loop
+					case <-ticker.C:
+					}
+				}
+			}
+			<-done
+
+			res := mtrReport(remote, monitors)
+			switch {
+			case flags.format == "json":
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(res); err != nil {
+					return serrors.WrapStr("encoding JSON result", err)
+				}
+			case live:
+				fmt.Print("\033[H\033[2J")
+				res.Human(os.Stdout, !flags.noColor)
+			default:
+				res.Human(os.Stdout, !flags.noColor)
+			}
+			if res.Received() == 0 {
+				return app.WithExitCode(serrors.New("no reply packet received"), 1)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&flags.refresh, "refresh", false, "set refresh flag for path request")
+	cmd.Flags().BoolVarP(&flags.interactive, "interactive", "i", false, "interactive mode")
+	cmd.Flags().BoolVar(&flags.noColor, "no-color", false, "disable colored output")
+	cmd.Flags().UintVarP(&flags.count, "count", "c", 0,
+		"number of rounds after which mtr terminates (0 means until interrupted)")
+	cmd.Flags().DurationVar(&flags.interval, "interval", time.Second, "time between rounds")
+	cmd.Flags().DurationVar(&flags.timeout, "timeout", time.Second, "timeout per packet")
+	cmd.Flags().IPVar(&flags.local, "local", nil, "IP address to listen on")
+	cmd.Flags().StringVar(&flags.dispatcher, "dispatcher", reliable.DefaultDispPath,
+		"dispatcher socket")
+	cmd.Flags().StringVar(&flags.sciond, "sciond", sciond.DefaultAPIAddress, "SCION Daemon address")
+	cmd.Flags().StringVar(&flags.sequence, "sequence", "", app.SequenceUsage)
+	cmd.Flags().BoolVar(&flags.allPaths, "all-paths", false,
+		"monitor all paths that match the sequence concurrently")
+	cmd.Flags().IntVar(&flags.maxPaths, "max-paths", 10,
+		`maximum number of paths that are monitored concurrently;
+setting this flag implies --all-paths. (0 means no limit)`)
+	cmd.Flags().StringVar(&flags.format, "format", "human",
+		"output format of the statistics (human|json)")
+	cmd.Flags().StringVar(&flags.logLevel, "log.level", "", app.LogLevelUsage)
+	cmd.Flags().StringVar(&flags.tracer, "tracing.agent", "", "Tracing agent address")
+	return cmd
+}
+
+// pathMonitor repeatedly traces a single path and aggregates the results.
+type pathMonitor struct {
+	path   snet.Path
+	local  *snet.UDPAddr
+	remote *snet.UDPAddr
+	stats  traceroute.Aggregator
+
+	mtx sync.Mutex
+	err error
+}
+
+func newPathMonitor(p snet.Path, localIA addr.IA, localIP net.IP, remote *snet.UDPAddr,
+	timeout time.Duration) (*pathMonitor, error) {
+
+	remote = remote.Copy()
+	remote.Path = p.Path()
+	remote.NextHop = p.UnderlayNextHop()
+	if remote.NextHop == nil {
+		remote.NextHop = &net.UDPAddr{
+			IP:   remote.Host.IP,
+			Port: topology.EndhostPort,
+		}
+	}
+	if localIP == nil {
+		var err error
+		if localIP, err = resolveLocal(remote); err != nil {
+			return nil, err
+		}
+	}
+	return &pathMonitor{
+		path:   p,
+		local:  &snet.UDPAddr{IA: localIA, Host: &net.UDPAddr{IP: localIP}},
+		remote: remote,
+		stats:  traceroute.Aggregator{Timeout: timeout},
+	}, nil
+}
+
+// Run traces the path the given number of rounds, or until the context is
+// canceled if rounds is 0.
+func (m *pathMonitor) Run(ctx context.Context, dispatcher reliable.Dispatcher, rounds int,
+	interval time.Duration) {
+
+	var mtu uint16
+	if md := m.path.Metadata(); md != nil {
+		mtu = md.MTU
+	}
+	cfg := traceroute.Config{
+		Dispatcher:    dispatcher,
+		Remote:        m.remote,
+		MTU:           mtu,
+		Local:         m.local,
+		PathEntry:     m.path,
+		Timeout:       m.stats.Timeout,
+		ProbesPerHop:  1,
+		UpdateHandler: m.stats.Update,
+	}
+	for round := 0; rounds == 0 || round < rounds; round++ {
+		if round > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+		if _, err := traceroute.Run(ctx, cfg); err != nil {
+			m.mtx.Lock()
+			defer m.mtx.Unlock()
+			m.err = err
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		m.stats.CompleteRound()
+	}
+}
+
+func (m *pathMonitor) Err() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.err
+}
+
+// mtrReport creates a snapshot of the statistics of all monitored paths.
+func mtrReport(remote *snet.UDPAddr, monitors []*pathMonitor) mtrResult {
+	res := mtrResult{
+		Destination: remote.String(),
+		Paths:       make([]mtrPathResult, 0, len(monitors)),
+	}
+	for _, m := range monitors {
+		r := mtrPathResult{
+			path:        m.path,
+			Fingerprint: snet.Fingerprint(m.path).String(),
+			Path:        []showpaths.Hop{},
+			LocalIP:     m.local.Host.IP,
+			Rounds:      m.stats.Rounds(),
+			Hops:        []mtrHopResult{},
+		}
+		if md := m.path.Metadata(); md != nil {
+			for _, intf := range md.Interfaces {
+				r.Path = append(r.Path, showpaths.Hop{IA: intf.IA, IfID: intf.ID})
+			}
+		}
+		for _, hop := range m.stats.Hops() {
+			rtt := hop.RTT()
+			h := mtrHopResult{
+				Index:     hop.Index,
+				Interface: hop.Interface,
+				Sent:      hop.Sent,
+				Received:  hop.Recv,
+				Loss:      hop.Loss(),
+				LastRTT:   rtt.Last,
+				MinRTT:    rtt.Min,
+				AvgRTT:    rtt.Avg,
+				MaxRTT:    rtt.Max,
+				StdDevRTT: rtt.StdDev,
+				Jitter:    rtt.Jitter,
+			}
+			if hop.Remote != nil {
+				h.Remote = hop.Remote.String()
+			}
+			r.Hops = append(r.Hops, h)
+		}
+		if err := m.Err(); err != nil {
+			r.Error = err.Error()
+		}
+		res.Paths = append(res.Paths, r)
+	}
+	return res
+}
+
+// mtrResult contains the per hop statistics of monitoring multiple paths.
+type mtrResult struct {
+	Destination string          `json:"destination"`
+	Paths       []mtrPathResult `json:"paths"`
+}
+
+// mtrPathResult contains the per hop statistics of monitoring a single path.
+type mtrPathResult struct {
+	path snet.Path
+
+	Fingerprint string          `json:"fingerprint"`
+	Path        []showpaths.Hop `json:"path"`
+	LocalIP     net.IP          `json:"local_ip"`
+	Rounds      int             `json:"rounds"`
+	Hops        []mtrHopResult  `json:"hops"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// mtrHopResult contains the statistics of a single hop.
+type mtrHopResult struct {
+	Index     int           `json:"index"`
+	Remote    string        `json:"remote,omitempty"`
+	Interface uint64        `json:"interface"`
+	Sent      int           `json:"sent"`
+	Received  int           `json:"received"`
+	Loss      float64       `json:"loss"`
+	LastRTT   time.Duration `json:"last_rtt"`
+	MinRTT    time.Duration `json:"min_rtt"`
+	AvgRTT    time.Duration `json:"avg_rtt"`
+	MaxRTT    time.Duration `json:"max_rtt"`
+	StdDevRTT time.Duration `json:"stddev_rtt"`
+	Jitter    time.Duration `json:"jitter"`
+}
+
+// Received returns the number of replies received on all paths.
+func (r mtrResult) Received() int {
+	var received int
+	for _, p := range r.Paths {
+		for _, h := range p.Hops {
+			received += h.Received
+		}
+	}
+	return received
+}
+
+// Human writes a table with the per hop statistics of every path to the
+// writer.
+func (r mtrResult) Human(w io.Writer, colored bool) {
+	cs := path.DefaultColorScheme(!colored)
+	fmt.Fprintf(w, "--- %s statistics per hop ---\n", r.Destination)
+	for i, p := range r.Paths {
+		fmt.Fprintf(w, "\n[%d] %s (%d rounds)\n", i, cs.Path(p.path), p.Rounds)
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "#\tREMOTE\tLOSS\tSENT\tLAST\tAVG\tBEST\tWORST\tSTDEV\tJITTER")
+		for _, h := range p.Hops {
+			remote := "??"
+			if h.Remote != "" {
+				remote = fmt.Sprintf("%s IfID=%d", h.Remote, h.Interface)
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%.1f%%\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				h.Index, remote, h.Loss*100, h.Sent,
+				humanRTT(h.LastRTT), humanRTT(h.AvgRTT), humanRTT(h.MinRTT),
+				humanRTT(h.MaxRTT), humanRTT(h.StdDevRTT), humanRTT(h.Jitter))
+		}
+		tw.Flush()
+		if p.Error != "" {
+			fmt.Fprintf(w, "ERROR: %s\n", p.Error)
+		}
+	}
+}
+
+// isTerminal returns whether the file is a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -16,15 +16,21 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"math"
 	"net"
 	"os"
+	"sync"
 	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/scionproto/scion/go/lib/addr"
+	"github.com/scionproto/scion/go/lib/log"
 	"github.com/scionproto/scion/go/lib/sciond"
 	"github.com/scionproto/scion/go/lib/serrors"
 	"github.com/scionproto/scion/go/lib/snet"
@@ -34,18 +40,22 @@ import (
 	"github.com/scionproto/scion/go/pkg/app"
 	"github.com/scionproto/scion/go/pkg/app/path"
 	"github.com/scionproto/scion/go/pkg/ping"
+	"github.com/scionproto/scion/go/pkg/showpaths"
 )
 
 func newPing(pather CommandPather) *cobra.Command {
 	var flags struct {
+		allPaths    bool
 		count       uint16
 		dispatcher  string
 		features    []string
+		format      string
 		interactive bool
 		interval    time.Duration
 		local       net.IP
 		logLevel    string
 		maxMTU      bool
+		maxPaths    int
 		noColor     bool
 		refresh     bool
 		healthyOnly bool
@@ -60,7 +70,9 @@ func newPing(pather CommandPather) *cobra.Command {
 		Use:   "ping [flags] <remote>",
 		Short: "Test connectivity to a remote SCION host using SCMP echo packets",
 		Example: fmt.Sprintf(`  %[1]s ping 1-ff00:0:110,10.0.0.1
-  %[1]s ping 1-ff00:0:110,10.0.0.1 -c 5`, pather.CommandPath()),
+  %[1]s ping 1-ff00:0:110,10.0.0.1 -c 5
+  %[1]s ping 1-ff00:0:110,10.0.0.1 -c 5 --all-paths --sequence="0* 1-ff00:0:112 0*"
+  %[1]s ping 1-ff00:0:110,10.0.0.1 -c 5 --max-paths 3 --format json`, pather.CommandPath()),
 		Long: fmt.Sprintf(`'ping' test connectivity to a remote SCION host using SCMP echo packets.
 
 When the --count option is set, ping sends the specified number of SCMP echo packets
@@ -69,6 +81,11 @@ and reports back the statistics.
 When the --healthy-only option is set, ping first determines healthy paths through probing and
 chooses amongst them.
 
+When the --all-paths or --max-paths option is set, ping sends SCMP echo packets on all paths
+that match the sequence concurrently, and reports the round trip time statistics, the loss,
+and the reordering per path. Use --format json to get the statistics in a machine readable
+format.
+
 If no reply packet is received at all, ping will exit with code 1.
 On other errors, ping will exit with code 2.
 
@@ -82,6 +99,9 @@ On other errors, ping will exit with code 2.
 			if err := app.SetupLog(flags.logLevel); err != nil {
 				return serrors.WrapStr("setting up logging", err)
 			}
+			if flags.format != "human" && flags.format != "json" {
+				return serrors.New("format not supported", "format", flags.format)
+			}
 			closer, err := setupTracer("ping", flags.tracer)
 			if err != nil {
 				return serrors.WrapStr("setting up tracing", err)
@@ -120,6 +140,58 @@ On other errors, ping will exit with code 2.
 					LocalIP: flags.local,
 				}))
 			}
+			count := flags.count
+			if count == 0 {
+				count = math.MaxUint16
+			}
+			cfg := ping.Config{
+				Dispatcher: reliable.NewDispatcher(flags.dispatcher),
+				Attempts:   count,
+				Interval:   flags.interval,
+				Timeout:    flags.timeout,
+			}
+			multi := flags.allPaths || cmd.Flags().Changed("max-paths")
+			if multi || flags.format == "json" {
+				var paths []snet.Path
+				if multi {
+					paths, err = path.Fetch(traceCtx, sd, remote.IA, opts...)
+					if err != nil {
+						return err
+					}
+					if flags.maxPaths > 0 && len(paths) > flags.maxPaths {
+						paths = paths[:flags.maxPaths]
+					}
+				} else {
+					p, err := path.Choose(traceCtx, sd, remote.IA, opts...)
+					if err != nil {
+						return err
+					}
+					paths = []snet.Path{p}
+				}
+				if flags.format == "human" {
+					fmt.Printf("PING %s on %d paths\n", remote, len(paths))
+				}
+				ctx = app.WithSignal(traceCtx, os.Interrupt, syscall.SIGTERM)
+				res, err := pingPaths(ctx, cfg, info.IA, flags.local, remote, paths,
+					int(flags.size), flags.maxMTU)
+				if err != nil {
+					return err
+				}
+				if flags.format == "json" {
+					enc := json.NewEncoder(os.Stdout)
+					enc.SetIndent("", "  ")
+					if err := enc.Encode(res); err != nil {
+						return serrors.WrapStr("encoding JSON result", err)
+					}
+				} else {
+					res.Human(os.Stdout, !flags.noColor)
+				}
+				if res.Received() == 0 {
+					return app.WithExitCode(serrors.New("no reply packet received"), 1)
+				}
+				return nil
+			}
+
 			path, err := path.Choose(traceCtx, sd, remote.IA, opts...)
 			if err != nil {
 				return err
@@ -130,13 +202,8 @@ On other errors, ping will exit with code 2.
 			// Resolve local IP based on underlay next hop
 			localIP := flags.local
 			if localIP == nil {
-				target := remote.Host.IP
-				if remote.NextHop != nil {
-					target = remote.NextHop.IP
-				}
-				if localIP, err = addrutil.ResolveLocal(target); err != nil {
-					return serrors.WrapStr("resolving local address", err)
-
+				if localIP, err = resolveLocal(remote); err != nil {
+					return err
 				}
 				fmt.Printf("Resolved local address:\n  %s\n", localIP)
 			}
@@ -162,36 +229,27 @@ On other errors, ping will exit with code 2.
 
 			start := time.Now()
 			ctx = app.WithSignal(traceCtx, os.Interrupt, syscall.SIGTERM)
-			count := flags.count
-			if count == 0 {
-				count = math.MaxUint16
+			cfg.Local = local
+			cfg.Remote = remote
+			cfg.PayloadSize = int(flags.size)
+			cfg.ErrHandler = func(err error) {
+				fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
 			}
-			stats, err := ping.Run(ctx, ping.Config{
-				Dispatcher:  reliable.NewDispatcher(flags.dispatcher),
-				Attempts:    count,
-				Interval:    flags.interval,
-				Timeout:     flags.timeout,
-				Local:       local,
-				Remote:      remote,
-				PayloadSize: int(flags.size),
-				ErrHandler: func(err error) {
-					fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
-				},
-				UpdateHandler: func(update ping.Update) {
-					var additional string
-					switch update.State {
-					case ping.AfterTimeout:
-						additional = " state=After timeout"
-					case ping.OutOfOrder:
-						additional = " state=Out of Order"
-					case ping.Duplicate:
-						additional = " state=Duplicate"
-					}
-					fmt.Fprintf(os.Stdout, "%d bytes from %s,%s: scmp_seq=%d time=%s%s\n",
-						update.Size, update.Source.IA, update.Source.Host, update.Sequence,
-						update.RTT, additional)
-				},
-			})
+			cfg.UpdateHandler = func(update ping.Update) {
+				var additional string
+				switch update.State {
+				case ping.AfterTimeout:
+					additional = " state=After timeout"
+				case ping.OutOfOrder:
+					additional = " state=Out of Order"
+				case ping.Duplicate:
+					additional = " state=Duplicate"
+				}
+				fmt.Fprintf(os.Stdout, "%d bytes from %s,%s: scmp_seq=%d time=%s%s\n",
+					update.Size, update.Source.IA, update.Source.Host, update.Sequence,
+					update.RTT, additional)
+			}
+			stats, err := ping.Run(ctx, cfg)
 			pingSummary(stats, remote, time.Since(start))
 			if err != nil {
 				return err
@@ -224,11 +282,159 @@ the SCION path.`,
 		`choose the payload size such that the sent SCION packet including the SCION Header,
 SCMP echo header and payload are equal to the MTU of the path. This flag overrides the
 'payload_size' flag.`)
+	cmd.Flags().BoolVar(&flags.allPaths, "all-paths", false,
+		"ping all paths that match the sequence concurrently and compare them")
+	cmd.Flags().IntVar(&flags.maxPaths, "max-paths", 10,
+		`maximum number of paths that are pinged concurrently;
+setting this flag implies --all-paths. (0 means no limit)`)
+	cmd.Flags().StringVar(&flags.format, "format", "human",
+		"output format of the statistics (human|json)")
 	cmd.Flags().StringVar(&flags.logLevel, "log.level", "", app.LogLevelUsage)
 	cmd.Flags().StringVar(&flags.tracer, "tracing.agent", "", "Tracing agent address")
 	return cmd
 }
 
+// resolveLocal resolves the local IP based on the underlay next hop.
+func resolveLocal(remote *snet.UDPAddr) (net.IP, error) {
+	target := remote.Host.IP
+	if remote.NextHop != nil {
+		target = remote.NextHop.IP
+	}
+	localIP, err := addrutil.ResolveLocal(target)
+	if err != nil {
+		return nil, serrors.WrapStr("resolving local address", err)
+	}
+	return localIP, nil
+}
+
+// pingPaths pings the remote on all paths concurrently.
+func pingPaths(ctx context.Context, cfg ping.Config, localIA addr.IA, localIP net.IP,
+	remote *snet.UDPAddr, paths []snet.Path, pldSize int, maxMTU bool) (pingResult, error) {
+
+	res := pingResult{
+		Destination: remote.String(),
+		Paths:       make([]pathPingResult, len(paths)),
+	}
+	var wg sync.WaitGroup
+	for i, p := range paths {
+		r := &res.Paths[i]
+		r.path = p
+		r.Fingerprint = snet.Fingerprint(p).String()
+		r.Hops = []showpaths.Hop{}
+		if md := p.Metadata(); md != nil {
+			for _, intf := range md.Interfaces {
+				r.Hops = append(r.Hops, showpaths.Hop{IA: intf.IA, IfID: intf.ID})
+			}
+		}
+
+		cfg := cfg
+		cfg.Remote = remote.Copy()
+		cfg.Remote.Path = p.Path()
+		cfg.Remote.NextHop = p.UnderlayNextHop()
+		ip := localIP
+		if ip == nil {
+			var err error
+			if ip, err = resolveLocal(cfg.Remote); err != nil {
+				return pingResult{}, err
+			}
+		}
+		r.LocalIP = ip
+		cfg.Local = &snet.UDPAddr{IA: localIA, Host: &net.UDPAddr{IP: ip}}
+		cfg.PayloadSize = pldSize
+		if maxMTU && p.Metadata() != nil {
+			size, err := calcMaxPldSize(cfg.Local, cfg.Remote, int(p.Metadata().MTU))
+			if err != nil {
+				return pingResult{}, err
+			}
+			cfg.PayloadSize = size
+		}
+		r.PayloadSize = cfg.PayloadSize
+
+		wg.Add(1)
+		go func() {
+			defer log.HandlePanic()
+			defer wg.Done()
+			stats, err := ping.Run(ctx, cfg)
+			if err != nil {
+				r.Error = err.Error()
+			}
+			rtt := stats.RTT()
+			r.Sent = stats.Sent
+			r.Received = stats.Received
+			r.Reordered = stats.Reordered
+			r.Duplicates = stats.Duplicates
+			r.Loss = stats.Loss()
+			r.MinRTT, r.AvgRTT, r.MaxRTT, r.MDevRTT = rtt.Min, rtt.Avg, rtt.Max, rtt.MDev
+			r.RTTs = stats.RTTs
+			if r.RTTs == nil {
+				r.RTTs = []time.Duration{}
+			}
+		}()
+	}
+	wg.Wait()
+	return res, nil
+}
+
+// pingResult contains the statistics of pinging a remote on multiple paths.
+type pingResult struct {
+	Destination string           `json:"destination"`
+	Paths       []pathPingResult `json:"paths"`
+}
+
+// pathPingResult contains the statistics of pinging on a single path.
+type pathPingResult struct {
+	path snet.Path
+
+	Fingerprint string          `json:"fingerprint"`
+	Hops        []showpaths.Hop `json:"hops"`
+	LocalIP     net.IP          `json:"local_ip"`
+	PayloadSize int             `json:"payload_size"`
+	Sent        int             `json:"sent"`
+	Received    int             `json:"received"`
+	Loss        float64         `json:"loss"`
+	Reordered   int             `json:"reordered"`
+	Duplicates  int             `json:"duplicates"`
+	MinRTT      time.Duration   `json:"min_rtt"`
+	AvgRTT      time.Duration   `json:"avg_rtt"`
+	MaxRTT      time.Duration   `json:"max_rtt"`
+	MDevRTT     time.Duration   `json:"mdev_rtt"`
+	RTTs        []time.Duration `json:"rtts"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// Received returns the number of replies received on all paths.
+func (r pingResult) Received() int {
+	var received int
+	for _, p := range r.Paths {
+		received += p.Received
+	}
+	return received
+}
+
+// Human writes a table comparing the paths to the writer.
+func (r pingResult) Human(w io.Writer, colored bool) {
+	cs := path.DefaultColorScheme(!colored)
+	fmt.Fprintf(w, "--- %s statistics per path ---\n", r.Destination)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tSENT\tRECV\tLOSS\tREORD\tMIN\tAVG\tMAX\tMDEV\tHOPS")
+	for i, p := range r.Paths {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%.1f%%\t%d\t%s\t%s\t%s\t%s\t%s\n",
+			i, p.Sent, p.Received, p.Loss*100, p.Reordered,
+			humanRTT(p.MinRTT), humanRTT(p.AvgRTT), humanRTT(p.MaxRTT), humanRTT(p.MDevRTT),
+			cs.Path(p.path))
+	}
+	tw.Flush()
+	for i, p := range r.Paths {
+		if p.Error != "" {
+			fmt.Fprintf(w, "ERROR: path %d: %s\n", i, p.Error)
+		}
+	}
+}
+
+func humanRTT(rtt time.Duration) string {
+	return fmt.Sprintf("%.3fms", float64(rtt)/float64(time.Millisecond))
+}
+
 func calcMaxPldSize(local, remote *snet.UDPAddr, mtu int) (int, error) {
 	overhead, err := ping.Size(local, remote, 0)
 	if err != nil {
@@ -48,6 +48,8 @@ func main() {
 	cmd.AddCommand(
 		command.NewCompletion(cmd),
 		command.NewVersion(cmd),
+		newBwtest(cmd),
+		newMtr(cmd),
 		newPing(cmd),
 		newShowpaths(cmd),
 		newTraceroute(cmd),
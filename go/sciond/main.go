@@ -89,11 +89,11 @@ func Sciond_RealMain() error {
 	}
 	defer closer.Close()
 
-	revCache := storage.NewRevocationStorage()
 	pathDB, err := storage.NewPathStorage(globalCfg.PathDB)
 	if err != nil {
 		return serrors.WrapStr("initializing path storage", err)
 	}
+	revCache := storage.NewRevocationStorage(pathDB)
 	pathDB = pathdb.WithMetrics(string(storage.BackendSqlite), pathDB)
 	defer pathDB.Close()
 	defer revCache.Close()
@@ -169,6 +169,18 @@ func Sciond_RealMain() error {
 		return serrors.WrapStr("listening", err)
 	}
 
+	createVerifier := func() infra.Verifier {
+		if globalCfg.SD.DisableSegVerification {
+			return acceptAllVerifier{}
+		}
+		return compat.Verifier{Verifier: trust.Verifier{
+			Engine:             engine,
+			Cache:              globalCfg.TrustEngine.Cache.New(),
+			CacheHits:          metrics.NewPromCounter(trustmetrics.CacheHitsTotal),
+			MaxCacheExpiration: globalCfg.TrustEngine.Cache.Expiration,
+		}}
+	}
+
 	hpGroups, err := hiddenpath.LoadHiddenPathGroups(globalCfg.SD.HiddenPathGroups)
 	if err != nil {
 		return serrors.WrapStr("loading hidden path groups", err)
@@ -178,38 +190,65 @@ func Sciond_RealMain() error {
 		Dialer: dialer,
 	}
 	if len(hpGroups) > 0 {
+		hpGroupStore := hiddenpath.NewGroupStore(hpGroups, nil)
 		requester = &hpgrpc.Requester{
 			RegularLookup: &segfetchergrpc.Requester{Dialer: dialer},
-			HPGroups:      hpGroups,
+			HPGroups:      hpGroupStore,
 			Dialer:        dialer,
 		}
+		hpGroupUpdater := periodic.Start(
+			&hiddenpath.GroupUpdater{
+				Store: hpGroupStore,
+				RPC: hpgrpc.GroupRequester{
+					Dialer:   dialer,
+					Verifier: createVerifier(),
+				},
+				LocalIA:  itopo.Get().IA(),
+				Server:   addr.SvcCS,
+				Location: globalCfg.SD.HiddenPathGroups,
+			},
+			hiddenpath.DefaultGroupUpdateInterval,
+			hiddenpath.DefaultGroupUpdateInterval,
+		)
+		defer hpGroupUpdater.Stop()
 	}
 
-	createVerifier := func() infra.Verifier {
-		if globalCfg.SD.DisableSegVerification {
-			return acceptAllVerifier{}
+	var prefetcher *segfetcher.Prefetcher
+	if prefetchCfg := globalCfg.SD.Prefetch; prefetchCfg.Enabled {
+		dsts, err := prefetchCfg.DestinationIAs()
+		if err != nil {
+			return serrors.WrapStr("parsing prefetch destinations", err)
+		}
+		prefetcher = &segfetcher.Prefetcher{
+			TopN:         *prefetchCfg.TopN,
+			LeadTime:     prefetchCfg.LeadTime.Duration,
+			Destinations: dsts,
+			TaskName:     "sd_segment_prefetcher",
 		}
-		return compat.Verifier{Verifier: trust.Verifier{
-			Engine:             engine,
-			Cache:              globalCfg.TrustEngine.Cache.New(),
-			CacheHits:          metrics.NewPromCounter(trustmetrics.CacheHitsTotal),
-			MaxCacheExpiration: globalCfg.TrustEngine.Cache.Expiration,
-		}}
+	}
+	pathFetcher := fetcher.NewFetcher(
+		fetcher.FetcherConfig{
+			RPC:          requester,
+			PathDB:       pathDB,
+			Inspector:    engine,
+			Verifier:     createVerifier(),
+			RevCache:     revCache,
+			Cfg:          globalCfg.SD,
+			TopoProvider: itopo.Provider(),
+			Prefetcher:   prefetcher,
+		},
+	)
+	if prefetcher != nil {
+		prefetcher.Target = pathFetcher
+		interval := globalCfg.SD.Prefetch.Interval.Duration
+		prefetchRunner := periodic.Start(prefetcher, interval, interval)
+		defer prefetchRunner.Stop()
+		prefetchRunner.TriggerRun()
 	}
 
 	server := grpc.NewServer(libgrpc.UnaryServerInterceptor())
 	sdpb.RegisterDaemonServiceServer(server, sciond.NewServer(sciond.ServerConfig{
-		Fetcher: fetcher.NewFetcher(
-			fetcher.FetcherConfig{
-				RPC:          requester,
-				PathDB:       pathDB,
-				Inspector:    engine,
-				Verifier:     createVerifier(),
-				RevCache:     revCache,
-				Cfg:          globalCfg.SD,
-				TopoProvider: itopo.Provider(),
-			},
-		),
+		Fetcher:      pathFetcher,
 		Engine:       engine,
 		PathDB:       pathDB,
 		RevCache:     revCache,